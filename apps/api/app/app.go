@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/Lil-Strudel/glassact-studios/apps/api/config"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/scheduler"
 	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/go-playground/validator/v10"
@@ -27,6 +28,10 @@ type Application struct {
 	Wg       sync.WaitGroup
 	S3       *s3.Client
 	Mailer   *Mailer
+	Tax      data.TaxEngine
+	// Geocoder is nil unless a Google Maps API key is configured.
+	Geocoder  Geocoder
+	Scheduler *scheduler.Scheduler
 }
 
 func (app *Application) Serve(routes http.Handler) error {
@@ -56,6 +61,9 @@ func (app *Application) Serve(routes http.Handler) error {
 			shutdownError <- err
 		}
 
+		app.Log.Info("stopping scheduled jobs")
+		app.Scheduler.Stop()
+
 		app.Log.Info("closing db pool")
 		app.Db.Pool.Close()
 
@@ -65,6 +73,8 @@ func (app *Application) Serve(routes http.Handler) error {
 		shutdownError <- nil
 	}()
 
+	app.Scheduler.Start()
+
 	app.Log.Info("starting server", "addr", srv.Addr, "env", app.Cfg.Env)
 
 	err := srv.ListenAndServe()
@@ -3,16 +3,29 @@ package app
 import (
 	"context"
 	"net/http"
+	"slices"
 
 	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
 )
 
 type contextKey string
 
-const userContextKey = contextKey("user")
+const (
+	userContextKey          = contextKey("user")
+	impersonationContextKey = contextKey("impersonation")
+	authCacheContextKey     = contextKey("auth_cache")
+)
+
+// authCache holds what the user may do and see once something in the request
+// has asked for it.
+type authCache struct {
+	permissions   data.PermissionSet
+	dealershipIDs []int
+}
 
 func (app *Application) ContextSetAuthUser(r *http.Request, user data.AuthUser) *http.Request {
 	ctx := context.WithValue(r.Context(), userContextKey, user)
+	ctx = context.WithValue(ctx, authCacheContextKey, &authCache{})
 	return r.WithContext(ctx)
 }
 
@@ -25,6 +38,73 @@ func (app *Application) ContextGetUser(r *http.Request) data.AuthUser {
 	return user
 }
 
+// ContextGetPermissions is what the request's user may do, resolved from
+// their role and overrides the first time it is asked for in a request.
+func (app *Application) ContextGetPermissions(r *http.Request) (data.PermissionSet, error) {
+	cache, ok := r.Context().Value(authCacheContextKey).(*authCache)
+	if ok && cache.permissions != nil {
+		return cache.permissions, nil
+	}
+
+	permissions, err := data.ResolvePermissions(&app.Db, app.ContextGetUser(r))
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		cache.permissions = permissions
+	}
+	return permissions, nil
+}
+
+// Can reports whether the request's user may take action.
+func (app *Application) Can(r *http.Request, action string) (bool, error) {
+	permissions, err := app.ContextGetPermissions(r)
+	if err != nil {
+		return false, err
+	}
+	return permissions.Has(action), nil
+}
+
+// ContextGetDealershipIDs lists the dealerships a dealership user can see:
+// their own, or every location in their group for a group-level user. It is
+// nil for internal users, who can see every dealership.
+func (app *Application) ContextGetDealershipIDs(r *http.Request) ([]int, error) {
+	dealershipUser, isDealership := app.ContextGetUser(r).(*data.DealershipUser)
+	if !isDealership {
+		return nil, nil
+	}
+
+	cache, ok := r.Context().Value(authCacheContextKey).(*authCache)
+	if ok && cache.dealershipIDs != nil {
+		return cache.dealershipIDs, nil
+	}
+
+	dealershipIDs, err := app.Db.DealershipGroups.DealershipIDsFor(dealershipUser)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		cache.dealershipIDs = dealershipIDs
+	}
+	return dealershipIDs, nil
+}
+
+// CanAccessDealership reports whether the request's user can see the
+// dealership's projects, invoices and the like.
+func (app *Application) CanAccessDealership(r *http.Request, dealershipID int) (bool, error) {
+	if !app.ContextGetUser(r).IsDealership() {
+		return true, nil
+	}
+
+	dealershipIDs, err := app.ContextGetDealershipIDs(r)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(dealershipIDs, dealershipID), nil
+}
+
 func (app *Application) ContextGetDealershipUser(r *http.Request) *data.DealershipUser {
 	user := app.ContextGetUser(r)
 	dealershipUser, ok := user.(*data.DealershipUser)
@@ -42,3 +122,15 @@ func (app *Application) ContextGetInternalUser(r *http.Request) *data.InternalUs
 	}
 	return internalUser
 }
+
+func (app *Application) ContextSetImpersonation(r *http.Request, impersonation *data.Impersonation) *http.Request {
+	ctx := context.WithValue(r.Context(), impersonationContextKey, impersonation)
+	return r.WithContext(ctx)
+}
+
+// ContextGetImpersonation is the impersonation the request is made under, or
+// nil when the user is signed in as themselves.
+func (app *Application) ContextGetImpersonation(r *http.Request) *data.Impersonation {
+	impersonation, _ := r.Context().Value(impersonationContextKey).(*data.Impersonation)
+	return impersonation
+}
@@ -16,6 +16,11 @@ type appError struct {
 	MissingRefreshToken ErrorType
 	AccountNotFound     ErrorType
 	Conflict            ErrorType
+	InvitationInvalid   ErrorType
+	TwoFactorInvalid    ErrorType
+	TwoFactorRequired   ErrorType
+	RateLimited         ErrorType
+	ReadOnly            ErrorType
 }
 
 var AppError = appError{
@@ -28,6 +33,11 @@ var AppError = appError{
 	MissingRefreshToken: ErrorType("missing-refresh-token"),
 	AccountNotFound:     ErrorType("account-not-found"),
 	Conflict:            ErrorType("conflict"),
+	InvitationInvalid:   ErrorType("invitation-invalid"),
+	TwoFactorInvalid:    ErrorType("two-factor-invalid"),
+	TwoFactorRequired:   ErrorType("two-factor-required"),
+	RateLimited:         ErrorType("rate-limited"),
+	ReadOnly:            ErrorType("read-only"),
 }
 
 type ErrorConfig struct {
@@ -82,4 +92,29 @@ var ErrorMap = map[ErrorType]ErrorConfig{
 		Message:  `This action conflicts with the current state of the resource.`,
 		Expected: true,
 	},
+	AppError.InvitationInvalid: {
+		Status:   http.StatusGone,
+		Message:  `This invitation has expired, been revoked or was already accepted. Ask for a new one.`,
+		Expected: true,
+	},
+	AppError.TwoFactorInvalid: {
+		Status:   http.StatusBadRequest,
+		Message:  `That code is wrong or has already been used. After too many wrong codes you will need to sign in again.`,
+		Expected: true,
+	},
+	AppError.TwoFactorRequired: {
+		Status:   http.StatusForbidden,
+		Message:  `Two-factor authentication is required for your role and cannot be turned off.`,
+		Expected: true,
+	},
+	AppError.RateLimited: {
+		Status:   http.StatusTooManyRequests,
+		Message:  `Too many requests. Please wait a moment and try again.`,
+		Expected: true,
+	},
+	AppError.ReadOnly: {
+		Status:   http.StatusForbidden,
+		Message:  `You are viewing as another user, which cannot make changes.`,
+		Expected: true,
+	},
 }
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+// ErrAddressNotFound is returned when a geocoder has no match for an address.
+var ErrAddressNotFound = errors.New("address could not be geocoded")
+
+// Geocoder finds the coordinates of a street address. The address's own
+// latitude and longitude are ignored.
+type Geocoder interface {
+	Geocode(ctx context.Context, address data.Address) (latitude, longitude float64, err error)
+}
+
+// GoogleGeocoder uses the Google Maps Geocoding API, the same service the
+// address autocomplete in the web app uses.
+type GoogleGeocoder struct {
+	apiKey string
+	client *http.Client
+}
+
+func NewGoogleGeocoder(apiKey string) *GoogleGeocoder {
+	return &GoogleGeocoder{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (g *GoogleGeocoder) Geocode(ctx context.Context, address data.Address) (float64, float64, error) {
+	parts := []string{}
+	for _, part := range []string{address.Street, address.StreetExt, address.City, address.State, address.PostalCode} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	query := url.Values{}
+	query.Set("address", strings.Join(parts, ", "))
+	query.Set("components", "country:"+address.Country)
+	query.Set("key", g.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://maps.googleapis.com/maps/api/geocode/json?"+query.Encode(), nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("geocoding request failed with status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Results      []struct {
+			Geometry struct {
+				Location struct {
+					Lat float64 `json:"lat"`
+					Lng float64 `json:"lng"`
+				} `json:"location"`
+			} `json:"geometry"`
+		} `json:"results"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch body.Status {
+	case "OK":
+		location := body.Results[0].Geometry.Location
+		return location.Lat, location.Lng, nil
+	case "ZERO_RESULTS":
+		return 0, 0, ErrAddressNotFound
+	default:
+		return 0, 0, fmt.Errorf("geocoding failed: %s %s", body.Status, body.ErrorMessage)
+	}
+}
@@ -93,44 +93,50 @@ func (app *Application) ReadJSONBody(w http.ResponseWriter, r *http.Request, dst
 
 	err = app.Validate.Struct(dst)
 	if err != nil {
-		var messages []string
-
-		for _, err := range err.(validator.ValidationErrors) {
-			var message string
-			fieldName := strings.ToLower(err.Field())
-
-			switch err.Tag() {
-			case "required":
-				message = fmt.Sprintf(`"%s" is required`, fieldName)
-			case "email":
-				message = fmt.Sprintf(`"%s" must be a valid email address`, fieldName)
-			case "min":
-				if err.Kind().String() == "string" {
-					message = fmt.Sprintf(`"%s" must be at least %s characters long`, fieldName, err.Param())
-				} else {
-					message = fmt.Sprintf(`"%s" must be at least %s`, fieldName, err.Param())
-				}
-			case "max":
-				if err.Kind().String() == "string" {
-					message = fmt.Sprintf(`"%s" must be at most %s characters long`, fieldName, err.Param())
-				} else {
-					message = fmt.Sprintf(`"%s" must be at most %s`, fieldName, err.Param())
-				}
-			default:
-				if err.Param() != "" {
-					message = fmt.Sprintf(`"%s" failed validation '%s' with parameter '%s' (current value: '%v')`,
-						fieldName, err.Tag(), err.Param(), err.Value())
-				} else {
-					message = fmt.Sprintf(`"%s" failed validation '%s' (current value: '%v')`,
-						fieldName, err.Tag(), err.Value())
-				}
-			}
+		return errors.New("There were issues with your body: " + strings.Join(ValidationMessages(err), ", "))
+	}
+
+	return nil
+}
 
-			messages = append(messages, message)
+// ValidationMessages turns the errors from validating a struct into messages
+// that name the offending fields.
+func ValidationMessages(err error) []string {
+	var messages []string
+
+	for _, err := range err.(validator.ValidationErrors) {
+		var message string
+		fieldName := strings.ToLower(err.Field())
+
+		switch err.Tag() {
+		case "required":
+			message = fmt.Sprintf(`"%s" is required`, fieldName)
+		case "email":
+			message = fmt.Sprintf(`"%s" must be a valid email address`, fieldName)
+		case "min":
+			if err.Kind().String() == "string" {
+				message = fmt.Sprintf(`"%s" must be at least %s characters long`, fieldName, err.Param())
+			} else {
+				message = fmt.Sprintf(`"%s" must be at least %s`, fieldName, err.Param())
+			}
+		case "max":
+			if err.Kind().String() == "string" {
+				message = fmt.Sprintf(`"%s" must be at most %s characters long`, fieldName, err.Param())
+			} else {
+				message = fmt.Sprintf(`"%s" must be at most %s`, fieldName, err.Param())
+			}
+		default:
+			if err.Param() != "" {
+				message = fmt.Sprintf(`"%s" failed validation '%s' with parameter '%s' (current value: '%v')`,
+					fieldName, err.Tag(), err.Param(), err.Value())
+			} else {
+				message = fmt.Sprintf(`"%s" failed validation '%s' (current value: '%v')`,
+					fieldName, err.Tag(), err.Value())
+			}
 		}
 
-		return errors.New("There were issues with your body: " + strings.Join(messages, ", "))
+		messages = append(messages, message)
 	}
 
-	return nil
+	return messages
 }
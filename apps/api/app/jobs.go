@@ -0,0 +1,68 @@
+package app
+
+import (
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/scheduler"
+)
+
+// ReadNotificationRetention is how long a notification is kept once read.
+const ReadNotificationRetention = 90 * 24 * time.Hour
+
+// JobRunRetention is how long a finished job run is kept.
+const JobRunRetention = 30 * 24 * time.Hour
+
+// RegisterJobs adds the background maintenance jobs to the scheduler.
+func (app *Application) RegisterJobs() error {
+	jobs := []scheduler.Job{
+		{
+			Name:        "expired-tokens",
+			Description: "Deletes expired dealership and internal access tokens.",
+			Schedule:    "@hourly",
+			Run: func() (int64, error) {
+				dealershipTokens, err := app.Db.DealershipTokens.DeleteExpired()
+				if err != nil {
+					return 0, err
+				}
+
+				internalTokens, err := app.Db.InternalTokens.DeleteExpired()
+				if err != nil {
+					return dealershipTokens, err
+				}
+
+				return dealershipTokens + internalTokens, nil
+			},
+		},
+		{
+			Name:        "read-notifications",
+			Description: "Deletes notifications read more than 90 days ago.",
+			Schedule:    "30 3 * * *",
+			Run: func() (int64, error) {
+				return app.Db.Notifications.DeleteReadBefore(time.Now().Add(-ReadNotificationRetention))
+			},
+		},
+		{
+			Name:        "rate-limit-buckets",
+			Description: "Deletes rate limit buckets that have refilled.",
+			Schedule:    "*/15 * * * *",
+			Run:         app.Db.RateLimits.Prune,
+		},
+		{
+			Name:        "job-runs",
+			Description: "Deletes job runs finished more than 30 days ago, keeping each job's latest.",
+			Schedule:    "45 3 * * *",
+			Run: func() (int64, error) {
+				return app.Db.JobRuns.DeleteFinishedBefore(time.Now().Add(-JobRunRetention))
+			},
+		},
+	}
+
+	for _, job := range jobs {
+		err := app.Scheduler.Register(job)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
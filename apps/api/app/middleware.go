@@ -1,9 +1,14 @@
 package app
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
@@ -75,7 +80,13 @@ func (app *Application) Authenticate(next http.Handler) http.Handler {
 
 		user, _, err := data.GetAuthUserForToken(&app.Db, data.ScopeAccess, token)
 		if err != nil {
-			app.WriteError(w, r, app.Err.AuthenticationError, err)
+			impersonation, found, lookupErr := app.Db.Impersonations.GetActiveForToken(token)
+			if lookupErr != nil || !found {
+				app.WriteError(w, r, app.Err.AuthenticationError, err)
+				return
+			}
+
+			app.serveImpersonation(w, r, next, impersonation)
 			return
 		}
 
@@ -84,3 +95,136 @@ func (app *Application) Authenticate(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// serveImpersonation handles a request made with an impersonation token. It
+// runs as the dealership user, but only reads, and every request goes in the
+// impersonation's audit trail first. The token stops working if either user is
+// deactivated or the admin loses the permission.
+func (app *Application) serveImpersonation(w http.ResponseWriter, r *http.Request, next http.Handler, impersonation *data.Impersonation) {
+	if impersonation.InternalUserID == nil || impersonation.DealershipUserID == nil {
+		app.WriteError(w, r, app.Err.AuthenticationError, nil)
+		return
+	}
+
+	admin, found, err := app.Db.InternalUsers.GetByID(*impersonation.InternalUserID)
+	if err != nil {
+		app.WriteError(w, r, app.Err.ServerError, err)
+		return
+	}
+	if !found || !admin.IsActive {
+		app.WriteError(w, r, app.Err.AuthenticationError, nil)
+		return
+	}
+
+	adminPermissions, err := data.ResolvePermissions(&app.Db, admin)
+	if err != nil {
+		app.WriteError(w, r, app.Err.ServerError, err)
+		return
+	}
+	if !adminPermissions.Has(data.ActionImpersonate) {
+		app.WriteError(w, r, app.Err.AuthenticationError, nil)
+		return
+	}
+
+	user, found, err := app.Db.DealershipUsers.GetByID(*impersonation.DealershipUserID)
+	if err != nil {
+		app.WriteError(w, r, app.Err.ServerError, err)
+		return
+	}
+	if !found || !user.IsActive {
+		app.WriteError(w, r, app.Err.AuthenticationError, nil)
+		return
+	}
+
+	blocked := r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions
+
+	// An impersonation nobody can audit does not happen.
+	err = app.Db.Impersonations.LogRequest(impersonation.ID, r.Method, r.URL.Path, blocked)
+	if err != nil {
+		app.WriteError(w, r, app.Err.ServerError, err)
+		return
+	}
+
+	if blocked {
+		app.WriteError(w, r, app.Err.ReadOnly, nil)
+		return
+	}
+
+	r = app.ContextSetAuthUser(r, user)
+	r = app.ContextSetImpersonation(r, impersonation)
+
+	next.ServeHTTP(w, r)
+}
+
+// RateLimitKey picks what a request is rate limited by. Returning false lets
+// the request through unlimited.
+type RateLimitKey func(r *http.Request) (string, bool)
+
+// RateLimit holds requests that share a key to limit. The buckets live in
+// Postgres so the limit holds across every API instance. name keeps
+// different limits on the same key apart.
+func (app *Application) RateLimit(name string, limit data.RateLimit, key RateLimitKey) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			value, ok := key(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, retryAfter, err := app.Db.RateLimits.Take(name+":"+value, limit)
+			if err != nil {
+				// Fail open: a database hiccup should not lock everyone out.
+				app.Log.Error("rate limit check failed", "limit", name, "error", err.Error())
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				app.WriteError(w, r, app.Err.RateLimited, nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimitByIP holds each client address to limit.
+func (app *Application) RateLimitByIP(name string, limit data.RateLimit) func(http.Handler) http.Handler {
+	return app.RateLimit(name, limit, app.byIP)
+}
+
+// RateLimitByJSONField holds each value of a JSON body field to limit.
+func (app *Application) RateLimitByJSONField(name, field string, limit data.RateLimit) func(http.Handler) http.Handler {
+	return app.RateLimit(name, limit, byJSONField(field))
+}
+
+func (app *Application) byIP(r *http.Request) (string, bool) {
+	ip := app.ClientIP(r)
+	return ip, ip != ""
+}
+
+// byJSONField keys on a string field of the JSON body, such as the email a
+// magic link is sent to, compared case-insensitively. The body is put back for
+// the handler. Requests without the field are not limited by it.
+func byJSONField(field string) RateLimitKey {
+	return func(r *http.Request) (string, bool) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1_048_576))
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return "", false
+		}
+
+		var fields map[string]any
+		err = json.Unmarshal(body, &fields)
+		if err != nil {
+			return "", false
+		}
+
+		value, _ := fields[field].(string)
+		value = strings.ToLower(strings.TrimSpace(value))
+		return value, value != ""
+	}
+}
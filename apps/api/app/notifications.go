@@ -77,6 +77,9 @@ var internalRoleFallback = map[data.NotificationEventType][]data.InternalUserRol
 	data.NotificationEventTypes.OrderPlaced: {
 		data.InternalUserRoles.Production, data.InternalUserRoles.Admin,
 	},
+	data.NotificationEventTypes.RushOrderPlaced: {
+		data.InternalUserRoles.Production, data.InternalUserRoles.Admin,
+	},
 	data.NotificationEventTypes.InternalReviewRequired: {
 		data.InternalUserRoles.Designer, data.InternalUserRoles.Admin,
 	},
@@ -230,6 +233,101 @@ func (app *Application) NotifyInternal(
 	}
 }
 
+// lowStockRoles hear about low stock. Stock belongs to no project, so there are
+// no watchers to reach; it goes to whoever orders glass.
+var lowStockRoles = []data.InternalUserRole{
+	data.InternalUserRoles.Production, data.InternalUserRoles.Admin,
+}
+
+// NotifyLowStock warns production about any of the given stock rows that have
+// fallen to their threshold. Each dip notifies once: the claim taken here is
+// only released when the row is restocked above the threshold. Failures are
+// logged, never returned, like every other notification.
+func (app *Application) NotifyLowStock(stockIDs []int) {
+	var users []*data.InternalUser
+
+	for _, stockID := range stockIDs {
+		stock, found, err := app.Db.MaterialStocks.GetByID(stockID)
+		if err != nil || !found {
+			app.Log.Error("failed to get material stock for low stock check",
+				"error", err, "material_stock_id", stockID)
+			continue
+		}
+		if !stock.IsLowStock() {
+			continue
+		}
+
+		claimed, err := app.Db.MaterialStocks.MarkLowStockNotified(stock.ID)
+		if err != nil {
+			app.Log.Error("failed to mark low stock notified",
+				"error", err, "material_stock_id", stock.ID)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		name, err := app.materialName(stock)
+		if err != nil {
+			app.Log.Error("failed to get material for low stock notification",
+				"error", err, "material_stock_id", stock.ID)
+			continue
+		}
+
+		if users == nil {
+			users, err = app.Db.InternalUsers.GetAll()
+			if err != nil {
+				app.Log.Error("failed to get internal users for low stock notification",
+					"error", err, "material_stock_id", stock.ID)
+				return
+			}
+		}
+
+		title := fmt.Sprintf("Low stock: %s", name)
+		body := fmt.Sprintf("%s is down to %s available (warning threshold %s).",
+			name, formatStockQuantity(stock.Available, stock.Unit), formatStockQuantity(stock.LowStockThreshold, stock.Unit))
+
+		for _, user := range users {
+			if !user.IsActive || !slices.Contains(lowStockRoles, user.Role) {
+				continue
+			}
+			app.SendNotificationToUser(user.ID, "internal", user.Email, data.NotificationEventTypes.LowStock, title, body, nil, nil)
+		}
+	}
+}
+
+func (app *Application) materialName(stock *data.MaterialStock) (string, error) {
+	if stock.GlassColorID != nil {
+		gc, found, err := app.Db.GlassColors.GetByID(*stock.GlassColorID)
+		if err != nil {
+			return "", err
+		}
+		if !found {
+			return "", fmt.Errorf("glass color %d not found", *stock.GlassColorID)
+		}
+		return gc.Name + " glass", nil
+	}
+
+	if stock.GroutID == nil {
+		return "", fmt.Errorf("material stock %d has no material", stock.ID)
+	}
+	grout, found, err := app.Db.Grouts.GetByID(*stock.GroutID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("grout %d not found", *stock.GroutID)
+	}
+	return grout.Name + " grout", nil
+}
+
+func formatStockQuantity(quantity float64, unit data.MaterialUnit) string {
+	if unit == data.MaterialUnits.Sheets {
+		return fmt.Sprintf("%.2f sheets", quantity)
+	}
+	return fmt.Sprintf("%.0f sq in", quantity)
+}
+
 func buildNotificationEmailHTML(title, body, baseURL string) string {
 	return fmt.Sprintf(`<!DOCTYPE html>
 <html lang="en">
@@ -7,9 +7,13 @@ import (
 func (app *Application) RequirePermission(action string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			user := app.ContextGetUser(r)
+			allowed, err := app.Can(r, action)
+			if err != nil {
+				app.WriteError(w, r, app.Err.ServerError, err)
+				return
+			}
 
-			if !user.Can(action) {
+			if !allowed {
 				app.WriteError(w, r, app.Err.Forbidden, nil)
 				return
 			}
@@ -1,6 +1,12 @@
 package app
 
-import "fmt"
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
 
 func (app *Application) Background(fn func()) {
 	app.Wg.Add(1)
@@ -17,3 +23,49 @@ func (app *Application) Background(fn func()) {
 		fn()
 	}()
 }
+
+// ClientIP is the address a request came from. When the peer is a trusted
+// proxy, X-Forwarded-For is walked from the right and the first address not
+// belonging to a trusted proxy wins; anything left of it could be forged by
+// the client.
+func (app *Application) ClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	if !app.isTrustedProxy(ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if _, err := netip.ParseAddr(hop); err != nil {
+			break
+		}
+		ip = hop
+		if !app.isTrustedProxy(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+func (app *Application) isTrustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range app.Cfg.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/config"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/scheduler"
 	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
@@ -46,15 +47,31 @@ func main() {
 	}
 	s3Client := s3.NewFromConfig(awsCfg)
 
+	models := data.NewModels(db, stdb)
+
+	var geocoder app.Geocoder
+	if cfg.GoogleMaps.APIKey != "" {
+		geocoder = app.NewGoogleGeocoder(cfg.GoogleMaps.APIKey)
+	}
+
 	app := &app.Application{
-		Cfg:      cfg,
-		Db:       data.NewModels(db, stdb),
-		Err:      app.AppError,
-		Log:      logger,
-		Validate: validator.New(validator.WithRequiredStructEnabled()),
-		Wg:       sync.WaitGroup{},
-		S3:       s3Client,
-		Mailer:   app.NewMailer(cfg.Smtp.Host, cfg.Smtp.Port, cfg.Smtp.Username, cfg.Smtp.Password),
+		Cfg:       cfg,
+		Db:        models,
+		Err:       app.AppError,
+		Log:       logger,
+		Validate:  validator.New(validator.WithRequiredStructEnabled()),
+		Wg:        sync.WaitGroup{},
+		S3:        s3Client,
+		Mailer:    app.NewMailer(cfg.Smtp.Host, cfg.Smtp.Port, cfg.Smtp.Username, cfg.Smtp.Password),
+		Tax:       data.RateTableTaxEngine{Rates: models.TaxRates},
+		Geocoder:  geocoder,
+		Scheduler: scheduler.New(models.JobRuns, logger),
+	}
+
+	err = app.RegisterJobs()
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
 	}
 
 	err = app.Serve(modules.GetRoutes(app))
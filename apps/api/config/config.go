@@ -2,9 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
 	"strconv"
+	"strings"
 
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
 	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
 )
@@ -39,6 +42,16 @@ type Config struct {
 		AccessKeyID     string
 		SecretAccessKey string
 	}
+	// GoogleMaps is optional. Without an API key, bulk imports need every
+	// address's coordinates filled in.
+	GoogleMaps struct {
+		APIKey string
+	}
+	// RushSurchargePercent is charged on the inlays of a rush order.
+	RushSurchargePercent float64 `validate:"gte=0,lte=100"`
+	// TrustedProxies are the load balancers whose X-Forwarded-For is
+	// believed. Without any, a request's address is its peer's.
+	TrustedProxies []netip.Prefix
 }
 
 func GetConfig() (*Config, error) {
@@ -86,6 +99,27 @@ func GetConfig() (*Config, error) {
 	cfg.S3.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
 	cfg.S3.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
 
+	cfg.GoogleMaps.APIKey = os.Getenv("GOOGLE_MAPS_API_KEY")
+
+	cfg.RushSurchargePercent = data.DefaultRushSurchargePercent
+	rushSurchargeStr := os.Getenv("RUSH_SURCHARGE_PERCENT")
+	if rushSurchargeStr != "" {
+		rushSurcharge, err := strconv.ParseFloat(rushSurchargeStr, 64)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RushSurchargePercent = rushSurcharge
+	}
+
+	trustedProxiesStr := os.Getenv("TRUSTED_PROXIES")
+	if trustedProxiesStr != "" {
+		trustedProxies, err := parseTrustedProxies(trustedProxiesStr)
+		if err != nil {
+			return nil, err
+		}
+		cfg.TrustedProxies = trustedProxies
+	}
+
 	validate := validator.New(validator.WithRequiredStructEnabled())
 	if err := validate.Struct(&cfg); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -93,3 +127,31 @@ func GetConfig() (*Config, error) {
 
 	return &cfg, nil
 }
+
+// parseTrustedProxies reads a comma-separated list of CIDRs or bare
+// addresses.
+func parseTrustedProxies(list string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
@@ -52,24 +52,7 @@ func (m *AuthModule) HandleGetGoogleAuthCallback(w http.ResponseWriter, r *http.
 		return
 	}
 
-	user, found, err := m.getUserFromProvider(userInfo.Email, "google", userInfo.ID)
-	if err != nil {
-		m.WriteError(w, r, m.Err.ServerError, err)
-		return
-	}
-
-	if !found {
-		m.WriteError(w, r, m.Err.AccountNotFound, nil)
-		return
-	}
-
-	err = m.login(user, w)
-	if err != nil {
-		m.WriteError(w, r, m.Err.ServerError, err)
-		return
-	}
-
-	http.Redirect(w, r, m.Cfg.BaseURL, http.StatusFound)
+	m.finishProviderLogin(w, r, userInfo.Email, "google", userInfo.ID, nil)
 }
 
 func (m *AuthModule) HandleGetMicrosoftAuth(w http.ResponseWriter, r *http.Request) {
@@ -104,7 +87,44 @@ func (m *AuthModule) HandleGetMicrosoftAuthCallback(w http.ResponseWriter, r *ht
 		return
 	}
 
-	user, found, err := m.getUserFromProvider(userInfo.Email, "microsoft", userInfo.Sub)
+	m.finishProviderLogin(w, r, userInfo.Email, "microsoft", userInfo.Sub, nil)
+}
+
+// HandlePostMagicLinkAuth emails a sign-in link. It answers the same whether
+// or not the address has an account, and sends in the background so the
+// timing does not tell either, to keep the customer list from being probed.
+func (m *AuthModule) HandlePostMagicLinkAuth(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	m.Background(func() {
+		err := m.sendMagicLink(body.Email)
+		if err != nil {
+			m.Log.Error("failed to send magic link", "error", err)
+		}
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleGetMagicLinkCallback signs in with an emailed link. Each link works
+// once; using one also voids any older links sent to the same user.
+func (m *AuthModule) HandleGetMagicLinkCallback(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	token := qs.Get("token")
+	if token == "" {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("missing token in query"))
+		return
+	}
+
+	user, found, err := m.consumeLoginToken(token)
 	if err != nil {
 		m.WriteError(w, r, m.Err.ServerError, err)
 		return
@@ -115,118 +135,117 @@ func (m *AuthModule) HandleGetMicrosoftAuthCallback(w http.ResponseWriter, r *ht
 		return
 	}
 
-	err = m.login(user, w)
+	redirect, err := m.signIn(user, w, r)
 	if err != nil {
 		m.WriteError(w, r, m.Err.ServerError, err)
 		return
 	}
 
-	http.Redirect(w, r, m.Cfg.BaseURL, http.StatusFound)
+	http.Redirect(w, r, redirect, http.StatusFound)
 }
 
-func (m *AuthModule) HandlePostMagicLinkAuth(w http.ResponseWriter, r *http.Request) {
-	var body struct {
-		Email string `json:"email" validate:"required,email"`
+// HandleGetInvitation previews an invitation so the accept page can show who
+// it is for before they pick how to sign in.
+func (m *AuthModule) HandleGetInvitation(w http.ResponseWriter, r *http.Request) {
+	invitation, ok := m.invitationForToken(w, r, r.URL.Query().Get("token"))
+	if !ok {
+		return
 	}
 
-	err := m.ReadJSONBody(w, r, &body)
-	if err != nil {
-		m.WriteError(w, r, m.Err.BadRequest, err)
+	m.WriteJSON(w, r, http.StatusOK, map[string]any{
+		"email":      invitation.Email,
+		"role":       invitation.Role,
+		"status":     invitation.Status,
+		"expires_at": invitation.ExpiresAt,
+		"open":       invitation.IsOpen(time.Now()),
+	})
+}
+
+// HandleGetInvitationAccept is where invitation emails link to. Without a
+// provider the emailed link itself is the proof of identity, the same as a
+// magic link. With google, microsoft or the key of a registered provider the
+// token rides along in a short-lived cookie through the provider's sign-in,
+// and the callback links that identity to the invited user.
+func (m *AuthModule) HandleGetInvitationAccept(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	invitation, ok := m.invitationForToken(w, r, qs.Get("token"))
+	if !ok {
 		return
 	}
 
-	dealershipUser, found, err := m.Db.DealershipUsers.GetByEmail(body.Email)
-	if err != nil {
-		m.WriteError(w, r, m.Err.ServerError, err)
+	if !invitation.IsOpen(time.Now()) {
+		m.WriteError(w, r, m.Err.InvitationInvalid, nil)
 		return
 	}
 
-	if found && dealershipUser.IsActive {
-		loginToken, err := m.Db.DealershipTokens.New(dealershipUser.ID, 2*time.Hour, data.DealershipScopeLogin)
+	var authURL string
+	switch qs.Get("provider") {
+	case "", magicLinkProvider:
+		user, err := m.acceptInvitation(invitation, "email", magicLinkProvider, invitation.Email, nil)
 		if err != nil {
-			m.WriteError(w, r, m.Err.ServerError, err)
+			m.writeAcceptError(w, r, err)
 			return
 		}
 
-		err = m.emailMagicLink(body.Email, loginToken.Plaintext)
+		redirect, err := m.signIn(user, w, r)
 		if err != nil {
 			m.WriteError(w, r, m.Err.ServerError, err)
 			return
 		}
 
-		m.WriteJSON(w, r, http.StatusNoContent, nil)
-		return
-	}
-
-	internalUser, found, err := m.Db.InternalUsers.GetByEmail(body.Email)
-	if err != nil {
-		m.WriteError(w, r, m.Err.ServerError, err)
+		http.Redirect(w, r, redirect, http.StatusFound)
 		return
-	}
-
-	if found && internalUser.IsActive {
-		loginToken, err := m.Db.InternalTokens.New(internalUser.ID, 2*time.Hour, data.InternalScopeLogin)
+	case "google":
+		state, err := m.generateSecureState()
 		if err != nil {
 			m.WriteError(w, r, m.Err.ServerError, err)
 			return
 		}
-
-		err = m.emailMagicLink(body.Email, loginToken.Plaintext)
+		authURL = m.configGoogle().AuthCodeURL(state)
+	case "microsoft":
+		state, err := m.generateSecureState()
 		if err != nil {
 			m.WriteError(w, r, m.Err.ServerError, err)
 			return
 		}
-
-		m.WriteJSON(w, r, http.StatusNoContent, nil)
-		return
-	}
-
-	m.WriteError(w, r, m.Err.AccountNotFound, nil)
-}
-
-func (m *AuthModule) HandleGetMagicLinkCallback(w http.ResponseWriter, r *http.Request) {
-	qs := r.URL.Query()
-	token := qs.Get("token")
-	if token == "" {
-		m.WriteError(w, r, m.Err.BadRequest, errors.New("missing token in query"))
-		return
-	}
-
-	dealershipUser, found, err := m.Db.DealershipUsers.GetForToken(data.DealershipScopeLogin, token)
-	if err != nil {
-		m.WriteError(w, r, m.Err.ServerError, err)
-		return
-	}
-
-	if found && dealershipUser.IsActive {
-		err = m.login(dealershipUser, w)
+		authURL = m.configMicrosoft().AuthCodeURL(state)
+	default:
+		provider, found, err := m.Db.OIDCProviders.GetByKey(qs.Get("provider"))
 		if err != nil {
 			m.WriteError(w, r, m.Err.ServerError, err)
 			return
 		}
-		http.Redirect(w, r, m.Cfg.BaseURL, http.StatusFound)
-		return
-	}
-
-	internalUser, found, err := m.Db.InternalUsers.GetForToken(data.InternalScopeLogin, token)
-	if err != nil {
-		m.WriteError(w, r, m.Err.ServerError, err)
-		return
-	}
+		if !found || !provider.IsActive {
+			m.WriteError(w, r, m.Err.BadRequest, errors.New("unknown provider"))
+			return
+		}
 
-	if found && internalUser.IsActive {
-		err = m.login(internalUser, w)
+		authURL, err = m.oidcAuthURL(r.Context(), provider)
 		if err != nil {
 			m.WriteError(w, r, m.Err.ServerError, err)
 			return
 		}
-		http.Redirect(w, r, m.Cfg.BaseURL, http.StatusFound)
-		return
 	}
 
-	m.WriteError(w, r, m.Err.AccountNotFound, nil)
+	// Lax rather than Strict: the provider's redirect back to our callback is
+	// a cross-site navigation and Strict cookies would not be sent with it.
+	http.SetCookie(w, &http.Cookie{
+		Name:     invitationCookie,
+		Value:    qs.Get("token"),
+		Path:     "/api/auth",
+		MaxAge:   int((15 * time.Minute).Seconds()),
+		Secure:   m.Cfg.Env == "production",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authURL, http.StatusFound)
 }
 
+// HandlePostTokenAccess trades the refresh cookie for an access token and a
+// new refresh cookie. A refresh token that is presented again after being
+// traded in means it was copied, so the whole session is revoked.
 func (m *AuthModule) HandlePostTokenAccess(w http.ResponseWriter, r *http.Request) {
 	cookie, err := r.Cookie("refresh_token")
 	if err != nil {
@@ -240,43 +259,47 @@ func (m *AuthModule) HandlePostTokenAccess(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
-	user, _, err := data.GetAuthUserForToken(&m.Db, data.ScopeRefresh, cookie.Value)
-	if err != nil {
-		m.WriteError(w, r, m.Err.AccountNotFound, nil)
-		return
-	}
+	client := m.sessionClient(r)
 
-	if user == nil {
-		m.WriteError(w, r, m.Err.AccountNotFound, nil)
+	user, rotation, err := data.RotateRefreshToken(&m.Db, cookie.Value, client)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRefreshTokenReused):
+			m.Log.Warn("refresh token reused; session revoked", "user_uuid", user.GetUUID(), "session_id", rotation.SessionID, "ip", client.IP)
+			clearRefreshCookie(w)
+			m.WriteError(w, r, m.Err.AccountNotFound, nil)
+		case errors.Is(err, data.ErrRefreshTokenNotFound):
+			clearRefreshCookie(w)
+			m.WriteError(w, r, m.Err.AccountNotFound, nil)
+		default:
+			m.WriteError(w, r, m.Err.ServerError, err)
+		}
 		return
 	}
 
-	var accessToken *data.DealershipToken
-	var internalAccessToken *data.InternalToken
+	var plaintext string
+	var expiry time.Time
 
 	if user.IsDealership() {
-		accessToken, err = m.Db.DealershipTokens.New(user.GetID(), 2*time.Hour, data.DealershipScopeAccess)
+		accessToken, err := m.Db.DealershipTokens.NewForSession(user.GetID(), 2*time.Hour, data.DealershipScopeAccess, rotation.SessionID, client)
 		if err != nil {
 			m.WriteError(w, r, m.Err.ServerError, err)
 			return
 		}
+		plaintext = accessToken.Plaintext
+		expiry = accessToken.Expiry
 	} else {
-		internalAccessToken, err = m.Db.InternalTokens.New(user.GetID(), 2*time.Hour, data.InternalScopeAccess)
+		accessToken, err := m.Db.InternalTokens.NewForSession(user.GetID(), 2*time.Hour, data.InternalScopeAccess, rotation.SessionID, client)
 		if err != nil {
 			m.WriteError(w, r, m.Err.ServerError, err)
 			return
 		}
-	}
-
-	var plaintext string
-	var expiry time.Time
-
-	if accessToken != nil {
 		plaintext = accessToken.Plaintext
 		expiry = accessToken.Expiry
-	} else {
-		plaintext = internalAccessToken.Plaintext
-		expiry = internalAccessToken.Expiry
+	}
+
+	if rotation.Plaintext != "" {
+		m.setRefreshCookie(w, rotation.Plaintext, rotation.Expiry)
 	}
 
 	m.WriteJSON(w, r, http.StatusCreated, map[string]any{
@@ -298,24 +321,67 @@ func (m *AuthModule) HandleGetLogout(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	err = m.Db.DealershipTokens.DeleteByPlaintext(data.DealershipScopeRefresh, cookie.Value)
+	err = m.Db.DealershipTokens.RevokeSessionByPlaintext(cookie.Value)
 	if err != nil {
 		m.WriteError(w, r, m.Err.ServerError, err)
 		return
 	}
 
-	err = m.Db.InternalTokens.DeleteByPlaintext(data.InternalScopeRefresh, cookie.Value)
+	err = m.Db.InternalTokens.RevokeSessionByPlaintext(cookie.Value)
 	if err != nil {
 		m.WriteError(w, r, m.Err.ServerError, err)
 		return
 	}
 
-	newCookie := http.Cookie{
-		Name:   "refresh_token",
-		Path:   "/api/auth",
-		MaxAge: -1,
+	clearRefreshCookie(w)
+	http.Redirect(w, r, m.Cfg.BaseURL, http.StatusFound)
+}
+
+// HandleGetSessions lists where the user is signed in, flagging the session
+// making the request.
+func (m *AuthModule) HandleGetSessions(w http.ResponseWriter, r *http.Request) {
+	user := m.ContextGetUser(r)
+
+	sessions, err := data.GetSessions(&m.Db, user)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
 	}
 
-	http.SetCookie(w, &newCookie)
-	http.Redirect(w, r, m.Cfg.BaseURL, http.StatusFound)
+	current, err := m.currentSessionID(r, user)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	for _, session := range sessions {
+		session.Current = session.ID == current
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, sessions)
+}
+
+// HandleDeleteSession signs the user out of one of their sessions, which may
+// be the one making the request.
+func (m *AuthModule) HandleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("id")
+
+	err := m.Validate.Var(sessionID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	revoked, err := data.RevokeSession(&m.Db, m.ContextGetUser(r), sessionID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if !revoked {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]interface{}{"success": true})
 }
@@ -18,24 +18,30 @@ import (
 	"time"
 
 	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/google/uuid"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"golang.org/x/oauth2/microsoft"
 )
 
-func (m *AuthModule) login(user data.AuthUser, w http.ResponseWriter) error {
+// login starts a new session for user on the requesting device and hands it
+// its first refresh token.
+func (m *AuthModule) login(user data.AuthUser, w http.ResponseWriter, r *http.Request) error {
+	sessionID := uuid.NewString()
+	client := m.sessionClient(r)
+
 	var plaintext string
 	var expiry time.Time
 
 	if user.IsDealership() {
-		refreshToken, err := m.Db.DealershipTokens.New(user.GetID(), 30*24*time.Hour, data.DealershipScopeRefresh)
+		refreshToken, err := m.Db.DealershipTokens.NewForSession(user.GetID(), data.RefreshTokenTTL, data.DealershipScopeRefresh, sessionID, client)
 		if err != nil {
 			return err
 		}
 		plaintext = refreshToken.Plaintext
 		expiry = refreshToken.Expiry
 	} else {
-		refreshToken, err := m.Db.InternalTokens.New(user.GetID(), 30*24*time.Hour, data.InternalScopeRefresh)
+		refreshToken, err := m.Db.InternalTokens.NewForSession(user.GetID(), data.RefreshTokenTTL, data.InternalScopeRefresh, sessionID, client)
 		if err != nil {
 			return err
 		}
@@ -43,6 +49,12 @@ func (m *AuthModule) login(user data.AuthUser, w http.ResponseWriter) error {
 		expiry = refreshToken.Expiry
 	}
 
+	m.setRefreshCookie(w, plaintext, expiry)
+
+	return nil
+}
+
+func (m *AuthModule) setRefreshCookie(w http.ResponseWriter, plaintext string, expiry time.Time) {
 	secure := false
 	if m.Cfg.Env == "production" {
 		secure = true
@@ -59,8 +71,183 @@ func (m *AuthModule) login(user data.AuthUser, w http.ResponseWriter) error {
 	}
 
 	http.SetCookie(w, &cookie)
+}
 
-	return nil
+func clearRefreshCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:   "refresh_token",
+		Path:   "/api/auth",
+		MaxAge: -1,
+	})
+}
+
+const twoFactorCookie = "two_factor_token"
+
+// signIn finishes the first step of signing in, whether by provider, magic
+// link or invitation, and returns where to send the browser. Internal users
+// who have an authenticator, or whose role the policy says must have one, get
+// a short-lived challenge cookie instead of a session and are sent to the page
+// that asks for their code.
+func (m *AuthModule) signIn(user data.AuthUser, w http.ResponseWriter, r *http.Request) (string, error) {
+	if internalUser, ok := user.(*data.InternalUser); ok {
+		required, err := m.twoFactorRequired(internalUser)
+		if err != nil {
+			return "", err
+		}
+
+		if required {
+			return m.startTwoFactorChallenge(internalUser, w)
+		}
+	}
+
+	err := m.login(user, w, r)
+	if err != nil {
+		return "", err
+	}
+
+	return m.Cfg.BaseURL, nil
+}
+
+// twoFactorRequired reports whether user has to pass a second step to sign
+// in: they have confirmed an authenticator, or the policy requires one.
+func (m *AuthModule) twoFactorRequired(user *data.InternalUser) (bool, error) {
+	twoFactor, found, err := m.Db.TwoFactor.GetByInternalUserID(user.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if found && twoFactor.IsEnabled() {
+		return true, nil
+	}
+
+	policy, err := m.Db.TwoFactor.GetPolicy()
+	if err != nil {
+		return false, err
+	}
+
+	permissions, err := data.ResolvePermissions(&m.Db, user)
+	if err != nil {
+		return false, err
+	}
+
+	return policy.Requires(permissions), nil
+}
+
+func (m *AuthModule) startTwoFactorChallenge(user *data.InternalUser, w http.ResponseWriter) (string, error) {
+	// Getting through the first step again earns a fresh set of tries.
+	err := m.Db.TwoFactor.ResetFailedAttempts(user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := m.Db.InternalTokens.New(user.ID, data.TwoFactorChallengeTTL, data.InternalScopeTwoFactor)
+	if err != nil {
+		return "", err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     twoFactorCookie,
+		Value:    token.Plaintext,
+		Path:     "/api/auth",
+		Expires:  token.Expiry,
+		Secure:   m.Cfg.Env == "production",
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return url.JoinPath(m.Cfg.BaseURL, "two-factor")
+}
+
+func clearTwoFactorCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:   twoFactorCookie,
+		Path:   "/api/auth",
+		MaxAge: -1,
+	})
+}
+
+// challengeUser is the internal user part-way through signing in, from the
+// challenge cookie. Writes the error itself when there is no live challenge.
+func (m *AuthModule) challengeUser(w http.ResponseWriter, r *http.Request) (*data.InternalUser, bool) {
+	cookie, err := r.Cookie(twoFactorCookie)
+	if err != nil {
+		m.WriteError(w, r, m.Err.AccountNotFound, err)
+		return nil, false
+	}
+
+	user, found, err := m.Db.InternalUsers.GetForToken(data.InternalScopeTwoFactor, cookie.Value)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+
+	if !found || !user.IsActive {
+		m.WriteError(w, r, m.Err.AccountNotFound, nil)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// secondFactor is a code from the authenticator or, failing that, one of the
+// user's recovery codes.
+type secondFactor struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// verifySecondFactor checks the code against the user's authenticator. Wrong
+// codes count towards locking them out until they next sign in; recovery
+// codes only work once the authenticator is confirmed.
+func (m *AuthModule) verifySecondFactor(twoFactor *data.TwoFactor, factor secondFactor) (bool, error) {
+	if twoFactor.IsLocked() {
+		return false, nil
+	}
+
+	if factor.RecoveryCode == "" {
+		return m.Db.TwoFactor.Verify(twoFactor, data.TwoFactorKey(m.Cfg.AuthSecret), factor.Code)
+	}
+
+	if !twoFactor.IsEnabled() {
+		return false, nil
+	}
+
+	ok, err := m.Db.TwoFactor.UseRecoveryCode(twoFactor.InternalUserID, factor.RecoveryCode)
+	if err != nil || ok {
+		return ok, err
+	}
+
+	return false, m.Db.TwoFactor.RecordFailure(twoFactor)
+}
+
+// maxUserAgentLength keeps a hostile User-Agent header from bloating the
+// tokens table.
+const maxUserAgentLength = 512
+
+// sessionClient describes the device a request came from, for the sessions
+// list.
+func (m *AuthModule) sessionClient(r *http.Request) data.SessionClient {
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	return data.SessionClient{UserAgent: userAgent, IP: m.ClientIP(r)}
+}
+
+// currentSessionID is the session the request's access token belongs to.
+func (m *AuthModule) currentSessionID(r *http.Request, user data.AuthUser) (string, error) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	var sessionID string
+	var err error
+	if user.IsDealership() {
+		sessionID, _, err = m.Db.DealershipTokens.GetSessionID(data.DealershipScopeAccess, token)
+	} else {
+		sessionID, _, err = m.Db.InternalTokens.GetSessionID(data.InternalScopeAccess, token)
+	}
+
+	return sessionID, err
 }
 
 func (m *AuthModule) configGoogle() *oauth2.Config {
@@ -166,7 +353,17 @@ func getMicrosoftUserInfo(token string) (*microsoftInfoResponse, error) {
 	return &data, nil
 }
 
-func (m *AuthModule) getUserFromProvider(email, provider, providerID string) (data.AuthUser, bool, error) {
+// userFilter narrows who a provider may sign in. A nil filter allows anyone.
+type userFilter func(data.AuthUser) bool
+
+func (f userFilter) allows(user data.AuthUser) bool {
+	return f == nil || f(user)
+}
+
+// getUserFromProvider finds the active user a provider identity signs in as,
+// linking the identity to the user with the same email the first time. Users
+// the filter refuses are not found.
+func (m *AuthModule) getUserFromProvider(email, provider, providerID string, filter userFilter) (data.AuthUser, bool, error) {
 	existingAccount, found, err := m.Db.DealershipAccounts.GetByProvider(provider, providerID)
 	if err != nil {
 		return nil, false, err
@@ -178,7 +375,7 @@ func (m *AuthModule) getUserFromProvider(email, provider, providerID string) (da
 			return nil, false, err
 		}
 
-		if !found || !existingUser.IsActive {
+		if !found || !existingUser.IsActive || !filter.allows(existingUser) {
 			return nil, false, nil
 		}
 
@@ -191,6 +388,10 @@ func (m *AuthModule) getUserFromProvider(email, provider, providerID string) (da
 	}
 
 	if found && dealershipUser.IsActive {
+		if !filter.allows(dealershipUser) {
+			return nil, false, nil
+		}
+
 		newAccount := data.DealershipAccount{
 			DealershipUserID:  dealershipUser.ID,
 			Type:              "oidc",
@@ -217,7 +418,7 @@ func (m *AuthModule) getUserFromProvider(email, provider, providerID string) (da
 			return nil, false, err
 		}
 
-		if !found || !internalUser.IsActive {
+		if !found || !internalUser.IsActive || !filter.allows(internalUser) {
 			return nil, false, nil
 		}
 
@@ -230,6 +431,10 @@ func (m *AuthModule) getUserFromProvider(email, provider, providerID string) (da
 	}
 
 	if found && internalUser.IsActive {
+		if !filter.allows(internalUser) {
+			return nil, false, nil
+		}
+
 		newAccount := data.InternalAccount{
 			InternalUserID:    internalUser.ID,
 			Type:              "oidc",
@@ -248,6 +453,71 @@ func (m *AuthModule) getUserFromProvider(email, provider, providerID string) (da
 	return nil, false, nil
 }
 
+// magicLinkTTL is how long an emailed sign-in link works.
+const magicLinkTTL = 2 * time.Hour
+
+// sendMagicLink emails a sign-in link if email belongs to an active user, and
+// does nothing otherwise.
+func (m *AuthModule) sendMagicLink(email string) error {
+	dealershipUser, found, err := m.Db.DealershipUsers.GetByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	if found && dealershipUser.IsActive {
+		loginToken, err := m.Db.DealershipTokens.New(dealershipUser.ID, magicLinkTTL, data.DealershipScopeLogin)
+		if err != nil {
+			return err
+		}
+
+		return m.emailMagicLink(email, loginToken.Plaintext)
+	}
+
+	internalUser, found, err := m.Db.InternalUsers.GetByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	if found && internalUser.IsActive {
+		loginToken, err := m.Db.InternalTokens.New(internalUser.ID, magicLinkTTL, data.InternalScopeLogin)
+		if err != nil {
+			return err
+		}
+
+		return m.emailMagicLink(email, loginToken.Plaintext)
+	}
+
+	return nil
+}
+
+// consumeLoginToken uses up a magic link token and returns its active user.
+func (m *AuthModule) consumeLoginToken(token string) (data.AuthUser, bool, error) {
+	userID, found, err := m.Db.DealershipTokens.Consume(data.DealershipScopeLogin, token)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if found {
+		user, found, err := m.Db.DealershipUsers.GetByID(userID)
+		if err != nil || !found || !user.IsActive {
+			return nil, false, err
+		}
+		return user, true, nil
+	}
+
+	userID, found, err = m.Db.InternalTokens.Consume(data.InternalScopeLogin, token)
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	user, found, err := m.Db.InternalUsers.GetByID(userID)
+	if err != nil || !found || !user.IsActive {
+		return nil, false, err
+	}
+
+	return user, true, nil
+}
+
 func (m *AuthModule) emailMagicLink(email, token string) error {
 	u, err := url.Parse(m.Cfg.BaseURL)
 	if err != nil {
@@ -362,3 +632,247 @@ func (m *AuthModule) validateState(state string) error {
 
 	return nil
 }
+
+// errIdentityTaken is returned when the identity someone accepts an invitation
+// with already signs in as a different user.
+var errIdentityTaken = errors.New("this sign-in is already linked to another user")
+
+// errProviderNotAllowed is returned when someone accepts an invitation through
+// a provider their dealership does not sign in with.
+var errProviderNotAllowed = errors.New("this sign-in is not available to the invited user")
+
+const invitationCookie = "invitation_token"
+
+// magicLinkProvider records invitations accepted straight from the emailed
+// link, which proves ownership of the address the same way a magic link does.
+const magicLinkProvider = "magic_link"
+
+// identityOwner reports which user, if any, a provider identity already signs
+// in as.
+func (m *AuthModule) identityOwner(provider, providerID string) (dealershipUserID, internalUserID int, err error) {
+	dealershipAccount, found, err := m.Db.DealershipAccounts.GetByProvider(provider, providerID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if found {
+		return dealershipAccount.DealershipUserID, 0, nil
+	}
+
+	internalAccount, found, err := m.Db.InternalAccounts.GetByProvider(provider, providerID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if found {
+		return 0, internalAccount.InternalUserID, nil
+	}
+
+	return 0, 0, nil
+}
+
+// acceptInvitation closes the invitation, activates the invited user and links
+// the identity they accepted with so it signs them in from now on, all in one
+// transaction so a failure leaves the invitation open to try again. Returns
+// data.ErrInvitationClosed if the invitation was already used, revoked or has
+// expired, or errProviderNotAllowed if the filter refuses the invited user.
+func (m *AuthModule) acceptInvitation(invitation *data.Invitation, accountType, provider, providerID string, filter userFilter) (data.AuthUser, error) {
+	if !invitation.IsOpen(time.Now()) {
+		return nil, data.ErrInvitationClosed
+	}
+
+	ownerDealershipUserID, ownerInternalUserID, err := m.identityOwner(provider, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invitation.DealershipUserID != nil {
+		user, found, err := m.Db.DealershipUsers.GetByID(*invitation.DealershipUserID)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, data.ErrInvitationClosed
+		}
+		if !filter.allows(user) {
+			return nil, errProviderNotAllowed
+		}
+
+		linked := ownerDealershipUserID == user.ID
+		if !linked && (ownerDealershipUserID != 0 || ownerInternalUserID != 0) {
+			return nil, errIdentityTaken
+		}
+
+		tx, err := m.Db.STDB.Begin()
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		err = m.Db.Invitations.TxAccept(tx, invitation)
+		if err != nil {
+			return nil, err
+		}
+
+		user.IsActive = true
+		err = m.Db.DealershipUsers.TxUpdate(tx, user)
+		if err != nil {
+			return nil, err
+		}
+
+		if !linked {
+			err = m.Db.DealershipAccounts.TxInsert(tx, &data.DealershipAccount{
+				DealershipUserID:  user.ID,
+				Type:              accountType,
+				Provider:          provider,
+				ProviderAccountID: providerID,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return nil, err
+		}
+
+		return user, nil
+	}
+
+	if invitation.InternalUserID == nil {
+		return nil, data.ErrInvitationClosed
+	}
+
+	user, found, err := m.Db.InternalUsers.GetByID(*invitation.InternalUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, data.ErrInvitationClosed
+	}
+	if !filter.allows(user) {
+		return nil, errProviderNotAllowed
+	}
+
+	linked := ownerInternalUserID == user.ID
+	if !linked && (ownerDealershipUserID != 0 || ownerInternalUserID != 0) {
+		return nil, errIdentityTaken
+	}
+
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = m.Db.Invitations.TxAccept(tx, invitation)
+	if err != nil {
+		return nil, err
+	}
+
+	user.IsActive = true
+	err = m.Db.InternalUsers.TxUpdate(tx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if !linked {
+		err = m.Db.InternalAccounts.TxInsert(tx, &data.InternalAccount{
+			InternalUserID:    user.ID,
+			Type:              accountType,
+			Provider:          provider,
+			ProviderAccountID: providerID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// invitationForToken writes the error itself when the token is missing or
+// matches no invitation.
+func (m *AuthModule) invitationForToken(w http.ResponseWriter, r *http.Request, token string) (*data.Invitation, bool) {
+	if token == "" {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("missing token in query"))
+		return nil, false
+	}
+
+	invitation, found, err := m.Db.Invitations.GetByToken(token)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.InvitationInvalid, nil)
+		return nil, false
+	}
+
+	return invitation, true
+}
+
+// finishProviderLogin signs in whoever a provider callback authenticated. If
+// they came from an invitation link, the identity is linked to the invited
+// user instead of being matched by email. Either way the filter has the final
+// say on who the provider may sign in.
+func (m *AuthModule) finishProviderLogin(w http.ResponseWriter, r *http.Request, email, provider, providerID string, filter userFilter) {
+	var user data.AuthUser
+	var err error
+
+	if cookie, cookieErr := r.Cookie(invitationCookie); cookieErr == nil {
+		http.SetCookie(w, &http.Cookie{
+			Name:   invitationCookie,
+			Path:   "/api/auth",
+			MaxAge: -1,
+		})
+
+		invitation, ok := m.invitationForToken(w, r, cookie.Value)
+		if !ok {
+			return
+		}
+
+		user, err = m.acceptInvitation(invitation, "oidc", provider, providerID, filter)
+		if err != nil {
+			m.writeAcceptError(w, r, err)
+			return
+		}
+	} else {
+		var found bool
+		user, found, err = m.getUserFromProvider(email, provider, providerID, filter)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+
+		if !found {
+			m.WriteError(w, r, m.Err.AccountNotFound, nil)
+			return
+		}
+	}
+
+	redirect, err := m.signIn(user, w, r)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+func (m *AuthModule) writeAcceptError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, data.ErrInvitationClosed):
+		m.WriteError(w, r, m.Err.InvitationInvalid, err)
+	case errors.Is(err, errIdentityTaken):
+		m.WriteError(w, r, m.Err.Conflict, err)
+	case errors.Is(err, errProviderNotAllowed):
+		m.WriteError(w, r, m.Err.Forbidden, err)
+	default:
+		m.WriteError(w, r, m.Err.ServerError, err)
+	}
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+// recentImpersonations is how much of the audit trail the list shows.
+const recentImpersonations = 200
+
+// HandlePostImpersonation gives an admin an access token that views the app
+// as a dealership user. The token only reads, lasts ImpersonationTTL, has no
+// refresh token, and everything done with it is audited.
+func (m *AuthModule) HandlePostImpersonation(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		DealershipUserUUID string `json:"dealership_user_uuid" validate:"required,uuid4"`
+		Reason             string `json:"reason" validate:"required,min=3,max=500"`
+	}
+
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	admin := m.ContextGetInternalUser(r)
+
+	target, found, err := m.Db.DealershipUsers.GetByUUID(body.DealershipUserUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found || !target.IsActive {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	impersonation, plaintext, err := m.Db.Impersonations.Start(admin, target, body.Reason, m.sessionClient(r))
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.Log.Info("impersonation started",
+		"impersonation_uuid", impersonation.UUID,
+		"internal_user_uuid", admin.UUID,
+		"dealership_user_uuid", target.UUID,
+	)
+
+	m.WriteJSON(w, r, http.StatusCreated, map[string]any{
+		"access_token":     plaintext,
+		"access_token_exp": impersonation.ExpiresAt,
+		"impersonation":    impersonation,
+	})
+}
+
+// HandleGetImpersonations lists the most recent impersonations, newest first.
+func (m *AuthModule) HandleGetImpersonations(w http.ResponseWriter, r *http.Request) {
+	impersonations, err := m.Db.Impersonations.GetRecent(recentImpersonations)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, impersonations)
+}
+
+func (m *AuthModule) getImpersonation(w http.ResponseWriter, r *http.Request) (*data.Impersonation, bool) {
+	impersonationUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(impersonationUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	impersonation, found, err := m.Db.Impersonations.GetByUUID(impersonationUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return impersonation, true
+}
+
+// HandleGetImpersonation shows an impersonation with every request made
+// during it.
+func (m *AuthModule) HandleGetImpersonation(w http.ResponseWriter, r *http.Request) {
+	impersonation, ok := m.getImpersonation(w, r)
+	if !ok {
+		return
+	}
+
+	requests, err := m.Db.Impersonations.GetRequests(impersonation.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]any{
+		"impersonation": impersonation,
+		"requests":      requests,
+		"active":        impersonation.IsActive(time.Now()),
+	})
+}
+
+// HandleDeleteImpersonation ends an impersonation before it expires. The
+// record stays in the audit trail.
+func (m *AuthModule) HandleDeleteImpersonation(w http.ResponseWriter, r *http.Request) {
+	impersonation, ok := m.getImpersonation(w, r)
+	if !ok {
+		return
+	}
+
+	err := m.Db.Impersonations.End(impersonation)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, impersonation)
+}
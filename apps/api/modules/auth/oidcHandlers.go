@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"net/http"
+)
+
+// HandleGetOIDCProviders lists the registered providers the login page can
+// offer, with where each one's button should go.
+func (m *AuthModule) HandleGetOIDCProviders(w http.ResponseWriter, r *http.Request) {
+	providers, err := m.Db.OIDCProviders.GetActive()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	type loginOption struct {
+		Key      string `json:"key"`
+		Name     string `json:"name"`
+		LoginURL string `json:"login_url"`
+	}
+
+	options := make([]loginOption, len(providers))
+	for i, provider := range providers {
+		loginURL, err := m.oidcLoginURL(provider)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+
+		options[i] = loginOption{Key: provider.Key, Name: provider.Name, LoginURL: loginURL}
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, options)
+}
+
+func (m *AuthModule) HandleGetOIDCAuth(w http.ResponseWriter, r *http.Request) {
+	provider, ok := m.activeOIDCProvider(w, r, r.PathValue("key"))
+	if !ok {
+		return
+	}
+
+	authURL, err := m.oidcAuthURL(r.Context(), provider)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// HandleGetOIDCAuthCallback finishes signing in with a registered provider.
+// Only users of the dealerships the provider is enabled for get in, and an
+// email the provider says it has not verified is never used to match a user.
+func (m *AuthModule) HandleGetOIDCAuthCallback(w http.ResponseWriter, r *http.Request) {
+	provider, ok := m.activeOIDCProvider(w, r, r.PathValue("key"))
+	if !ok {
+		return
+	}
+
+	state := r.FormValue("state")
+	if err := m.validateState(state); err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	config, discovery, err := m.configOIDC(r.Context(), provider)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	token, err := config.Exchange(r.Context(), r.FormValue("code"))
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	claims, err := getOIDCUserInfo(r.Context(), discovery.UserinfoEndpoint, token.AccessToken)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	identity, err := provider.MapClaims(claims)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	email := identity.Email
+	if !identity.EmailVerified {
+		email = ""
+	}
+
+	m.finishProviderLogin(w, r, email, provider.Key, identity.Subject, oidcFilter(provider))
+}
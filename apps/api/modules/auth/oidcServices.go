@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"golang.org/x/oauth2"
+)
+
+// oidcClient talks to registered providers, which are outside our control, so
+// a slow one fails the sign-in rather than holding the request open.
+var oidcClient = &http.Client{Timeout: 10 * time.Second}
+
+// oidcDiscovery is the part of a provider's
+// .well-known/openid-configuration document the login flow needs.
+type oidcDiscovery struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+func getOIDCDiscovery(ctx context.Context, discoveryURL string) (*oidcDiscovery, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := oidcClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery returned %s", res.Status)
+	}
+
+	var discovery oidcDiscovery
+	err = json.NewDecoder(res.Body).Decode(&discovery)
+	if err != nil {
+		return nil, err
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.UserinfoEndpoint == "" {
+		return nil, errors.New("discovery document is missing an endpoint")
+	}
+
+	return &discovery, nil
+}
+
+func getOIDCUserInfo(ctx context.Context, userinfoURL, token string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, userinfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := oidcClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("userinfo returned %s", res.Status)
+	}
+
+	var claims map[string]any
+	err = json.NewDecoder(res.Body).Decode(&claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// oidcLoginURL is where the login page sends someone to sign in with a
+// registered provider.
+func (m *AuthModule) oidcLoginURL(provider *data.OIDCProvider) (string, error) {
+	return url.JoinPath(m.Cfg.BaseURL, "api", "auth", "oidc", provider.Key)
+}
+
+// configOIDC builds the OAuth client for a registered provider from its
+// discovery document.
+func (m *AuthModule) configOIDC(ctx context.Context, provider *data.OIDCProvider) (*oauth2.Config, *oidcDiscovery, error) {
+	discovery, err := getOIDCDiscovery(ctx, provider.DiscoveryURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientSecret, err := provider.OpenClientSecret(data.OIDCProviderKey(m.Cfg.AuthSecret))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	redirectURL, err := url.JoinPath(m.Cfg.BaseURL, "api", "auth", "oidc", provider.Key, "callback")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     provider.ClientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       provider.ScopeList(),
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}, discovery, nil
+}
+
+// activeOIDCProvider looks up the provider named in the path. Writes the
+// error itself when there is no active provider by that key.
+func (m *AuthModule) activeOIDCProvider(w http.ResponseWriter, r *http.Request, key string) (*data.OIDCProvider, bool) {
+	provider, found, err := m.Db.OIDCProviders.GetByKey(key)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+
+	if !found || !provider.IsActive {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return provider, true
+}
+
+// oidcAuthURL sends the browser to a registered provider's sign-in page.
+func (m *AuthModule) oidcAuthURL(ctx context.Context, provider *data.OIDCProvider) (string, error) {
+	config, _, err := m.configOIDC(ctx, provider)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := m.generateSecureState()
+	if err != nil {
+		return "", err
+	}
+
+	return config.AuthCodeURL(state), nil
+}
+
+// oidcFilter lets a registered provider sign in only the users of the
+// dealerships it is enabled for. Internal users sign in with Google,
+// Microsoft or a magic link.
+func oidcFilter(provider *data.OIDCProvider) userFilter {
+	return func(user data.AuthUser) bool {
+		dealershipUser, ok := user.(*data.DealershipUser)
+		return ok && provider.AllowsDealership(dealershipUser.DealershipID)
+	}
+}
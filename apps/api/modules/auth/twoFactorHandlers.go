@@ -0,0 +1,354 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+// HandleGetTwoFactorChallenge tells the code page whether the user has an
+// authenticator to enter a code from, or has to set one up first because the
+// policy requires it.
+func (m *AuthModule) HandleGetTwoFactorChallenge(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.challengeUser(w, r)
+	if !ok {
+		return
+	}
+
+	twoFactor, found, err := m.Db.TwoFactor.GetByInternalUserID(user.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]any{
+		"email":    user.Email,
+		"enrolled": found && twoFactor.IsEnabled(),
+	})
+}
+
+// HandlePostTwoFactorChallengeEnroll starts enrolment for a user the policy
+// stopped at the code page without an authenticator. Verifying the first code
+// confirms it and finishes signing in.
+func (m *AuthModule) HandlePostTwoFactorChallengeEnroll(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.challengeUser(w, r)
+	if !ok {
+		return
+	}
+
+	m.enroll(w, r, user)
+}
+
+// HandlePostTwoFactorChallengeVerify finishes signing in with a code from the
+// authenticator or a recovery code. The first code after enrolling confirms
+// the authenticator and returns the recovery codes, which are not shown again.
+func (m *AuthModule) HandlePostTwoFactorChallengeVerify(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.challengeUser(w, r)
+	if !ok {
+		return
+	}
+
+	var body secondFactor
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	twoFactor, found, err := m.Db.TwoFactor.GetByInternalUserID(user.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if !found {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("set up an authenticator first"))
+		return
+	}
+
+	enrolling := !twoFactor.IsEnabled()
+
+	ok, err = m.verifySecondFactor(twoFactor, body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if !ok {
+		if twoFactor.IsLocked() {
+			err = m.Db.InternalTokens.DeleteAllForUser(data.InternalScopeTwoFactor, user.ID)
+			if err != nil {
+				m.WriteError(w, r, m.Err.ServerError, err)
+				return
+			}
+			clearTwoFactorCookie(w)
+		}
+
+		m.WriteError(w, r, m.Err.TwoFactorInvalid, nil)
+		return
+	}
+
+	err = m.Db.InternalTokens.DeleteAllForUser(data.InternalScopeTwoFactor, user.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	clearTwoFactorCookie(w)
+
+	err = m.login(user, w, r)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	response := map[string]any{"success": true}
+	if enrolling {
+		codes, err := m.Db.TwoFactor.ReplaceRecoveryCodes(user.ID)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+		response["recovery_codes"] = codes
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, response)
+}
+
+// selfTwoFactorUser is the signed-in user, who has to be internal staff.
+func (m *AuthModule) selfTwoFactorUser(w http.ResponseWriter, r *http.Request) (*data.InternalUser, bool) {
+	user, ok := m.ContextGetUser(r).(*data.InternalUser)
+	if !ok {
+		m.WriteError(w, r, m.Err.Forbidden, errors.New("two-factor authentication is for internal users"))
+		return nil, false
+	}
+
+	return user, true
+}
+
+// HandleGetTwoFactor reports whether the signed-in user has two-factor
+// authentication on and whether the policy requires it.
+func (m *AuthModule) HandleGetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.selfTwoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	twoFactor, found, err := m.Db.TwoFactor.GetByInternalUserID(user.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	policy, err := m.Db.TwoFactor.GetPolicy()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	remaining, err := m.Db.TwoFactor.RemainingRecoveryCodes(user.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	permissions, err := m.ContextGetPermissions(r)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]any{
+		"enabled":                  found && twoFactor.IsEnabled(),
+		"required":                 policy.Requires(permissions),
+		"recovery_codes_remaining": remaining,
+	})
+}
+
+// HandlePostTwoFactorEnroll starts setting up an authenticator. It is not
+// turned on until HandlePostTwoFactorConfirm gets a code from it.
+func (m *AuthModule) HandlePostTwoFactorEnroll(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.selfTwoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	m.enroll(w, r, user)
+}
+
+func (m *AuthModule) enroll(w http.ResponseWriter, r *http.Request, user *data.InternalUser) {
+	_, secret, err := m.Db.TwoFactor.Enroll(user.ID, data.TwoFactorKey(m.Cfg.AuthSecret))
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrTwoFactorEnabled):
+			m.WriteError(w, r, m.Err.Conflict, err)
+		default:
+			m.WriteError(w, r, m.Err.ServerError, err)
+		}
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusCreated, map[string]any{
+		"secret":      secret,
+		"otpauth_url": data.TOTPURI(secret, user.Email),
+	})
+}
+
+// HandlePostTwoFactorConfirm turns on the authenticator being set up once it
+// produces a right code, and returns the recovery codes.
+func (m *AuthModule) HandlePostTwoFactorConfirm(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.selfTwoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Code string `json:"code"`
+	}
+
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	twoFactor, found, err := m.Db.TwoFactor.GetByInternalUserID(user.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if !found {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("set up an authenticator first"))
+		return
+	}
+
+	if twoFactor.IsEnabled() {
+		m.WriteError(w, r, m.Err.Conflict, data.ErrTwoFactorEnabled)
+		return
+	}
+
+	ok, err = m.verifySecondFactor(twoFactor, secondFactor{Code: body.Code})
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if !ok {
+		m.WriteError(w, r, m.Err.TwoFactorInvalid, nil)
+		return
+	}
+
+	codes, err := m.Db.TwoFactor.ReplaceRecoveryCodes(user.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]any{"recovery_codes": codes})
+}
+
+// enabledTwoFactor checks a code from the signed-in user's confirmed
+// authenticator, writing the error itself if there isn't one or the code is
+// wrong.
+func (m *AuthModule) enabledTwoFactor(w http.ResponseWriter, r *http.Request, user *data.InternalUser, factor secondFactor) bool {
+	twoFactor, found, err := m.Db.TwoFactor.GetByInternalUserID(user.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return false
+	}
+
+	if !found || !twoFactor.IsEnabled() {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return false
+	}
+
+	ok, err := m.verifySecondFactor(twoFactor, factor)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return false
+	}
+
+	if !ok {
+		m.WriteError(w, r, m.Err.TwoFactorInvalid, nil)
+		return false
+	}
+
+	return true
+}
+
+// HandleDeleteTwoFactor turns two-factor authentication off, given a current
+// code, unless the policy requires it for the user's role.
+func (m *AuthModule) HandleDeleteTwoFactor(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.selfTwoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	var body secondFactor
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	policy, err := m.Db.TwoFactor.GetPolicy()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	permissions, err := m.ContextGetPermissions(r)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if policy.Requires(permissions) {
+		m.WriteError(w, r, m.Err.TwoFactorRequired, nil)
+		return
+	}
+
+	if !m.enabledTwoFactor(w, r, user, body) {
+		return
+	}
+
+	err = m.Db.TwoFactor.Disable(user.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// HandlePostTwoFactorRecoveryCodes replaces the user's recovery codes, given
+// a current code from their authenticator.
+func (m *AuthModule) HandlePostTwoFactorRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.selfTwoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Code string `json:"code"`
+	}
+
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	if !m.enabledTwoFactor(w, r, user, secondFactor{Code: body.Code}) {
+		return
+	}
+
+	codes, err := m.Db.TwoFactor.ReplaceRecoveryCodes(user.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]any{"recovery_codes": codes})
+}
@@ -36,13 +36,14 @@ func (m ChatModule) getProjectWithAccessCheck(w http.ResponseWriter, r *http.Req
 		return nil, false
 	}
 
-	user := m.ContextGetUser(r)
-	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || *dealershipID != project.DealershipID {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
-			return nil, false
-		}
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return nil, false
 	}
 
 	return project, true
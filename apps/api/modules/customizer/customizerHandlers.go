@@ -3,6 +3,7 @@ package customizer
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/upload"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/svg"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
 )
 
 type CustomizerModule struct {
@@ -37,9 +39,10 @@ type bakeResponse struct {
 	Height         float64                `json:"height"`
 }
 
-// HandleBake renders a flat, self-contained SVG from a catalog item's canonical
-// SVG + the supplied color overrides, uploads it to S3, and returns the asset
-// URL. It creates no DB row — the future ordering flow persists these artifacts
+// HandleBake renders a self-contained preview SVG from a catalog item's
+// canonical SVG + the supplied color overrides, layering each glass color's
+// finish, texture, opacity and swatch over the flat fills, uploads it to S3,
+// and returns the asset URL. It creates no DB row — the future ordering flow persists these artifacts
 // onto an inlay_proof.
 func (m *CustomizerModule) HandleBake(w http.ResponseWriter, r *http.Request) {
 	uuid := r.PathValue("uuid")
@@ -80,11 +83,16 @@ func (m *CustomizerModule) HandleBake(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	glassHexByID, groutHexByID, err := m.colorMaps()
+	glassColors, grouts, err := m.palettes()
 	if err != nil {
 		m.WriteError(w, r, m.Err.ServerError, err)
 		return
 	}
+	if err := unavailableChoice(overrides, glassColors, grouts, time.Now()); err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+	glassHexByID, groutHexByID := colorMaps(glassColors, grouts)
 
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
@@ -101,7 +109,9 @@ func (m *CustomizerModule) HandleBake(w http.ResponseWriter, r *http.Request) {
 		scaleFactor = 1.0
 	}
 
-	baked, err := svg.BakeConsumer(structureSVG, manifest, scaleFactor, overrides, glassHexByID, groutHexByID)
+	appearanceByID := m.glassAppearances(ctx, glassColors, usedGlassColorIDs(manifest, overrides))
+
+	baked, err := svg.BakeConsumer(structureSVG, manifest, scaleFactor, overrides, glassHexByID, groutHexByID, appearanceByID)
 	if err != nil {
 		m.WriteError(w, r, m.Err.BadRequest, err)
 		return
@@ -129,7 +139,93 @@ func (m *CustomizerModule) HandleBake(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (m *CustomizerModule) colorMaps() (glass map[int]string, grout map[int]string, err error) {
+type themeRequest struct {
+	Family  *string `json:"family" validate:"required_without=SeedHex,excluded_with=SeedHex"`
+	SeedHex *string `json:"seed_hex" validate:"required_without=Family,omitempty,hexcolor"`
+}
+
+type themeResponse struct {
+	ColorOverrides svg.ColorOverrides `json:"color_overrides"`
+}
+
+// HandleTheme proposes color overrides that recolor a catalog item in one
+// glass family, or in the colors sharing a seed color's hue, keeping the
+// design's light-to-dark ordering. It only proposes: the dealer reviews the
+// result in the customizer and bakes it like any other coloring. Colors that
+// are out of stock are never proposed.
+func (m *CustomizerModule) HandleTheme(w http.ResponseWriter, r *http.Request) {
+	uuid := r.PathValue("uuid")
+	if err := m.Validate.Var(uuid, "required,uuid4"); err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	var body themeRequest
+	if err := m.ReadJSONBody(w, r, &body); err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	item, found, err := m.Db.CatalogItems.GetByUUID(uuid)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	var manifest svg.Manifest
+	if err := remarshal(item.Manifest, &manifest); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to decode manifest: %w", err))
+		return
+	}
+	if len(manifest.GlassRegions) == 0 {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("catalog item has no recolorable regions"))
+		return
+	}
+
+	glassColors, grouts, err := m.palettes()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	glassHexByID, _ := colorMaps(glassColors, grouts)
+
+	now := time.Now()
+	var candidates []svg.PaletteColor
+	for _, gc := range glassColors {
+		if !gc.IsAvailable(now) {
+			continue
+		}
+		if body.Family != nil && (gc.Family == nil || !strings.EqualFold(*gc.Family, *body.Family)) {
+			continue
+		}
+		candidates = append(candidates, svg.PaletteColor{ID: gc.ID, Hex: gc.Hex})
+	}
+	if body.SeedHex != nil {
+		candidates, err = svg.ThemeCandidatesFromSeed(*body.SeedHex, candidates)
+		if err != nil {
+			m.WriteError(w, r, m.Err.BadRequest, err)
+			return
+		}
+	}
+	if len(candidates) == 0 {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("no available glass colors match that theme"))
+		return
+	}
+
+	overrides, err := svg.Theme(manifest, glassHexByID, candidates)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, themeResponse{ColorOverrides: overrides})
+}
+
+func (m *CustomizerModule) palettes() ([]*data.GlassColor, []*data.Grout, error) {
 	glassColors, err := m.Db.GlassColors.GetAllActive()
 	if err != nil {
 		return nil, nil, err
@@ -138,7 +234,10 @@ func (m *CustomizerModule) colorMaps() (glass map[int]string, grout map[int]stri
 	if err != nil {
 		return nil, nil, err
 	}
+	return glassColors, grouts, nil
+}
 
+func colorMaps(glassColors []*data.GlassColor, grouts []*data.Grout) (glass map[int]string, grout map[int]string) {
 	glass = make(map[int]string, len(glassColors))
 	for _, gc := range glassColors {
 		glass[gc.ID] = gc.Hex
@@ -147,7 +246,98 @@ func (m *CustomizerModule) colorMaps() (glass map[int]string, grout map[int]stri
 	for _, g := range grouts {
 		grout[g.ID] = g.Hex
 	}
-	return glass, grout, nil
+	return glass, grout
+}
+
+// unavailableChoice rejects overrides that pick a color or grout marked out of
+// stock. Only explicit choices are checked: a catalog item's own defaults still
+// bake, so browsing a design never fails because one of its colors ran out.
+func unavailableChoice(overrides svg.ColorOverrides, glassColors []*data.GlassColor, grouts []*data.Grout, now time.Time) error {
+	chosen := map[int]bool{}
+	for _, ref := range overrides.Groups {
+		chosen[ref.GlassColorID] = true
+	}
+	for _, ref := range overrides.Pieces {
+		chosen[ref.GlassColorID] = true
+	}
+	for _, gc := range glassColors {
+		if chosen[gc.ID] && !gc.IsAvailable(now) {
+			return fmt.Errorf("glass color %q is unavailable until %s", gc.Name, gc.UnavailableUntil.Format("2006-01-02"))
+		}
+	}
+
+	if overrides.Background != nil {
+		for _, g := range grouts {
+			if g.ID == overrides.Background.GroutID && !g.IsAvailable(now) {
+				return fmt.Errorf("grout %q is unavailable until %s", g.Name, g.UnavailableUntil.Format("2006-01-02"))
+			}
+		}
+	}
+	return nil
+}
+
+// usedGlassColorIDs collects every glass color the bake can resolve to, so
+// swatches are only fetched for colors that actually appear.
+func usedGlassColorIDs(manifest svg.Manifest, overrides svg.ColorOverrides) map[int]bool {
+	used := map[int]bool{}
+	for _, region := range manifest.GlassRegions {
+		if region.GlassColorID != nil {
+			used[*region.GlassColorID] = true
+		}
+	}
+	for _, ref := range overrides.Groups {
+		used[ref.GlassColorID] = true
+	}
+	for _, ref := range overrides.Pieces {
+		used[ref.GlassColorID] = true
+	}
+	return used
+}
+
+// glassAppearances builds the preview appearance of each used glass color. A
+// swatch that cannot be fetched or is not a raster image is logged and skipped;
+// the color then falls back to its texture filter.
+func (m *CustomizerModule) glassAppearances(ctx context.Context, glassColors []*data.GlassColor, used map[int]bool) map[int]svg.GlassAppearance {
+	appearanceByID := make(map[int]svg.GlassAppearance, len(used))
+	for _, gc := range glassColors {
+		if !used[gc.ID] {
+			continue
+		}
+
+		appearance := svg.GlassAppearance{
+			Finish:  string(gc.Finish),
+			Texture: string(gc.Texture),
+			Opacity: gc.Opacity,
+		}
+		if gc.SwatchImageURL != nil && *gc.SwatchImageURL != "" {
+			dataURI, err := m.swatchDataURI(ctx, *gc.SwatchImageURL)
+			if err != nil {
+				m.Log.Error("failed to inline glass swatch", "error", err, "glass_color_id", gc.ID)
+			} else {
+				appearance.SwatchDataURI = dataURI
+			}
+		}
+		appearanceByID[gc.ID] = appearance
+	}
+	return appearanceByID
+}
+
+// swatchDataURI inlines a swatch photo. The baked SVG is served on its own, so
+// it cannot reference other assets by URL.
+func (m *CustomizerModule) swatchDataURI(ctx context.Context, url string) (string, error) {
+	raw, err := upload.GetFileFromS3(ctx, m.S3, m.Cfg, strings.TrimPrefix(url, "/"))
+	if err != nil {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(raw)
+	switch contentType {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+	default:
+		return "", fmt.Errorf("swatch %s is %s, not a raster image", url, contentType)
+	}
+
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(raw), nil
 }
 
 // remarshal converts between a generic JSONB map and a typed struct.
@@ -83,7 +83,14 @@ func (m DealershipModule) HandleGetDealershipByUUID(w http.ResponseWriter, r *ht
 		return
 	}
 
-	if !m.canManageDealership(r, dealership) {
+	// Group-level users can see the other locations in their group, though
+	// only their own location's admins can change it.
+	allowed, err := m.CanAccessDealership(r, dealership.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !allowed {
 		m.WriteError(w, r, m.Err.Forbidden, nil)
 		return
 	}
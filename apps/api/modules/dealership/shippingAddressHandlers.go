@@ -0,0 +1,196 @@
+package dealership
+
+import (
+	"net/http"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type shippingAddressBody struct {
+	Label      string `json:"label" validate:"required"`
+	Recipient  string `json:"recipient"`
+	Street     string `json:"street" validate:"required"`
+	StreetExt  string `json:"street_ext"`
+	City       string `json:"city" validate:"required"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country" validate:"required,iso3166_1_alpha2"`
+}
+
+// readShippingAddress reads the body and checks the address against its
+// country's rules, writing the error itself when either fails.
+func (m DealershipModule) readShippingAddress(w http.ResponseWriter, r *http.Request) (string, data.ShipTo, bool) {
+	var body shippingAddressBody
+
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return "", data.ShipTo{}, false
+	}
+
+	shipTo, err := data.NormalizeAddress(data.ShipTo{
+		Recipient:  body.Recipient,
+		Street:     body.Street,
+		StreetExt:  body.StreetExt,
+		City:       body.City,
+		State:      body.State,
+		PostalCode: body.PostalCode,
+		Country:    body.Country,
+	})
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return "", data.ShipTo{}, false
+	}
+
+	return body.Label, shipTo, true
+}
+
+// getDealershipForAddresses and getShippingAddress let a group-level user reach
+// every location in their group, the same as its projects, so they can pick a
+// sibling location's saved address when ordering on its behalf.
+func (m DealershipModule) getDealershipForAddresses(w http.ResponseWriter, r *http.Request) (*data.Dealership, bool) {
+	dealershipUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(dealershipUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	dealership, found, err := m.Db.Dealerships.GetByUUID(dealershipUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	allowed, err := m.CanAccessDealership(r, dealership.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return nil, false
+	}
+
+	return dealership, true
+}
+
+func (m DealershipModule) getShippingAddress(w http.ResponseWriter, r *http.Request) (*data.ShippingAddress, bool) {
+	addressUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(addressUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	address, found, err := m.Db.ShippingAddresses.GetByUUID(addressUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	allowed, err := m.CanAccessDealership(r, address.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return nil, false
+	}
+
+	return address, true
+}
+
+func (m DealershipModule) HandleGetShippingAddresses(w http.ResponseWriter, r *http.Request) {
+	dealership, ok := m.getDealershipForAddresses(w, r)
+	if !ok {
+		return
+	}
+
+	addresses, err := m.Db.ShippingAddresses.GetByDealershipID(dealership.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, addresses)
+}
+
+func (m DealershipModule) HandlePostShippingAddress(w http.ResponseWriter, r *http.Request) {
+	dealership, ok := m.getDealershipForAddresses(w, r)
+	if !ok {
+		return
+	}
+
+	label, shipTo, ok := m.readShippingAddress(w, r)
+	if !ok {
+		return
+	}
+
+	address := &data.ShippingAddress{
+		DealershipID: dealership.ID,
+		Label:        label,
+		ShipTo:       shipTo,
+	}
+
+	err := m.Db.ShippingAddresses.Insert(address)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusCreated, address)
+}
+
+// HandlePutShippingAddress edits a saved address. Orders already placed keep
+// the copy they were given.
+func (m DealershipModule) HandlePutShippingAddress(w http.ResponseWriter, r *http.Request) {
+	address, ok := m.getShippingAddress(w, r)
+	if !ok {
+		return
+	}
+
+	label, shipTo, ok := m.readShippingAddress(w, r)
+	if !ok {
+		return
+	}
+
+	address.Label = label
+	address.ShipTo = shipTo
+
+	err := m.Db.ShippingAddresses.Update(address)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, address)
+}
+
+// HandleDeleteShippingAddress removes a saved address. Drafts that had picked
+// it go back to shipping to the dealership.
+func (m DealershipModule) HandleDeleteShippingAddress(w http.ResponseWriter, r *http.Request) {
+	address, ok := m.getShippingAddress(w, r)
+	if !ok {
+		return
+	}
+
+	err := m.Db.ShippingAddresses.Delete(address.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]interface{}{"success": true})
+}
@@ -0,0 +1,120 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDealershipGroups_GroupUserSeesEveryLocation(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+
+	branch := &data.Dealership{
+		Name:                "Branch Location",
+		PaymentTiming:       data.PaymentTimings.PostShipping,
+		SandblastFileFormat: data.SandblastFileFormats.PDF,
+		Address: data.Address{
+			Street: "456 Side St", City: "Other City", State: "OS",
+			PostalCode: "67890", Country: "US", Latitude: 41.0, Longitude: -73.0,
+		},
+	}
+	require.NoError(t, ctx.db.Dealerships.Insert(branch))
+	branchProject := seedDraftProject(t, ctx, branch.ID, "Branch Project")
+	seedDraftProject(t, ctx, dealershipUser.DealershipID, "Head Office Project")
+	require.NoError(t, ctx.db.Invoices.Insert(&data.Invoice{
+		ProjectID:     branchProject.ID,
+		Status:        data.InvoiceStatuses.Sent,
+		SubtotalCents: 25000,
+		TotalCents:    25000,
+	}))
+
+	branchProjectPath := fmt.Sprintf("/api/project/%s", branchProject.UUID)
+	resp := ctx.request(testRequest{method: http.MethodGet, path: branchProjectPath, token: dealershipToken})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "a location cannot see its sibling's projects")
+
+	branchAddressesPath := fmt.Sprintf("/api/dealership/%s/shipping-addresses", branch.UUID)
+	resp = ctx.request(testRequest{method: http.MethodGet, path: branchAddressesPath, token: dealershipToken})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "nor its saved addresses")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/dealership-group",
+		token:  dealershipToken,
+		body:   map[string]any{"name": "Head Office"},
+	})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "only internal admins manage groups")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/dealership-group",
+		token:  internalToken,
+		body:   map[string]any{"name": "Head Office", "dealership_ids": []int{dealershipUser.DealershipID, branch.ID}},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var group data.DealershipGroup
+	require.NoError(t, json.Unmarshal(resp.body, &group))
+	assert.ElementsMatch(t, []int{dealershipUser.DealershipID, branch.ID}, group.DealershipIDs)
+
+	groupPath := "/api/dealership-group/" + group.UUID
+	resp = ctx.request(testRequest{method: http.MethodGet, path: groupPath + "/dashboard", token: dealershipToken})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "only group-level users see the group")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPut,
+		path:   "/api/dealership-user/" + dealershipUser.UUID + "/dealership-group",
+		token:  internalToken,
+		body:   map[string]any{"dealership_group_id": group.ID},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: branchProjectPath, token: dealershipToken})
+	assert.Equal(t, http.StatusOK, resp.statusCode, "group-level users see every location's projects")
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: branchAddressesPath, token: dealershipToken})
+	assert.Equal(t, http.StatusOK, resp.statusCode, "and its saved addresses, to ship its orders")
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/project", token: dealershipToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+	assert.Len(t, resp.parsed, 2)
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: groupPath + "/dashboard", token: dealershipToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+	assert.Len(t, resp.parsed.(map[string]any)["locations"], 2)
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: groupPath + "/statement", token: dealershipToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var statement data.GroupStatement
+	require.NoError(t, json.Unmarshal(resp.body, &statement))
+	require.Len(t, statement.Invoices, 1)
+	assert.Equal(t, branch.ID, statement.Invoices[0].DealershipID)
+	assert.Equal(t, int64(25000), statement.OutstandingCents)
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: groupPath + "/statement?status=bogus", token: dealershipToken})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPut,
+		path:   "/api/dealership-user/" + dealershipUser.UUID + "/permissions",
+		token:  internalToken,
+		body: map[string]any{"overrides": []map[string]any{
+			{"action": data.ActionViewInvoices, "granted": false},
+		}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: groupPath + "/dashboard", token: dealershipToken})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "the dashboard totals invoices too")
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: groupPath + "/statement", token: dealershipToken})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode)
+}
@@ -0,0 +1,200 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDealershipImport_DryRunThenApply(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+
+	existing, found, err := ctx.db.Dealerships.GetByID(dealershipUser.DealershipID)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	dealershipsCSV := strings.Join([]string{
+		"ref,name,payment_timing,sandblast_file_format,street,city,state,postal_code,country,latitude,longitude",
+		"north,North Memorials,post-shipping,pdf,1 North St,Northtown,NT,11111,us,45.0,-93.0",
+		"south,South Memorials,pre-shipping,svg,2 South St,Southtown,ST,22222,US,,",
+	}, "\n")
+	badUsersCSV := strings.Join([]string{
+		"dealership,name,email,role",
+		"north,Nora,nora@example.com,admin",
+		"north,Nora Again,NORA@example.com,viewer",
+		"south,Sam,sam@example.com,submitter",
+		"north,Taken," + dealershipUser.Email + ",viewer",
+		"north,Nobody,nobody@example.com,overlord",
+	}, "\n")
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/dealership-import",
+		token:  dealershipToken,
+		body:   map[string]any{"dealerships_csv": dealershipsCSV, "dry_run": true},
+	})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "only internal admins import dealerships")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/dealership-import",
+		token:  internalToken,
+		body:   map[string]any{"dealerships_csv": dealershipsCSV, "users_csv": badUsersCSV, "dry_run": true},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	report := resp.parsed.(map[string]any)
+	assert.Equal(t, float64(5), report["error_count"], "missing coordinates, and the users that depend on them, duplicate and unknown-role rows")
+	users := report["users"].([]any)
+	assert.Empty(t, users[0].(map[string]any)["errors"])
+	assert.NotEmpty(t, users[1].(map[string]any)["errors"], "emails are unique regardless of case")
+	assert.NotEmpty(t, users[2].(map[string]any)["errors"], "its dealership has errors")
+	assert.NotEmpty(t, users[3].(map[string]any)["errors"], "the email already belongs to a user")
+	assert.NotEmpty(t, users[4].(map[string]any)["errors"], "the role does not exist")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/dealership-import",
+		token:  internalToken,
+		body:   map[string]any{"dealerships_csv": dealershipsCSV, "users_csv": badUsersCSV},
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.statusCode, "an import with errors is not applied")
+
+	all, err := ctx.db.Dealerships.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	dealershipsCSV = strings.Join([]string{
+		"ref,name,payment_timing,sandblast_file_format,street,city,state,postal_code,country,latitude,longitude",
+		"north,North Memorials,post-shipping,pdf,1 North St,Northtown,NT,11111,us,45.0,-93.0",
+	}, "\n")
+	usersCSV := strings.Join([]string{
+		"dealership,name,email,role",
+		"north,Nora,nora@example.com,admin",
+		existing.UUID + ",Erin,erin@example.com,viewer",
+	}, "\n")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/dealership-import",
+		token:  internalToken,
+		body:   map[string]any{"dealerships_csv": dealershipsCSV, "users_csv": usersCSV, "send_invitations": true},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var applied struct {
+		Applied     bool `json:"applied"`
+		Dealerships []struct {
+			Dealership data.Dealership `json:"dealership"`
+		} `json:"dealerships"`
+		Users []struct {
+			User    data.DealershipUser `json:"user"`
+			Invited bool                `json:"invited"`
+		} `json:"users"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &applied))
+	assert.True(t, applied.Applied)
+	require.Len(t, applied.Users, 2)
+
+	north := applied.Dealerships[0].Dealership
+	assert.Equal(t, "US", north.Address.Country)
+	assert.Equal(t, north.ID, applied.Users[0].User.DealershipID)
+	assert.Equal(t, existing.ID, applied.Users[1].User.DealershipID)
+
+	for _, imported := range applied.Users {
+		assert.True(t, imported.Invited)
+		assert.False(t, imported.User.IsActive, "imported users wait for their invitation")
+
+		invitation, found, err := ctx.db.Invitations.GetLatestForUser(&imported.User)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, data.InvitationStatuses.Pending, invitation.Status)
+	}
+}
+
+// slowGeocoder takes a while to answer and remembers how many addresses it was
+// asked for at once. It cannot find anything in Nowhere.
+type slowGeocoder struct {
+	mu       sync.Mutex
+	inFlight int
+	most     int
+}
+
+func (g *slowGeocoder) Geocode(ctx context.Context, address data.Address) (float64, float64, error) {
+	g.mu.Lock()
+	g.inFlight++
+	g.most = max(g.most, g.inFlight)
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		g.inFlight--
+		g.mu.Unlock()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return 0, 0, ctx.Err()
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if address.City == "Nowhere" {
+		return 0, 0, app.ErrAddressNotFound
+	}
+	return 44.9, -93.2, nil
+}
+
+func TestDealershipImport_GeocodesAddressesAtOnce(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, _, _, internalToken := seedTestData(t, ctx)
+
+	geocoder := &slowGeocoder{}
+	ctx.app.Geocoder = geocoder
+
+	lines := []string{"ref,name,payment_timing,sandblast_file_format,street,city,state,postal_code,country"}
+	for i := range 6 {
+		lines = append(lines, fmt.Sprintf("d%d,Dealer %d,post-shipping,pdf,1 Main St,Northtown,NT,11111,US", i, i))
+	}
+	lines = append(lines, "lost,Lost Memorials,post-shipping,pdf,1 Main St,Nowhere,NT,11111,US")
+
+	started := time.Now()
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/dealership-import",
+		token:  internalToken,
+		body:   map[string]any{"dealerships_csv": strings.Join(lines, "\n"), "dry_run": true},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	assert.Greater(t, geocoder.most, 1, "addresses are geocoded concurrently")
+	assert.Less(t, time.Since(started), 7*200*time.Millisecond)
+
+	var report struct {
+		ErrorCount  int `json:"error_count"`
+		Dealerships []struct {
+			Errors     []string         `json:"errors"`
+			Dealership *data.Dealership `json:"dealership"`
+		} `json:"dealerships"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &report))
+	assert.Equal(t, 1, report.ErrorCount)
+	require.Len(t, report.Dealerships, 7)
+	require.NotNil(t, report.Dealerships[0].Dealership)
+	assert.Equal(t, 44.9, report.Dealerships[0].Dealership.Address.Latitude)
+	assert.Equal(t, []string{"the address could not be found"}, report.Dealerships[6].Errors)
+}
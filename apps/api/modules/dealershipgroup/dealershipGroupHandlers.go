@@ -0,0 +1,311 @@
+package dealershipgroup
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type DealershipGroupModule struct {
+	*app.Application
+}
+
+func NewDealershipGroupModule(app *app.Application) *DealershipGroupModule {
+	return &DealershipGroupModule{app}
+}
+
+type dealershipGroupRequest struct {
+	Name          string `json:"name" validate:"required,min=1,max=255"`
+	DealershipIDs []int  `json:"dealership_ids" validate:"dive,gt=0"`
+}
+
+func (m *DealershipGroupModule) getDealershipGroup(w http.ResponseWriter, r *http.Request) (*data.DealershipGroup, bool) {
+	groupUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(groupUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	group, found, err := m.Db.DealershipGroups.GetByUUID(groupUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return group, true
+}
+
+// getVisibleGroup loads the group in the path for internal users and for the
+// group's own group-level users.
+func (m *DealershipGroupModule) getVisibleGroup(w http.ResponseWriter, r *http.Request) (*data.DealershipGroup, bool) {
+	group, ok := m.getDealershipGroup(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	user := m.ContextGetUser(r)
+	if user.IsDealership() {
+		dealershipUser := m.ContextGetDealershipUser(r)
+		if dealershipUser.DealershipGroupID == nil || *dealershipUser.DealershipGroupID != group.ID {
+			m.WriteError(w, r, m.Err.Forbidden, nil)
+			return nil, false
+		}
+	}
+
+	return group, true
+}
+
+func (m *DealershipGroupModule) HandleGetDealershipGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := m.Db.DealershipGroups.GetAll()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, groups)
+}
+
+func (m *DealershipGroupModule) HandleGetDealershipGroup(w http.ResponseWriter, r *http.Request) {
+	group, ok := m.getVisibleGroup(w, r)
+	if !ok {
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, group)
+}
+
+// HandlePostDealershipGroup creates a group. A location already in another
+// group moves to this one.
+func (m *DealershipGroupModule) HandlePostDealershipGroup(w http.ResponseWriter, r *http.Request) {
+	var body dealershipGroupRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	missingID, missing, err := m.Db.Dealerships.FirstMissingID(body.DealershipIDs)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if missing {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("dealership %d not found", missingID))
+		return
+	}
+
+	group := &data.DealershipGroup{
+		Name: body.Name,
+	}
+
+	err = m.Db.DealershipGroups.Insert(group)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if len(body.DealershipIDs) > 0 {
+		group.DealershipIDs = body.DealershipIDs
+		err = m.Db.DealershipGroups.Update(group)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+	}
+
+	m.WriteJSON(w, r, http.StatusCreated, group)
+}
+
+// HandlePutDealershipGroup renames a group and replaces its locations.
+func (m *DealershipGroupModule) HandlePutDealershipGroup(w http.ResponseWriter, r *http.Request) {
+	var body dealershipGroupRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	group, ok := m.getDealershipGroup(w, r)
+	if !ok {
+		return
+	}
+
+	missingID, missing, err := m.Db.Dealerships.FirstMissingID(body.DealershipIDs)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if missing {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("dealership %d not found", missingID))
+		return
+	}
+
+	group.Name = body.Name
+	group.DealershipIDs = body.DealershipIDs
+	if group.DealershipIDs == nil {
+		group.DealershipIDs = []int{}
+	}
+
+	err = m.Db.DealershipGroups.Update(group)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, group)
+}
+
+func (m *DealershipGroupModule) HandleDeleteDealershipGroup(w http.ResponseWriter, r *http.Request) {
+	group, ok := m.getDealershipGroup(w, r)
+	if !ok {
+		return
+	}
+
+	err := m.Db.DealershipGroups.Delete(group.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
+
+// canViewGroupInvoices writes a Forbidden response and reports false when a
+// dealership user may not see invoices. Both the dashboard and the statement
+// total what the group owes, so both need it.
+func (m *DealershipGroupModule) canViewGroupInvoices(w http.ResponseWriter, r *http.Request) bool {
+	if !m.ContextGetUser(r).IsDealership() {
+		return true
+	}
+
+	allowed, err := m.Can(r, data.ActionViewInvoices)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return false
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return false
+	}
+
+	return true
+}
+
+// HandleGetDealershipGroupDashboard rolls the dealership dashboard up across
+// every location in the group, outstanding invoices included.
+func (m *DealershipGroupModule) HandleGetDealershipGroupDashboard(w http.ResponseWriter, r *http.Request) {
+	group, ok := m.getVisibleGroup(w, r)
+	if !ok {
+		return
+	}
+
+	if !m.canViewGroupInvoices(w, r) {
+		return
+	}
+
+	dashboard, err := m.Db.Dashboard.GetGroupDashboard(group.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, dashboard)
+}
+
+// HandleGetDealershipGroupStatement is the group's consolidated invoicing:
+// every location's invoices with what is outstanding across all of them.
+// ?status= narrows it to draft, sent, paid or void invoices.
+func (m *DealershipGroupModule) HandleGetDealershipGroupStatement(w http.ResponseWriter, r *http.Request) {
+	group, ok := m.getVisibleGroup(w, r)
+	if !ok {
+		return
+	}
+
+	if !m.canViewGroupInvoices(w, r) {
+		return
+	}
+
+	var status *data.InvoiceStatus
+	if s := r.URL.Query().Get("status"); s != "" {
+		err := m.Validate.Var(s, "oneof=draft sent paid void")
+		if err != nil {
+			m.WriteError(w, r, m.Err.BadRequest, err)
+			return
+		}
+		invoiceStatus := data.InvoiceStatus(s)
+		status = &invoiceStatus
+	}
+
+	statement, err := m.Db.DealershipGroups.GetStatement(group.ID, status)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, statement)
+}
+
+// HandlePutDealershipUserGroup makes a dealership user a group-level user, or
+// with a null dealership_group_id, a user of their own location only. The
+// user's location must be in the group.
+func (m *DealershipGroupModule) HandlePutDealershipUserGroup(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		DealershipGroupID *int `json:"dealership_group_id" validate:"omitempty,gt=0"`
+	}
+
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	userUUID := r.PathValue("uuid")
+	err = m.Validate.Var(userUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	user, found, err := m.Db.DealershipUsers.GetByUUID(userUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	if body.DealershipGroupID != nil {
+		dealership, found, err := m.Db.Dealerships.GetByID(user.DealershipID)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+		if !found || dealership.DealershipGroupID == nil || *dealership.DealershipGroupID != *body.DealershipGroupID {
+			m.WriteError(w, r, m.Err.BadRequest, errors.New("the user's dealership is not in that group"))
+			return
+		}
+	}
+
+	user.DealershipGroupID = body.DealershipGroupID
+
+	err = m.Db.DealershipUsers.Update(user)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, user)
+}
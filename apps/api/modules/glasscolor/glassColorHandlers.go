@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
 	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
@@ -18,9 +19,29 @@ func NewGlassColorModule(app *app.Application) *GlassColorModule {
 }
 
 // HandleGetGlassColors returns the active glass palette, sorted, as a flat array
-// for the customizer's color picker.
+// for the customizer's color picker. An optional ?finish=opalescent,iridescent
+// narrows it to those finishes.
 func (m *GlassColorModule) HandleGetGlassColors(w http.ResponseWriter, r *http.Request) {
-	glassColors, err := m.Db.GlassColors.GetAllActive()
+	var finishes []data.GlassFinish
+	if f := r.URL.Query().Get("finish"); f != "" {
+		for _, finish := range strings.Split(f, ",") {
+			finish = strings.TrimSpace(finish)
+			err := m.Validate.Var(finish, "oneof=cathedral opalescent iridescent")
+			if err != nil {
+				m.WriteError(w, r, m.Err.BadRequest, err)
+				return
+			}
+			finishes = append(finishes, data.GlassFinish(finish))
+		}
+	}
+
+	var glassColors []*data.GlassColor
+	var err error
+	if len(finishes) > 0 {
+		glassColors, err = m.Db.GlassColors.GetAllActiveByFinish(finishes)
+	} else {
+		glassColors, err = m.Db.GlassColors.GetAllActive()
+	}
 	if err != nil {
 		m.WriteError(w, r, m.Err.ServerError, err)
 		return
@@ -96,11 +117,15 @@ func (m *GlassColorModule) HandleGetGlassColorsAdmin(w http.ResponseWriter, r *h
 
 func (m *GlassColorModule) HandlePostGlassColor(w http.ResponseWriter, r *http.Request) {
 	var body struct {
-		Name      string  `json:"name" validate:"required,min=1,max=255"`
-		Hex       string  `json:"hex" validate:"required,hexcolor"`
-		Family    *string `json:"family"`
-		SortOrder int     `json:"sort_order"`
-		IsActive  bool    `json:"is_active"`
+		Name           string   `json:"name" validate:"required,min=1,max=255"`
+		Hex            string   `json:"hex" validate:"required,hexcolor"`
+		Family         *string  `json:"family"`
+		SortOrder      int      `json:"sort_order"`
+		IsActive       bool     `json:"is_active"`
+		Finish         string   `json:"finish" validate:"omitempty,oneof=cathedral opalescent iridescent"`
+		Texture        string   `json:"texture" validate:"omitempty,oneof=smooth streaky rippled seedy hammered granite"`
+		Opacity        *float64 `json:"opacity" validate:"omitempty,gt=0,lte=1"`
+		SwatchImageURL *string  `json:"swatch_image_url"`
 	}
 
 	err := m.ReadJSONBody(w, r, &body)
@@ -109,12 +134,22 @@ func (m *GlassColorModule) HandlePostGlassColor(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Glass is fully opaque unless the request says otherwise.
+	opacity := 1.0
+	if body.Opacity != nil {
+		opacity = *body.Opacity
+	}
+
 	glassColor := &data.GlassColor{
-		Name:      body.Name,
-		Hex:       body.Hex,
-		Family:    body.Family,
-		SortOrder: body.SortOrder,
-		IsActive:  body.IsActive,
+		Name:           body.Name,
+		Hex:            body.Hex,
+		Family:         body.Family,
+		SortOrder:      body.SortOrder,
+		IsActive:       body.IsActive,
+		Finish:         data.GlassFinish(body.Finish),
+		Texture:        data.GlassTexture(body.Texture),
+		Opacity:        opacity,
+		SwatchImageURL: body.SwatchImageURL,
 	}
 
 	err = m.Db.GlassColors.Insert(glassColor)
@@ -134,11 +169,15 @@ func (m *GlassColorModule) HandlePatchGlassColor(w http.ResponseWriter, r *http.
 	}
 
 	var body struct {
-		Name      *string `json:"name"`
-		Hex       *string `json:"hex" validate:"omitempty,hexcolor"`
-		Family    *string `json:"family"`
-		SortOrder *int    `json:"sort_order"`
-		IsActive  *bool   `json:"is_active"`
+		Name           *string  `json:"name"`
+		Hex            *string  `json:"hex" validate:"omitempty,hexcolor"`
+		Family         *string  `json:"family"`
+		SortOrder      *int     `json:"sort_order"`
+		IsActive       *bool    `json:"is_active"`
+		Finish         *string  `json:"finish" validate:"omitempty,oneof=cathedral opalescent iridescent"`
+		Texture        *string  `json:"texture" validate:"omitempty,oneof=smooth streaky rippled seedy hammered granite"`
+		Opacity        *float64 `json:"opacity" validate:"omitempty,gt=0,lte=1"`
+		SwatchImageURL *string  `json:"swatch_image_url"`
 	}
 
 	err := m.ReadJSONBody(w, r, &body)
@@ -172,6 +211,18 @@ func (m *GlassColorModule) HandlePatchGlassColor(w http.ResponseWriter, r *http.
 	if body.IsActive != nil {
 		glassColor.IsActive = *body.IsActive
 	}
+	if body.Finish != nil {
+		glassColor.Finish = data.GlassFinish(*body.Finish)
+	}
+	if body.Texture != nil {
+		glassColor.Texture = data.GlassTexture(*body.Texture)
+	}
+	if body.Opacity != nil {
+		glassColor.Opacity = *body.Opacity
+	}
+	if body.SwatchImageURL != nil {
+		glassColor.SwatchImageURL = body.SwatchImageURL
+	}
 
 	err = m.Db.GlassColors.Update(glassColor)
 	if err != nil {
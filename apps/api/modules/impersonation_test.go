@@ -0,0 +1,74 @@
+package modules
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonation_ReadOnlyAndAudited(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, internalUser, internalToken := seedTestData(t, ctx)
+
+	start := map[string]any{
+		"dealership_user_uuid": dealershipUser.UUID,
+		"reason":               "Cannot see the approve button",
+	}
+
+	resp := ctx.request(testRequest{method: http.MethodPost, path: "/api/auth/impersonation", token: dealershipToken, body: start})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "only admins can impersonate")
+
+	resp = ctx.request(testRequest{method: http.MethodPost, path: "/api/auth/impersonation", token: internalToken, body: start})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	started := resp.parsed.(map[string]any)
+	viewAs := started["access_token"].(string)
+	impersonation := started["impersonation"].(map[string]any)
+	impersonationUUID := impersonation["uuid"].(string)
+	assert.Equal(t, internalUser.Email, impersonation["internal_user_email"])
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/user/self", token: viewAs})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+	self := resp.parsed.(map[string]any)
+	assert.Equal(t, dealershipUser.Email, self["email"], "the token acts as the dealership user")
+	require.NotNil(t, self["impersonation"], "the app can tell it is being viewed as")
+	assert.Equal(t, impersonationUUID, self["impersonation"].(map[string]any)["uuid"])
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/user/self", token: dealershipToken})
+	require.Equal(t, http.StatusOK, resp.statusCode)
+	assert.Nil(t, resp.parsed.(map[string]any)["impersonation"])
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/project",
+		token:  viewAs,
+		body:   map[string]any{"name": "Made while impersonating"},
+	})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "writes are refused")
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/impersonations/" + impersonationUUID, token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	detail := resp.parsed.(map[string]any)
+	assert.Equal(t, true, detail["active"])
+	requests := detail["requests"].([]any)
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/api/user/self", requests[0].(map[string]any)["path"])
+	assert.Equal(t, false, requests[0].(map[string]any)["blocked"])
+	assert.Equal(t, http.MethodPost, requests[1].(map[string]any)["method"])
+	assert.Equal(t, true, requests[1].(map[string]any)["blocked"])
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/impersonations", token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode)
+	assert.Len(t, resp.parsed.([]any), 1)
+
+	resp = ctx.request(testRequest{method: http.MethodDelete, path: "/api/impersonations/" + impersonationUUID, token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/user/self", token: viewAs})
+	assert.Equal(t, http.StatusUnauthorized, resp.statusCode, "an ended impersonation's token stops working")
+}
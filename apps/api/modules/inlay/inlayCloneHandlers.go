@@ -0,0 +1,270 @@
+package inlay
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type reorderResponse struct {
+	Project *data.Project `json:"project"`
+	Inlays  []*data.Inlay `json:"inlays"`
+}
+
+// HandleReorderProject starts a new draft from a past project, copying all of
+// its inlays or just the ones named. The source can be in any status: reorders
+// are usually of delivered work. See txCloneInlay for what carries over.
+func (m InlayModule) HandleReorderProject(w http.ResponseWriter, r *http.Request) {
+	projectUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(projectUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	var body struct {
+		Name       *string  `json:"name" validate:"omitempty,min=1"`
+		InlayUUIDs []string `json:"inlay_uuids" validate:"omitempty,dive,uuid4"`
+	}
+
+	err = m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	user := m.ContextGetUser(r)
+	if !user.IsDealership() {
+		m.WriteError(w, r, m.Err.Forbidden, fmt.Errorf("only dealership users can create projects"))
+		return
+	}
+
+	source, ok := m.getProjectForInlayAccess(w, r, projectUUID)
+	if !ok {
+		return
+	}
+
+	inlays, err := m.Db.Inlays.GetByProjectID(source.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if len(body.InlayUUIDs) > 0 {
+		wanted := make(map[string]bool, len(body.InlayUUIDs))
+		for _, uuid := range body.InlayUUIDs {
+			wanted[uuid] = true
+		}
+		chosen := make([]*data.Inlay, 0, len(body.InlayUUIDs))
+		for _, inlay := range inlays {
+			if wanted[inlay.UUID] {
+				chosen = append(chosen, inlay)
+			}
+		}
+		if len(chosen) != len(wanted) {
+			m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("every inlay to reorder must belong to this project"))
+			return
+		}
+		inlays = chosen
+	}
+
+	if len(inlays) == 0 {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("project has no inlays to reorder"))
+		return
+	}
+
+	sources, ok := m.resolveCloneSources(w, r, inlays)
+	if !ok {
+		return
+	}
+
+	name := fmt.Sprintf("%s (reorder)", source.Name)
+	if body.Name != nil && strings.TrimSpace(*body.Name) != "" {
+		name = strings.TrimSpace(*body.Name)
+	}
+
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	project := &data.Project{
+		DealershipID:    source.DealershipID,
+		Name:            name,
+		Status:          data.ProjectStatuses.Draft,
+		InstallationKit: source.InstallationKit,
+	}
+	if err := m.Db.Projects.TxInsert(tx, project); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to create reorder project: %w", err))
+		return
+	}
+
+	now := time.Now()
+	cloned := make([]*data.Inlay, len(sources))
+	outcomes := make([]cloneOutcome, len(sources))
+	for i, cloneSource := range sources {
+		cloned[i], outcomes[i], err = m.txCloneInlay(tx, cloneSource, project.ID, now)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+	}
+
+	if err := m.Db.ProjectWatchers.TxAutoSubscribe(tx, project.ID, user); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to subscribe to reorder project: %w", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.notifyCloned(project.ID, user, cloned, outcomes)
+
+	m.WriteJSON(w, r, http.StatusCreated, reorderResponse{Project: project, Inlays: cloned})
+}
+
+// HandleCloneInlays copies inlays from any of the dealership's projects into
+// this draft, for adding a past design to an order already being put together.
+func (m InlayModule) HandleCloneInlays(w http.ResponseWriter, r *http.Request) {
+	projectUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(projectUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	var body struct {
+		InlayUUIDs []string `json:"inlay_uuids" validate:"required,min=1,dive,uuid4"`
+	}
+
+	err = m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	project, ok := m.getProjectForInlayAccess(w, r, projectUUID)
+	if !ok {
+		return
+	}
+
+	if project.Status != data.ProjectStatuses.Draft {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("can only add inlays to projects in draft status, current status: %s", project.Status))
+		return
+	}
+
+	inlays := make([]*data.Inlay, 0, len(body.InlayUUIDs))
+	for _, inlayUUID := range body.InlayUUIDs {
+		inlay, found, err := m.Db.Inlays.GetByUUID(inlayUUID)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+		if !found {
+			m.WriteError(w, r, m.Err.RecordNotFound, nil)
+			return
+		}
+
+		// Copies never cross dealerships, even for internal users.
+		owner, found, err := m.Db.Projects.GetByID(inlay.ProjectID)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+		if !found || owner.DealershipID != project.DealershipID {
+			m.WriteError(w, r, m.Err.Forbidden, nil)
+			return
+		}
+
+		inlays = append(inlays, inlay)
+	}
+
+	sources, ok := m.resolveCloneSources(w, r, inlays)
+	if !ok {
+		return
+	}
+
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	cloned := make([]*data.Inlay, len(sources))
+	outcomes := make([]cloneOutcome, len(sources))
+	for i, cloneSource := range sources {
+		cloned[i], outcomes[i], err = m.txCloneInlay(tx, cloneSource, project.ID, now)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	user := m.ContextGetUser(r)
+	m.AutoWatchProject(project.ID, user)
+	m.notifyCloned(project.ID, user, cloned, outcomes)
+
+	m.WriteJSON(w, r, http.StatusCreated, cloned)
+}
+
+// resolveCloneSources resolves every inlay before anything is written, so one
+// that cannot be reordered fails the request without a partial copy.
+func (m InlayModule) resolveCloneSources(w http.ResponseWriter, r *http.Request, inlays []*data.Inlay) ([]*cloneSource, bool) {
+	sources := make([]*cloneSource, len(inlays))
+	for i, inlay := range inlays {
+		source, err := m.resolveCloneSource(inlay)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return nil, false
+		}
+		if source.catalogItem != nil && !source.catalogItem.IsActive {
+			m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("inlay %q uses a catalog item that is no longer offered", inlay.Name))
+			return nil, false
+		}
+		sources[i] = source
+	}
+	return sources, true
+}
+
+// notifyCloned tells internal staff about copies that need them: a coloring
+// to price, or a custom design to proof again.
+func (m InlayModule) notifyCloned(projectID int, user data.AuthUser, inlays []*data.Inlay, outcomes []cloneOutcome) {
+	for i, inlay := range inlays {
+		switch outcomes[i] {
+		case cloneNeedsReview:
+			m.NotifyInternal(
+				projectID,
+				user,
+				data.NotificationEventTypes.InternalReviewRequired,
+				fmt.Sprintf("Reordered inlay needs review: %s", inlay.Name),
+				fmt.Sprintf("The reordered inlay %q carries a customization that needs pricing review before it can be ordered again.", inlay.Name),
+				&inlay.ID,
+			)
+		case cloneNeedsProof:
+			m.NotifyInternal(
+				projectID,
+				user,
+				data.NotificationEventTypes.CustomInlaySubmitted,
+				fmt.Sprintf("Reordered custom inlay needs a proof: %s", inlay.Name),
+				fmt.Sprintf("The reordered custom inlay %q could not reuse its earlier proof and needs a designer to create a new one.", inlay.Name),
+				&inlay.ID,
+			)
+		}
+	}
+}
@@ -23,13 +23,15 @@ type InlayCatalogItemRef struct {
 // InlayDetail is the response shape for a single inlay. It extends the list
 // shape with everything the inlay page renders on its own: which catalog design
 // this came from, the proofs that matter (approved and latest), and the
-// immutable order snapshot once the project has been ordered.
+// immutable order snapshot once the project has been ordered, and any remakes
+// opened after delivery.
 type InlayDetail struct {
 	InlayWithProofStatus
 	CatalogItem   *InlayCatalogItemRef `json:"catalog_item"`
 	ApprovedProof *data.InlayProof     `json:"approved_proof"`
 	LatestProof   *data.InlayProof     `json:"latest_proof"`
 	OrderSnapshot *data.OrderSnapshot  `json:"order_snapshot"`
+	Remakes       []*data.InlayRemake  `json:"remakes"`
 }
 
 // applyDeleteBlockers records which dependent rows block deletion. Callers that
@@ -69,6 +71,8 @@ func (m InlayModule) buildInlayWithProofStatus(inlay *data.Inlay) (InlayWithProo
 	result.PriceGroupID = pricing.PriceGroupID
 	result.PriceGroupName = pricing.PriceGroupName
 	result.PriceCents = pricing.PriceCents
+	result.ListPriceCents = pricing.ListPriceCents
+	result.TierDiscountCents = pricing.TierDiscountCents
 	result.PriceAdjustmentType = pricing.AdjustmentType
 	result.PriceAdjustmentValue = pricing.AdjustmentValue
 
@@ -130,5 +134,11 @@ func (m InlayModule) buildInlayDetail(inlay *data.Inlay) (*InlayDetail, error) {
 		detail.OrderSnapshot = snapshot
 	}
 
+	remakes, err := m.Db.InlayRemakes.GetByInlayID(inlay.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load remakes for inlay %d: %w", inlay.ID, err)
+	}
+	detail.Remakes = remakes
+
 	return &detail, nil
 }
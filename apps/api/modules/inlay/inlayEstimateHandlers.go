@@ -0,0 +1,368 @@
+package inlay
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+// slowStepWindow is the recent stretch the slow-steps report compares against
+// the longer history before it.
+const slowStepWindow = 14 * 24 * time.Hour
+
+// slowStepRatio is how far the recent median may run past the usual median
+// before a step is called slow.
+const slowStepRatio = 1.25
+
+// InlayShipEstimate is the predicted ready-to-ship date for one inlay. The
+// dates are null while the inlay is not in production or a remaining step has
+// no history yet.
+type InlayShipEstimate struct {
+	InlayUUID  string                  `json:"inlay_uuid"`
+	InlayName  string                  `json:"inlay_name"`
+	Step       *data.ManufacturingStep `json:"step"`
+	ExpectedAt *time.Time              `json:"expected_at"`
+	LatestAt   *time.Time              `json:"latest_at"`
+}
+
+// ProjectShipEstimate dates a project by its slowest inlay still to ship.
+type ProjectShipEstimate struct {
+	ProjectUUID string              `json:"project_uuid"`
+	ExpectedAt  *time.Time          `json:"expected_at"`
+	LatestAt    *time.Time          `json:"latest_at"`
+	Inlays      []InlayShipEstimate `json:"inlays"`
+}
+
+func (m InlayModule) shipEstimator(now time.Time) (*data.ShipEstimator, error) {
+	from := now.AddDate(0, 0, -data.StepHistoryDays)
+
+	overall, err := m.Db.InlayMilestones.GetStepDurations(from, now, data.StepGroupings.None)
+	if err != nil {
+		return nil, err
+	}
+	byPriceGroup, err := m.Db.InlayMilestones.GetStepDurations(from, now, data.StepGroupings.PriceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.NewShipEstimator(overall, byPriceGroup), nil
+}
+
+// estimateInlays dates each inlay from the step it is in now and how long it
+// has been there. The step an order starts in has no milestone, so time in it
+// runs from when the project was ordered.
+func (m InlayModule) estimateInlays(project *data.Project, inlays []*data.Inlay, now time.Time) ([]InlayShipEstimate, error) {
+	estimator, err := m.shipEstimator(now)
+	if err != nil {
+		return nil, err
+	}
+
+	inlayIDs := make([]int, len(inlays))
+	for i, inlay := range inlays {
+		inlayIDs[i] = inlay.ID
+	}
+	entries, err := m.Db.InlayMilestones.GetCurrentStepEntries(inlayIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := m.Db.OrderSnapshots.GetByProjectID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	priceGroups := make(map[int]int, len(snapshots))
+	for _, snapshot := range snapshots {
+		if snapshot.RemakeID == nil {
+			priceGroups[snapshot.InlayID] = snapshot.PriceGroupID
+		}
+	}
+
+	estimates := make([]InlayShipEstimate, len(inlays))
+	for i, inlay := range inlays {
+		estimates[i] = InlayShipEstimate{
+			InlayUUID: inlay.UUID,
+			InlayName: inlay.Name,
+		}
+		if inlay.ManufacturingStep == nil {
+			continue
+		}
+
+		step := data.ManufacturingStep(*inlay.ManufacturingStep)
+		estimates[i].Step = &step
+
+		var enteredAt *time.Time
+		if entry, ok := entries[inlay.ID]; ok && entry.Step == step {
+			enteredAt = &entry.EventTime
+		} else if step == data.ManufacturingSteps.Ordered {
+			enteredAt = project.OrderedAt
+		}
+		if enteredAt == nil {
+			continue
+		}
+
+		estimate, ok := estimator.Estimate(step, *enteredAt, priceGroups[inlay.ID], now)
+		if !ok {
+			continue
+		}
+		estimates[i].ExpectedAt = &estimate.ExpectedAt
+		estimates[i].LatestAt = &estimate.LatestAt
+	}
+
+	return estimates, nil
+}
+
+func (m InlayModule) HandleGetInlayShipEstimate(w http.ResponseWriter, r *http.Request) {
+	inlayUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(inlayUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	inlay, found, err := m.Db.Inlays.GetByUUID(inlayUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	project, ok := m.validateInlayOwnership(w, r, inlay)
+	if !ok {
+		return
+	}
+
+	estimates, err := m.estimateInlays(project, []*data.Inlay{inlay}, time.Now())
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, estimates[0])
+}
+
+// HandleGetProjectShipEstimate dates every inlay of the project that is in
+// production and not yet shipped. The project's dates are null until each of
+// those inlays can be dated.
+func (m InlayModule) HandleGetProjectShipEstimate(w http.ResponseWriter, r *http.Request) {
+	projectUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(projectUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	project, ok := m.getProjectForInlayAccess(w, r, projectUUID)
+	if !ok {
+		return
+	}
+
+	inlays, err := m.Db.Inlays.GetByProjectID(project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	shipments, err := m.Db.Shipments.GetByProjectID(project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	shipped := make(map[int]bool)
+	for _, shipment := range shipments {
+		if shipment.RemakeID != nil {
+			continue
+		}
+		for _, inlayID := range shipment.InlayIDs {
+			shipped[inlayID] = true
+		}
+	}
+
+	var pending []*data.Inlay
+	for _, inlay := range inlays {
+		if inlay.ManufacturingStep != nil && !shipped[inlay.ID] {
+			pending = append(pending, inlay)
+		}
+	}
+
+	estimates, err := m.estimateInlays(project, pending, time.Now())
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	response := ProjectShipEstimate{
+		ProjectUUID: project.UUID,
+		Inlays:      estimates,
+	}
+	for i, estimate := range estimates {
+		if estimate.ExpectedAt == nil {
+			response.ExpectedAt = nil
+			response.LatestAt = nil
+			break
+		}
+		if i == 0 || estimate.ExpectedAt.After(*response.ExpectedAt) {
+			response.ExpectedAt = estimate.ExpectedAt
+		}
+		if i == 0 || estimate.LatestAt.After(*response.LatestAt) {
+			response.LatestAt = estimate.LatestAt
+		}
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, response)
+}
+
+// HandleGetStepDurations reports historical time spent in each step, for every
+// inlay or split by ?group_by=price_group or inlay_type.
+func (m InlayModule) HandleGetStepDurations(w http.ResponseWriter, r *http.Request) {
+	grouping := data.StepGrouping(r.URL.Query().Get("group_by"))
+
+	err := m.Validate.Var(string(grouping), "omitempty,oneof=price_group inlay_type")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("invalid group_by: %s", grouping))
+		return
+	}
+
+	now := time.Now()
+	durations, err := m.Db.InlayMilestones.GetStepDurations(now.AddDate(0, 0, -data.StepHistoryDays), now, grouping)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, durations)
+}
+
+// StuckInlay is an inlay that has been in its step longer than nine in ten
+// inlays usually are.
+type StuckInlay struct {
+	InlayUUID   string  `json:"inlay_uuid"`
+	InlayName   string  `json:"inlay_name"`
+	HoursInStep float64 `json:"hours_in_step"`
+}
+
+// SlowStep compares the last two weeks in a step with the history before
+// them. Slow needs enough stays on both sides to mean anything.
+type SlowStep struct {
+	Step             data.ManufacturingStep `json:"step"`
+	BaselineSamples  int                    `json:"baseline_samples"`
+	BaselineP50Hours float64                `json:"baseline_p50_hours"`
+	BaselineP90Hours float64                `json:"baseline_p90_hours"`
+	RecentSamples    int                    `json:"recent_samples"`
+	RecentP50Hours   float64                `json:"recent_p50_hours"`
+	Slow             bool                   `json:"slow"`
+	StuckInlays      []StuckInlay           `json:"stuck_inlays"`
+}
+
+// HandleGetSlowSteps is the production report of steps running slower than
+// normal, with the inlays sitting in each one well past the usual time.
+func (m InlayModule) HandleGetSlowSteps(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	recentFrom := now.Add(-slowStepWindow)
+
+	baseline, err := m.Db.InlayMilestones.GetStepDurations(now.AddDate(0, 0, -data.StepHistoryDays), recentFrom, data.StepGroupings.None)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	recent, err := m.Db.InlayMilestones.GetStepDurations(recentFrom, now, data.StepGroupings.None)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	baselineByStep := make(map[data.ManufacturingStep]data.StepDuration, len(baseline))
+	for _, d := range baseline {
+		baselineByStep[d.Step] = d
+	}
+	recentByStep := make(map[data.ManufacturingStep]data.StepDuration, len(recent))
+	for _, d := range recent {
+		recentByStep[d.Step] = d
+	}
+
+	var report []SlowStep
+	for _, step := range manufacturingStepOrder {
+		if step == data.ManufacturingSteps.ReadyToShip {
+			continue
+		}
+
+		b := baselineByStep[step]
+		c := recentByStep[step]
+		slowStep := SlowStep{
+			Step:             step,
+			BaselineSamples:  b.Samples,
+			BaselineP50Hours: b.P50Hours,
+			BaselineP90Hours: b.P90Hours,
+			RecentSamples:    c.Samples,
+			RecentP50Hours:   c.P50Hours,
+			Slow: b.Samples >= data.MinStepSamples && c.Samples >= data.MinStepSamples &&
+				c.P50Hours >= b.P50Hours*slowStepRatio,
+			StuckInlays: []StuckInlay{},
+		}
+
+		if b.Samples >= data.MinStepSamples {
+			stuck, stuckErr := m.stuckInlays(step, b.P90Hours, now)
+			if stuckErr != nil {
+				m.WriteError(w, r, m.Err.ServerError, stuckErr)
+				return
+			}
+			slowStep.StuckInlays = stuck
+		}
+
+		report = append(report, slowStep)
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, report)
+}
+
+func (m InlayModule) stuckInlays(step data.ManufacturingStep, limitHours float64, now time.Time) ([]StuckInlay, error) {
+	inlays, err := m.Db.Inlays.GetByManufacturingStep(step)
+	if err != nil {
+		return nil, err
+	}
+
+	inlayIDs := make([]int, len(inlays))
+	for i, inlay := range inlays {
+		inlayIDs[i] = inlay.ID
+	}
+	entries, err := m.Db.InlayMilestones.GetCurrentStepEntries(inlayIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	stuck := []StuckInlay{}
+	for _, inlay := range inlays {
+		var enteredAt *time.Time
+		if entry, ok := entries[inlay.ID]; ok && entry.Step == step {
+			enteredAt = &entry.EventTime
+		} else if step == data.ManufacturingSteps.Ordered {
+			project, found, projectErr := m.Db.Projects.GetByID(inlay.ProjectID)
+			if projectErr != nil {
+				return nil, projectErr
+			}
+			if found {
+				enteredAt = project.OrderedAt
+			}
+		}
+		if enteredAt == nil {
+			continue
+		}
+
+		hours := now.Sub(*enteredAt).Hours()
+		if hours > limitHours {
+			stuck = append(stuck, StuckInlay{
+				InlayUUID:   inlay.UUID,
+				InlayName:   inlay.Name,
+				HoursInStep: hours,
+			})
+		}
+	}
+
+	return stuck, nil
+}
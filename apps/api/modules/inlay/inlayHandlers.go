@@ -40,13 +40,14 @@ func (m InlayModule) getProjectForInlayAccess(w http.ResponseWriter, r *http.Req
 		return nil, false
 	}
 
-	user := m.ContextGetUser(r)
-	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || *dealershipID != project.DealershipID {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
-			return nil, false
-		}
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return nil, false
 	}
 
 	return project, true
@@ -63,13 +64,14 @@ func (m InlayModule) validateInlayOwnership(w http.ResponseWriter, r *http.Reque
 		return nil, false
 	}
 
-	user := m.ContextGetUser(r)
-	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || *dealershipID != project.DealershipID {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
-			return nil, false
-		}
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return nil, false
 	}
 
 	return project, true
@@ -86,6 +88,8 @@ type InlayWithProofStatus struct {
 	PriceGroupID         *int                     `json:"price_group_id"`
 	PriceGroupName       *string                  `json:"price_group_name"`
 	PriceCents           *int                     `json:"price_cents"`
+	ListPriceCents       *int                     `json:"list_price_cents"`
+	TierDiscountCents    int                      `json:"tier_discount_cents"`
 	PriceAdjustmentType  data.PriceAdjustmentType `json:"price_adjustment_type"`
 	PriceAdjustmentValue float64                  `json:"price_adjustment_value"`
 	// CanDelete is false when dependent records (a proof, milestones, updates or
@@ -96,14 +100,25 @@ type InlayWithProofStatus struct {
 }
 
 // inlayPricing carries the resolved pricing for an inlay: which price group
-// applies, its display name, the final per-unit price after adjustment, and the
+// applies, its display name, the final per-unit price after adjustment and the
+// dealership's tier discount, the list price before that discount, and the
 // adjustment itself (so the frontend can render the "PG1 + 20%" formula).
 type inlayPricing struct {
-	PriceGroupID    *int
-	PriceGroupName  *string
-	PriceCents      *int
-	AdjustmentType  data.PriceAdjustmentType
-	AdjustmentValue float64
+	PriceGroupID      *int
+	PriceGroupName    *string
+	PriceCents        *int
+	ListPriceCents    *int
+	TierDiscountCents int
+	AdjustmentType    data.PriceAdjustmentType
+	AdjustmentValue   float64
+}
+
+// setPrice fills in the tiered price for a price group's list base.
+func (p *inlayPricing) setPrice(tier *data.DealershipPriceTier, priceGroupID, listBaseCents int) {
+	price := data.ComputeTieredPrice(tier, priceGroupID, listBaseCents, p.AdjustmentType, p.AdjustmentValue)
+	p.PriceCents = &price.NetCents
+	p.ListPriceCents = &price.ListCents
+	p.TierDiscountCents = price.DiscountCents
 }
 
 // inlayIsReady is the single source of truth for "can this inlay be ordered?"
@@ -121,13 +136,18 @@ func inlayIsReady(inlay *data.Inlay) bool {
 // (or, for customized catalog inlays still pending internal review, the latest
 // proof's proposed pricing).
 func (m InlayModule) buildInlayPricing(inlay *data.Inlay) (inlayPricing, error) {
+	tier, _, err := m.Db.DealershipPriceTiers.GetActiveForProject(inlay.ProjectID, time.Now())
+	if err != nil {
+		return inlayPricing{}, err
+	}
+
 	if inlay.ApprovedProofID != nil {
 		proof, found, err := m.Db.InlayProofs.GetByID(*inlay.ApprovedProofID)
 		if err != nil {
 			return inlayPricing{}, err
 		}
 		if found && proof.PriceGroupID != nil {
-			return m.pricingFromProof(proof)
+			return m.pricingFromProof(proof, tier)
 		}
 	}
 
@@ -139,7 +159,7 @@ func (m InlayModule) buildInlayPricing(inlay *data.Inlay) (inlayPricing, error)
 			return inlayPricing{}, err
 		}
 		if found && latest.PriceGroupID != nil {
-			return m.pricingFromProof(latest)
+			return m.pricingFromProof(latest, tier)
 		}
 	}
 
@@ -161,9 +181,8 @@ func (m InlayModule) buildInlayPricing(inlay *data.Inlay) (inlayPricing, error)
 			}
 			if pgFound {
 				name := pg.Name
-				base := pg.BasePriceCents
 				pricing.PriceGroupName = &name
-				pricing.PriceCents = &base
+				pricing.setPrice(tier, priceGroupID, pg.BasePriceCents)
 			}
 			return pricing, nil
 		}
@@ -173,8 +192,9 @@ func (m InlayModule) buildInlayPricing(inlay *data.Inlay) (inlayPricing, error)
 }
 
 // pricingFromProof resolves a proof's price group base and applies its
-// adjustment to produce the final per-unit price plus the formula components.
-func (m InlayModule) pricingFromProof(proof *data.InlayProof) (inlayPricing, error) {
+// adjustment and the dealership's tier to produce the final per-unit price plus
+// the formula components.
+func (m InlayModule) pricingFromProof(proof *data.InlayProof, tier *data.DealershipPriceTier) (inlayPricing, error) {
 	pg, pgFound, pgErr := m.Db.PriceGroups.GetByID(*proof.PriceGroupID)
 	if pgErr != nil {
 		return inlayPricing{}, pgErr
@@ -188,8 +208,7 @@ func (m InlayModule) pricingFromProof(proof *data.InlayProof) (inlayPricing, err
 	if pgFound {
 		name := pg.Name
 		pricing.PriceGroupName = &name
-		priceCents := data.ComputeAdjustedPriceCents(pg.BasePriceCents, proof.PriceAdjustmentType, proof.PriceAdjustmentValue)
-		pricing.PriceCents = &priceCents
+		pricing.setPrice(tier, *proof.PriceGroupID, pg.BasePriceCents)
 	}
 
 	return pricing, nil
@@ -640,13 +659,7 @@ func inlayDeleteBlockedMessage(blockers []data.InlayDeleteBlocker) string {
 	}
 }
 
-var manufacturingStepOrder = []data.ManufacturingStep{
-	data.ManufacturingSteps.Ordered,
-	data.ManufacturingSteps.MaterialsPrep,
-	data.ManufacturingSteps.Manufacturing,
-	data.ManufacturingSteps.Packaging,
-	data.ManufacturingSteps.ReadyToShip,
-}
+var manufacturingStepOrder = data.ManufacturingStepOrder
 
 func manufacturingStepIndex(step data.ManufacturingStep) int {
 	for i, s := range manufacturingStepOrder {
@@ -657,11 +670,43 @@ func manufacturingStepIndex(step data.ManufacturingStep) int {
 	return -1
 }
 
+// KanbanInlay is an inlay on the production board. RemakeCycle is set while
+// the inlay is back on the board for an approved remake. Rush, RequiredBy and
+// Overdue come from the project and decide the inlay's place in its column;
+// a remake is never rush or overdue.
 type KanbanInlay struct {
 	*data.Inlay
-	ProjectUUID    string `json:"project_uuid"`
-	ProjectName    string `json:"project_name"`
-	DealershipName string `json:"dealership_name"`
+	ProjectUUID    string     `json:"project_uuid"`
+	ProjectName    string     `json:"project_name"`
+	DealershipName string     `json:"dealership_name"`
+	RemakeCycle    *int       `json:"remake_cycle"`
+	Rush           bool       `json:"rush"`
+	RequiredBy     *time.Time `json:"required_by"`
+	Overdue        bool       `json:"overdue"`
+}
+
+// compareKanbanPriority orders the board: rush and overdue inlays first, then
+// the nearest required-by date, leaving the rest in the order they came in.
+func compareKanbanPriority(a, b KanbanInlay) int {
+	aUrgent := a.Rush || a.Overdue
+	bUrgent := b.Rush || b.Overdue
+	if aUrgent != bUrgent {
+		if aUrgent {
+			return -1
+		}
+		return 1
+	}
+
+	switch {
+	case a.RequiredBy == nil && b.RequiredBy == nil:
+		return 0
+	case a.RequiredBy == nil:
+		return 1
+	case b.RequiredBy == nil:
+		return -1
+	default:
+		return a.RequiredBy.Compare(*b.RequiredBy)
+	}
 }
 
 func (m InlayModule) HandleGetKanbanInlays(w http.ResponseWriter, r *http.Request) {
@@ -670,22 +715,36 @@ func (m InlayModule) HandleGetKanbanInlays(w http.ResponseWriter, r *http.Reques
 		table.Projects.UUID.AS("project_uuid"),
 		table.Projects.Name.AS("project_name"),
 		table.Dealerships.Name.AS("dealership_name"),
+		table.InlayRemakes.Cycle.AS("remake_cycle"),
+		table.Projects.Rush.AS("project_rush"),
+		table.Projects.RequiredBy.AS("project_required_by"),
 	).FROM(
 		table.Inlays.
 			INNER_JOIN(table.Projects, table.Projects.ID.EQ(table.Inlays.ProjectID)).
-			INNER_JOIN(table.Dealerships, table.Dealerships.ID.EQ(table.Projects.DealershipID)),
+			INNER_JOIN(table.Dealerships, table.Dealerships.ID.EQ(table.Projects.DealershipID)).
+			LEFT_JOIN(table.InlayRemakes, postgres.AND(
+				table.InlayRemakes.InlayID.EQ(table.Inlays.ID),
+				table.InlayRemakes.Status.EQ(postgres.String(string(data.RemakeStatuses.Approved))),
+			)),
 	).WHERE(
 		postgres.AND(
 			table.Inlays.ManufacturingStep.IS_NOT_NULL(),
-			// An inlay's step tops out at ready-to-ship and shipping happens at
-			// the project level, so without this the board never empties.
-			table.Projects.Status.NOT_IN(
-				postgres.String(string(data.ProjectStatuses.Shipped)),
-				postgres.String(string(data.ProjectStatuses.Invoiced)),
-				postgres.String(string(data.ProjectStatuses.Completed)),
-				postgres.String(string(data.ProjectStatuses.Cancelled)),
+			postgres.OR(
+				// An inlay's step tops out at ready-to-ship and shipping happens at
+				// the project level, so without this the board never empties.
+				table.Projects.Status.NOT_IN(
+					postgres.String(string(data.ProjectStatuses.Shipped)),
+					postgres.String(string(data.ProjectStatuses.Invoiced)),
+					postgres.String(string(data.ProjectStatuses.Completed)),
+					postgres.String(string(data.ProjectStatuses.Cancelled)),
+				),
+				// A remake puts a delivered inlay back on the board until it ships.
+				table.InlayRemakes.ID.IS_NOT_NULL(),
 			),
 		),
+	).ORDER_BY(
+		table.Projects.OrderedAt.ASC(),
+		table.Inlays.ID.ASC(),
 	)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -693,9 +752,12 @@ func (m InlayModule) HandleGetKanbanInlays(w http.ResponseWriter, r *http.Reques
 
 	var dest []struct {
 		model.Inlays
-		ProjectUUID    uuid.UUID `alias:"project_uuid"`
-		ProjectName    string    `alias:"project_name"`
-		DealershipName string    `alias:"dealership_name"`
+		ProjectUUID    uuid.UUID  `alias:"project_uuid"`
+		ProjectName    string     `alias:"project_name"`
+		DealershipName string     `alias:"dealership_name"`
+		RemakeCycle    *int32     `alias:"remake_cycle"`
+		Rush           bool       `alias:"project_rush"`
+		RequiredBy     *time.Time `alias:"project_required_by"`
 	}
 	err := query.QueryContext(ctx, m.Db.STDB, &dest)
 	if err != nil {
@@ -703,6 +765,7 @@ func (m InlayModule) HandleGetKanbanInlays(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	now := time.Now()
 	result := make([]KanbanInlay, len(dest))
 	for i, d := range dest {
 		inlay := data.Inlay{
@@ -731,9 +794,21 @@ func (m InlayModule) HandleGetKanbanInlays(w http.ResponseWriter, r *http.Reques
 			ProjectUUID:    d.ProjectUUID.String(),
 			ProjectName:    d.ProjectName,
 			DealershipName: d.DealershipName,
+			RequiredBy:     d.RequiredBy,
+		}
+		// Rush and the required-by date were for the original order, which has
+		// shipped by the time a remake is on the board.
+		if d.RemakeCycle != nil {
+			cycle := int(*d.RemakeCycle)
+			result[i].RemakeCycle = &cycle
+		} else {
+			result[i].Rush = d.Rush
+			result[i].Overdue = data.PastRequiredBy(d.RequiredBy, now)
 		}
 	}
 
+	slices.SortStableFunc(result, compareKanbanPriority)
+
 	m.WriteJSON(w, r, http.StatusOK, result)
 }
 
@@ -786,6 +861,18 @@ func (m InlayModule) HandlePatchInlayStep(w http.ResponseWriter, r *http.Request
 
 	destStepIdx := manufacturingStepIndex(body.Step)
 
+	// Steps taken while an approved remake is in production belong to the
+	// remake's cycle, leaving the original order's timeline as it was.
+	cycle := 1
+	remake, remakeFound, err := m.Db.InlayRemakes.GetOpenByInlayID(inlay.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if remakeFound && remake.Status == data.RemakeStatuses.Approved {
+		cycle = remake.Cycle
+	}
+
 	var newEventType data.MilestoneEventType
 	if destStepIdx >= currentStepIdx {
 		newEventType = data.MilestoneEventTypes.Entered
@@ -807,6 +894,7 @@ func (m InlayModule) HandlePatchInlayStep(w http.ResponseWriter, r *http.Request
 			EventType:   data.MilestoneEventTypes.Exited,
 			PerformedBy: userID,
 			EventTime:   now,
+			Cycle:       cycle,
 		}
 		if err := m.Db.InlayMilestones.TxInsert(tx, &exitedMilestone); err != nil {
 			m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to insert exited milestone: %w", err))
@@ -820,6 +908,7 @@ func (m InlayModule) HandlePatchInlayStep(w http.ResponseWriter, r *http.Request
 		EventType:   newEventType,
 		PerformedBy: userID,
 		EventTime:   now,
+		Cycle:       cycle,
 	}
 	if err := m.Db.InlayMilestones.TxInsert(tx, &enteredMilestone); err != nil {
 		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to insert entered milestone: %w", err))
@@ -833,11 +922,25 @@ func (m InlayModule) HandlePatchInlayStep(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// Materials-prep is where glass comes off the rack, so that is when the
+	// order's reservation becomes real consumption. Jumping past the step
+	// consumes too; consuming again after a revert is a no-op.
+	var consumedStockIDs []int
+	if destStepIdx >= manufacturingStepIndex(data.ManufacturingSteps.MaterialsPrep) {
+		consumedStockIDs, err = m.Db.MaterialReservations.TxConsumeForInlay(tx, inlay.ID)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to consume reserved materials: %w", err))
+			return
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		m.WriteError(w, r, m.Err.ServerError, err)
 		return
 	}
 
+	m.NotifyLowStock(consumedStockIDs)
+
 	// Manufacturing moves through many steps; notifying the dealership on every
 	// transition is noise. Only the "manufacturing" milestone is worth an alert
 	// (the dealership sees the full step history on the inlay timeline). Shipping
@@ -1 +1,206 @@
 package inlay
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+// cloneSource is an inlay to reorder, with what it takes to copy it resolved
+// up front so the copy itself only writes.
+type cloneSource struct {
+	inlay       *data.Inlay
+	catalogItem *data.CatalogItem
+	// proof is the design to start the copy from: the approved proof, or for a
+	// customized catalog inlay still under review, its latest proof.
+	proof *data.InlayProof
+	// proofStillApproved is true when proof can be carried over approved: the
+	// design it was approved against is unchanged and its price group is still
+	// offered.
+	proofStillApproved bool
+}
+
+// cloneOutcome says what a copied inlay still needs before it can be ordered,
+// so the right people are told once the copies are committed.
+type cloneOutcome int
+
+const (
+	cloneReady cloneOutcome = iota
+	cloneNeedsReview
+	cloneNeedsProof
+)
+
+// resolveCloneSource works out how an inlay will be copied.
+func (m InlayModule) resolveCloneSource(inlay *data.Inlay) (*cloneSource, error) {
+	source := &cloneSource{inlay: inlay}
+
+	if inlay.Type == data.InlayTypes.Catalog {
+		if inlay.CatalogInfo == nil {
+			return nil, fmt.Errorf("catalog inlay %d is missing its catalog info", inlay.ID)
+		}
+		item, found, err := m.Db.CatalogItems.GetByID(inlay.CatalogInfo.CatalogItemID)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("catalog item %d not found", inlay.CatalogInfo.CatalogItemID)
+		}
+		source.catalogItem = item
+	}
+
+	var proof *data.InlayProof
+	if inlay.ApprovedProofID != nil {
+		approved, found, err := m.Db.InlayProofs.GetByID(*inlay.ApprovedProofID)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			proof = approved
+		}
+	} else if inlay.Type == data.InlayTypes.Catalog && inlay.IsCustomized {
+		latest, found, err := m.Db.InlayProofs.GetLatestByInlayID(inlay.ID)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			proof = latest
+		}
+	}
+	if proof == nil {
+		return source, nil
+	}
+	source.proof = proof
+
+	priceGroupActive := false
+	if proof.PriceGroupID != nil {
+		priceGroup, found, err := m.Db.PriceGroups.GetByID(*proof.PriceGroupID)
+		if err != nil {
+			return nil, err
+		}
+		priceGroupActive = found && priceGroup.IsActive
+	}
+
+	designUnchanged := true
+	if source.catalogItem != nil {
+		// A customized catalog design is a bake of the catalog item, so any edit
+		// to the item since approval may have changed what was approved.
+		designUnchanged = proof.ApprovedAt != nil && !source.catalogItem.UpdatedAt.After(*proof.ApprovedAt)
+	}
+
+	source.proofStillApproved = proof.Status == data.ProofStatuses.Approved && designUnchanged && priceGroupActive
+	return source, nil
+}
+
+// txCloneInlay copies an inlay into a draft project. Catalog info, notes and
+// reference images are copied as-is. Nothing priced is copied: the copy is
+// priced from its price group at order time like any new inlay, never from the
+// original's order snapshot.
+//
+// An approved design whose proof is still valid becomes the copy's approved,
+// internal-authority v1, so an unchanged reorder is ready straight away.
+// Otherwise a customized catalog design starts as a pending v1 for internal
+// review, and a custom inlay starts without a proof for a designer to redo.
+func (m InlayModule) txCloneInlay(tx *sql.Tx, source *cloneSource, projectID int, now time.Time) (*data.Inlay, cloneOutcome, error) {
+	original := source.inlay
+	inlay := &data.Inlay{
+		ProjectID:    projectID,
+		Name:         original.Name,
+		Type:         original.Type,
+		IsCustomized: original.IsCustomized,
+		PreviewURL:   original.PreviewURL,
+	}
+
+	if original.CatalogInfo != nil {
+		inlay.CatalogInfo = &data.InlayCatalogInfo{
+			CatalogItemID:      original.CatalogInfo.CatalogItemID,
+			CustomizationNotes: original.CatalogInfo.CustomizationNotes,
+		}
+		// A customized inlay with no proof to copy has no coloring to keep, so
+		// its copy is the stock design.
+		if !original.IsCustomized || source.proof == nil {
+			inlay.IsCustomized = false
+			inlay.PreviewURL = source.catalogItem.SvgURL
+		}
+	}
+	if original.CustomInfo != nil {
+		referenceImages := make([]data.InlayCustomReferenceImage, len(original.CustomInfo.ReferenceImages))
+		for i, image := range original.CustomInfo.ReferenceImages {
+			referenceImages[i] = data.InlayCustomReferenceImage{ImageURL: image.ImageURL, SortOrder: image.SortOrder}
+		}
+		inlay.CustomInfo = &data.InlayCustomInfo{
+			Description:     original.CustomInfo.Description,
+			RequestedWidth:  original.CustomInfo.RequestedWidth,
+			RequestedHeight: original.CustomInfo.RequestedHeight,
+			ReferenceImages: referenceImages,
+		}
+	}
+
+	// A custom design that can no longer be carried over approved has nothing
+	// to review: the internal queue only covers catalog designs.
+	proof := source.proof
+	if proof != nil && original.Type == data.InlayTypes.Custom && !source.proofStillApproved {
+		proof = nil
+	}
+	if proof != nil {
+		inlay.PreviewURL = proof.DesignAssetURL
+	}
+
+	if err := m.Db.Inlays.TxInsert(tx, inlay); err != nil {
+		return nil, 0, fmt.Errorf("failed to copy inlay %q: %w", original.Name, err)
+	}
+
+	if proof == nil {
+		if original.Type == data.InlayTypes.Custom {
+			return inlay, cloneNeedsProof, nil
+		}
+		return inlay, cloneReady, nil
+	}
+
+	priceGroupID := proof.PriceGroupID
+	adjustmentType, adjustmentValue := proof.PriceAdjustmentType, proof.PriceAdjustmentValue
+	if !source.proofStillApproved && source.catalogItem != nil {
+		defaultPriceGroupID := source.catalogItem.DefaultPriceGroupID
+		priceGroupID = &defaultPriceGroupID
+		adjustmentType, adjustmentValue = data.PriceAdjustmentTypes.None, 0
+	}
+
+	colorOverrides := map[string]interface{}{}
+	if proof.ColorOverrides != nil {
+		colorOverrides = proof.ColorOverrides
+	}
+
+	startingProof := &data.InlayProof{
+		InlayID:              inlay.ID,
+		VersionNumber:        1,
+		DesignAssetURL:       proof.DesignAssetURL,
+		Width:                proof.Width,
+		Height:               proof.Height,
+		PriceGroupID:         priceGroupID,
+		PriceAdjustmentType:  adjustmentType,
+		PriceAdjustmentValue: adjustmentValue,
+		ScaleFactor:          proof.ScaleFactor,
+		ColorOverrides:       colorOverrides,
+		ApprovalAuthority:    data.ProofApprovalAuthorities.Internal,
+		Status:               data.ProofStatuses.Pending,
+	}
+	if source.proofStillApproved {
+		startingProof.Status = data.ProofStatuses.Approved
+		startingProof.ApprovedAt = &now
+	}
+
+	if err := m.Db.InlayProofs.TxInsert(tx, startingProof); err != nil {
+		return nil, 0, fmt.Errorf("failed to copy proof for inlay %q: %w", original.Name, err)
+	}
+
+	if !source.proofStillApproved {
+		return inlay, cloneNeedsReview, nil
+	}
+
+	inlay.ApprovedProofID = &startingProof.ID
+	if err := m.Db.Inlays.TxUpdateFields(tx, inlay); err != nil {
+		return nil, 0, fmt.Errorf("failed to approve copied inlay %q: %w", original.Name, err)
+	}
+	return inlay, cloneReady, nil
+}
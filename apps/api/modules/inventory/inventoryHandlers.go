@@ -0,0 +1,226 @@
+package inventory
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type InventoryModule struct {
+	*app.Application
+}
+
+func NewInventoryModule(app *app.Application) *InventoryModule {
+	return &InventoryModule{app}
+}
+
+// inventoryItem is a stock row with enough of its material to show it in a
+// list without a second lookup.
+type inventoryItem struct {
+	*data.MaterialStock
+	MaterialUUID     string     `json:"material_uuid"`
+	Name             string     `json:"name"`
+	Hex              string     `json:"hex"`
+	UnavailableUntil *time.Time `json:"unavailable_until"`
+	IsLowStock       bool       `json:"is_low_stock"`
+}
+
+type stockRequest struct {
+	Unit              data.MaterialUnit `json:"unit" validate:"required,oneof=sheets square_inches"`
+	SheetArea         *float64          `json:"sheet_area" validate:"required_if=Unit sheets,omitempty,gt=0"`
+	OnHand            float64           `json:"on_hand"`
+	LowStockThreshold float64           `json:"low_stock_threshold" validate:"gte=0"`
+	UnavailableUntil  *time.Time        `json:"unavailable_until"`
+}
+
+// HandleGetInventory lists every counted material with what is on hand, what
+// open orders have reserved, and what is left to promise.
+func (m *InventoryModule) HandleGetInventory(w http.ResponseWriter, r *http.Request) {
+	stocks, err := m.Db.MaterialStocks.GetAll()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	glassColors, err := m.Db.GlassColors.GetAll()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	grouts, err := m.Db.Grouts.GetAll()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	glassByID := make(map[int]*data.GlassColor, len(glassColors))
+	for _, gc := range glassColors {
+		glassByID[gc.ID] = gc
+	}
+	groutByID := make(map[int]*data.Grout, len(grouts))
+	for _, g := range grouts {
+		groutByID[g.ID] = g
+	}
+
+	items := make([]inventoryItem, 0, len(stocks))
+	for _, stock := range stocks {
+		item := inventoryItem{MaterialStock: stock, IsLowStock: stock.IsLowStock()}
+		if stock.GlassColorID != nil {
+			if gc, ok := glassByID[*stock.GlassColorID]; ok {
+				item.MaterialUUID, item.Name, item.Hex, item.UnavailableUntil = gc.UUID, gc.Name, gc.Hex, gc.UnavailableUntil
+			}
+		} else if stock.GroutID != nil {
+			if g, ok := groutByID[*stock.GroutID]; ok {
+				item.MaterialUUID, item.Name, item.Hex, item.UnavailableUntil = g.UUID, g.Name, g.Hex, g.UnavailableUntil
+			}
+		}
+		items = append(items, item)
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, items)
+}
+
+// HandlePutGlassColorStock records a count for a glass color, creating its
+// stock row on first use. unavailable_until is part of the same form: staff
+// set it when a count hits zero and a restock date is known, and send null to
+// clear it.
+func (m *InventoryModule) HandlePutGlassColorStock(w http.ResponseWriter, r *http.Request) {
+	uuid := r.PathValue("uuid")
+
+	err := m.Validate.Var(uuid, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	var body stockRequest
+	err = m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	glassColor, found, err := m.Db.GlassColors.GetByUUID(uuid)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	stock, found, err := m.Db.MaterialStocks.GetByGlassColorID(glassColor.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		stock = &data.MaterialStock{GlassColorID: &glassColor.ID}
+	}
+
+	if err := m.saveStock(stock, body); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	glassColor.UnavailableUntil = body.UnavailableUntil
+	if err := m.Db.GlassColors.Update(glassColor); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, inventoryItem{
+		MaterialStock:    stock,
+		MaterialUUID:     glassColor.UUID,
+		Name:             glassColor.Name,
+		Hex:              glassColor.Hex,
+		UnavailableUntil: glassColor.UnavailableUntil,
+		IsLowStock:       stock.IsLowStock(),
+	})
+}
+
+// HandlePutGroutStock is HandlePutGlassColorStock for grout.
+func (m *InventoryModule) HandlePutGroutStock(w http.ResponseWriter, r *http.Request) {
+	uuid := r.PathValue("uuid")
+
+	err := m.Validate.Var(uuid, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	var body stockRequest
+	err = m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	grout, found, err := m.Db.Grouts.GetByUUID(uuid)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	stock, found, err := m.Db.MaterialStocks.GetByGroutID(grout.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		stock = &data.MaterialStock{GroutID: &grout.ID}
+	}
+
+	if err := m.saveStock(stock, body); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	grout.UnavailableUntil = body.UnavailableUntil
+	if err := m.Db.Grouts.Update(grout); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, inventoryItem{
+		MaterialStock:    stock,
+		MaterialUUID:     grout.UUID,
+		Name:             grout.Name,
+		Hex:              grout.Hex,
+		UnavailableUntil: grout.UnavailableUntil,
+		IsLowStock:       stock.IsLowStock(),
+	})
+}
+
+// saveStock applies a count to a stock row, inserting it when new. A count that
+// lands at or under the threshold warns production straight away.
+func (m *InventoryModule) saveStock(stock *data.MaterialStock, body stockRequest) error {
+	stock.Unit = body.Unit
+	stock.SheetArea = body.SheetArea
+	stock.OnHand = body.OnHand
+	stock.LowStockThreshold = body.LowStockThreshold
+	if body.Unit != data.MaterialUnits.Sheets {
+		stock.SheetArea = nil
+	}
+
+	var err error
+	if stock.ID == 0 {
+		err = m.Db.MaterialStocks.Insert(stock)
+	} else {
+		err = m.Db.MaterialStocks.Update(stock)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save stock: %w", err)
+	}
+
+	m.NotifyLowStock([]int{stock.ID})
+	return nil
+}
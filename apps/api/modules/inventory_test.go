@@ -0,0 +1,169 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedGlassColor seeds an active glass color, in family unless it is empty.
+func seedGlassColor(t *testing.T, ctx *testContext, name, hex, family string) *data.GlassColor {
+	gc := &data.GlassColor{Name: name, Hex: hex, IsActive: true}
+	if family != "" {
+		gc.Family = &family
+	}
+	require.NoError(t, ctx.db.GlassColors.Insert(gc))
+	return gc
+}
+
+func TestPutGlassColorStock_CreatesThenUpdates(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, _, _, internalToken := seedTestData(t, ctx)
+	gc := seedGlassColor(t, ctx, "Amber", "#c07a1a", "")
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPut,
+		path:   fmt.Sprintf("/api/glass-colors/%s/stock", gc.UUID),
+		token:  internalToken,
+		body:   map[string]any{"unit": "sheets", "sheet_area": 432, "on_hand": 12, "low_stock_threshold": 2},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPut,
+		path:   fmt.Sprintf("/api/glass-colors/%s/stock", gc.UUID),
+		token:  internalToken,
+		body: map[string]any{
+			"unit": "sheets", "sheet_area": 432, "on_hand": 8, "low_stock_threshold": 2,
+			"unavailable_until": "2030-01-15T00:00:00Z",
+		},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/inventory", token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal(resp.body, &items))
+	require.Len(t, items, 1, "a second count updates the same row")
+	assert.Equal(t, gc.UUID, items[0]["material_uuid"])
+	assert.EqualValues(t, 8, items[0]["on_hand"])
+	assert.EqualValues(t, 8, items[0]["available"])
+	assert.Equal(t, "2030-01-15T00:00:00Z", items[0]["unavailable_until"])
+
+	reloaded, _, err := ctx.db.GlassColors.GetByID(gc.ID)
+	require.NoError(t, err)
+	require.NotNil(t, reloaded.UnavailableUntil)
+}
+
+func TestPutGlassColorStock_SheetsRequireSheetArea(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, _, _, internalToken := seedTestData(t, ctx)
+	gc := seedGlassColor(t, ctx, "Amber", "#c07a1a", "")
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPut,
+		path:   fmt.Sprintf("/api/glass-colors/%s/stock", gc.UUID),
+		token:  internalToken,
+		body:   map[string]any{"unit": "sheets", "on_hand": 12},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, string(resp.body))
+}
+
+func TestGetInventory_DealershipUserForbidden(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, dealershipToken, _, _ := seedTestData(t, ctx)
+
+	resp := ctx.request(testRequest{method: http.MethodGet, path: "/api/inventory", token: dealershipToken})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, string(resp.body))
+}
+
+func TestPatchInlayStep_MaterialsPrepConsumesReservationAndWarnsLowStock(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, internalUser, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-INV-0001")
+	project, inlay := seedOrderedProjectWithInlay(t, ctx, dealershipUser.DealershipID, item.ID)
+	setInlayStep(t, ctx, inlay, data.ManufacturingSteps.Ordered)
+
+	gc := seedGlassColor(t, ctx, "Amber", "#c07a1a", "")
+	stock := &data.MaterialStock{GlassColorID: &gc.ID, Unit: data.MaterialUnits.SquareInches, OnHand: 100, LowStockThreshold: 50}
+	require.NoError(t, ctx.db.MaterialStocks.Insert(stock))
+
+	tx, err := ctx.db.STDB.Begin()
+	require.NoError(t, err)
+	require.NoError(t, ctx.db.MaterialReservations.TxReserve(tx, &data.MaterialReservation{
+		MaterialStockID: stock.ID, ProjectID: project.ID, InlayID: inlay.ID, Quantity: 60,
+	}))
+	require.NoError(t, tx.Commit())
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPatch,
+		path:   fmt.Sprintf("/api/inlay/%s/step", inlay.UUID),
+		token:  internalToken,
+		body:   map[string]any{"step": "materials-prep"},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	reloaded, _, err := ctx.db.MaterialStocks.GetByID(stock.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 40, reloaded.OnHand, 1e-9)
+	assert.Zero(t, reloaded.Reserved)
+	assert.NotNil(t, reloaded.LowStockNotifiedAt)
+
+	notifications, err := ctx.db.Notifications.GetForInternalUser(internalUser.ID)
+	require.NoError(t, err)
+	var lowStock int
+	for _, n := range notifications {
+		if n.EventType == data.NotificationEventTypes.LowStock {
+			lowStock++
+		}
+	}
+	assert.Equal(t, 1, lowStock, "admins hear about low stock even when they moved the inlay")
+}
+
+func TestDeleteProject_ReleasesReservations(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-INV-0002")
+	project, inlay := seedOrderedProjectWithInlay(t, ctx, dealershipUser.DealershipID, item.ID)
+
+	gc := seedGlassColor(t, ctx, "Amber", "#c07a1a", "")
+	stock := &data.MaterialStock{GlassColorID: &gc.ID, Unit: data.MaterialUnits.SquareInches, OnHand: 100}
+	require.NoError(t, ctx.db.MaterialStocks.Insert(stock))
+
+	tx, err := ctx.db.STDB.Begin()
+	require.NoError(t, err)
+	require.NoError(t, ctx.db.MaterialReservations.TxReserve(tx, &data.MaterialReservation{
+		MaterialStockID: stock.ID, ProjectID: project.ID, InlayID: inlay.ID, Quantity: 30,
+	}))
+	require.NoError(t, tx.Commit())
+
+	resp := ctx.request(testRequest{
+		method: http.MethodDelete,
+		path:   fmt.Sprintf("/api/project/%s", project.UUID),
+		token:  internalToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	reloaded, _, err := ctx.db.MaterialStocks.GetByID(stock.ID)
+	require.NoError(t, err)
+	assert.Zero(t, reloaded.Reserved)
+	assert.InDelta(t, 100, reloaded.Available, 1e-9)
+}
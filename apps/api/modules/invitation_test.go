@@ -0,0 +1,135 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitation_CreateAcceptOnce(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, dealershipToken, _, _ := seedTestData(t, ctx)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/dealership-user",
+		token:  dealershipToken,
+		body: map[string]any{
+			"name":      "Invited User",
+			"email":     "invited@example.com",
+			"avatar":    "https://example.com/avatar.jpg",
+			"role":      "submitter",
+			"is_active": true,
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var created data.DealershipUser
+	require.NoError(t, json.Unmarshal(resp.body, &created))
+	assert.False(t, created.IsActive, "new users wait for their invitation to be accepted")
+
+	invitationPath := fmt.Sprintf("/api/dealership-user/%s/invitation", created.UUID)
+	resp = ctx.request(testRequest{method: http.MethodGet, path: invitationPath, token: dealershipToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var invitation data.Invitation
+	require.NoError(t, json.Unmarshal(resp.body, &invitation))
+	assert.Equal(t, data.InvitationStatuses.Pending, invitation.Status)
+	assert.Equal(t, "invited@example.com", invitation.Email)
+	assert.Equal(t, "submitter", invitation.Role)
+	assert.NotNil(t, invitation.InvitedByDealershipUserID)
+
+	// The token only exists in the email, so mint a fresh one to follow.
+	stored, found, err := ctx.db.Invitations.GetLatestForUser(&created)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NoError(t, ctx.db.Invitations.Resend(stored))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   "/api/auth/invitation?token=" + stored.Plaintext,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+	assert.Equal(t, true, resp.parsed.(map[string]any)["open"])
+
+	acceptPath := "/api/auth/invitation/accept?token=" + stored.Plaintext
+	resp = ctx.request(testRequest{method: http.MethodGet, path: acceptPath})
+	require.Equal(t, http.StatusFound, resp.statusCode, string(resp.body))
+
+	user, _, err := ctx.db.DealershipUsers.GetByID(created.ID)
+	require.NoError(t, err)
+	assert.True(t, user.IsActive)
+
+	account, found, err := ctx.db.DealershipAccounts.GetByProvider("magic_link", "invited@example.com")
+	require.NoError(t, err)
+	require.True(t, found, "the identity they accepted with is linked")
+	assert.Equal(t, created.ID, account.DealershipUserID)
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: acceptPath})
+	assert.Equal(t, http.StatusGone, resp.statusCode, "an invitation can only be used once")
+
+	resp = ctx.request(testRequest{method: http.MethodPost, path: invitationPath + "/resend", token: dealershipToken})
+	assert.Equal(t, http.StatusConflict, resp.statusCode, "nothing to resend once accepted")
+}
+
+func TestInvitation_RevokeAndResend(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, _, _, internalToken := seedTestData(t, ctx)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/internal-user",
+		token:  internalToken,
+		body: map[string]any{
+			"name":   "Invited Designer",
+			"email":  "designer@example.com",
+			"avatar": "https://example.com/avatar.jpg",
+			"role":   "designer",
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var created data.InternalUser
+	require.NoError(t, json.Unmarshal(resp.body, &created))
+
+	stored, found, err := ctx.db.Invitations.GetLatestForUser(&created)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NoError(t, ctx.db.Invitations.Resend(stored))
+	revokedToken := stored.Plaintext
+
+	invitationPath := fmt.Sprintf("/api/internal-user/%s/invitation", created.UUID)
+	resp = ctx.request(testRequest{method: http.MethodDelete, path: invitationPath, token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+	assert.Equal(t, string(data.InvitationStatuses.Revoked), resp.parsed.(map[string]any)["status"])
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/auth/invitation/accept?token=" + revokedToken})
+	assert.Equal(t, http.StatusGone, resp.statusCode, "a revoked invitation cannot be accepted")
+
+	user, _, err := ctx.db.InternalUsers.GetByID(created.ID)
+	require.NoError(t, err)
+	assert.False(t, user.IsActive)
+
+	resp = ctx.request(testRequest{method: http.MethodDelete, path: invitationPath, token: internalToken})
+	assert.Equal(t, http.StatusConflict, resp.statusCode, "already revoked")
+
+	resp = ctx.request(testRequest{method: http.MethodPost, path: invitationPath + "/resend", token: internalToken})
+	require.Equal(t, http.StatusCreated, resp.statusCode, "resending after a revoke sends a new invitation")
+
+	var reissued data.Invitation
+	require.NoError(t, json.Unmarshal(resp.body, &reissued))
+	assert.Equal(t, data.InvitationStatuses.Pending, reissued.Status)
+	assert.NotEqual(t, stored.UUID, reissued.UUID)
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/auth/invitation/accept?token=" + revokedToken})
+	assert.Equal(t, http.StatusGone, resp.statusCode, "the old link stays dead")
+}
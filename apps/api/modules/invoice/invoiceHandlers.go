@@ -62,6 +62,12 @@ func (m *InvoiceModule) HandlePostProjectInvoice(w http.ResponseWriter, r *http.
 		Status:     data.InvoiceStatuses.Sent,
 	}
 
+	err = m.Db.Invoices.SetProjectTotals(invoice)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to total invoice: %w", err))
+		return
+	}
+
 	err = m.Db.Invoices.Insert(invoice)
 	if err != nil {
 		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to create invoice: %w", err))
@@ -106,13 +112,14 @@ func (m *InvoiceModule) HandleGetProjectInvoice(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	user := m.ContextGetUser(r)
-	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || project.DealershipID != *dealershipID {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
-			return
-		}
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return
 	}
 
 	invoice, found, err := m.Db.Invoices.GetActiveByProjectID(project.ID)
@@ -154,8 +161,12 @@ func (m *InvoiceModule) HandleGetInvoice(w http.ResponseWriter, r *http.Request)
 			m.WriteError(w, r, m.Err.Forbidden, nil)
 			return
 		}
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || project.DealershipID != *dealershipID {
+		allowed, err := m.CanAccessDealership(r, project.DealershipID)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+		if !allowed {
 			m.WriteError(w, r, m.Err.Forbidden, nil)
 			return
 		}
@@ -0,0 +1,110 @@
+package job
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/scheduler"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+// recentRuns is how much of a job's history the runs list shows.
+const recentRuns = 100
+
+type JobModule struct {
+	*app.Application
+}
+
+func NewJobModule(app *app.Application) *JobModule {
+	return &JobModule{app}
+}
+
+type jobResponse struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Schedule    string       `json:"schedule"`
+	NextRunAt   *time.Time   `json:"next_run_at"`
+	LastRun     *data.JobRun `json:"last_run"`
+}
+
+func (m *JobModule) getJob(w http.ResponseWriter, r *http.Request) (*scheduler.Job, bool) {
+	job, found := m.Scheduler.Job(r.PathValue("name"))
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+	return job, true
+}
+
+// HandleGetJobs lists the background jobs with when each next runs and how
+// its last run went.
+func (m *JobModule) HandleGetJobs(w http.ResponseWriter, r *http.Request) {
+	latest, err := m.Db.JobRuns.GetLatestForEachJob()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	now := time.Now()
+	jobs := []jobResponse{}
+	for _, job := range m.Scheduler.Jobs() {
+		var nextRunAt *time.Time
+		if next := job.NextRun(now); !next.IsZero() {
+			nextRunAt = &next
+		}
+
+		jobs = append(jobs, jobResponse{
+			Name:        job.Name,
+			Description: job.Description,
+			Schedule:    job.Schedule,
+			NextRunAt:   nextRunAt,
+			LastRun:     latest[job.Name],
+		})
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, jobs)
+}
+
+// HandleGetJobRuns lists a job's most recent runs, newest first.
+func (m *JobModule) HandleGetJobRuns(w http.ResponseWriter, r *http.Request) {
+	job, ok := m.getJob(w, r)
+	if !ok {
+		return
+	}
+
+	runs, err := m.Db.JobRuns.GetForJob(job.Name, recentRuns)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, runs)
+}
+
+// HandlePostJobRun runs a job now. It runs in the background, so the response
+// is the run as it starts; its outcome shows in the job's runs.
+func (m *JobModule) HandlePostJobRun(w http.ResponseWriter, r *http.Request) {
+	job, ok := m.getJob(w, r)
+	if !ok {
+		return
+	}
+
+	admin := m.ContextGetInternalUser(r)
+
+	run, err := m.Scheduler.Trigger(job.Name, &admin.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, scheduler.ErrJobRunning):
+			m.WriteError(w, r, m.Err.Conflict, err)
+		default:
+			m.WriteError(w, r, m.Err.ServerError, err)
+		}
+		return
+	}
+
+	m.Log.Info("job run triggered", "job", job.Name, "run", run.UUID, "internal_user_uuid", admin.UUID)
+
+	m.WriteJSON(w, r, http.StatusAccepted, run)
+}
@@ -0,0 +1,87 @@
+package modules
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobs_ListAndRunByHand(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+
+	_, err := ctx.db.DealershipTokens.New(dealershipUser.ID, -time.Hour, data.DealershipScopeAccess)
+	require.NoError(t, err)
+
+	resp := ctx.request(testRequest{method: http.MethodGet, path: "/api/jobs", token: dealershipToken})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode)
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/jobs", token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var jobs []struct {
+		Name      string       `json:"name"`
+		Schedule  string       `json:"schedule"`
+		NextRunAt *time.Time   `json:"next_run_at"`
+		LastRun   *data.JobRun `json:"last_run"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &jobs))
+	require.Len(t, jobs, 4)
+	assert.Equal(t, "expired-tokens", jobs[0].Name)
+	require.NotNil(t, jobs[0].NextRunAt)
+	assert.Equal(t, 0, jobs[0].NextRunAt.Minute())
+	assert.Nil(t, jobs[0].LastRun)
+
+	resp = ctx.request(testRequest{method: http.MethodPost, path: "/api/jobs/nope/run", token: internalToken})
+	assert.Equal(t, http.StatusNotFound, resp.statusCode)
+
+	resp = ctx.request(testRequest{method: http.MethodPost, path: "/api/jobs/expired-tokens/run", token: internalToken})
+	require.Equal(t, http.StatusAccepted, resp.statusCode, string(resp.body))
+
+	var run data.JobRun
+	require.NoError(t, json.Unmarshal(resp.body, &run))
+	assert.Equal(t, data.JobRunTriggers.Manual, run.Trigger)
+
+	var runs []*data.JobRun
+	require.Eventually(t, func() bool {
+		resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/jobs/expired-tokens/runs", token: internalToken})
+		require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+		require.NoError(t, json.Unmarshal(resp.body, &runs))
+		return len(runs) == 1 && runs[0].Status != data.JobRunStatuses.Running
+	}, 5*time.Second, 50*time.Millisecond)
+
+	assert.Equal(t, run.UUID, runs[0].UUID)
+	assert.Equal(t, data.JobRunStatuses.Succeeded, runs[0].Status)
+	assert.Equal(t, int64(1), runs[0].RowsAffected, "only the expired token is deleted")
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/jobs", token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+	assert.Contains(t, string(resp.body), run.UUID)
+}
+
+func TestJobs_RunningJobConflicts(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, _, _, internalToken := seedTestData(t, ctx)
+
+	// Another instance holding the lock is running the job.
+	lock, locked, err := ctx.db.JobRuns.TryLock("read-notifications")
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	resp := ctx.request(testRequest{method: http.MethodPost, path: "/api/jobs/read-notifications/run", token: internalToken})
+	assert.Equal(t, http.StatusConflict, resp.statusCode)
+
+	require.NoError(t, lock.Release())
+
+	resp = ctx.request(testRequest{method: http.MethodPost, path: "/api/jobs/read-notifications/run", token: internalToken})
+	assert.Equal(t, http.StatusAccepted, resp.statusCode, string(resp.body))
+}
@@ -2,6 +2,7 @@ package modules
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/auth"
@@ -10,16 +11,24 @@ import (
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/customizer"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/dashboard"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/dealership"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/dealershipgroup"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/glasscolor"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/grout"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/inlay"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/inventory"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/invoice"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/job"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/nesting"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/notification"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/oidc"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/pricegroup"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/project"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/proof"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/remake"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/review"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/role"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/support"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/tax"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/upload"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/user"
 	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
@@ -33,19 +42,52 @@ func GetRoutes(app *app.Application) http.Handler {
 	protected := alice.New(app.Authenticate)
 	standard := alice.New(app.RecoverPanic, app.LogRequest)
 
+	// Sign-in endpoints are limited per IP. Magic links are also limited per
+	// address, so one inbox cannot be flooded from many IPs.
+	signIn := alice.New(app.RateLimitByIP("sign-in:ip", data.RateLimit{Burst: 30, Interval: 10 * time.Second}))
+	magicLink := signIn.Append(
+		app.RateLimitByIP("magic-link:ip", data.RateLimit{Burst: 10, Interval: time.Minute}),
+		app.RateLimitByJSONField("magic-link:email", "email", data.RateLimit{Burst: 3, Interval: 5 * time.Minute}),
+	)
+
 	authModule := auth.NewAuthModule(app)
-	mux.Handle("GET /api/auth/google", unprotected.ThenFunc(authModule.HandleGetGoogleAuth))
-	mux.Handle("GET /api/auth/google/callback", unprotected.ThenFunc(authModule.HandleGetGoogleAuthCallback))
+	mux.Handle("GET /api/auth/google", signIn.ThenFunc(authModule.HandleGetGoogleAuth))
+	mux.Handle("GET /api/auth/google/callback", signIn.ThenFunc(authModule.HandleGetGoogleAuthCallback))
+
+	mux.Handle("GET /api/auth/microsoft", signIn.ThenFunc(authModule.HandleGetMicrosoftAuth))
+	mux.Handle("GET /api/auth/microsoft/callback", signIn.ThenFunc(authModule.HandleGetMicrosoftAuthCallback))
+
+	mux.Handle("GET /api/auth/oidc", unprotected.ThenFunc(authModule.HandleGetOIDCProviders))
+	mux.Handle("GET /api/auth/oidc/{key}", signIn.ThenFunc(authModule.HandleGetOIDCAuth))
+	mux.Handle("GET /api/auth/oidc/{key}/callback", signIn.ThenFunc(authModule.HandleGetOIDCAuthCallback))
 
-	mux.Handle("GET /api/auth/microsoft", unprotected.ThenFunc(authModule.HandleGetMicrosoftAuth))
-	mux.Handle("GET /api/auth/microsoft/callback", unprotected.ThenFunc(authModule.HandleGetMicrosoftAuthCallback))
+	mux.Handle("POST /api/auth/magic-link", magicLink.ThenFunc(authModule.HandlePostMagicLinkAuth))
+	mux.Handle("GET /api/auth/magic-link/callback", signIn.ThenFunc(authModule.HandleGetMagicLinkCallback))
 
-	mux.Handle("POST /api/auth/magic-link", unprotected.ThenFunc(authModule.HandlePostMagicLinkAuth))
-	mux.Handle("GET /api/auth/magic-link/callback", unprotected.ThenFunc(authModule.HandleGetMagicLinkCallback))
+	mux.Handle("GET /api/auth/invitation", signIn.ThenFunc(authModule.HandleGetInvitation))
+	mux.Handle("GET /api/auth/invitation/accept", signIn.ThenFunc(authModule.HandleGetInvitationAccept))
 
 	mux.Handle("POST /api/auth/token/access", unprotected.ThenFunc(authModule.HandlePostTokenAccess))
 	mux.Handle("GET /api/auth/logout", unprotected.ThenFunc(authModule.HandleGetLogout))
 
+	mux.Handle("GET /api/auth/sessions", protected.ThenFunc(authModule.HandleGetSessions))
+	mux.Handle("DELETE /api/auth/sessions/{id}", protected.ThenFunc(authModule.HandleDeleteSession))
+
+	mux.Handle("GET /api/auth/two-factor/challenge", signIn.ThenFunc(authModule.HandleGetTwoFactorChallenge))
+	mux.Handle("POST /api/auth/two-factor/challenge/enroll", signIn.ThenFunc(authModule.HandlePostTwoFactorChallengeEnroll))
+	mux.Handle("POST /api/auth/two-factor/challenge/verify", signIn.ThenFunc(authModule.HandlePostTwoFactorChallengeVerify))
+	mux.Handle("GET /api/auth/two-factor", protected.ThenFunc(authModule.HandleGetTwoFactor))
+	mux.Handle("POST /api/auth/two-factor/enroll", protected.ThenFunc(authModule.HandlePostTwoFactorEnroll))
+	mux.Handle("POST /api/auth/two-factor/confirm", protected.ThenFunc(authModule.HandlePostTwoFactorConfirm))
+	mux.Handle("DELETE /api/auth/two-factor", protected.ThenFunc(authModule.HandleDeleteTwoFactor))
+	mux.Handle("POST /api/auth/two-factor/recovery-codes", protected.ThenFunc(authModule.HandlePostTwoFactorRecoveryCodes))
+
+	canImpersonate := alice.New(app.Authenticate, app.RequirePermission(data.ActionImpersonate))
+	mux.Handle("POST /api/auth/impersonation", canImpersonate.ThenFunc(authModule.HandlePostImpersonation))
+	mux.Handle("GET /api/impersonations", canImpersonate.ThenFunc(authModule.HandleGetImpersonations))
+	mux.Handle("GET /api/impersonations/{uuid}", canImpersonate.ThenFunc(authModule.HandleGetImpersonation))
+	mux.Handle("DELETE /api/impersonations/{uuid}", canImpersonate.ThenFunc(authModule.HandleDeleteImpersonation))
+
 	canManageDealerships := alice.New(app.Authenticate, app.RequirePermission(data.ActionManageDealerships))
 	canManageDealership := alice.New(app.Authenticate, app.RequirePermission(data.ActionManageDealership))
 	canAccessAdmin := alice.New(app.Authenticate, app.RequirePermission(data.ActionAccessAdmin))
@@ -57,12 +99,34 @@ func GetRoutes(app *app.Application) http.Handler {
 	mux.Handle("POST /api/dealership", canManageDealerships.ThenFunc(dealershipModule.HandlePostDealership))
 	mux.Handle("PATCH /api/dealership/{uuid}", canManageDealership.ThenFunc(dealershipModule.HandlePatchDealership))
 
+	dealershipGroupModule := dealershipgroup.NewDealershipGroupModule(app)
+	mux.Handle("GET /api/dealership-group", canManageDealerships.ThenFunc(dealershipGroupModule.HandleGetDealershipGroups))
+	mux.Handle("POST /api/dealership-group", canManageDealerships.ThenFunc(dealershipGroupModule.HandlePostDealershipGroup))
+	mux.Handle("GET /api/dealership-group/{uuid}", protected.ThenFunc(dealershipGroupModule.HandleGetDealershipGroup))
+	mux.Handle("PUT /api/dealership-group/{uuid}", canManageDealerships.ThenFunc(dealershipGroupModule.HandlePutDealershipGroup))
+	mux.Handle("DELETE /api/dealership-group/{uuid}", canManageDealerships.ThenFunc(dealershipGroupModule.HandleDeleteDealershipGroup))
+	mux.Handle("GET /api/dealership-group/{uuid}/dashboard", protected.ThenFunc(dealershipGroupModule.HandleGetDealershipGroupDashboard))
+	mux.Handle("GET /api/dealership-group/{uuid}/statement", protected.ThenFunc(dealershipGroupModule.HandleGetDealershipGroupStatement))
+	mux.Handle("PUT /api/dealership-user/{uuid}/dealership-group", canManageDealerships.ThenFunc(dealershipGroupModule.HandlePutDealershipUserGroup))
+
+	oidcModule := oidc.NewOIDCModule(app)
+	mux.Handle("GET /api/oidc-providers", canManageDealerships.ThenFunc(oidcModule.HandleGetOIDCProviders))
+	mux.Handle("POST /api/oidc-providers", canManageDealerships.ThenFunc(oidcModule.HandlePostOIDCProvider))
+	mux.Handle("GET /api/oidc-providers/{uuid}", canManageDealerships.ThenFunc(oidcModule.HandleGetOIDCProvider))
+	mux.Handle("PUT /api/oidc-providers/{uuid}", canManageDealerships.ThenFunc(oidcModule.HandlePutOIDCProvider))
+	mux.Handle("DELETE /api/oidc-providers/{uuid}", canManageDealerships.ThenFunc(oidcModule.HandleDeleteOIDCProvider))
+
 	canCreateProject := alice.New(app.Authenticate, app.RequirePermission(data.ActionCreateProject))
 	canManageProject := alice.New(app.Authenticate, app.RequirePermission(data.ActionManageProject))
 	canPlaceOrder := alice.New(app.Authenticate, app.RequirePermission(data.ActionPlaceOrder))
 	canManageShipping := alice.New(app.Authenticate, app.RequirePermission(data.ActionManageShipping))
 	canSendChat := alice.New(app.Authenticate, app.RequirePermission(data.ActionSendChat))
 
+	mux.Handle("GET /api/dealership/{uuid}/shipping-addresses", protected.ThenFunc(dealershipModule.HandleGetShippingAddresses))
+	mux.Handle("POST /api/dealership/{uuid}/shipping-addresses", canPlaceOrder.ThenFunc(dealershipModule.HandlePostShippingAddress))
+	mux.Handle("PUT /api/shipping-address/{uuid}", canPlaceOrder.ThenFunc(dealershipModule.HandlePutShippingAddress))
+	mux.Handle("DELETE /api/shipping-address/{uuid}", canPlaceOrder.ThenFunc(dealershipModule.HandleDeleteShippingAddress))
+
 	projectModule := project.NewProjectModule(app)
 	mux.Handle("GET /api/project", protected.ThenFunc(projectModule.HandleGetProjects))
 	mux.Handle("POST /api/project", canCreateProject.ThenFunc(projectModule.HandlePostProject))
@@ -72,6 +136,13 @@ func GetRoutes(app *app.Application) http.Handler {
 	mux.Handle("POST /api/project/{uuid}/place-order", canPlaceOrder.ThenFunc(projectModule.HandlePlaceOrder))
 	mux.Handle("POST /api/project/{uuid}/ship", canManageShipping.ThenFunc(projectModule.HandleMarkProjectShipped))
 	mux.Handle("POST /api/project/{uuid}/deliver", canManageShipping.ThenFunc(projectModule.HandleMarkProjectDelivered))
+	mux.Handle("GET /api/project/{uuid}/shipments", protected.ThenFunc(projectModule.HandleGetProjectShipments))
+	mux.Handle("POST /api/project/{uuid}/shipments", canManageShipping.ThenFunc(projectModule.HandlePostShipment))
+	mux.Handle("POST /api/shipment/{uuid}/deliver", canManageShipping.ThenFunc(projectModule.HandleDeliverShipment))
+	mux.Handle("GET /api/shipment/{uuid}/packing-slip", canManageShipping.ThenFunc(projectModule.HandleGetPackingSlip))
+	mux.Handle("GET /api/project/{uuid}/quotes", protected.ThenFunc(projectModule.HandleGetProjectQuotes))
+	mux.Handle("POST /api/project/{uuid}/quotes", canCreateProject.ThenFunc(projectModule.HandlePostProjectQuote))
+	mux.Handle("GET /api/quote/{uuid}", protected.ThenFunc(projectModule.HandleGetQuote))
 	mux.Handle("PUT /api/project/{uuid}/watch", protected.ThenFunc(projectModule.HandlePutProjectWatch))
 	mux.Handle("GET /api/project/{uuid}/watchers", protected.ThenFunc(projectModule.HandleGetProjectWatchers))
 
@@ -83,17 +154,37 @@ func GetRoutes(app *app.Application) http.Handler {
 	mux.Handle("GET /api/project/{uuid}/inlays", protected.ThenFunc(inlayModule.HandleGetInlaysByProject))
 	mux.Handle("POST /api/project/{uuid}/inlays/catalog", canCreateProject.ThenFunc(inlayModule.HandlePostCatalogInlay))
 	mux.Handle("POST /api/project/{uuid}/inlays/custom", canCreateProject.ThenFunc(inlayModule.HandlePostCustomInlay))
+	mux.Handle("POST /api/project/{uuid}/inlays/clone", canCreateProject.ThenFunc(inlayModule.HandleCloneInlays))
+	mux.Handle("POST /api/project/{uuid}/reorder", canCreateProject.ThenFunc(inlayModule.HandleReorderProject))
 	mux.Handle("GET /api/inlay/{uuid}", protected.ThenFunc(inlayModule.HandleGetInlayByUUID))
 	mux.Handle("PATCH /api/inlay/{uuid}", canManageProject.ThenFunc(inlayModule.HandlePatchInlay))
 	mux.Handle("POST /api/inlay/{uuid}/recustomize", canManageProject.ThenFunc(inlayModule.HandleRecustomizeInlay))
 	mux.Handle("PATCH /api/inlay/{uuid}/step", canManageKanban.ThenFunc(inlayModule.HandlePatchInlayStep))
 	mux.Handle("DELETE /api/inlay/{uuid}", canManageProject.ThenFunc(inlayModule.HandleDeleteInlay))
 	mux.Handle("GET /api/inlay/{uuid}/milestones", protected.ThenFunc(inlayModule.HandleGetInlayMilestones))
+	mux.Handle("GET /api/inlay/{uuid}/ship-estimate", protected.ThenFunc(inlayModule.HandleGetInlayShipEstimate))
+	mux.Handle("GET /api/project/{uuid}/ship-estimate", protected.ThenFunc(inlayModule.HandleGetProjectShipEstimate))
+	mux.Handle("GET /api/reports/step-durations", canManageKanban.ThenFunc(inlayModule.HandleGetStepDurations))
+	mux.Handle("GET /api/reports/slow-steps", canManageKanban.ThenFunc(inlayModule.HandleGetSlowSteps))
 	mux.Handle("GET /api/inlay/{uuid}/updates", protected.ThenFunc(inlayModule.HandleGetInlayUpdates))
 	mux.Handle("POST /api/inlay/{uuid}/updates", canCreateInlayUpdate.ThenFunc(inlayModule.HandlePostInlayUpdate))
 	mux.Handle("GET /api/inlay/{uuid}/sandblast", protected.ThenFunc(inlayModule.HandleGetSandblastFile))
 	mux.Handle("POST /api/inlay/{uuid}/sandblast", canManageKanban.ThenFunc(inlayModule.HandlePostSandblastFile))
 
+	nestingModule := nesting.NewNestingModule(app)
+	mux.Handle("POST /api/nesting", canManageKanban.ThenFunc(nestingModule.HandlePostBatchNesting))
+	mux.Handle("POST /api/project/{uuid}/nesting", canManageKanban.ThenFunc(nestingModule.HandlePostProjectNesting))
+
+	canManageRemakes := alice.New(app.Authenticate, app.RequirePermission(data.ActionManageRemakes))
+
+	remakeModule := remake.NewRemakeModule(app)
+	mux.Handle("GET /api/remakes", canManageRemakes.ThenFunc(remakeModule.HandleGetRemakes))
+	mux.Handle("GET /api/inlay/{uuid}/remakes", protected.ThenFunc(remakeModule.HandleGetInlayRemakes))
+	mux.Handle("POST /api/inlay/{uuid}/remakes", canManageProject.ThenFunc(remakeModule.HandlePostInlayRemake))
+	mux.Handle("POST /api/remake/{uuid}/approve", canManageRemakes.ThenFunc(remakeModule.HandleApproveRemake))
+	mux.Handle("POST /api/remake/{uuid}/decline", canManageRemakes.ThenFunc(remakeModule.HandleDeclineRemake))
+	mux.Handle("POST /api/remake/{uuid}/ship", canManageShipping.ThenFunc(remakeModule.HandleShipRemake))
+
 	chatModule := chat.NewChatModule(app)
 	mux.Handle("GET /api/project/{uuid}/chats", protected.ThenFunc(chatModule.HandleGetProjectChats))
 	mux.Handle("POST /api/project/{uuid}/chats", canSendChat.ThenFunc(chatModule.HandlePostProjectChat))
@@ -120,11 +211,42 @@ func GetRoutes(app *app.Application) http.Handler {
 	mux.Handle("POST /api/dealership-user", canManageDealershipUsers.ThenFunc(userModule.HandleCreateDealershipUser))
 	mux.Handle("PATCH /api/dealership-user/{uuid}", canManageDealershipUsers.ThenFunc(userModule.HandleUpdateDealershipUser))
 	mux.Handle("DELETE /api/dealership-user/{uuid}", canManageDealershipUsers.ThenFunc(userModule.HandleDeleteDealershipUser))
+	mux.Handle("GET /api/dealership-user/{uuid}/invitation", canManageDealershipUsers.ThenFunc(userModule.HandleGetDealershipUserInvitation))
+	mux.Handle("POST /api/dealership-user/{uuid}/invitation/resend", canManageDealershipUsers.ThenFunc(userModule.HandleResendDealershipUserInvitation))
+	mux.Handle("DELETE /api/dealership-user/{uuid}/invitation", canManageDealershipUsers.ThenFunc(userModule.HandleRevokeDealershipUserInvitation))
+	mux.Handle("DELETE /api/dealership-user/{uuid}/sessions", canManageDealershipUsers.ThenFunc(userModule.HandleDeleteDealershipUserSessions))
+	mux.Handle("POST /api/dealership-import", canManageDealerships.ThenFunc(userModule.HandlePostDealershipImport))
 	mux.Handle("GET /api/internal-user", protected.ThenFunc(userModule.HandleGetInternalUsers))
 	mux.Handle("GET /api/internal-user/{uuid}", protected.ThenFunc(userModule.HandleGetInternalUserByUUID))
 	mux.Handle("POST /api/internal-user", canManageInternalUsers.ThenFunc(userModule.HandleCreateInternalUser))
 	mux.Handle("PATCH /api/internal-user/{uuid}", canManageInternalUsers.ThenFunc(userModule.HandleUpdateInternalUser))
 	mux.Handle("DELETE /api/internal-user/{uuid}", canManageInternalUsers.ThenFunc(userModule.HandleDeleteInternalUser))
+	mux.Handle("GET /api/internal-user/{uuid}/invitation", canManageInternalUsers.ThenFunc(userModule.HandleGetInternalUserInvitation))
+	mux.Handle("POST /api/internal-user/{uuid}/invitation/resend", canManageInternalUsers.ThenFunc(userModule.HandleResendInternalUserInvitation))
+	mux.Handle("DELETE /api/internal-user/{uuid}/invitation", canManageInternalUsers.ThenFunc(userModule.HandleRevokeInternalUserInvitation))
+	mux.Handle("DELETE /api/internal-user/{uuid}/sessions", canManageInternalUsers.ThenFunc(userModule.HandleDeleteInternalUserSessions))
+	mux.Handle("DELETE /api/internal-user/{uuid}/two-factor", canManageInternalUsers.ThenFunc(userModule.HandleDeleteInternalUserTwoFactor))
+	mux.Handle("GET /api/two-factor-policy", canManageInternalUsers.ThenFunc(userModule.HandleGetTwoFactorPolicy))
+	mux.Handle("PUT /api/two-factor-policy", canManageInternalUsers.ThenFunc(userModule.HandlePutTwoFactorPolicy))
+
+	canManageRoles := alice.New(app.Authenticate, app.RequirePermission(data.ActionManageRoles))
+
+	roleModule := role.NewRoleModule(app)
+	mux.Handle("GET /api/roles/{user_type}", canManageRoles.ThenFunc(roleModule.HandleGetRoles))
+	mux.Handle("POST /api/roles/{user_type}", canManageRoles.ThenFunc(roleModule.HandlePostRole))
+	mux.Handle("PUT /api/roles/{user_type}/{uuid}", canManageRoles.ThenFunc(roleModule.HandlePutRole))
+	mux.Handle("DELETE /api/roles/{user_type}/{uuid}", canManageRoles.ThenFunc(roleModule.HandleDeleteRole))
+	mux.Handle("GET /api/dealership-user/{uuid}/permissions", canManageRoles.ThenFunc(roleModule.HandleGetDealershipUserPermissions))
+	mux.Handle("PUT /api/dealership-user/{uuid}/permissions", canManageRoles.ThenFunc(roleModule.HandlePutDealershipUserPermissions))
+	mux.Handle("GET /api/internal-user/{uuid}/permissions", canManageRoles.ThenFunc(roleModule.HandleGetInternalUserPermissions))
+	mux.Handle("PUT /api/internal-user/{uuid}/permissions", canManageRoles.ThenFunc(roleModule.HandlePutInternalUserPermissions))
+
+	canManageJobs := alice.New(app.Authenticate, app.RequirePermission(data.ActionManageJobs))
+
+	jobModule := job.NewJobModule(app)
+	mux.Handle("GET /api/jobs", canManageJobs.ThenFunc(jobModule.HandleGetJobs))
+	mux.Handle("GET /api/jobs/{name}/runs", canManageJobs.ThenFunc(jobModule.HandleGetJobRuns))
+	mux.Handle("POST /api/jobs/{name}/run", canManageJobs.ThenFunc(jobModule.HandlePostJobRun))
 
 	uploadModule := upload.NewUploadModule(app)
 	mux.Handle("POST /api/upload", protected.ThenFunc(uploadModule.HandlePostUpload))
@@ -155,6 +277,7 @@ func GetRoutes(app *app.Application) http.Handler {
 
 	customizerModule := customizer.NewCustomizerModule(app)
 	mux.Handle("POST /api/catalog/{uuid}/bake", protected.ThenFunc(customizerModule.HandleBake))
+	mux.Handle("POST /api/catalog/{uuid}/theme", protected.ThenFunc(customizerModule.HandleTheme))
 
 	canManageMaterials := alice.New(app.Authenticate, app.RequirePermission(data.ActionManageMaterials))
 
@@ -174,12 +297,30 @@ func GetRoutes(app *app.Application) http.Handler {
 	mux.Handle("PATCH /api/grouts/{uuid}", canManageMaterials.ThenFunc(groutModule.HandlePatchGrout))
 	mux.Handle("DELETE /api/grouts/{uuid}", canManageMaterials.ThenFunc(groutModule.HandleDeleteGrout))
 
+	inventoryModule := inventory.NewInventoryModule(app)
+	mux.Handle("GET /api/inventory", canManageMaterials.ThenFunc(inventoryModule.HandleGetInventory))
+	mux.Handle("PUT /api/glass-colors/{uuid}/stock", canManageMaterials.ThenFunc(inventoryModule.HandlePutGlassColorStock))
+	mux.Handle("PUT /api/grouts/{uuid}/stock", canManageMaterials.ThenFunc(inventoryModule.HandlePutGroutStock))
+
 	priceGroupModule := pricegroup.NewPriceGroupModule(app)
 	mux.Handle("GET /api/price-groups", canManagePriceGroups.ThenFunc(priceGroupModule.HandleGetPriceGroups))
 	mux.Handle("POST /api/price-groups", canManagePriceGroups.ThenFunc(priceGroupModule.HandlePostPriceGroup))
 	mux.Handle("GET /api/price-groups/{uuid}", canManagePriceGroups.ThenFunc(priceGroupModule.HandleGetPriceGroup))
 	mux.Handle("PATCH /api/price-groups/{uuid}", canManagePriceGroups.ThenFunc(priceGroupModule.HandlePatchPriceGroup))
 	mux.Handle("DELETE /api/price-groups/{uuid}", canManagePriceGroups.ThenFunc(priceGroupModule.HandleDeletePriceGroup))
+	mux.Handle("GET /api/dealership/{uuid}/price-tiers", canManagePriceGroups.ThenFunc(priceGroupModule.HandleGetDealershipPriceTiers))
+	mux.Handle("POST /api/dealership/{uuid}/price-tiers", canManagePriceGroups.ThenFunc(priceGroupModule.HandlePostDealershipPriceTier))
+	mux.Handle("PUT /api/price-tiers/{uuid}", canManagePriceGroups.ThenFunc(priceGroupModule.HandlePutPriceTier))
+	mux.Handle("DELETE /api/price-tiers/{uuid}", canManagePriceGroups.ThenFunc(priceGroupModule.HandleDeletePriceTier))
+
+	canManageTaxes := alice.New(app.Authenticate, app.RequirePermission(data.ActionManageTaxes))
+
+	taxModule := tax.NewTaxModule(app)
+	mux.Handle("GET /api/tax-rates", canManageTaxes.ThenFunc(taxModule.HandleGetTaxRates))
+	mux.Handle("POST /api/tax-rates", canManageTaxes.ThenFunc(taxModule.HandlePostTaxRate))
+	mux.Handle("PUT /api/tax-rates/{uuid}", canManageTaxes.ThenFunc(taxModule.HandlePutTaxRate))
+	mux.Handle("DELETE /api/tax-rates/{uuid}", canManageTaxes.ThenFunc(taxModule.HandleDeleteTaxRate))
+	mux.Handle("PUT /api/dealership/{uuid}/tax-exemption", canManageTaxes.ThenFunc(taxModule.HandlePutTaxExemption))
 
 	canManageSupport := alice.New(app.Authenticate, app.RequirePermission(data.ActionManageSupport))
 
@@ -18,6 +18,7 @@ import (
 
 	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
 	"github.com/Lil-Strudel/glassact-studios/apps/api/config"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/scheduler"
 	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-migrate/migrate/v4"
@@ -85,6 +86,8 @@ func setupTestApp(t *testing.T) (*testContext, func()) {
 			Port:       8080,
 			BaseURL:    "http://localhost:3000",
 			AuthSecret: "test-secret-key-at-least-32-characters-long",
+
+			RushSurchargePercent: data.DefaultRushSurchargePercent,
 		},
 		Db:       db,
 		Err:      app.AppError,
@@ -93,9 +96,15 @@ func setupTestApp(t *testing.T) (*testContext, func()) {
 		Wg:       sync.WaitGroup{},
 		S3:       nil,
 		Mailer:   app.NewMailer("localhost", 1025, "", ""),
+		Tax:      data.RateTableTaxEngine{Rates: db.TaxRates},
 	}
 
+	// Jobs are registered so they can be run by hand, but not started.
+	testApp.Scheduler = scheduler.New(db.JobRuns, testApp.Log)
+	require.NoError(t, testApp.RegisterJobs())
+
 	cleanup := func() {
+		testApp.Scheduler.Stop()
 		pool.Close()
 		stdb.Close()
 		container.Terminate(ctx) //nolint:errcheck
@@ -290,7 +299,7 @@ func TestAPIEndpoints(t *testing.T) {
 			t.Logf("✓ GET /api/auth/microsoft (%d)", resp.statusCode)
 		})
 
-		t.Run("POST /api/auth/magic-link (invalid email)", func(t *testing.T) {
+		t.Run("POST /api/auth/magic-link (unknown email)", func(t *testing.T) {
 			resp := testCtx.request(testRequest{
 				method: "POST",
 				path:   "/api/auth/magic-link",
@@ -299,8 +308,8 @@ func TestAPIEndpoints(t *testing.T) {
 				},
 			})
 
-			assert.Equal(t, http.StatusUnauthorized, resp.statusCode)
-			t.Logf("✓ POST /api/auth/magic-link (invalid email) (%d)", resp.statusCode)
+			assert.Equal(t, http.StatusNoContent, resp.statusCode, "answers the same as for a real account")
+			t.Logf("✓ POST /api/auth/magic-link (unknown email) (%d)", resp.statusCode)
 		})
 
 		t.Run("POST /api/auth/token/access", func(t *testing.T) {
@@ -1384,6 +1393,10 @@ func TestAPIEndpoints(t *testing.T) {
 
 		t.Run("ship persists tracking number then deliver unpaid goes to invoiced", func(t *testing.T) {
 			project := createShippingProject("Ship Unpaid", data.ProjectStatuses.InProduction)
+			priceGroup := seedPriceGroup(t, testCtx, "Shipping Price Group")
+			catalogItem := seedCatalogItem(t, testCtx, priceGroup.ID, "TEST-SHIP-001")
+			inlay := seedDraftCatalogInlay(t, testCtx, project.ID, catalogItem.ID, "Shipped Inlay")
+			seedOrderSnapshot(t, testCtx, inlay, priceGroup.ID)
 
 			shipResp := testCtx.request(testRequest{
 				method: "POST",
@@ -0,0 +1,281 @@
+package nesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/upload"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/svg"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type NestingModule struct {
+	*app.Application
+}
+
+func NewNestingModule(app *app.Application) *NestingModule {
+	return &NestingModule{app}
+}
+
+// Stock sheet defaults when a request does not name one: a common art-glass
+// half sheet, with an eighth of an inch between pieces for the score line.
+var defaultSheet = svg.SheetSize{Width: 24, Height: 18}
+
+const defaultSpacing = 0.125
+
+type nestingRequest struct {
+	InlayUUIDs  []string              `json:"inlay_uuids" validate:"omitempty,dive,uuid4"`
+	Sheet       *svg.SheetSize        `json:"sheet"`
+	ColorSheets map[int]svg.SheetSize `json:"color_sheets" validate:"omitempty,dive"`
+	Spacing     *float64              `json:"spacing" validate:"omitempty,gte=0"`
+}
+
+type nestingLayout struct {
+	GlassColorID int    `json:"glass_color_id"`
+	SheetIndex   int    `json:"sheet_index"`
+	SVG          string `json:"svg"`
+	DXF          string `json:"dxf"`
+}
+
+type nestingSkip struct {
+	InlayUUID string `json:"inlay_uuid"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+}
+
+type nestingResponse struct {
+	svg.NestReport
+	InlayCount int             `json:"inlay_count"`
+	Layouts    []nestingLayout `json:"layouts"`
+	Skipped    []nestingSkip   `json:"skipped"`
+}
+
+// HandlePostProjectNesting nests every materials-prep inlay of one project, or
+// the subset named in inlay_uuids.
+func (m NestingModule) HandlePostProjectNesting(w http.ResponseWriter, r *http.Request) {
+	projectUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(projectUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	var body nestingRequest
+	err = m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	project, found, err := m.Db.Projects.GetByUUID(projectUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	projectInlays, err := m.Db.Inlays.GetByProjectID(project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	inlays, err := selectInlays(projectInlays, body.InlayUUIDs)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	m.writeNesting(w, r, inlays, body)
+}
+
+// HandlePostBatchNesting nests a batch of kanban inlays across projects. With
+// no inlay_uuids it nests the whole materials-prep column.
+func (m NestingModule) HandlePostBatchNesting(w http.ResponseWriter, r *http.Request) {
+	var body nestingRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	var candidates []*data.Inlay
+	if len(body.InlayUUIDs) == 0 {
+		candidates, err = m.Db.Inlays.GetByManufacturingStep(data.ManufacturingSteps.MaterialsPrep)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+	} else {
+		for _, inlayUUID := range body.InlayUUIDs {
+			inlay, found, err := m.Db.Inlays.GetByUUID(inlayUUID)
+			if err != nil {
+				m.WriteError(w, r, m.Err.ServerError, err)
+				return
+			}
+			if !found {
+				m.WriteError(w, r, m.Err.RecordNotFound, nil)
+				return
+			}
+			candidates = append(candidates, inlay)
+		}
+	}
+
+	inlays, err := selectInlays(candidates, body.InlayUUIDs)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	m.writeNesting(w, r, inlays, body)
+}
+
+// selectInlays narrows candidates to the requested uuids (all of them when none
+// are given) and requires each chosen inlay to be at materials-prep — earlier
+// steps may still change, later ones have already been cut.
+func selectInlays(candidates []*data.Inlay, uuids []string) ([]*data.Inlay, error) {
+	materialsPrep := string(data.ManufacturingSteps.MaterialsPrep)
+
+	if len(uuids) == 0 {
+		out := make([]*data.Inlay, 0, len(candidates))
+		for _, inlay := range candidates {
+			if inlay.ManufacturingStep != nil && *inlay.ManufacturingStep == materialsPrep {
+				out = append(out, inlay)
+			}
+		}
+		return out, nil
+	}
+
+	byUUID := make(map[string]*data.Inlay, len(candidates))
+	for _, inlay := range candidates {
+		byUUID[inlay.UUID] = inlay
+	}
+
+	out := make([]*data.Inlay, 0, len(uuids))
+	seen := make(map[string]bool, len(uuids))
+	for _, u := range uuids {
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+
+		inlay, ok := byUUID[u]
+		if !ok {
+			return nil, fmt.Errorf("inlay %s is not part of this batch", u)
+		}
+		if inlay.ManufacturingStep == nil || *inlay.ManufacturingStep != materialsPrep {
+			return nil, fmt.Errorf("inlay %q is not in %s", inlay.Name, materialsPrep)
+		}
+		out = append(out, inlay)
+	}
+	return out, nil
+}
+
+func (m NestingModule) writeNesting(w http.ResponseWriter, r *http.Request, inlays []*data.Inlay, body nestingRequest) {
+	opts := svg.NestOptions{
+		Sheet:         defaultSheet,
+		SheetsByColor: body.ColorSheets,
+		Spacing:       defaultSpacing,
+	}
+	if body.Sheet != nil {
+		opts.Sheet = *body.Sheet
+	}
+	if body.Spacing != nil {
+		opts.Spacing = *body.Spacing
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	var pieces []svg.NestPiece
+	skipped := []nestingSkip{}
+	for _, inlay := range inlays {
+		design, width, height, reason, err := m.loadDesign(ctx, inlay)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+		if reason != "" {
+			skipped = append(skipped, nestingSkip{InlayUUID: inlay.UUID, Name: inlay.Name, Reason: reason})
+			continue
+		}
+
+		inlayPieces, err := svg.ExtractPieces(design, width, height)
+		if err != nil {
+			skipped = append(skipped, nestingSkip{InlayUUID: inlay.UUID, Name: inlay.Name, Reason: err.Error()})
+			continue
+		}
+		if len(inlayPieces) == 0 {
+			skipped = append(skipped, nestingSkip{InlayUUID: inlay.UUID, Name: inlay.Name, Reason: "design has no assigned glass pieces"})
+			continue
+		}
+		for i := range inlayPieces {
+			inlayPieces[i].InlayRef = inlay.UUID
+		}
+		pieces = append(pieces, inlayPieces...)
+	}
+
+	report, err := svg.Nest(pieces, opts)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	layouts := []nestingLayout{}
+	for _, color := range report.Colors {
+		for _, sheet := range color.Sheets {
+			sheetSVG, err := svg.RenderSheetSVG(sheet, color.Sheet)
+			if err != nil {
+				m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to render cut layout: %w", err))
+				return
+			}
+			layouts = append(layouts, nestingLayout{
+				GlassColorID: color.GlassColorID,
+				SheetIndex:   sheet.Index,
+				SVG:          string(sheetSVG),
+				DXF:          string(svg.RenderSheetDXF(sheet, color.Sheet)),
+			})
+		}
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, nestingResponse{
+		NestReport: report,
+		InlayCount: len(inlays) - len(skipped),
+		Layouts:    layouts,
+		Skipped:    skipped,
+	})
+}
+
+// loadDesign fetches the baked design an inlay was ordered with and the inch
+// dimensions it was ordered at, both from its order snapshot. A non-empty
+// reason means the inlay cannot be nested and is reported rather than failing
+// the whole batch.
+func (m NestingModule) loadDesign(ctx context.Context, inlay *data.Inlay) ([]byte, float64, float64, string, error) {
+	snapshot, found, err := m.Db.OrderSnapshots.GetByInlayID(inlay.ID)
+	if err != nil {
+		return nil, 0, 0, "", err
+	}
+	if !found {
+		return nil, 0, 0, "inlay has no order snapshot", nil
+	}
+
+	design, err := upload.GetOrderedDesign(ctx, m.Db, m.S3, m.Cfg, inlay, snapshot)
+	switch {
+	case errors.Is(err, upload.ErrOrderedDesignNotFound),
+		errors.Is(err, upload.ErrNoDesign),
+		errors.Is(err, upload.ErrDesignNotSVG):
+		return nil, 0, 0, err.Error(), nil
+	case err != nil:
+		return nil, 0, 0, "", err
+	}
+
+	return design, snapshot.Width, snapshot.Height, "", nil
+}
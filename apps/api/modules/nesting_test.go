@@ -0,0 +1,108 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setInlayStep(t *testing.T, ctx *testContext, inlay *data.Inlay, step data.ManufacturingStep) {
+	tx, err := ctx.db.STDB.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	s := string(step)
+	inlay.ManufacturingStep = &s
+	require.NoError(t, ctx.db.Inlays.TxUpdateFields(tx, inlay))
+	require.NoError(t, tx.Commit())
+}
+
+func TestPostProjectNesting_DealershipUserForbidden(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, _ := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-NEST-0001")
+
+	project, _ := seedOrderedProjectWithInlay(t, ctx, dealershipUser.DealershipID, item.ID)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/nesting", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{},
+	})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, string(resp.body))
+}
+
+func TestPostBatchNesting_RejectsInlayOutsideMaterialsPrep(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-NEST-0002")
+
+	_, inlay := seedOrderedProjectWithInlay(t, ctx, dealershipUser.DealershipID, item.ID)
+	setInlayStep(t, ctx, inlay, data.ManufacturingSteps.Ordered)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/nesting",
+		token:  internalToken,
+		body:   map[string]any{"inlay_uuids": []string{inlay.UUID}},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, string(resp.body))
+}
+
+func TestPostBatchNesting_ReportsInlaysWithoutSnapshotAsSkipped(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-NEST-0003")
+
+	_, inlay := seedOrderedProjectWithInlay(t, ctx, dealershipUser.DealershipID, item.ID)
+	setInlayStep(t, ctx, inlay, data.ManufacturingSteps.MaterialsPrep)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/nesting",
+		token:  internalToken,
+		body:   map[string]any{"sheet": map[string]any{"width": 12, "height": 12}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var result struct {
+		TotalSheets int `json:"total_sheets"`
+		Skipped     []struct {
+			InlayUUID string `json:"inlay_uuid"`
+		} `json:"skipped"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &result))
+	assert.Equal(t, 0, result.TotalSheets)
+	require.Len(t, result.Skipped, 1)
+	assert.Equal(t, inlay.UUID, result.Skipped[0].InlayUUID)
+}
+
+func TestPostBatchNesting_RejectsNonPositiveSheet(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, _, _, internalToken := seedTestData(t, ctx)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/nesting",
+		token:  internalToken,
+		body:   map[string]any{"sheet": map[string]any{"width": 0, "height": 12}},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, string(resp.body))
+}
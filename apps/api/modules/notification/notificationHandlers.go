@@ -27,6 +27,7 @@ var dealershipEventTypes = []data.NotificationEventType{
 	data.NotificationEventTypes.InvoiceSent,
 	data.NotificationEventTypes.PaymentReceived,
 	data.NotificationEventTypes.ChatMessage,
+	data.NotificationEventTypes.RemakeReviewed,
 }
 
 var internalEventTypes = []data.NotificationEventType{
@@ -38,6 +39,9 @@ var internalEventTypes = []data.NotificationEventType{
 	data.NotificationEventTypes.ProofDeclined,
 	data.NotificationEventTypes.ProjectDelivered,
 	data.NotificationEventTypes.ChatMessage,
+	data.NotificationEventTypes.LowStock,
+	data.NotificationEventTypes.RemakeRequested,
+	data.NotificationEventTypes.RushOrderPlaced,
 }
 
 func (m *NotificationModule) HandleGetNotifications(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,216 @@
+package oidc
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type OIDCModule struct {
+	*app.Application
+}
+
+func NewOIDCModule(app *app.Application) *OIDCModule {
+	return &OIDCModule{app}
+}
+
+// oidcProviderRequest configures a provider. The client secret is write-only:
+// it is never sent back, and leaving it out of an update keeps the old one.
+type oidcProviderRequest struct {
+	Key                string  `json:"key" validate:"required"`
+	Name               string  `json:"name" validate:"required,min=1,max=255"`
+	DiscoveryURL       string  `json:"discovery_url" validate:"required,url"`
+	ClientID           string  `json:"client_id" validate:"required,max=255"`
+	ClientSecret       *string `json:"client_secret" validate:"omitempty,min=1"`
+	Scopes             string  `json:"scopes" validate:"max=255"`
+	SubjectClaim       string  `json:"subject_claim" validate:"max=255"`
+	EmailClaim         string  `json:"email_claim" validate:"max=255"`
+	EmailVerifiedClaim *string `json:"email_verified_claim" validate:"omitempty,max=255"`
+	IsActive           bool    `json:"is_active"`
+	DealershipIDs      []int   `json:"dealership_ids" validate:"dive,gt=0"`
+}
+
+func (m *OIDCModule) getOIDCProvider(w http.ResponseWriter, r *http.Request) (*data.OIDCProvider, bool) {
+	providerUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(providerUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	provider, found, err := m.Db.OIDCProviders.GetByUUID(providerUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return provider, true
+}
+
+func (m *OIDCModule) applyRequest(provider *data.OIDCProvider, body oidcProviderRequest) error {
+	provider.Name = body.Name
+	provider.DiscoveryURL = body.DiscoveryURL
+	provider.ClientID = body.ClientID
+	provider.Scopes = body.Scopes
+	provider.SubjectClaim = body.SubjectClaim
+	provider.EmailClaim = body.EmailClaim
+	provider.IsActive = body.IsActive
+	provider.DealershipIDs = body.DealershipIDs
+
+	if body.EmailVerifiedClaim != nil {
+		provider.EmailVerifiedClaim = *body.EmailVerifiedClaim
+	}
+
+	if body.ClientSecret != nil {
+		return provider.SetClientSecret(data.OIDCProviderKey(m.Cfg.AuthSecret), *body.ClientSecret)
+	}
+	return nil
+}
+
+func (m *OIDCModule) HandleGetOIDCProviders(w http.ResponseWriter, r *http.Request) {
+	providers, err := m.Db.OIDCProviders.GetAll()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, providers)
+}
+
+func (m *OIDCModule) HandleGetOIDCProvider(w http.ResponseWriter, r *http.Request) {
+	provider, ok := m.getOIDCProvider(w, r)
+	if !ok {
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, provider)
+}
+
+// HandlePostOIDCProvider registers a provider. Its key becomes part of the
+// login and callback URLs, so it cannot be changed afterwards.
+func (m *OIDCModule) HandlePostOIDCProvider(w http.ResponseWriter, r *http.Request) {
+	var body oidcProviderRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	err = data.ValidateProviderKey(body.Key)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	if body.ClientSecret == nil {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("client_secret is required"))
+		return
+	}
+
+	_, found, err := m.Db.OIDCProviders.GetByKey(body.Key)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if found {
+		m.WriteError(w, r, m.Err.Conflict, errors.New("a provider with this key already exists"))
+		return
+	}
+
+	_, missing, err := m.Db.Dealerships.FirstMissingID(body.DealershipIDs)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if missing {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("dealership_ids names a dealership that does not exist"))
+		return
+	}
+
+	provider := &data.OIDCProvider{
+		Key:                body.Key,
+		EmailVerifiedClaim: "email_verified",
+	}
+	err = m.applyRequest(provider, body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	err = m.Db.OIDCProviders.Insert(provider)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusCreated, provider)
+}
+
+func (m *OIDCModule) HandlePutOIDCProvider(w http.ResponseWriter, r *http.Request) {
+	var body oidcProviderRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	provider, ok := m.getOIDCProvider(w, r)
+	if !ok {
+		return
+	}
+
+	if body.Key != provider.Key {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("key cannot be changed"))
+		return
+	}
+
+	_, missing, err := m.Db.Dealerships.FirstMissingID(body.DealershipIDs)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if missing {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("dealership_ids names a dealership that does not exist"))
+		return
+	}
+
+	err = m.applyRequest(provider, body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	err = m.Db.OIDCProviders.Update(provider)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, provider)
+}
+
+// HandleDeleteOIDCProvider removes a provider and unlinks every identity that
+// signed in through it.
+func (m *OIDCModule) HandleDeleteOIDCProvider(w http.ResponseWriter, r *http.Request) {
+	provider, ok := m.getOIDCProvider(w, r)
+	if !ok {
+		return
+	}
+
+	err := m.Db.OIDCProviders.Delete(provider)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
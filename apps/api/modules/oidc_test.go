@@ -0,0 +1,174 @@
+package modules
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// standInIdP is a minimal OpenID provider. The authorization code a test
+// calls back with picks which user's claims userinfo returns.
+func standInIdP(t *testing.T, users map[string]map[string]any) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 server.URL,
+			"authorization_endpoint": server.URL + "/authorize",
+			"token_endpoint":         server.URL + "/token",
+			"userinfo_endpoint":      server.URL + "/userinfo",
+		})
+	})
+
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok {
+			clientID, clientSecret = r.FormValue("client_id"), r.FormValue("client_secret")
+		}
+		if clientID != "glassact" || clientSecret != "idp-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": r.FormValue("code"),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	})
+
+	mux.HandleFunc("GET /userinfo", func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := users[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(claims)
+	})
+
+	return server
+}
+
+func TestOIDCProvider_LoginPerDealership(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, internalUser, internalToken := seedTestData(t, ctx)
+
+	otherDealership := &data.Dealership{
+		Name:                "Other Dealership",
+		PaymentTiming:       data.PaymentTimings.PostShipping,
+		SandblastFileFormat: data.SandblastFileFormats.PDF,
+		Address:             data.Address{Street: "1 Side St", City: "Test City", State: "TS", PostalCode: "12345", Country: "US"},
+	}
+	require.NoError(t, ctx.db.Dealerships.Insert(otherDealership))
+
+	otherUser := &data.DealershipUser{
+		DealershipID: otherDealership.ID,
+		Name:         "Other User",
+		Email:        "other@example.com",
+		Avatar:       "https://example.com/avatar.jpg",
+		Role:         data.DealershipUserRoles.Admin,
+		IsActive:     true,
+	}
+	require.NoError(t, ctx.db.DealershipUsers.Insert(otherUser))
+
+	idp := standInIdP(t, map[string]map[string]any{
+		"dealer":     {"oid": "dealer-1", "mail": dealershipUser.Email},
+		"unverified": {"oid": "dealer-2", "mail": dealershipUser.Email, "mail_verified": false},
+		"internal":   {"oid": "staff-1", "mail": internalUser.Email},
+		"other":      {"oid": "other-1", "mail": otherUser.Email},
+	})
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/oidc-providers",
+		token:  internalToken,
+		body: map[string]any{
+			"key":                  "google",
+			"name":                 "Not Google",
+			"discovery_url":        idp.URL + "/.well-known/openid-configuration",
+			"client_id":            "glassact",
+			"client_secret":        "idp-secret",
+			"dealership_ids":       []int{dealershipUser.DealershipID},
+			"email_verified_claim": "mail_verified",
+		},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "built-in provider keys are reserved")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/oidc-providers",
+		token:  internalToken,
+		body: map[string]any{
+			"key":                  "acme",
+			"name":                 "Acme SSO",
+			"discovery_url":        idp.URL + "/.well-known/openid-configuration",
+			"client_id":            "glassact",
+			"client_secret":        "idp-secret",
+			"subject_claim":        "oid",
+			"email_claim":          "mail",
+			"email_verified_claim": "mail_verified",
+			"is_active":            true,
+			"dealership_ids":       []int{dealershipUser.DealershipID},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+	assert.NotContains(t, string(resp.body), "idp-secret", "the client secret is write-only")
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/auth/oidc"})
+	require.Equal(t, http.StatusOK, resp.statusCode)
+	assert.Contains(t, string(resp.body), `"login_url":"http://localhost:3000/api/auth/oidc/acme"`)
+
+	callback := func(code string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		w := cookieRequest(t, ctx, http.MethodGet, "/api/auth/oidc/acme", "", nil)
+		require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+
+		location, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, idp.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+		assert.Equal(t, "http://localhost:3000/api/auth/oidc/acme/callback", location.Query().Get("redirect_uri"))
+
+		query := url.Values{"state": {location.Query().Get("state")}, "code": {code}}
+		return cookieRequest(t, ctx, http.MethodGet, "/api/auth/oidc/acme/callback?"+query.Encode(), "", nil)
+	}
+
+	w := callback("unverified")
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "an unverified email is not matched to a user")
+
+	w = callback("dealer")
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	assert.NotEmpty(t, responseCookie(w, "refresh_token"))
+
+	account, found, err := ctx.db.DealershipAccounts.GetByProvider("acme", "dealer-1")
+	require.NoError(t, err)
+	require.True(t, found, "the identity is linked under the provider's key")
+	assert.Equal(t, dealershipUser.ID, account.DealershipUserID)
+
+	w = callback("internal")
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "registered providers do not sign in internal users")
+
+	w = callback("other")
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "the provider is not enabled for the other dealership")
+
+	_, found, err = ctx.db.DealershipAccounts.GetByProvider("acme", "other-1")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	w = cookieRequest(t, ctx, http.MethodGet, "/api/auth/oidc/unknown", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,111 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceTiers_PlaceOrderRecordsTierDiscount(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-TIER-0001")
+
+	dealership, found, err := ctx.db.Dealerships.GetByID(dealershipUser.DealershipID)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/dealership/%s/price-tiers", dealership.UUID),
+		token:  internalToken,
+		body: map[string]any{
+			"name":             "Preferred",
+			"discount_percent": 10,
+			"effective_from":   time.Now().Add(-time.Hour),
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var tier data.DealershipPriceTier
+	require.NoError(t, json.Unmarshal(resp.body, &tier))
+
+	project := seedDraftProject(t, ctx, dealershipUser.DealershipID, "Tiered Project")
+	inlay := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Dove")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{inlay.UUID}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	snapshot, found, err := ctx.db.OrderSnapshots.GetByInlayID(inlay.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, 10000, snapshot.ListPriceCents)
+	assert.Equal(t, 1000, snapshot.TierDiscountCents)
+	assert.Equal(t, 9000, snapshot.PriceCents)
+	require.NotNil(t, snapshot.PriceTierID)
+	assert.Equal(t, tier.ID, *snapshot.PriceTierID)
+}
+
+func TestPriceTiers_OverrideAndValidation(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+
+	dealership, found, err := ctx.db.Dealerships.GetByID(dealershipUser.DealershipID)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	path := fmt.Sprintf("/api/dealership/%s/price-tiers", dealership.UUID)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   path,
+		token:  dealershipToken,
+		body:   map[string]any{"name": "Sneaky", "discount_percent": 50},
+	})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "dealerships cannot set their own tier")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   path,
+		token:  internalToken,
+		body: map[string]any{
+			"name":            "Backwards",
+			"effective_from":  time.Now(),
+			"effective_until": time.Now().Add(-time.Hour),
+		},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   path,
+		token:  internalToken,
+		body: map[string]any{
+			"name":      "Contract",
+			"overrides": []map[string]any{{"price_group_id": priceGroup.ID, "price_cents": 7500}},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	tier, found, err := ctx.db.DealershipPriceTiers.GetActiveForDealership(dealership.ID, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, 7500, tier.BasePriceCents(priceGroup.ID, priceGroup.BasePriceCents))
+}
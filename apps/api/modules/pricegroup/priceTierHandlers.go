@@ -0,0 +1,199 @@
+package pricegroup
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type priceTierRequest struct {
+	Name            string                   `json:"name" validate:"required,min=1,max=255"`
+	DiscountPercent float64                  `json:"discount_percent" validate:"gte=0,lte=100"`
+	EffectiveFrom   *time.Time               `json:"effective_from"`
+	EffectiveUntil  *time.Time               `json:"effective_until"`
+	Overrides       []data.PriceTierOverride `json:"overrides" validate:"omitempty,dive"`
+}
+
+// validateTier checks what the struct tags cannot: the date range runs
+// forwards, and each override names a real price group once.
+func (m *PriceGroupModule) validateTier(body priceTierRequest, effectiveFrom time.Time) error {
+	if body.EffectiveUntil != nil && !body.EffectiveUntil.After(effectiveFrom) {
+		return fmt.Errorf("effective_until must be after effective_from")
+	}
+
+	seen := make(map[int]bool, len(body.Overrides))
+	for _, override := range body.Overrides {
+		if seen[override.PriceGroupID] {
+			return fmt.Errorf("price group %d is overridden more than once", override.PriceGroupID)
+		}
+		seen[override.PriceGroupID] = true
+
+		_, found, err := m.Db.PriceGroups.GetByID(override.PriceGroupID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("price group %d does not exist", override.PriceGroupID)
+		}
+	}
+	return nil
+}
+
+func (m *PriceGroupModule) getDealership(w http.ResponseWriter, r *http.Request) (*data.Dealership, bool) {
+	dealershipUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(dealershipUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	dealership, found, err := m.Db.Dealerships.GetByUUID(dealershipUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return dealership, true
+}
+
+func (m *PriceGroupModule) getPriceTier(w http.ResponseWriter, r *http.Request) (*data.DealershipPriceTier, bool) {
+	tierUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(tierUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	tier, found, err := m.Db.DealershipPriceTiers.GetByUUID(tierUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return tier, true
+}
+
+func (m *PriceGroupModule) HandleGetDealershipPriceTiers(w http.ResponseWriter, r *http.Request) {
+	dealership, ok := m.getDealership(w, r)
+	if !ok {
+		return
+	}
+
+	tiers, err := m.Db.DealershipPriceTiers.GetByDealershipID(dealership.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, tiers)
+}
+
+// HandlePostDealershipPriceTier sets up a negotiated rate for a dealership,
+// taking effect now unless a start is given. Orders and quotes made while it
+// is in effect are priced through it.
+func (m *PriceGroupModule) HandlePostDealershipPriceTier(w http.ResponseWriter, r *http.Request) {
+	var body priceTierRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	dealership, ok := m.getDealership(w, r)
+	if !ok {
+		return
+	}
+
+	effectiveFrom := time.Now()
+	if body.EffectiveFrom != nil {
+		effectiveFrom = *body.EffectiveFrom
+	}
+
+	if err := m.validateTier(body, effectiveFrom); err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	tier := &data.DealershipPriceTier{
+		DealershipID:    dealership.ID,
+		Name:            body.Name,
+		DiscountPercent: body.DiscountPercent,
+		EffectiveFrom:   effectiveFrom,
+		EffectiveUntil:  body.EffectiveUntil,
+		Overrides:       body.Overrides,
+	}
+
+	if err := m.Db.DealershipPriceTiers.Insert(tier); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusCreated, tier)
+}
+
+// HandlePutPriceTier replaces a tier's terms and overrides. Orders already
+// placed keep the prices recorded on their snapshots.
+func (m *PriceGroupModule) HandlePutPriceTier(w http.ResponseWriter, r *http.Request) {
+	var body priceTierRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	tier, ok := m.getPriceTier(w, r)
+	if !ok {
+		return
+	}
+
+	effectiveFrom := tier.EffectiveFrom
+	if body.EffectiveFrom != nil {
+		effectiveFrom = *body.EffectiveFrom
+	}
+
+	if err := m.validateTier(body, effectiveFrom); err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	tier.Name = body.Name
+	tier.DiscountPercent = body.DiscountPercent
+	tier.EffectiveFrom = effectiveFrom
+	tier.EffectiveUntil = body.EffectiveUntil
+	tier.Overrides = body.Overrides
+
+	if err := m.Db.DealershipPriceTiers.Update(tier); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, tier)
+}
+
+func (m *PriceGroupModule) HandleDeletePriceTier(w http.ResponseWriter, r *http.Request) {
+	tier, ok := m.getPriceTier(w, r)
+	if !ok {
+		return
+	}
+
+	if err := m.Db.DealershipPriceTiers.Delete(tier.ID); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
@@ -1,11 +1,13 @@
 package project
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/svg"
 	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
 )
 
@@ -26,12 +28,13 @@ func (m ProjectModule) HandleGetProjects(w http.ResponseWriter, r *http.Request)
 	var err error
 
 	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
+		// A group-level user sees every location's projects.
+		dealershipIDs, idsErr := m.ContextGetDealershipIDs(r)
+		if idsErr != nil {
+			m.WriteError(w, r, m.Err.ServerError, idsErr)
 			return
 		}
-		projects, err = m.Db.Projects.GetByDealershipID(*dealershipID)
+		projects, err = m.Db.Projects.GetByDealershipIDs(dealershipIDs)
 	} else {
 		projects, err = m.Db.Projects.GetAll()
 	}
@@ -103,6 +106,19 @@ type projectDetail struct {
 	AwaitingPayment     bool                     `json:"awaiting_payment"`
 	IsWatching          bool                     `json:"is_watching"`
 	WatcherCount        int                      `json:"watcher_count"`
+	ParentProject       *relatedProject          `json:"parent_project"`
+	ChildProjects       []relatedProject         `json:"child_projects"`
+}
+
+// relatedProject is a project linked to another by a split at order time.
+type relatedProject struct {
+	UUID   string             `json:"uuid"`
+	Name   string             `json:"name"`
+	Status data.ProjectStatus `json:"status"`
+}
+
+func toRelatedProject(project *data.Project) relatedProject {
+	return relatedProject{UUID: project.UUID, Name: project.Name, Status: project.Status}
 }
 
 // paymentDueAhead reports whether the dealership's payment deadline still lies
@@ -145,13 +161,14 @@ func (m ProjectModule) getProjectWithAccessCheck(w http.ResponseWriter, r *http.
 		return nil, false
 	}
 
-	user := m.ContextGetUser(r)
-	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || *dealershipID != project.DealershipID {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
-			return nil, false
-		}
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return nil, false
 	}
 
 	return project, true
@@ -165,7 +182,28 @@ func (m ProjectModule) HandleGetProjectByUUID(w http.ResponseWriter, r *http.Req
 
 	user := m.ContextGetUser(r)
 
-	detail := projectDetail{Project: project}
+	detail := projectDetail{Project: project, ChildProjects: []relatedProject{}}
+
+	if project.ParentProjectID != nil {
+		parent, found, err := m.Db.Projects.GetByID(*project.ParentProjectID)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to load parent project: %w", err))
+			return
+		}
+		if found {
+			related := toRelatedProject(parent)
+			detail.ParentProject = &related
+		}
+	}
+
+	children, err := m.Db.Projects.GetChildren(project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to load child projects: %w", err))
+		return
+	}
+	for _, child := range children {
+		detail.ChildProjects = append(detail.ChildProjects, toRelatedProject(child))
+	}
 
 	if isWatching, err := m.Db.ProjectWatchers.IsWatching(project.ID, user); err == nil {
 		detail.IsWatching = isWatching
@@ -254,6 +292,11 @@ func (m ProjectModule) HandlePatchProject(w http.ResponseWriter, r *http.Request
 		Name              *string `json:"name"`
 		InternalReference *string `json:"internal_reference"`
 		InstallationKit   *bool   `json:"installation_kit"`
+		// An empty string clears the date.
+		RequiredBy *string `json:"required_by" validate:"omitempty,len=0|datetime=2006-01-02"`
+		// A saved address of the project's dealership; an empty string ships
+		// to the dealership itself.
+		ShipToAddressUUID *string `json:"ship_to_address_uuid" validate:"omitempty,len=0|uuid4"`
 	}
 
 	err = m.ReadJSONBody(w, r, &body)
@@ -272,13 +315,14 @@ func (m ProjectModule) HandlePatchProject(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	user := m.ContextGetUser(r)
-	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || *dealershipID != project.DealershipID {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
-			return
-		}
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return
 	}
 
 	if body.Name != nil {
@@ -299,6 +343,41 @@ func (m ProjectModule) HandlePatchProject(w http.ResponseWriter, r *http.Request
 		project.InstallationKit = *body.InstallationKit
 	}
 
+	if body.RequiredBy != nil {
+		if !requiredByEditableStatuses[project.Status] {
+			m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("cannot change the required-by date on a project in %s status", project.Status))
+			return
+		}
+		requiredBy, parseErr := parseRequiredBy(*body.RequiredBy, time.Now())
+		if parseErr != nil {
+			m.WriteError(w, r, m.Err.BadRequest, parseErr)
+			return
+		}
+		project.RequiredBy = requiredBy
+	}
+
+	// The ship-to is copied into the order when it is placed; after that it is
+	// a shipping matter for GlassAct, not a setting on the project.
+	if body.ShipToAddressUUID != nil {
+		if project.Status != data.ProjectStatuses.Draft {
+			m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("can only change the ship-to address on projects in draft status"))
+			return
+		}
+		project.ShipToAddressID = nil
+		if *body.ShipToAddressUUID != "" {
+			address, addressFound, addressErr := m.Db.ShippingAddresses.GetByUUID(*body.ShipToAddressUUID)
+			if addressErr != nil {
+				m.WriteError(w, r, m.Err.ServerError, addressErr)
+				return
+			}
+			if !addressFound || address.DealershipID != project.DealershipID {
+				m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("shipping address %s not found for this dealership", *body.ShipToAddressUUID))
+				return
+			}
+			project.ShipToAddressID = &address.ID
+		}
+	}
+
 	err = m.Db.Projects.Update(project)
 	if err != nil {
 		m.WriteError(w, r, m.Err.ServerError, err)
@@ -308,6 +387,30 @@ func (m ProjectModule) HandlePatchProject(w http.ResponseWriter, r *http.Request
 	m.WriteJSON(w, r, http.StatusOK, project)
 }
 
+// The required-by date means something to production until the order ships.
+var requiredByEditableStatuses = map[data.ProjectStatus]bool{
+	data.ProjectStatuses.Draft:        true,
+	data.ProjectStatuses.Ordered:      true,
+	data.ProjectStatuses.InProduction: true,
+}
+
+// parseRequiredBy reads a YYYY-MM-DD required-by date; empty means none. A
+// date already gone by is refused, since it could never be met.
+func parseRequiredBy(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	requiredBy, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	if data.PastRequiredBy(&requiredBy, now) {
+		return nil, fmt.Errorf("required-by date %s has already passed", value)
+	}
+	return &requiredBy, nil
+}
+
 // Projects can only be cancelled before manufacturing starts.
 var cancellableStatuses = map[data.ProjectStatus]bool{
 	data.ProjectStatuses.Draft:   true,
@@ -333,13 +436,14 @@ func (m ProjectModule) HandleDeleteProject(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	user := m.ContextGetUser(r)
-	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || *dealershipID != project.DealershipID {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
-			return
-		}
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return
 	}
 
 	if !cancellableStatuses[project.Status] {
@@ -349,7 +453,27 @@ func (m ProjectModule) HandleDeleteProject(w http.ResponseWriter, r *http.Reques
 
 	project.Status = data.ProjectStatuses.Cancelled
 
-	err = m.Db.Projects.Update(project)
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	err = m.Db.Projects.TxUpdate(tx, project)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	// Nothing has been cut yet, so whatever the order reserved goes back.
+	err = m.Db.MaterialReservations.TxReleaseForProject(tx, project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to release reserved materials: %w", err))
+		return
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		m.WriteError(w, r, m.Err.ServerError, err)
 		return
@@ -369,6 +493,17 @@ func inlayIsReady(inlay *data.Inlay) bool {
 	return inlay.ApprovedProofID != nil
 }
 
+// placeOrderResponse is the ordered project, plus the draft that received the
+// inlays left out of the order, if any were.
+type placeOrderResponse struct {
+	*data.Project
+	FollowUpProject *data.Project `json:"follow_up_project,omitempty"`
+}
+
+// HandlePlaceOrder orders the selected inlays. Inlays left out of the order
+// would otherwise be stranded on a project that can no longer be proofed or
+// ordered, so they move, with their tagged chat messages, to a new draft linked
+// back to this project.
 func (m ProjectModule) HandlePlaceOrder(w http.ResponseWriter, r *http.Request) {
 	projectUUID := r.PathValue("uuid")
 
@@ -380,6 +515,8 @@ func (m ProjectModule) HandlePlaceOrder(w http.ResponseWriter, r *http.Request)
 
 	var body struct {
 		InlayUUIDs []string `json:"inlay_uuids" validate:"required,min=1,dive,uuid4"`
+		Rush       bool     `json:"rush"`
+		RequiredBy *string  `json:"required_by" validate:"omitempty,datetime=2006-01-02"`
 	}
 
 	err = m.ReadJSONBody(w, r, &body)
@@ -400,12 +537,14 @@ func (m ProjectModule) HandlePlaceOrder(w http.ResponseWriter, r *http.Request)
 	}
 
 	user := m.ContextGetUser(r)
-	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || *dealershipID != project.DealershipID {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
-			return
-		}
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return
 	}
 
 	if project.Status != data.ProjectStatuses.Draft {
@@ -413,6 +552,15 @@ func (m ProjectModule) HandlePlaceOrder(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if body.RequiredBy != nil {
+		requiredBy, parseErr := parseRequiredBy(*body.RequiredBy, time.Now())
+		if parseErr != nil {
+			m.WriteError(w, r, m.Err.BadRequest, parseErr)
+			return
+		}
+		project.RequiredBy = requiredBy
+	}
+
 	allInlays, err := m.Db.Inlays.GetByProjectID(project.ID)
 	if err != nil {
 		m.WriteError(w, r, m.Err.ServerError, err)
@@ -425,9 +573,12 @@ func (m ProjectModule) HandlePlaceOrder(w http.ResponseWriter, r *http.Request)
 	}
 
 	selected := make([]*data.Inlay, 0, len(allInlays))
+	var unselected []*data.Inlay
 	for _, inlayItem := range allInlays {
 		if selectedUUIDs[inlayItem.UUID] {
 			selected = append(selected, inlayItem)
+		} else {
+			unselected = append(unselected, inlayItem)
 		}
 	}
 
@@ -443,26 +594,101 @@ func (m ProjectModule) HandlePlaceOrder(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	tx, err := m.Db.STDB.Begin()
+	tier, _, err := m.Db.DealershipPriceTiers.GetActiveForDealership(project.DealershipID, time.Now())
 	if err != nil {
 		m.WriteError(w, r, m.Err.ServerError, err)
 		return
 	}
-	defer tx.Rollback()
 
-	orderedStep := string(data.ManufacturingSteps.Ordered)
-	for _, inlayItem := range selected {
-		snapshot, snapshotErr := m.buildOrderSnapshot(project.ID, inlayItem)
+	// An unexpired quote locks the prices it shows, whatever has happened to the
+	// price groups or the dealership's tier since.
+	quote, quoteFound, err := m.Db.ProjectQuotes.GetLatestByProjectID(project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if quoteFound && quote.IsExpired(time.Now()) {
+		quote, quoteFound = nil, false
+	}
+
+	shipTo, err := m.orderShipTo(project)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	// Snapshots and material usage are worked out before the transaction opens,
+	// so fetching designs from S3 never holds it.
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	snapshots := make([]*data.OrderSnapshot, len(selected))
+	usages := make([]*svg.Usage, len(selected))
+	for i, inlayItem := range selected {
+		snapshot, snapshotErr := m.buildOrderSnapshot(project.ID, tier, inlayItem)
 		if snapshotErr != nil {
 			m.WriteError(w, r, m.Err.ServerError, snapshotErr)
 			return
 		}
+		if quoteFound {
+			applyQuote(quote, snapshot)
+		}
+		snapshot.ShipTo = &shipTo
+		snapshots[i] = snapshot
+		usages[i] = m.measureUsage(ctx, inlayItem, snapshot)
+	}
+
+	// One flat kit charge for the whole project, locked here the same way order
+	// snapshots lock inlay prices.
+	kitPriceCents := 0
+	if project.InstallationKit {
+		kitPriceCents = data.InstallationKitPriceCents
+		if quoteFound && quote.InstallationKit {
+			kitPriceCents = quote.InstallationKitPriceCents
+		}
+	}
+
+	// Rush is priced off the inlays being ordered, at the surcharge configured
+	// when the order is placed.
+	rushSurchargeCents := 0
+	if body.Rush {
+		inlaysCents := 0
+		for _, snapshot := range snapshots {
+			inlaysCents += snapshot.PriceCents
+		}
+		rushSurchargeCents = data.ComputeRushSurchargeCents(inlaysCents, m.Cfg.RushSurchargePercent)
+	}
+
+	tax, err := m.orderTax(project.DealershipID, snapshots, kitPriceCents+rushSurchargeCents)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to calculate tax: %w", err))
+		return
+	}
+
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	orderedStep := string(data.ManufacturingSteps.Ordered)
+	var reservedStockIDs []int
+	for i, inlayItem := range selected {
+		snapshot := snapshots[i]
 
 		if snapErr := m.Db.OrderSnapshots.TxInsert(tx, snapshot); snapErr != nil {
 			m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to create order snapshot for inlay %q: %w", inlayItem.Name, snapErr))
 			return
 		}
 
+		stockIDs, reserveErr := m.reserveMaterials(tx, project.ID, inlayItem, usages[i])
+		if reserveErr != nil {
+			m.WriteError(w, r, m.Err.ServerError, reserveErr)
+			return
+		}
+		reservedStockIDs = append(reservedStockIDs, stockIDs...)
+
 		inlayItem.ManufacturingStep = &orderedStep
 		if updateErr := m.Db.Inlays.TxUpdateFields(tx, inlayItem); updateErr != nil {
 			m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to update inlay manufacturing step: %w", updateErr))
@@ -470,19 +696,24 @@ func (m ProjectModule) HandlePlaceOrder(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	var followUp *data.Project
+	if len(unselected) > 0 {
+		followUp, err = m.txSplitOff(tx, project, unselected)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+	}
+
 	now := time.Now()
 	userID := user.GetID()
 	project.Status = data.ProjectStatuses.Ordered
 	project.OrderedAt = &now
 	project.OrderedBy = &userID
-
-	// One flat kit charge for the whole project, locked here the same way order
-	// snapshots lock inlay prices.
-	kitPriceCents := 0
-	if project.InstallationKit {
-		kitPriceCents = data.InstallationKitPriceCents
-	}
 	project.InstallationKitPriceCents = &kitPriceCents
+	project.Rush = body.Rush
+	project.RushSurchargeCents = &rushSurchargeCents
+	setProjectTax(project, tax)
 
 	err = m.Db.Projects.TxUpdate(tx, project)
 	if err != nil {
@@ -502,28 +733,34 @@ func (m ProjectModule) HandlePlaceOrder(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	m.NotifyInternal(
-		project.ID,
-		user,
-		data.NotificationEventTypes.OrderPlaced,
-		fmt.Sprintf("Order placed: %s", project.Name),
-		fmt.Sprintf("A new order has been placed for project %q.", project.Name),
-		nil,
-	)
+	if project.Rush {
+		m.notifyRushOrder(project, user)
+	} else {
+		m.NotifyInternal(
+			project.ID,
+			user,
+			data.NotificationEventTypes.OrderPlaced,
+			fmt.Sprintf("Order placed: %s", project.Name),
+			fmt.Sprintf("A new order has been placed for project %q.", project.Name),
+			nil,
+		)
+	}
+	m.NotifyLowStock(reservedStockIDs)
 
-	m.WriteJSON(w, r, http.StatusOK, project)
+	m.WriteJSON(w, r, http.StatusOK, placeOrderResponse{Project: project, FollowUpProject: followUp})
 }
 
-// shippableStatuses are the statuses from which an internal user may mark a
-// project shipped. Inlays no longer carry shipping steps, so shipping is an
-// explicit internal action rather than an inlay-driven transition.
+// shippableStatuses are the statuses from which an internal user may ship
+// packages. Inlays no longer carry shipping steps, so shipping is an explicit
+// internal action rather than an inlay-driven transition.
 var shippableStatuses = map[data.ProjectStatus]bool{
 	data.ProjectStatuses.Ordered:      true,
 	data.ProjectStatuses.InProduction: true,
 }
 
-// HandleMarkProjectShipped records a shipment: it captures the tracking number
-// and moves the project to "shipped". Internal-only (guarded by
+// HandleMarkProjectShipped is the shortcut for sending everything at once: it
+// packs every inlay not yet shipped into one package with the given tracking
+// number and moves the project to "shipped". Internal-only (guarded by
 // ActionManageShipping middleware). This is not blocked by an unpaid invoice —
 // the "requires payment before shipping" flag is purely a soft signal.
 func (m ProjectModule) HandleMarkProjectShipped(w http.ResponseWriter, r *http.Request) {
@@ -536,7 +773,9 @@ func (m ProjectModule) HandleMarkProjectShipped(w http.ResponseWriter, r *http.R
 	}
 
 	var body struct {
-		TrackingNumber string `json:"tracking_number" validate:"required"`
+		TrackingNumber string                `json:"tracking_number" validate:"required"`
+		Carrier        *data.ShipmentCarrier `json:"carrier" validate:"omitempty,oneof=ups fedex usps dhl other"`
+		WeightLbs      *float64              `json:"weight_lbs" validate:"omitempty,gt=0"`
 	}
 
 	err = m.ReadJSONBody(w, r, &body)
@@ -560,36 +799,57 @@ func (m ProjectModule) HandleMarkProjectShipped(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	project.Status = data.ProjectStatuses.Shipped
-	project.TrackingNumber = &body.TrackingNumber
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	defer tx.Rollback()
 
-	err = m.Db.Projects.Update(project)
+	unshipped, err := m.Db.Shipments.TxGetUnshippedInlayIDs(tx, project.ID)
 	if err != nil {
-		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to mark project shipped: %w", err))
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if len(unshipped) == 0 {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("every ordered inlay in this project has already shipped"))
+		return
+	}
+
+	carrier := data.ShipmentCarriers.Other
+	if body.Carrier != nil {
+		carrier = *body.Carrier
+	}
+
+	shipment := &data.Shipment{
+		ProjectID:      project.ID,
+		Carrier:        carrier,
+		TrackingNumber: &body.TrackingNumber,
+		WeightLbs:      body.WeightLbs,
+		InlayIDs:       unshipped,
+	}
+	progress, err := m.txRecordShipment(tx, project, shipment)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
 		return
 	}
 
 	user := m.ContextGetUser(r)
 	m.AutoWatchProject(project.ID, user)
-
-	m.NotifyDealership(
-		project.ID,
-		user,
-		data.NotificationEventTypes.ProjectShipped,
-		fmt.Sprintf("Project shipped: %s", project.Name),
-		fmt.Sprintf("Your project %q has shipped. Tracking number: %s", project.Name, body.TrackingNumber),
-		nil,
-	)
+	m.notifyShipped(project, user, shipment, progress)
 
 	m.WriteJSON(w, r, http.StatusOK, project)
 }
 
-// HandleMarkProjectDelivered records delivery. Because delivery and billing are
-// now decoupled, the resulting project status depends on payment: if the active
-// invoice is already paid the project goes straight to "completed"; otherwise it
-// moves to "invoiced" (awaiting payment), and paying the invoice later completes
-// it. A project is therefore never surfaced in a distinct "delivered" status.
-// Internal-only (guarded by ActionManageShipping middleware).
+// HandleMarkProjectDelivered is the shortcut for everything arriving at once:
+// it marks every package still in transit delivered and moves the project on
+// (see txCompleteDelivery). Internal-only (guarded by ActionManageShipping
+// middleware).
 func (m ProjectModule) HandleMarkProjectDelivered(w http.ResponseWriter, r *http.Request) {
 	projectUUID := r.PathValue("uuid")
 
@@ -614,61 +874,41 @@ func (m ProjectModule) HandleMarkProjectDelivered(w http.ResponseWriter, r *http
 		return
 	}
 
-	invoicePaid := false
-	if invoice, invFound, invErr := m.Db.Invoices.GetActiveByProjectID(project.ID); invErr == nil && invFound {
-		invoicePaid = invoice.Status == data.InvoiceStatuses.Paid
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
 	}
+	defer tx.Rollback()
 
-	if invoicePaid {
-		project.Status = data.ProjectStatuses.Completed
-	} else {
-		project.Status = data.ProjectStatuses.Invoiced
+	if err := m.Db.Shipments.TxMarkProjectDelivered(tx, project.ID); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to mark shipments delivered: %w", err))
+		return
 	}
 
-	err = m.Db.Projects.Update(project)
+	invoicePaid, err := m.txCompleteDelivery(tx, project)
 	if err != nil {
-		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to mark project delivered: %w", err))
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
 		return
 	}
 
 	user := m.ContextGetUser(r)
 	m.AutoWatchProject(project.ID, user)
-
-	if invoicePaid {
-		m.NotifyDealership(
-			project.ID,
-			user,
-			data.NotificationEventTypes.ProjectDelivered,
-			fmt.Sprintf("Project delivered: %s", project.Name),
-			fmt.Sprintf("Your project %q has been delivered and is complete.", project.Name),
-			nil,
-		)
-	} else {
-		m.NotifyDealership(
-			project.ID,
-			user,
-			data.NotificationEventTypes.ProjectDelivered,
-			fmt.Sprintf("Project delivered: %s", project.Name),
-			fmt.Sprintf("Your project %q has been delivered.", project.Name),
-			nil,
-		)
-		m.NotifyInternal(
-			project.ID,
-			user,
-			data.NotificationEventTypes.ProjectDelivered,
-			fmt.Sprintf("Project delivered: %s", project.Name),
-			fmt.Sprintf("Project %q has been delivered and is awaiting payment.", project.Name),
-			nil,
-		)
-	}
+	m.notifyDelivered(project, user, invoicePaid)
 
 	m.WriteJSON(w, r, http.StatusOK, project)
 }
 
 // buildOrderSnapshot assembles the immutable pricing/dimension record for an
 // inlay at order time. Stock catalog inlays pull from the catalog defaults;
-// approved-proof inlays pull from the proof.
-func (m ProjectModule) buildOrderSnapshot(projectID int, inlay *data.Inlay) (*data.OrderSnapshot, error) {
+// approved-proof inlays pull from the proof. The dealership's price tier, if
+// it has one, discounts the list price.
+func (m ProjectModule) buildOrderSnapshot(projectID int, tier *data.DealershipPriceTier, inlay *data.Inlay) (*data.OrderSnapshot, error) {
 	if inlay.ApprovedProofID != nil {
 		approvedProof, proofFound, err := m.Db.InlayProofs.GetByID(*inlay.ApprovedProofID)
 		if err != nil {
@@ -691,7 +931,7 @@ func (m ProjectModule) buildOrderSnapshot(projectID int, inlay *data.Inlay) (*da
 			}
 		}
 
-		priceCents := data.ComputeAdjustedPriceCents(baseCents, approvedProof.PriceAdjustmentType, approvedProof.PriceAdjustmentValue)
+		price := data.ComputeTieredPrice(tier, priceGroupID, baseCents, approvedProof.PriceAdjustmentType, approvedProof.PriceAdjustmentValue)
 
 		proofID := approvedProof.ID
 		return &data.OrderSnapshot{
@@ -699,7 +939,10 @@ func (m ProjectModule) buildOrderSnapshot(projectID int, inlay *data.Inlay) (*da
 			InlayID:              inlay.ID,
 			ProofID:              &proofID,
 			PriceGroupID:         priceGroupID,
-			PriceCents:           priceCents,
+			PriceCents:           price.NetCents,
+			ListPriceCents:       price.ListCents,
+			TierDiscountCents:    price.DiscountCents,
+			PriceTierID:          tierID(tier),
 			PriceAdjustmentType:  approvedProof.PriceAdjustmentType,
 			PriceAdjustmentValue: approvedProof.PriceAdjustmentValue,
 			Width:                approvedProof.Width,
@@ -728,12 +971,17 @@ func (m ProjectModule) buildOrderSnapshot(projectID int, inlay *data.Inlay) (*da
 		return nil, fmt.Errorf("default price group not found for catalog item %q", catalogItem.Name)
 	}
 
+	price := data.ComputeTieredPrice(tier, catalogItem.DefaultPriceGroupID, priceGroup.BasePriceCents, data.PriceAdjustmentTypes.None, 0)
+
 	return &data.OrderSnapshot{
 		ProjectID:            projectID,
 		InlayID:              inlay.ID,
 		ProofID:              nil,
 		PriceGroupID:         catalogItem.DefaultPriceGroupID,
-		PriceCents:           priceGroup.BasePriceCents,
+		PriceCents:           price.NetCents,
+		ListPriceCents:       price.ListCents,
+		TierDiscountCents:    price.DiscountCents,
+		PriceTierID:          tierID(tier),
 		PriceAdjustmentType:  data.PriceAdjustmentTypes.None,
 		PriceAdjustmentValue: 0,
 		Width:                catalogItem.DefaultWidth,
@@ -741,6 +989,86 @@ func (m ProjectModule) buildOrderSnapshot(projectID int, inlay *data.Inlay) (*da
 	}, nil
 }
 
+// notifyRushOrder tells production a rush order has come in, in place of the
+// usual order notification, so it stands out from the day's orders.
+func (m ProjectModule) notifyRushOrder(project *data.Project, user data.AuthUser) {
+	body := fmt.Sprintf("A rush order has been placed for project %q.", project.Name)
+	if project.RequiredBy != nil {
+		body = fmt.Sprintf("A rush order has been placed for project %q. It must ship by %s.",
+			project.Name, project.RequiredBy.Format("January 2, 2006"))
+	}
+
+	m.NotifyInternal(
+		project.ID,
+		user,
+		data.NotificationEventTypes.RushOrderPlaced,
+		fmt.Sprintf("Rush order placed: %s", project.Name),
+		body,
+		nil,
+	)
+}
+
+// orderTax works out the tax on an order: the inlays being ordered plus the kit
+// and rush charges, at the rate for the dealership's address when the order is
+// placed.
+func (m ProjectModule) orderTax(dealershipID int, snapshots []*data.OrderSnapshot, chargesCents int) (data.Tax, error) {
+	dealership, found, err := m.Db.Dealerships.GetByID(dealershipID)
+	if err != nil {
+		return data.Tax{}, err
+	}
+	if !found {
+		return data.Tax{}, fmt.Errorf("dealership %d not found", dealershipID)
+	}
+
+	taxableCents := chargesCents
+	for _, snapshot := range snapshots {
+		taxableCents += snapshot.PriceCents
+	}
+
+	return m.Tax.Calculate(dealership, taxableCents, time.Now())
+}
+
+// orderShipTo is where an order is going when it is placed: the saved address
+// picked on the draft, or the dealership's own.
+func (m ProjectModule) orderShipTo(project *data.Project) (data.ShipTo, error) {
+	dealership, found, err := m.Db.Dealerships.GetByID(project.DealershipID)
+	if err != nil {
+		return data.ShipTo{}, err
+	}
+	if !found {
+		return data.ShipTo{}, fmt.Errorf("dealership %d not found", project.DealershipID)
+	}
+
+	if project.ShipToAddressID != nil {
+		address, addressFound, addressErr := m.Db.ShippingAddresses.GetByID(*project.ShipToAddressID)
+		if addressErr != nil {
+			return data.ShipTo{}, addressErr
+		}
+		if addressFound {
+			return address.ForDealership(dealership), nil
+		}
+	}
+
+	return data.DealershipShipTo(dealership), nil
+}
+
+func setProjectTax(project *data.Project, tax data.Tax) {
+	project.TaxCents = &tax.Cents
+	project.TaxRatePercent = &tax.RatePercent
+	project.TaxJurisdiction = nil
+	if tax.Jurisdiction != "" {
+		project.TaxJurisdiction = &tax.Jurisdiction
+	}
+	project.TaxExemptCertificate = tax.ExemptCertificate
+}
+
+func tierID(tier *data.DealershipPriceTier) *int {
+	if tier == nil {
+		return nil
+	}
+	return &tier.ID
+}
+
 func normalizeInternalReference(in *string) *string {
 	if in == nil {
 		return nil
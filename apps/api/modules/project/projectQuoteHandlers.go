@@ -0,0 +1,248 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type postQuoteRequest struct {
+	InlayUUIDs []string `json:"inlay_uuids" validate:"omitempty,dive,uuid4"`
+	ValidDays  *int     `json:"valid_days" validate:"omitempty,min=1,max=90"`
+}
+
+func (m ProjectModule) HandleGetProjectQuotes(w http.ResponseWriter, r *http.Request) {
+	project, ok := m.getProjectWithAccessCheck(w, r)
+	if !ok {
+		return
+	}
+
+	quotes, err := m.Db.ProjectQuotes.GetByProjectID(project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to get quotes for project %d: %w", project.ID, err))
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, quotes)
+}
+
+// HandleGetQuote returns one quote. The quote carries every name and figure it
+// shows, so it renders to a PDF as is.
+func (m ProjectModule) HandleGetQuote(w http.ResponseWriter, r *http.Request) {
+	quoteUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(quoteUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	quote, found, err := m.Db.ProjectQuotes.GetByUUID(quoteUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	user := m.ContextGetUser(r)
+	if user.IsDealership() {
+		project, found, err := m.Db.Projects.GetByID(quote.ProjectID)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+		if !found {
+			m.WriteError(w, r, m.Err.Forbidden, nil)
+			return
+		}
+		allowed, err := m.CanAccessDealership(r, project.DealershipID)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+		if !allowed {
+			m.WriteError(w, r, m.Err.Forbidden, nil)
+			return
+		}
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, quote)
+}
+
+// HandlePostProjectQuote quotes a draft project's inlays, all of them unless some
+// are picked. Every quoted inlay must be ready to order, since it is priced
+// exactly as the order snapshot would price it now. A new quote supersedes the
+// project's earlier ones.
+func (m ProjectModule) HandlePostProjectQuote(w http.ResponseWriter, r *http.Request) {
+	var body postQuoteRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	project, ok := m.getProjectWithAccessCheck(w, r)
+	if !ok {
+		return
+	}
+
+	if project.Status != data.ProjectStatuses.Draft {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("only a draft project can be quoted, currently: %s", project.Status))
+		return
+	}
+
+	allInlays, err := m.Db.Inlays.GetByProjectID(project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	inlays := allInlays
+	if len(body.InlayUUIDs) > 0 {
+		selectedUUIDs := make(map[string]bool, len(body.InlayUUIDs))
+		for _, uuid := range body.InlayUUIDs {
+			selectedUUIDs[uuid] = true
+		}
+
+		inlays = make([]*data.Inlay, 0, len(body.InlayUUIDs))
+		for _, inlayItem := range allInlays {
+			if selectedUUIDs[inlayItem.UUID] {
+				inlays = append(inlays, inlayItem)
+			}
+		}
+	}
+
+	if len(inlays) == 0 {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("no inlays to quote"))
+		return
+	}
+
+	dealership, found, err := m.Db.Dealerships.GetByID(project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	tier, _, err := m.Db.DealershipPriceTiers.GetActiveForDealership(project.DealershipID, time.Now())
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	validDays := data.QuoteValidityDays
+	if body.ValidDays != nil {
+		validDays = *body.ValidDays
+	}
+
+	quote := &data.ProjectQuote{
+		ProjectID:                 project.ID,
+		ProjectName:               project.Name,
+		DealershipName:            dealership.Name,
+		InstallationKit:           project.InstallationKit,
+		InstallationKitPriceCents: data.InstallationKitPriceCents,
+		ExpiresAt:                 time.Now().AddDate(0, 0, validDays),
+		Lines:                     make([]data.ProjectQuoteLine, 0, len(inlays)),
+	}
+
+	for _, inlayItem := range inlays {
+		if !inlayIsReady(inlayItem) {
+			m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("inlay %q is not ready to quote", inlayItem.Name))
+			return
+		}
+
+		line, lineErr := m.buildQuoteLine(project.ID, tier, inlayItem)
+		if lineErr != nil {
+			m.WriteError(w, r, m.Err.ServerError, lineErr)
+			return
+		}
+		quote.Lines = append(quote.Lines, *line)
+	}
+
+	user := m.ContextGetUser(r)
+	userID := user.GetID()
+	if user.IsDealership() {
+		quote.CreatedByDealershipUserID = &userID
+	} else {
+		quote.CreatedByInternalUserID = &userID
+	}
+
+	if err := m.Db.ProjectQuotes.Insert(quote); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to save quote: %w", err))
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusCreated, quote)
+}
+
+// buildQuoteLine prices an inlay through buildOrderSnapshot, so a quote and
+// the order placed from it cannot disagree, and adds the names and base price
+// the quote document shows.
+func (m ProjectModule) buildQuoteLine(projectID int, tier *data.DealershipPriceTier, inlay *data.Inlay) (*data.ProjectQuoteLine, error) {
+	snapshot, err := m.buildOrderSnapshot(projectID, tier, inlay)
+	if err != nil {
+		return nil, err
+	}
+
+	inlayID := inlay.ID
+	line := &data.ProjectQuoteLine{
+		InlayID:              &inlayID,
+		InlayName:            inlay.Name,
+		ProofID:              snapshot.ProofID,
+		PriceGroupID:         snapshot.PriceGroupID,
+		PriceAdjustmentType:  snapshot.PriceAdjustmentType,
+		PriceAdjustmentValue: snapshot.PriceAdjustmentValue,
+		PriceCents:           snapshot.PriceCents,
+		TierDiscountCents:    snapshot.TierDiscountCents,
+		PriceTierID:          snapshot.PriceTierID,
+		Width:                snapshot.Width,
+		Height:               snapshot.Height,
+	}
+
+	if snapshot.PriceGroupID != 0 {
+		priceGroup, found, err := m.Db.PriceGroups.GetByID(snapshot.PriceGroupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load price group for inlay %q: %w", inlay.Name, err)
+		}
+		if found {
+			line.PriceGroupName = priceGroup.Name
+			line.BasePriceCents = priceGroup.BasePriceCents
+		}
+	}
+
+	return line, nil
+}
+
+// applyQuote swaps the quoted price into an order snapshot. A line only holds
+// while the inlay is still priced from the same proof it was quoted on; an
+// inlay re-proofed since, or added after quoting, keeps today's price.
+func applyQuote(quote *data.ProjectQuote, snapshot *data.OrderSnapshot) {
+	line, found := quote.LineForInlay(snapshot.InlayID)
+	if !found || !sameID(line.ProofID, snapshot.ProofID) {
+		return
+	}
+
+	snapshot.PriceGroupID = line.PriceGroupID
+	snapshot.PriceCents = line.PriceCents
+	snapshot.ListPriceCents = line.PriceCents + line.TierDiscountCents
+	snapshot.TierDiscountCents = line.TierDiscountCents
+	snapshot.PriceTierID = line.PriceTierID
+	snapshot.PriceAdjustmentType = line.PriceAdjustmentType
+	snapshot.PriceAdjustmentValue = line.PriceAdjustmentValue
+	snapshot.QuoteID = &quote.ID
+}
+
+func sameID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -1 +1,119 @@
 package project
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/modules/upload"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/svg"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+// measureUsage computes the glass and grout an inlay's ordered design uses.
+// Inventory is bookkeeping and never blocks an order, so a design that cannot
+// be fetched or measured is logged and yields nil, as does a non-SVG design.
+func (m ProjectModule) measureUsage(ctx context.Context, inlay *data.Inlay, snapshot *data.OrderSnapshot) *svg.Usage {
+	design, err := upload.GetOrderedDesign(ctx, m.Db, m.S3, m.Cfg, inlay, snapshot)
+	if errors.Is(err, upload.ErrNoDesign) || errors.Is(err, upload.ErrDesignNotSVG) {
+		return nil
+	}
+	if err != nil {
+		m.Log.Error("failed to fetch design for material usage", "error", err, "inlay_id", inlay.ID)
+		return nil
+	}
+
+	usage, err := svg.MeasureUsage(design, snapshot.Width, snapshot.Height)
+	if err != nil {
+		m.Log.Error("failed to measure material usage", "error", err, "inlay_id", inlay.ID)
+		return nil
+	}
+	return &usage
+}
+
+// reserveMaterials reserves an inlay's usage against stock, returning the ids
+// of the stock rows it reserved from. Materials nobody counts (no stock row)
+// are skipped.
+func (m ProjectModule) reserveMaterials(tx *sql.Tx, projectID int, inlay *data.Inlay, usage *svg.Usage) ([]int, error) {
+	if usage == nil {
+		return nil, nil
+	}
+
+	var stockIDs []int
+	reserve := func(stock *data.MaterialStock, area float64) error {
+		reservation := &data.MaterialReservation{
+			MaterialStockID: stock.ID,
+			ProjectID:       projectID,
+			InlayID:         inlay.ID,
+			Quantity:        stock.FromSquareInches(area),
+		}
+		if err := m.Db.MaterialReservations.TxReserve(tx, reservation); err != nil {
+			return fmt.Errorf("failed to reserve materials for inlay %q: %w", inlay.Name, err)
+		}
+		stockIDs = append(stockIDs, stock.ID)
+		return nil
+	}
+
+	for glassColorID, area := range usage.GlassByColor {
+		stock, found, err := m.Db.MaterialStocks.GetByGlassColorID(glassColorID)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		if err := reserve(stock, area); err != nil {
+			return nil, err
+		}
+	}
+
+	if usage.GroutID != nil && usage.GroutArea > 0 {
+		stock, found, err := m.Db.MaterialStocks.GetByGroutID(*usage.GroutID)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			if err := reserve(stock, usage.GroutArea); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return stockIDs, nil
+}
+
+// txSplitOff moves inlays into a new draft project linked to project. The draft
+// inherits the project's reference and watchers; messages tagged with a moved
+// inlay go with it. It never has an installation kit, which the original order
+// already covers.
+func (m ProjectModule) txSplitOff(tx *sql.Tx, project *data.Project, inlays []*data.Inlay) (*data.Project, error) {
+	followUp := &data.Project{
+		DealershipID:      project.DealershipID,
+		Name:              fmt.Sprintf("%s (continued)", project.Name),
+		InternalReference: project.InternalReference,
+		Status:            data.ProjectStatuses.Draft,
+		ParentProjectID:   &project.ID,
+		ShipToAddressID:   project.ShipToAddressID,
+	}
+	if err := m.Db.Projects.TxInsert(tx, followUp); err != nil {
+		return nil, fmt.Errorf("failed to create follow-up project: %w", err)
+	}
+
+	inlayIDs := make([]int, len(inlays))
+	for i, inlay := range inlays {
+		inlayIDs[i] = inlay.ID
+	}
+
+	if err := m.Db.Inlays.TxMoveToProject(tx, inlayIDs, followUp.ID); err != nil {
+		return nil, fmt.Errorf("failed to move inlays to follow-up project: %w", err)
+	}
+	if err := m.Db.ProjectChats.TxMoveInlayMessages(tx, project.ID, followUp.ID, inlayIDs); err != nil {
+		return nil, fmt.Errorf("failed to move chat messages to follow-up project: %w", err)
+	}
+	if err := m.Db.ProjectWatchers.TxCopy(tx, project.ID, followUp.ID); err != nil {
+		return nil, fmt.Errorf("failed to copy watchers to follow-up project: %w", err)
+	}
+
+	return followUp, nil
+}
@@ -0,0 +1,479 @@
+package project
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type postShipmentRequest struct {
+	Carrier        data.ShipmentCarrier `json:"carrier" validate:"required,oneof=ups fedex usps dhl other"`
+	TrackingNumber *string              `json:"tracking_number" validate:"omitempty,min=1"`
+	WeightLbs      *float64             `json:"weight_lbs" validate:"omitempty,gt=0"`
+	InlayUUIDs     []string             `json:"inlay_uuids" validate:"required,min=1,dive,uuid4"`
+}
+
+type postShipmentResponse struct {
+	Shipment *data.Shipment `json:"shipment"`
+	Project  *data.Project  `json:"project"`
+}
+
+func (m ProjectModule) HandleGetProjectShipments(w http.ResponseWriter, r *http.Request) {
+	project, ok := m.getProjectWithAccessCheck(w, r)
+	if !ok {
+		return
+	}
+
+	shipments, err := m.Db.Shipments.GetByProjectID(project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to get shipments for project %d: %w", project.ID, err))
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, shipments)
+}
+
+// HandlePostShipment records one package holding some of a project's inlays.
+// The project moves to shipped when the package holds the last unshipped inlay.
+// Internal-only (guarded by ActionManageShipping middleware).
+func (m ProjectModule) HandlePostShipment(w http.ResponseWriter, r *http.Request) {
+	projectUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(projectUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	var body postShipmentRequest
+	err = m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	project, found, err := m.Db.Projects.GetByUUID(projectUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	if !shippableStatuses[project.Status] {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("cannot ship a project in %s status", project.Status))
+		return
+	}
+
+	inlayIDs := make([]int, 0, len(body.InlayUUIDs))
+	inPackage := make(map[int]bool, len(body.InlayUUIDs))
+	for _, inlayUUID := range body.InlayUUIDs {
+		inlay, found, err := m.Db.Inlays.GetByUUID(inlayUUID)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+		if !found || inlay.ProjectID != project.ID {
+			m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("every inlay in a shipment must belong to this project"))
+			return
+		}
+		if inPackage[inlay.ID] {
+			m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("inlay %s is listed more than once", inlayUUID))
+			return
+		}
+		inPackage[inlay.ID] = true
+		inlayIDs = append(inlayIDs, inlay.ID)
+	}
+
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	unshipped, err := m.Db.Shipments.TxGetUnshippedInlayIDs(tx, project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	unshippedSet := make(map[int]bool, len(unshipped))
+	for _, id := range unshipped {
+		unshippedSet[id] = true
+	}
+	for _, id := range inlayIDs {
+		if !unshippedSet[id] {
+			m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("an inlay in this shipment has already shipped"))
+			return
+		}
+	}
+
+	trackingNumber := body.TrackingNumber
+	if trackingNumber != nil {
+		trimmed := strings.TrimSpace(*trackingNumber)
+		trackingNumber = &trimmed
+	}
+
+	shipment := &data.Shipment{
+		ProjectID:      project.ID,
+		Carrier:        body.Carrier,
+		TrackingNumber: trackingNumber,
+		WeightLbs:      body.WeightLbs,
+		InlayIDs:       inlayIDs,
+	}
+	progress, err := m.txRecordShipment(tx, project, shipment)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	user := m.ContextGetUser(r)
+	m.AutoWatchProject(project.ID, user)
+	m.notifyShipped(project, user, shipment, progress)
+
+	m.WriteJSON(w, r, http.StatusCreated, postShipmentResponse{Shipment: shipment, Project: project})
+}
+
+// HandleDeliverShipment marks one package delivered. The project moves on from
+// shipped once every package has arrived. Internal-only (guarded by
+// ActionManageShipping middleware).
+func (m ProjectModule) HandleDeliverShipment(w http.ResponseWriter, r *http.Request) {
+	shipmentUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(shipmentUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	shipment, found, err := m.Db.Shipments.GetByUUID(shipmentUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	if shipment.DeliveredAt != nil {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("shipment was already delivered"))
+		return
+	}
+
+	project, found, err := m.Db.Projects.GetByID(shipment.ProjectID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	if err := m.Db.Shipments.TxMarkDelivered(tx, shipment); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to mark shipment delivered: %w", err))
+		return
+	}
+
+	// A remake's package closes out the remake; the project itself was settled
+	// with the original order and keeps its status.
+	var remake *data.InlayRemake
+	if shipment.RemakeID != nil {
+		remake, found, err = m.Db.InlayRemakes.GetByID(*shipment.RemakeID)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+		if !found {
+			m.WriteError(w, r, m.Err.RecordNotFound, nil)
+			return
+		}
+
+		remake.Status = data.RemakeStatuses.Delivered
+		if err := m.Db.InlayRemakes.TxUpdate(tx, remake); err != nil {
+			m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to mark remake delivered: %w", err))
+			return
+		}
+	}
+
+	progress, err := m.Db.Shipments.TxProgress(tx, project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	delivered := remake == nil && project.Status == data.ProjectStatuses.Shipped && progress.AllDelivered()
+	invoicePaid := false
+	if delivered {
+		invoicePaid, err = m.txCompleteDelivery(tx, project)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	user := m.ContextGetUser(r)
+	m.AutoWatchProject(project.ID, user)
+	if delivered {
+		m.notifyDelivered(project, user, invoicePaid)
+	}
+	if remake != nil {
+		m.NotifyDealership(
+			project.ID,
+			user,
+			data.NotificationEventTypes.ProjectDelivered,
+			fmt.Sprintf("Remake delivered: %s", project.Name),
+			fmt.Sprintf("A remade inlay from your project %q has been delivered.", project.Name),
+			&remake.InlayID,
+		)
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, postShipmentResponse{Shipment: shipment, Project: project})
+}
+
+// txRecordShipment inserts a package and moves the project to shipped once no
+// inlay is left to pack. The package is addressed to wherever its inlays were
+// ordered to. The project's tracking number follows the latest package that
+// has one.
+func (m ProjectModule) txRecordShipment(tx *sql.Tx, project *data.Project, shipment *data.Shipment) (data.ShipmentProgress, error) {
+	if len(shipment.InlayIDs) > 0 {
+		shipTo, err := m.Db.OrderSnapshots.TxGetShipTo(tx, shipment.InlayIDs[0])
+		if err != nil {
+			return data.ShipmentProgress{}, err
+		}
+		shipment.ShipTo = &shipTo
+	}
+
+	if err := m.Db.Shipments.TxInsert(tx, shipment); err != nil {
+		return data.ShipmentProgress{}, fmt.Errorf("failed to record shipment: %w", err)
+	}
+
+	progress, err := m.Db.Shipments.TxProgress(tx, project.ID)
+	if err != nil {
+		return data.ShipmentProgress{}, err
+	}
+
+	if shipment.TrackingNumber != nil {
+		project.TrackingNumber = shipment.TrackingNumber
+	}
+	if progress.AllShipped() {
+		project.Status = data.ProjectStatuses.Shipped
+	}
+
+	if err := m.Db.Projects.TxUpdate(tx, project); err != nil {
+		return data.ShipmentProgress{}, fmt.Errorf("failed to mark project shipped: %w", err)
+	}
+	return progress, nil
+}
+
+// txCompleteDelivery moves a project whose packages have all arrived on from
+// shipped: to completed if its active invoice is already paid, otherwise to
+// invoiced to await payment. A project is never left in a distinct
+// "delivered" status.
+func (m ProjectModule) txCompleteDelivery(tx *sql.Tx, project *data.Project) (bool, error) {
+	invoice, found, err := m.Db.Invoices.GetActiveByProjectID(project.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get invoice for project %d: %w", project.ID, err)
+	}
+	invoicePaid := found && invoice.Status == data.InvoiceStatuses.Paid
+
+	if invoicePaid {
+		project.Status = data.ProjectStatuses.Completed
+	} else {
+		project.Status = data.ProjectStatuses.Invoiced
+	}
+
+	err = m.Db.Projects.TxUpdate(tx, project)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark project delivered: %w", err)
+	}
+	return invoicePaid, nil
+}
+
+func (m ProjectModule) notifyShipped(project *data.Project, user data.AuthUser, shipment *data.Shipment, progress data.ShipmentProgress) {
+	tracking := ""
+	if shipment.TrackingNumber != nil {
+		tracking = fmt.Sprintf(" Tracking number: %s", *shipment.TrackingNumber)
+		if shipment.TrackingURL != nil {
+			tracking += fmt.Sprintf(" (%s)", *shipment.TrackingURL)
+		}
+	}
+
+	if progress.AllShipped() {
+		m.NotifyDealership(
+			project.ID,
+			user,
+			data.NotificationEventTypes.ProjectShipped,
+			fmt.Sprintf("Project shipped: %s", project.Name),
+			fmt.Sprintf("Your project %q has shipped.%s", project.Name, tracking),
+			nil,
+		)
+		return
+	}
+
+	m.NotifyDealership(
+		project.ID,
+		user,
+		data.NotificationEventTypes.ProjectShipped,
+		fmt.Sprintf("Package shipped: %s", project.Name),
+		fmt.Sprintf("A package from your project %q has shipped, %d of %d inlays so far.%s", project.Name, progress.Shipped, progress.Inlays, tracking),
+		nil,
+	)
+}
+
+func (m ProjectModule) notifyDelivered(project *data.Project, user data.AuthUser, invoicePaid bool) {
+	if invoicePaid {
+		m.NotifyDealership(
+			project.ID,
+			user,
+			data.NotificationEventTypes.ProjectDelivered,
+			fmt.Sprintf("Project delivered: %s", project.Name),
+			fmt.Sprintf("Your project %q has been delivered and is complete.", project.Name),
+			nil,
+		)
+		return
+	}
+
+	m.NotifyDealership(
+		project.ID,
+		user,
+		data.NotificationEventTypes.ProjectDelivered,
+		fmt.Sprintf("Project delivered: %s", project.Name),
+		fmt.Sprintf("Your project %q has been delivered.", project.Name),
+		nil,
+	)
+	m.NotifyInternal(
+		project.ID,
+		user,
+		data.NotificationEventTypes.ProjectDelivered,
+		fmt.Sprintf("Project delivered: %s", project.Name),
+		fmt.Sprintf("Project %q has been delivered and is awaiting payment.", project.Name),
+		nil,
+	)
+}
+
+// PackingSlipLine is one inlay in the package.
+type PackingSlipLine struct {
+	InlayUUID string         `json:"inlay_uuid"`
+	Name      string         `json:"name"`
+	Type      data.InlayType `json:"type"`
+}
+
+// PackingSlip is what goes in the box: who it is for, where it is going and
+// what is in it.
+type PackingSlip struct {
+	ShipmentUUID      string               `json:"shipment_uuid"`
+	ProjectName       string               `json:"project_name"`
+	InternalReference *string              `json:"internal_reference"`
+	DealershipName    string               `json:"dealership_name"`
+	ShipTo            data.ShipTo          `json:"ship_to"`
+	Carrier           data.ShipmentCarrier `json:"carrier"`
+	TrackingNumber    *string              `json:"tracking_number"`
+	ShippedAt         time.Time            `json:"shipped_at"`
+	Remake            bool                 `json:"remake"`
+	Lines             []PackingSlipLine    `json:"lines"`
+}
+
+// HandleGetPackingSlip lays out the packing slip for a package, addressed to
+// the ship-to recorded on it. Internal-only (guarded by ActionManageShipping
+// middleware).
+func (m ProjectModule) HandleGetPackingSlip(w http.ResponseWriter, r *http.Request) {
+	shipmentUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(shipmentUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	shipment, found, err := m.Db.Shipments.GetByUUID(shipmentUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	project, found, err := m.Db.Projects.GetByID(shipment.ProjectID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	dealership, found, err := m.Db.Dealerships.GetByID(project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	inlays, err := m.Db.Inlays.GetByProjectID(project.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	packed := make(map[int]bool, len(shipment.InlayIDs))
+	for _, inlayID := range shipment.InlayIDs {
+		packed[inlayID] = true
+	}
+
+	slip := PackingSlip{
+		ShipmentUUID:      shipment.UUID,
+		ProjectName:       project.Name,
+		InternalReference: project.InternalReference,
+		DealershipName:    dealership.Name,
+		ShipTo:            data.DealershipShipTo(dealership),
+		Carrier:           shipment.Carrier,
+		TrackingNumber:    shipment.TrackingNumber,
+		ShippedAt:         shipment.ShippedAt,
+		Remake:            shipment.RemakeID != nil,
+		Lines:             []PackingSlipLine{},
+	}
+	if shipment.ShipTo != nil {
+		slip.ShipTo = *shipment.ShipTo
+	}
+	for _, inlay := range inlays {
+		if packed[inlay.ID] {
+			slip.Lines = append(slip.Lines, PackingSlipLine{
+				InlayUUID: inlay.UUID,
+				Name:      inlay.Name,
+				Type:      inlay.Type,
+			})
+		}
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, slip)
+}
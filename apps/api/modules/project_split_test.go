@@ -0,0 +1,156 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceOrder_PartialOrderSplitsOffDraft(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, _ := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-SPL-0001")
+
+	project := &data.Project{
+		Name:            "Lobby Doors",
+		Status:          data.ProjectStatuses.Draft,
+		DealershipID:    dealershipUser.DealershipID,
+		InstallationKit: true,
+	}
+	require.NoError(t, ctx.db.Projects.Insert(project))
+
+	ordered := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Left Door")
+	leftOut := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Right Door")
+
+	taggedChat := &data.ProjectChat{
+		ProjectID:        project.ID,
+		InlayID:          &leftOut.ID,
+		DealershipUserID: &dealershipUser.ID,
+		MessageType:      data.ChatMessageTypes.Text,
+		Message:          "Can the right door be a little wider?",
+	}
+	require.NoError(t, ctx.db.ProjectChats.Insert(taggedChat))
+	untaggedChat := &data.ProjectChat{
+		ProjectID:        project.ID,
+		DealershipUserID: &dealershipUser.ID,
+		MessageType:      data.ChatMessageTypes.Text,
+		Message:          "Thanks for the quick turnaround.",
+	}
+	require.NoError(t, ctx.db.ProjectChats.Insert(untaggedChat))
+	require.NoError(t, ctx.db.ProjectWatchers.AutoSubscribe(project.ID, dealershipUser))
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{ordered.UUID}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var body struct {
+		Status          data.ProjectStatus `json:"status"`
+		FollowUpProject *data.Project      `json:"follow_up_project"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &body))
+	assert.Equal(t, data.ProjectStatuses.Ordered, body.Status)
+	require.NotNil(t, body.FollowUpProject)
+
+	followUp, found, err := ctx.db.Projects.GetByUUID(body.FollowUpProject.UUID)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, data.ProjectStatuses.Draft, followUp.Status)
+	require.NotNil(t, followUp.ParentProjectID)
+	assert.Equal(t, project.ID, *followUp.ParentProjectID)
+	assert.False(t, followUp.InstallationKit, "the original order already has the kit")
+
+	movedInlay, _, err := ctx.db.Inlays.GetByUUID(leftOut.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, followUp.ID, movedInlay.ProjectID)
+	assert.Nil(t, movedInlay.ManufacturingStep, "the left-out inlay is still a draft")
+
+	stayedInlay, _, err := ctx.db.Inlays.GetByUUID(ordered.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, project.ID, stayedInlay.ProjectID)
+
+	movedChat, _, err := ctx.db.ProjectChats.GetByID(taggedChat.ID)
+	require.NoError(t, err)
+	assert.Equal(t, followUp.ID, movedChat.ProjectID, "messages about a moved inlay follow it")
+	stayedChat, _, err := ctx.db.ProjectChats.GetByID(untaggedChat.ID)
+	require.NoError(t, err)
+	assert.Equal(t, project.ID, stayedChat.ProjectID)
+
+	watching, err := ctx.db.ProjectWatchers.IsWatching(followUp.ID, dealershipUser)
+	require.NoError(t, err)
+	assert.True(t, watching, "watchers carry over to the follow-up project")
+
+	detailResp := ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/api/project/%s", project.UUID),
+		token:  dealershipToken,
+	})
+	require.Equal(t, http.StatusOK, detailResp.statusCode, string(detailResp.body))
+	var detail struct {
+		ChildProjects []struct {
+			UUID string `json:"uuid"`
+		} `json:"child_projects"`
+	}
+	require.NoError(t, json.Unmarshal(detailResp.body, &detail))
+	require.Len(t, detail.ChildProjects, 1)
+	assert.Equal(t, followUp.UUID, detail.ChildProjects[0].UUID)
+
+	childResp := ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/api/project/%s", followUp.UUID),
+		token:  dealershipToken,
+	})
+	require.Equal(t, http.StatusOK, childResp.statusCode, string(childResp.body))
+	var childDetail struct {
+		ParentProject *struct {
+			UUID string `json:"uuid"`
+		} `json:"parent_project"`
+	}
+	require.NoError(t, json.Unmarshal(childResp.body, &childDetail))
+	require.NotNil(t, childDetail.ParentProject)
+	assert.Equal(t, project.UUID, childDetail.ParentProject.UUID)
+}
+
+func TestPlaceOrder_FullOrderDoesNotSplit(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, _ := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-SPL-0002")
+
+	project := &data.Project{
+		Name:         "Single Door",
+		Status:       data.ProjectStatuses.Draft,
+		DealershipID: dealershipUser.DealershipID,
+	}
+	require.NoError(t, ctx.db.Projects.Insert(project))
+	inlay := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Door")
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{inlay.UUID}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(resp.body, &body))
+	assert.NotContains(t, body, "follow_up_project")
+
+	children, err := ctx.db.Projects.GetChildren(project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, children)
+}
@@ -48,13 +48,14 @@ func (m ProofModule) getInlayWithAccessCheck(w http.ResponseWriter, r *http.Requ
 		return nil, nil, false
 	}
 
-	user := m.ContextGetUser(r)
-	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || *dealershipID != project.DealershipID {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
-			return nil, nil, false
-		}
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, nil, false
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return nil, nil, false
 	}
 
 	return inlay, project, true
@@ -117,13 +118,14 @@ func (m ProofModule) HandleGetProof(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user := m.ContextGetUser(r)
-	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || *dealershipID != project.DealershipID {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
-			return
-		}
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return
 	}
 
 	m.WriteJSON(w, r, http.StatusOK, proof)
@@ -312,13 +314,14 @@ func (m ProofModule) loadProofWithContext(w http.ResponseWriter, r *http.Request
 		return nil, nil, nil, false
 	}
 
-	user := m.ContextGetUser(r)
-	if user.IsDealership() {
-		dealershipID := user.GetDealershipID()
-		if dealershipID == nil || *dealershipID != project.DealershipID {
-			m.WriteError(w, r, m.Err.Forbidden, nil)
-			return nil, nil, nil, false
-		}
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, nil, nil, false
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return nil, nil, nil, false
 	}
 
 	return proof, inlay, project, true
@@ -329,14 +332,20 @@ func (m ProofModule) loadProofWithContext(w http.ResponseWriter, r *http.Request
 func (m ProofModule) authorizeProofAction(w http.ResponseWriter, r *http.Request, proof *data.InlayProof) bool {
 	user := m.ContextGetUser(r)
 
+	permissions, err := m.ContextGetPermissions(r)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return false
+	}
+
 	switch proof.ApprovalAuthority {
 	case data.ProofApprovalAuthorities.Internal:
-		if !user.IsInternal() || !user.Can(data.ActionInternalApproveProof) {
+		if !user.IsInternal() || !permissions.Has(data.ActionInternalApproveProof) {
 			m.WriteError(w, r, m.Err.Forbidden, nil)
 			return false
 		}
 	default: // dealership
-		if !user.Can(data.ActionApproveProof) {
+		if !permissions.Has(data.ActionApproveProof) {
 			m.WriteError(w, r, m.Err.Forbidden, nil)
 			return false
 		}
@@ -0,0 +1,151 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotes_PlaceOrderHonorsUnexpiredQuote(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, _ := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-QTE-0001")
+
+	project := seedDraftProject(t, ctx, dealershipUser.DealershipID, "Quoted Project")
+	inlay := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Dove")
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/quotes", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"valid_days": 14},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var quote data.ProjectQuote
+	require.NoError(t, json.Unmarshal(resp.body, &quote))
+	assert.Equal(t, 1, quote.QuoteNumber)
+	assert.Equal(t, 10000, quote.TotalCents)
+	require.Len(t, quote.Lines, 1)
+	assert.Equal(t, "Standard", quote.Lines[0].PriceGroupName)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 14), quote.ExpiresAt, time.Minute)
+
+	priceGroup.BasePriceCents = 15000
+	require.NoError(t, ctx.db.PriceGroups.Update(priceGroup))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{inlay.UUID}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	snapshot, found, err := ctx.db.OrderSnapshots.GetByInlayID(inlay.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, 10000, snapshot.PriceCents, "the quoted price holds after the price group changed")
+	require.NotNil(t, snapshot.QuoteID)
+	assert.Equal(t, quote.ID, *snapshot.QuoteID)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/quotes", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "an ordered project cannot be quoted")
+}
+
+func TestQuotes_ExpiredQuoteIsIgnored(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, _ := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-QTE-0002")
+
+	project := seedDraftProject(t, ctx, dealershipUser.DealershipID, "Stale Quote Project")
+	inlay := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Dove")
+
+	inlayID := inlay.ID
+	require.NoError(t, ctx.db.ProjectQuotes.Insert(&data.ProjectQuote{
+		ProjectID:      project.ID,
+		ProjectName:    project.Name,
+		DealershipName: "Test Dealership",
+		ExpiresAt:      time.Now().Add(-time.Hour),
+		Lines: []data.ProjectQuoteLine{{
+			InlayID:        &inlayID,
+			InlayName:      inlay.Name,
+			PriceGroupID:   priceGroup.ID,
+			PriceGroupName: priceGroup.Name,
+			BasePriceCents: 5000,
+			PriceCents:     5000,
+			Width:          12,
+			Height:         8,
+		}},
+	}))
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{inlay.UUID}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	snapshot, found, err := ctx.db.OrderSnapshots.GetByInlayID(inlay.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, priceGroup.BasePriceCents, snapshot.PriceCents)
+	assert.Nil(t, snapshot.QuoteID)
+}
+
+func TestQuotes_OtherDealershipForbidden(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, dealershipToken, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-QTE-0003")
+
+	other := &data.Dealership{
+		Name:                "Other Dealership",
+		PaymentTiming:       data.PaymentTimings.PostShipping,
+		SandblastFileFormat: data.SandblastFileFormats.PDF,
+		Address: data.Address{
+			Street: "456 Side St", City: "Other City", State: "OS",
+			PostalCode: "67890", Country: "US", Latitude: 41.0, Longitude: -73.0,
+		},
+	}
+	require.NoError(t, ctx.db.Dealerships.Insert(other))
+	project := seedDraftProject(t, ctx, other.ID, "Someone Else's Project")
+	seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Dove")
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/quotes", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var quote data.ProjectQuote
+	require.NoError(t, json.Unmarshal(resp.body, &quote))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/api/quote/%s", quote.UUID),
+		token:  dealershipToken,
+	})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode)
+}
@@ -0,0 +1,94 @@
+package modules
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"testing"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMagicLink_UniformAndRateLimited(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, _, _ := seedTestData(t, ctx)
+
+	for range 3 {
+		resp := ctx.request(testRequest{
+			method: http.MethodPost,
+			path:   "/api/auth/magic-link",
+			body:   map[string]string{"email": "nobody@example.com"},
+		})
+		assert.Equal(t, http.StatusNoContent, resp.statusCode, "unknown addresses get the same answer")
+	}
+
+	w := cookieRequest(t, ctx, http.MethodPost, "/api/auth/magic-link", "", map[string]string{"email": "NOBODY@example.com"})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "an address is limited however it is capitalised")
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/auth/magic-link",
+		body:   map[string]string{"email": dealershipUser.Email},
+	})
+	assert.Equal(t, http.StatusNoContent, resp.statusCode, "other addresses are unaffected")
+}
+
+func TestMagicLink_RateLimitedByForwardedClient(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	magicLink := func(i int, forwardedFor string) int {
+		return ctx.request(testRequest{
+			method:  http.MethodPost,
+			path:    "/api/auth/magic-link",
+			body:    map[string]string{"email": fmt.Sprintf("nobody%d@example.com", i)},
+			headers: map[string]string{"X-Forwarded-For": forwardedFor},
+		}).statusCode
+	}
+
+	for i := range 10 {
+		assert.Equal(t, http.StatusNoContent, magicLink(i, fmt.Sprintf("203.0.113.%d", i)))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, magicLink(10, "203.0.113.10"),
+		"an untrusted peer cannot dodge the limit with a forged header")
+
+	// httptest requests come from 192.0.2.1.
+	ctx.app.Cfg.TrustedProxies = []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}
+
+	for i := range 10 {
+		assert.Equal(t, http.StatusNoContent, magicLink(20+i, "198.51.100.7, 203.0.113.50"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, magicLink(30, "198.51.100.8, 203.0.113.50"),
+		"behind a trusted proxy the client is the right-most untrusted hop")
+	assert.Equal(t, http.StatusNoContent, magicLink(31, "203.0.113.51, 192.0.2.9"),
+		"other clients behind the proxy are unaffected")
+}
+
+func TestMagicLink_SingleUse(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, _, _ := seedTestData(t, ctx)
+
+	older, err := ctx.db.DealershipTokens.New(dealershipUser.ID, time.Hour, data.DealershipScopeLogin)
+	require.NoError(t, err)
+	latest, err := ctx.db.DealershipTokens.New(dealershipUser.ID, time.Hour, data.DealershipScopeLogin)
+	require.NoError(t, err)
+
+	callback := "/api/auth/magic-link/callback?token="
+
+	resp := ctx.request(testRequest{method: http.MethodGet, path: callback + latest.Plaintext})
+	require.Equal(t, http.StatusFound, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: callback + latest.Plaintext})
+	assert.Equal(t, http.StatusUnauthorized, resp.statusCode, "a link works once")
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: callback + older.Plaintext})
+	assert.Equal(t, http.StatusUnauthorized, resp.statusCode, "and voids the links sent before it")
+}
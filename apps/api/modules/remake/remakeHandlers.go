@@ -0,0 +1,496 @@
+package remake
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type RemakeModule struct {
+	*app.Application
+}
+
+func NewRemakeModule(app *app.Application) *RemakeModule {
+	return &RemakeModule{app}
+}
+
+type postRemakeRequest struct {
+	Reason    string   `json:"reason" validate:"required"`
+	PhotoURLs []string `json:"photo_urls" validate:"omitempty,dive,required"`
+}
+
+type approveRemakeRequest struct {
+	Billing data.RemakeBilling `json:"billing" validate:"required,oneof=free charged"`
+}
+
+type declineRemakeRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type shipRemakeRequest struct {
+	Carrier        data.ShipmentCarrier `json:"carrier" validate:"required,oneof=ups fedex usps dhl other"`
+	TrackingNumber *string              `json:"tracking_number" validate:"omitempty,min=1"`
+	WeightLbs      *float64             `json:"weight_lbs" validate:"omitempty,gt=0"`
+}
+
+// getInlayWithAccessCheck loads the inlay named in the path along with its
+// project, refusing dealership users outside the owning dealership.
+func (m *RemakeModule) getInlayWithAccessCheck(w http.ResponseWriter, r *http.Request) (*data.Inlay, *data.Project, bool) {
+	inlayUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(inlayUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, nil, false
+	}
+
+	inlay, found, err := m.Db.Inlays.GetByUUID(inlayUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, nil, false
+	}
+
+	project, found, err := m.Db.Projects.GetByID(inlay.ProjectID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, nil, false
+	}
+
+	allowed, err := m.CanAccessDealership(r, project.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, nil, false
+	}
+	if !allowed {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return nil, nil, false
+	}
+
+	return inlay, project, true
+}
+
+// getRemake loads the remake named in the path along with its inlay.
+func (m *RemakeModule) getRemake(w http.ResponseWriter, r *http.Request) (*data.InlayRemake, *data.Inlay, bool) {
+	remakeUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(remakeUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, nil, false
+	}
+
+	remake, found, err := m.Db.InlayRemakes.GetByUUID(remakeUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, nil, false
+	}
+
+	inlay, found, err := m.Db.Inlays.GetByID(remake.InlayID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, nil, false
+	}
+
+	return remake, inlay, true
+}
+
+func (m *RemakeModule) HandleGetInlayRemakes(w http.ResponseWriter, r *http.Request) {
+	inlay, _, ok := m.getInlayWithAccessCheck(w, r)
+	if !ok {
+		return
+	}
+
+	remakes, err := m.Db.InlayRemakes.GetByInlayID(inlay.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, remakes)
+}
+
+// HandleGetRemakes lists remakes in one status for internal staff, awaiting
+// review by default.
+func (m *RemakeModule) HandleGetRemakes(w http.ResponseWriter, r *http.Request) {
+	status := data.RemakeStatus(r.URL.Query().Get("status"))
+	if status == "" {
+		status = data.RemakeStatuses.Requested
+	}
+
+	err := m.Validate.Var(string(status), "oneof=requested approved declined shipped delivered")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	remakes, err := m.Db.InlayRemakes.GetByStatus(status)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, remakes)
+}
+
+// HandlePostInlayRemake opens a remake for an inlay that arrived broken or
+// defective. The inlay's package must have been delivered, and an inlay has at
+// most one remake open at a time.
+func (m *RemakeModule) HandlePostInlayRemake(w http.ResponseWriter, r *http.Request) {
+	var body postRemakeRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	inlay, project, ok := m.getInlayWithAccessCheck(w, r)
+	if !ok {
+		return
+	}
+
+	delivered, err := m.Db.Shipments.IsInlayDelivered(inlay.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !delivered {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("only a delivered inlay can be remade"))
+		return
+	}
+
+	_, open, err := m.Db.InlayRemakes.GetOpenByInlayID(inlay.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if open {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("inlay already has an open remake"))
+		return
+	}
+
+	user := m.ContextGetUser(r)
+	userID := user.GetID()
+
+	remake := &data.InlayRemake{
+		InlayID: inlay.ID,
+		Reason:  strings.TrimSpace(body.Reason),
+	}
+	if user.IsDealership() {
+		remake.RequestedByDealershipUserID = &userID
+	} else {
+		remake.RequestedByInternalUserID = &userID
+	}
+
+	if err := m.Db.InlayRemakes.Insert(remake, body.PhotoURLs); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to open remake: %w", err))
+		return
+	}
+
+	m.AutoWatchProject(project.ID, user)
+	m.NotifyInternal(
+		project.ID,
+		user,
+		data.NotificationEventTypes.RemakeRequested,
+		fmt.Sprintf("Remake requested: %s", inlay.Name),
+		fmt.Sprintf("A remake of inlay %q on project %q was requested: %s", inlay.Name, project.Name, remake.Reason),
+		&inlay.ID,
+	)
+
+	m.WriteJSON(w, r, http.StatusCreated, remake)
+}
+
+// HandleApproveRemake accepts a remake as free or charged and sends the inlay
+// back to the start of the manufacturing ladder in a new cycle. The remake gets
+// its own order snapshot, copied from the original order's with the price
+// zeroed for a free remake; the original snapshot is left as it was. A charged
+// remake is billed on the project's next invoice, so it is refused while the
+// dealership holds a sent or paid one.
+func (m *RemakeModule) HandleApproveRemake(w http.ResponseWriter, r *http.Request) {
+	var body approveRemakeRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	remake, inlay, ok := m.getRemake(w, r)
+	if !ok {
+		return
+	}
+
+	if remake.Status != data.RemakeStatuses.Requested {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("cannot approve a remake in %s status", remake.Status))
+		return
+	}
+
+	original, found, err := m.Db.OrderSnapshots.GetByInlayID(inlay.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("inlay has no order to remake"))
+		return
+	}
+
+	user := m.ContextGetUser(r)
+	userID := user.GetID()
+	now := time.Now()
+
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	remake.Status = data.RemakeStatuses.Approved
+	remake.Billing = &body.Billing
+	remake.ReviewedBy = &userID
+	remake.ReviewedAt = &now
+	if err := m.Db.InlayRemakes.TxUpdate(tx, remake); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to approve remake: %w", err))
+		return
+	}
+
+	priceCents := original.PriceCents
+	if body.Billing == data.RemakeBillings.Free {
+		priceCents = 0
+	}
+	snapshot := &data.OrderSnapshot{
+		ProjectID:            original.ProjectID,
+		InlayID:              inlay.ID,
+		ProofID:              original.ProofID,
+		PriceGroupID:         original.PriceGroupID,
+		PriceCents:           priceCents,
+		ListPriceCents:       original.ListPriceCents,
+		TierDiscountCents:    original.TierDiscountCents,
+		PriceTierID:          original.PriceTierID,
+		PriceAdjustmentType:  original.PriceAdjustmentType,
+		PriceAdjustmentValue: original.PriceAdjustmentValue,
+		Width:                original.Width,
+		Height:               original.Height,
+		RemakeID:             &remake.ID,
+	}
+	if err := m.Db.OrderSnapshots.TxInsert(tx, snapshot); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to snapshot remake: %w", err))
+		return
+	}
+
+	if body.Billing == data.RemakeBillings.Charged {
+		err = m.Db.Invoices.TxAddRemakeCharge(tx, original.ProjectID, priceCents)
+		if err != nil {
+			switch {
+			case errors.Is(err, data.ErrInvoicePaid), errors.Is(err, data.ErrInvoiceSent):
+				m.WriteError(w, r, m.Err.BadRequest, err)
+			default:
+				m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to bill remake: %w", err))
+			}
+			return
+		}
+	}
+
+	milestone := data.InlayMilestone{
+		InlayID:     inlay.ID,
+		Step:        data.ManufacturingSteps.Ordered,
+		EventType:   data.MilestoneEventTypes.Entered,
+		PerformedBy: userID,
+		EventTime:   now,
+		Cycle:       remake.Cycle,
+	}
+	if err := m.Db.InlayMilestones.TxInsert(tx, &milestone); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to insert remake milestone: %w", err))
+		return
+	}
+
+	orderedStep := string(data.ManufacturingSteps.Ordered)
+	inlay.ManufacturingStep = &orderedStep
+	if err := m.Db.Inlays.TxUpdateFields(tx, inlay); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to return inlay to production: %w", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.AutoWatchProject(inlay.ProjectID, user)
+
+	billingNote := "at no charge"
+	if body.Billing == data.RemakeBillings.Charged {
+		billingNote = "and will be charged"
+	}
+	m.NotifyDealership(
+		inlay.ProjectID,
+		user,
+		data.NotificationEventTypes.RemakeReviewed,
+		fmt.Sprintf("Remake approved: %s", inlay.Name),
+		fmt.Sprintf("The remake of inlay %q was approved %s and is back in production.", inlay.Name, billingNote),
+		&inlay.ID,
+	)
+
+	m.WriteJSON(w, r, http.StatusOK, remake)
+}
+
+func (m *RemakeModule) HandleDeclineRemake(w http.ResponseWriter, r *http.Request) {
+	var body declineRemakeRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	remake, inlay, ok := m.getRemake(w, r)
+	if !ok {
+		return
+	}
+
+	if remake.Status != data.RemakeStatuses.Requested {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("cannot decline a remake in %s status", remake.Status))
+		return
+	}
+
+	user := m.ContextGetUser(r)
+	userID := user.GetID()
+	now := time.Now()
+	reason := strings.TrimSpace(body.Reason)
+
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	remake.Status = data.RemakeStatuses.Declined
+	remake.ReviewedBy = &userID
+	remake.ReviewedAt = &now
+	remake.DeclineReason = &reason
+	if err := m.Db.InlayRemakes.TxUpdate(tx, remake); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to decline remake: %w", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.NotifyDealership(
+		inlay.ProjectID,
+		user,
+		data.NotificationEventTypes.RemakeReviewed,
+		fmt.Sprintf("Remake declined: %s", inlay.Name),
+		fmt.Sprintf("The remake of inlay %q was declined: %s", inlay.Name, reason),
+		&inlay.ID,
+	)
+
+	m.WriteJSON(w, r, http.StatusOK, remake)
+}
+
+// HandleShipRemake sends a remade inlay in its own package, taking it off the
+// production board. Delivery goes through the shipment like any other package.
+func (m *RemakeModule) HandleShipRemake(w http.ResponseWriter, r *http.Request) {
+	var body shipRemakeRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	remake, inlay, ok := m.getRemake(w, r)
+	if !ok {
+		return
+	}
+
+	if remake.Status != data.RemakeStatuses.Approved {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("cannot ship a remake in %s status", remake.Status))
+		return
+	}
+
+	// Approval puts the inlay back at ordered, so its step is the remake's
+	// cycle and not where the original order got to.
+	if inlay.ManufacturingStep == nil || data.ManufacturingStep(*inlay.ManufacturingStep) != data.ManufacturingSteps.ReadyToShip {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("the remake has not reached ready-to-ship"))
+		return
+	}
+
+	tx, err := m.Db.STDB.Begin()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	shipment := &data.Shipment{
+		ProjectID:      inlay.ProjectID,
+		Carrier:        body.Carrier,
+		TrackingNumber: body.TrackingNumber,
+		WeightLbs:      body.WeightLbs,
+		InlayIDs:       []int{inlay.ID},
+		RemakeID:       &remake.ID,
+	}
+
+	shipTo, err := m.Db.OrderSnapshots.TxGetShipTo(tx, inlay.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	shipment.ShipTo = &shipTo
+
+	if err := m.Db.Shipments.TxInsert(tx, shipment); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to record remake shipment: %w", err))
+		return
+	}
+
+	remake.Status = data.RemakeStatuses.Shipped
+	if err := m.Db.InlayRemakes.TxUpdate(tx, remake); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, fmt.Errorf("failed to mark remake shipped: %w", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	user := m.ContextGetUser(r)
+	m.AutoWatchProject(inlay.ProjectID, user)
+
+	tracking := ""
+	if shipment.TrackingNumber != nil {
+		tracking = fmt.Sprintf(" Tracking number: %s", *shipment.TrackingNumber)
+	}
+	m.NotifyDealership(
+		inlay.ProjectID,
+		user,
+		data.NotificationEventTypes.ProjectShipped,
+		fmt.Sprintf("Remake shipped: %s", inlay.Name),
+		fmt.Sprintf("The remake of inlay %q has shipped.%s", inlay.Name, tracking),
+		&inlay.ID,
+	)
+
+	m.WriteJSON(w, r, http.StatusCreated, shipment)
+}
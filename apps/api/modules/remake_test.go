@@ -0,0 +1,226 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedOrderSnapshot records an inlay as part of its project's order.
+func seedOrderSnapshot(t *testing.T, ctx *testContext, inlay *data.Inlay, priceGroupID int) {
+	require.NoError(t, ctx.db.OrderSnapshots.Insert(&data.OrderSnapshot{
+		ProjectID:           inlay.ProjectID,
+		InlayID:             inlay.ID,
+		PriceGroupID:        priceGroupID,
+		PriceCents:          12500,
+		PriceAdjustmentType: data.PriceAdjustmentTypes.None,
+		Width:               12,
+		Height:              8,
+	}))
+}
+
+// seedOrderedInlayWithSnapshot seeds an ordered project and inlay along with
+// the order snapshot a remake is priced from.
+func seedOrderedInlayWithSnapshot(t *testing.T, ctx *testContext, dealershipID, priceGroupID, catalogItemID int) (*data.Project, *data.Inlay) {
+	project, inlay := seedOrderedProjectWithInlay(t, ctx, dealershipID, catalogItemID)
+	seedOrderSnapshot(t, ctx, inlay, priceGroupID)
+
+	return project, inlay
+}
+
+func TestRemakes_ApproveShipAndDeliver(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-RMK-0001")
+
+	project, inlay := seedOrderedInlayWithSnapshot(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID)
+	project.Rush = true
+	require.NoError(t, ctx.db.Projects.Update(project))
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/inlay/%s/remakes", inlay.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"reason": "Cracked in transit"},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "the inlay has not been delivered yet")
+
+	resp = ctx.request(testRequest{method: http.MethodPost, path: fmt.Sprintf("/api/project/%s/ship", project.UUID), token: internalToken, body: map[string]any{"tracking_number": "1Z999AA10123456784", "carrier": "ups"}})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+	resp = ctx.request(testRequest{method: http.MethodPost, path: fmt.Sprintf("/api/project/%s/deliver", project.UUID), token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/inlay/%s/remakes", inlay.UUID),
+		token:  dealershipToken,
+		body: map[string]any{
+			"reason":     "Cracked in transit",
+			"photo_urls": []string{"/file/remakes/crack-1.jpg", "/file/remakes/crack-2.jpg"},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var remake data.InlayRemake
+	require.NoError(t, json.Unmarshal(resp.body, &remake))
+	assert.Equal(t, 2, remake.Cycle)
+	assert.Equal(t, data.RemakeStatuses.Requested, remake.Status)
+	assert.Len(t, remake.Photos, 2)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/inlay/%s/remakes", inlay.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"reason": "Still cracked"},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "one open remake per inlay")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/remake/%s/approve", remake.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"billing": "free"},
+	})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/remake/%s/approve", remake.UUID),
+		token:  internalToken,
+		body:   map[string]any{"billing": "free"},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	reloaded, _, err := ctx.db.Inlays.GetByUUID(inlay.UUID)
+	require.NoError(t, err)
+	require.NotNil(t, reloaded.ManufacturingStep)
+	assert.Equal(t, string(data.ManufacturingSteps.Ordered), *reloaded.ManufacturingStep)
+
+	snapshot, found, err := ctx.db.OrderSnapshots.GetByRemakeID(remake.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, 0, snapshot.PriceCents, "a free remake is not charged")
+
+	original, _, err := ctx.db.OrderSnapshots.GetByInlayID(inlay.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 12500, original.PriceCents, "the original order is untouched")
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/inlays", token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var board []struct {
+		UUID        string `json:"uuid"`
+		RemakeCycle *int   `json:"remake_cycle"`
+		Rush        bool   `json:"rush"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &board))
+	require.Len(t, board, 1, "the remade inlay is back on the board")
+	require.NotNil(t, board[0].RemakeCycle)
+	assert.Equal(t, 2, *board[0].RemakeCycle)
+	assert.False(t, board[0].Rush, "the rush was for the original order")
+
+	shipRemake := testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/remake/%s/ship", remake.UUID),
+		token:  internalToken,
+		body:   map[string]any{"carrier": "fedex", "tracking_number": "7489 0000 1234"},
+	}
+	resp = ctx.request(shipRemake)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "the remake has not been made yet")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPatch,
+		path:   fmt.Sprintf("/api/inlay/%s/step", inlay.UUID),
+		token:  internalToken,
+		body:   map[string]any{"step": data.ManufacturingSteps.ReadyToShip},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(shipRemake)
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var shipment data.Shipment
+	require.NoError(t, json.Unmarshal(resp.body, &shipment))
+	require.NotNil(t, shipment.RemakeID)
+	assert.Equal(t, remake.ID, *shipment.RemakeID)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/shipment/%s/deliver", shipment.UUID),
+		token:  internalToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	closed, _, err := ctx.db.InlayRemakes.GetByUUID(remake.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, data.RemakeStatuses.Delivered, closed.Status)
+
+	reloadedProject, _, err := ctx.db.Projects.GetByUUID(project.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, data.ProjectStatuses.Invoiced, reloadedProject.Status, "a remake leaves the project status alone")
+}
+
+func TestRemakes_Decline(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-RMK-0002")
+
+	project, inlay := seedOrderedInlayWithSnapshot(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID)
+	resp := ctx.request(testRequest{method: http.MethodPost, path: fmt.Sprintf("/api/project/%s/ship", project.UUID), token: internalToken, body: map[string]any{"tracking_number": "1Z999AA10123456784"}})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+	resp = ctx.request(testRequest{method: http.MethodPost, path: fmt.Sprintf("/api/project/%s/deliver", project.UUID), token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/inlay/%s/remakes", inlay.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"reason": "Color looks off"},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var remake data.InlayRemake
+	require.NoError(t, json.Unmarshal(resp.body, &remake))
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/remakes", token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var queue []data.InlayRemake
+	require.NoError(t, json.Unmarshal(resp.body, &queue))
+	require.Len(t, queue, 1)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/remake/%s/decline", remake.UUID),
+		token:  internalToken,
+		body:   map[string]any{"reason": "Matches the approved proof"},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/remake/%s/ship", remake.UUID),
+		token:  internalToken,
+		body:   map[string]any{"carrier": "ups"},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "a declined remake never ships")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/inlay/%s/remakes", inlay.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"reason": "Color still looks off"},
+	})
+	assert.Equal(t, http.StatusCreated, resp.statusCode, "a declined remake does not block a new request")
+}
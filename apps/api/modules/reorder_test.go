@@ -0,0 +1,157 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedApprovedCustomizedInlay is a customized catalog inlay on a delivered
+// project whose coloring was priced and approved.
+func seedApprovedCustomizedInlay(t *testing.T, ctx *testContext, dealershipID, catalogItemID, priceGroupID int) (*data.Project, *data.Inlay, *data.InlayProof) {
+	project := &data.Project{
+		Name:         "Smith Memorial",
+		Status:       data.ProjectStatuses.Completed,
+		DealershipID: dealershipID,
+	}
+	require.NoError(t, ctx.db.Projects.Insert(project))
+
+	inlay, proof := seedCustomizedCatalogInlay(t, ctx, project.ID, catalogItemID, priceGroupID)
+
+	approvedAt := time.Now()
+	proof.Status = data.ProofStatuses.Approved
+	proof.ApprovedAt = &approvedAt
+	proof.PriceAdjustmentType = data.PriceAdjustmentTypes.Percent
+	proof.PriceAdjustmentValue = 20
+	require.NoError(t, ctx.db.InlayProofs.Update(proof))
+
+	tx, err := ctx.db.STDB.Begin()
+	require.NoError(t, err)
+	inlay.ApprovedProofID = &proof.ID
+	require.NoError(t, ctx.db.Inlays.TxUpdateFields(tx, inlay))
+	require.NoError(t, tx.Commit())
+
+	return project, inlay, proof
+}
+
+func TestReorderProject_CarriesApprovedDesignOver(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, _ := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-RO-0001")
+	source, original, originalProof := seedApprovedCustomizedInlay(t, ctx, dealershipUser.DealershipID, item.ID, priceGroup.ID)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/reorder", source.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var body struct {
+		Project data.Project `json:"project"`
+		Inlays  []data.Inlay `json:"inlays"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &body))
+	assert.Equal(t, data.ProjectStatuses.Draft, body.Project.Status)
+	assert.Equal(t, "Smith Memorial (reorder)", body.Project.Name)
+	require.Len(t, body.Inlays, 1)
+
+	clone, found, err := ctx.db.Inlays.GetByUUID(body.Inlays[0].UUID)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.NotEqual(t, original.ID, clone.ID)
+	assert.True(t, clone.IsCustomized)
+	require.NotNil(t, clone.ApprovedProofID, "an unchanged design is ready to order again")
+
+	proof, _, err := ctx.db.InlayProofs.GetByID(*clone.ApprovedProofID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, proof.VersionNumber)
+	assert.Equal(t, data.ProofStatuses.Approved, proof.Status)
+	assert.Equal(t, data.ProofApprovalAuthorities.Internal, proof.ApprovalAuthority)
+	assert.Equal(t, originalProof.DesignAssetURL, proof.DesignAssetURL)
+	assert.Equal(t, data.PriceAdjustmentTypes.Percent, proof.PriceAdjustmentType)
+	assert.InDelta(t, 20, proof.PriceAdjustmentValue, 1e-9)
+}
+
+func TestReorderProject_EditedCatalogItemNeedsReview(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, _ := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-RO-0002")
+	source, _, _ := seedApprovedCustomizedInlay(t, ctx, dealershipUser.DealershipID, item.ID, priceGroup.ID)
+
+	item.SvgURL = "https://example.com/test-v2.svg"
+	require.NoError(t, ctx.db.CatalogItems.Update(item))
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/reorder", source.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"name": "Smith Memorial, second sister"},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var body struct {
+		Project data.Project `json:"project"`
+		Inlays  []data.Inlay `json:"inlays"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &body))
+	assert.Equal(t, "Smith Memorial, second sister", body.Project.Name)
+	require.Len(t, body.Inlays, 1)
+
+	clone, _, err := ctx.db.Inlays.GetByUUID(body.Inlays[0].UUID)
+	require.NoError(t, err)
+	assert.Nil(t, clone.ApprovedProofID)
+
+	proof, found, err := ctx.db.InlayProofs.GetLatestByInlayID(clone.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, data.ProofStatuses.Pending, proof.Status)
+	assert.Equal(t, data.PriceAdjustmentTypes.None, proof.PriceAdjustmentType, "review starts from the catalog default price")
+}
+
+func TestCloneInlays_IntoDraftProject(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, _ := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-RO-0003")
+	past, stock := seedOrderedProjectWithInlay(t, ctx, dealershipUser.DealershipID, item.ID)
+	draft := seedDraftProject(t, ctx, dealershipUser.DealershipID, "New Order")
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/inlays/clone", draft.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{stock.UUID}},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	inlays, err := ctx.db.Inlays.GetByProjectID(draft.ID)
+	require.NoError(t, err)
+	require.Len(t, inlays, 1)
+	assert.False(t, inlays[0].IsCustomized)
+	assert.Nil(t, inlays[0].ManufacturingStep)
+	assert.Equal(t, item.SvgURL, inlays[0].PreviewURL)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/inlays/clone", past.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{stock.UUID}},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "only drafts take new inlays")
+}
@@ -0,0 +1,344 @@
+package role
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type RoleModule struct {
+	*app.Application
+}
+
+func NewRoleModule(app *app.Application) *RoleModule {
+	return &RoleModule{app}
+}
+
+type roleRequest struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name" validate:"required,min=1,max=255"`
+	Description string   `json:"description" validate:"max=1000"`
+	Actions     []string `json:"actions" validate:"required"`
+}
+
+type overridesRequest struct {
+	Overrides []data.PermissionOverride `json:"overrides" validate:"required"`
+}
+
+// roleModel picks the dealership or internal roles from the {user_type} path
+// segment.
+func (m *RoleModule) roleModel(w http.ResponseWriter, r *http.Request) (data.RoleModel, bool) {
+	switch r.PathValue("user_type") {
+	case "dealership":
+		return m.Db.DealershipRoles, true
+	case "internal":
+		return m.Db.InternalRoles, true
+	default:
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return data.RoleModel{}, false
+	}
+}
+
+func (m *RoleModule) getRole(w http.ResponseWriter, r *http.Request, roles data.RoleModel) (*data.Role, bool) {
+	roleUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(roleUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	role, found, err := roles.GetByUUID(roleUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return role, true
+}
+
+// HandleGetRoles lists the roles for one kind of user along with the actions
+// they can be given.
+func (m *RoleModule) HandleGetRoles(w http.ResponseWriter, r *http.Request) {
+	roles, ok := m.roleModel(w, r)
+	if !ok {
+		return
+	}
+
+	all, err := roles.GetAll()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]any{
+		"roles":   all,
+		"actions": roles.Actions(),
+	})
+}
+
+// HandlePostRole creates a custom role. Users reference roles by key, so it
+// cannot be changed afterwards.
+func (m *RoleModule) HandlePostRole(w http.ResponseWriter, r *http.Request) {
+	roles, ok := m.roleModel(w, r)
+	if !ok {
+		return
+	}
+
+	var body roleRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	err = data.ValidateRoleKey(body.Key)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	err = roles.ValidateActions(body.Actions)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	_, found, err := roles.GetByKey(body.Key)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if found {
+		m.WriteError(w, r, m.Err.Conflict, errors.New("a role with this key already exists"))
+		return
+	}
+
+	role := &data.Role{
+		Key:         body.Key,
+		Name:        body.Name,
+		Description: body.Description,
+		Actions:     body.Actions,
+	}
+
+	err = roles.Insert(role)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusCreated, role)
+}
+
+// HandlePutRole changes a role's name, description and actions. Built-in roles
+// can be changed too; they are only the defaults the app ships with.
+func (m *RoleModule) HandlePutRole(w http.ResponseWriter, r *http.Request) {
+	roles, ok := m.roleModel(w, r)
+	if !ok {
+		return
+	}
+
+	var body roleRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	role, ok := m.getRole(w, r, roles)
+	if !ok {
+		return
+	}
+
+	if body.Key != "" && body.Key != role.Key {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("key cannot be changed"))
+		return
+	}
+
+	err = roles.ValidateActions(body.Actions)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	role.Name = body.Name
+	role.Description = body.Description
+	role.Actions = body.Actions
+
+	err = roles.Update(role)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, role)
+}
+
+func (m *RoleModule) HandleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	roles, ok := m.roleModel(w, r)
+	if !ok {
+		return
+	}
+
+	role, ok := m.getRole(w, r, roles)
+	if !ok {
+		return
+	}
+
+	err := roles.Delete(role)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRoleBuiltin), errors.Is(err, data.ErrRoleInUse):
+			m.WriteError(w, r, m.Err.Conflict, err)
+		default:
+			m.WriteError(w, r, m.Err.ServerError, err)
+		}
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
+
+// writePermissions shows a user's role, their overrides and what they end up
+// being allowed to do.
+func (m *RoleModule) writePermissions(w http.ResponseWriter, r *http.Request, user data.AuthUser) {
+	roles := m.Db.RolesFor(user)
+
+	overrides, err := roles.GetOverrides(user.GetID())
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	permissions, err := roles.Resolve(user.GetID(), user.GetRole())
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]any{
+		"role":      user.GetRole(),
+		"overrides": overrides,
+		"actions":   permissions.Actions(),
+	})
+}
+
+func (m *RoleModule) setOverrides(w http.ResponseWriter, r *http.Request, user data.AuthUser) {
+	var body overridesRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	roles := m.Db.RolesFor(user)
+
+	actions := make([]string, len(body.Overrides))
+	for i, override := range body.Overrides {
+		actions[i] = override.Action
+	}
+
+	err = roles.ValidateActions(actions)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	err = roles.SetOverrides(user.GetID(), body.Overrides)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.writePermissions(w, r, user)
+}
+
+func (m *RoleModule) getDealershipUser(w http.ResponseWriter, r *http.Request) (*data.DealershipUser, bool) {
+	userUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(userUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	user, found, err := m.Db.DealershipUsers.GetByUUID(userUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (m *RoleModule) getInternalUser(w http.ResponseWriter, r *http.Request) (*data.InternalUser, bool) {
+	userUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(userUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	user, found, err := m.Db.InternalUsers.GetByUUID(userUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (m *RoleModule) HandleGetDealershipUserPermissions(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getDealershipUser(w, r)
+	if !ok {
+		return
+	}
+
+	m.writePermissions(w, r, user)
+}
+
+// HandlePutDealershipUserPermissions replaces the user's overrides.
+func (m *RoleModule) HandlePutDealershipUserPermissions(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getDealershipUser(w, r)
+	if !ok {
+		return
+	}
+
+	m.setOverrides(w, r, user)
+}
+
+func (m *RoleModule) HandleGetInternalUserPermissions(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getInternalUser(w, r)
+	if !ok {
+		return
+	}
+
+	m.writePermissions(w, r, user)
+}
+
+// HandlePutInternalUserPermissions replaces the user's overrides.
+func (m *RoleModule) HandlePutInternalUserPermissions(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getInternalUser(w, r)
+	if !ok {
+		return
+	}
+
+	m.setOverrides(w, r, user)
+}
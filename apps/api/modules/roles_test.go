@@ -0,0 +1,89 @@
+package modules
+
+import (
+	"net/http"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoles_CustomRoleAndOverrides(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+
+	resp := ctx.request(testRequest{method: http.MethodGet, path: "/api/roles/dealership", token: dealershipToken})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "only internal admins manage roles")
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/roles/dealership", token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+	assert.Len(t, resp.parsed.(map[string]any)["roles"], 4, "the built-in roles are seeded")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/roles/dealership",
+		token:  internalToken,
+		body:   map[string]any{"key": "chatter", "name": "Chatter", "actions": []string{data.ActionManageCatalog}},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "internal actions cannot go on dealership roles")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/roles/dealership",
+		token:  internalToken,
+		body:   map[string]any{"key": "chatter", "name": "Chatter", "actions": []string{data.ActionViewProjects, data.ActionSendChat}},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+	roleUUID := resp.parsed.(map[string]any)["uuid"].(string)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPatch,
+		path:   "/api/dealership-user/" + dealershipUser.UUID,
+		token:  internalToken,
+		body:   map[string]any{"role": "nonexistent"},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "users can only be given roles that exist")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPatch,
+		path:   "/api/dealership-user/" + dealershipUser.UUID,
+		token:  internalToken,
+		body:   map[string]any{"role": "chatter"},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	newProject := testRequest{
+		method: http.MethodPost,
+		path:   "/api/project",
+		token:  dealershipToken,
+		body:   map[string]any{"name": "Chatter's project"},
+	}
+
+	resp = ctx.request(newProject)
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "the custom role cannot create projects")
+
+	permissionsPath := "/api/dealership-user/" + dealershipUser.UUID + "/permissions"
+	resp = ctx.request(testRequest{
+		method: http.MethodPut,
+		path:   permissionsPath,
+		token:  internalToken,
+		body: map[string]any{"overrides": []map[string]any{
+			{"action": data.ActionCreateProject, "granted": true},
+			{"action": data.ActionSendChat, "granted": false},
+		}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resolved := resp.parsed.(map[string]any)
+	assert.Equal(t, "chatter", resolved["role"])
+	assert.ElementsMatch(t, []any{data.ActionCreateProject, data.ActionViewProjects}, resolved["actions"])
+
+	resp = ctx.request(newProject)
+	assert.NotEqual(t, http.StatusForbidden, resp.statusCode, "a granted override lets the user through")
+
+	resp = ctx.request(testRequest{method: http.MethodDelete, path: "/api/roles/dealership/" + roleUUID, token: internalToken})
+	assert.Equal(t, http.StatusConflict, resp.statusCode, "a role in use cannot be deleted")
+}
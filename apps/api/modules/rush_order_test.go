@@ -0,0 +1,170 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRushOrder_LocksSurchargeAndNotifiesProduction(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+	production, _ := seedInternalUser(t, ctx, data.InternalUserRoles.Production)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-RUSH-0001")
+
+	project := seedDraftProject(t, ctx, dealershipUser.DealershipID, "Rush Project")
+	inlay := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Dove")
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body: map[string]any{
+			"inlay_uuids": []string{inlay.UUID},
+			"rush":        true,
+			"required_by": time.Now().AddDate(0, 0, -2).Format(time.DateOnly),
+		},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "a date already gone by could never be met")
+
+	requiredBy := time.Now().AddDate(0, 0, 10).Format(time.DateOnly)
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body: map[string]any{
+			"inlay_uuids": []string{inlay.UUID},
+			"rush":        true,
+			"required_by": requiredBy,
+		},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	ordered, found, err := ctx.db.Projects.GetByID(project.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.True(t, ordered.Rush)
+	require.NotNil(t, ordered.RushSurchargeCents)
+	assert.Equal(t, 2500, *ordered.RushSurchargeCents)
+	require.NotNil(t, ordered.RequiredBy)
+	assert.Equal(t, requiredBy, ordered.RequiredBy.Format(time.DateOnly))
+
+	assert.Contains(t,
+		notificationEventTypesFor(t, ctx, production.ID, false),
+		data.NotificationEventTypes.RushOrderPlaced,
+	)
+	assert.NotContains(t,
+		notificationEventTypesFor(t, ctx, production.ID, false),
+		data.NotificationEventTypes.OrderPlaced,
+		"a rush order replaces the usual order notification",
+	)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/invoice", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{"invoice_url": "https://example.com/invoice.pdf"},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var invoice data.Invoice
+	require.NoError(t, json.Unmarshal(resp.body, &invoice))
+	assert.Equal(t, 12500, invoice.SubtotalCents)
+	assert.Equal(t, 12500, invoice.TotalCents)
+}
+
+func TestRushOrder_PlainOrderHasNoSurcharge(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, _ := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-RUSH-0002")
+
+	project := seedDraftProject(t, ctx, dealershipUser.DealershipID, "Plain Project")
+	inlay := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Dove")
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{inlay.UUID}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var placed data.Project
+	require.NoError(t, json.Unmarshal(resp.body, &placed))
+	assert.False(t, placed.Rush)
+	require.NotNil(t, placed.RushSurchargeCents)
+	assert.Equal(t, 0, *placed.RushSurchargeCents)
+}
+
+func TestKanban_RushAndOverdueSortFirst(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-RUSH-0003")
+
+	placeOnBoard := func(rush bool, requiredBy *time.Time) *data.Inlay {
+		project, inlay := seedOrderedProjectWithInlay(t, ctx, dealershipUser.DealershipID, item.ID)
+		project.Rush = rush
+		project.RequiredBy = requiredBy
+		require.NoError(t, ctx.db.Projects.Update(project))
+		setInlayStep(t, ctx, inlay, data.ManufacturingSteps.Manufacturing)
+		return inlay
+	}
+
+	lastWeek := time.Now().AddDate(0, 0, -7)
+	nextMonth := time.Now().AddDate(0, 1, 0)
+
+	plain := placeOnBoard(false, nil)
+	dueLater := placeOnBoard(false, &nextMonth)
+	overdue := placeOnBoard(false, &lastWeek)
+	rush := placeOnBoard(true, nil)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPatch,
+		path:   fmt.Sprintf("/api/project/%s", projectOfInlay(t, ctx, plain).UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"required_by": lastWeek.Format(time.DateOnly)},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "a past date can't be set")
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/inlays", token: internalToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var board []struct {
+		UUID    string `json:"uuid"`
+		Rush    bool   `json:"rush"`
+		Overdue bool   `json:"overdue"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &board))
+	require.Len(t, board, 4)
+
+	assert.Equal(t, overdue.UUID, board[0].UUID, "overdue has the earliest required-by date")
+	assert.True(t, board[0].Overdue)
+	assert.Equal(t, rush.UUID, board[1].UUID)
+	assert.True(t, board[1].Rush)
+	assert.Equal(t, dueLater.UUID, board[2].UUID)
+	assert.Equal(t, plain.UUID, board[3].UUID)
+}
+
+func projectOfInlay(t *testing.T, ctx *testContext, inlay *data.Inlay) *data.Project {
+	t.Helper()
+
+	p, found, err := ctx.db.Projects.GetByID(inlay.ProjectID)
+	require.NoError(t, err)
+	require.True(t, found)
+	return p
+}
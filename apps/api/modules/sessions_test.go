@@ -0,0 +1,128 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refreshAccess posts a refresh cookie and returns the access token along with
+// the rotated refresh cookie, if one was set.
+func refreshAccess(t *testing.T, ctx *testContext, refreshToken string) (int, string, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/token/access", nil)
+	req.Header.Set("Cookie", fmt.Sprintf("refresh_token=%s", refreshToken))
+	req.Header.Set("User-Agent", "session-test")
+	w := httptest.NewRecorder()
+	ctx.handler.ServeHTTP(w, req)
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+
+	var rotated string
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "refresh_token" && cookie.MaxAge >= 0 {
+			rotated = cookie.Value
+		}
+	}
+
+	return w.Code, body.AccessToken, rotated
+}
+
+func TestSessions_RotationAndReuse(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, _, _ := seedTestData(t, ctx)
+
+	sessionID := uuid.NewString()
+	first, err := ctx.db.DealershipTokens.NewForSession(dealershipUser.ID, data.RefreshTokenTTL, data.DealershipScopeRefresh, sessionID, data.SessionClient{})
+	require.NoError(t, err)
+
+	status, accessToken, second := refreshAccess(t, ctx, first.Plaintext)
+	require.Equal(t, http.StatusCreated, status)
+	require.NotEmpty(t, accessToken)
+	require.NotEmpty(t, second, "each refresh hands out a new refresh token")
+	assert.NotEqual(t, first.Plaintext, second)
+
+	resp := ctx.request(testRequest{method: http.MethodGet, path: "/api/auth/sessions", token: accessToken})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var sessions []data.Session
+	require.NoError(t, json.Unmarshal(resp.body, &sessions))
+	require.Len(t, sessions, 1)
+	assert.Equal(t, sessionID, sessions[0].ID)
+	assert.Equal(t, "session-test", sessions[0].UserAgent)
+	assert.True(t, sessions[0].Current)
+
+	status, _, rotated := refreshAccess(t, ctx, first.Plaintext)
+	assert.Equal(t, http.StatusCreated, status, "a second tab refreshing at the same moment still gets in")
+	assert.Empty(t, rotated, "but keeps the cookie the first tab was given")
+
+	_, err = ctx.db.DealershipTokens.DB.Exec(t.Context(), `UPDATE dealership_tokens SET rotated_at = now() - interval '1 hour' WHERE rotated_at IS NOT NULL`)
+	require.NoError(t, err)
+
+	status, _, _ = refreshAccess(t, ctx, first.Plaintext)
+	assert.Equal(t, http.StatusUnauthorized, status, "an old refresh token presented again is treated as stolen")
+
+	status, _, _ = refreshAccess(t, ctx, second)
+	assert.Equal(t, http.StatusUnauthorized, status, "and the whole session is revoked")
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/user/self", token: accessToken})
+	assert.Equal(t, http.StatusUnauthorized, resp.statusCode, "including its access tokens")
+}
+
+func TestSessions_RevokeAndForceLogout(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, _, internalToken := seedTestData(t, ctx)
+
+	laptop, err := ctx.db.DealershipTokens.NewForSession(dealershipUser.ID, data.RefreshTokenTTL, data.DealershipScopeRefresh, uuid.NewString(), data.SessionClient{})
+	require.NoError(t, err)
+	phone, err := ctx.db.DealershipTokens.NewForSession(dealershipUser.ID, data.RefreshTokenTTL, data.DealershipScopeRefresh, uuid.NewString(), data.SessionClient{})
+	require.NoError(t, err)
+
+	_, laptopAccess, _ := refreshAccess(t, ctx, laptop.Plaintext)
+	_, phoneAccess, phoneRefresh := refreshAccess(t, ctx, phone.Plaintext)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodDelete,
+		path:   fmt.Sprintf("/api/auth/sessions/%s", phone.SessionID),
+		token:  laptopAccess,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/user/self", token: phoneAccess})
+	assert.Equal(t, http.StatusUnauthorized, resp.statusCode, "the revoked session is signed out at once")
+
+	status, _, _ := refreshAccess(t, ctx, phoneRefresh)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodDelete,
+		path:   fmt.Sprintf("/api/auth/sessions/%s", phone.SessionID),
+		token:  laptopAccess,
+	})
+	assert.Equal(t, http.StatusNotFound, resp.statusCode)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodDelete,
+		path:   fmt.Sprintf("/api/dealership-user/%s/sessions", dealershipUser.UUID),
+		token:  internalToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/user/self", token: laptopAccess})
+	assert.Equal(t, http.StatusUnauthorized, resp.statusCode, "an admin can sign a user out everywhere")
+}
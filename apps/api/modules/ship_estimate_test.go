@@ -0,0 +1,203 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordStay writes the milestones for one finished stay in a step.
+func recordStay(t *testing.T, ctx *testContext, inlayID, userID int, step data.ManufacturingStep, enteredAt time.Time, hours float64) time.Time {
+	exitedAt := enteredAt.Add(time.Duration(hours * float64(time.Hour)))
+	require.NoError(t, ctx.db.InlayMilestones.Insert(&data.InlayMilestone{
+		InlayID:     inlayID,
+		Step:        step,
+		EventType:   data.MilestoneEventTypes.Entered,
+		PerformedBy: userID,
+		EventTime:   enteredAt,
+	}))
+	require.NoError(t, ctx.db.InlayMilestones.Insert(&data.InlayMilestone{
+		InlayID:     inlayID,
+		Step:        step,
+		EventType:   data.MilestoneEventTypes.Exited,
+		PerformedBy: userID,
+		EventTime:   exitedAt,
+	}))
+	return exitedAt
+}
+
+// seedProductionHistory runs count inlays through every step, starting at
+// start, spending the given hours in manufacturing.
+func seedProductionHistory(t *testing.T, ctx *testContext, dealershipID, priceGroupID, catalogItemID, userID, count int, start time.Time, manufacturingHours float64) {
+	for range count {
+		_, inlay := seedOrderedInlayWithSnapshot(t, ctx, dealershipID, priceGroupID, catalogItemID)
+
+		at := recordStay(t, ctx, inlay.ID, userID, data.ManufacturingSteps.Ordered, start, 4)
+		at = recordStay(t, ctx, inlay.ID, userID, data.ManufacturingSteps.MaterialsPrep, at, 10)
+		at = recordStay(t, ctx, inlay.ID, userID, data.ManufacturingSteps.Manufacturing, at, manufacturingHours)
+		recordStay(t, ctx, inlay.ID, userID, data.ManufacturingSteps.Packaging, at, 2)
+	}
+}
+
+func TestShipEstimate_FollowsInlayThroughSteps(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, internalUser, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-ETA-0001")
+
+	seedProductionHistory(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID, internalUser.ID, 5, time.Now().AddDate(0, 0, -30), 20)
+
+	project, inlay := seedOrderedInlayWithSnapshot(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPatch,
+		path:   fmt.Sprintf("/api/inlay/%s/step", inlay.UUID),
+		token:  internalToken,
+		body:   map[string]any{"step": data.ManufacturingSteps.MaterialsPrep},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/api/inlay/%s/ship-estimate", inlay.UUID),
+		token:  dealershipToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var estimate struct {
+		Step       string     `json:"step"`
+		ExpectedAt *time.Time `json:"expected_at"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &estimate))
+	assert.Equal(t, string(data.ManufacturingSteps.MaterialsPrep), estimate.Step)
+	require.NotNil(t, estimate.ExpectedAt)
+	assert.WithinDuration(t, time.Now().Add(32*time.Hour), *estimate.ExpectedAt, time.Minute)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPatch,
+		path:   fmt.Sprintf("/api/inlay/%s/step", inlay.UUID),
+		token:  internalToken,
+		body:   map[string]any{"step": data.ManufacturingSteps.Manufacturing},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/api/project/%s/ship-estimate", project.UUID),
+		token:  dealershipToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var projectEstimate struct {
+		ExpectedAt *time.Time `json:"expected_at"`
+		Inlays     []struct {
+			InlayUUID string `json:"inlay_uuid"`
+		} `json:"inlays"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &projectEstimate))
+	require.NotNil(t, projectEstimate.ExpectedAt)
+	assert.WithinDuration(t, time.Now().Add(22*time.Hour), *projectEstimate.ExpectedAt, time.Minute)
+	require.Len(t, projectEstimate.Inlays, 1)
+	assert.Equal(t, inlay.UUID, projectEstimate.Inlays[0].InlayUUID)
+}
+
+func TestShipEstimate_NoHistoryLeavesDatesEmpty(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, _ := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-ETA-0002")
+
+	_, inlay := seedOrderedInlayWithSnapshot(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID)
+	setInlayStep(t, ctx, inlay, data.ManufacturingSteps.Ordered)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/api/inlay/%s/ship-estimate", inlay.UUID),
+		token:  dealershipToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var estimate map[string]any
+	require.NoError(t, json.Unmarshal(resp.body, &estimate))
+	assert.Nil(t, estimate["expected_at"])
+}
+
+func TestSlowSteps_FlagsStepAndStuckInlays(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, internalUser, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-ETA-0003")
+
+	seedProductionHistory(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID, internalUser.ID, 5, time.Now().AddDate(0, 0, -60), 20)
+	seedProductionHistory(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID, internalUser.ID, 5, time.Now().AddDate(0, 0, -7), 40)
+
+	_, stuck := seedOrderedInlayWithSnapshot(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID)
+	require.NoError(t, ctx.db.InlayMilestones.Insert(&data.InlayMilestone{
+		InlayID:     stuck.ID,
+		Step:        data.ManufacturingSteps.Manufacturing,
+		EventType:   data.MilestoneEventTypes.Entered,
+		PerformedBy: internalUser.ID,
+		EventTime:   time.Now().Add(-72 * time.Hour),
+	}))
+	setInlayStep(t, ctx, stuck, data.ManufacturingSteps.Manufacturing)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   "/api/reports/slow-steps",
+		token:  dealershipToken,
+	})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   "/api/reports/slow-steps",
+		token:  internalToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var report []struct {
+		Step        string `json:"step"`
+		Slow        bool   `json:"slow"`
+		StuckInlays []struct {
+			InlayUUID string `json:"inlay_uuid"`
+		} `json:"stuck_inlays"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &report))
+
+	slow := map[string]bool{}
+	for _, step := range report {
+		slow[step.Step] = step.Slow
+		if step.Step == string(data.ManufacturingSteps.Manufacturing) {
+			require.Len(t, step.StuckInlays, 1)
+			assert.Equal(t, stuck.UUID, step.StuckInlays[0].InlayUUID)
+		}
+	}
+	assert.True(t, slow[string(data.ManufacturingSteps.Manufacturing)])
+	assert.False(t, slow[string(data.ManufacturingSteps.MaterialsPrep)])
+
+	resp = ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   "/api/reports/step-durations?group_by=inlay_type",
+		token:  internalToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   "/api/reports/step-durations?group_by=color",
+		token:  internalToken,
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode)
+}
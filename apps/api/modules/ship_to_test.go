@@ -0,0 +1,178 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShipTo_CapturedAtOrderAndUsedOnShipment(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+	dealership, _, err := ctx.db.Dealerships.GetByID(dealershipUser.DealershipID)
+	require.NoError(t, err)
+
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-SHIP-0001")
+	project := seedDraftProject(t, ctx, dealershipUser.DealershipID, "Lake House")
+	inlay := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Heron")
+
+	addressesPath := fmt.Sprintf("/api/dealership/%s/shipping-addresses", dealership.UUID)
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   addressesPath,
+		token:  dealershipToken,
+		body: map[string]any{
+			"label":       "Lake house",
+			"street":      "12 Shore Rd",
+			"city":        "Coeur d'Alene",
+			"state":       "ID",
+			"postal_code": "838",
+			"country":     "US",
+		},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "a US address needs a full ZIP code")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   addressesPath,
+		token:  dealershipToken,
+		body: map[string]any{
+			"label":       "Lake house",
+			"recipient":   "Pat Client",
+			"street":      "12 Shore Rd",
+			"city":        "Coeur d'Alene",
+			"state":       "id",
+			"postal_code": "83814",
+			"country":     "us",
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var address data.ShippingAddress
+	require.NoError(t, json.Unmarshal(resp.body, &address))
+	assert.Equal(t, "ID", address.State)
+	assert.Equal(t, "US", address.Country)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPatch,
+		path:   fmt.Sprintf("/api/project/%s", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"ship_to_address_uuid": address.UUID},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{inlay.UUID}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	snapshot, found, err := ctx.db.OrderSnapshots.GetByInlayID(inlay.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NotNil(t, snapshot.ShipTo)
+	assert.Equal(t, "Pat Client", snapshot.ShipTo.Recipient)
+	assert.Equal(t, "12 Shore Rd", snapshot.ShipTo.Street)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPut,
+		path:   fmt.Sprintf("/api/shipping-address/%s", address.UUID),
+		token:  dealershipToken,
+		body: map[string]any{
+			"label":       "Lake house",
+			"street":      "99 Moved Ave",
+			"city":        "Sandpoint",
+			"state":       "ID",
+			"postal_code": "83864",
+			"country":     "US",
+		},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/ship", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{"tracking_number": "1Z999", "carrier": "ups"},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	shipments, err := ctx.db.Shipments.GetByProjectID(project.ID)
+	require.NoError(t, err)
+	require.Len(t, shipments, 1)
+	require.NotNil(t, shipments[0].ShipTo)
+	assert.Equal(t, "12 Shore Rd", shipments[0].ShipTo.Street, "the package goes where the order was placed to")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/api/shipment/%s/packing-slip", shipments[0].UUID),
+		token:  dealershipToken,
+	})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/api/shipment/%s/packing-slip", shipments[0].UUID),
+		token:  internalToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var slip struct {
+		ShipTo data.ShipTo `json:"ship_to"`
+		Lines  []struct {
+			InlayUUID string `json:"inlay_uuid"`
+		} `json:"lines"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &slip))
+	assert.Equal(t, "Pat Client", slip.ShipTo.Recipient)
+	assert.Equal(t, "83814", slip.ShipTo.PostalCode)
+	require.Len(t, slip.Lines, 1)
+	assert.Equal(t, inlay.UUID, slip.Lines[0].InlayUUID)
+}
+
+func TestShipTo_DefaultsToDealershipAddress(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, _ := seedTestData(t, ctx)
+	dealership, _, err := ctx.db.Dealerships.GetByID(dealershipUser.DealershipID)
+	require.NoError(t, err)
+
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-SHIP-0002")
+	project := seedDraftProject(t, ctx, dealershipUser.DealershipID, "Showroom")
+	inlay := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Wren")
+
+	other := seedDraftProject(t, ctx, dealershipUser.DealershipID, "Other")
+	resp := ctx.request(testRequest{
+		method: http.MethodPatch,
+		path:   fmt.Sprintf("/api/project/%s", other.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"ship_to_address_uuid": "5b4c4a1e-8d0e-4b8a-9f6a-2f1d3c4b5a69"},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "the address must be one of the dealership's")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{inlay.UUID}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	snapshot, found, err := ctx.db.OrderSnapshots.GetByInlayID(inlay.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NotNil(t, snapshot.ShipTo)
+	assert.Equal(t, data.DealershipShipTo(dealership), *snapshot.ShipTo)
+}
@@ -0,0 +1,238 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShipments_PartialPackagesDeriveProjectStatus(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-SHP-0001")
+
+	project, first := seedOrderedInlayWithSnapshot(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID)
+	second := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Heron")
+	seedOrderSnapshot(t, ctx, second, priceGroup.ID)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/shipments", project.UUID),
+		token:  internalToken,
+		body: map[string]any{
+			"carrier":         "ups",
+			"tracking_number": "1Z999AA10123456784",
+			"weight_lbs":      12.5,
+			"inlay_uuids":     []string{first.UUID},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var firstPackage struct {
+		Shipment data.Shipment `json:"shipment"`
+		Project  data.Project  `json:"project"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &firstPackage))
+	assert.Equal(t, []int{first.ID}, firstPackage.Shipment.InlayIDs)
+	require.NotNil(t, firstPackage.Shipment.TrackingURL)
+	assert.Equal(t, "https://www.ups.com/track?tracknum=1Z999AA10123456784", *firstPackage.Shipment.TrackingURL)
+	assert.Equal(t, data.ProjectStatuses.Ordered, firstPackage.Project.Status, "one inlay is still in the shop")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/shipments", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{"carrier": "usps", "inlay_uuids": []string{first.UUID}},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "an inlay ships once")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/shipments", project.UUID),
+		token:  internalToken,
+		body: map[string]any{
+			"carrier":         "fedex",
+			"tracking_number": "7489 0000 1234",
+			"inlay_uuids":     []string{second.UUID},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var secondPackage struct {
+		Shipment data.Shipment `json:"shipment"`
+		Project  data.Project  `json:"project"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &secondPackage))
+	assert.Equal(t, data.ProjectStatuses.Shipped, secondPackage.Project.Status)
+	require.NotNil(t, secondPackage.Project.TrackingNumber)
+	assert.Equal(t, "7489 0000 1234", *secondPackage.Project.TrackingNumber)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/shipment/%s/deliver", firstPackage.Shipment.UUID),
+		token:  internalToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	reloaded, _, err := ctx.db.Projects.GetByUUID(project.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, data.ProjectStatuses.Shipped, reloaded.Status, "the second package is still in transit")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/shipment/%s/deliver", secondPackage.Shipment.UUID),
+		token:  internalToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	reloaded, _, err = ctx.db.Projects.GetByUUID(project.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, data.ProjectStatuses.Invoiced, reloaded.Status)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/shipment/%s/deliver", secondPackage.Shipment.UUID),
+		token:  internalToken,
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "a package is delivered once")
+}
+
+func TestShipments_ShipShortcutPacksRemainingInlays(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-SHP-0002")
+
+	project, first := seedOrderedInlayWithSnapshot(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID)
+	second := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Heron")
+	seedOrderSnapshot(t, ctx, second, priceGroup.ID)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/shipments", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{"carrier": "other", "inlay_uuids": []string{first.UUID}},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/ship", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{"tracking_number": "9400100000000000000000", "carrier": "usps"},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/api/project/%s/shipments", project.UUID),
+		token:  dealershipToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var shipments []data.Shipment
+	require.NoError(t, json.Unmarshal(resp.body, &shipments))
+	require.Len(t, shipments, 2)
+	assert.Nil(t, shipments[0].TrackingURL, "no tracking number, no link")
+	assert.Equal(t, []int{second.ID}, shipments[1].InlayIDs)
+	assert.Equal(t, data.ShipmentCarriers.USPS, shipments[1].Carrier)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/deliver", project.UUID),
+		token:  internalToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	delivered, err := ctx.db.Shipments.GetByProjectID(project.ID)
+	require.NoError(t, err)
+	for _, shipment := range delivered {
+		assert.NotNil(t, shipment.DeliveredAt)
+	}
+}
+
+func TestShipments_RejectsInlayFromAnotherProject(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-SHP-0003")
+
+	project, _ := seedOrderedInlayWithSnapshot(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID)
+	_, other := seedOrderedInlayWithSnapshot(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/shipments", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{"carrier": "dhl", "inlay_uuids": []string{other.UUID}},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode)
+}
+
+func TestShipments_OnlyOrderedInlaysShip(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, _, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-SHP-0004")
+
+	project, ordered := seedOrderedInlayWithSnapshot(t, ctx, dealershipUser.DealershipID, priceGroup.ID, item.ID)
+	unordered := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Heron")
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/shipments", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{"carrier": "ups", "inlay_uuids": []string{ordered.UUID, ordered.UUID}},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "an inlay is listed once per package")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/shipments", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{"carrier": "ups", "inlay_uuids": []string{unordered.UUID}},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "an inlay left out of the order does not ship")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/ship", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{"tracking_number": "1Z999AA10123456784"},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var shipped data.Project
+	require.NoError(t, json.Unmarshal(resp.body, &shipped))
+	assert.Equal(t, data.ProjectStatuses.Shipped, shipped.Status, "every ordered inlay is packed")
+
+	shipments, err := ctx.db.Shipments.GetByProjectID(project.ID)
+	require.NoError(t, err)
+	require.Len(t, shipments, 1)
+	assert.Equal(t, []int{ordered.ID}, shipments[0].InlayIDs)
+
+	shipped.Status = data.ProjectStatuses.InProduction
+	require.NoError(t, ctx.db.Projects.Update(&shipped))
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/ship", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{"tracking_number": "1Z999AA10123456785"},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "there is nothing left to pack")
+}
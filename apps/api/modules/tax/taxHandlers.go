@@ -0,0 +1,219 @@
+package tax
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+type TaxModule struct {
+	*app.Application
+}
+
+func NewTaxModule(app *app.Application) *TaxModule {
+	return &TaxModule{app}
+}
+
+type taxRateRequest struct {
+	Name             string  `json:"name" validate:"required,min=1,max=255"`
+	State            string  `json:"state" validate:"required,len=2,alpha"`
+	PostalCodePrefix *string `json:"postal_code_prefix" validate:"omitempty,min=1,max=10,alphanum"`
+	RatePercent      float64 `json:"rate_percent" validate:"gte=0,lte=100"`
+}
+
+func (m *TaxModule) getTaxRate(w http.ResponseWriter, r *http.Request) (*data.TaxRate, bool) {
+	taxRateUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(taxRateUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	taxRate, found, err := m.Db.TaxRates.GetByUUID(taxRateUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return taxRate, true
+}
+
+// checkRuleFree reports an error when another rule already covers the same
+// state and prefix, since only one rule may decide an address's rate.
+func (m *TaxModule) checkRuleFree(body taxRateRequest, exceptID int) error {
+	rates, err := m.Db.TaxRates.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, rate := range rates {
+		if rate.ID == exceptID || !strings.EqualFold(rate.State, body.State) {
+			continue
+		}
+		if (rate.PostalCodePrefix == nil) != (body.PostalCodePrefix == nil) {
+			continue
+		}
+		if rate.PostalCodePrefix == nil || *rate.PostalCodePrefix == *body.PostalCodePrefix {
+			return fmt.Errorf("a tax rate already covers %s", rate.Jurisdiction())
+		}
+	}
+	return nil
+}
+
+func (m *TaxModule) HandleGetTaxRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := m.Db.TaxRates.GetAll()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, rates)
+}
+
+func (m *TaxModule) HandlePostTaxRate(w http.ResponseWriter, r *http.Request) {
+	var body taxRateRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	err = m.checkRuleFree(body, 0)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	taxRate := &data.TaxRate{
+		Name:             body.Name,
+		State:            body.State,
+		PostalCodePrefix: body.PostalCodePrefix,
+		RatePercent:      body.RatePercent,
+	}
+
+	err = m.Db.TaxRates.Insert(taxRate)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusCreated, taxRate)
+}
+
+// HandlePutTaxRate replaces a rule. Orders already placed keep the tax locked
+// on them.
+func (m *TaxModule) HandlePutTaxRate(w http.ResponseWriter, r *http.Request) {
+	var body taxRateRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	taxRate, ok := m.getTaxRate(w, r)
+	if !ok {
+		return
+	}
+
+	err = m.checkRuleFree(body, taxRate.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	taxRate.Name = body.Name
+	taxRate.State = body.State
+	taxRate.PostalCodePrefix = body.PostalCodePrefix
+	taxRate.RatePercent = body.RatePercent
+
+	err = m.Db.TaxRates.Update(taxRate)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, taxRate)
+}
+
+func (m *TaxModule) HandleDeleteTaxRate(w http.ResponseWriter, r *http.Request) {
+	taxRate, ok := m.getTaxRate(w, r)
+	if !ok {
+		return
+	}
+
+	err := m.Db.TaxRates.Delete(taxRate.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
+
+// HandlePutTaxExemption records or clears a dealership's exemption
+// certificate. An exemption needs a certificate number; it covers orders placed
+// through the end of its expiry date.
+func (m *TaxModule) HandlePutTaxExemption(w http.ResponseWriter, r *http.Request) {
+	dealershipUUID := r.PathValue("uuid")
+
+	err := m.Validate.Var(dealershipUUID, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	var body struct {
+		Exempt      bool    `json:"exempt"`
+		Certificate *string `json:"certificate" validate:"required_if=Exempt true,omitempty,min=1,max=255"`
+		ExpiresAt   *string `json:"expires_at" validate:"omitempty,datetime=2006-01-02"`
+	}
+
+	err = m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	dealership, found, err := m.Db.Dealerships.GetByUUID(dealershipUUID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return
+	}
+
+	exemption := data.TaxExemption{
+		Exempt:      body.Exempt,
+		Certificate: body.Certificate,
+	}
+	if body.ExpiresAt != nil {
+		expiresAt, parseErr := time.Parse(time.DateOnly, *body.ExpiresAt)
+		if parseErr != nil {
+			m.WriteError(w, r, m.Err.BadRequest, parseErr)
+			return
+		}
+		exemption.ExpiresAt = &expiresAt
+	}
+	dealership.TaxExemption = exemption
+
+	err = m.Db.Dealerships.UpdateTaxExemption(dealership)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, dealership)
+}
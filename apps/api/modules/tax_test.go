@@ -0,0 +1,140 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTax_PlaceOrderChargesMostSpecificRate(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-TAX-0001")
+
+	for _, rate := range []map[string]any{
+		{"name": "State", "state": "TS", "rate_percent": 5},
+		{"name": "City", "state": "TS", "postal_code_prefix": "123", "rate_percent": 7.5},
+		{"name": "Elsewhere", "state": "TS", "postal_code_prefix": "999", "rate_percent": 9},
+	} {
+		resp := ctx.request(testRequest{
+			method: http.MethodPost,
+			path:   "/api/tax-rates",
+			token:  internalToken,
+			body:   rate,
+		})
+		require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+	}
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/tax-rates",
+		token:  internalToken,
+		body:   map[string]any{"name": "Duplicate", "state": "ts", "rate_percent": 6},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "a second state-wide rule would be ambiguous")
+
+	project := seedDraftProject(t, ctx, dealershipUser.DealershipID, "Taxed Project")
+	inlay := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Dove")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{inlay.UUID}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	ordered, found, err := ctx.db.Projects.GetByID(project.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NotNil(t, ordered.TaxCents)
+	assert.Equal(t, 750, *ordered.TaxCents)
+	require.NotNil(t, ordered.TaxJurisdiction)
+	assert.Equal(t, "TS 123", *ordered.TaxJurisdiction)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/invoice", project.UUID),
+		token:  internalToken,
+		body:   map[string]any{"invoice_url": "https://example.com/invoice.pdf"},
+	})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+
+	var invoice data.Invoice
+	require.NoError(t, json.Unmarshal(resp.body, &invoice))
+	assert.Equal(t, 10000, invoice.SubtotalCents)
+	assert.Equal(t, 750, invoice.TaxCents)
+	assert.Equal(t, 10750, invoice.TotalCents)
+
+	dashboard, err := ctx.db.Dashboard.GetDealershipDashboard(dealershipUser.DealershipID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10750), dashboard.OutstandingInvoiceAmountCents)
+}
+
+func TestTax_ExemptDealershipIsNotCharged(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	dealershipUser, dealershipToken, _, internalToken := seedTestData(t, ctx)
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-TAX-0002")
+
+	require.NoError(t, ctx.db.TaxRates.Insert(&data.TaxRate{Name: "State", State: "TS", RatePercent: 5}))
+
+	dealership, found, err := ctx.db.Dealerships.GetByID(dealershipUser.DealershipID)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	path := fmt.Sprintf("/api/dealership/%s/tax-exemption", dealership.UUID)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPut,
+		path:   path,
+		token:  internalToken,
+		body:   map[string]any{"exempt": true},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, "an exemption needs a certificate")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPut,
+		path:   path,
+		token:  dealershipToken,
+		body:   map[string]any{"exempt": true, "certificate": "RESALE-1"},
+	})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "dealerships cannot exempt themselves")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPut,
+		path:   path,
+		token:  internalToken,
+		body:   map[string]any{"exempt": true, "certificate": "RESALE-1", "expires_at": "2099-12-31"},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	project := seedDraftProject(t, ctx, dealershipUser.DealershipID, "Exempt Project")
+	inlay := seedDraftCatalogInlay(t, ctx, project.ID, item.ID, "Dove")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/project/%s/place-order", project.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"inlay_uuids": []string{inlay.UUID}},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	ordered, found, err := ctx.db.Projects.GetByID(project.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NotNil(t, ordered.TaxCents)
+	assert.Equal(t, 0, *ordered.TaxCents)
+	require.NotNil(t, ordered.TaxExemptCertificate)
+	assert.Equal(t, "RESALE-1", *ordered.TaxExemptCertificate)
+}
@@ -0,0 +1,96 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedThemeableCatalogItem(t *testing.T, ctx *testContext) *data.CatalogItem {
+	priceGroup := seedPriceGroup(t, ctx, "Standard")
+	item := seedCatalogItem(t, ctx, priceGroup.ID, "A-THM-0001")
+	item.Manifest = map[string]interface{}{
+		"view_box":     "0 0 3000 3000",
+		"grout_region": map[string]interface{}{"grout_id": nil, "piece_ids": []string{}, "count": 0},
+		"glass_regions": map[string]interface{}{
+			"group-0": map[string]interface{}{"glass_color_id": nil, "piece_ids": []string{"p0"}, "count": 1, "source_hex": "#202020"},
+			"group-1": map[string]interface{}{"glass_color_id": nil, "piece_ids": []string{"p1"}, "count": 1, "source_hex": "#e0e0e0"},
+		},
+	}
+	require.NoError(t, ctx.db.CatalogItems.Update(item))
+	return item
+}
+
+func TestPostTheme_FamilyKeepsLightnessOrder(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, dealershipToken, _, _ := seedTestData(t, ctx)
+	item := seedThemeableCatalogItem(t, ctx)
+	navy := seedGlassColor(t, ctx, "Navy", "#0a1a50", "Blues")
+	sky := seedGlassColor(t, ctx, "Sky", "#a8c8f0", "Blues")
+	seedGlassColor(t, ctx, "Ruby", "#c02020", "Reds")
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/catalog/%s/theme", item.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"family": "blues"},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var body struct {
+		ColorOverrides struct {
+			Groups map[string]struct {
+				GlassColorID int `json:"glass_color_id"`
+			} `json:"groups"`
+		} `json:"color_overrides"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &body))
+	assert.Equal(t, navy.ID, body.ColorOverrides.Groups["group-0"].GlassColorID)
+	assert.Equal(t, sky.ID, body.ColorOverrides.Groups["group-1"].GlassColorID)
+}
+
+func TestPostTheme_SkipsUnavailableColors(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, dealershipToken, _, _ := seedTestData(t, ctx)
+	item := seedThemeableCatalogItem(t, ctx)
+	navy := seedGlassColor(t, ctx, "Navy", "#0a1a50", "Blues")
+	restock := time.Now().Add(72 * time.Hour)
+	navy.UnavailableUntil = &restock
+	require.NoError(t, ctx.db.GlassColors.Update(navy))
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/api/catalog/%s/theme", item.UUID),
+		token:  dealershipToken,
+		body:   map[string]any{"seed_hex": "#2e5fb0"},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, string(resp.body))
+}
+
+func TestPostTheme_RequiresExactlyOneSource(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, dealershipToken, _, _ := seedTestData(t, ctx)
+	item := seedThemeableCatalogItem(t, ctx)
+
+	for _, body := range []map[string]any{{}, {"family": "Blues", "seed_hex": "#2e5fb0"}} {
+		resp := ctx.request(testRequest{
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/api/catalog/%s/theme", item.UUID),
+			token:  dealershipToken,
+			body:   body,
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.statusCode, string(resp.body))
+	}
+}
@@ -0,0 +1,203 @@
+package modules
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cookieRequest sends a request carrying a cookie, for the sign-in steps that
+// happen before there is an access token.
+func cookieRequest(t *testing.T, ctx *testContext, method, path, cookie string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if cookie != "" {
+		req.Header.Set("Cookie", cookie)
+	}
+
+	w := httptest.NewRecorder()
+	ctx.handler.ServeHTTP(w, req)
+	return w
+}
+
+func responseCookie(w *httptest.ResponseRecorder, name string) string {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == name && cookie.MaxAge >= 0 {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// magicLinkSignIn follows a magic link for an internal user and returns the
+// response to the callback.
+func magicLinkSignIn(t *testing.T, ctx *testContext, user *data.InternalUser) *httptest.ResponseRecorder {
+	t.Helper()
+
+	token, err := ctx.db.InternalTokens.New(user.ID, 15*time.Minute, data.InternalScopeLogin)
+	require.NoError(t, err)
+
+	return cookieRequest(t, ctx, http.MethodGet, "/api/auth/magic-link/callback?token="+token.Plaintext, "", nil)
+}
+
+func currentCode(t *testing.T, secret string) string {
+	t.Helper()
+
+	code, err := data.TOTPCode(secret, data.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+func TestTwoFactor_PolicyForcesEnrolment(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, _, internalUser, internalToken := seedTestData(t, ctx)
+
+	resp := ctx.request(testRequest{
+		method: http.MethodPut,
+		path:   "/api/two-factor-policy",
+		token:  internalToken,
+		body:   map[string]any{"require_for_privileged_roles": true},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	resp = ctx.request(testRequest{method: http.MethodGet, path: "/api/user/self", token: internalToken})
+	assert.Equal(t, http.StatusUnauthorized, resp.statusCode, "admins without an authenticator are signed out")
+
+	w := magicLinkSignIn(t, ctx, internalUser)
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	assert.True(t, strings.HasSuffix(w.Header().Get("Location"), "/two-factor"))
+	assert.Empty(t, responseCookie(w, "refresh_token"), "no session until the second step")
+
+	challenge := "two_factor_token=" + responseCookie(w, "two_factor_token")
+
+	w = cookieRequest(t, ctx, http.MethodGet, "/api/auth/two-factor/challenge", challenge, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"enrolled":false`)
+
+	w = cookieRequest(t, ctx, http.MethodPost, "/api/auth/two-factor/challenge/enroll", challenge, nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var enrolment struct {
+		Secret     string `json:"secret"`
+		OTPAuthURL string `json:"otpauth_url"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &enrolment))
+	assert.True(t, strings.HasPrefix(enrolment.OTPAuthURL, "otpauth://totp/"))
+
+	w = cookieRequest(t, ctx, http.MethodPost, "/api/auth/two-factor/challenge/verify", challenge, map[string]any{
+		"code": currentCode(t, enrolment.Secret),
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var verified struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &verified))
+	assert.Len(t, verified.RecoveryCodes, data.RecoveryCodeCount)
+
+	refreshToken := responseCookie(w, "refresh_token")
+	require.NotEmpty(t, refreshToken, "the first code finishes signing in")
+
+	w = cookieRequest(t, ctx, http.MethodGet, "/api/auth/two-factor/challenge", challenge, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "the challenge is used up")
+
+	status, accessToken, _ := refreshAccess(t, ctx, refreshToken)
+	require.Equal(t, http.StatusCreated, status)
+
+	resp = ctx.request(testRequest{
+		method: http.MethodDelete,
+		path:   "/api/auth/two-factor",
+		token:  accessToken,
+		body:   map[string]any{"recovery_code": verified.RecoveryCodes[0]},
+	})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "the policy keeps it on")
+}
+
+func TestTwoFactor_OptionalEnrolmentAndRecovery(t *testing.T) {
+	ctx, teardown := setupTestApp(t)
+	defer teardown()
+
+	_, dealershipToken, internalUser, internalToken := seedTestData(t, ctx)
+
+	resp := ctx.request(testRequest{method: http.MethodPost, path: "/api/auth/two-factor/enroll", token: dealershipToken})
+	assert.Equal(t, http.StatusForbidden, resp.statusCode, "two-factor is for internal users")
+
+	w := magicLinkSignIn(t, ctx, internalUser)
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.NotEmpty(t, responseCookie(w, "refresh_token"), "without an authenticator or policy sign-in is one step")
+
+	resp = ctx.request(testRequest{method: http.MethodPost, path: "/api/auth/two-factor/enroll", token: internalToken})
+	require.Equal(t, http.StatusCreated, resp.statusCode, string(resp.body))
+	secret := resp.parsed.(map[string]any)["secret"].(string)
+
+	code := currentCode(t, secret)
+	resp = ctx.request(testRequest{
+		method: http.MethodPost,
+		path:   "/api/auth/two-factor/confirm",
+		token:  internalToken,
+		body:   map[string]any{"code": code},
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	var confirmed struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	require.NoError(t, json.Unmarshal(resp.body, &confirmed))
+	require.Len(t, confirmed.RecoveryCodes, data.RecoveryCodeCount)
+
+	w = magicLinkSignIn(t, ctx, internalUser)
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Empty(t, responseCookie(w, "refresh_token"), "now the code is asked for")
+	challenge := "two_factor_token=" + responseCookie(w, "two_factor_token")
+
+	w = cookieRequest(t, ctx, http.MethodPost, "/api/auth/two-factor/challenge/verify", challenge, map[string]any{"code": code})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "a code only works once")
+
+	w = cookieRequest(t, ctx, http.MethodPost, "/api/auth/two-factor/challenge/verify", challenge, map[string]any{
+		"recovery_code": confirmed.RecoveryCodes[0],
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotEmpty(t, responseCookie(w, "refresh_token"))
+
+	w = magicLinkSignIn(t, ctx, internalUser)
+	challenge = "two_factor_token=" + responseCookie(w, "two_factor_token")
+
+	for range data.MaxTwoFactorAttempts {
+		w = cookieRequest(t, ctx, http.MethodPost, "/api/auth/two-factor/challenge/verify", challenge, map[string]any{
+			"recovery_code": confirmed.RecoveryCodes[0],
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code, "a recovery code only works once")
+	}
+
+	w = cookieRequest(t, ctx, http.MethodPost, "/api/auth/two-factor/challenge/verify", challenge, map[string]any{
+		"recovery_code": confirmed.RecoveryCodes[1],
+	})
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "too many wrong codes end the sign-in")
+
+	resp = ctx.request(testRequest{
+		method: http.MethodDelete,
+		path:   "/api/internal-user/" + internalUser.UUID + "/two-factor",
+		token:  internalToken,
+	})
+	require.Equal(t, http.StatusOK, resp.statusCode, string(resp.body))
+
+	w = magicLinkSignIn(t, ctx, internalUser)
+	assert.NotEmpty(t, responseCookie(w, "refresh_token"), "an admin reset turns it off")
+}
@@ -1,13 +1,17 @@
 package upload
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	"github.com/Lil-Strudel/glassact-studios/apps/api/svg"
 )
 
 type UploadModule struct {
@@ -57,6 +61,30 @@ func (m UploadModule) HandlePostUpload(w http.ResponseWriter, r *http.Request) {
 		uploadPath = "uploads"
 	}
 
+	var body io.Reader = file
+	size := header.Size
+
+	// SVGs are served back and embedded inline, so they are sanitized before
+	// they are stored. The declared content type is client-controlled, hence
+	// the extension check as well.
+	if isSVGUpload(contentType, header.Filename) {
+		raw, err := io.ReadAll(file)
+		if err != nil {
+			m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("failed to read file: %w", err))
+			return
+		}
+
+		clean, err := svg.Sanitize(raw)
+		if err != nil {
+			m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("invalid svg: %w", err))
+			return
+		}
+
+		body = bytes.NewReader(clean)
+		size = int64(len(clean))
+		contentType = "image/svg+xml"
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -64,9 +92,9 @@ func (m UploadModule) HandlePostUpload(w http.ResponseWriter, r *http.Request) {
 		ctx,
 		m.S3,
 		m.Cfg,
-		file,
+		body,
 		header.Filename,
-		header.Size,
+		size,
 		contentType,
 		uploadPath,
 	)
@@ -78,6 +106,11 @@ func (m UploadModule) HandlePostUpload(w http.ResponseWriter, r *http.Request) {
 	m.WriteJSON(w, r, http.StatusOK, result)
 }
 
+func isSVGUpload(contentType, filename string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	return mediaType == "image/svg+xml" || strings.EqualFold(filepath.Ext(filename), ".svg")
+}
+
 func (m UploadModule) HandleGetFile(w http.ResponseWriter, r *http.Request) {
 	path := r.PathValue("path")
 	if path == "" {
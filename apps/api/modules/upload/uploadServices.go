@@ -2,6 +2,7 @@ package upload
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -9,11 +10,20 @@ import (
 	"time"
 
 	"github.com/Lil-Strudel/glassact-studios/apps/api/config"
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrOrderedDesignNotFound means the proof or catalog item an inlay was
+	// ordered with no longer exists.
+	ErrOrderedDesignNotFound = errors.New("ordered design not found")
+	ErrNoDesign              = errors.New("inlay has no design asset")
+	ErrDesignNotSVG          = errors.New("design asset is not an SVG")
+)
+
 type UploadResult struct {
 	URL         string `json:"url"`
 	Filename    string `json:"filename"`
@@ -108,6 +118,47 @@ func GetFileFromS3(
 	return data, nil
 }
 
+// GetOrderedDesign fetches the SVG an inlay was ordered with: the approved
+// proof on its order snapshot, or the catalog item's for stock inlays.
+func GetOrderedDesign(
+	ctx context.Context,
+	db data.Models,
+	s3Client *s3.Client,
+	cfg *config.Config,
+	inlay *data.Inlay,
+	snapshot *data.OrderSnapshot,
+) ([]byte, error) {
+	var designURL string
+	if snapshot.ProofID != nil {
+		proof, found, err := db.InlayProofs.GetByID(*snapshot.ProofID)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, ErrOrderedDesignNotFound
+		}
+		designURL = proof.DesignAssetURL
+	} else if inlay.CatalogInfo != nil {
+		item, found, err := db.CatalogItems.GetByID(inlay.CatalogInfo.CatalogItemID)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, ErrOrderedDesignNotFound
+		}
+		designURL = item.SvgURL
+	}
+
+	if designURL == "" {
+		return nil, ErrNoDesign
+	}
+	if !strings.HasSuffix(strings.ToLower(designURL), ".svg") {
+		return nil, ErrDesignNotSVG
+	}
+
+	return GetFileFromS3(ctx, s3Client, cfg, strings.TrimPrefix(designURL, "/"))
+}
+
 func GenerateSignedURL(
 	ctx context.Context,
 	s3Client *s3.Client,
@@ -0,0 +1,474 @@
+package user
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/apps/api/app"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+	"github.com/google/uuid"
+)
+
+// importRequest carries the CSV files as text. Either may be left empty, so
+// users can be added to existing dealerships on their own.
+type importRequest struct {
+	DealershipsCSV  string `json:"dealerships_csv"`
+	UsersCSV        string `json:"users_csv"`
+	DryRun          bool   `json:"dry_run"`
+	SendInvitations bool   `json:"send_invitations"`
+}
+
+var dealershipImportColumns = []string{
+	"ref", "name", "phone", "payment_timing", "sandblast_file_format",
+	"street", "street_ext", "city", "state", "postal_code", "country",
+	"latitude", "longitude",
+}
+
+var userImportColumns = []string{"dealership", "name", "email", "role", "avatar"}
+
+// importGeocodeTimeout bounds geocoding a whole import, leaving the rest of the
+// request's write timeout to check the users and apply it.
+const importGeocodeTimeout = 5 * time.Second
+
+// importGeocodeWorkers is how many addresses are geocoded at once.
+const importGeocodeWorkers = 8
+
+// dealershipImportRow is a line of the dealerships CSV. Ref is how the users
+// CSV names the dealership. Latitude and longitude can be left blank when a
+// geocoder is configured.
+type dealershipImportRow struct {
+	Ref                 string
+	Name                string `validate:"required"`
+	Phone               string `validate:"omitempty,numeric,len=10"`
+	PaymentTiming       string `validate:"required,oneof=pre-manufacturing pre-shipping post-shipping"`
+	SandblastFileFormat string `validate:"required,oneof=pdf svg png dxf"`
+	Street              string `validate:"required"`
+	StreetExt           string
+	City                string `validate:"required"`
+	State               string `validate:"required"`
+	PostalCode          string `validate:"required"`
+	Country             string `validate:"required,iso3166_1_alpha2"`
+	Latitude            string `validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude           string `validate:"required_with=Latitude,omitempty,longitude"`
+}
+
+// userImportRow is a line of the users CSV. Dealership is either the ref of a
+// row in the dealerships CSV or the uuid of an existing dealership.
+type userImportRow struct {
+	Dealership string `validate:"required"`
+	Name       string `validate:"required"`
+	Email      string `validate:"required,email"`
+	Role       string `validate:"required"`
+	Avatar     string `validate:"omitempty,url"`
+}
+
+// importRowResult is one CSV line in the report. Row counts the header as
+// line 1, so it matches what a spreadsheet shows.
+type importRowResult struct {
+	Row    int      `json:"row"`
+	Errors []string `json:"errors"`
+}
+
+type dealershipImportResult struct {
+	importRowResult
+	Ref        string           `json:"ref"`
+	Dealership *data.Dealership `json:"dealership"`
+}
+
+type userImportResult struct {
+	importRowResult
+	User    *data.DealershipUser `json:"user"`
+	Invited bool                 `json:"invited"`
+	joining string
+}
+
+// importReport is the outcome of an import. ErrorCount is how many rows have
+// errors; any at all and nothing is applied.
+type importReport struct {
+	DryRun      bool                      `json:"dry_run"`
+	Applied     bool                      `json:"applied"`
+	ErrorCount  int                       `json:"error_count"`
+	Dealerships []*dealershipImportResult `json:"dealerships"`
+	Users       []*userImportResult       `json:"users"`
+}
+
+// readImportCSV reads a CSV with a header row into one map per line, keyed by
+// lower-cased column name. Columns may come in any order; unknown ones are
+// refused so a typo does not silently drop a field.
+func readImportCSV(text string, columns []string) ([]map[string]string, error) {
+	text = strings.TrimPrefix(text, "\ufeff")
+	if strings.TrimSpace(text) == "" {
+		return []map[string]string{}, nil
+	}
+
+	records, err := csv.NewReader(strings.NewReader(text)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	header := records[0]
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(columns, header[i]) {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, value := range record {
+			row[header[i]] = strings.TrimSpace(value)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// importDealership checks a dealerships CSV line and, if it is valid, returns
+// the dealership to create. located is false when the line left latitude and
+// longitude blank for geocodeImport to fill in.
+func (m *UserModule) importDealership(values map[string]string) (dealership *data.Dealership, located bool, problems []string) {
+	row := dealershipImportRow{
+		Ref:                 values["ref"],
+		Name:                values["name"],
+		Phone:               values["phone"],
+		PaymentTiming:       strings.ToLower(values["payment_timing"]),
+		SandblastFileFormat: strings.ToLower(values["sandblast_file_format"]),
+		Street:              values["street"],
+		StreetExt:           values["street_ext"],
+		City:                values["city"],
+		State:               values["state"],
+		PostalCode:          values["postal_code"],
+		Country:             strings.ToUpper(values["country"]),
+		Latitude:            values["latitude"],
+		Longitude:           values["longitude"],
+	}
+
+	err := m.Validate.Struct(row)
+	if err != nil {
+		return nil, false, app.ValidationMessages(err)
+	}
+
+	dealership = &data.Dealership{
+		Name:                row.Name,
+		Phone:               row.Phone,
+		PaymentTiming:       data.PaymentTiming(row.PaymentTiming),
+		SandblastFileFormat: data.SandblastFileFormat(row.SandblastFileFormat),
+		Address: data.Address{
+			Street:     row.Street,
+			StreetExt:  row.StreetExt,
+			City:       row.City,
+			State:      row.State,
+			PostalCode: row.PostalCode,
+			Country:    row.Country,
+		},
+	}
+
+	if row.Latitude != "" {
+		dealership.Address.Latitude, _ = strconv.ParseFloat(row.Latitude, 64)
+		dealership.Address.Longitude, _ = strconv.ParseFloat(row.Longitude, 64)
+		return dealership, true, nil
+	}
+
+	if m.Geocoder == nil {
+		return nil, false, []string{`"latitude" and "longitude" are required`}
+	}
+
+	return dealership, false, nil
+}
+
+// geocodeImport fills in the coordinates of dealerships whose lines left them
+// blank. Addresses are geocoded several at a time and all within
+// importGeocodeTimeout, so a large import still answers in time; problems[i]
+// is what went wrong with dealerships[i], if anything.
+func (m *UserModule) geocodeImport(ctx context.Context, dealerships []*data.Dealership) (problems []string) {
+	ctx, cancel := context.WithTimeout(ctx, importGeocodeTimeout)
+	defer cancel()
+
+	problems = make([]string, len(dealerships))
+	workers := make(chan struct{}, importGeocodeWorkers)
+
+	var wg sync.WaitGroup
+	for i, dealership := range dealerships {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			latitude, longitude, err := m.Geocoder.Geocode(ctx, dealership.Address)
+			switch {
+			case errors.Is(err, app.ErrAddressNotFound):
+				problems[i] = "the address could not be found"
+			case err != nil:
+				m.Log.Error("failed to geocode imported dealership", "error", err)
+				problems[i] = "the address could not be geocoded, fill in latitude and longitude"
+			default:
+				dealership.Address.Latitude = latitude
+				dealership.Address.Longitude = longitude
+			}
+		}()
+	}
+	wg.Wait()
+
+	return problems
+}
+
+// HandlePostDealershipImport creates dealerships and their users from CSV.
+// Every row is checked first and the report lists what is wrong with each;
+// only a clean import is applied, all at once. Users are created inactive, and
+// invited when send_invitations is set. Those left uninvited can be sent one
+// later with the resend endpoint.
+func (m *UserModule) HandlePostDealershipImport(w http.ResponseWriter, r *http.Request) {
+	var body importRequest
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	dealershipRows, err := readImportCSV(body.DealershipsCSV, dealershipImportColumns)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("dealerships_csv: %w", err))
+		return
+	}
+
+	userRows, err := readImportCSV(body.UsersCSV, userImportColumns)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("users_csv: %w", err))
+		return
+	}
+
+	if len(dealershipRows) == 0 && len(userRows) == 0 {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("there is nothing to import"))
+		return
+	}
+
+	report := &importReport{
+		DryRun:      body.DryRun,
+		Dealerships: make([]*dealershipImportResult, len(dealershipRows)),
+		Users:       make([]*userImportResult, len(userRows)),
+	}
+	batch := &data.BulkImport{}
+
+	// refs maps a lower-cased ref to its row in the report, and newDealerships
+	// a row to its index in the batch when it is valid.
+	refs := map[string]*dealershipImportResult{}
+	newDealerships := map[*dealershipImportResult]int{}
+
+	dealerships := make([]*data.Dealership, len(dealershipRows))
+	var unlocated []int
+
+	for i, values := range dealershipRows {
+		result := &dealershipImportResult{
+			importRowResult: importRowResult{Row: i + 2, Errors: []string{}},
+			Ref:             values["ref"],
+		}
+		report.Dealerships[i] = result
+
+		dealership, located, problems := m.importDealership(values)
+		result.Errors = append(result.Errors, problems...)
+		dealerships[i] = dealership
+		if dealership != nil && !located {
+			unlocated = append(unlocated, i)
+		}
+
+		if result.Ref != "" {
+			key := strings.ToLower(result.Ref)
+			if other, ok := refs[key]; ok {
+				result.Errors = append(result.Errors, fmt.Sprintf("ref %q is also used on row %d", result.Ref, other.Row))
+			} else {
+				refs[key] = result
+			}
+		}
+	}
+
+	if len(unlocated) > 0 {
+		toGeocode := make([]*data.Dealership, len(unlocated))
+		for j, i := range unlocated {
+			toGeocode[j] = dealerships[i]
+		}
+
+		for j, problem := range m.geocodeImport(r.Context(), toGeocode) {
+			if problem != "" {
+				result := report.Dealerships[unlocated[j]]
+				result.Errors = append(result.Errors, problem)
+			}
+		}
+	}
+
+	for i, result := range report.Dealerships {
+		if len(result.Errors) == 0 {
+			result.Dealership = dealerships[i]
+			newDealerships[result] = len(batch.Dealerships)
+			batch.Dealerships = append(batch.Dealerships, dealerships[i])
+		}
+	}
+
+	existingDealerships := map[string]*data.Dealership{}
+	roles := map[string]bool{}
+	emails := map[string]*userImportResult{}
+
+	for i, values := range userRows {
+		result := &userImportResult{
+			importRowResult: importRowResult{Row: i + 2, Errors: []string{}},
+		}
+		report.Users[i] = result
+
+		row := userImportRow{
+			Dealership: values["dealership"],
+			Name:       values["name"],
+			Email:      values["email"],
+			Role:       strings.ToLower(values["role"]),
+			Avatar:     values["avatar"],
+		}
+
+		err := m.Validate.Struct(row)
+		if err != nil {
+			result.Errors = append(result.Errors, app.ValidationMessages(err)...)
+			continue
+		}
+
+		imported := &data.BulkImportUser{
+			User: &data.DealershipUser{
+				Name:     row.Name,
+				Email:    row.Email,
+				Avatar:   row.Avatar,
+				Role:     data.DealershipUserRole(row.Role),
+				IsActive: false,
+			},
+		}
+
+		if ref, ok := refs[strings.ToLower(row.Dealership)]; ok {
+			index, valid := newDealerships[ref]
+			if valid {
+				imported.NewDealership = &index
+				result.joining = batch.Dealerships[index].Name
+			} else {
+				result.Errors = append(result.Errors, fmt.Sprintf("dealership %q on row %d has errors", row.Dealership, ref.Row))
+			}
+		} else if _, err := uuid.Parse(row.Dealership); err == nil {
+			dealership, ok := existingDealerships[row.Dealership]
+			if !ok {
+				dealership, _, err = m.Db.Dealerships.GetByUUID(row.Dealership)
+				if err != nil {
+					m.WriteError(w, r, m.Err.ServerError, err)
+					return
+				}
+				existingDealerships[row.Dealership] = dealership
+			}
+
+			if dealership == nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("dealership %q does not exist", row.Dealership))
+			} else {
+				imported.User.DealershipID = dealership.ID
+				result.joining = dealership.Name
+			}
+		} else {
+			result.Errors = append(result.Errors, fmt.Sprintf("no dealership has ref %q", row.Dealership))
+		}
+
+		exists, ok := roles[row.Role]
+		if !ok {
+			_, exists, err = m.Db.DealershipRoles.GetByKey(row.Role)
+			if err != nil {
+				m.WriteError(w, r, m.Err.ServerError, err)
+				return
+			}
+			roles[row.Role] = exists
+		}
+		if !exists {
+			result.Errors = append(result.Errors, fmt.Sprintf("role %q does not exist", row.Role))
+		}
+
+		key := strings.ToLower(row.Email)
+		if other, ok := emails[key]; ok {
+			result.Errors = append(result.Errors, fmt.Sprintf("email is also used on row %d", other.Row))
+		} else {
+			emails[key] = result
+		}
+
+		if len(result.Errors) == 0 {
+			result.User = imported.User
+			batch.Users = append(batch.Users, imported)
+		}
+	}
+
+	candidates := make([]string, 0, len(batch.Users))
+	for _, imported := range batch.Users {
+		candidates = append(candidates, imported.User.Email)
+	}
+
+	existing, err := m.Db.DealershipUsers.GetExistingEmails(candidates)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	for _, email := range existing {
+		result := emails[strings.ToLower(email)]
+		result.Errors = append(result.Errors, "a user with this email already exists")
+		result.User = nil
+	}
+
+	for _, result := range report.Dealerships {
+		if len(result.Errors) > 0 {
+			report.ErrorCount++
+		}
+	}
+	for _, result := range report.Users {
+		if len(result.Errors) > 0 {
+			report.ErrorCount++
+		}
+	}
+
+	if report.ErrorCount > 0 {
+		status := http.StatusUnprocessableEntity
+		if body.DryRun {
+			status = http.StatusOK
+		}
+		m.WriteJSON(w, r, status, report)
+		return
+	}
+
+	if body.DryRun {
+		m.WriteJSON(w, r, http.StatusOK, report)
+		return
+	}
+
+	if body.SendInvitations {
+		batch.Inviter = m.ContextGetUser(r)
+	}
+
+	err = m.Db.ApplyImport(batch)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+	report.Applied = true
+
+	results := map[*data.DealershipUser]*userImportResult{}
+	for _, result := range report.Users {
+		results[result.User] = result
+	}
+	for _, imported := range batch.Users {
+		if imported.Invitation == nil {
+			continue
+		}
+
+		result := results[imported.User]
+		m.sendInvitation(imported.Invitation, imported.User.Name, result.joining)
+		result.Invited = true
+	}
+
+	m.WriteJSON(w, r, http.StatusCreated, report)
+}
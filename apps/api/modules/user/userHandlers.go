@@ -1,6 +1,7 @@
 package user
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -19,10 +20,21 @@ func NewUserModule(app *app.Application) *UserModule {
 	}
 }
 
+// HandleGetUserSelf returns the signed-in user. For dealership users it also
+// says whether an admin is viewing as them, so the app can show it.
 func (m *UserModule) HandleGetUserSelf(w http.ResponseWriter, r *http.Request) {
 	user := m.ContextGetUser(r)
 
-	m.WriteJSON(w, r, http.StatusOK, user)
+	dealershipUser, ok := user.(*data.DealershipUser)
+	if !ok {
+		m.WriteJSON(w, r, http.StatusOK, user)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, struct {
+		*data.DealershipUser
+		Impersonation *data.Impersonation `json:"impersonation"`
+	}{dealershipUser, m.ContextGetImpersonation(r)})
 }
 
 func (m *UserModule) HandleGetUsers(w http.ResponseWriter, r *http.Request) {
@@ -155,13 +167,19 @@ func (m *UserModule) HandleCreateDealershipUser(w http.ResponseWriter, r *http.R
 		dealershipID = body.DealershipID
 	}
 
+	if !m.roleExists(w, r, m.Db.DealershipRoles, string(body.Role)) {
+		return
+	}
+
+	// is_active is still accepted but ignored: new users stay inactive until
+	// they accept the invitation emailed to them below.
 	user := data.DealershipUser{
 		Name:         body.Name,
 		Email:        body.Email,
 		Avatar:       body.Avatar,
 		DealershipID: dealershipID,
 		Role:         body.Role,
-		IsActive:     body.IsActive,
+		IsActive:     false,
 	}
 
 	err = m.Db.DealershipUsers.Insert(&user)
@@ -170,6 +188,18 @@ func (m *UserModule) HandleCreateDealershipUser(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	joining, err := m.dealershipName(dealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	_, err = m.invite(r, &user, joining)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
 	m.WriteJSON(w, r, http.StatusCreated, user)
 }
 
@@ -221,6 +251,9 @@ func (m *UserModule) HandleUpdateDealershipUser(w http.ResponseWriter, r *http.R
 		user.Avatar = body.Avatar
 	}
 	if body.Role != "" {
+		if !m.roleExists(w, r, m.Db.DealershipRoles, string(body.Role)) {
+			return
+		}
 		user.Role = body.Role
 	}
 	if body.IsActive != nil {
@@ -233,6 +266,16 @@ func (m *UserModule) HandleUpdateDealershipUser(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// A deactivated user is signed out everywhere rather than at their next
+	// refresh.
+	if !user.IsActive {
+		err = data.RevokeAllSessions(&m.Db, user)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+	}
+
 	m.WriteJSON(w, r, http.StatusOK, user)
 }
 
@@ -267,6 +310,12 @@ func (m *UserModule) HandleDeleteDealershipUser(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	err = data.RevokeAllSessions(&m.Db, user)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
 	m.WriteJSON(w, r, http.StatusOK, user)
 }
 
@@ -318,12 +367,17 @@ func (m *UserModule) HandleCreateInternalUser(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if !m.roleExists(w, r, m.Db.InternalRoles, string(body.Role)) {
+		return
+	}
+
+	// is_active is still accepted but ignored, as for dealership users.
 	user := data.InternalUser{
 		Name:     body.Name,
 		Email:    body.Email,
 		Avatar:   body.Avatar,
 		Role:     body.Role,
-		IsActive: body.IsActive,
+		IsActive: false,
 	}
 
 	err = m.Db.InternalUsers.Insert(&user)
@@ -332,6 +386,12 @@ func (m *UserModule) HandleCreateInternalUser(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	_, err = m.invite(r, &user, internalTeamName)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
 	m.WriteJSON(w, r, http.StatusCreated, user)
 }
 
@@ -378,6 +438,9 @@ func (m *UserModule) HandleUpdateInternalUser(w http.ResponseWriter, r *http.Req
 		user.Avatar = body.Avatar
 	}
 	if body.Role != "" {
+		if !m.roleExists(w, r, m.Db.InternalRoles, string(body.Role)) {
+			return
+		}
 		user.Role = body.Role
 	}
 	if body.IsActive != nil {
@@ -390,6 +453,16 @@ func (m *UserModule) HandleUpdateInternalUser(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// A deactivated user is signed out everywhere rather than at their next
+	// refresh.
+	if !user.IsActive {
+		err = data.RevokeAllSessions(&m.Db, user)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+	}
+
 	m.WriteJSON(w, r, http.StatusOK, user)
 }
 
@@ -419,5 +492,239 @@ func (m *UserModule) HandleDeleteInternalUser(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	err = data.RevokeAllSessions(&m.Db, user)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
 	m.WriteJSON(w, r, http.StatusOK, user)
 }
+
+// internalTeamName is what internal users are invited to join.
+const internalTeamName = "the Glassact Studios team"
+
+func (m *UserModule) getManagedDealershipUser(w http.ResponseWriter, r *http.Request) (*data.DealershipUser, bool) {
+	uuid := r.PathValue("uuid")
+	err := m.Validate.Var(uuid, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	user, found, err := m.Db.DealershipUsers.GetByUUID(uuid)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	if !m.canManageDealershipUser(r, user) {
+		m.WriteError(w, r, m.Err.Forbidden, nil)
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (m *UserModule) HandleGetDealershipUserInvitation(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getManagedDealershipUser(w, r)
+	if !ok {
+		return
+	}
+
+	invitation, ok := m.invitationForUser(w, r, user)
+	if !ok {
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, invitation)
+}
+
+func (m *UserModule) HandleResendDealershipUserInvitation(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getManagedDealershipUser(w, r)
+	if !ok {
+		return
+	}
+
+	joining, err := m.dealershipName(user.DealershipID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.resendInvitation(w, r, user, joining)
+}
+
+func (m *UserModule) HandleRevokeDealershipUserInvitation(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getManagedDealershipUser(w, r)
+	if !ok {
+		return
+	}
+
+	m.revokeInvitation(w, r, user)
+}
+
+func (m *UserModule) getManagedInternalUser(w http.ResponseWriter, r *http.Request) (*data.InternalUser, bool) {
+	uuid := r.PathValue("uuid")
+	err := m.Validate.Var(uuid, "required,uuid4")
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return nil, false
+	}
+
+	user, found, err := m.Db.InternalUsers.GetByUUID(uuid)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (m *UserModule) HandleGetInternalUserInvitation(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getManagedInternalUser(w, r)
+	if !ok {
+		return
+	}
+
+	invitation, ok := m.invitationForUser(w, r, user)
+	if !ok {
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, invitation)
+}
+
+func (m *UserModule) HandleResendInternalUserInvitation(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getManagedInternalUser(w, r)
+	if !ok {
+		return
+	}
+
+	m.resendInvitation(w, r, user, internalTeamName)
+}
+
+func (m *UserModule) HandleRevokeInternalUserInvitation(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getManagedInternalUser(w, r)
+	if !ok {
+		return
+	}
+
+	m.revokeInvitation(w, r, user)
+}
+
+// HandleDeleteDealershipUserSessions signs a dealership user out everywhere.
+func (m *UserModule) HandleDeleteDealershipUserSessions(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getManagedDealershipUser(w, r)
+	if !ok {
+		return
+	}
+
+	err := data.RevokeAllSessions(&m.Db, user)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// HandleDeleteInternalUserSessions signs an internal user out everywhere.
+func (m *UserModule) HandleDeleteInternalUserSessions(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getManagedInternalUser(w, r)
+	if !ok {
+		return
+	}
+
+	err := data.RevokeAllSessions(&m.Db, user)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// HandleDeleteInternalUserTwoFactor removes an internal user's authenticator
+// and recovery codes, for when they have lost both. If the policy requires
+// two-factor authentication for their role they set up a new one at their
+// next sign-in.
+func (m *UserModule) HandleDeleteInternalUserTwoFactor(w http.ResponseWriter, r *http.Request) {
+	user, ok := m.getManagedInternalUser(w, r)
+	if !ok {
+		return
+	}
+
+	err := m.Db.TwoFactor.Disable(user.ID)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+func (m *UserModule) HandleGetTwoFactorPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := m.Db.TwoFactor.GetPolicy()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, policy)
+}
+
+// HandlePutTwoFactorPolicy turns the two-factor requirement for privileged
+// roles on or off. Turning it on signs out everyone it applies to who has not
+// set up an authenticator, so their next sign-in makes them enrol.
+func (m *UserModule) HandlePutTwoFactorPolicy(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		RequireForPrivilegedRoles *bool `json:"require_for_privileged_roles"`
+	}
+
+	err := m.ReadJSONBody(w, r, &body)
+	if err != nil {
+		m.WriteError(w, r, m.Err.BadRequest, err)
+		return
+	}
+
+	if body.RequireForPrivilegedRoles == nil {
+		m.WriteError(w, r, m.Err.BadRequest, errors.New("require_for_privileged_roles is required"))
+		return
+	}
+
+	policy, err := m.Db.TwoFactor.GetPolicy()
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	wasRequired := policy.RequireForPrivilegedRoles
+	policy.RequireForPrivilegedRoles = *body.RequireForPrivilegedRoles
+
+	err = m.Db.TwoFactor.UpdatePolicy(policy)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if policy.RequireForPrivilegedRoles && !wasRequired {
+		err = m.signOutUnenrolled(policy)
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, policy)
+}
@@ -1 +1,268 @@
 package user
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+// invite creates an invitation for a freshly inserted user and emails it.
+func (m *UserModule) invite(r *http.Request, user data.AuthUser, joining string) (*data.Invitation, error) {
+	invitation := data.NewInvitation(user, m.ContextGetUser(r))
+
+	err := m.Db.Invitations.Insert(invitation)
+	if err != nil {
+		return nil, err
+	}
+
+	m.sendInvitation(invitation, user.GetName(), joining)
+	return invitation, nil
+}
+
+// sendInvitation emails the invitation's current link in the background. A
+// failed send is logged; the admin can resend from the user's page.
+func (m *UserModule) sendInvitation(invitation *data.Invitation, name, joining string) {
+	links, err := m.invitationLinks(invitation.Plaintext)
+	if err != nil {
+		m.Log.Error("failed to build invitation links", "error", err, "invitation_id", invitation.ID)
+		return
+	}
+
+	textBody, htmlBody := generateInvitationEmail(name, joining, links)
+	email := invitation.Email
+	invitationID := invitation.ID
+
+	m.Wg.Add(1)
+	go func() {
+		defer m.Wg.Done()
+		if err := m.Mailer.Send(email, "You're invited to Glassact Studios", htmlBody, textBody); err != nil {
+			m.Log.Error("failed to send invitation email", "error", err, "invitation_id", invitationID)
+		}
+	}()
+}
+
+type invitationLinks struct {
+	MagicLink string
+	Google    string
+	Microsoft string
+}
+
+func (m *UserModule) invitationLinks(token string) (invitationLinks, error) {
+	u, err := url.Parse(m.Cfg.BaseURL)
+	if err != nil {
+		return invitationLinks{}, err
+	}
+
+	u.Path = path.Join(u.Path, "api", "auth", "invitation", "accept")
+
+	link := func(provider string) string {
+		q := url.Values{}
+		q.Set("token", token)
+		if provider != "" {
+			q.Set("provider", provider)
+		}
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	return invitationLinks{
+		MagicLink: link(""),
+		Google:    link("google"),
+		Microsoft: link("microsoft"),
+	}, nil
+}
+
+func generateInvitationEmail(name, joining string, links invitationLinks) (string, string) {
+	textBody := fmt.Sprintf(`Hi %s,
+
+You've been invited to join %s on Glassact Studios.
+
+Accept the invitation by email:
+%s
+
+Or accept with your Google account:
+%s
+
+Or accept with your Microsoft account:
+%s
+
+The invitation expires in 7 days. If you weren't expecting it, you can ignore this email.`, name, joining, links.MagicLink, links.Google, links.Microsoft)
+
+	htmlBody := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+    <title>Glassact Studios – Invitation</title>
+  </head>
+  <body style="margin:0; padding:0; background-color:#ffffff; font-family:Roboto, Arial, sans-serif; color:#0a0a0a;">
+    <table role="presentation" cellspacing="0" cellpadding="0" border="0" width="100%%">
+      <tr>
+        <td align="center" style="padding: 40px 0;">
+          <table role="presentation" cellspacing="0" cellpadding="0" border="0" width="100%%" style="max-width:600px; background:#ffffff; border-radius:8px; box-shadow:0 2px 4px rgba(0,0,0,0.1); padding:40px;">
+            <tr>
+              <td style="text-align:center;">
+                <h1 style="margin:0; font-size:24px; font-weight:600; color:#0a0a0a;">You're invited to Glassact Studios</h1>
+                <p style="margin:20px 0; font-size:16px; color:#737373;">Hi %s, you've been invited to join %s. Accept to activate your account:</p>
+                <a href="%s" style="display:inline-block; padding:12px 24px; background-color:#8b0f24; color:#ffffff; text-decoration:none; border-radius:8px; font-size:16px; font-weight:500;">
+                  Accept Invitation
+                </a>
+                <p style="margin:20px 0 0; font-size:14px; color:#737373;">
+                  Or accept with <a href="%s" style="color:#8b0f24;">Google</a> or <a href="%s" style="color:#8b0f24;">Microsoft</a>.
+                </p>
+                <p style="margin-top:30px; font-size:14px; color:#737373;">
+                  The invitation expires in 7 days. If you weren't expecting it, you can safely ignore this email.
+                </p>
+              </td>
+            </tr>
+          </table>
+        </td>
+      </tr>
+    </table>
+  </body>
+</html>`, html.EscapeString(name), html.EscapeString(joining), links.MagicLink, links.Google, links.Microsoft)
+
+	return textBody, htmlBody
+}
+
+// invitationForUser writes the error itself when the user has never been
+// invited.
+func (m *UserModule) invitationForUser(w http.ResponseWriter, r *http.Request, user data.AuthUser) (*data.Invitation, bool) {
+	invitation, found, err := m.Db.Invitations.GetLatestForUser(user)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return nil, false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.RecordNotFound, nil)
+		return nil, false
+	}
+
+	return invitation, true
+}
+
+// resendInvitation sends the user a fresh link. A pending invitation gets a new
+// token and expiry; a revoked one is replaced by a new invitation, as long as
+// the user has not been activated in the meantime.
+func (m *UserModule) resendInvitation(w http.ResponseWriter, r *http.Request, user data.AuthUser, joining string) {
+	invitation, found, err := m.Db.Invitations.GetLatestForUser(user)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	if found && invitation.Status == data.InvitationStatuses.Pending {
+		err = m.Db.Invitations.Resend(invitation)
+		if errors.Is(err, data.ErrInvitationClosed) {
+			m.WriteError(w, r, m.Err.Conflict, err)
+			return
+		}
+		if err != nil {
+			m.WriteError(w, r, m.Err.ServerError, err)
+			return
+		}
+
+		m.sendInvitation(invitation, user.GetName(), joining)
+		m.WriteJSON(w, r, http.StatusOK, invitation)
+		return
+	}
+
+	if user.GetIsActive() || (found && invitation.Status == data.InvitationStatuses.Accepted) {
+		m.WriteError(w, r, m.Err.Conflict, errors.New("user has already joined"))
+		return
+	}
+
+	invitation, err = m.invite(r, user, joining)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusCreated, invitation)
+}
+
+func (m *UserModule) revokeInvitation(w http.ResponseWriter, r *http.Request, user data.AuthUser) {
+	invitation, ok := m.invitationForUser(w, r, user)
+	if !ok {
+		return
+	}
+
+	err := m.Db.Invitations.Revoke(invitation)
+	if errors.Is(err, data.ErrInvitationClosed) {
+		m.WriteError(w, r, m.Err.Conflict, err)
+		return
+	}
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return
+	}
+
+	m.WriteJSON(w, r, http.StatusOK, invitation)
+}
+
+// dealershipName is what a dealership user is invited to join.
+func (m *UserModule) dealershipName(dealershipID int) (string, error) {
+	dealership, found, err := m.Db.Dealerships.GetByID(dealershipID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("dealership %d not found", dealershipID)
+	}
+	return dealership.Name, nil
+}
+
+// roleExists writes a bad request unless key names one of roles.
+func (m *UserModule) roleExists(w http.ResponseWriter, r *http.Request, roles data.RoleModel, key string) bool {
+	_, found, err := roles.GetByKey(key)
+	if err != nil {
+		m.WriteError(w, r, m.Err.ServerError, err)
+		return false
+	}
+	if !found {
+		m.WriteError(w, r, m.Err.BadRequest, fmt.Errorf("role %q does not exist", key))
+		return false
+	}
+	return true
+}
+
+// signOutUnenrolled ends the sessions of every internal user the policy
+// applies to who has no confirmed authenticator.
+func (m *UserModule) signOutUnenrolled(policy *data.TwoFactorPolicy) error {
+	users, err := m.Db.InternalUsers.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		permissions, err := data.ResolvePermissions(&m.Db, user)
+		if err != nil {
+			return err
+		}
+
+		if !policy.Requires(permissions) {
+			continue
+		}
+
+		twoFactor, found, err := m.Db.TwoFactor.GetByInternalUserID(user.ID)
+		if err != nil {
+			return err
+		}
+
+		if found && twoFactor.IsEnabled() {
+			continue
+		}
+
+		err = data.RevokeAllSessions(&m.Db, user)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
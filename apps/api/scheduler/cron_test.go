@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParse(t *testing.T, expr string) *Schedule {
+	t.Helper()
+	schedule, err := ParseSchedule(expr)
+	require.NoError(t, err)
+	return schedule
+}
+
+func TestParseSchedule_RejectsBadExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"*/0 * * * *",
+		"30-10 * * * *",
+		"a * * * *",
+		"@yearly",
+	} {
+		_, err := ParseSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// A Wednesday.
+	from := time.Date(2026, time.March, 4, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, time.March, 4, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.March, 4, 10, 15, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, time.March, 4, 11, 0, 0, 0, time.UTC)},
+		{"30 3 * * *", time.Date(2026, time.March, 5, 3, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, time.March, 4, 13, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1-5", time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// With both days restricted, either one fires it.
+		{"0 0 20 * 5", time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			assert.Equal(t, tt.want, mustParse(t, tt.expr).Next(from))
+		})
+	}
+}
+
+func TestSchedule_NextOnTheMinuteMovesOn(t *testing.T) {
+	schedule := mustParse(t, "*/15 * * * *")
+	from := time.Date(2026, time.March, 4, 10, 15, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, time.March, 4, 10, 30, 0, 0, time.UTC), schedule.Next(from))
+}
+
+func TestSchedule_NextNeverFires(t *testing.T) {
+	schedule := mustParse(t, "0 0 30 2 *")
+
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
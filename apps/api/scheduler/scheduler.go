@@ -0,0 +1,233 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	data "github.com/Lil-Strudel/glassact-studios/libs/data/pkg"
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobRunning  = errors.New("job is already running")
+)
+
+// Job is a piece of background maintenance. Run does the work and returns how
+// many rows it touched, which is kept in the job's run history.
+type Job struct {
+	Name        string
+	Description string
+	// Schedule is a cron expression, evaluated in UTC.
+	Schedule string
+	Run      func() (int64, error)
+
+	schedule *Schedule
+}
+
+// NextRun is when the job is next due after now.
+func (j *Job) NextRun(now time.Time) time.Time {
+	return j.schedule.Next(now.UTC())
+}
+
+// Scheduler runs registered jobs on their schedules. Every instance of the
+// API runs one; a job's advisory lock means only one of them runs it at a
+// time, and its run history means a tick is only run once.
+type Scheduler struct {
+	runs   data.JobRunModel
+	log    *slog.Logger
+	jobs   []*Job
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(runs data.JobRunModel, log *slog.Logger) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		runs:   runs,
+		log:    log,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Register adds a job. Jobs must be registered before Start.
+func (s *Scheduler) Register(job Job) error {
+	if _, found := s.Job(job.Name); found {
+		return fmt.Errorf("job %q is already registered", job.Name)
+	}
+
+	schedule, err := ParseSchedule(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %q: %w", job.Name, err)
+	}
+	job.schedule = schedule
+
+	s.jobs = append(s.jobs, &job)
+
+	return nil
+}
+
+// Jobs lists the registered jobs in the order they were registered.
+func (s *Scheduler) Jobs() []*Job {
+	return s.jobs
+}
+
+func (s *Scheduler) Job(name string) (*Job, bool) {
+	for _, job := range s.jobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return nil, false
+}
+
+// Start runs each job on its schedule until Stop.
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+	}
+}
+
+// Stop stops scheduling jobs and waits for runs in progress to finish.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(job *Job) {
+	defer s.wg.Done()
+
+	for {
+		next := job.NextRun(time.Now())
+		if next.IsZero() {
+			s.log.Warn("job schedule never fires", "job", job.Name, "schedule", job.Schedule)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		lock, locked, err := s.runs.TryLock(job.Name)
+		if err != nil {
+			s.log.Error("failed to lock job", "job", job.Name, "error", err.Error())
+			continue
+		}
+		if !locked {
+			continue
+		}
+
+		run := &data.JobRun{
+			JobName:      job.Name,
+			Trigger:      data.JobRunTriggers.Schedule,
+			ScheduledFor: &next,
+		}
+		started, err := s.start(run)
+		if err != nil {
+			s.log.Error("failed to start job", "job", job.Name, "error", err.Error())
+		}
+		if started {
+			s.execute(job, run)
+		}
+		s.release(lock)
+	}
+}
+
+// Trigger runs a job now, in the background, and returns its run.
+// triggeredBy is the internal user who asked for it. It fails with
+// ErrJobRunning when the job is already running on any instance.
+func (s *Scheduler) Trigger(name string, triggeredBy *int) (*data.JobRun, error) {
+	job, found := s.Job(name)
+	if !found {
+		return nil, ErrJobNotFound
+	}
+
+	lock, locked, err := s.runs.TryLock(job.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, ErrJobRunning
+	}
+
+	run := &data.JobRun{
+		JobName:                   job.Name,
+		Trigger:                   data.JobRunTriggers.Manual,
+		TriggeredByInternalUserID: triggeredBy,
+	}
+	_, err = s.start(run)
+	if err != nil {
+		s.release(lock)
+		return nil, err
+	}
+
+	// The caller gets its own copy; the background run updates the original.
+	started := *run
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.release(lock)
+		s.execute(job, run)
+	}()
+
+	return &started, nil
+}
+
+// start records run, first failing any runs of the job an earlier holder of
+// its lock left behind. It reports false when run's tick has already run.
+func (s *Scheduler) start(run *data.JobRun) (bool, error) {
+	abandoned, err := s.runs.FailAbandoned(run.JobName)
+	if err != nil {
+		return false, err
+	}
+	if abandoned > 0 {
+		s.log.Warn("failed abandoned job runs", "job", run.JobName, "count", abandoned)
+	}
+
+	return s.runs.Start(run)
+}
+
+func (s *Scheduler) execute(job *Job, run *data.JobRun) {
+	rowsAffected, err := s.call(job)
+	if err != nil {
+		s.log.Error("job failed", "job", job.Name, "run", run.UUID, "error", err.Error())
+	} else {
+		s.log.Info("job succeeded", "job", job.Name, "run", run.UUID, "rows_affected", rowsAffected)
+	}
+
+	err = s.runs.Finish(run, rowsAffected, err)
+	if err != nil {
+		s.log.Error("failed to record job run", "job", job.Name, "run", run.UUID, "error", err.Error())
+	}
+}
+
+// call runs the job, turning a panic into its error so the run is still
+// recorded and the scheduler keeps going.
+func (s *Scheduler) call(job *Job) (rowsAffected int64, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	return job.Run()
+}
+
+func (s *Scheduler) release(lock *data.JobLock) {
+	err := lock.Release()
+	if err != nil {
+		s.log.Error("failed to release job lock", "error", err.Error())
+	}
+}
@@ -0,0 +1,231 @@
+package svg
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// appearanceDefsID is the id of the <defs> block holding preview patterns and
+// filters in a consumer bake.
+const appearanceDefsID = "gac-appearance"
+
+// minPreviewOpacity keeps even clear glass visible in a preview; real clear
+// glass still reads as a tinted sheet against the grout.
+const minPreviewOpacity = 0.2
+
+// GlassAppearance is the physical look of a glass color, used only by
+// BakeConsumer to make previews read like the real sheet. Finish and Texture
+// take the glass_colors values; SwatchDataURI, when set, is an inlined raster
+// photo of the sheet used as the fill instead of the flat hex.
+type GlassAppearance struct {
+	Finish        string
+	Texture       string
+	Opacity       float64
+	SwatchDataURI string
+}
+
+// textureNoise is the feTurbulence setup that approximates each texture.
+// Frequencies are per user unit, so they are tuned for 300 units/inch art.
+type textureNoise struct {
+	kind    string
+	freq    string
+	octaves int
+	seed    int
+}
+
+var textureNoises = map[string]textureNoise{
+	"streaky":  {kind: "fractalNoise", freq: "0.002 0.04", octaves: 2, seed: 3},
+	"rippled":  {kind: "turbulence", freq: "0.02", octaves: 2, seed: 5},
+	"seedy":    {kind: "fractalNoise", freq: "0.15", octaves: 1, seed: 7},
+	"hammered": {kind: "turbulence", freq: "0.05", octaves: 3, seed: 9},
+	"granite":  {kind: "fractalNoise", freq: "0.35", octaves: 3, seed: 13},
+}
+
+var inlineFillOpacityRe = regexp.MustCompile(`(?i)\s*fill-opacity\s*:[^;]*;?`)
+
+// applyAppearance decorates recolored pieces with swatch patterns, texture and
+// finish filters, and translucency. Pieces whose color has no appearance entry
+// keep their flat fill. Any appearance block from a previous bake is replaced.
+func applyAppearance(root *etree.Element, appearanceByID map[int]GlassAppearance) {
+	stripAppearance(root)
+	if len(appearanceByID) == 0 {
+		return
+	}
+
+	defs := etree.NewElement("defs")
+	defs.CreateAttr("id", appearanceDefsID)
+	defined := map[string]bool{}
+
+	for _, el := range root.FindElements("//*[@data-glass-color-id]") {
+		colorID, err := strconv.Atoi(el.SelectAttrValue("data-glass-color-id", ""))
+		if err != nil {
+			continue
+		}
+		a, ok := appearanceByID[colorID]
+		if !ok {
+			continue
+		}
+
+		if a.SwatchDataURI != "" {
+			id := fmt.Sprintf("gac-swatch-%d", colorID)
+			if !defined[id] {
+				defs.AddChild(swatchPattern(id, a.SwatchDataURI))
+				defined[id] = true
+			}
+			setInlineFill(el, "url(#"+id+")")
+		} else if id := glassFilterID(a); id != "" {
+			if !defined[id] {
+				defs.AddChild(glassFilter(id, a))
+				defined[id] = true
+			}
+			el.CreateAttr("filter", "url(#"+id+")")
+		}
+
+		setInlineFillOpacity(el, previewOpacity(a))
+	}
+
+	if len(defs.ChildElements()) > 0 {
+		root.InsertChildAt(0, defs)
+	}
+}
+
+// stripAppearance removes everything applyAppearance adds, so a preview fed
+// back into Bake still comes out flat.
+func stripAppearance(root *etree.Element) {
+	for _, stale := range root.FindElements("//defs[@id='" + appearanceDefsID + "']") {
+		if parent := stale.Parent(); parent != nil {
+			parent.RemoveChild(stale)
+		}
+	}
+	for _, el := range root.FindElements("//*[@data-glass-color-id]") {
+		if strings.HasPrefix(el.SelectAttrValue("filter", ""), "url(#gac-glass-") {
+			el.RemoveAttr("filter")
+		}
+		setInlineFillOpacity(el, 1)
+	}
+}
+
+func previewOpacity(a GlassAppearance) float64 {
+	if a.Finish == "opalescent" || a.Opacity >= 1 {
+		return 1
+	}
+	if a.Opacity < minPreviewOpacity {
+		return minPreviewOpacity
+	}
+	return a.Opacity
+}
+
+func setInlineFillOpacity(el *etree.Element, opacity float64) {
+	style := el.SelectAttrValue("style", "")
+	style = inlineFillOpacityRe.ReplaceAllString(style, "")
+	style = strings.Trim(strings.TrimSpace(style), ";")
+	if opacity < 1 {
+		if style != "" {
+			style += ";"
+		}
+		style += "fill-opacity:" + formatNum(opacity)
+	}
+	if style == "" {
+		el.RemoveAttr("style")
+		return
+	}
+	el.CreateAttr("style", style)
+}
+
+// swatchPattern tiles the swatch photo at two inches square.
+func swatchPattern(id, dataURI string) *etree.Element {
+	size := formatNum(2 * unitsPerInch)
+
+	pattern := etree.NewElement("pattern")
+	pattern.CreateAttr("id", id)
+	pattern.CreateAttr("patternUnits", "userSpaceOnUse")
+	pattern.CreateAttr("width", size)
+	pattern.CreateAttr("height", size)
+
+	img := pattern.CreateElement("image")
+	img.CreateAttr("href", dataURI)
+	img.CreateAttr("width", size)
+	img.CreateAttr("height", size)
+	img.CreateAttr("preserveAspectRatio", "xMidYMid slice")
+
+	return pattern
+}
+
+func glassFilterID(a GlassAppearance) string {
+	_, textured := textureNoises[a.Texture]
+	if !textured && a.Finish != "iridescent" {
+		return ""
+	}
+	texture := a.Texture
+	if !textured {
+		texture = "smooth"
+	}
+	return "gac-glass-" + texture + "-" + a.Finish
+}
+
+// glassFilter builds the preview filter: grayscale noise soft-lit over the
+// fill for texture, a low-frequency colored sheen screened on top for
+// iridescent glass, clipped back to the piece's own shape.
+func glassFilter(id string, a GlassAppearance) *etree.Element {
+	f := etree.NewElement("filter")
+	f.CreateAttr("id", id)
+	f.CreateAttr("color-interpolation-filters", "sRGB")
+
+	in := "SourceGraphic"
+	if n, ok := textureNoises[a.Texture]; ok {
+		turb := f.CreateElement("feTurbulence")
+		turb.CreateAttr("type", n.kind)
+		turb.CreateAttr("baseFrequency", n.freq)
+		turb.CreateAttr("numOctaves", strconv.Itoa(n.octaves))
+		turb.CreateAttr("seed", strconv.Itoa(n.seed))
+		turb.CreateAttr("result", "noise")
+
+		grain := f.CreateElement("feColorMatrix")
+		grain.CreateAttr("in", "noise")
+		grain.CreateAttr("type", "saturate")
+		grain.CreateAttr("values", "0")
+		grain.CreateAttr("result", "grain")
+
+		blend := f.CreateElement("feBlend")
+		blend.CreateAttr("in", in)
+		blend.CreateAttr("in2", "grain")
+		blend.CreateAttr("mode", "soft-light")
+		blend.CreateAttr("result", "textured")
+		in = "textured"
+	}
+
+	if a.Finish == "iridescent" {
+		turb := f.CreateElement("feTurbulence")
+		turb.CreateAttr("type", "fractalNoise")
+		turb.CreateAttr("baseFrequency", "0.006")
+		turb.CreateAttr("numOctaves", "1")
+		turb.CreateAttr("seed", "11")
+		turb.CreateAttr("result", "sheenNoise")
+
+		alpha := f.CreateElement("feComponentTransfer")
+		alpha.CreateAttr("in", "sheenNoise")
+		alpha.CreateAttr("result", "sheen")
+		funcA := alpha.CreateElement("feFuncA")
+		funcA.CreateAttr("type", "linear")
+		funcA.CreateAttr("slope", "0")
+		funcA.CreateAttr("intercept", "0.35")
+
+		blend := f.CreateElement("feBlend")
+		blend.CreateAttr("in", in)
+		blend.CreateAttr("in2", "sheen")
+		blend.CreateAttr("mode", "screen")
+		blend.CreateAttr("result", "iridescent")
+		in = "iridescent"
+	}
+
+	clip := f.CreateElement("feComposite")
+	clip.CreateAttr("in", in)
+	clip.CreateAttr("in2", "SourceGraphic")
+	clip.CreateAttr("operator", "in")
+
+	return f
+}
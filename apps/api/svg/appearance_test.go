@@ -0,0 +1,98 @@
+package svg
+
+import (
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func consumerPieces(t *testing.T, out []byte) (*etree.Element, []*etree.Element) {
+	t.Helper()
+	doc := etree.NewDocument()
+	require.NoError(t, doc.ReadFromBytes(out))
+	root := doc.SelectElement("svg")
+	pieces := root.FindElements("//*[@data-glass-color-id='5']")
+	require.NotEmpty(t, pieces)
+	return root, pieces
+}
+
+func TestBakeConsumer_AppliesTextureFinishAndOpacity(t *testing.T) {
+	manifest, structureSVG := bakedManifest(t, svgMultiClass)
+	overrides := ColorOverrides{Groups: map[string]GlassColorRef{"group-0": {GlassColorID: 5}}}
+
+	out, err := BakeConsumer(structureSVG, *manifest, 1, overrides,
+		map[int]string{5: "#ff0000"}, nil,
+		map[int]GlassAppearance{5: {Finish: "iridescent", Texture: "streaky", Opacity: 0.5}})
+	require.NoError(t, err)
+
+	root, pieces := consumerPieces(t, out)
+	filter := root.FindElement("//defs[@id='gac-appearance']/filter[@id='gac-glass-streaky-iridescent']")
+	require.NotNil(t, filter, "one shared filter for the color")
+	assert.Len(t, root.FindElements("//filter"), 1)
+	assert.NotNil(t, filter.FindElement("./feTurbulence"))
+
+	for _, p := range pieces {
+		assert.Equal(t, "url(#gac-glass-streaky-iridescent)", p.SelectAttrValue("filter", ""))
+		assert.Contains(t, p.SelectAttrValue("style", ""), "fill:#ff0000")
+		assert.Contains(t, p.SelectAttrValue("style", ""), "fill-opacity:0.5")
+	}
+}
+
+func TestBakeConsumer_SwatchReplacesFlatFill(t *testing.T) {
+	manifest, structureSVG := bakedManifest(t, svgMultiClass)
+	overrides := ColorOverrides{Groups: map[string]GlassColorRef{"group-0": {GlassColorID: 5}}}
+
+	out, err := BakeConsumer(structureSVG, *manifest, 1, overrides,
+		map[int]string{5: "#ff0000"}, nil,
+		map[int]GlassAppearance{5: {Finish: "opalescent", Texture: "granite", Opacity: 0.3, SwatchDataURI: "data:image/png;base64,AAAA"}})
+	require.NoError(t, err)
+
+	root, pieces := consumerPieces(t, out)
+	img := root.FindElement("//pattern[@id='gac-swatch-5']/image")
+	require.NotNil(t, img)
+	assert.Equal(t, "data:image/png;base64,AAAA", img.SelectAttrValue("href", ""))
+
+	for _, p := range pieces {
+		assert.Contains(t, p.SelectAttrValue("style", ""), "fill:url(#gac-swatch-5)")
+		assert.NotContains(t, p.SelectAttrValue("style", ""), "fill-opacity", "opalescent glass is opaque")
+		assert.Empty(t, p.SelectAttrValue("filter", ""))
+	}
+}
+
+func TestBakeConsumer_ClampsClearGlassOpacity(t *testing.T) {
+	manifest, structureSVG := bakedManifest(t, svgMultiClass)
+	overrides := ColorOverrides{Groups: map[string]GlassColorRef{"group-0": {GlassColorID: 5}}}
+
+	out, err := BakeConsumer(structureSVG, *manifest, 1, overrides,
+		map[int]string{5: "#ff0000"}, nil,
+		map[int]GlassAppearance{5: {Finish: "cathedral", Texture: "smooth", Opacity: 0}})
+	require.NoError(t, err)
+
+	root, pieces := consumerPieces(t, out)
+	assert.Nil(t, root.FindElement("//defs[@id='gac-appearance']"), "smooth cathedral needs no defs")
+	for _, p := range pieces {
+		assert.Contains(t, p.SelectAttrValue("style", ""), "fill-opacity:0.2")
+	}
+}
+
+func TestBake_ProductionStaysFlat(t *testing.T) {
+	manifest, structureSVG := bakedManifest(t, svgMultiClass)
+	overrides := ColorOverrides{Groups: map[string]GlassColorRef{"group-0": {GlassColorID: 5}}}
+
+	consumer, err := BakeConsumer(structureSVG, *manifest, 1, overrides,
+		map[int]string{5: "#ff0000"}, nil,
+		map[int]GlassAppearance{5: {Finish: "iridescent", Texture: "rippled", Opacity: 0.5}})
+	require.NoError(t, err)
+
+	// Re-baking a preview for production must drop every preview decoration.
+	bbox := ContentBBox{X: 0, Y: 0, Width: 100, Height: 200}
+	out, err := Bake(consumer, *manifest, bbox, 1, 2, overrides, map[int]string{5: "#ff0000"}, nil)
+	require.NoError(t, err)
+
+	doc := etree.NewDocument()
+	require.NoError(t, doc.ReadFromBytes(out))
+	assert.Empty(t, doc.FindElements("//filter"))
+	assert.Empty(t, doc.FindElements("//pattern"))
+}
@@ -61,6 +61,7 @@ func Bake(
 	}
 
 	stripStyles(root)
+	stripAppearance(root)
 	applyFit(root, bbox, width, height)
 
 	if err := recolorGrout(root, manifest, overrides, groutHexByID); err != nil {
@@ -71,10 +72,12 @@ func Bake(
 	return doc.WriteToBytes()
 }
 
-// BakeConsumer renders a flat SVG for the consumer customizer. The stored
+// BakeConsumer renders an SVG for the consumer customizer. The stored
 // structure SVG is already fit, so this path keeps the manifest viewBox and only
 // applies scale_factor to the root width/height for display sizing — it never
-// recomputes fit.
+// recomputes fit. Unlike Bake it is a preview: appearanceByID layers swatch
+// patterns, texture/finish filters and translucency over the flat colors. Pass
+// nil for a flat render.
 func BakeConsumer(
 	structureSVG []byte,
 	manifest Manifest,
@@ -82,6 +85,7 @@ func BakeConsumer(
 	overrides ColorOverrides,
 	glassHexByID map[int]string,
 	groutHexByID map[int]string,
+	appearanceByID map[int]GlassAppearance,
 ) ([]byte, error) {
 	doc, root, err := parseRoot(structureSVG)
 	if err != nil {
@@ -96,16 +100,23 @@ func BakeConsumer(
 	if err := recolorGrout(root, manifest, overrides, groutHexByID); err != nil {
 		return nil, err
 	}
+	applyAppearance(root, appearanceByID)
 	applyScale(root, manifest.ViewBox, scaleFactor)
 	addCutListMetadata(root, cl)
 	return doc.WriteToBytes()
 }
 
+// parseRoot parses and sanitizes an SVG. Every entry point into this package
+// goes through it, so nothing downstream ever sees unsanitized input.
 func parseRoot(in []byte) (*etree.Document, *etree.Element, error) {
+	if err := checkLimits(in); err != nil {
+		return nil, nil, err
+	}
 	doc := etree.NewDocument()
 	if err := doc.ReadFromBytes(in); err != nil {
 		return nil, nil, fmt.Errorf("parse structure svg: %w", err)
 	}
+	sanitizeTree(doc)
 	root := doc.SelectElement("svg")
 	if root == nil {
 		root = doc.Root()
@@ -0,0 +1,532 @@
+package svg
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// Point is a 2D coordinate. Depending on context it is in SVG user units or in
+// inches (nesting works exclusively in inches).
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// matrix is an SVG affine transform [a b c d e f]:
+//
+//	x' = a*x + c*y + e
+//	y' = b*x + d*y + f
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// mul returns m·n, i.e. n applied first, then m.
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+func (m matrix) apply(p Point) Point {
+	return Point{
+		X: m[0]*p.X + m[2]*p.Y + m[4],
+		Y: m[1]*p.X + m[3]*p.Y + m[5],
+	}
+}
+
+var transformFnRe = regexp.MustCompile(`([a-zA-Z]+)\s*\(([^)]*)\)`)
+
+// parseTransform parses an SVG transform attribute. Functions compose left to
+// right, so "translate(..) scale(..)" scales first and then translates, which
+// is exactly how the gac-fit wrapper is written.
+func parseTransform(s string) matrix {
+	out := identity
+	for _, fn := range transformFnRe.FindAllStringSubmatch(s, -1) {
+		args := parseNumbers(fn[2])
+		var t matrix
+		switch strings.ToLower(fn[1]) {
+		case "matrix":
+			if len(args) != 6 {
+				continue
+			}
+			t = matrix{args[0], args[1], args[2], args[3], args[4], args[5]}
+		case "translate":
+			if len(args) == 0 {
+				continue
+			}
+			ty := 0.0
+			if len(args) > 1 {
+				ty = args[1]
+			}
+			t = matrix{1, 0, 0, 1, args[0], ty}
+		case "scale":
+			if len(args) == 0 {
+				continue
+			}
+			sy := args[0]
+			if len(args) > 1 {
+				sy = args[1]
+			}
+			t = matrix{args[0], 0, 0, sy, 0, 0}
+		case "rotate":
+			if len(args) == 0 {
+				continue
+			}
+			rad := args[0] * math.Pi / 180
+			cos, sin := math.Cos(rad), math.Sin(rad)
+			t = matrix{cos, sin, -sin, cos, 0, 0}
+			if len(args) == 3 {
+				cx, cy := args[1], args[2]
+				t = matrix{1, 0, 0, 1, cx, cy}.mul(t).mul(matrix{1, 0, 0, 1, -cx, -cy})
+			}
+		case "skewx":
+			if len(args) == 0 {
+				continue
+			}
+			t = matrix{1, 0, math.Tan(args[0] * math.Pi / 180), 1, 0, 0}
+		case "skewy":
+			if len(args) == 0 {
+				continue
+			}
+			t = matrix{1, math.Tan(args[0] * math.Pi / 180), 0, 1, 0, 0}
+		default:
+			continue
+		}
+		out = out.mul(t)
+	}
+	return out
+}
+
+var numberRe = regexp.MustCompile(`[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+func parseNumbers(s string) []float64 {
+	matches := numberRe.FindAllString(s, -1)
+	out := make([]float64, 0, len(matches))
+	for _, m := range matches {
+		f, err := strconv.ParseFloat(m, 64)
+		if err == nil {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Segment counts used when flattening curves. Glass is scored by hand or on a
+// cutter with far coarser tolerance than this, so fixed counts are plenty.
+const (
+	curveSegments  = 16
+	circleSegments = 48
+)
+
+// shapeOutlines flattens a fillable shape into closed polylines in the
+// element's own user space. Lines are skipped: they have no area to cut.
+func shapeOutlines(el *etree.Element) [][]Point {
+	attr := func(name string) float64 {
+		return parseDim(el.SelectAttrValue(name, "0"))
+	}
+
+	switch strings.ToLower(localName(el.Tag)) {
+	case "rect":
+		x, y, w, h := attr("x"), attr("y"), attr("width"), attr("height")
+		if w <= 0 || h <= 0 {
+			return nil
+		}
+		return [][]Point{{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}}
+	case "circle":
+		r := attr("r")
+		if r <= 0 {
+			return nil
+		}
+		return [][]Point{ellipsePoints(attr("cx"), attr("cy"), r, r)}
+	case "ellipse":
+		rx, ry := attr("rx"), attr("ry")
+		if rx <= 0 || ry <= 0 {
+			return nil
+		}
+		return [][]Point{ellipsePoints(attr("cx"), attr("cy"), rx, ry)}
+	case "polygon", "polyline":
+		nums := parseNumbers(el.SelectAttrValue("points", ""))
+		var poly []Point
+		for i := 0; i+1 < len(nums); i += 2 {
+			poly = append(poly, Point{nums[i], nums[i+1]})
+		}
+		if len(poly) < 3 {
+			return nil
+		}
+		return [][]Point{poly}
+	case "path":
+		return flattenPath(el.SelectAttrValue("d", ""))
+	}
+	return nil
+}
+
+func ellipsePoints(cx, cy, rx, ry float64) []Point {
+	pts := make([]Point, circleSegments)
+	for i := range pts {
+		a := 2 * math.Pi * float64(i) / circleSegments
+		pts[i] = Point{cx + rx*math.Cos(a), cy + ry*math.Sin(a)}
+	}
+	return pts
+}
+
+// pathTokenizer walks SVG path data. Numbers may be packed ("1.5.5", "1-2")
+// and arc flags may be written without separators ("a1 1 0 011 1").
+type pathTokenizer struct {
+	s   string
+	pos int
+}
+
+func (t *pathTokenizer) skipSeparators() {
+	for t.pos < len(t.s) {
+		c := t.s[t.pos]
+		if c == ' ' || c == ',' || c == '\t' || c == '\n' || c == '\r' {
+			t.pos++
+			continue
+		}
+		break
+	}
+}
+
+// command returns the next command letter, or 0 when the next token is a
+// number (an implicit repeat of the previous command) or input is exhausted.
+func (t *pathTokenizer) command() byte {
+	t.skipSeparators()
+	if t.pos >= len(t.s) {
+		return 0
+	}
+	c := t.s[t.pos]
+	if (c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') && c != 'e' && c != 'E' {
+		t.pos++
+		return c
+	}
+	return 0
+}
+
+func (t *pathTokenizer) done() bool {
+	t.skipSeparators()
+	return t.pos >= len(t.s)
+}
+
+func (t *pathTokenizer) number() (float64, bool) {
+	t.skipSeparators()
+	loc := numberRe.FindStringIndex(t.s[t.pos:])
+	if loc == nil || loc[0] != 0 {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(t.s[t.pos:t.pos+loc[1]], 64)
+	if err != nil {
+		return 0, false
+	}
+	t.pos += loc[1]
+	return f, true
+}
+
+func (t *pathTokenizer) flag() (bool, bool) {
+	t.skipSeparators()
+	if t.pos >= len(t.s) {
+		return false, false
+	}
+	switch t.s[t.pos] {
+	case '0':
+		t.pos++
+		return false, true
+	case '1':
+		t.pos++
+		return true, true
+	}
+	return false, false
+}
+
+func (t *pathTokenizer) numbers(n int) ([]float64, bool) {
+	out := make([]float64, n)
+	for i := range out {
+		f, ok := t.number()
+		if !ok {
+			return nil, false
+		}
+		out[i] = f
+	}
+	return out, true
+}
+
+// flattenPath converts path data into one polyline per subpath. Malformed data
+// stops parsing at the first bad token and keeps whatever was read so far,
+// matching how browsers render a partially valid path.
+func flattenPath(d string) [][]Point {
+	t := &pathTokenizer{s: d}
+	var (
+		out            [][]Point
+		cur            []Point
+		pos, start     Point
+		lastCtrl       Point
+		lastCmd, cmd   byte
+		hasLastControl bool
+	)
+
+	flush := func() {
+		if len(cur) >= 3 {
+			out = append(out, cur)
+		}
+		cur = nil
+	}
+	lineTo := func(p Point) {
+		if len(cur) == 0 {
+			cur = append(cur, pos)
+		}
+		cur = append(cur, p)
+		pos = p
+	}
+
+	for !t.done() {
+		if c := t.command(); c != 0 {
+			cmd = c
+		} else if cmd == 0 {
+			break
+		}
+		rel := cmd >= 'a'
+		upper := cmd &^ 0x20
+		at := func(x, y float64) Point {
+			if rel {
+				return Point{pos.X + x, pos.Y + y}
+			}
+			return Point{x, y}
+		}
+
+		switch upper {
+		case 'M':
+			v, ok := t.numbers(2)
+			if !ok {
+				return finishPaths(out, cur)
+			}
+			flush()
+			pos = at(v[0], v[1])
+			start = pos
+			cur = []Point{pos}
+			// Subsequent coordinate pairs are implicit line-tos.
+			if rel {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+		case 'L':
+			v, ok := t.numbers(2)
+			if !ok {
+				return finishPaths(out, cur)
+			}
+			lineTo(at(v[0], v[1]))
+		case 'H':
+			v, ok := t.number()
+			if !ok {
+				return finishPaths(out, cur)
+			}
+			if rel {
+				v += pos.X
+			}
+			lineTo(Point{v, pos.Y})
+		case 'V':
+			v, ok := t.number()
+			if !ok {
+				return finishPaths(out, cur)
+			}
+			if rel {
+				v += pos.Y
+			}
+			lineTo(Point{pos.X, v})
+		case 'C', 'S':
+			var c1 Point
+			var rest []float64
+			var ok bool
+			if upper == 'C' {
+				var v []float64
+				if v, ok = t.numbers(6); !ok {
+					return finishPaths(out, cur)
+				}
+				c1 = at(v[0], v[1])
+				rest = v[2:]
+			} else {
+				if rest, ok = t.numbers(4); !ok {
+					return finishPaths(out, cur)
+				}
+				c1 = pos
+				if hasLastControl && (lastCmd&^0x20 == 'C' || lastCmd&^0x20 == 'S') {
+					c1 = Point{2*pos.X - lastCtrl.X, 2*pos.Y - lastCtrl.Y}
+				}
+			}
+			c2 := at(rest[0], rest[1])
+			end := at(rest[2], rest[3])
+			p0 := pos
+			for i := 1; i <= curveSegments; i++ {
+				lineTo(cubicAt(p0, c1, c2, end, float64(i)/curveSegments))
+			}
+			lastCtrl, hasLastControl = c2, true
+		case 'Q', 'T':
+			var c Point
+			var end Point
+			if upper == 'Q' {
+				v, ok := t.numbers(4)
+				if !ok {
+					return finishPaths(out, cur)
+				}
+				c = at(v[0], v[1])
+				end = at(v[2], v[3])
+			} else {
+				v, ok := t.numbers(2)
+				if !ok {
+					return finishPaths(out, cur)
+				}
+				c = pos
+				if hasLastControl && (lastCmd&^0x20 == 'Q' || lastCmd&^0x20 == 'T') {
+					c = Point{2*pos.X - lastCtrl.X, 2*pos.Y - lastCtrl.Y}
+				}
+				end = at(v[0], v[1])
+			}
+			p0 := pos
+			for i := 1; i <= curveSegments; i++ {
+				lineTo(quadAt(p0, c, end, float64(i)/curveSegments))
+			}
+			lastCtrl, hasLastControl = c, true
+		case 'A':
+			radii, ok := t.numbers(3)
+			if !ok {
+				return finishPaths(out, cur)
+			}
+			large, ok1 := t.flag()
+			sweep, ok2 := t.flag()
+			v, ok3 := t.numbers(2)
+			if !ok1 || !ok2 || !ok3 {
+				return finishPaths(out, cur)
+			}
+			end := at(v[0], v[1])
+			for _, p := range arcPoints(pos, end, radii[0], radii[1], radii[2], large, sweep) {
+				lineTo(p)
+			}
+		case 'Z':
+			flush()
+			pos = start
+		default:
+			return finishPaths(out, cur)
+		}
+
+		if upper != 'C' && upper != 'S' && upper != 'Q' && upper != 'T' {
+			hasLastControl = false
+		}
+		lastCmd = cmd
+	}
+
+	return finishPaths(out, cur)
+}
+
+func finishPaths(out [][]Point, cur []Point) [][]Point {
+	if len(cur) >= 3 {
+		out = append(out, cur)
+	}
+	return out
+}
+
+func cubicAt(p0, p1, p2, p3 Point, t float64) Point {
+	mt := 1 - t
+	a, b, c, d := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+	return Point{
+		a*p0.X + b*p1.X + c*p2.X + d*p3.X,
+		a*p0.Y + b*p1.Y + c*p2.Y + d*p3.Y,
+	}
+}
+
+func quadAt(p0, p1, p2 Point, t float64) Point {
+	mt := 1 - t
+	a, b, c := mt*mt, 2*mt*t, t*t
+	return Point{
+		a*p0.X + b*p1.X + c*p2.X,
+		a*p0.Y + b*p1.Y + c*p2.Y,
+	}
+}
+
+// arcPoints flattens an elliptical arc given in SVG endpoint form, using the
+// endpoint-to-center conversion from the SVG spec (implementation notes F.6).
+// The returned points exclude the start point and include the end point.
+func arcPoints(p0, p1 Point, rx, ry, phiDeg float64, large, sweep bool) []Point {
+	if p0 == p1 {
+		return nil
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		return []Point{p1}
+	}
+
+	phi := phiDeg * math.Pi / 180
+	cosPhi, sinPhi := math.Cos(phi), math.Sin(phi)
+
+	dx, dy := (p0.X-p1.X)/2, (p0.Y-p1.Y)/2
+	x1p := cosPhi*dx + sinPhi*dy
+	y1p := -sinPhi*dx + cosPhi*dy
+
+	// Scale radii up when they are too small to span the endpoints.
+	if lambda := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); lambda > 1 {
+		s := math.Sqrt(lambda)
+		rx, ry = rx*s, ry*s
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1p*y1p - ry*ry*x1p*x1p
+	den := rx*rx*y1p*y1p + ry*ry*x1p*x1p
+	coef := 0.0
+	if den != 0 && num > 0 {
+		coef = math.Sqrt(num / den)
+	}
+	if large == sweep {
+		coef = -coef
+	}
+	cxp := coef * rx * y1p / ry
+	cyp := -coef * ry * x1p / rx
+
+	cx := cosPhi*cxp - sinPhi*cyp + (p0.X+p1.X)/2
+	cy := sinPhi*cxp + cosPhi*cyp + (p0.Y+p1.Y)/2
+
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	theta1 := angle(1, 0, (x1p-cxp)/rx, (y1p-cyp)/ry)
+	delta := angle((x1p-cxp)/rx, (y1p-cyp)/ry, (-x1p-cxp)/rx, (-y1p-cyp)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	n := int(math.Ceil(math.Abs(delta) / (2 * math.Pi) * circleSegments))
+	if n < 2 {
+		n = 2
+	}
+	pts := make([]Point, 0, n)
+	for i := 1; i <= n; i++ {
+		th := theta1 + delta*float64(i)/float64(n)
+		x, y := rx*math.Cos(th), ry*math.Sin(th)
+		pts = append(pts, Point{
+			cosPhi*x - sinPhi*y + cx,
+			sinPhi*x + cosPhi*y + cy,
+		})
+	}
+	pts[len(pts)-1] = p1
+	return pts
+}
+
+// polygonArea returns the filled area of a set of subpaths. Holes in inlay art
+// are drawn with opposite winding, so summing signed areas nets them out.
+func polygonArea(polys [][]Point) float64 {
+	total := 0.0
+	for _, poly := range polys {
+		for i := range poly {
+			j := (i + 1) % len(poly)
+			total += poly[i].X*poly[j].Y - poly[j].X*poly[i].Y
+		}
+	}
+	return math.Abs(total) / 2
+}
@@ -63,21 +63,15 @@ type colorGroup struct {
 // is resolved the way a renderer resolves it — black — and flagged for the admin
 // to verify, since black-by-omission is also what a forgotten fill looks like.
 //
-// It only hard-errors on a genuinely unparseable SVG or a missing <svg> root.
+// It only hard-errors on a genuinely unparseable SVG, a missing <svg> root, or a
+// file over the sanitizer's size limits; scripts and external references are
+// silently stripped before anything else looks at the document.
 // Embedded raster, gradients and missing fills are surfaced as warnings rather
 // than rejected — the manifest editor handles fixing them.
 func Ingest(raw []byte, glassPalette, groutPalette []PaletteColor) (structureSVG []byte, manifest *Manifest, warnings []string, err error) {
-	doc := etree.NewDocument()
-	if err := doc.ReadFromBytes(raw); err != nil {
-		return nil, nil, nil, fmt.Errorf("parse svg: %w", err)
-	}
-
-	root := doc.SelectElement("svg")
-	if root == nil {
-		root = doc.Root()
-	}
-	if root == nil {
-		return nil, nil, nil, fmt.Errorf("svg has no root element")
+	doc, root, err := parseRoot(raw)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	if len(doc.FindElements("//image")) > 0 {
@@ -0,0 +1,125 @@
+package svg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// RenderSheetSVG draws one nested sheet as a cut layout in inches: the sheet
+// outline plus every placed piece's outline, each piece labelled by id.
+func RenderSheetSVG(sheet NestSheet, size SheetSize) ([]byte, error) {
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
+
+	root := doc.CreateElement("svg")
+	root.CreateAttr("xmlns", "http://www.w3.org/2000/svg")
+	root.CreateAttr("width", formatNum(size.Width)+"in")
+	root.CreateAttr("height", formatNum(size.Height)+"in")
+	root.CreateAttr("viewBox", fmt.Sprintf("0 0 %s %s", formatNum(size.Width), formatNum(size.Height)))
+
+	border := root.CreateElement("rect")
+	border.CreateAttr("id", "sheet")
+	border.CreateAttr("x", "0")
+	border.CreateAttr("y", "0")
+	border.CreateAttr("width", formatNum(size.Width))
+	border.CreateAttr("height", formatNum(size.Height))
+	border.CreateAttr("fill", "none")
+	border.CreateAttr("stroke", "#999999")
+	border.CreateAttr("stroke-width", "0.02")
+
+	cuts := root.CreateElement("g")
+	cuts.CreateAttr("id", "cuts")
+	cuts.CreateAttr("fill", "none")
+	cuts.CreateAttr("stroke", "#ff0000")
+	cuts.CreateAttr("stroke-width", "0.01")
+
+	for _, p := range sheet.Placements {
+		path := cuts.CreateElement("path")
+		path.CreateAttr("id", placementLabel(p))
+		path.CreateAttr("d", outlinePathData(p))
+	}
+
+	doc.Indent(2)
+	return doc.WriteToBytes()
+}
+
+func placementLabel(p Placement) string {
+	if p.InlayRef == "" {
+		return p.PieceID
+	}
+	return p.InlayRef + "-" + p.PieceID
+}
+
+func outlinePathData(p Placement) string {
+	var b strings.Builder
+	for _, poly := range p.outline {
+		for i, pt := range poly {
+			if i == 0 {
+				b.WriteString("M")
+			} else {
+				b.WriteString(" L")
+			}
+			b.WriteString(formatCoord(p.X + pt.X))
+			b.WriteString(" ")
+			b.WriteString(formatCoord(p.Y + pt.Y))
+		}
+		b.WriteString(" Z ")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// formatCoord rounds to a ten-thousandth of an inch, well below cutting
+// tolerance, to keep layouts readable and diffable.
+func formatCoord(f float64) string {
+	return fmt.Sprintf("%.4f", f)
+}
+
+// RenderSheetDXF writes one nested sheet as an ASCII DXF (R12 entities, inch
+// units) for cutter software. DXF's Y axis points up, so coordinates are
+// flipped against the sheet height. The sheet outline is on layer SHEET and
+// each piece is a closed POLYLINE on layer CUT.
+func RenderSheetDXF(sheet NestSheet, size SheetSize) []byte {
+	var b strings.Builder
+	pair := func(code int, value string) {
+		fmt.Fprintf(&b, "%d\n%s\n", code, value)
+	}
+	polyline := func(layer string, pts []Point) {
+		pair(0, "POLYLINE")
+		pair(8, layer)
+		pair(66, "1")
+		pair(70, "1")
+		for _, pt := range pts {
+			pair(0, "VERTEX")
+			pair(8, layer)
+			pair(10, formatCoord(pt.X))
+			pair(20, formatCoord(size.Height-pt.Y))
+		}
+		pair(0, "SEQEND")
+		pair(8, layer)
+	}
+
+	pair(0, "SECTION")
+	pair(2, "HEADER")
+	pair(9, "$INSUNITS")
+	pair(70, "1")
+	pair(0, "ENDSEC")
+
+	pair(0, "SECTION")
+	pair(2, "ENTITIES")
+	polyline("SHEET", []Point{{0, 0}, {size.Width, 0}, {size.Width, size.Height}, {0, size.Height}})
+	for _, p := range sheet.Placements {
+		for _, poly := range p.outline {
+			pts := make([]Point, len(poly))
+			for i, pt := range poly {
+				pts[i] = Point{X: p.X + pt.X, Y: p.Y + pt.Y}
+			}
+			polyline("CUT", pts)
+		}
+	}
+	pair(0, "ENDSEC")
+	pair(0, "EOF")
+
+	return []byte(b.String())
+}
@@ -0,0 +1,363 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/beevik/etree"
+)
+
+// NestPiece is one glass piece to be cut, in inches. Outline is relative to the
+// piece's bounding box origin so it can be dropped anywhere on a sheet.
+type NestPiece struct {
+	InlayRef     string    `json:"inlay_ref"`
+	PieceID      string    `json:"piece_id"`
+	GlassColorID int       `json:"glass_color_id"`
+	Width        float64   `json:"width"`
+	Height       float64   `json:"height"`
+	Area         float64   `json:"area"`
+	Outline      [][]Point `json:"-"`
+}
+
+// SheetSize is a stock glass sheet in inches.
+type SheetSize struct {
+	Width  float64 `json:"width" validate:"gt=0"`
+	Height float64 `json:"height" validate:"gt=0"`
+}
+
+// NestOptions configures a nesting run. Sheet is the default stock size;
+// SheetsByColor overrides it for colors sold in a different size. Spacing is the
+// clearance kept between pieces and from the sheet edge.
+type NestOptions struct {
+	Sheet         SheetSize
+	SheetsByColor map[int]SheetSize
+	Spacing       float64
+}
+
+// Placement positions one piece on a sheet. X/Y is the top-left corner of the
+// piece's (possibly rotated) bounding box.
+type Placement struct {
+	InlayRef string  `json:"inlay_ref"`
+	PieceID  string  `json:"piece_id"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Width    float64 `json:"width"`
+	Height   float64 `json:"height"`
+	Rotated  bool    `json:"rotated"`
+
+	outline [][]Point
+}
+
+type NestSheet struct {
+	Index       int         `json:"index"`
+	Utilization float64     `json:"utilization"`
+	Placements  []Placement `json:"placements"`
+}
+
+// ColorNest is the nesting result for one glass color. Oversize lists pieces
+// that fit on no sheet in either orientation; they need a larger sheet or a
+// manual cut.
+type ColorNest struct {
+	GlassColorID int         `json:"glass_color_id"`
+	Sheet        SheetSize   `json:"sheet"`
+	PieceCount   int         `json:"piece_count"`
+	SheetsNeeded int         `json:"sheets_needed"`
+	Utilization  float64     `json:"utilization"`
+	Sheets       []NestSheet `json:"sheets"`
+	Oversize     []NestPiece `json:"oversize"`
+}
+
+type NestReport struct {
+	Spacing     float64     `json:"spacing"`
+	Colors      []ColorNest `json:"colors"`
+	TotalSheets int         `json:"total_sheets"`
+}
+
+// ExtractPieces reads every glass piece out of a baked design SVG. Only pieces
+// carrying data-glass-color-id are returned — grout is poured, not cut. The
+// design's viewBox is mapped onto width x height inches; when either is zero
+// the canonical 300 units/inch density is assumed instead.
+func ExtractPieces(design []byte, width, height float64) ([]NestPiece, error) {
+	_, root, err := parseRoot(design)
+	if err != nil {
+		return nil, err
+	}
+
+	pieces, _, _, err := extractPieces(root, width, height)
+	return pieces, err
+}
+
+// extractPieces is ExtractPieces over an already parsed root. It also returns
+// the inch dimensions the viewBox was mapped onto.
+func extractPieces(root *etree.Element, width, height float64) ([]NestPiece, float64, float64, error) {
+	vx, vy, vw, vh, ok := parseViewBox(root.SelectAttrValue("viewBox", ""))
+	if !ok {
+		return nil, 0, 0, fmt.Errorf("design svg has no usable viewBox")
+	}
+	if width <= 0 || height <= 0 {
+		width, height = vw/unitsPerInch, vh/unitsPerInch
+	}
+	toInches := matrix{width / vw, 0, 0, height / vh, -vx * width / vw, -vy * height / vh}
+
+	var pieces []NestPiece
+	var walk func(el *etree.Element, m matrix) error
+	walk = func(el *etree.Element, m matrix) error {
+		for _, child := range el.ChildElements() {
+			cm := m
+			if tf := child.SelectAttrValue("transform", ""); tf != "" {
+				cm = m.mul(parseTransform(tf))
+			}
+
+			if isFillable(child.Tag) {
+				attr := child.SelectAttrValue("data-glass-color-id", "")
+				if attr == "" {
+					continue
+				}
+				colorID, err := strconv.Atoi(attr)
+				if err != nil {
+					return fmt.Errorf("piece %q has invalid data-glass-color-id %q", child.SelectAttrValue("id", ""), attr)
+				}
+				if piece, ok := buildPiece(child, cm, colorID); ok {
+					pieces = append(pieces, piece)
+				}
+				continue
+			}
+
+			if err := walk(child, cm); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(root, toInches); err != nil {
+		return nil, 0, 0, err
+	}
+
+	return pieces, width, height, nil
+}
+
+func buildPiece(el *etree.Element, m matrix, colorID int) (NestPiece, bool) {
+	outlines := shapeOutlines(el)
+	if len(outlines) == 0 {
+		return NestPiece{}, false
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, poly := range outlines {
+		for i, p := range poly {
+			p = m.apply(p)
+			poly[i] = p
+			minX, minY = math.Min(minX, p.X), math.Min(minY, p.Y)
+			maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+		}
+	}
+	if maxX-minX <= 0 || maxY-minY <= 0 {
+		return NestPiece{}, false
+	}
+
+	for _, poly := range outlines {
+		for i := range poly {
+			poly[i].X -= minX
+			poly[i].Y -= minY
+		}
+	}
+
+	return NestPiece{
+		PieceID:      el.SelectAttrValue("id", ""),
+		GlassColorID: colorID,
+		Width:        maxX - minX,
+		Height:       maxY - minY,
+		Area:         polygonArea(outlines),
+		Outline:      outlines,
+	}, true
+}
+
+// Nest packs pieces onto sheets, one independent run per glass color. It is a
+// first-fit decreasing-height shelf packer over piece bounding boxes: pieces
+// are laid flat (long side horizontal) when that fits, sorted tallest first,
+// and dropped onto the first shelf of the first sheet with room, opening a new
+// shelf or sheet as needed.
+func Nest(pieces []NestPiece, opts NestOptions) (NestReport, error) {
+	if opts.Sheet.Width <= 0 || opts.Sheet.Height <= 0 {
+		return NestReport{}, fmt.Errorf("sheet size must be positive")
+	}
+	if opts.Spacing < 0 {
+		return NestReport{}, fmt.Errorf("spacing cannot be negative")
+	}
+
+	byColor := map[int][]NestPiece{}
+	for _, p := range pieces {
+		byColor[p.GlassColorID] = append(byColor[p.GlassColorID], p)
+	}
+	colorIDs := make([]int, 0, len(byColor))
+	for id := range byColor {
+		colorIDs = append(colorIDs, id)
+	}
+	sort.Ints(colorIDs)
+
+	report := NestReport{Spacing: opts.Spacing, Colors: []ColorNest{}}
+	for _, id := range colorIDs {
+		sheet := opts.Sheet
+		if s, ok := opts.SheetsByColor[id]; ok && s.Width > 0 && s.Height > 0 {
+			sheet = s
+		}
+		cn := nestColor(id, byColor[id], sheet, opts.Spacing)
+		report.TotalSheets += cn.SheetsNeeded
+		report.Colors = append(report.Colors, cn)
+	}
+
+	return report, nil
+}
+
+type shelf struct {
+	y, height, used float64
+}
+
+type sheetState struct {
+	shelves    []shelf
+	placements []Placement
+	area       float64
+}
+
+// oriented is a piece in the orientation it will be placed in.
+type oriented struct {
+	piece   NestPiece
+	w, h    float64
+	rotated bool
+}
+
+func nestColor(colorID int, pieces []NestPiece, sheet SheetSize, spacing float64) ColorNest {
+	cn := ColorNest{
+		GlassColorID: colorID,
+		Sheet:        sheet,
+		PieceCount:   len(pieces),
+		Sheets:       []NestSheet{},
+		Oversize:     []NestPiece{},
+	}
+
+	fits := func(w, h float64) bool {
+		return w+2*spacing <= sheet.Width+1e-9 && h+2*spacing <= sheet.Height+1e-9
+	}
+
+	items := make([]oriented, 0, len(pieces))
+	for _, p := range pieces {
+		flatW, flatH := math.Max(p.Width, p.Height), math.Min(p.Width, p.Height)
+		flatRotated := p.Height > p.Width
+		switch {
+		case fits(flatW, flatH):
+			items = append(items, oriented{p, flatW, flatH, flatRotated})
+		case fits(flatH, flatW):
+			items = append(items, oriented{p, flatH, flatW, !flatRotated})
+		default:
+			cn.Oversize = append(cn.Oversize, p)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].h != items[j].h {
+			return items[i].h > items[j].h
+		}
+		if items[i].w != items[j].w {
+			return items[i].w > items[j].w
+		}
+		if items[i].piece.InlayRef != items[j].piece.InlayRef {
+			return items[i].piece.InlayRef < items[j].piece.InlayRef
+		}
+		return items[i].piece.PieceID < items[j].piece.PieceID
+	})
+
+	var sheets []*sheetState
+	for _, it := range items {
+		placed := false
+		for _, s := range sheets {
+			if placeOnSheet(s, it, sheet, spacing) {
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			s := &sheetState{}
+			placeOnSheet(s, it, sheet, spacing)
+			sheets = append(sheets, s)
+		}
+	}
+
+	sheetArea := sheet.Width * sheet.Height
+	usedArea := 0.0
+	for i, s := range sheets {
+		cn.Sheets = append(cn.Sheets, NestSheet{
+			Index:       i + 1,
+			Utilization: roundRatio(s.area / sheetArea),
+			Placements:  s.placements,
+		})
+		usedArea += s.area
+	}
+	cn.SheetsNeeded = len(sheets)
+	if len(sheets) > 0 {
+		cn.Utilization = roundRatio(usedArea / (sheetArea * float64(len(sheets))))
+	}
+
+	return cn
+}
+
+// placeOnSheet tries every existing shelf, then a new shelf below the last.
+func placeOnSheet(s *sheetState, it oriented, sheet SheetSize, spacing float64) bool {
+	for i := range s.shelves {
+		sh := &s.shelves[i]
+		if it.h <= sh.height+1e-9 && sh.used+it.w+spacing <= sheet.Width+1e-9 {
+			place(s, it, sh.used, sh.y)
+			sh.used += it.w + spacing
+			return true
+		}
+	}
+
+	y := spacing
+	if n := len(s.shelves); n > 0 {
+		y = s.shelves[n-1].y + s.shelves[n-1].height + spacing
+	}
+	if y+it.h+spacing > sheet.Height+1e-9 {
+		return false
+	}
+	s.shelves = append(s.shelves, shelf{y: y, height: it.h, used: spacing + it.w + spacing})
+	place(s, it, spacing, y)
+	return true
+}
+
+func place(s *sheetState, it oriented, x, y float64) {
+	outline := it.piece.Outline
+	if it.rotated {
+		outline = rotateOutline(outline, it.piece.Height)
+	}
+	s.placements = append(s.placements, Placement{
+		InlayRef: it.piece.InlayRef,
+		PieceID:  it.piece.PieceID,
+		X:        x,
+		Y:        y,
+		Width:    it.w,
+		Height:   it.h,
+		Rotated:  it.rotated,
+		outline:  outline,
+	})
+	s.area += it.piece.Area
+}
+
+// rotateOutline turns an outline 90° clockwise about its bounding box so that
+// the result is again anchored at the origin.
+func rotateOutline(polys [][]Point, height float64) [][]Point {
+	out := make([][]Point, len(polys))
+	for i, poly := range polys {
+		rp := make([]Point, len(poly))
+		for j, p := range poly {
+			rp[j] = Point{X: height - p.Y, Y: p.X}
+		}
+		out[i] = rp
+	}
+	return out
+}
+
+func roundRatio(f float64) float64 {
+	return math.Round(f*10000) / 10000
+}
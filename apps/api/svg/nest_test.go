@@ -0,0 +1,171 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A baked 2in x 1in design: viewBox at 300 units/inch with content inside the
+// gac-fit wrapper scaled by 2, plus a grout backing that must not be nested.
+const svgBakedForNesting = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 600 300">
+  <g id="gac-fit" transform="translate(0 0) scale(2)">
+    <rect id="p0" x="0" y="0" width="300" height="150" style="fill:#111111" data-grout-id="1"/>
+    <rect id="p1" x="0" y="0" width="75" height="150" style="fill:#ff0000" data-glass-color-id="7"/>
+    <path id="p2" d="M150 0 h150 v75 h-150 z" style="fill:#00ff00" data-glass-color-id="8"/>
+    <circle id="p3" cx="225" cy="112.5" r="37.5" style="fill:#00ff00" data-glass-color-id="8"/>
+  </g>
+</svg>`
+
+func TestExtractPieces_SkipsGroutAndConvertsToInches(t *testing.T) {
+	pieces, err := ExtractPieces([]byte(svgBakedForNesting), 2, 1)
+	require.NoError(t, err)
+	require.Len(t, pieces, 3)
+
+	byID := map[string]NestPiece{}
+	for _, p := range pieces {
+		byID[p.PieceID] = p
+	}
+	assert.NotContains(t, byID, "p0", "grout is poured, not cut")
+
+	p1 := byID["p1"]
+	assert.Equal(t, 7, p1.GlassColorID)
+	assert.InDelta(t, 0.5, p1.Width, 1e-9)
+	assert.InDelta(t, 1.0, p1.Height, 1e-9)
+	assert.InDelta(t, 0.5, p1.Area, 1e-9)
+
+	p2 := byID["p2"]
+	assert.Equal(t, 8, p2.GlassColorID)
+	assert.InDelta(t, 1.0, p2.Width, 1e-9)
+	assert.InDelta(t, 0.5, p2.Height, 1e-9)
+
+	p3 := byID["p3"]
+	assert.InDelta(t, 0.5, p3.Width, 1e-3)
+	assert.InDelta(t, 0.5, p3.Height, 1e-3)
+}
+
+func TestExtractPieces_ScalesToSnapshotDimensions(t *testing.T) {
+	pieces, err := ExtractPieces([]byte(svgBakedForNesting), 4, 2)
+	require.NoError(t, err)
+
+	for _, p := range pieces {
+		if p.PieceID == "p1" {
+			assert.InDelta(t, 1.0, p.Width, 1e-9)
+			assert.InDelta(t, 2.0, p.Height, 1e-9)
+		}
+	}
+}
+
+func TestFlattenPath_HandlesRelativeCommandsAndArcs(t *testing.T) {
+	polys := flattenPath("m10 10 l10 0 0 10 -10 0z M0 0 A5 5 0 0 1 10 0 A5 5 0 0 1 0 0Z")
+	require.Len(t, polys, 2)
+
+	assert.InDelta(t, 100, polygonArea(polys[:1]), 1e-9)
+	// Two half-circle arcs of radius 5 make a full circle.
+	assert.InDelta(t, 78.54, polygonArea(polys[1:]), 0.5)
+}
+
+func TestParseTransform_ComposesLeftToRight(t *testing.T) {
+	m := parseTransform("translate(10 20) scale(2)")
+	p := m.apply(Point{X: 1, Y: 1})
+	assert.InDelta(t, 12, p.X, 1e-9)
+	assert.InDelta(t, 22, p.Y, 1e-9)
+}
+
+func TestNest_GroupsByColorAndFillsSheets(t *testing.T) {
+	var pieces []NestPiece
+	for i := 0; i < 8; i++ {
+		pieces = append(pieces, NestPiece{
+			PieceID:      "p" + string(rune('0'+i)),
+			GlassColorID: 1,
+			Width:        5,
+			Height:       5,
+			Area:         25,
+			Outline:      [][]Point{{{0, 0}, {5, 0}, {5, 5}, {0, 5}}},
+		})
+	}
+	pieces = append(pieces, NestPiece{PieceID: "q0", GlassColorID: 2, Width: 2, Height: 3, Area: 6})
+
+	report, err := Nest(pieces, NestOptions{Sheet: SheetSize{Width: 10, Height: 10}})
+	require.NoError(t, err)
+	require.Len(t, report.Colors, 2)
+
+	c1 := report.Colors[0]
+	assert.Equal(t, 1, c1.GlassColorID)
+	assert.Equal(t, 8, c1.PieceCount)
+	assert.Equal(t, 2, c1.SheetsNeeded, "four 5x5 pieces per 10x10 sheet")
+	assert.InDelta(t, 1.0, c1.Utilization, 1e-9)
+
+	c2 := report.Colors[1]
+	assert.Equal(t, 1, c2.SheetsNeeded)
+	assert.Equal(t, 3, report.TotalSheets)
+}
+
+func TestNest_RotatesPiecesFlatAndHonorsSpacing(t *testing.T) {
+	pieces := []NestPiece{{PieceID: "tall", GlassColorID: 1, Width: 2, Height: 8, Area: 16}}
+
+	report, err := Nest(pieces, NestOptions{Sheet: SheetSize{Width: 10, Height: 4}, Spacing: 0.5})
+	require.NoError(t, err)
+
+	placements := report.Colors[0].Sheets[0].Placements
+	require.Len(t, placements, 1)
+	assert.True(t, placements[0].Rotated)
+	assert.InDelta(t, 8, placements[0].Width, 1e-9)
+	assert.InDelta(t, 0.5, placements[0].X, 1e-9)
+	assert.InDelta(t, 0.5, placements[0].Y, 1e-9)
+}
+
+func TestNest_ReportsOversizePieces(t *testing.T) {
+	pieces := []NestPiece{{PieceID: "huge", GlassColorID: 1, Width: 30, Height: 30, Area: 900}}
+
+	report, err := Nest(pieces, NestOptions{Sheet: SheetSize{Width: 24, Height: 18}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, report.Colors[0].SheetsNeeded)
+	require.Len(t, report.Colors[0].Oversize, 1)
+	assert.Equal(t, "huge", report.Colors[0].Oversize[0].PieceID)
+}
+
+func TestNest_UsesPerColorSheetSize(t *testing.T) {
+	pieces := []NestPiece{{PieceID: "p0", GlassColorID: 3, Width: 15, Height: 15, Area: 225}}
+
+	report, err := Nest(pieces, NestOptions{
+		Sheet:         SheetSize{Width: 12, Height: 12},
+		SheetsByColor: map[int]SheetSize{3: {Width: 20, Height: 20}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, SheetSize{Width: 20, Height: 20}, report.Colors[0].Sheet)
+	assert.Equal(t, 1, report.Colors[0].SheetsNeeded)
+	assert.Empty(t, report.Colors[0].Oversize)
+}
+
+func TestNest_RejectsInvalidSheet(t *testing.T) {
+	_, err := Nest(nil, NestOptions{Sheet: SheetSize{Width: 0, Height: 10}})
+	assert.Error(t, err)
+}
+
+func TestRenderSheet_EmitsSVGAndDXF(t *testing.T) {
+	pieces, err := ExtractPieces([]byte(svgBakedForNesting), 2, 1)
+	require.NoError(t, err)
+	for i := range pieces {
+		pieces[i].InlayRef = "inlay"
+	}
+
+	size := SheetSize{Width: 12, Height: 12}
+	report, err := Nest(pieces, NestOptions{Sheet: size})
+	require.NoError(t, err)
+	sheet := report.Colors[1].Sheets[0]
+
+	out, err := RenderSheetSVG(sheet, size)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `viewBox="0 0 12 12"`)
+	assert.Contains(t, string(out), `id="inlay-p2"`)
+	assert.Contains(t, string(out), `id="inlay-p3"`)
+
+	dxf := string(RenderSheetDXF(sheet, size))
+	assert.True(t, strings.HasSuffix(dxf, "0\nEOF\n"))
+	assert.Equal(t, 3, strings.Count(dxf, "POLYLINE"), "sheet outline plus two pieces")
+}
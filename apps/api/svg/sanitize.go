@@ -0,0 +1,176 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// Limits that bound the work a single SVG can cause. Real inlay artwork is a
+// few thousand shapes at most a dozen groups deep; anything far past that is
+// either broken or hostile.
+const (
+	maxElements = 100000
+	maxDepth    = 64
+)
+
+// Elements that can execute script, embed foreign content, or pull in another
+// document. They are removed outright along with their subtree.
+var blockedTags = map[string]bool{
+	"script":        true,
+	"foreignobject": true,
+	"iframe":        true,
+	"embed":         true,
+	"object":        true,
+	"handler":       true,
+	"listener":      true,
+}
+
+// Animation elements can rewrite an attribute at runtime, so one targeting an
+// href or event handler is as dangerous as writing that attribute directly.
+var animationTags = map[string]bool{
+	"set":              true,
+	"animate":          true,
+	"animatemotion":    true,
+	"animatetransform": true,
+	"animatecolor":     true,
+}
+
+var (
+	cssURLRe      = regexp.MustCompile(`(?i)url\(\s*['"]?\s*([^'")]*?)\s*['"]?\s*\)`)
+	cssImportRe   = regexp.MustCompile(`(?i)@import[^;]*;?`)
+	safeDataURIRe = regexp.MustCompile(`(?i)^data:image/(png|jpe?g|gif|webp)[;,]`)
+)
+
+// Sanitize parses an SVG, rejects it if it exceeds the element-count or nesting
+// limits or declares entities, and returns it re-serialized without scripts,
+// event handler attributes, foreignObject, external references or DOCTYPE.
+func Sanitize(raw []byte) ([]byte, error) {
+	doc, _, err := parseRoot(raw)
+	if err != nil {
+		return nil, err
+	}
+	return doc.WriteToBytes()
+}
+
+// checkLimits streams the raw document before any tree is built, so an XML bomb
+// is refused without ever being materialized.
+func checkLimits(raw []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(raw))
+	elements, depth := 0, 0
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parse svg: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			elements++
+			depth++
+			if elements > maxElements {
+				return fmt.Errorf("svg exceeds %d elements", maxElements)
+			}
+			if depth > maxDepth {
+				return fmt.Errorf("svg exceeds nesting depth of %d", maxDepth)
+			}
+		case xml.EndElement:
+			depth--
+		case xml.Directive:
+			if bytes.Contains(bytes.ToUpper(t), []byte("ENTITY")) {
+				return fmt.Errorf("svg declares entities, which are not allowed")
+			}
+		}
+	}
+}
+
+// sanitizeTree strips everything unsafe from a parsed document in place.
+func sanitizeTree(doc *etree.Document) {
+	for _, tok := range append([]etree.Token(nil), doc.Child...) {
+		switch t := tok.(type) {
+		case *etree.Directive:
+			doc.RemoveChild(t)
+		case *etree.ProcInst:
+			if t.Target != "xml" {
+				doc.RemoveChild(t)
+			}
+		}
+	}
+	if root := doc.Root(); root != nil {
+		sanitizeElement(root)
+	}
+}
+
+func sanitizeElement(el *etree.Element) {
+	attrs := append([]etree.Attr(nil), el.Attr...)
+	for _, a := range attrs {
+		if !isSafeAttr(a) {
+			el.RemoveAttr(a.FullKey())
+		}
+	}
+
+	if strings.EqualFold(localName(el.Tag), "style") {
+		el.SetText(sanitizeCSS(el.Text()))
+	}
+	if style := el.SelectAttr("style"); style != nil {
+		style.Value = sanitizeCSS(style.Value)
+	}
+
+	for _, child := range el.ChildElements() {
+		tag := strings.ToLower(localName(child.Tag))
+		if blockedTags[tag] || (animationTags[tag] && animatesUnsafeAttr(child)) {
+			el.RemoveChild(child)
+			continue
+		}
+		sanitizeElement(child)
+	}
+}
+
+func animatesUnsafeAttr(el *etree.Element) bool {
+	name := strings.ToLower(localName(el.SelectAttrValue("attributeName", "")))
+	return name == "href" || strings.HasPrefix(name, "on")
+}
+
+func isSafeAttr(a etree.Attr) bool {
+	key := strings.ToLower(a.Key)
+	value := strings.TrimSpace(a.Value)
+
+	if strings.HasPrefix(key, "on") {
+		return false
+	}
+	if key == "href" || key == "src" || (key == "base" && strings.EqualFold(a.Space, "xml")) {
+		return strings.HasPrefix(value, "#") || safeDataURIRe.MatchString(value)
+	}
+	if strings.Contains(strings.ToLower(value), "javascript:") {
+		return false
+	}
+	if key != "style" {
+		for _, m := range cssURLRe.FindAllStringSubmatch(value, -1) {
+			if !strings.HasPrefix(m[1], "#") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sanitizeCSS drops @import rules and neutralizes url() references that point
+// anywhere but a fragment in this same document.
+func sanitizeCSS(css string) string {
+	css = cssImportRe.ReplaceAllString(css, "")
+	return cssURLRe.ReplaceAllStringFunc(css, func(m string) string {
+		if sub := cssURLRe.FindStringSubmatch(m); sub != nil && strings.HasPrefix(sub[1], "#") {
+			return m
+		}
+		return "none"
+	})
+}
@@ -0,0 +1,103 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const svgHostile = `<?xml version="1.0" encoding="UTF-8"?>
+<?xml-stylesheet href="https://evil.example/x.css"?>
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 10 10" onload="alert(1)">
+  <script>alert(1)</script>
+  <style>@import url(https://evil.example/a.css); .st0{fill:#123456;background:url(https://evil.example/b.png)}</style>
+  <foreignObject><div xmlns="http://www.w3.org/1999/xhtml">hi</div></foreignObject>
+  <a href="javascript:alert(1)"><rect id="keep" class="st0" width="1" height="1" onclick="alert(1)"/></a>
+  <use xlink:href="https://evil.example/sprite.svg#a"/>
+  <use href="#keep"/>
+  <g xml:base="https://evil.example/"><use href="#keep"/></g>
+  <image href="data:image/png;base64,AAAA"/>
+  <image href="data:image/svg+xml;base64,AAAA"/>
+  <rect fill="url(https://evil.example/p.svg#g)" width="1" height="1"/>
+  <rect fill="url(#grad)" width="1" height="1"/>
+  <set attributeName="href" to="javascript:alert(1)"/>
+  <animate attributeName="opacity" from="0" to="1"/>
+</svg>`
+
+func TestSanitize_StripsActiveAndExternalContent(t *testing.T) {
+	out, err := Sanitize([]byte(svgHostile))
+	require.NoError(t, err)
+	s := string(out)
+
+	for _, bad := range []string{
+		"<script", "onload", "onclick", "foreignObject", "javascript:",
+		"evil.example", "@import", "xml-stylesheet", "data:image/svg+xml", "<set",
+	} {
+		assert.NotContains(t, s, bad)
+	}
+
+	assert.Contains(t, s, `id="keep"`, "shapes survive")
+	assert.Contains(t, s, `href="#keep"`, "local references survive")
+	assert.Contains(t, s, "data:image/png", "embedded raster survives")
+	assert.Contains(t, s, `fill="url(#grad)"`)
+	assert.Contains(t, s, ".st0{fill:#123456;", "class fills survive for ingest")
+	assert.Contains(t, s, "<animate", "harmless animation survives")
+}
+
+func TestSanitize_RejectsEntityDeclarations(t *testing.T) {
+	bomb := `<?xml version="1.0"?>
+<!DOCTYPE svg [<!ENTITY a "aaaaaaaaaa"><!ENTITY b "&a;&a;&a;&a;&a;&a;&a;&a;&a;&a;">]>
+<svg xmlns="http://www.w3.org/2000/svg">&b;</svg>`
+
+	_, err := Sanitize([]byte(bomb))
+	assert.Error(t, err)
+}
+
+func TestSanitize_DropsPlainDoctype(t *testing.T) {
+	in := `<!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.1//EN" "http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd">
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1"><rect width="1" height="1"/></svg>`
+
+	out, err := Sanitize([]byte(in))
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "DOCTYPE")
+}
+
+func TestSanitize_RejectsExcessiveNesting(t *testing.T) {
+	in := `<svg xmlns="http://www.w3.org/2000/svg">` +
+		strings.Repeat("<g>", maxDepth) + strings.Repeat("</g>", maxDepth) + `</svg>`
+
+	_, err := Sanitize([]byte(in))
+	assert.ErrorContains(t, err, "nesting depth")
+}
+
+func TestSanitize_RejectsTooManyElements(t *testing.T) {
+	in := `<svg xmlns="http://www.w3.org/2000/svg">` +
+		strings.Repeat("<rect/>", maxElements) + `</svg>`
+
+	_, err := Sanitize([]byte(in))
+	assert.ErrorContains(t, err, "elements")
+}
+
+func TestIngest_SanitizesSource(t *testing.T) {
+	in := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10">
+  <defs><style>.st0{fill:#222222;}</style></defs>
+  <script>alert(1)</script>
+  <path class="st0" d="M0 0h10v10H0z" onmouseover="alert(1)"/>
+  <path class="st0" d="M1 1h1v1H1z"/>
+</svg>`
+
+	structure, manifest, _, err := Ingest([]byte(in), nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, manifest)
+	assert.NotContains(t, string(structure), "script")
+	assert.NotContains(t, string(structure), "onmouseover")
+}
+
+func TestBake_RejectsEntityDeclarations(t *testing.T) {
+	in := `<!DOCTYPE svg [<!ENTITY x "boom">]><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1"/>`
+
+	_, err := Bake([]byte(in), Manifest{}, ContentBBox{}, 1, 1, ColorOverrides{}, nil, nil)
+	assert.Error(t, err)
+}
@@ -482,7 +482,7 @@ func TestBake_UnknownGlassColorIDErrors(t *testing.T) {
 func TestBakeConsumer_KeepsManifestViewBoxAndScales(t *testing.T) {
 	manifest, structureSVG := bakedManifest(t, svgMultiClass)
 
-	out, err := BakeConsumer(structureSVG, *manifest, 2.0, ColorOverrides{}, nil, nil)
+	out, err := BakeConsumer(structureSVG, *manifest, 2.0, ColorOverrides{}, nil, nil, nil)
 	require.NoError(t, err)
 
 	doc := etree.NewDocument()
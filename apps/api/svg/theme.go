@@ -0,0 +1,139 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// seedHueThreshold is the widest hue angle, in degrees, between a seed color
+// and a palette color for the palette color to count as "in" the seed's
+// colorway.
+const seedHueThreshold = 30.0
+
+// neutralChroma is the Lab chroma under which a color reads as a grey. Hue is
+// meaningless there, so a neutral seed picks neutrals instead.
+const neutralChroma = 10.0
+
+// ThemeCandidatesFromSeed narrows a palette to the colors that share a seed
+// color's hue, or to the neutrals when the seed is itself a grey.
+func ThemeCandidatesFromSeed(seedHex string, palette []PaletteColor) ([]PaletteColor, error) {
+	seed, ok := hexToLab(seedHex)
+	if !ok {
+		return nil, fmt.Errorf("invalid seed color %q", seedHex)
+	}
+
+	var candidates []PaletteColor
+	for _, c := range palette {
+		lab, ok := hexToLab(c.Hex)
+		if !ok {
+			continue
+		}
+		if chroma(seed) < neutralChroma {
+			if chroma(lab) < neutralChroma {
+				candidates = append(candidates, c)
+			}
+			continue
+		}
+		if chroma(lab) >= neutralChroma && hueDistance(seed, lab) <= seedHueThreshold {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, nil
+}
+
+// Theme proposes group overrides that recolor every glass region of a design
+// from candidates. Regions are ranked by the CIELAB lightness of their source
+// color and spread across the candidates ranked the same way, so what was the
+// darkest glass stays the darkest and the lightest stays the lightest. With at
+// least as many candidates as regions every region gets its own color.
+//
+// A region's source color is its ingested source_hex, falling back to its
+// default glass color's hex in glassHexByID. Regions with neither keep their
+// default. Grout is left alone.
+func Theme(manifest Manifest, glassHexByID map[int]string, candidates []PaletteColor) (ColorOverrides, error) {
+	var palette []themeColor
+	for _, c := range candidates {
+		if lab, ok := hexToLab(c.Hex); ok {
+			palette = append(palette, themeColor{id: c.ID, l: lab.L})
+		}
+	}
+	if len(palette) == 0 {
+		return ColorOverrides{}, fmt.Errorf("no glass colors to theme with")
+	}
+	sort.SliceStable(palette, func(i, j int) bool {
+		if palette[i].l != palette[j].l {
+			return palette[i].l < palette[j].l
+		}
+		return palette[i].id < palette[j].id
+	})
+
+	var regions []themeColor
+	for key, region := range manifest.GlassRegions {
+		hex := ""
+		if region.SourceHex != nil {
+			hex = *region.SourceHex
+		} else if region.GlassColorID != nil {
+			hex = glassHexByID[*region.GlassColorID]
+		}
+		if lab, ok := hexToLab(hex); ok {
+			regions = append(regions, themeColor{key: key, l: lab.L})
+		}
+	}
+	if len(regions) == 0 {
+		return ColorOverrides{}, fmt.Errorf("design has no glass regions with a known color")
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		if regions[i].l != regions[j].l {
+			return regions[i].l < regions[j].l
+		}
+		return regions[i].key < regions[j].key
+	})
+
+	overrides := ColorOverrides{Groups: make(map[string]GlassColorRef, len(regions))}
+	if len(regions) == 1 {
+		overrides.Groups[regions[0].key] = GlassColorRef{GlassColorID: nearestLightness(regions[0].l, palette)}
+		return overrides, nil
+	}
+
+	last := float64(len(palette) - 1)
+	for i, region := range regions {
+		idx := int(math.Round(float64(i) * last / float64(len(regions)-1)))
+		overrides.Groups[region.key] = GlassColorRef{GlassColorID: palette[idx].id}
+	}
+	return overrides, nil
+}
+
+// themeColor is a palette color or a design region placed on the lightness
+// axis.
+type themeColor struct {
+	key string
+	id  int
+	l   float64
+}
+
+// nearestLightness picks the candidate closest in lightness to l. A lone region
+// has no ordering to preserve, so it keeps its own lightness instead.
+func nearestLightness(l float64, palette []themeColor) int {
+	best, bestDist := palette[0].id, math.Inf(1)
+	for _, c := range palette {
+		if d := math.Abs(c.l - l); d < bestDist {
+			best, bestDist = c.id, d
+		}
+	}
+	return best
+}
+
+func chroma(c labColor) float64 {
+	return math.Hypot(c.A, c.B)
+}
+
+// hueDistance is the angle in degrees between two colors' hues on the a*b*
+// plane.
+func hueDistance(a, b labColor) float64 {
+	d := math.Abs(math.Atan2(a.B, a.A)-math.Atan2(b.B, b.A)) * 180 / math.Pi
+	if d > 180 {
+		d = 360 - d
+	}
+	return d
+}
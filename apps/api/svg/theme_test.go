@@ -0,0 +1,91 @@
+package svg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func themeManifest() Manifest {
+	dark, mid, light := "#202020", "#808080", "#e0e0e0"
+	return Manifest{GlassRegions: map[string]GlassRegion{
+		"group-0": {SourceHex: &light},
+		"group-1": {SourceHex: &dark},
+		"group-2": {SourceHex: &mid},
+	}}
+}
+
+func TestTheme_PreservesLightnessOrder(t *testing.T) {
+	blues := []PaletteColor{
+		{ID: 11, Hex: "#a8c8f0"}, // light
+		{ID: 12, Hex: "#0a1a50"}, // dark
+		{ID: 13, Hex: "#2e5fb0"}, // mid
+		{ID: 14, Hex: "#6090d8"},
+	}
+
+	overrides, err := Theme(themeManifest(), nil, blues)
+	require.NoError(t, err)
+
+	assert.Equal(t, 12, overrides.Groups["group-1"].GlassColorID, "darkest region takes the darkest blue")
+	assert.Equal(t, 11, overrides.Groups["group-0"].GlassColorID, "lightest region takes the lightest blue")
+	mid := overrides.Groups["group-2"].GlassColorID
+	assert.Contains(t, []int{13, 14}, mid)
+	assert.Nil(t, overrides.Background, "grout is not themed")
+}
+
+func TestTheme_FewerCandidatesThanRegions(t *testing.T) {
+	overrides, err := Theme(themeManifest(), nil, []PaletteColor{{ID: 1, Hex: "#0a1a50"}, {ID: 2, Hex: "#a8c8f0"}})
+	require.NoError(t, err)
+	require.Len(t, overrides.Groups, 3)
+	assert.Equal(t, 1, overrides.Groups["group-1"].GlassColorID)
+	assert.Equal(t, 2, overrides.Groups["group-0"].GlassColorID)
+}
+
+func TestTheme_FallsBackToDefaultColorHex(t *testing.T) {
+	darkID, lightID := 1, 2
+	manifest := Manifest{GlassRegions: map[string]GlassRegion{
+		"group-0": {GlassColorID: &lightID},
+		"group-1": {GlassColorID: &darkID},
+	}}
+	hexByID := map[int]string{darkID: "#000000", lightID: "#ffffff"}
+
+	overrides, err := Theme(manifest, hexByID, []PaletteColor{{ID: 20, Hex: "#f0a0a0"}, {ID: 21, Hex: "#600000"}})
+	require.NoError(t, err)
+	assert.Equal(t, 21, overrides.Groups["group-1"].GlassColorID)
+	assert.Equal(t, 20, overrides.Groups["group-0"].GlassColorID)
+}
+
+func TestTheme_NoCandidates(t *testing.T) {
+	_, err := Theme(themeManifest(), nil, nil)
+	assert.Error(t, err)
+}
+
+func TestThemeCandidatesFromSeed(t *testing.T) {
+	palette := []PaletteColor{
+		{ID: 1, Hex: "#0a1a50"}, // navy
+		{ID: 2, Hex: "#6090d8"}, // cornflower
+		{ID: 3, Hex: "#c02020"}, // red
+		{ID: 4, Hex: "#808080"}, // grey
+		{ID: 5, Hex: "#f0f0f0"}, // white
+	}
+
+	blues, err := ThemeCandidatesFromSeed("#2e5fb0", palette)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{1, 2}, paletteIDs(blues))
+
+	neutrals, err := ThemeCandidatesFromSeed("#777777", palette)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{4, 5}, paletteIDs(neutrals))
+
+	_, err = ThemeCandidatesFromSeed("blue-ish", palette)
+	assert.Error(t, err)
+}
+
+func paletteIDs(colors []PaletteColor) []int {
+	ids := make([]int, len(colors))
+	for i, c := range colors {
+		ids[i] = c.ID
+	}
+	return ids
+}
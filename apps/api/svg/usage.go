@@ -0,0 +1,46 @@
+package svg
+
+import (
+	"strconv"
+)
+
+// Usage is the material one inlay consumes, in square inches. Glass is the
+// summed area of every piece of each color. Grout fills whatever the glass does
+// not cover, so its area is the inlay's footprint less all of the glass.
+type Usage struct {
+	GlassByColor map[int]float64 `json:"glass_by_color"`
+	GroutID      *int            `json:"grout_id"`
+	GroutArea    float64         `json:"grout_area"`
+}
+
+// MeasureUsage computes the Usage of a baked design ordered at width x height
+// inches. Like ExtractPieces, a zero dimension falls back to the canonical 300
+// units/inch density. Piece areas are net of holes, so no allowance for scoring
+// waste is included; stock planning adds that on top.
+func MeasureUsage(design []byte, width, height float64) (Usage, error) {
+	_, root, err := parseRoot(design)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	pieces, width, height, err := extractPieces(root, width, height)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	usage := Usage{GlassByColor: map[int]float64{}}
+	glassArea := 0.0
+	for _, piece := range pieces {
+		usage.GlassByColor[piece.GlassColorID] += piece.Area
+		glassArea += piece.Area
+	}
+
+	if el := root.FindElement("//*[@data-grout-id]"); el != nil {
+		if id, err := strconv.Atoi(el.SelectAttrValue("data-grout-id", "")); err == nil {
+			usage.GroutID = &id
+			usage.GroutArea = max(width*height-glassArea, 0)
+		}
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,44 @@
+package svg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasureUsage_SumsGlassPerColorAndGroutRemainder(t *testing.T) {
+	usage, err := MeasureUsage([]byte(svgBakedForNesting), 2, 1)
+	require.NoError(t, err)
+
+	circle := math.Pi * 0.25 * 0.25
+	assert.InDelta(t, 0.5, usage.GlassByColor[7], 1e-9)
+	assert.InDelta(t, 0.5+circle, usage.GlassByColor[8], 1e-2)
+
+	require.NotNil(t, usage.GroutID)
+	assert.Equal(t, 1, *usage.GroutID)
+	assert.InDelta(t, 2-1-circle, usage.GroutArea, 1e-2)
+}
+
+func TestMeasureUsage_ScalesWithOrderedSize(t *testing.T) {
+	small, err := MeasureUsage([]byte(svgBakedForNesting), 2, 1)
+	require.NoError(t, err)
+	large, err := MeasureUsage([]byte(svgBakedForNesting), 4, 2)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 4*small.GlassByColor[7], large.GlassByColor[7], 1e-9)
+	assert.InDelta(t, 4*small.GroutArea, large.GroutArea, 1e-2)
+}
+
+func TestMeasureUsage_NoGroutWithoutGroutPieces(t *testing.T) {
+	design := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 300 300">
+  <rect id="p1" width="300" height="300" data-glass-color-id="3"/>
+</svg>`
+
+	usage, err := MeasureUsage([]byte(design), 0, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, usage.GlassByColor[3], 1e-9)
+	assert.Nil(t, usage.GroutID)
+	assert.Zero(t, usage.GroutArea)
+}
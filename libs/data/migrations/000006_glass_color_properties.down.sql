@@ -0,0 +1,9 @@
+--------------------------------------------------------------------------------
+-- GLASS COLOR PHYSICAL PROPERTIES
+--------------------------------------------------------------------------------
+
+ALTER TABLE glass_colors
+    DROP COLUMN swatch_image_url,
+    DROP COLUMN opacity,
+    DROP COLUMN texture,
+    DROP COLUMN finish;
@@ -0,0 +1,20 @@
+--------------------------------------------------------------------------------
+-- GLASS COLOR PHYSICAL PROPERTIES
+--
+-- A single hex cannot tell cathedral from opalescent glass, and misrepresents
+-- streaky sheets. finish and texture drive the customizer preview; opacity runs
+-- up to 1 (fully opaque) and is never 0, since even the clearest glass tints
+-- what is behind it. Existing colors default to opaque, smooth cathedral so
+-- their previews render exactly as before until staff fill these in.
+-- swatch_image_url is an optional photo of the sheet, used as the preview
+-- fill in place of the hex when present. Production bakes ignore all of it.
+--------------------------------------------------------------------------------
+
+ALTER TABLE glass_colors
+    ADD COLUMN finish TEXT NOT NULL DEFAULT 'cathedral'
+        CHECK (finish IN ('cathedral', 'opalescent', 'iridescent')),
+    ADD COLUMN texture TEXT NOT NULL DEFAULT 'smooth'
+        CHECK (texture IN ('smooth', 'streaky', 'rippled', 'seedy', 'hammered', 'granite')),
+    ADD COLUMN opacity DOUBLE PRECISION NOT NULL DEFAULT 1
+        CHECK (opacity > 0 AND opacity <= 1),
+    ADD COLUMN swatch_image_url TEXT;
@@ -0,0 +1,39 @@
+--------------------------------------------------------------------------------
+-- LOW STOCK NOTIFICATIONS
+--
+-- Rows for the removed event type would violate the restored constraints, so
+-- they go first.
+--------------------------------------------------------------------------------
+
+DELETE FROM notifications WHERE event_type = 'low_stock';
+DELETE FROM dealership_user_notification_prefs WHERE event_type = 'low_stock';
+DELETE FROM internal_user_notification_prefs WHERE event_type = 'low_stock';
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_event_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message')
+);
+
+ALTER TABLE dealership_user_notification_prefs DROP CONSTRAINT dealership_user_notification_prefs_event_type_check;
+ALTER TABLE dealership_user_notification_prefs ADD CONSTRAINT dealership_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message')
+);
+
+ALTER TABLE internal_user_notification_prefs DROP CONSTRAINT internal_user_notification_prefs_event_type_check;
+ALTER TABLE internal_user_notification_prefs ADD CONSTRAINT internal_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message')
+);
+
+--------------------------------------------------------------------------------
+-- UNAVAILABLE UNTIL
+--------------------------------------------------------------------------------
+
+ALTER TABLE grouts DROP COLUMN unavailable_until;
+ALTER TABLE glass_colors DROP COLUMN unavailable_until;
+
+--------------------------------------------------------------------------------
+-- MATERIAL STOCK & RESERVATIONS
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS material_reservations;
+DROP TABLE IF EXISTS material_stocks;
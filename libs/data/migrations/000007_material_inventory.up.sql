@@ -0,0 +1,107 @@
+--------------------------------------------------------------------------------
+-- MATERIAL STOCK
+--
+-- One row per glass color or grout that production counts. on_hand is in the
+-- row's unit: whole or partial sheets, or square inches. sheet_area converts
+-- design usage (always square inches) into sheets. on_hand is not constrained
+-- to be non-negative: consuming more than was counted means the count was off,
+-- and the negative number is the honest record of that until the next recount.
+--
+-- low_stock_notified_at remembers that production was already told, so a color
+-- hovering under its threshold does not notify on every order. It is cleared
+-- when stock climbs back above the threshold.
+--------------------------------------------------------------------------------
+
+CREATE TABLE material_stocks (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    glass_color_id INTEGER UNIQUE REFERENCES glass_colors ON DELETE CASCADE,
+    grout_id INTEGER UNIQUE REFERENCES grouts ON DELETE CASCADE,
+    unit TEXT NOT NULL DEFAULT 'square_inches' CHECK (unit IN ('sheets', 'square_inches')),
+    sheet_area DOUBLE PRECISION CHECK (sheet_area > 0),
+    on_hand DOUBLE PRECISION NOT NULL DEFAULT 0,
+    low_stock_threshold DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (low_stock_threshold >= 0),
+    low_stock_notified_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1,
+    CONSTRAINT material_stocks_material_check CHECK (
+        (glass_color_id IS NOT NULL AND grout_id IS NULL) OR
+        (glass_color_id IS NULL AND grout_id IS NOT NULL)
+    ),
+    CONSTRAINT material_stocks_sheet_area_check CHECK (unit <> 'sheets' OR sheet_area IS NOT NULL)
+);
+
+CREATE TRIGGER update_material_stocks_updated_at
+    BEFORE UPDATE ON material_stocks
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_material_stocks_version
+    BEFORE UPDATE ON material_stocks
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+--------------------------------------------------------------------------------
+-- MATERIAL RESERVATIONS
+--
+-- Placing an order reserves each inlay's computed usage against stock, in the
+-- stock's unit. The reservation is consumed (and on_hand decremented) when the
+-- inlay reaches materials-prep, or released if the order is cancelled first.
+-- Available stock is on_hand minus everything still reserved.
+--------------------------------------------------------------------------------
+
+CREATE TABLE material_reservations (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    material_stock_id INTEGER NOT NULL REFERENCES material_stocks ON DELETE CASCADE,
+    project_id INTEGER NOT NULL REFERENCES projects ON DELETE CASCADE,
+    inlay_id INTEGER NOT NULL REFERENCES inlays ON DELETE CASCADE,
+    quantity DOUBLE PRECISION NOT NULL CHECK (quantity >= 0),
+    status TEXT NOT NULL DEFAULT 'reserved' CHECK (status IN ('reserved', 'consumed', 'released')),
+    consumed_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1,
+    UNIQUE(material_stock_id, inlay_id)
+);
+
+CREATE INDEX idx_material_reservations_inlay ON material_reservations(inlay_id);
+CREATE INDEX idx_material_reservations_reserved ON material_reservations(material_stock_id)
+    WHERE status = 'reserved';
+
+CREATE TRIGGER update_material_reservations_updated_at
+    BEFORE UPDATE ON material_reservations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_material_reservations_version
+    BEFORE UPDATE ON material_reservations
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+--------------------------------------------------------------------------------
+-- UNAVAILABLE UNTIL
+--
+-- Set by staff when a color or grout is out and a restock date is known. The
+-- customizer palette still lists it, greyed out, but refuses to apply it until
+-- the date passes.
+--------------------------------------------------------------------------------
+
+ALTER TABLE glass_colors ADD COLUMN unavailable_until TIMESTAMPTZ;
+ALTER TABLE grouts ADD COLUMN unavailable_until TIMESTAMPTZ;
+
+--------------------------------------------------------------------------------
+-- LOW STOCK NOTIFICATIONS
+--------------------------------------------------------------------------------
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_event_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock')
+);
+
+ALTER TABLE dealership_user_notification_prefs DROP CONSTRAINT dealership_user_notification_prefs_event_type_check;
+ALTER TABLE dealership_user_notification_prefs ADD CONSTRAINT dealership_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock')
+);
+
+ALTER TABLE internal_user_notification_prefs DROP CONSTRAINT internal_user_notification_prefs_event_type_check;
+ALTER TABLE internal_user_notification_prefs ADD CONSTRAINT internal_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock')
+);
@@ -0,0 +1,7 @@
+--------------------------------------------------------------------------------
+-- PROJECT SPLITS
+--------------------------------------------------------------------------------
+
+DROP INDEX IF EXISTS idx_projects_parent;
+
+ALTER TABLE projects DROP COLUMN parent_project_id;
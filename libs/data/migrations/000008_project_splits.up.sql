@@ -0,0 +1,13 @@
+--------------------------------------------------------------------------------
+-- PROJECT SPLITS
+--
+-- Placing an order for only some of a project's inlays moves the rest into a
+-- new draft project so they can still be proofed and ordered. The draft points
+-- back at the project it was split from; the link is informational only, so
+-- deleting either side leaves the other intact.
+--------------------------------------------------------------------------------
+
+ALTER TABLE projects
+    ADD COLUMN parent_project_id INTEGER REFERENCES projects ON DELETE SET NULL;
+
+CREATE INDEX idx_projects_parent ON projects(parent_project_id) WHERE parent_project_id IS NOT NULL;
@@ -0,0 +1,6 @@
+--------------------------------------------------------------------------------
+-- SHIPMENTS
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS shipment_inlays;
+DROP TABLE IF EXISTS shipments;
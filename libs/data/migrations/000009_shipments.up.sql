@@ -0,0 +1,63 @@
+--------------------------------------------------------------------------------
+-- SHIPMENTS
+--
+-- A project can leave the shop in several packages, each with its own carrier
+-- and tracking number. shipment_inlays records which inlays went in which
+-- package; an inlay ships exactly once. A project is shipped when every one of
+-- its inlays is in a shipment, and delivered when every one of those shipments
+-- has been delivered.
+--
+-- projects.tracking_number is kept as the tracking number of the most recent
+-- package, for the places that only show one.
+--------------------------------------------------------------------------------
+
+CREATE TABLE shipments (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    project_id INTEGER NOT NULL REFERENCES projects ON DELETE CASCADE,
+    carrier TEXT NOT NULL DEFAULT 'other' CHECK (carrier IN ('ups', 'fedex', 'usps', 'dhl', 'other')),
+    tracking_number TEXT,
+    weight_lbs DOUBLE PRECISION CHECK (weight_lbs > 0),
+    shipped_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX idx_shipments_project ON shipments(project_id);
+
+CREATE TRIGGER update_shipments_updated_at
+    BEFORE UPDATE ON shipments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_shipments_version
+    BEFORE UPDATE ON shipments
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+CREATE TABLE shipment_inlays (
+    id SERIAL PRIMARY KEY,
+    shipment_id INTEGER NOT NULL REFERENCES shipments ON DELETE CASCADE,
+    inlay_id INTEGER UNIQUE NOT NULL REFERENCES inlays ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_shipment_inlays_shipment ON shipment_inlays(shipment_id);
+
+--------------------------------------------------------------------------------
+-- BACKFILL
+--
+-- Projects that already shipped went out as a single package holding all of
+-- their inlays.
+--------------------------------------------------------------------------------
+
+INSERT INTO shipments (project_id, tracking_number, shipped_at, delivered_at)
+SELECT id, tracking_number, updated_at,
+       CASE WHEN status IN ('invoiced', 'completed') THEN updated_at END
+FROM projects
+WHERE status IN ('shipped', 'invoiced', 'completed');
+
+INSERT INTO shipment_inlays (shipment_id, inlay_id)
+SELECT s.id, i.id
+FROM shipments s
+JOIN inlays i ON i.project_id = s.project_id;
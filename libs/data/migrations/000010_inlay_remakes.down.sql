@@ -0,0 +1,52 @@
+--------------------------------------------------------------------------------
+-- REMAKE NOTIFICATIONS
+--
+-- Rows for the removed event types would violate the restored constraints, so
+-- they go first.
+--------------------------------------------------------------------------------
+
+DELETE FROM notifications WHERE event_type IN ('remake_requested', 'remake_reviewed');
+DELETE FROM dealership_user_notification_prefs WHERE event_type IN ('remake_requested', 'remake_reviewed');
+DELETE FROM internal_user_notification_prefs WHERE event_type IN ('remake_requested', 'remake_reviewed');
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_event_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock')
+);
+
+ALTER TABLE dealership_user_notification_prefs DROP CONSTRAINT dealership_user_notification_prefs_event_type_check;
+ALTER TABLE dealership_user_notification_prefs ADD CONSTRAINT dealership_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock')
+);
+
+ALTER TABLE internal_user_notification_prefs DROP CONSTRAINT internal_user_notification_prefs_event_type_check;
+ALTER TABLE internal_user_notification_prefs ADD CONSTRAINT internal_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock')
+);
+
+--------------------------------------------------------------------------------
+-- REMAKE CYCLES
+--
+-- Remake snapshots, packages and milestones have nothing to belong to once the
+-- remakes are gone.
+--------------------------------------------------------------------------------
+
+DELETE FROM shipment_inlays WHERE remake_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_shipment_inlays_original;
+ALTER TABLE shipment_inlays DROP COLUMN remake_id;
+ALTER TABLE shipment_inlays ADD CONSTRAINT shipment_inlays_inlay_id_key UNIQUE (inlay_id);
+
+DELETE FROM order_snapshots WHERE remake_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_order_snapshots_original;
+ALTER TABLE order_snapshots DROP COLUMN remake_id;
+ALTER TABLE order_snapshots ADD CONSTRAINT order_snapshots_inlay_id_key UNIQUE (inlay_id);
+
+DELETE FROM inlay_milestones WHERE cycle > 1;
+ALTER TABLE inlay_milestones DROP COLUMN cycle;
+
+--------------------------------------------------------------------------------
+-- INLAY REMAKES
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS inlay_remake_photos;
+DROP TABLE IF EXISTS inlay_remakes;
@@ -0,0 +1,93 @@
+--------------------------------------------------------------------------------
+-- INLAY REMAKES
+--
+-- A remake is a request to make a delivered inlay again because it arrived
+-- broken or defective. It is opened with a reason and photos by the dealership
+-- or by staff, then approved as free or charged, or declined. An approved remake
+-- sends the same inlay back through the manufacturing ladder as a new cycle;
+-- the original order is cycle 1 and each remake numbers the next.
+--
+-- Only one remake per inlay can be open at a time. A remake is open from the
+-- request until it is delivered or declined.
+--------------------------------------------------------------------------------
+
+CREATE TABLE inlay_remakes (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    inlay_id INTEGER NOT NULL REFERENCES inlays ON DELETE RESTRICT,
+    cycle INTEGER NOT NULL CHECK (cycle >= 2),
+    reason TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'requested' CHECK (status IN ('requested', 'approved', 'declined', 'shipped', 'delivered')),
+    billing TEXT CHECK (billing IN ('free', 'charged')),
+    requested_by_dealership_user_id INTEGER REFERENCES dealership_users ON DELETE SET NULL,
+    requested_by_internal_user_id INTEGER REFERENCES internal_users ON DELETE SET NULL,
+    reviewed_by INTEGER REFERENCES internal_users ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    decline_reason TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1,
+    UNIQUE(inlay_id, cycle),
+    CHECK (status IN ('requested', 'declined') OR billing IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX idx_inlay_remakes_open ON inlay_remakes(inlay_id)
+    WHERE status IN ('requested', 'approved', 'shipped');
+CREATE INDEX idx_inlay_remakes_status ON inlay_remakes(status);
+
+CREATE TRIGGER update_inlay_remakes_updated_at
+    BEFORE UPDATE ON inlay_remakes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_inlay_remakes_version
+    BEFORE UPDATE ON inlay_remakes
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+CREATE TABLE inlay_remake_photos (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    inlay_remake_id INTEGER NOT NULL REFERENCES inlay_remakes ON DELETE CASCADE,
+    image_url TEXT NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_inlay_remake_photos_remake ON inlay_remake_photos(inlay_remake_id);
+
+--------------------------------------------------------------------------------
+-- REMAKE CYCLES
+--
+-- Milestones record which manufacturing cycle they belong to, so a remake's
+-- trip through the ladder does not rewrite the original's timeline. A remake
+-- gets its own order snapshot and ships in its own package; the original
+-- order's snapshot and package stay one per inlay.
+--------------------------------------------------------------------------------
+
+ALTER TABLE inlay_milestones ADD COLUMN cycle INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE order_snapshots ADD COLUMN remake_id INTEGER UNIQUE REFERENCES inlay_remakes ON DELETE RESTRICT;
+ALTER TABLE order_snapshots DROP CONSTRAINT order_snapshots_inlay_id_key;
+CREATE UNIQUE INDEX idx_order_snapshots_original ON order_snapshots(inlay_id) WHERE remake_id IS NULL;
+
+ALTER TABLE shipment_inlays ADD COLUMN remake_id INTEGER UNIQUE REFERENCES inlay_remakes ON DELETE RESTRICT;
+ALTER TABLE shipment_inlays DROP CONSTRAINT shipment_inlays_inlay_id_key;
+CREATE UNIQUE INDEX idx_shipment_inlays_original ON shipment_inlays(inlay_id) WHERE remake_id IS NULL;
+
+--------------------------------------------------------------------------------
+-- REMAKE NOTIFICATIONS
+--------------------------------------------------------------------------------
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_event_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock', 'remake_requested', 'remake_reviewed')
+);
+
+ALTER TABLE dealership_user_notification_prefs DROP CONSTRAINT dealership_user_notification_prefs_event_type_check;
+ALTER TABLE dealership_user_notification_prefs ADD CONSTRAINT dealership_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock', 'remake_requested', 'remake_reviewed')
+);
+
+ALTER TABLE internal_user_notification_prefs DROP CONSTRAINT internal_user_notification_prefs_event_type_check;
+ALTER TABLE internal_user_notification_prefs ADD CONSTRAINT internal_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock', 'remake_requested', 'remake_reviewed')
+);
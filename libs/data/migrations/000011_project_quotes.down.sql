@@ -0,0 +1,8 @@
+--------------------------------------------------------------------------------
+-- PROJECT QUOTES
+--------------------------------------------------------------------------------
+
+ALTER TABLE order_snapshots DROP COLUMN IF EXISTS quote_id;
+
+DROP TABLE IF EXISTS project_quote_lines;
+DROP TABLE IF EXISTS project_quotes;
@@ -0,0 +1,55 @@
+--------------------------------------------------------------------------------
+-- PROJECT QUOTES
+--
+-- A quote is an immutable price breakdown for a draft project, so a dealership
+-- can show a family a price before ordering. Each new quote on a project takes
+-- the next quote number rather than changing an earlier one. Names are copied
+-- onto the quote so it reads the same however the project or price groups
+-- change afterwards.
+--
+-- Placing the order before the latest quote expires locks its prices into the
+-- order snapshots; order_snapshots.quote_id records which quote did so.
+--------------------------------------------------------------------------------
+
+CREATE TABLE project_quotes (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    project_id INTEGER NOT NULL REFERENCES projects ON DELETE CASCADE,
+    quote_number INTEGER NOT NULL CHECK (quote_number >= 1),
+    project_name TEXT NOT NULL,
+    dealership_name TEXT NOT NULL,
+    subtotal_cents INTEGER NOT NULL,
+    installation_kit BOOLEAN NOT NULL DEFAULT false,
+    installation_kit_price_cents INTEGER NOT NULL DEFAULT 0,
+    total_cents INTEGER NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_by_dealership_user_id INTEGER REFERENCES dealership_users ON DELETE SET NULL,
+    created_by_internal_user_id INTEGER REFERENCES internal_users ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE(project_id, quote_number)
+);
+
+CREATE TABLE project_quote_lines (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    quote_id INTEGER NOT NULL REFERENCES project_quotes ON DELETE CASCADE,
+    inlay_id INTEGER REFERENCES inlays ON DELETE SET NULL,
+    inlay_name TEXT NOT NULL,
+    proof_id INTEGER REFERENCES inlay_proofs ON DELETE SET NULL,
+    price_group_id INTEGER NOT NULL,
+    price_group_name TEXT NOT NULL,
+    base_price_cents INTEGER NOT NULL,
+    price_adjustment_type VARCHAR(255) NOT NULL DEFAULT 'none' CHECK (price_adjustment_type IN (
+        'none', 'percent', 'fixed'
+    )),
+    price_adjustment_value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    price_cents INTEGER NOT NULL,
+    width DOUBLE PRECISION NOT NULL,
+    height DOUBLE PRECISION NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_project_quote_lines_quote ON project_quote_lines(quote_id);
+
+ALTER TABLE order_snapshots ADD COLUMN quote_id INTEGER REFERENCES project_quotes ON DELETE SET NULL;
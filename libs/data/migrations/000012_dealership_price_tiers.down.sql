@@ -0,0 +1,15 @@
+--------------------------------------------------------------------------------
+-- DEALERSHIP PRICE TIERS
+--------------------------------------------------------------------------------
+
+ALTER TABLE project_quote_lines
+    DROP COLUMN IF EXISTS price_tier_id,
+    DROP COLUMN IF EXISTS tier_discount_cents;
+
+ALTER TABLE order_snapshots
+    DROP COLUMN IF EXISTS price_tier_id,
+    DROP COLUMN IF EXISTS tier_discount_cents,
+    DROP COLUMN IF EXISTS list_price_cents;
+
+DROP TABLE IF EXISTS dealership_price_tier_overrides;
+DROP TABLE IF EXISTS dealership_price_tiers;
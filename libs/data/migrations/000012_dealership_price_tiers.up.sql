@@ -0,0 +1,66 @@
+--------------------------------------------------------------------------------
+-- DEALERSHIP PRICE TIERS
+--
+-- A tier is a dealership's negotiated rate: a percentage off every price group,
+-- with per-price-group overrides that set the dealership's base price for that
+-- group outright. The tier sets the base price that a proof's adjustment is then
+-- applied to.
+--
+-- Tiers take effect over a date range. When ranges overlap, the tier that took
+-- effect most recently wins, so a new rate can be scheduled without ending the
+-- current one first.
+--------------------------------------------------------------------------------
+
+CREATE TABLE dealership_price_tiers (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    dealership_id INTEGER NOT NULL REFERENCES dealerships ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    discount_percent DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (discount_percent >= 0 AND discount_percent <= 100),
+    effective_from TIMESTAMPTZ NOT NULL DEFAULT now(),
+    effective_until TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1,
+    CHECK (effective_until IS NULL OR effective_until > effective_from)
+);
+
+CREATE INDEX idx_dealership_price_tiers_dealership ON dealership_price_tiers(dealership_id, effective_from);
+
+CREATE TRIGGER update_dealership_price_tiers_updated_at
+    BEFORE UPDATE ON dealership_price_tiers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_dealership_price_tiers_version
+    BEFORE UPDATE ON dealership_price_tiers
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+CREATE TABLE dealership_price_tier_overrides (
+    id SERIAL PRIMARY KEY,
+    tier_id INTEGER NOT NULL REFERENCES dealership_price_tiers ON DELETE CASCADE,
+    price_group_id INTEGER NOT NULL REFERENCES price_groups ON DELETE CASCADE,
+    price_cents INTEGER NOT NULL CHECK (price_cents >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE(tier_id, price_group_id)
+);
+
+--------------------------------------------------------------------------------
+-- TIERED ORDER PRICES
+--
+-- Order snapshots and quote lines keep the list price alongside the tier's
+-- discount, price_cents being the net. Orders placed before tiers existed were
+-- charged list price.
+--------------------------------------------------------------------------------
+
+ALTER TABLE order_snapshots
+    ADD COLUMN list_price_cents INTEGER,
+    ADD COLUMN tier_discount_cents INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN price_tier_id INTEGER REFERENCES dealership_price_tiers ON DELETE SET NULL;
+
+UPDATE order_snapshots SET list_price_cents = price_cents;
+
+ALTER TABLE order_snapshots ALTER COLUMN list_price_cents SET NOT NULL;
+
+ALTER TABLE project_quote_lines
+    ADD COLUMN tier_discount_cents INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN price_tier_id INTEGER REFERENCES dealership_price_tiers ON DELETE SET NULL;
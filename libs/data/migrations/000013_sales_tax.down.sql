@@ -0,0 +1,21 @@
+--------------------------------------------------------------------------------
+-- SALES TAX
+--------------------------------------------------------------------------------
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS total_cents,
+    DROP COLUMN IF EXISTS tax_cents,
+    DROP COLUMN IF EXISTS subtotal_cents;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS tax_exempt_certificate,
+    DROP COLUMN IF EXISTS tax_jurisdiction,
+    DROP COLUMN IF EXISTS tax_rate_percent,
+    DROP COLUMN IF EXISTS tax_cents;
+
+ALTER TABLE dealerships
+    DROP COLUMN IF EXISTS tax_exempt_expires_at,
+    DROP COLUMN IF EXISTS tax_exempt_certificate,
+    DROP COLUMN IF EXISTS tax_exempt;
+
+DROP TABLE IF EXISTS tax_rates;
@@ -0,0 +1,69 @@
+--------------------------------------------------------------------------------
+-- TAX RATES
+--
+-- The local rate table behind the tax engine. A rule covers a whole state, or
+-- only the postal codes starting with its prefix; the longest matching prefix
+-- wins over the state-wide rule.
+--------------------------------------------------------------------------------
+
+CREATE TABLE tax_rates (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    state TEXT NOT NULL,
+    postal_code_prefix TEXT,
+    rate_percent DOUBLE PRECISION NOT NULL CHECK (rate_percent >= 0 AND rate_percent <= 100),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1,
+    UNIQUE NULLS NOT DISTINCT (state, postal_code_prefix)
+);
+
+CREATE TRIGGER update_tax_rates_updated_at
+    BEFORE UPDATE ON tax_rates
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_tax_rates_version
+    BEFORE UPDATE ON tax_rates
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+--------------------------------------------------------------------------------
+-- TAX EXEMPTION
+--
+-- A dealership is exempt while it is flagged, has a certificate on file and the
+-- certificate has not expired.
+--------------------------------------------------------------------------------
+
+ALTER TABLE dealerships
+    ADD COLUMN tax_exempt BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN tax_exempt_certificate TEXT,
+    ADD COLUMN tax_exempt_expires_at DATE;
+
+--------------------------------------------------------------------------------
+-- ORDER TAX
+--
+-- Tax is worked out once, when the order is placed, and locked on the project
+-- next to the installation kit charge. Invoices carry their totals from then on.
+--------------------------------------------------------------------------------
+
+ALTER TABLE projects
+    ADD COLUMN tax_cents INTEGER,
+    ADD COLUMN tax_rate_percent DOUBLE PRECISION,
+    ADD COLUMN tax_jurisdiction TEXT,
+    ADD COLUMN tax_exempt_certificate TEXT;
+
+ALTER TABLE invoices
+    ADD COLUMN subtotal_cents INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN tax_cents INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN total_cents INTEGER NOT NULL DEFAULT 0;
+
+UPDATE invoices SET subtotal_cents = totals.subtotal, total_cents = totals.subtotal
+FROM (
+    SELECT projects.id AS project_id,
+           COALESCE(SUM(order_snapshots.price_cents), 0)
+           + COALESCE(MAX(projects.installation_kit_price_cents), 0) AS subtotal
+    FROM projects
+    LEFT JOIN order_snapshots ON order_snapshots.project_id = projects.id
+    GROUP BY projects.id
+) totals
+WHERE totals.project_id = invoices.project_id;
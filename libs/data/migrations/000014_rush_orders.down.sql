@@ -0,0 +1,34 @@
+--------------------------------------------------------------------------------
+-- RUSH NOTIFICATIONS
+--
+-- Rows for the removed event type would violate the restored constraints, so
+-- they go first.
+--------------------------------------------------------------------------------
+
+DELETE FROM notifications WHERE event_type = 'rush_order_placed';
+DELETE FROM dealership_user_notification_prefs WHERE event_type = 'rush_order_placed';
+DELETE FROM internal_user_notification_prefs WHERE event_type = 'rush_order_placed';
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_event_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock', 'remake_requested', 'remake_reviewed')
+);
+
+ALTER TABLE dealership_user_notification_prefs DROP CONSTRAINT dealership_user_notification_prefs_event_type_check;
+ALTER TABLE dealership_user_notification_prefs ADD CONSTRAINT dealership_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock', 'remake_requested', 'remake_reviewed')
+);
+
+ALTER TABLE internal_user_notification_prefs DROP CONSTRAINT internal_user_notification_prefs_event_type_check;
+ALTER TABLE internal_user_notification_prefs ADD CONSTRAINT internal_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock', 'remake_requested', 'remake_reviewed')
+);
+
+--------------------------------------------------------------------------------
+-- RUSH ORDERS
+--------------------------------------------------------------------------------
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS required_by,
+    DROP COLUMN IF EXISTS rush_surcharge_cents,
+    DROP COLUMN IF EXISTS rush;
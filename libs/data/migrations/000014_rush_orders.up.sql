@@ -0,0 +1,32 @@
+--------------------------------------------------------------------------------
+-- RUSH ORDERS
+--
+-- A rush order jumps the production queue for a surcharge. rush_surcharge_cents
+-- is null until the order is placed, then locks the charge the way
+-- installation_kit_price_cents does. required_by is the date the dealership
+-- needs the order shipped by; production sorts overdue inlays to the top.
+--------------------------------------------------------------------------------
+
+ALTER TABLE projects
+    ADD COLUMN rush BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN rush_surcharge_cents INTEGER CHECK (rush_surcharge_cents >= 0),
+    ADD COLUMN required_by DATE;
+
+--------------------------------------------------------------------------------
+-- RUSH NOTIFICATIONS
+--------------------------------------------------------------------------------
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_event_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock', 'remake_requested', 'remake_reviewed', 'rush_order_placed')
+);
+
+ALTER TABLE dealership_user_notification_prefs DROP CONSTRAINT dealership_user_notification_prefs_event_type_check;
+ALTER TABLE dealership_user_notification_prefs ADD CONSTRAINT dealership_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock', 'remake_requested', 'remake_reviewed', 'rush_order_placed')
+);
+
+ALTER TABLE internal_user_notification_prefs DROP CONSTRAINT internal_user_notification_prefs_event_type_check;
+ALTER TABLE internal_user_notification_prefs ADD CONSTRAINT internal_user_notification_prefs_event_type_check CHECK (
+    event_type IN ('proof_ready', 'proof_approved', 'proof_declined', 'internal_review_required', 'custom_inlay_submitted', 'order_placed', 'inlay_step_changed', 'inlay_update', 'project_shipped', 'project_delivered', 'invoice_sent', 'invoice_voided', 'payment_received', 'chat_message', 'low_stock', 'remake_requested', 'remake_reviewed', 'rush_order_placed')
+);
@@ -0,0 +1,18 @@
+--------------------------------------------------------------------------------
+-- SHIP-TO SNAPSHOTS
+--------------------------------------------------------------------------------
+
+ALTER TABLE shipments DROP COLUMN IF EXISTS ship_to;
+ALTER TABLE order_snapshots DROP COLUMN IF EXISTS ship_to;
+
+--------------------------------------------------------------------------------
+-- PROJECT SHIP-TO
+--------------------------------------------------------------------------------
+
+ALTER TABLE projects DROP COLUMN IF EXISTS ship_to_address_id;
+
+--------------------------------------------------------------------------------
+-- SHIPPING ADDRESSES
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS shipping_addresses;
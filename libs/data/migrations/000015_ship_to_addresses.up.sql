@@ -0,0 +1,84 @@
+--------------------------------------------------------------------------------
+-- SHIPPING ADDRESSES
+--
+-- Saved places a dealership has orders sent to, besides its own address: a
+-- job site, a warehouse, the customer's home. recipient is who signs for the
+-- package; the dealership name stands in when it is blank.
+--------------------------------------------------------------------------------
+
+CREATE TABLE shipping_addresses (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    dealership_id INTEGER NOT NULL REFERENCES dealerships(id) ON DELETE CASCADE,
+    label TEXT NOT NULL,
+    recipient TEXT NOT NULL DEFAULT '',
+    street TEXT NOT NULL,
+    street_ext TEXT NOT NULL DEFAULT '',
+    city TEXT NOT NULL,
+    state TEXT NOT NULL DEFAULT '',
+    postal_code TEXT NOT NULL DEFAULT '',
+    country TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX idx_shipping_addresses_dealership_id ON shipping_addresses(dealership_id);
+
+CREATE TRIGGER update_shipping_addresses_updated_at
+    BEFORE UPDATE ON shipping_addresses
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_shipping_addresses_version
+    BEFORE UPDATE ON shipping_addresses
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+--------------------------------------------------------------------------------
+-- PROJECT SHIP-TO
+--
+-- The saved address a draft project will ship to, null for the dealership's
+-- own address. Deleting the address puts the project back on the dealership.
+--------------------------------------------------------------------------------
+
+ALTER TABLE projects
+    ADD COLUMN ship_to_address_id INTEGER REFERENCES shipping_addresses(id) ON DELETE SET NULL;
+
+--------------------------------------------------------------------------------
+-- SHIP-TO SNAPSHOTS
+--
+-- The address is copied into the order when it is placed and onto each package
+-- when it goes out, so editing or deleting a saved address never changes where
+-- an order went. Orders and packages from before saved addresses went to the
+-- dealership.
+--------------------------------------------------------------------------------
+
+ALTER TABLE order_snapshots ADD COLUMN ship_to JSONB;
+ALTER TABLE shipments ADD COLUMN ship_to JSONB;
+
+UPDATE order_snapshots os
+SET ship_to = jsonb_build_object(
+    'recipient', d.name,
+    'street', d.street,
+    'street_ext', d.street_ext,
+    'city', d.city,
+    'state', d.state,
+    'postal_code', d.postal_code,
+    'country', d.country
+)
+FROM projects p
+JOIN dealerships d ON d.id = p.dealership_id
+WHERE p.id = os.project_id;
+
+UPDATE shipments s
+SET ship_to = jsonb_build_object(
+    'recipient', d.name,
+    'street', d.street,
+    'street_ext', d.street_ext,
+    'city', d.city,
+    'state', d.state,
+    'postal_code', d.postal_code,
+    'country', d.country
+)
+FROM projects p
+JOIN dealerships d ON d.id = p.dealership_id
+WHERE p.id = s.project_id;
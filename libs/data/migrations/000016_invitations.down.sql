@@ -0,0 +1,5 @@
+--------------------------------------------------------------------------------
+-- INVITATIONS
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS invitations;
@@ -0,0 +1,42 @@
+--------------------------------------------------------------------------------
+-- INVITATIONS
+--
+-- A new user is created inactive and emailed an invitation. Accepting it
+-- activates the user and links the identity they accepted with, so a later
+-- OAuth sign-in is matched on that identity rather than on the email alone.
+-- The link is single-use: the token is stored hashed, resending replaces it,
+-- and accepting or revoking ends the invitation. A user has at most one
+-- pending invitation.
+--------------------------------------------------------------------------------
+
+CREATE TABLE invitations (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    dealership_user_id INTEGER REFERENCES dealership_users(id) ON DELETE CASCADE,
+    internal_user_id INTEGER REFERENCES internal_users(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    role TEXT NOT NULL,
+    invited_by_dealership_user_id INTEGER REFERENCES dealership_users(id) ON DELETE SET NULL,
+    invited_by_internal_user_id INTEGER REFERENCES internal_users(id) ON DELETE SET NULL,
+    token_hash BYTEA UNIQUE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'revoked')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    accepted_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1,
+    CHECK ((dealership_user_id IS NULL) <> (internal_user_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_invitations_pending_dealership_user ON invitations(dealership_user_id) WHERE status = 'pending';
+CREATE UNIQUE INDEX idx_invitations_pending_internal_user ON invitations(internal_user_id) WHERE status = 'pending';
+
+CREATE TRIGGER update_invitations_updated_at
+    BEFORE UPDATE ON invitations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_invitations_version
+    BEFORE UPDATE ON invitations
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
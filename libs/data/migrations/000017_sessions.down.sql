@@ -0,0 +1,21 @@
+--------------------------------------------------------------------------------
+-- SESSIONS
+--------------------------------------------------------------------------------
+
+DROP INDEX IF EXISTS idx_internal_tokens_session;
+
+ALTER TABLE internal_tokens
+    DROP COLUMN IF EXISTS rotated_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS ip,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS session_id;
+
+DROP INDEX IF EXISTS idx_dealership_tokens_session;
+
+ALTER TABLE dealership_tokens
+    DROP COLUMN IF EXISTS rotated_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS ip,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS session_id;
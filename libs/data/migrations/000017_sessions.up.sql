@@ -0,0 +1,33 @@
+--------------------------------------------------------------------------------
+-- SESSIONS
+--
+-- A session is one sign-in on one device. Each refresh is exchanged for a new
+-- one on use; the refresh tokens a sign-in goes through, and the access tokens
+-- they mint, share a session_id. A refresh token is kept after it is rotated so
+-- that presenting it again can be spotted as theft, which revokes the whole
+-- session. Login tokens have no session.
+--
+-- Tokens issued before sessions existed each become a session of their own.
+--------------------------------------------------------------------------------
+
+ALTER TABLE dealership_tokens
+    ADD COLUMN session_id UUID,
+    ADD COLUMN user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN ip TEXT NOT NULL DEFAULT '',
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN rotated_at TIMESTAMPTZ;
+
+UPDATE dealership_tokens SET session_id = gen_random_uuid() WHERE scope = 'refresh';
+
+CREATE INDEX idx_dealership_tokens_session ON dealership_tokens(session_id);
+
+ALTER TABLE internal_tokens
+    ADD COLUMN session_id UUID,
+    ADD COLUMN user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN ip TEXT NOT NULL DEFAULT '',
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN rotated_at TIMESTAMPTZ;
+
+UPDATE internal_tokens SET session_id = gen_random_uuid() WHERE scope = 'refresh';
+
+CREATE INDEX idx_internal_tokens_session ON internal_tokens(session_id);
@@ -0,0 +1,17 @@
+--------------------------------------------------------------------------------
+-- TWO-FACTOR POLICY
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS two_factor_policy;
+
+--------------------------------------------------------------------------------
+-- RECOVERY CODES
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS internal_user_recovery_codes;
+
+--------------------------------------------------------------------------------
+-- TWO-FACTOR AUTHENTICATION
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS internal_user_two_factor;
@@ -0,0 +1,72 @@
+--------------------------------------------------------------------------------
+-- TWO-FACTOR AUTHENTICATION
+--
+-- Internal users can add a TOTP (RFC 6238) authenticator as a second step
+-- after the magic link or Google/Microsoft sign-in. The secret is stored
+-- sealed with a key derived from the API's auth secret. It only counts once
+-- confirmed with a first code. last_used_step stops a code being replayed;
+-- failed_attempts ends the sign-in after too many wrong codes.
+--------------------------------------------------------------------------------
+
+CREATE TABLE internal_user_two_factor (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    internal_user_id INTEGER UNIQUE NOT NULL REFERENCES internal_users(id) ON DELETE CASCADE,
+    secret BYTEA NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TRIGGER update_internal_user_two_factor_updated_at
+    BEFORE UPDATE ON internal_user_two_factor
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_internal_user_two_factor_version
+    BEFORE UPDATE ON internal_user_two_factor
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+--------------------------------------------------------------------------------
+-- RECOVERY CODES
+--
+-- Single-use codes for when the authenticator is lost, stored hashed. A new
+-- set replaces the old one.
+--------------------------------------------------------------------------------
+
+CREATE TABLE internal_user_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    internal_user_id INTEGER NOT NULL REFERENCES internal_users(id) ON DELETE CASCADE,
+    code_hash BYTEA UNIQUE NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_internal_user_recovery_codes_user ON internal_user_recovery_codes(internal_user_id);
+
+--------------------------------------------------------------------------------
+-- TWO-FACTOR POLICY
+--
+-- A single row. When require_for_privileged_roles is on, internal users whose
+-- role can manage internal users or create invoices must enrol before they
+-- can sign in.
+--------------------------------------------------------------------------------
+
+CREATE TABLE two_factor_policy (
+    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    require_for_privileged_roles BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+INSERT INTO two_factor_policy (id) VALUES (1);
+
+CREATE TRIGGER update_two_factor_policy_updated_at
+    BEFORE UPDATE ON two_factor_policy
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_two_factor_policy_version
+    BEFORE UPDATE ON two_factor_policy
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
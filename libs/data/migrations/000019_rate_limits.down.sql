@@ -0,0 +1,5 @@
+--------------------------------------------------------------------------------
+-- RATE LIMIT BUCKETS
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS rate_limit_buckets;
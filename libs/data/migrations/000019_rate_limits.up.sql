@@ -0,0 +1,18 @@
+--------------------------------------------------------------------------------
+-- RATE LIMIT BUCKETS
+--
+-- Token buckets for the API's rate limits, kept in Postgres so every instance
+-- shares them. key_hash is a hash of the limit's name and what it limits by
+-- (an IP, an email address), so addresses are not stored. full_at is when the
+-- bucket will have refilled completely; past it the row means the same as no
+-- row and is pruned.
+--------------------------------------------------------------------------------
+
+CREATE TABLE rate_limit_buckets (
+    key_hash BYTEA PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    full_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_buckets_full_at ON rate_limit_buckets(full_at);
@@ -0,0 +1,11 @@
+--------------------------------------------------------------------------------
+-- OIDC PROVIDER DEALERSHIPS
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS oidc_provider_dealerships;
+
+--------------------------------------------------------------------------------
+-- OIDC PROVIDERS
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS oidc_providers;
@@ -0,0 +1,51 @@
+--------------------------------------------------------------------------------
+-- OIDC PROVIDERS
+--
+-- Identity providers beyond the built-in Google and Microsoft, such as a
+-- dealership group's Okta. key names the provider in its login route and is
+-- stored as dealership_accounts.provider for the identities it signs in.
+-- client_secret is sealed with a key derived from the API's auth secret. The
+-- claim columns say which userinfo claims hold the subject, email and whether
+-- the email is verified.
+--------------------------------------------------------------------------------
+
+CREATE TABLE oidc_providers (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    key TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    discovery_url TEXT NOT NULL,
+    client_id TEXT NOT NULL,
+    client_secret BYTEA NOT NULL,
+    scopes TEXT NOT NULL DEFAULT 'openid email profile',
+    subject_claim TEXT NOT NULL DEFAULT 'sub',
+    email_claim TEXT NOT NULL DEFAULT 'email',
+    email_verified_claim TEXT NOT NULL DEFAULT 'email_verified',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TRIGGER update_oidc_providers_updated_at
+    BEFORE UPDATE ON oidc_providers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_oidc_providers_version
+    BEFORE UPDATE ON oidc_providers
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+--------------------------------------------------------------------------------
+-- OIDC PROVIDER DEALERSHIPS
+--
+-- The dealerships whose users may sign in with a provider. A provider signs
+-- in nobody else, internal staff included.
+--------------------------------------------------------------------------------
+
+CREATE TABLE oidc_provider_dealerships (
+    oidc_provider_id INTEGER NOT NULL REFERENCES oidc_providers(id) ON DELETE CASCADE,
+    dealership_id INTEGER NOT NULL REFERENCES dealerships(id) ON DELETE CASCADE,
+    PRIMARY KEY (oidc_provider_id, dealership_id)
+);
+
+CREATE INDEX idx_oidc_provider_dealerships_dealership ON oidc_provider_dealerships(dealership_id);
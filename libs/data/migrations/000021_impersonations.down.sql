@@ -0,0 +1,11 @@
+--------------------------------------------------------------------------------
+-- IMPERSONATION REQUESTS
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS impersonation_requests;
+
+--------------------------------------------------------------------------------
+-- IMPERSONATIONS
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS impersonations;
@@ -0,0 +1,46 @@
+--------------------------------------------------------------------------------
+-- IMPERSONATIONS
+--
+-- An internal admin viewing the app as a dealership user, to see what they
+-- see. Each row is one session: who started it, who they viewed as, why, and
+-- the hash of its access token, which is read-only and cannot be refreshed.
+-- The rows are the audit trail, so they outlive the users; the emails keep
+-- them readable once a user is deleted.
+--------------------------------------------------------------------------------
+
+CREATE TABLE impersonations (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    internal_user_id INTEGER REFERENCES internal_users(id) ON DELETE SET NULL,
+    internal_user_email TEXT NOT NULL,
+    dealership_user_id INTEGER REFERENCES dealership_users(id) ON DELETE SET NULL,
+    dealership_user_email TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    token_hash BYTEA UNIQUE NOT NULL,
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_impersonations_internal_user ON impersonations(internal_user_id);
+CREATE INDEX idx_impersonations_dealership_user ON impersonations(dealership_user_id);
+
+--------------------------------------------------------------------------------
+-- IMPERSONATION REQUESTS
+--
+-- Every request made during an impersonation, including the writes that were
+-- refused.
+--------------------------------------------------------------------------------
+
+CREATE TABLE impersonation_requests (
+    id SERIAL PRIMARY KEY,
+    impersonation_id INTEGER NOT NULL REFERENCES impersonations(id) ON DELETE CASCADE,
+    method TEXT NOT NULL,
+    path TEXT NOT NULL,
+    blocked BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_impersonation_requests_impersonation ON impersonation_requests(impersonation_id);
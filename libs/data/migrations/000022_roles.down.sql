@@ -0,0 +1,23 @@
+--------------------------------------------------------------------------------
+-- USER PERMISSION OVERRIDES
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS internal_user_permissions;
+DROP TABLE IF EXISTS dealership_user_permissions;
+
+--------------------------------------------------------------------------------
+-- ROLES
+--------------------------------------------------------------------------------
+
+ALTER TABLE internal_users DROP CONSTRAINT IF EXISTS internal_users_role_fkey;
+ALTER TABLE internal_users
+    ADD CONSTRAINT internal_users_role_check CHECK (role IN ('designer', 'production', 'billing', 'admin'));
+
+ALTER TABLE dealership_users DROP CONSTRAINT IF EXISTS dealership_users_role_fkey;
+ALTER TABLE dealership_users
+    ADD CONSTRAINT dealership_users_role_check CHECK (role IN ('viewer', 'submitter', 'approver', 'admin'));
+
+DROP TABLE IF EXISTS internal_role_permissions;
+DROP TABLE IF EXISTS internal_roles;
+DROP TABLE IF EXISTS dealership_role_permissions;
+DROP TABLE IF EXISTS dealership_roles;
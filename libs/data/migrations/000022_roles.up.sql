@@ -0,0 +1,180 @@
+--------------------------------------------------------------------------------
+-- ROLES
+--
+-- Roles are data rather than CHECK constraints, so a new role is a row rather
+-- than a migration. Each role grants a set of the API's permission actions.
+-- The original roles are seeded below as built-in: their permissions can be
+-- edited but they cannot be deleted. A user's role column now references its
+-- role's key, which cannot change once created.
+--------------------------------------------------------------------------------
+
+CREATE TABLE dealership_roles (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    key VARCHAR(255) UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    is_builtin BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TRIGGER update_dealership_roles_updated_at
+    BEFORE UPDATE ON dealership_roles
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_dealership_roles_version
+    BEFORE UPDATE ON dealership_roles
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+CREATE TABLE dealership_role_permissions (
+    dealership_role_id INTEGER NOT NULL REFERENCES dealership_roles(id) ON DELETE CASCADE,
+    action VARCHAR(255) NOT NULL,
+    PRIMARY KEY (dealership_role_id, action)
+);
+
+CREATE TABLE internal_roles (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    key VARCHAR(255) UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    is_builtin BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TRIGGER update_internal_roles_updated_at
+    BEFORE UPDATE ON internal_roles
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_internal_roles_version
+    BEFORE UPDATE ON internal_roles
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+CREATE TABLE internal_role_permissions (
+    internal_role_id INTEGER NOT NULL REFERENCES internal_roles(id) ON DELETE CASCADE,
+    action VARCHAR(255) NOT NULL,
+    PRIMARY KEY (internal_role_id, action)
+);
+
+INSERT INTO dealership_roles (key, name, is_builtin) VALUES
+    ('viewer', 'Viewer', true),
+    ('submitter', 'Submitter', true),
+    ('approver', 'Approver', true),
+    ('admin', 'Admin', true);
+
+INSERT INTO dealership_role_permissions (dealership_role_id, action)
+SELECT r.id, p.action
+FROM dealership_roles r
+JOIN (VALUES
+    ('viewer', 'view_projects'),
+    ('viewer', 'view_invoices'),
+    ('submitter', 'view_projects'),
+    ('submitter', 'view_invoices'),
+    ('submitter', 'create_project'),
+    ('submitter', 'manage_project'),
+    ('submitter', 'send_chat'),
+    ('approver', 'view_projects'),
+    ('approver', 'view_invoices'),
+    ('approver', 'create_project'),
+    ('approver', 'manage_project'),
+    ('approver', 'send_chat'),
+    ('approver', 'approve_proof'),
+    ('approver', 'place_order'),
+    ('admin', 'view_projects'),
+    ('admin', 'view_invoices'),
+    ('admin', 'create_project'),
+    ('admin', 'manage_project'),
+    ('admin', 'send_chat'),
+    ('admin', 'approve_proof'),
+    ('admin', 'place_order'),
+    ('admin', 'pay_invoice'),
+    ('admin', 'manage_dealership_users'),
+    ('admin', 'manage_dealership')
+) AS p(role, action) ON p.role = r.key;
+
+INSERT INTO internal_roles (key, name, is_builtin) VALUES
+    ('designer', 'Designer', true),
+    ('production', 'Production', true),
+    ('billing', 'Billing', true),
+    ('admin', 'Admin', true);
+
+INSERT INTO internal_role_permissions (internal_role_id, action)
+SELECT r.id, p.action
+FROM internal_roles r
+JOIN (VALUES
+    ('designer', 'access_admin'),
+    ('designer', 'manage_project'),
+    ('designer', 'send_chat'),
+    ('designer', 'create_proof'),
+    ('designer', 'internal_approve_proof'),
+    ('designer', 'manage_catalog'),
+    ('designer', 'manage_materials'),
+    ('production', 'access_admin'),
+    ('production', 'manage_project'),
+    ('production', 'send_chat'),
+    ('production', 'manage_kanban'),
+    ('production', 'manage_shipping'),
+    ('production', 'create_inlay_update'),
+    ('production', 'manage_remakes'),
+    ('billing', 'access_admin'),
+    ('billing', 'manage_project'),
+    ('billing', 'send_chat'),
+    ('billing', 'create_invoice'),
+    ('billing', 'manage_price_groups'),
+    ('billing', 'manage_taxes'),
+    ('admin', 'access_admin'),
+    ('admin', 'manage_project'),
+    ('admin', 'send_chat'),
+    ('admin', 'create_proof'),
+    ('admin', 'internal_approve_proof'),
+    ('admin', 'manage_catalog'),
+    ('admin', 'manage_materials'),
+    ('admin', 'manage_kanban'),
+    ('admin', 'manage_shipping'),
+    ('admin', 'create_inlay_update'),
+    ('admin', 'manage_remakes'),
+    ('admin', 'create_invoice'),
+    ('admin', 'manage_price_groups'),
+    ('admin', 'manage_taxes'),
+    ('admin', 'manage_internal_users'),
+    ('admin', 'manage_dealership_users'),
+    ('admin', 'manage_dealerships'),
+    ('admin', 'manage_dealership'),
+    ('admin', 'manage_support'),
+    ('admin', 'view_all'),
+    ('admin', 'impersonate'),
+    ('admin', 'manage_roles')
+) AS p(role, action) ON p.role = r.key;
+
+ALTER TABLE dealership_users DROP CONSTRAINT dealership_users_role_check;
+ALTER TABLE dealership_users
+    ADD CONSTRAINT dealership_users_role_fkey FOREIGN KEY (role) REFERENCES dealership_roles(key);
+
+ALTER TABLE internal_users DROP CONSTRAINT internal_users_role_check;
+ALTER TABLE internal_users
+    ADD CONSTRAINT internal_users_role_fkey FOREIGN KEY (role) REFERENCES internal_roles(key);
+
+--------------------------------------------------------------------------------
+-- USER PERMISSION OVERRIDES
+--
+-- Per-user exceptions on top of the role: granted adds an action the role
+-- lacks, otherwise the action is denied even if the role has it.
+--------------------------------------------------------------------------------
+
+CREATE TABLE dealership_user_permissions (
+    dealership_user_id INTEGER NOT NULL REFERENCES dealership_users(id) ON DELETE CASCADE,
+    action VARCHAR(255) NOT NULL,
+    granted BOOLEAN NOT NULL,
+    PRIMARY KEY (dealership_user_id, action)
+);
+
+CREATE TABLE internal_user_permissions (
+    internal_user_id INTEGER NOT NULL REFERENCES internal_users(id) ON DELETE CASCADE,
+    action VARCHAR(255) NOT NULL,
+    granted BOOLEAN NOT NULL,
+    PRIMARY KEY (internal_user_id, action)
+);
@@ -0,0 +1,13 @@
+--------------------------------------------------------------------------------
+-- GROUP-LEVEL DEALERSHIP USERS
+--------------------------------------------------------------------------------
+
+ALTER TABLE dealership_users DROP COLUMN IF EXISTS dealership_group_id;
+
+--------------------------------------------------------------------------------
+-- DEALERSHIP GROUPS
+--------------------------------------------------------------------------------
+
+ALTER TABLE dealerships DROP COLUMN IF EXISTS dealership_group_id;
+
+DROP TABLE IF EXISTS dealership_groups;
@@ -0,0 +1,42 @@
+--------------------------------------------------------------------------------
+-- DEALERSHIP GROUPS
+--
+-- A parent organization, such as a franchise, with several dealership
+-- locations. A dealership belongs to at most one group.
+--------------------------------------------------------------------------------
+
+CREATE TABLE dealership_groups (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TRIGGER update_dealership_groups_updated_at
+    BEFORE UPDATE ON dealership_groups
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_dealership_groups_version
+    BEFORE UPDATE ON dealership_groups
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+
+ALTER TABLE dealerships
+    ADD COLUMN dealership_group_id INTEGER REFERENCES dealership_groups(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_dealerships_dealership_group_id ON dealerships(dealership_group_id);
+
+--------------------------------------------------------------------------------
+-- GROUP-LEVEL DEALERSHIP USERS
+--
+-- A dealership user with dealership_group_id set, such as someone at a
+-- franchise's head office, can see every location in that group. They still
+-- belong to one location, which must be in the group; projects they create go
+-- there.
+--------------------------------------------------------------------------------
+
+ALTER TABLE dealership_users
+    ADD COLUMN dealership_group_id INTEGER REFERENCES dealership_groups(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_dealership_users_dealership_group_id ON dealership_users(dealership_group_id);
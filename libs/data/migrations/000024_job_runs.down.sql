@@ -0,0 +1,12 @@
+--------------------------------------------------------------------------------
+-- MANAGE JOBS PERMISSION
+--------------------------------------------------------------------------------
+
+DELETE FROM internal_role_permissions WHERE action = 'manage_jobs';
+DELETE FROM internal_user_permissions WHERE action = 'manage_jobs';
+
+--------------------------------------------------------------------------------
+-- JOB RUNS
+--------------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS job_runs;
@@ -0,0 +1,36 @@
+--------------------------------------------------------------------------------
+-- JOB RUNS
+--
+-- Each run of a background maintenance job, scheduled or started by hand from
+-- the admin. Scheduled runs record the tick they were for, and the unique
+-- index on it means an instance that takes over from another never repeats a
+-- tick that already ran. Rows left 'running' by an instance that died are
+-- marked failed by the next instance to take the job.
+--------------------------------------------------------------------------------
+
+CREATE TABLE job_runs (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    job_name TEXT NOT NULL,
+    trigger TEXT NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+    scheduled_for TIMESTAMPTZ,
+    triggered_by_internal_user_id INTEGER REFERENCES internal_users(id) ON DELETE SET NULL,
+    status TEXT NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed')),
+    rows_affected BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_job_runs_job_name ON job_runs(job_name, started_at DESC);
+CREATE UNIQUE INDEX idx_job_runs_scheduled_for ON job_runs(job_name, scheduled_for)
+    WHERE scheduled_for IS NOT NULL;
+
+--------------------------------------------------------------------------------
+-- MANAGE JOBS PERMISSION
+--
+-- Listing jobs and running one by hand is for internal admins.
+--------------------------------------------------------------------------------
+
+INSERT INTO internal_role_permissions (internal_role_id, action)
+SELECT id, 'manage_jobs' FROM internal_roles WHERE key = 'admin';
@@ -17,5 +17,4 @@ type AuthUser interface {
 	IsInternal() bool
 	IsDealership() bool
 	GetDealershipID() *int
-	Can(action string) bool
 }
@@ -0,0 +1,69 @@
+package data
+
+import (
+	"context"
+	"time"
+)
+
+// BulkImport is a batch of new dealerships and dealership users, such as a new
+// region being onboarded. Apply creates all of it or none of it.
+type BulkImport struct {
+	Dealerships []*Dealership
+	Users       []*BulkImportUser
+	// Inviter, when set, is who every imported user is invited by. Leaving it
+	// nil creates the users without invitations.
+	Inviter AuthUser
+}
+
+// BulkImportUser is a user in a BulkImport. Either User.DealershipID is an
+// existing dealership, or NewDealership is the index in Dealerships of the one
+// being imported alongside.
+type BulkImportUser struct {
+	User          *DealershipUser
+	NewDealership *int
+	// Invitation is set by Apply when the import invites its users. Its
+	// Plaintext is what to email.
+	Invitation *Invitation
+}
+
+// ApplyImport creates the batch's dealerships, then its users and their
+// invitations, in one transaction. The whole batch shares one deadline rather
+// than each row getting its own.
+func (m Models) ApplyImport(batch *BulkImport) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, dealership := range batch.Dealerships {
+		err = m.Dealerships.insertDealership(ctx, tx, dealership)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, imported := range batch.Users {
+		if imported.NewDealership != nil {
+			imported.User.DealershipID = batch.Dealerships[*imported.NewDealership].ID
+		}
+
+		err = m.DealershipUsers.insertUser(ctx, tx, imported.User)
+		if err != nil {
+			return err
+		}
+
+		if batch.Inviter != nil {
+			imported.Invitation = NewInvitation(imported.User, batch.Inviter)
+			err = m.Invitations.insertInvitation(ctx, tx, imported.Invitation)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
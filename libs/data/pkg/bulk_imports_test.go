@@ -0,0 +1,85 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyImport_CreatesDealershipsUsersAndInvitations(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	existing := createTestDealership(t, models)
+	inviter := createTestDealershipUser(t, models, existing.ID)
+
+	newDealership := 0
+	batch := &BulkImport{
+		Dealerships: []*Dealership{{
+			Name:                "Imported Dealership",
+			PaymentTiming:       PaymentTimings.PostShipping,
+			SandblastFileFormat: SandblastFileFormats.PDF,
+			Address: Address{
+				Street: "1 Import Way", City: "Import City", State: "IC",
+				PostalCode: "54321", Country: "US", Latitude: 44.9, Longitude: -93.2,
+			},
+		}},
+		Users: []*BulkImportUser{
+			{
+				User:          &DealershipUser{Name: "New Location", Email: "new@example.com", Role: DealershipUserRoles.Admin},
+				NewDealership: &newDealership,
+			},
+			{
+				User: &DealershipUser{Name: "Existing Location", Email: "existing@example.com", Role: DealershipUserRoles.Viewer, DealershipID: existing.ID},
+			},
+		},
+		Inviter: inviter,
+	}
+
+	require.NoError(t, models.ApplyImport(batch))
+
+	imported := batch.Dealerships[0]
+	assert.NotZero(t, imported.ID)
+	assert.Equal(t, imported.ID, batch.Users[0].User.DealershipID)
+	assert.Equal(t, existing.ID, batch.Users[1].User.DealershipID)
+
+	for _, user := range batch.Users {
+		require.NotNil(t, user.Invitation)
+		assert.NotEmpty(t, user.Invitation.Plaintext)
+		assert.Equal(t, inviter.ID, *user.Invitation.InvitedByDealershipUserID)
+	}
+
+	emails, err := models.DealershipUsers.GetExistingEmails([]string{"NEW@example.com", "missing@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new@example.com"}, emails)
+}
+
+func TestApplyImport_RollsBackOnFailure(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	existing := createTestDealership(t, models)
+	taken := createTestDealershipUser(t, models, existing.ID)
+
+	batch := &BulkImport{
+		Dealerships: []*Dealership{{
+			Name:                "Never Created",
+			PaymentTiming:       PaymentTimings.PostShipping,
+			SandblastFileFormat: SandblastFileFormats.PDF,
+			Address: Address{
+				Street: "1 Import Way", City: "Import City", State: "IC",
+				PostalCode: "54321", Country: "US", Latitude: 44.9, Longitude: -93.2,
+			},
+		}},
+		Users: []*BulkImportUser{{
+			User: &DealershipUser{Name: "Duplicate", Email: taken.Email, Role: DealershipUserRoles.Viewer, DealershipID: existing.ID},
+		}},
+	}
+
+	require.Error(t, models.ApplyImport(batch))
+
+	dealerships, err := models.Dealerships.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, dealerships, 1, "the dealership is rolled back with the user that failed")
+}
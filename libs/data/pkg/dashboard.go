@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
@@ -39,6 +40,20 @@ type InternalDashboard struct {
 	RecentProjects                []*Project               `json:"recent_projects"`
 }
 
+// LocationDashboard is one location's part of a GroupDashboard.
+type LocationDashboard struct {
+	DealershipID   int                  `json:"dealership_id"`
+	DealershipName string               `json:"dealership_name"`
+	Dashboard      *DealershipDashboard `json:"dashboard"`
+}
+
+// GroupDashboard is the dealership dashboard summed over every location in a
+// group, with each location's own dashboard alongside.
+type GroupDashboard struct {
+	DealershipDashboard
+	Locations []LocationDashboard `json:"locations"`
+}
+
 type DashboardModel struct {
 	DB   *pgxpool.Pool
 	STDB *sql.DB
@@ -78,6 +93,56 @@ func (m DashboardModel) GetDealershipDashboard(dealershipID int) (*DealershipDas
 	return dashboard, nil
 }
 
+func (m DashboardModel) GetGroupDashboard(groupID int) (*GroupDashboard, error) {
+	dashboard := &GroupDashboard{
+		DealershipDashboard: DealershipDashboard{
+			ProjectStatusCounts: []StatusCount{},
+			RecentProjects:      []*Project{},
+		},
+		Locations: []LocationDashboard{},
+	}
+
+	locations, err := m.groupLocations(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("group locations: %w", err)
+	}
+
+	statusCounts := map[string]int64{}
+	for _, location := range locations {
+		location.Dashboard, err = m.GetDealershipDashboard(location.DealershipID)
+		if err != nil {
+			return nil, fmt.Errorf("dealership %d: %w", location.DealershipID, err)
+		}
+		dashboard.Locations = append(dashboard.Locations, location)
+
+		for _, sc := range location.Dashboard.ProjectStatusCounts {
+			statusCounts[sc.Status] += sc.Count
+		}
+		dashboard.PendingApprovalCount += location.Dashboard.PendingApprovalCount
+		dashboard.OutstandingInvoiceCount += location.Dashboard.OutstandingInvoiceCount
+		dashboard.OutstandingInvoiceAmountCents += location.Dashboard.OutstandingInvoiceAmountCents
+		dashboard.RecentProjects = append(dashboard.RecentProjects, location.Dashboard.RecentProjects...)
+	}
+
+	for status, count := range statusCounts {
+		dashboard.ProjectStatusCounts = append(dashboard.ProjectStatusCounts, StatusCount{Status: status, Count: count})
+	}
+	sort.Slice(dashboard.ProjectStatusCounts, func(i, j int) bool {
+		return dashboard.ProjectStatusCounts[i].Status < dashboard.ProjectStatusCounts[j].Status
+	})
+
+	// Each location brings its five most recent; the group shows the five
+	// most recent of those.
+	sort.SliceStable(dashboard.RecentProjects, func(i, j int) bool {
+		return dashboard.RecentProjects[i].UpdatedAt.After(dashboard.RecentProjects[j].UpdatedAt)
+	})
+	if len(dashboard.RecentProjects) > 5 {
+		dashboard.RecentProjects = dashboard.RecentProjects[:5]
+	}
+
+	return dashboard, nil
+}
+
 func (m DashboardModel) GetInternalDashboard() (*InternalDashboard, error) {
 	dashboard := &InternalDashboard{
 		ProjectStatusCounts:     []StatusCount{},
@@ -119,6 +184,34 @@ func (m DashboardModel) GetInternalDashboard() (*InternalDashboard, error) {
 	return dashboard, nil
 }
 
+func (m DashboardModel) groupLocations(groupID int) ([]LocationDashboard, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.STDB.QueryContext(ctx, `
+		SELECT id, name FROM dealerships
+		WHERE dealership_group_id = $1
+		ORDER BY name, id
+	`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []LocationDashboard{}
+	for rows.Next() {
+		var location LocationDashboard
+		if err := rows.Scan(&location.DealershipID, &location.DealershipName); err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
 func (m DashboardModel) projectStatusCountsByDealership(dealershipID int) ([]StatusCount, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -242,15 +335,9 @@ func (m DashboardModel) outstandingInvoicesByDealership(dealershipID int) (int64
 
 	var amount sql.NullInt64
 	err = m.STDB.QueryRowContext(ctx, `
-		SELECT COALESCE(SUM(per_project.total), 0) FROM (
-			SELECT COALESCE(SUM(order_snapshots.price_cents), 0)
-			     + COALESCE(MAX(projects.installation_kit_price_cents), 0) AS total
-			FROM invoices
-			JOIN projects ON projects.id = invoices.project_id
-			LEFT JOIN order_snapshots ON order_snapshots.project_id = projects.id
-			WHERE invoices.status = $1 AND projects.dealership_id = $2
-			GROUP BY projects.id
-		) per_project
+		SELECT COALESCE(SUM(invoices.total_cents), 0) FROM invoices
+		JOIN projects ON projects.id = invoices.project_id
+		WHERE invoices.status = $1 AND projects.dealership_id = $2
 	`, string(InvoiceStatuses.Sent), dealershipID).Scan(&amount)
 	if err != nil {
 		return 0, 0, err
@@ -273,15 +360,7 @@ func (m DashboardModel) outstandingInvoicesGlobal() (int64, int64, error) {
 
 	var amount sql.NullInt64
 	err = m.STDB.QueryRowContext(ctx, `
-		SELECT COALESCE(SUM(per_project.total), 0) FROM (
-			SELECT COALESCE(SUM(order_snapshots.price_cents), 0)
-			     + COALESCE(MAX(projects.installation_kit_price_cents), 0) AS total
-			FROM invoices
-			JOIN projects ON projects.id = invoices.project_id
-			LEFT JOIN order_snapshots ON order_snapshots.project_id = projects.id
-			WHERE invoices.status = $1
-			GROUP BY projects.id
-		) per_project
+		SELECT COALESCE(SUM(total_cents), 0) FROM invoices WHERE status = $1
 	`, string(InvoiceStatuses.Sent)).Scan(&amount)
 	if err != nil {
 		return 0, 0, err
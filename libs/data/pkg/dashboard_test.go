@@ -99,8 +99,20 @@ func TestGetDealershipDashboard_WithSentInvoice_ReturnsOutstandingAmount(t *test
 		ProjectID: project.ID,
 		Status:    InvoiceStatuses.Sent,
 	}
+	require.NoError(t, models.Invoices.SetProjectTotals(invoice))
 	require.NoError(t, models.Invoices.Insert(invoice))
 
+	// The balance is the invoice's stored total, not recomputed from the
+	// snapshots, so a later snapshot does not move it.
+	require.NoError(t, models.OrderSnapshots.Insert(&OrderSnapshot{
+		ProjectID:    project.ID,
+		InlayID:      createTestInlay(t, models, project.ID).ID,
+		PriceGroupID: priceGroup.ID,
+		PriceCents:   5000,
+		Width:        10.0,
+		Height:       10.0,
+	}))
+
 	dashboard, err := models.Dashboard.GetDealershipDashboard(dealership.ID)
 	require.NoError(t, err)
 
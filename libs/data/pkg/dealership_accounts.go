@@ -71,7 +71,7 @@ func dealershipAccountToGen(a *DealershipAccount) (*model.DealershipAccounts, er
 	return &genAcc, nil
 }
 
-func (m DealershipAccountModel) Insert(account *DealershipAccount) error {
+func (m DealershipAccountModel) insertAccount(ctx context.Context, executor qrm.Queryable, account *DealershipAccount) error {
 	genAcc, err := dealershipAccountToGen(account)
 	if err != nil {
 		return err
@@ -92,11 +92,8 @@ func (m DealershipAccountModel) Insert(account *DealershipAccount) error {
 		table.DealershipAccounts.Version,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
 	var dest model.DealershipAccounts
-	err = query.QueryContext(ctx, m.STDB, &dest)
+	err = query.QueryContext(ctx, executor, &dest)
 	if err != nil {
 		return err
 	}
@@ -110,6 +107,20 @@ func (m DealershipAccountModel) Insert(account *DealershipAccount) error {
 	return nil
 }
 
+func (m DealershipAccountModel) Insert(account *DealershipAccount) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.insertAccount(ctx, m.STDB, account)
+}
+
+func (m DealershipAccountModel) TxInsert(tx *sql.Tx, account *DealershipAccount) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.insertAccount(ctx, tx, account)
+}
+
 func (m DealershipAccountModel) GetByID(id int) (*DealershipAccount, bool, error) {
 	query := postgres.SELECT(
 		table.DealershipAccounts.AllColumns,
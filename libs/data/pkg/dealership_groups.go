@@ -0,0 +1,307 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DealershipGroup is a parent organization, such as a franchise, with several
+// dealership locations. Its group-level users see every location.
+type DealershipGroup struct {
+	StandardTable
+	Name          string `json:"name"`
+	DealershipIDs []int  `json:"dealership_ids"`
+}
+
+// GroupInvoice is one location's invoice on a group's consolidated statement.
+type GroupInvoice struct {
+	Invoice
+	DealershipID   int    `json:"dealership_id"`
+	DealershipName string `json:"dealership_name"`
+	ProjectUUID    string `json:"project_uuid"`
+	ProjectName    string `json:"project_name"`
+}
+
+// GroupStatement is every location's invoices in one place, so a group's head
+// office can settle them together.
+type GroupStatement struct {
+	Invoices         []*GroupInvoice `json:"invoices"`
+	OutstandingCents int64           `json:"outstanding_cents"`
+	PaidCents        int64           `json:"paid_cents"`
+}
+
+type DealershipGroupModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func (m DealershipGroupModel) Insert(group *DealershipGroup) error {
+	query := table.DealershipGroups.INSERT(
+		table.DealershipGroups.Name,
+	).MODEL(model.DealershipGroups{
+		Name: group.Name,
+	}).RETURNING(
+		table.DealershipGroups.ID,
+		table.DealershipGroups.UUID,
+		table.DealershipGroups.UpdatedAt,
+		table.DealershipGroups.CreatedAt,
+		table.DealershipGroups.Version,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.DealershipGroups
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return err
+	}
+
+	group.ID = int(dest.ID)
+	group.UUID = dest.UUID.String()
+	group.UpdatedAt = dest.UpdatedAt
+	group.CreatedAt = dest.CreatedAt
+	group.Version = int(dest.Version)
+	if group.DealershipIDs == nil {
+		group.DealershipIDs = []int{}
+	}
+
+	return nil
+}
+
+func (m DealershipGroupModel) getWhere(condition string, args ...any) ([]*DealershipGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.STDB.QueryContext(ctx, `
+		SELECT g.id, g.uuid, g.name, g.updated_at, g.created_at, g.version, d.id
+		FROM dealership_groups g
+		LEFT JOIN dealerships d ON d.dealership_group_id = g.id
+		WHERE `+condition+`
+		ORDER BY g.name, g.id, d.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []*DealershipGroup{}
+	for rows.Next() {
+		var group DealershipGroup
+		var dealershipID sql.NullInt64
+
+		err = rows.Scan(&group.ID, &group.UUID, &group.Name, &group.UpdatedAt, &group.CreatedAt, &group.Version, &dealershipID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(groups) == 0 || groups[len(groups)-1].ID != group.ID {
+			group.DealershipIDs = []int{}
+			groups = append(groups, &group)
+		}
+		if dealershipID.Valid {
+			last := groups[len(groups)-1]
+			last.DealershipIDs = append(last.DealershipIDs, int(dealershipID.Int64))
+		}
+	}
+
+	return groups, rows.Err()
+}
+
+func (m DealershipGroupModel) getOne(condition string, args ...any) (*DealershipGroup, bool, error) {
+	groups, err := m.getWhere(condition, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(groups) == 0 {
+		return nil, false, nil
+	}
+	return groups[0], true, nil
+}
+
+func (m DealershipGroupModel) GetByID(id int) (*DealershipGroup, bool, error) {
+	return m.getOne("g.id = $1", id)
+}
+
+func (m DealershipGroupModel) GetByUUID(uuidStr string) (*DealershipGroup, bool, error) {
+	parsedUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return m.getOne("g.uuid = $1", parsedUUID)
+}
+
+func (m DealershipGroupModel) GetAll() ([]*DealershipGroup, error) {
+	return m.getWhere("true")
+}
+
+// Update saves the group's name and makes DealershipIDs its locations. A
+// location taken out of the group loses its group-level users' access: they
+// go back to seeing only their own location.
+func (m DealershipGroupModel) Update(group *DealershipGroup) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE dealership_groups SET name = $1
+		WHERE id = $2 AND version = $3
+		RETURNING updated_at, version
+	`, group.Name, group.ID, group.Version).Scan(&group.UpdatedAt, &group.Version)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE dealerships SET dealership_group_id = NULL WHERE dealership_group_id = $1`, group.ID)
+	if err != nil {
+		return err
+	}
+
+	dealershipIDs := []int{}
+	for _, dealershipID := range group.DealershipIDs {
+		if slices.Contains(dealershipIDs, dealershipID) {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE dealerships SET dealership_group_id = $1 WHERE id = $2`, group.ID, dealershipID)
+		if err != nil {
+			return err
+		}
+
+		dealershipIDs = append(dealershipIDs, dealershipID)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE dealership_users u SET dealership_group_id = NULL
+		WHERE u.dealership_group_id IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM dealerships d
+			WHERE d.id = u.dealership_id AND d.dealership_group_id = u.dealership_group_id
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	slices.Sort(dealershipIDs)
+	group.DealershipIDs = dealershipIDs
+	return nil
+}
+
+// Delete removes a group. Its locations and users stay, as independent
+// dealerships.
+func (m DealershipGroupModel) Delete(id int) error {
+	query := table.DealershipGroups.DELETE().WHERE(
+		table.DealershipGroups.ID.EQ(postgres.Int(int64(id))),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := query.ExecContext(ctx, m.STDB)
+	return err
+}
+
+// DealershipIDsFor lists the dealerships user can see: their own, and every
+// location in their group if they are a group-level user.
+func (m DealershipGroupModel) DealershipIDsFor(user *DealershipUser) ([]int, error) {
+	if user.DealershipGroupID == nil {
+		return []int{user.DealershipID}, nil
+	}
+
+	group, found, err := m.GetByID(*user.DealershipGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	dealershipIDs := []int{user.DealershipID}
+	if !found {
+		return dealershipIDs, nil
+	}
+
+	for _, dealershipID := range group.DealershipIDs {
+		if dealershipID != user.DealershipID {
+			dealershipIDs = append(dealershipIDs, dealershipID)
+		}
+	}
+
+	return dealershipIDs, nil
+}
+
+// GetStatement lists the invoices of every location in the group, newest
+// first, optionally only those with status. Voided invoices are left out
+// unless asked for.
+func (m DealershipGroupModel) GetStatement(groupID int, status *InvoiceStatus) (*GroupStatement, error) {
+	condition := "i.status <> $2"
+	statusArg := string(InvoiceStatuses.Void)
+	if status != nil {
+		condition = "i.status = $2"
+		statusArg = string(*status)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.STDB.QueryContext(ctx, `
+		SELECT i.id, i.uuid, i.project_id, i.invoice_url, i.status, i.paid_at,
+		       i.subtotal_cents, i.tax_cents, i.total_cents,
+		       i.updated_at, i.created_at, i.version,
+		       d.id, d.name, p.uuid, p.name
+		FROM invoices i
+		JOIN projects p ON p.id = i.project_id
+		JOIN dealerships d ON d.id = p.dealership_id
+		WHERE d.dealership_group_id = $1 AND `+condition+`
+		ORDER BY i.created_at DESC, i.id DESC
+	`, groupID, statusArg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statement := &GroupStatement{
+		Invoices: []*GroupInvoice{},
+	}
+	for rows.Next() {
+		var invoice GroupInvoice
+
+		err = rows.Scan(
+			&invoice.ID, &invoice.UUID, &invoice.ProjectID, &invoice.InvoiceURL, &invoice.Status, &invoice.PaidAt,
+			&invoice.SubtotalCents, &invoice.TaxCents, &invoice.TotalCents,
+			&invoice.UpdatedAt, &invoice.CreatedAt, &invoice.Version,
+			&invoice.DealershipID, &invoice.DealershipName, &invoice.ProjectUUID, &invoice.ProjectName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		statement.Invoices = append(statement.Invoices, &invoice)
+
+		switch invoice.Status {
+		case InvoiceStatuses.Sent:
+			statement.OutstandingCents += int64(invoice.TotalCents)
+		case InvoiceStatuses.Paid:
+			statement.PaidCents += int64(invoice.TotalCents)
+		}
+	}
+
+	return statement, rows.Err()
+}
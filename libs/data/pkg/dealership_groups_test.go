@@ -0,0 +1,125 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestDealershipGroup(t *testing.T, models Models, dealershipIDs ...int) *DealershipGroup {
+	t.Helper()
+
+	group := &DealershipGroup{Name: "Test Group"}
+	require.NoError(t, models.DealershipGroups.Insert(group))
+
+	group.DealershipIDs = dealershipIDs
+	require.NoError(t, models.DealershipGroups.Update(group))
+
+	return group
+}
+
+func TestDealershipGroupModel_MembershipAndTenancy(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	headOffice := createTestDealership(t, models)
+	branch := createTestDealership(t, models)
+	independent := createTestDealership(t, models)
+
+	group := createTestDealershipGroup(t, models, headOffice.ID, branch.ID, branch.ID)
+	assert.Equal(t, []int{headOffice.ID, branch.ID}, group.DealershipIDs)
+
+	fetched, found, err := models.DealershipGroups.GetByUUID(group.UUID)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, group.DealershipIDs, fetched.DealershipIDs)
+
+	dealership, _, err := models.Dealerships.GetByID(branch.ID)
+	require.NoError(t, err)
+	require.NotNil(t, dealership.DealershipGroupID)
+	assert.Equal(t, group.ID, *dealership.DealershipGroupID)
+
+	user := createTestDealershipUser(t, models, headOffice.ID)
+	dealershipIDs, err := models.DealershipGroups.DealershipIDsFor(user)
+	require.NoError(t, err)
+	assert.Equal(t, []int{headOffice.ID}, dealershipIDs, "users only see their own location until made group-level")
+
+	user.DealershipGroupID = &group.ID
+	require.NoError(t, models.DealershipUsers.Update(user))
+
+	dealershipIDs, err = models.DealershipGroups.DealershipIDsFor(user)
+	require.NoError(t, err)
+	assert.Equal(t, []int{headOffice.ID, branch.ID}, dealershipIDs)
+	assert.NotContains(t, dealershipIDs, independent.ID)
+
+	group.DealershipIDs = []int{branch.ID}
+	require.NoError(t, models.DealershipGroups.Update(group))
+
+	user, _, err = models.DealershipUsers.GetByID(user.ID)
+	require.NoError(t, err)
+	assert.Nil(t, user.DealershipGroupID, "a user whose location leaves the group loses group-level access")
+}
+
+func TestDealershipGroupModel_GetStatement(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	headOffice := createTestDealership(t, models)
+	branch := createTestDealership(t, models)
+	independent := createTestDealership(t, models)
+	group := createTestDealershipGroup(t, models, headOffice.ID, branch.ID)
+
+	for _, invoice := range []struct {
+		dealershipID int
+		status       InvoiceStatus
+	}{
+		{headOffice.ID, InvoiceStatuses.Sent},
+		{branch.ID, InvoiceStatuses.Paid},
+		{branch.ID, InvoiceStatuses.Void},
+		{independent.ID, InvoiceStatuses.Sent},
+	} {
+		project := createTestProject(t, models, invoice.dealershipID)
+		require.NoError(t, models.Invoices.Insert(&Invoice{
+			ProjectID:     project.ID,
+			Status:        invoice.status,
+			SubtotalCents: 10000,
+			TotalCents:    10000,
+		}))
+	}
+
+	statement, err := models.DealershipGroups.GetStatement(group.ID, nil)
+	require.NoError(t, err)
+	assert.Len(t, statement.Invoices, 2, "void invoices and other dealerships are left out")
+	assert.Equal(t, int64(10000), statement.OutstandingCents)
+	assert.Equal(t, int64(10000), statement.PaidCents)
+
+	void := InvoiceStatuses.Void
+	statement, err = models.DealershipGroups.GetStatement(group.ID, &void)
+	require.NoError(t, err)
+	require.Len(t, statement.Invoices, 1)
+	assert.Equal(t, branch.ID, statement.Invoices[0].DealershipID)
+}
+
+func TestGetGroupDashboard_SumsLocations(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	headOffice := createTestDealership(t, models)
+	branch := createTestDealership(t, models)
+	independent := createTestDealership(t, models)
+	group := createTestDealershipGroup(t, models, headOffice.ID, branch.ID)
+
+	insertProjectWithStatus(t, models, headOffice.ID, ProjectStatuses.Draft)
+	insertProjectWithStatus(t, models, branch.ID, ProjectStatuses.Draft)
+	insertProjectWithStatus(t, models, branch.ID, ProjectStatuses.Ordered)
+	insertProjectWithStatus(t, models, independent.ID, ProjectStatuses.Draft)
+
+	dashboard, err := models.Dashboard.GetGroupDashboard(group.ID)
+	require.NoError(t, err)
+
+	assert.Len(t, dashboard.Locations, 2)
+	assert.Equal(t, int64(2), findStatusCount(dashboard.ProjectStatusCounts, string(ProjectStatuses.Draft)))
+	assert.Equal(t, int64(1), findStatusCount(dashboard.ProjectStatusCounts, string(ProjectStatuses.Ordered)))
+	assert.Len(t, dashboard.RecentProjects, 3)
+}
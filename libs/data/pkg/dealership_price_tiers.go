@@ -0,0 +1,365 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PriceTierOverride sets a dealership's base price for one price group,
+// replacing the tier's percentage for that group.
+type PriceTierOverride struct {
+	PriceGroupID int `json:"price_group_id" validate:"required,gt=0"`
+	PriceCents   int `json:"price_cents" validate:"gte=0"`
+}
+
+// DealershipPriceTier is a dealership's negotiated rate over a date range. An
+// open-ended tier has no EffectiveUntil.
+type DealershipPriceTier struct {
+	StandardTable
+	DealershipID    int                 `json:"dealership_id"`
+	Name            string              `json:"name"`
+	DiscountPercent float64             `json:"discount_percent"`
+	EffectiveFrom   time.Time           `json:"effective_from"`
+	EffectiveUntil  *time.Time          `json:"effective_until"`
+	Overrides       []PriceTierOverride `json:"overrides"`
+}
+
+// BasePriceCents is the dealership's base price for a price group whose list
+// base is listCents. A nil tier is list price.
+func (t *DealershipPriceTier) BasePriceCents(priceGroupID, listCents int) int {
+	if t == nil {
+		return listCents
+	}
+	for _, override := range t.Overrides {
+		if override.PriceGroupID == priceGroupID {
+			return override.PriceCents
+		}
+	}
+	return max(0, int(math.Round(float64(listCents)*(1-t.DiscountPercent/100))))
+}
+
+type DealershipPriceTierModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func dealershipPriceTierFromGen(gen model.DealershipPriceTiers) *DealershipPriceTier {
+	return &DealershipPriceTier{
+		StandardTable: StandardTable{
+			ID:        int(gen.ID),
+			UUID:      gen.UUID.String(),
+			CreatedAt: gen.CreatedAt,
+			UpdatedAt: gen.UpdatedAt,
+			Version:   int(gen.Version),
+		},
+		DealershipID:    int(gen.DealershipID),
+		Name:            gen.Name,
+		DiscountPercent: gen.DiscountPercent,
+		EffectiveFrom:   gen.EffectiveFrom,
+		EffectiveUntil:  gen.EffectiveUntil,
+		Overrides:       []PriceTierOverride{},
+	}
+}
+
+func dealershipPriceTierToGen(t *DealershipPriceTier) (*model.DealershipPriceTiers, error) {
+	var tierUUID uuid.UUID
+	var err error
+
+	if t.UUID != "" {
+		tierUUID, err = uuid.Parse(t.UUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.DealershipPriceTiers{
+		ID:              int32(t.ID),
+		UUID:            tierUUID,
+		DealershipID:    int32(t.DealershipID),
+		Name:            t.Name,
+		DiscountPercent: t.DiscountPercent,
+		EffectiveFrom:   t.EffectiveFrom,
+		EffectiveUntil:  t.EffectiveUntil,
+		UpdatedAt:       t.UpdatedAt,
+		CreatedAt:       t.CreatedAt,
+		Version:         int32(t.Version),
+	}, nil
+}
+
+func (m DealershipPriceTierModel) Insert(tier *DealershipPriceTier) error {
+	gen, err := dealershipPriceTierToGen(tier)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := table.DealershipPriceTiers.INSERT(
+		table.DealershipPriceTiers.DealershipID,
+		table.DealershipPriceTiers.Name,
+		table.DealershipPriceTiers.DiscountPercent,
+		table.DealershipPriceTiers.EffectiveFrom,
+		table.DealershipPriceTiers.EffectiveUntil,
+	).MODEL(
+		gen,
+	).RETURNING(
+		table.DealershipPriceTiers.ID,
+		table.DealershipPriceTiers.UUID,
+		table.DealershipPriceTiers.UpdatedAt,
+		table.DealershipPriceTiers.CreatedAt,
+		table.DealershipPriceTiers.Version,
+	)
+
+	var dest model.DealershipPriceTiers
+	if err := query.QueryContext(ctx, tx, &dest); err != nil {
+		return err
+	}
+
+	tier.ID = int(dest.ID)
+	tier.UUID = dest.UUID.String()
+	tier.UpdatedAt = dest.UpdatedAt
+	tier.CreatedAt = dest.CreatedAt
+	tier.Version = int(dest.Version)
+
+	if err := m.txReplaceOverrides(ctx, tx, tier); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update writes the tier's terms and replaces its overrides with
+// tier.Overrides.
+func (m DealershipPriceTierModel) Update(tier *DealershipPriceTier) error {
+	gen, err := dealershipPriceTierToGen(tier)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := table.DealershipPriceTiers.UPDATE(
+		table.DealershipPriceTiers.Name,
+		table.DealershipPriceTiers.DiscountPercent,
+		table.DealershipPriceTiers.EffectiveFrom,
+		table.DealershipPriceTiers.EffectiveUntil,
+		table.DealershipPriceTiers.Version,
+	).MODEL(
+		gen,
+	).WHERE(
+		postgres.AND(
+			table.DealershipPriceTiers.ID.EQ(postgres.Int(int64(tier.ID))),
+			table.DealershipPriceTiers.Version.EQ(postgres.Int(int64(tier.Version))),
+		),
+	).RETURNING(
+		table.DealershipPriceTiers.UpdatedAt,
+		table.DealershipPriceTiers.Version,
+	)
+
+	var dest model.DealershipPriceTiers
+	if err := query.QueryContext(ctx, tx, &dest); err != nil {
+		return err
+	}
+
+	tier.UpdatedAt = dest.UpdatedAt
+	tier.Version = int(dest.Version)
+
+	if err := m.txReplaceOverrides(ctx, tx, tier); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m DealershipPriceTierModel) txReplaceOverrides(ctx context.Context, tx *sql.Tx, tier *DealershipPriceTier) error {
+	_, err := table.DealershipPriceTierOverrides.DELETE().WHERE(
+		table.DealershipPriceTierOverrides.TierID.EQ(postgres.Int(int64(tier.ID))),
+	).ExecContext(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if len(tier.Overrides) == 0 {
+		tier.Overrides = []PriceTierOverride{}
+		return nil
+	}
+
+	genOverrides := make([]model.DealershipPriceTierOverrides, len(tier.Overrides))
+	for i, override := range tier.Overrides {
+		genOverrides[i] = model.DealershipPriceTierOverrides{
+			TierID:       int32(tier.ID),
+			PriceGroupID: int32(override.PriceGroupID),
+			PriceCents:   int32(override.PriceCents),
+		}
+	}
+
+	_, err = table.DealershipPriceTierOverrides.INSERT(
+		table.DealershipPriceTierOverrides.TierID,
+		table.DealershipPriceTierOverrides.PriceGroupID,
+		table.DealershipPriceTierOverrides.PriceCents,
+	).MODELS(
+		genOverrides,
+	).ExecContext(ctx, tx)
+	return err
+}
+
+func (m DealershipPriceTierModel) getMany(condition postgres.BoolExpression, limit int64) ([]*DealershipPriceTier, error) {
+	query := postgres.SELECT(
+		table.DealershipPriceTiers.AllColumns,
+	).FROM(
+		table.DealershipPriceTiers,
+	).WHERE(
+		condition,
+	).ORDER_BY(
+		table.DealershipPriceTiers.EffectiveFrom.DESC(),
+		table.DealershipPriceTiers.ID.DESC(),
+	)
+	if limit > 0 {
+		query = query.LIMIT(limit)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.DealershipPriceTiers
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return nil, err
+	}
+
+	tiers := make([]*DealershipPriceTier, len(dest))
+	for i, d := range dest {
+		tiers[i] = dealershipPriceTierFromGen(d)
+	}
+
+	if err := m.withOverrides(ctx, tiers); err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+func (m DealershipPriceTierModel) withOverrides(ctx context.Context, tiers []*DealershipPriceTier) error {
+	if len(tiers) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*DealershipPriceTier, len(tiers))
+	ids := make([]postgres.Expression, len(tiers))
+	for i, t := range tiers {
+		byID[t.ID] = t
+		ids[i] = postgres.Int(int64(t.ID))
+	}
+
+	query := postgres.SELECT(
+		table.DealershipPriceTierOverrides.AllColumns,
+	).FROM(
+		table.DealershipPriceTierOverrides,
+	).WHERE(
+		table.DealershipPriceTierOverrides.TierID.IN(ids...),
+	).ORDER_BY(
+		table.DealershipPriceTierOverrides.PriceGroupID.ASC(),
+	)
+
+	var dest []model.DealershipPriceTierOverrides
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return err
+	}
+
+	for _, d := range dest {
+		t := byID[int(d.TierID)]
+		t.Overrides = append(t.Overrides, PriceTierOverride{
+			PriceGroupID: int(d.PriceGroupID),
+			PriceCents:   int(d.PriceCents),
+		})
+	}
+	return nil
+}
+
+func (m DealershipPriceTierModel) GetByUUID(uuidStr string) (*DealershipPriceTier, bool, error) {
+	parsedUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tiers, err := m.getMany(table.DealershipPriceTiers.UUID.EQ(postgres.UUID(parsedUUID)), 1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(tiers) == 0 {
+		return nil, false, nil
+	}
+	return tiers[0], true, nil
+}
+
+// GetByDealershipID returns a dealership's tiers, latest to take effect first.
+func (m DealershipPriceTierModel) GetByDealershipID(dealershipID int) ([]*DealershipPriceTier, error) {
+	return m.getMany(table.DealershipPriceTiers.DealershipID.EQ(postgres.Int(int64(dealershipID))), 0)
+}
+
+// GetActiveForDealership returns the tier in effect for a dealership at a
+// moment. Where tiers overlap, the one that took effect last wins.
+func (m DealershipPriceTierModel) GetActiveForDealership(dealershipID int, at time.Time) (*DealershipPriceTier, bool, error) {
+	return m.getActive(table.DealershipPriceTiers.DealershipID.EQ(postgres.Int(int64(dealershipID))), at)
+}
+
+// GetActiveForProject is GetActiveForDealership for the project's dealership.
+func (m DealershipPriceTierModel) GetActiveForProject(projectID int, at time.Time) (*DealershipPriceTier, bool, error) {
+	return m.getActive(table.DealershipPriceTiers.DealershipID.IN(
+		postgres.SELECT(table.Projects.DealershipID).
+			FROM(table.Projects).
+			WHERE(table.Projects.ID.EQ(postgres.Int(int64(projectID)))),
+	), at)
+}
+
+func (m DealershipPriceTierModel) getActive(dealership postgres.BoolExpression, at time.Time) (*DealershipPriceTier, bool, error) {
+	tiers, err := m.getMany(postgres.AND(
+		dealership,
+		table.DealershipPriceTiers.EffectiveFrom.LT_EQ(postgres.TimestampzT(at)),
+		postgres.OR(
+			table.DealershipPriceTiers.EffectiveUntil.IS_NULL(),
+			table.DealershipPriceTiers.EffectiveUntil.GT(postgres.TimestampzT(at)),
+		),
+	), 1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(tiers) == 0 {
+		return nil, false, nil
+	}
+	return tiers[0], true, nil
+}
+
+func (m DealershipPriceTierModel) Delete(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := table.DealershipPriceTiers.DELETE().WHERE(
+		table.DealershipPriceTiers.ID.EQ(postgres.Int(int64(id))),
+	).ExecContext(ctx, m.STDB)
+	return err
+}
@@ -0,0 +1,80 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDealershipPriceTier_GetActive(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+	models := getTestModels(t)
+
+	dealership := createTestDealership(t, models)
+	project := createTestProject(t, models, dealership.ID)
+	priceGroup := createTestPriceGroup(t, models)
+
+	now := time.Now()
+	expired := now.Add(-time.Hour)
+
+	standing := &DealershipPriceTier{
+		DealershipID:    dealership.ID,
+		Name:            "Standing",
+		DiscountPercent: 5,
+		EffectiveFrom:   now.AddDate(0, -1, 0),
+	}
+	promo := &DealershipPriceTier{
+		DealershipID:    dealership.ID,
+		Name:            "Promo",
+		DiscountPercent: 20,
+		EffectiveFrom:   now.AddDate(0, 0, -7),
+		EffectiveUntil:  &expired,
+	}
+	contract := &DealershipPriceTier{
+		DealershipID:  dealership.ID,
+		Name:          "Contract",
+		EffectiveFrom: now.AddDate(0, 0, -1),
+		Overrides:     []PriceTierOverride{{PriceGroupID: priceGroup.ID, PriceCents: 4200}},
+	}
+	for _, tier := range []*DealershipPriceTier{standing, promo, contract} {
+		if err := models.DealershipPriceTiers.Insert(tier); err != nil {
+			t.Fatalf("Failed to insert tier %q: %v", tier.Name, err)
+		}
+	}
+
+	active, found, err := models.DealershipPriceTiers.GetActiveForProject(project.ID, now)
+	if err != nil {
+		t.Fatalf("Failed to get active tier: %v", err)
+	}
+	if !found || active.ID != contract.ID {
+		t.Fatalf("Expected the latest-starting tier, got %+v", active)
+	}
+	if len(active.Overrides) != 1 || active.BasePriceCents(priceGroup.ID, 10000) != 4200 {
+		t.Errorf("Expected the override to price the group, got %+v", active.Overrides)
+	}
+	if active.BasePriceCents(priceGroup.ID+1, 10000) != 10000 {
+		t.Error("Expected groups without an override to keep their list price")
+	}
+
+	if err := models.DealershipPriceTiers.Delete(contract.ID); err != nil {
+		t.Fatalf("Failed to delete tier: %v", err)
+	}
+
+	active, found, err = models.DealershipPriceTiers.GetActiveForDealership(dealership.ID, now)
+	if err != nil {
+		t.Fatalf("Failed to get active tier: %v", err)
+	}
+	if !found || active.ID != standing.ID {
+		t.Fatalf("Expected the standing tier once the promo expired, got %+v", active)
+	}
+	if active.BasePriceCents(priceGroup.ID, 10000) != 9500 {
+		t.Errorf("Expected 5%% off, got %d", active.BasePriceCents(priceGroup.ID, 10000))
+	}
+
+	_, found, err = models.DealershipPriceTiers.GetActiveForDealership(dealership.ID, now.AddDate(0, -2, 0))
+	if err != nil {
+		t.Fatalf("Failed to get active tier: %v", err)
+	}
+	if found {
+		t.Error("Expected no tier before any took effect")
+	}
+}
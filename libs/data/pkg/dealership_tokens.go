@@ -5,10 +5,14 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"database/sql"
+	"errors"
 	"time"
 
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
 	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
 	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -24,6 +28,8 @@ type DealershipToken struct {
 	DealershipUserID int
 	Expiry           time.Time
 	Scope            string
+	SessionID        string
+	Client           SessionClient
 }
 
 func generateDealershipToken(dealershipUserID int, ttl time.Duration, scope string) *DealershipToken {
@@ -56,18 +62,47 @@ func (m DealershipTokenModel) New(dealershipUserID int, ttl time.Duration, scope
 	return token, nil
 }
 
+// NewForSession issues a refresh or access token belonging to a session.
+func (m DealershipTokenModel) NewForSession(dealershipUserID int, ttl time.Duration, scope, sessionID string, client SessionClient) (*DealershipToken, error) {
+	token := generateDealershipToken(dealershipUserID, ttl, scope)
+	token.SessionID = sessionID
+	token.Client = client
+
+	err := m.Insert(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
 func (m DealershipTokenModel) Insert(token *DealershipToken) error {
+	var sessionID *uuid.UUID
+	if token.SessionID != "" {
+		parsed, err := uuid.Parse(token.SessionID)
+		if err != nil {
+			return err
+		}
+		sessionID = &parsed
+	}
+
 	query := table.DealershipTokens.INSERT(
 		table.DealershipTokens.Hash,
 		table.DealershipTokens.DealershipUserID,
 		table.DealershipTokens.Expiry,
 		table.DealershipTokens.Scope,
-	).VALUES(
-		token.Hash,
-		int32(token.DealershipUserID),
-		token.Expiry,
-		token.Scope,
-	)
+		table.DealershipTokens.SessionID,
+		table.DealershipTokens.UserAgent,
+		table.DealershipTokens.IP,
+	).MODEL(model.DealershipTokens{
+		Hash:             token.Hash,
+		DealershipUserID: int32(token.DealershipUserID),
+		Expiry:           token.Expiry,
+		Scope:            token.Scope,
+		SessionID:        sessionID,
+		UserAgent:        token.Client.UserAgent,
+		IP:               token.Client.IP,
+	})
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -91,6 +126,23 @@ func (m DealershipTokenModel) DeleteAllForUser(scope string, dealershipUserID in
 	return err
 }
 
+// DeleteExpired removes tokens past their expiry, which no longer work, and
+// returns how many there were.
+func (m DealershipTokenModel) DeleteExpired() (int64, error) {
+	query := table.DealershipTokens.DELETE().WHERE(
+		table.DealershipTokens.Expiry.LT(postgres.TimestampzExp(Now())),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := query.ExecContext(ctx, m.STDB)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 func (m DealershipTokenModel) DeleteByPlaintext(scope string, plaintext string) error {
 	hash := sha256.Sum256([]byte(plaintext))
 
@@ -107,3 +159,70 @@ func (m DealershipTokenModel) DeleteByPlaintext(scope string, plaintext string)
 	_, err := query.ExecContext(ctx, m.STDB)
 	return err
 }
+
+// Consume uses up an unexpired token and returns whose it was. Deleting it
+// as it is looked up means two requests racing with the same link cannot both
+// get in. The user's other tokens of the scope go with it, so older links stop
+// working once one has been used.
+func (m DealershipTokenModel) Consume(scope string, plaintext string) (int, bool, error) {
+	hash := sha256.Sum256([]byte(plaintext))
+
+	query := table.DealershipTokens.DELETE().WHERE(
+		postgres.AND(
+			table.DealershipTokens.Scope.EQ(postgres.String(scope)),
+			table.DealershipTokens.Hash.EQ(postgres.Bytea(hash[:])),
+			table.DealershipTokens.Expiry.GT(postgres.TimestampzExp(Now())),
+		),
+	).RETURNING(
+		table.DealershipTokens.DealershipUserID,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.DealershipTokens
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	userID := int(dest.DealershipUserID)
+
+	err = m.DeleteAllForUser(scope, userID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return userID, true, nil
+}
+
+// Rotate exchanges a refresh token for the next one in its session. See
+// RotateRefreshToken.
+func (m DealershipTokenModel) Rotate(plaintext string, client SessionClient) (*RefreshRotation, error) {
+	return dealershipSessionTokens.rotate(m.STDB, plaintext, client)
+}
+
+func (m DealershipTokenModel) GetSessions(dealershipUserID int) ([]*Session, error) {
+	return dealershipSessionTokens.list(m.STDB, dealershipUserID)
+}
+
+// GetSessionID returns the session a refresh or access token belongs to.
+func (m DealershipTokenModel) GetSessionID(scope, plaintext string) (string, bool, error) {
+	return dealershipSessionTokens.sessionID(m.STDB, scope, plaintext)
+}
+
+func (m DealershipTokenModel) RevokeSession(dealershipUserID int, sessionID string) (bool, error) {
+	return dealershipSessionTokens.revoke(m.STDB, dealershipUserID, sessionID)
+}
+
+// RevokeSessionByPlaintext ends the session a refresh token belongs to.
+func (m DealershipTokenModel) RevokeSessionByPlaintext(plaintext string) error {
+	return dealershipSessionTokens.revokeByPlaintext(m.STDB, plaintext)
+}
+
+func (m DealershipTokenModel) RevokeAllSessions(dealershipUserID int) error {
+	return dealershipSessionTokens.revokeAll(m.STDB, dealershipUserID)
+}
@@ -232,3 +232,34 @@ func TestDealershipToken_DifferentScopes(t *testing.T) {
 		t.Errorf("Expected refresh scope")
 	}
 }
+
+func TestDealershipToken_DeleteExpired(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	user := createTestDealershipUser(t, models, dealership.ID)
+
+	for _, ttl := range []time.Duration{-time.Hour, -time.Minute, time.Hour} {
+		_, err := models.DealershipTokens.New(user.ID, ttl, DealershipScopeAccess)
+		if err != nil {
+			t.Fatalf("Failed to create token: %v", err)
+		}
+	}
+
+	deleted, err := models.DealershipTokens.DeleteExpired()
+	if err != nil {
+		t.Fatalf("Failed to delete expired tokens: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("Expected 2 expired tokens deleted, got %d", deleted)
+	}
+
+	deleted, err = models.DealershipTokens.DeleteExpired()
+	if err != nil {
+		t.Fatalf("Failed to delete expired tokens: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("Expected the live token to be kept, got %d deleted", deleted)
+	}
+}
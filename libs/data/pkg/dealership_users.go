@@ -39,6 +39,9 @@ type DealershipUser struct {
 	Avatar       string             `json:"avatar"`
 	Role         DealershipUserRole `json:"role"`
 	IsActive     bool               `json:"is_active"`
+	// DealershipGroupID makes a group-level user, who can see every location
+	// in the group rather than only DealershipID.
+	DealershipGroupID *int `json:"dealership_group_id"`
 }
 
 type DealershipUserModel struct {
@@ -63,6 +66,11 @@ func dealershipUserFromGen(genUser model.DealershipUsers) *DealershipUser {
 		IsActive:     genUser.IsActive,
 	}
 
+	if genUser.DealershipGroupID != nil {
+		groupID := int(*genUser.DealershipGroupID)
+		user.DealershipGroupID = &groupID
+	}
+
 	return &user
 }
 
@@ -91,10 +99,15 @@ func dealershipUserToGen(u *DealershipUser) (*model.DealershipUsers, error) {
 		Version:      int32(u.Version),
 	}
 
+	if u.DealershipGroupID != nil {
+		groupID := int32(*u.DealershipGroupID)
+		genUser.DealershipGroupID = &groupID
+	}
+
 	return &genUser, nil
 }
 
-func (m DealershipUserModel) Insert(user *DealershipUser) error {
+func (m DealershipUserModel) insertUser(ctx context.Context, executor qrm.Queryable, user *DealershipUser) error {
 	genUser, err := dealershipUserToGen(user)
 	if err != nil {
 		return err
@@ -107,6 +120,7 @@ func (m DealershipUserModel) Insert(user *DealershipUser) error {
 		table.DealershipUsers.Avatar,
 		table.DealershipUsers.Role,
 		table.DealershipUsers.IsActive,
+		table.DealershipUsers.DealershipGroupID,
 	).MODEL(
 		genUser,
 	).RETURNING(
@@ -117,11 +131,8 @@ func (m DealershipUserModel) Insert(user *DealershipUser) error {
 		table.DealershipUsers.Version,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
 	var dest model.DealershipUsers
-	err = query.QueryContext(ctx, m.STDB, &dest)
+	err = query.QueryContext(ctx, executor, &dest)
 	if err != nil {
 		return err
 	}
@@ -135,6 +146,20 @@ func (m DealershipUserModel) Insert(user *DealershipUser) error {
 	return nil
 }
 
+func (m DealershipUserModel) Insert(user *DealershipUser) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.insertUser(ctx, m.STDB, user)
+}
+
+func (m DealershipUserModel) TxInsert(tx *sql.Tx, user *DealershipUser) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.insertUser(ctx, tx, user)
+}
+
 func (m DealershipUserModel) GetByID(id int) (*DealershipUser, bool, error) {
 	query := postgres.SELECT(
 		table.DealershipUsers.AllColumns,
@@ -218,6 +243,37 @@ func (m DealershipUserModel) GetByEmail(email string) (*DealershipUser, bool, er
 	return dealershipUserFromGen(dest), true, nil
 }
 
+// GetExistingEmails returns those of emails that already belong to a
+// dealership user. Emails are compared case-insensitively.
+func (m DealershipUserModel) GetExistingEmails(emails []string) ([]string, error) {
+	existing := []string{}
+	if len(emails) == 0 {
+		return existing, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.STDB.QueryContext(ctx, `
+		SELECT email FROM dealership_users
+		WHERE email = ANY($1::citext[])
+	`, emails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		existing = append(existing, email)
+	}
+
+	return existing, rows.Err()
+}
+
 func (m DealershipUserModel) GetForToken(tokenScope, tokenPlaintext string) (*DealershipUser, bool, error) {
 	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
 
@@ -300,7 +356,7 @@ func (m DealershipUserModel) GetAll() ([]*DealershipUser, error) {
 	return users, nil
 }
 
-func (m DealershipUserModel) Update(user *DealershipUser) error {
+func (m DealershipUserModel) updateUser(ctx context.Context, executor qrm.Queryable, user *DealershipUser) error {
 	genUser, err := dealershipUserToGen(user)
 	if err != nil {
 		return err
@@ -312,6 +368,7 @@ func (m DealershipUserModel) Update(user *DealershipUser) error {
 		table.DealershipUsers.Avatar,
 		table.DealershipUsers.Role,
 		table.DealershipUsers.IsActive,
+		table.DealershipUsers.DealershipGroupID,
 		table.DealershipUsers.Version,
 	).MODEL(
 		genUser,
@@ -325,11 +382,8 @@ func (m DealershipUserModel) Update(user *DealershipUser) error {
 		table.DealershipUsers.Version,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
 	var dest model.DealershipUsers
-	err = query.QueryContext(ctx, m.STDB, &dest)
+	err = query.QueryContext(ctx, executor, &dest)
 	if err != nil {
 		return err
 	}
@@ -340,6 +394,20 @@ func (m DealershipUserModel) Update(user *DealershipUser) error {
 	return nil
 }
 
+func (m DealershipUserModel) Update(user *DealershipUser) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.updateUser(ctx, m.STDB, user)
+}
+
+func (m DealershipUserModel) TxUpdate(tx *sql.Tx, user *DealershipUser) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.updateUser(ctx, tx, user)
+}
+
 func (m DealershipUserModel) Delete(id int) error {
 	query := table.DealershipUsers.DELETE().WHERE(
 		table.DealershipUsers.ID.EQ(postgres.Int(int64(id))),
@@ -395,42 +463,3 @@ func (u *DealershipUser) IsDealership() bool {
 func (u *DealershipUser) GetDealershipID() *int {
 	return &u.DealershipID
 }
-
-func (u *DealershipUser) Can(action string) bool {
-	switch action {
-	case ActionCreateProject:
-		return u.Role == DealershipUserRoles.Submitter ||
-			u.Role == DealershipUserRoles.Approver ||
-			u.Role == DealershipUserRoles.Admin
-	case ActionManageProject:
-		return u.Role == DealershipUserRoles.Submitter ||
-			u.Role == DealershipUserRoles.Approver ||
-			u.Role == DealershipUserRoles.Admin
-	case ActionSendChat:
-		return u.Role == DealershipUserRoles.Submitter ||
-			u.Role == DealershipUserRoles.Approver ||
-			u.Role == DealershipUserRoles.Admin
-	case ActionApproveProof:
-		return u.Role == DealershipUserRoles.Approver ||
-			u.Role == DealershipUserRoles.Admin
-	case ActionPlaceOrder:
-		return u.Role == DealershipUserRoles.Approver ||
-			u.Role == DealershipUserRoles.Admin
-	case ActionPayInvoice:
-		return u.Role == DealershipUserRoles.Admin
-	case ActionManageDealershipUsers:
-		return u.Role == DealershipUserRoles.Admin
-	case ActionManageDealership:
-		return u.Role == DealershipUserRoles.Admin
-	case ActionViewProjects:
-		return true
-	case ActionViewInvoices:
-		return true
-	case ActionAccessAdmin:
-		return false
-	case ActionManageDealerships:
-		return false
-	default:
-		return false
-	}
-}
@@ -68,6 +68,33 @@ type Dealership struct {
 	PaymentTiming       PaymentTiming       `json:"payment_timing"`
 	SandblastFileFormat SandblastFileFormat `json:"sandblast_file_format"`
 	Address             Address             `json:"address"`
+	TaxExemption        TaxExemption        `json:"tax_exemption"`
+	// DealershipGroupID is the group this location belongs to, if any. It is
+	// set by GlassAct through the group, never by the dealership.
+	DealershipGroupID *int `json:"dealership_group_id"`
+}
+
+// TaxExemption is a dealership's resale or exemption certificate. It is set by
+// GlassAct billing, never by the dealership, and only holds while unexpired.
+type TaxExemption struct {
+	Exempt      bool       `json:"exempt"`
+	Certificate *string    `json:"certificate"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// IsTaxExempt reports whether the dealership's certificate covers an order
+// placed at the given time. A flag without a certificate on file does not.
+func (d *Dealership) IsTaxExempt(at time.Time) bool {
+	e := d.TaxExemption
+	if !e.Exempt || e.Certificate == nil || *e.Certificate == "" {
+		return false
+	}
+	if e.ExpiresAt == nil {
+		return true
+	}
+	// The certificate is good through the whole of its expiry date.
+	year, month, day := e.ExpiresAt.Date()
+	return at.Before(time.Date(year, month, day+1, 0, 0, 0, 0, e.ExpiresAt.Location()))
 }
 
 type DealershipModel struct {
@@ -98,6 +125,16 @@ func dealershipFromGen(genDeal model.Dealerships, longitude, latitude float64) *
 			Longitude:  longitude,
 			Latitude:   latitude,
 		},
+		TaxExemption: TaxExemption{
+			Exempt:      genDeal.TaxExempt,
+			Certificate: genDeal.TaxExemptCertificate,
+			ExpiresAt:   genDeal.TaxExemptExpiresAt,
+		},
+	}
+
+	if genDeal.DealershipGroupID != nil {
+		groupID := int(*genDeal.DealershipGroupID)
+		dealership.DealershipGroupID = &groupID
 	}
 
 	return &dealership
@@ -115,27 +152,35 @@ func dealershipToGen(d *Dealership) (*model.Dealerships, error) {
 	}
 
 	genDeal := model.Dealerships{
-		ID:                  int32(d.ID),
-		UUID:                dealershipUUID,
-		Name:                d.Name,
-		Street:              d.Address.Street,
-		StreetExt:           d.Address.StreetExt,
-		City:                d.Address.City,
-		State:               d.Address.State,
-		PostalCode:          d.Address.PostalCode,
-		Country:             d.Address.Country,
-		Phone:               d.Phone,
-		PaymentTiming:       string(d.PaymentTiming),
-		SandblastFileFormat: string(d.SandblastFileFormat),
-		UpdatedAt:           d.UpdatedAt,
-		CreatedAt:           d.CreatedAt,
-		Version:             int32(d.Version),
+		ID:                   int32(d.ID),
+		UUID:                 dealershipUUID,
+		Name:                 d.Name,
+		Street:               d.Address.Street,
+		StreetExt:            d.Address.StreetExt,
+		City:                 d.Address.City,
+		State:                d.Address.State,
+		PostalCode:           d.Address.PostalCode,
+		Country:              d.Address.Country,
+		Phone:                d.Phone,
+		PaymentTiming:        string(d.PaymentTiming),
+		SandblastFileFormat:  string(d.SandblastFileFormat),
+		TaxExempt:            d.TaxExemption.Exempt,
+		TaxExemptCertificate: d.TaxExemption.Certificate,
+		TaxExemptExpiresAt:   d.TaxExemption.ExpiresAt,
+		UpdatedAt:            d.UpdatedAt,
+		CreatedAt:            d.CreatedAt,
+		Version:              int32(d.Version),
+	}
+
+	if d.DealershipGroupID != nil {
+		groupID := int32(*d.DealershipGroupID)
+		genDeal.DealershipGroupID = &groupID
 	}
 
 	return &genDeal, nil
 }
 
-func (m DealershipModel) Insert(dealership *Dealership) error {
+func (m DealershipModel) insertDealership(ctx context.Context, executor qrm.Queryable, dealership *Dealership) error {
 	genDeal, err := dealershipToGen(dealership)
 	if err != nil {
 		return err
@@ -177,11 +222,8 @@ func (m DealershipModel) Insert(dealership *Dealership) error {
 		table.Dealerships.Version,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
 	var dest model.Dealerships
-	err = query.QueryContext(ctx, m.STDB, &dest)
+	err = query.QueryContext(ctx, executor, &dest)
 	if err != nil {
 		return err
 	}
@@ -195,6 +237,20 @@ func (m DealershipModel) Insert(dealership *Dealership) error {
 	return nil
 }
 
+func (m DealershipModel) Insert(dealership *Dealership) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.insertDealership(ctx, m.STDB, dealership)
+}
+
+func (m DealershipModel) TxInsert(tx *sql.Tx, dealership *Dealership) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.insertDealership(ctx, tx, dealership)
+}
+
 func (m DealershipModel) GetByID(id int) (*Dealership, bool, error) {
 	query := postgres.SELECT(
 		table.Dealerships.AllColumns,
@@ -264,6 +320,41 @@ func (m DealershipModel) GetByUUID(uuidStr string) (*Dealership, bool, error) {
 	return dealershipFromGen(dest.Dealerships, dest.Longitude, dest.Latitude), true, nil
 }
 
+// FirstMissingID returns the first of ids that names no dealership, or false
+// when every id exists.
+func (m DealershipModel) FirstMissingID(ids []int) (int, bool, error) {
+	if len(ids) == 0 {
+		return 0, false, nil
+	}
+
+	wanted := make([]int64, len(ids))
+	for i, id := range ids {
+		wanted[i] = int64(id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var missing int
+	err := m.STDB.QueryRowContext(ctx, `
+		SELECT w.id
+		FROM unnest($1::bigint[]) WITH ORDINALITY AS w(id, position)
+		WHERE NOT EXISTS (SELECT 1 FROM dealerships d WHERE d.id = w.id)
+		ORDER BY w.position
+		LIMIT 1
+	`, wanted).Scan(&missing)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return 0, false, nil
+		default:
+			return 0, false, err
+		}
+	}
+
+	return missing, true, nil
+}
+
 func (m DealershipModel) GetAll() ([]*Dealership, error) {
 	query := postgres.SELECT(
 		table.Dealerships.AllColumns,
@@ -353,6 +444,46 @@ func (m DealershipModel) Update(dealership *Dealership) error {
 	return nil
 }
 
+// UpdateTaxExemption writes only the exemption, leaving the rest of the
+// dealership as the dealership last saved it.
+func (m DealershipModel) UpdateTaxExemption(dealership *Dealership) error {
+	genDeal, err := dealershipToGen(dealership)
+	if err != nil {
+		return err
+	}
+
+	query := table.Dealerships.UPDATE(
+		table.Dealerships.TaxExempt,
+		table.Dealerships.TaxExemptCertificate,
+		table.Dealerships.TaxExemptExpiresAt,
+		table.Dealerships.Version,
+	).MODEL(
+		genDeal,
+	).WHERE(
+		postgres.AND(
+			table.Dealerships.ID.EQ(postgres.Int(int64(dealership.ID))),
+			table.Dealerships.Version.EQ(postgres.Int(int64(dealership.Version))),
+		),
+	).RETURNING(
+		table.Dealerships.UpdatedAt,
+		table.Dealerships.Version,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.Dealerships
+	err = query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return err
+	}
+
+	dealership.UpdatedAt = dest.UpdatedAt
+	dealership.Version = int(dest.Version)
+
+	return nil
+}
+
 func (m DealershipModel) Delete(id int) error {
 	query := table.Dealerships.DELETE().WHERE(
 		table.Dealerships.ID.EQ(postgres.Int(int64(id))),
@@ -195,3 +195,29 @@ func TestDealership_Delete(t *testing.T) {
 		t.Errorf("Expected dealership to be deleted")
 	}
 }
+
+func TestDealership_FirstMissingID(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	created := createTestDealership(t, models)
+
+	_, missing, err := models.Dealerships.FirstMissingID([]int{created.ID})
+	if err != nil {
+		t.Fatalf("Failed to check dealerships: %v", err)
+	}
+	if missing {
+		t.Errorf("Expected every dealership to exist")
+	}
+
+	missingID, missing, err := models.Dealerships.FirstMissingID([]int{created.ID, created.ID + 1000, created.ID + 2000})
+	if err != nil {
+		t.Fatalf("Failed to check dealerships: %v", err)
+	}
+	if !missing {
+		t.Fatalf("Expected a missing dealership")
+	}
+	if missingID != created.ID+1000 {
+		t.Errorf("Expected missing ID %d, got %d", created.ID+1000, missingID)
+	}
+}
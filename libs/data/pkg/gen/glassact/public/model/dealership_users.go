@@ -13,15 +13,16 @@ import (
 )
 
 type DealershipUsers struct {
-	ID           int32 `sql:"primary_key"`
-	UUID         uuid.UUID
-	DealershipID int32
-	Name         string
-	Email        string
-	Avatar       string
-	Role         string
-	IsActive     bool
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	Version      int32
+	ID                int32 `sql:"primary_key"`
+	UUID              uuid.UUID
+	DealershipID      int32
+	Name              string
+	Email             string
+	Avatar            string
+	Role              string
+	IsActive          bool
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Version           int32
+	DealershipGroupID *int32
 }
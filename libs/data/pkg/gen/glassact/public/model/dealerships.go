@@ -13,20 +13,24 @@ import (
 )
 
 type Dealerships struct {
-	ID                  int32 `sql:"primary_key"`
-	UUID                uuid.UUID
-	Name                string
-	Street              string
-	StreetExt           string
-	City                string
-	State               string
-	PostalCode          string
-	Country             string
-	Location            string
-	CreatedAt           time.Time
-	UpdatedAt           time.Time
-	Version             int32
-	PaymentTiming       string
-	SandblastFileFormat string
-	Phone               string
+	ID                   int32 `sql:"primary_key"`
+	UUID                 uuid.UUID
+	Name                 string
+	Street               string
+	StreetExt            string
+	City                 string
+	State                string
+	PostalCode           string
+	Country              string
+	Location             string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Version              int32
+	PaymentTiming        string
+	SandblastFileFormat  string
+	Phone                string
+	TaxExempt            bool
+	TaxExemptCertificate *string
+	TaxExemptExpiresAt   *time.Time
+	DealershipGroupID    *int32
 }
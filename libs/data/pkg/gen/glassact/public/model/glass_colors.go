@@ -13,14 +13,19 @@ import (
 )
 
 type GlassColors struct {
-	ID        int32 `sql:"primary_key"`
-	UUID      uuid.UUID
-	Name      string
-	Hex       string
-	Family    *string
-	SortOrder int32
-	IsActive  bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Version   int32
+	ID               int32 `sql:"primary_key"`
+	UUID             uuid.UUID
+	Name             string
+	Hex              string
+	Family           *string
+	SortOrder        int32
+	IsActive         bool
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Version          int32
+	Finish           string
+	Texture          string
+	Opacity          float64
+	SwatchImageURL   *string
+	UnavailableUntil *time.Time
 }
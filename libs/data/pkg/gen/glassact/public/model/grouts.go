@@ -13,13 +13,14 @@ import (
 )
 
 type Grouts struct {
-	ID        int32 `sql:"primary_key"`
-	UUID      uuid.UUID
-	Name      string
-	Hex       string
-	SortOrder int32
-	IsActive  bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Version   int32
+	ID               int32 `sql:"primary_key"`
+	UUID             uuid.UUID
+	Name             string
+	Hex              string
+	SortOrder        int32
+	IsActive         bool
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Version          int32
+	UnavailableUntil *time.Time
 }
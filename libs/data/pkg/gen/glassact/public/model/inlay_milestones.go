@@ -23,4 +23,5 @@ type InlayMilestones struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Version     int32
+	Cycle       int32
 }
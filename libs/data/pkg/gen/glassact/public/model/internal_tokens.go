@@ -8,6 +8,7 @@
 package model
 
 import (
+	"github.com/google/uuid"
 	"time"
 )
 
@@ -16,4 +17,9 @@ type InternalTokens struct {
 	InternalUserID int32
 	Expiry         time.Time
 	Scope          string
+	SessionID      *uuid.UUID
+	UserAgent      string
+	IP             string
+	CreatedAt      time.Time
+	RotatedAt      *time.Time
 }
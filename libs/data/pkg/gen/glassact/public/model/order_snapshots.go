@@ -25,4 +25,10 @@ type OrderSnapshots struct {
 	Width                float64
 	Height               float64
 	CreatedAt            time.Time
+	RemakeID             *int32
+	QuoteID              *int32
+	ListPriceCents       int32
+	TierDiscountCents    int32
+	PriceTierID          *int32
+	ShipTo               *string
 }
@@ -27,4 +27,13 @@ type Projects struct {
 	Version                   int32
 	InstallationKit           bool
 	InstallationKitPriceCents *int32
+	ParentProjectID           *int32
+	TaxCents                  *int32
+	TaxRatePercent            *float64
+	TaxJurisdiction           *string
+	TaxExemptCertificate      *string
+	Rush                      bool
+	RushSurchargeCents        *int32
+	RequiredBy                *time.Time
+	ShipToAddressID           *int32
 }
@@ -21,6 +21,11 @@ type dealershipTokensTable struct {
 	DealershipUserID postgres.ColumnInteger
 	Expiry           postgres.ColumnTimestampz
 	Scope            postgres.ColumnString
+	SessionID        postgres.ColumnString
+	UserAgent        postgres.ColumnString
+	IP               postgres.ColumnString
+	CreatedAt        postgres.ColumnTimestampz
+	RotatedAt        postgres.ColumnTimestampz
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -66,9 +71,14 @@ func newDealershipTokensTableImpl(schemaName, tableName, alias string) dealershi
 		DealershipUserIDColumn = postgres.IntegerColumn("dealership_user_id")
 		ExpiryColumn           = postgres.TimestampzColumn("expiry")
 		ScopeColumn            = postgres.StringColumn("scope")
-		allColumns             = postgres.ColumnList{HashColumn, DealershipUserIDColumn, ExpiryColumn, ScopeColumn}
-		mutableColumns         = postgres.ColumnList{DealershipUserIDColumn, ExpiryColumn, ScopeColumn}
-		defaultColumns         = postgres.ColumnList{}
+		SessionIDColumn        = postgres.StringColumn("session_id")
+		UserAgentColumn        = postgres.StringColumn("user_agent")
+		IPColumn               = postgres.StringColumn("ip")
+		CreatedAtColumn        = postgres.TimestampzColumn("created_at")
+		RotatedAtColumn        = postgres.TimestampzColumn("rotated_at")
+		allColumns             = postgres.ColumnList{HashColumn, DealershipUserIDColumn, ExpiryColumn, ScopeColumn, SessionIDColumn, UserAgentColumn, IPColumn, CreatedAtColumn, RotatedAtColumn}
+		mutableColumns         = postgres.ColumnList{DealershipUserIDColumn, ExpiryColumn, ScopeColumn, SessionIDColumn, UserAgentColumn, IPColumn, CreatedAtColumn, RotatedAtColumn}
+		defaultColumns         = postgres.ColumnList{UserAgentColumn, IPColumn, CreatedAtColumn}
 	)
 
 	return dealershipTokensTable{
@@ -79,6 +89,11 @@ func newDealershipTokensTableImpl(schemaName, tableName, alias string) dealershi
 		DealershipUserID: DealershipUserIDColumn,
 		Expiry:           ExpiryColumn,
 		Scope:            ScopeColumn,
+		SessionID:        SessionIDColumn,
+		UserAgent:        UserAgentColumn,
+		IP:               IPColumn,
+		CreatedAt:        CreatedAtColumn,
+		RotatedAt:        RotatedAtColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
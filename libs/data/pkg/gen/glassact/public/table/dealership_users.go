@@ -17,17 +17,18 @@ type dealershipUsersTable struct {
 	postgres.Table
 
 	// Columns
-	ID           postgres.ColumnInteger
-	UUID         postgres.ColumnString
-	DealershipID postgres.ColumnInteger
-	Name         postgres.ColumnString
-	Email        postgres.ColumnString
-	Avatar       postgres.ColumnString
-	Role         postgres.ColumnString
-	IsActive     postgres.ColumnBool
-	CreatedAt    postgres.ColumnTimestampz
-	UpdatedAt    postgres.ColumnTimestampz
-	Version      postgres.ColumnInteger
+	ID                postgres.ColumnInteger
+	UUID              postgres.ColumnString
+	DealershipID      postgres.ColumnInteger
+	Name              postgres.ColumnString
+	Email             postgres.ColumnString
+	Avatar            postgres.ColumnString
+	Role              postgres.ColumnString
+	IsActive          postgres.ColumnBool
+	CreatedAt         postgres.ColumnTimestampz
+	UpdatedAt         postgres.ColumnTimestampz
+	Version           postgres.ColumnInteger
+	DealershipGroupID postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -69,37 +70,39 @@ func newDealershipUsersTable(schemaName, tableName, alias string) *DealershipUse
 
 func newDealershipUsersTableImpl(schemaName, tableName, alias string) dealershipUsersTable {
 	var (
-		IDColumn           = postgres.IntegerColumn("id")
-		UUIDColumn         = postgres.StringColumn("uuid")
-		DealershipIDColumn = postgres.IntegerColumn("dealership_id")
-		NameColumn         = postgres.StringColumn("name")
-		EmailColumn        = postgres.StringColumn("email")
-		AvatarColumn       = postgres.StringColumn("avatar")
-		RoleColumn         = postgres.StringColumn("role")
-		IsActiveColumn     = postgres.BoolColumn("is_active")
-		CreatedAtColumn    = postgres.TimestampzColumn("created_at")
-		UpdatedAtColumn    = postgres.TimestampzColumn("updated_at")
-		VersionColumn      = postgres.IntegerColumn("version")
-		allColumns         = postgres.ColumnList{IDColumn, UUIDColumn, DealershipIDColumn, NameColumn, EmailColumn, AvatarColumn, RoleColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
-		mutableColumns     = postgres.ColumnList{UUIDColumn, DealershipIDColumn, NameColumn, EmailColumn, AvatarColumn, RoleColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
-		defaultColumns     = postgres.ColumnList{IDColumn, UUIDColumn, AvatarColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
+		IDColumn                = postgres.IntegerColumn("id")
+		UUIDColumn              = postgres.StringColumn("uuid")
+		DealershipIDColumn      = postgres.IntegerColumn("dealership_id")
+		NameColumn              = postgres.StringColumn("name")
+		EmailColumn             = postgres.StringColumn("email")
+		AvatarColumn            = postgres.StringColumn("avatar")
+		RoleColumn              = postgres.StringColumn("role")
+		IsActiveColumn          = postgres.BoolColumn("is_active")
+		CreatedAtColumn         = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn         = postgres.TimestampzColumn("updated_at")
+		VersionColumn           = postgres.IntegerColumn("version")
+		DealershipGroupIDColumn = postgres.IntegerColumn("dealership_group_id")
+		allColumns              = postgres.ColumnList{IDColumn, UUIDColumn, DealershipIDColumn, NameColumn, EmailColumn, AvatarColumn, RoleColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, DealershipGroupIDColumn}
+		mutableColumns          = postgres.ColumnList{UUIDColumn, DealershipIDColumn, NameColumn, EmailColumn, AvatarColumn, RoleColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, DealershipGroupIDColumn}
+		defaultColumns          = postgres.ColumnList{IDColumn, UUIDColumn, AvatarColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
 	)
 
 	return dealershipUsersTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:           IDColumn,
-		UUID:         UUIDColumn,
-		DealershipID: DealershipIDColumn,
-		Name:         NameColumn,
-		Email:        EmailColumn,
-		Avatar:       AvatarColumn,
-		Role:         RoleColumn,
-		IsActive:     IsActiveColumn,
-		CreatedAt:    CreatedAtColumn,
-		UpdatedAt:    UpdatedAtColumn,
-		Version:      VersionColumn,
+		ID:                IDColumn,
+		UUID:              UUIDColumn,
+		DealershipID:      DealershipIDColumn,
+		Name:              NameColumn,
+		Email:             EmailColumn,
+		Avatar:            AvatarColumn,
+		Role:              RoleColumn,
+		IsActive:          IsActiveColumn,
+		CreatedAt:         CreatedAtColumn,
+		UpdatedAt:         UpdatedAtColumn,
+		Version:           VersionColumn,
+		DealershipGroupID: DealershipGroupIDColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -17,22 +17,26 @@ type dealershipsTable struct {
 	postgres.Table
 
 	// Columns
-	ID                  postgres.ColumnInteger
-	UUID                postgres.ColumnString
-	Name                postgres.ColumnString
-	Street              postgres.ColumnString
-	StreetExt           postgres.ColumnString
-	City                postgres.ColumnString
-	State               postgres.ColumnString
-	PostalCode          postgres.ColumnString
-	Country             postgres.ColumnString
-	Location            postgres.ColumnString
-	CreatedAt           postgres.ColumnTimestampz
-	UpdatedAt           postgres.ColumnTimestampz
-	Version             postgres.ColumnInteger
-	PaymentTiming       postgres.ColumnString
-	SandblastFileFormat postgres.ColumnString
-	Phone               postgres.ColumnString
+	ID                   postgres.ColumnInteger
+	UUID                 postgres.ColumnString
+	Name                 postgres.ColumnString
+	Street               postgres.ColumnString
+	StreetExt            postgres.ColumnString
+	City                 postgres.ColumnString
+	State                postgres.ColumnString
+	PostalCode           postgres.ColumnString
+	Country              postgres.ColumnString
+	Location             postgres.ColumnString
+	CreatedAt            postgres.ColumnTimestampz
+	UpdatedAt            postgres.ColumnTimestampz
+	Version              postgres.ColumnInteger
+	PaymentTiming        postgres.ColumnString
+	SandblastFileFormat  postgres.ColumnString
+	Phone                postgres.ColumnString
+	TaxExempt            postgres.ColumnBool
+	TaxExemptCertificate postgres.ColumnString
+	TaxExemptExpiresAt   postgres.ColumnDate
+	DealershipGroupID    postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -74,47 +78,55 @@ func newDealershipsTable(schemaName, tableName, alias string) *DealershipsTable
 
 func newDealershipsTableImpl(schemaName, tableName, alias string) dealershipsTable {
 	var (
-		IDColumn                  = postgres.IntegerColumn("id")
-		UUIDColumn                = postgres.StringColumn("uuid")
-		NameColumn                = postgres.StringColumn("name")
-		StreetColumn              = postgres.StringColumn("street")
-		StreetExtColumn           = postgres.StringColumn("street_ext")
-		CityColumn                = postgres.StringColumn("city")
-		StateColumn               = postgres.StringColumn("state")
-		PostalCodeColumn          = postgres.StringColumn("postal_code")
-		CountryColumn             = postgres.StringColumn("country")
-		LocationColumn            = postgres.StringColumn("location")
-		CreatedAtColumn           = postgres.TimestampzColumn("created_at")
-		UpdatedAtColumn           = postgres.TimestampzColumn("updated_at")
-		VersionColumn             = postgres.IntegerColumn("version")
-		PaymentTimingColumn       = postgres.StringColumn("payment_timing")
-		SandblastFileFormatColumn = postgres.StringColumn("sandblast_file_format")
-		PhoneColumn               = postgres.StringColumn("phone")
-		allColumns                = postgres.ColumnList{IDColumn, UUIDColumn, NameColumn, StreetColumn, StreetExtColumn, CityColumn, StateColumn, PostalCodeColumn, CountryColumn, LocationColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, PaymentTimingColumn, SandblastFileFormatColumn, PhoneColumn}
-		mutableColumns            = postgres.ColumnList{UUIDColumn, NameColumn, StreetColumn, StreetExtColumn, CityColumn, StateColumn, PostalCodeColumn, CountryColumn, LocationColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, PaymentTimingColumn, SandblastFileFormatColumn, PhoneColumn}
-		defaultColumns            = postgres.ColumnList{IDColumn, UUIDColumn, StreetExtColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, PaymentTimingColumn, SandblastFileFormatColumn, PhoneColumn}
+		IDColumn                   = postgres.IntegerColumn("id")
+		UUIDColumn                 = postgres.StringColumn("uuid")
+		NameColumn                 = postgres.StringColumn("name")
+		StreetColumn               = postgres.StringColumn("street")
+		StreetExtColumn            = postgres.StringColumn("street_ext")
+		CityColumn                 = postgres.StringColumn("city")
+		StateColumn                = postgres.StringColumn("state")
+		PostalCodeColumn           = postgres.StringColumn("postal_code")
+		CountryColumn              = postgres.StringColumn("country")
+		LocationColumn             = postgres.StringColumn("location")
+		CreatedAtColumn            = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn            = postgres.TimestampzColumn("updated_at")
+		VersionColumn              = postgres.IntegerColumn("version")
+		PaymentTimingColumn        = postgres.StringColumn("payment_timing")
+		SandblastFileFormatColumn  = postgres.StringColumn("sandblast_file_format")
+		PhoneColumn                = postgres.StringColumn("phone")
+		TaxExemptColumn            = postgres.BoolColumn("tax_exempt")
+		TaxExemptCertificateColumn = postgres.StringColumn("tax_exempt_certificate")
+		TaxExemptExpiresAtColumn   = postgres.DateColumn("tax_exempt_expires_at")
+		DealershipGroupIDColumn    = postgres.IntegerColumn("dealership_group_id")
+		allColumns                 = postgres.ColumnList{IDColumn, UUIDColumn, NameColumn, StreetColumn, StreetExtColumn, CityColumn, StateColumn, PostalCodeColumn, CountryColumn, LocationColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, PaymentTimingColumn, SandblastFileFormatColumn, PhoneColumn, TaxExemptColumn, TaxExemptCertificateColumn, TaxExemptExpiresAtColumn, DealershipGroupIDColumn}
+		mutableColumns             = postgres.ColumnList{UUIDColumn, NameColumn, StreetColumn, StreetExtColumn, CityColumn, StateColumn, PostalCodeColumn, CountryColumn, LocationColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, PaymentTimingColumn, SandblastFileFormatColumn, PhoneColumn, TaxExemptColumn, TaxExemptCertificateColumn, TaxExemptExpiresAtColumn, DealershipGroupIDColumn}
+		defaultColumns             = postgres.ColumnList{IDColumn, UUIDColumn, StreetExtColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, PaymentTimingColumn, SandblastFileFormatColumn, PhoneColumn, TaxExemptColumn}
 	)
 
 	return dealershipsTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:                  IDColumn,
-		UUID:                UUIDColumn,
-		Name:                NameColumn,
-		Street:              StreetColumn,
-		StreetExt:           StreetExtColumn,
-		City:                CityColumn,
-		State:               StateColumn,
-		PostalCode:          PostalCodeColumn,
-		Country:             CountryColumn,
-		Location:            LocationColumn,
-		CreatedAt:           CreatedAtColumn,
-		UpdatedAt:           UpdatedAtColumn,
-		Version:             VersionColumn,
-		PaymentTiming:       PaymentTimingColumn,
-		SandblastFileFormat: SandblastFileFormatColumn,
-		Phone:               PhoneColumn,
+		ID:                   IDColumn,
+		UUID:                 UUIDColumn,
+		Name:                 NameColumn,
+		Street:               StreetColumn,
+		StreetExt:            StreetExtColumn,
+		City:                 CityColumn,
+		State:                StateColumn,
+		PostalCode:           PostalCodeColumn,
+		Country:              CountryColumn,
+		Location:             LocationColumn,
+		CreatedAt:            CreatedAtColumn,
+		UpdatedAt:            UpdatedAtColumn,
+		Version:              VersionColumn,
+		PaymentTiming:        PaymentTimingColumn,
+		SandblastFileFormat:  SandblastFileFormatColumn,
+		Phone:                PhoneColumn,
+		TaxExempt:            TaxExemptColumn,
+		TaxExemptCertificate: TaxExemptCertificateColumn,
+		TaxExemptExpiresAt:   TaxExemptExpiresAtColumn,
+		DealershipGroupID:    DealershipGroupIDColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -17,16 +17,21 @@ type glassColorsTable struct {
 	postgres.Table
 
 	// Columns
-	ID        postgres.ColumnInteger
-	UUID      postgres.ColumnString
-	Name      postgres.ColumnString
-	Hex       postgres.ColumnString
-	Family    postgres.ColumnString
-	SortOrder postgres.ColumnInteger
-	IsActive  postgres.ColumnBool
-	CreatedAt postgres.ColumnTimestampz
-	UpdatedAt postgres.ColumnTimestampz
-	Version   postgres.ColumnInteger
+	ID               postgres.ColumnInteger
+	UUID             postgres.ColumnString
+	Name             postgres.ColumnString
+	Hex              postgres.ColumnString
+	Family           postgres.ColumnString
+	SortOrder        postgres.ColumnInteger
+	IsActive         postgres.ColumnBool
+	CreatedAt        postgres.ColumnTimestampz
+	UpdatedAt        postgres.ColumnTimestampz
+	Version          postgres.ColumnInteger
+	Finish           postgres.ColumnString
+	Texture          postgres.ColumnString
+	Opacity          postgres.ColumnFloat
+	SwatchImageURL   postgres.ColumnString
+	UnavailableUntil postgres.ColumnTimestampz
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -68,35 +73,45 @@ func newGlassColorsTable(schemaName, tableName, alias string) *GlassColorsTable
 
 func newGlassColorsTableImpl(schemaName, tableName, alias string) glassColorsTable {
 	var (
-		IDColumn        = postgres.IntegerColumn("id")
-		UUIDColumn      = postgres.StringColumn("uuid")
-		NameColumn      = postgres.StringColumn("name")
-		HexColumn       = postgres.StringColumn("hex")
-		FamilyColumn    = postgres.StringColumn("family")
-		SortOrderColumn = postgres.IntegerColumn("sort_order")
-		IsActiveColumn  = postgres.BoolColumn("is_active")
-		CreatedAtColumn = postgres.TimestampzColumn("created_at")
-		UpdatedAtColumn = postgres.TimestampzColumn("updated_at")
-		VersionColumn   = postgres.IntegerColumn("version")
-		allColumns      = postgres.ColumnList{IDColumn, UUIDColumn, NameColumn, HexColumn, FamilyColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
-		mutableColumns  = postgres.ColumnList{UUIDColumn, NameColumn, HexColumn, FamilyColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
-		defaultColumns  = postgres.ColumnList{IDColumn, UUIDColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
+		IDColumn               = postgres.IntegerColumn("id")
+		UUIDColumn             = postgres.StringColumn("uuid")
+		NameColumn             = postgres.StringColumn("name")
+		HexColumn              = postgres.StringColumn("hex")
+		FamilyColumn           = postgres.StringColumn("family")
+		SortOrderColumn        = postgres.IntegerColumn("sort_order")
+		IsActiveColumn         = postgres.BoolColumn("is_active")
+		CreatedAtColumn        = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn        = postgres.TimestampzColumn("updated_at")
+		VersionColumn          = postgres.IntegerColumn("version")
+		FinishColumn           = postgres.StringColumn("finish")
+		TextureColumn          = postgres.StringColumn("texture")
+		OpacityColumn          = postgres.FloatColumn("opacity")
+		SwatchImageURLColumn   = postgres.StringColumn("swatch_image_url")
+		UnavailableUntilColumn = postgres.TimestampzColumn("unavailable_until")
+		allColumns             = postgres.ColumnList{IDColumn, UUIDColumn, NameColumn, HexColumn, FamilyColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, FinishColumn, TextureColumn, OpacityColumn, SwatchImageURLColumn, UnavailableUntilColumn}
+		mutableColumns         = postgres.ColumnList{UUIDColumn, NameColumn, HexColumn, FamilyColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, FinishColumn, TextureColumn, OpacityColumn, SwatchImageURLColumn, UnavailableUntilColumn}
+		defaultColumns         = postgres.ColumnList{IDColumn, UUIDColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, FinishColumn, TextureColumn, OpacityColumn}
 	)
 
 	return glassColorsTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:        IDColumn,
-		UUID:      UUIDColumn,
-		Name:      NameColumn,
-		Hex:       HexColumn,
-		Family:    FamilyColumn,
-		SortOrder: SortOrderColumn,
-		IsActive:  IsActiveColumn,
-		CreatedAt: CreatedAtColumn,
-		UpdatedAt: UpdatedAtColumn,
-		Version:   VersionColumn,
+		ID:               IDColumn,
+		UUID:             UUIDColumn,
+		Name:             NameColumn,
+		Hex:              HexColumn,
+		Family:           FamilyColumn,
+		SortOrder:        SortOrderColumn,
+		IsActive:         IsActiveColumn,
+		CreatedAt:        CreatedAtColumn,
+		UpdatedAt:        UpdatedAtColumn,
+		Version:          VersionColumn,
+		Finish:           FinishColumn,
+		Texture:          TextureColumn,
+		Opacity:          OpacityColumn,
+		SwatchImageURL:   SwatchImageURLColumn,
+		UnavailableUntil: UnavailableUntilColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -17,15 +17,16 @@ type groutsTable struct {
 	postgres.Table
 
 	// Columns
-	ID        postgres.ColumnInteger
-	UUID      postgres.ColumnString
-	Name      postgres.ColumnString
-	Hex       postgres.ColumnString
-	SortOrder postgres.ColumnInteger
-	IsActive  postgres.ColumnBool
-	CreatedAt postgres.ColumnTimestampz
-	UpdatedAt postgres.ColumnTimestampz
-	Version   postgres.ColumnInteger
+	ID               postgres.ColumnInteger
+	UUID             postgres.ColumnString
+	Name             postgres.ColumnString
+	Hex              postgres.ColumnString
+	SortOrder        postgres.ColumnInteger
+	IsActive         postgres.ColumnBool
+	CreatedAt        postgres.ColumnTimestampz
+	UpdatedAt        postgres.ColumnTimestampz
+	Version          postgres.ColumnInteger
+	UnavailableUntil postgres.ColumnTimestampz
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -67,33 +68,35 @@ func newGroutsTable(schemaName, tableName, alias string) *GroutsTable {
 
 func newGroutsTableImpl(schemaName, tableName, alias string) groutsTable {
 	var (
-		IDColumn        = postgres.IntegerColumn("id")
-		UUIDColumn      = postgres.StringColumn("uuid")
-		NameColumn      = postgres.StringColumn("name")
-		HexColumn       = postgres.StringColumn("hex")
-		SortOrderColumn = postgres.IntegerColumn("sort_order")
-		IsActiveColumn  = postgres.BoolColumn("is_active")
-		CreatedAtColumn = postgres.TimestampzColumn("created_at")
-		UpdatedAtColumn = postgres.TimestampzColumn("updated_at")
-		VersionColumn   = postgres.IntegerColumn("version")
-		allColumns      = postgres.ColumnList{IDColumn, UUIDColumn, NameColumn, HexColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
-		mutableColumns  = postgres.ColumnList{UUIDColumn, NameColumn, HexColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
-		defaultColumns  = postgres.ColumnList{IDColumn, UUIDColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
+		IDColumn               = postgres.IntegerColumn("id")
+		UUIDColumn             = postgres.StringColumn("uuid")
+		NameColumn             = postgres.StringColumn("name")
+		HexColumn              = postgres.StringColumn("hex")
+		SortOrderColumn        = postgres.IntegerColumn("sort_order")
+		IsActiveColumn         = postgres.BoolColumn("is_active")
+		CreatedAtColumn        = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn        = postgres.TimestampzColumn("updated_at")
+		VersionColumn          = postgres.IntegerColumn("version")
+		UnavailableUntilColumn = postgres.TimestampzColumn("unavailable_until")
+		allColumns             = postgres.ColumnList{IDColumn, UUIDColumn, NameColumn, HexColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, UnavailableUntilColumn}
+		mutableColumns         = postgres.ColumnList{UUIDColumn, NameColumn, HexColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, UnavailableUntilColumn}
+		defaultColumns         = postgres.ColumnList{IDColumn, UUIDColumn, SortOrderColumn, IsActiveColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
 	)
 
 	return groutsTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:        IDColumn,
-		UUID:      UUIDColumn,
-		Name:      NameColumn,
-		Hex:       HexColumn,
-		SortOrder: SortOrderColumn,
-		IsActive:  IsActiveColumn,
-		CreatedAt: CreatedAtColumn,
-		UpdatedAt: UpdatedAtColumn,
-		Version:   VersionColumn,
+		ID:               IDColumn,
+		UUID:             UUIDColumn,
+		Name:             NameColumn,
+		Hex:              HexColumn,
+		SortOrder:        SortOrderColumn,
+		IsActive:         IsActiveColumn,
+		CreatedAt:        CreatedAtColumn,
+		UpdatedAt:        UpdatedAtColumn,
+		Version:          VersionColumn,
+		UnavailableUntil: UnavailableUntilColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -27,6 +27,7 @@ type inlayMilestonesTable struct {
 	CreatedAt   postgres.ColumnTimestampz
 	UpdatedAt   postgres.ColumnTimestampz
 	Version     postgres.ColumnInteger
+	Cycle       postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -78,9 +79,10 @@ func newInlayMilestonesTableImpl(schemaName, tableName, alias string) inlayMiles
 		CreatedAtColumn   = postgres.TimestampzColumn("created_at")
 		UpdatedAtColumn   = postgres.TimestampzColumn("updated_at")
 		VersionColumn     = postgres.IntegerColumn("version")
-		allColumns        = postgres.ColumnList{IDColumn, UUIDColumn, InlayIDColumn, StepColumn, EventTypeColumn, PerformedByColumn, EventTimeColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
-		mutableColumns    = postgres.ColumnList{UUIDColumn, InlayIDColumn, StepColumn, EventTypeColumn, PerformedByColumn, EventTimeColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
-		defaultColumns    = postgres.ColumnList{IDColumn, UUIDColumn, EventTimeColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
+		CycleColumn       = postgres.IntegerColumn("cycle")
+		allColumns        = postgres.ColumnList{IDColumn, UUIDColumn, InlayIDColumn, StepColumn, EventTypeColumn, PerformedByColumn, EventTimeColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, CycleColumn}
+		mutableColumns    = postgres.ColumnList{UUIDColumn, InlayIDColumn, StepColumn, EventTypeColumn, PerformedByColumn, EventTimeColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, CycleColumn}
+		defaultColumns    = postgres.ColumnList{IDColumn, UUIDColumn, EventTimeColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, CycleColumn}
 	)
 
 	return inlayMilestonesTable{
@@ -97,6 +99,7 @@ func newInlayMilestonesTableImpl(schemaName, tableName, alias string) inlayMiles
 		CreatedAt:   CreatedAtColumn,
 		UpdatedAt:   UpdatedAtColumn,
 		Version:     VersionColumn,
+		Cycle:       CycleColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -21,6 +21,11 @@ type internalTokensTable struct {
 	InternalUserID postgres.ColumnInteger
 	Expiry         postgres.ColumnTimestampz
 	Scope          postgres.ColumnString
+	SessionID      postgres.ColumnString
+	UserAgent      postgres.ColumnString
+	IP             postgres.ColumnString
+	CreatedAt      postgres.ColumnTimestampz
+	RotatedAt      postgres.ColumnTimestampz
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -66,9 +71,14 @@ func newInternalTokensTableImpl(schemaName, tableName, alias string) internalTok
 		InternalUserIDColumn = postgres.IntegerColumn("internal_user_id")
 		ExpiryColumn         = postgres.TimestampzColumn("expiry")
 		ScopeColumn          = postgres.StringColumn("scope")
-		allColumns           = postgres.ColumnList{HashColumn, InternalUserIDColumn, ExpiryColumn, ScopeColumn}
-		mutableColumns       = postgres.ColumnList{InternalUserIDColumn, ExpiryColumn, ScopeColumn}
-		defaultColumns       = postgres.ColumnList{}
+		SessionIDColumn      = postgres.StringColumn("session_id")
+		UserAgentColumn      = postgres.StringColumn("user_agent")
+		IPColumn             = postgres.StringColumn("ip")
+		CreatedAtColumn      = postgres.TimestampzColumn("created_at")
+		RotatedAtColumn      = postgres.TimestampzColumn("rotated_at")
+		allColumns           = postgres.ColumnList{HashColumn, InternalUserIDColumn, ExpiryColumn, ScopeColumn, SessionIDColumn, UserAgentColumn, IPColumn, CreatedAtColumn, RotatedAtColumn}
+		mutableColumns       = postgres.ColumnList{InternalUserIDColumn, ExpiryColumn, ScopeColumn, SessionIDColumn, UserAgentColumn, IPColumn, CreatedAtColumn, RotatedAtColumn}
+		defaultColumns       = postgres.ColumnList{UserAgentColumn, IPColumn, CreatedAtColumn}
 	)
 
 	return internalTokensTable{
@@ -79,6 +89,11 @@ func newInternalTokensTableImpl(schemaName, tableName, alias string) internalTok
 		InternalUserID: InternalUserIDColumn,
 		Expiry:         ExpiryColumn,
 		Scope:          ScopeColumn,
+		SessionID:      SessionIDColumn,
+		UserAgent:      UserAgentColumn,
+		IP:             IPColumn,
+		CreatedAt:      CreatedAtColumn,
+		RotatedAt:      RotatedAtColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -17,15 +17,18 @@ type invoicesTable struct {
 	postgres.Table
 
 	// Columns
-	ID         postgres.ColumnInteger
-	UUID       postgres.ColumnString
-	ProjectID  postgres.ColumnInteger
-	InvoiceURL postgres.ColumnString
-	Status     postgres.ColumnString
-	PaidAt     postgres.ColumnTimestampz
-	CreatedAt  postgres.ColumnTimestampz
-	UpdatedAt  postgres.ColumnTimestampz
-	Version    postgres.ColumnInteger
+	ID            postgres.ColumnInteger
+	UUID          postgres.ColumnString
+	ProjectID     postgres.ColumnInteger
+	InvoiceURL    postgres.ColumnString
+	Status        postgres.ColumnString
+	PaidAt        postgres.ColumnTimestampz
+	CreatedAt     postgres.ColumnTimestampz
+	UpdatedAt     postgres.ColumnTimestampz
+	Version       postgres.ColumnInteger
+	SubtotalCents postgres.ColumnInteger
+	TaxCents      postgres.ColumnInteger
+	TotalCents    postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -67,33 +70,39 @@ func newInvoicesTable(schemaName, tableName, alias string) *InvoicesTable {
 
 func newInvoicesTableImpl(schemaName, tableName, alias string) invoicesTable {
 	var (
-		IDColumn         = postgres.IntegerColumn("id")
-		UUIDColumn       = postgres.StringColumn("uuid")
-		ProjectIDColumn  = postgres.IntegerColumn("project_id")
-		InvoiceURLColumn = postgres.StringColumn("invoice_url")
-		StatusColumn     = postgres.StringColumn("status")
-		PaidAtColumn     = postgres.TimestampzColumn("paid_at")
-		CreatedAtColumn  = postgres.TimestampzColumn("created_at")
-		UpdatedAtColumn  = postgres.TimestampzColumn("updated_at")
-		VersionColumn    = postgres.IntegerColumn("version")
-		allColumns       = postgres.ColumnList{IDColumn, UUIDColumn, ProjectIDColumn, InvoiceURLColumn, StatusColumn, PaidAtColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
-		mutableColumns   = postgres.ColumnList{UUIDColumn, ProjectIDColumn, InvoiceURLColumn, StatusColumn, PaidAtColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
-		defaultColumns   = postgres.ColumnList{IDColumn, UUIDColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn}
+		IDColumn            = postgres.IntegerColumn("id")
+		UUIDColumn          = postgres.StringColumn("uuid")
+		ProjectIDColumn     = postgres.IntegerColumn("project_id")
+		InvoiceURLColumn    = postgres.StringColumn("invoice_url")
+		StatusColumn        = postgres.StringColumn("status")
+		PaidAtColumn        = postgres.TimestampzColumn("paid_at")
+		CreatedAtColumn     = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn     = postgres.TimestampzColumn("updated_at")
+		VersionColumn       = postgres.IntegerColumn("version")
+		SubtotalCentsColumn = postgres.IntegerColumn("subtotal_cents")
+		TaxCentsColumn      = postgres.IntegerColumn("tax_cents")
+		TotalCentsColumn    = postgres.IntegerColumn("total_cents")
+		allColumns          = postgres.ColumnList{IDColumn, UUIDColumn, ProjectIDColumn, InvoiceURLColumn, StatusColumn, PaidAtColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, SubtotalCentsColumn, TaxCentsColumn, TotalCentsColumn}
+		mutableColumns      = postgres.ColumnList{UUIDColumn, ProjectIDColumn, InvoiceURLColumn, StatusColumn, PaidAtColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, SubtotalCentsColumn, TaxCentsColumn, TotalCentsColumn}
+		defaultColumns      = postgres.ColumnList{IDColumn, UUIDColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, SubtotalCentsColumn, TaxCentsColumn, TotalCentsColumn}
 	)
 
 	return invoicesTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:         IDColumn,
-		UUID:       UUIDColumn,
-		ProjectID:  ProjectIDColumn,
-		InvoiceURL: InvoiceURLColumn,
-		Status:     StatusColumn,
-		PaidAt:     PaidAtColumn,
-		CreatedAt:  CreatedAtColumn,
-		UpdatedAt:  UpdatedAtColumn,
-		Version:    VersionColumn,
+		ID:            IDColumn,
+		UUID:          UUIDColumn,
+		ProjectID:     ProjectIDColumn,
+		InvoiceURL:    InvoiceURLColumn,
+		Status:        StatusColumn,
+		PaidAt:        PaidAtColumn,
+		CreatedAt:     CreatedAtColumn,
+		UpdatedAt:     UpdatedAtColumn,
+		Version:       VersionColumn,
+		SubtotalCents: SubtotalCentsColumn,
+		TaxCents:      TaxCentsColumn,
+		TotalCents:    TotalCentsColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
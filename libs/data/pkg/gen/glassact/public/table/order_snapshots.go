@@ -29,6 +29,12 @@ type orderSnapshotsTable struct {
 	Width                postgres.ColumnFloat
 	Height               postgres.ColumnFloat
 	CreatedAt            postgres.ColumnTimestampz
+	RemakeID             postgres.ColumnInteger
+	QuoteID              postgres.ColumnInteger
+	ListPriceCents       postgres.ColumnInteger
+	TierDiscountCents    postgres.ColumnInteger
+	PriceTierID          postgres.ColumnInteger
+	ShipTo               postgres.ColumnString
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -82,9 +88,15 @@ func newOrderSnapshotsTableImpl(schemaName, tableName, alias string) orderSnapsh
 		WidthColumn                = postgres.FloatColumn("width")
 		HeightColumn               = postgres.FloatColumn("height")
 		CreatedAtColumn            = postgres.TimestampzColumn("created_at")
-		allColumns                 = postgres.ColumnList{IDColumn, UUIDColumn, ProjectIDColumn, InlayIDColumn, ProofIDColumn, PriceGroupIDColumn, PriceCentsColumn, PriceAdjustmentTypeColumn, PriceAdjustmentValueColumn, WidthColumn, HeightColumn, CreatedAtColumn}
-		mutableColumns             = postgres.ColumnList{UUIDColumn, ProjectIDColumn, InlayIDColumn, ProofIDColumn, PriceGroupIDColumn, PriceCentsColumn, PriceAdjustmentTypeColumn, PriceAdjustmentValueColumn, WidthColumn, HeightColumn, CreatedAtColumn}
-		defaultColumns             = postgres.ColumnList{IDColumn, UUIDColumn, PriceAdjustmentTypeColumn, PriceAdjustmentValueColumn, CreatedAtColumn}
+		RemakeIDColumn             = postgres.IntegerColumn("remake_id")
+		QuoteIDColumn              = postgres.IntegerColumn("quote_id")
+		ListPriceCentsColumn       = postgres.IntegerColumn("list_price_cents")
+		TierDiscountCentsColumn    = postgres.IntegerColumn("tier_discount_cents")
+		PriceTierIDColumn          = postgres.IntegerColumn("price_tier_id")
+		ShipToColumn               = postgres.StringColumn("ship_to")
+		allColumns                 = postgres.ColumnList{IDColumn, UUIDColumn, ProjectIDColumn, InlayIDColumn, ProofIDColumn, PriceGroupIDColumn, PriceCentsColumn, PriceAdjustmentTypeColumn, PriceAdjustmentValueColumn, WidthColumn, HeightColumn, CreatedAtColumn, RemakeIDColumn, QuoteIDColumn, ListPriceCentsColumn, TierDiscountCentsColumn, PriceTierIDColumn, ShipToColumn}
+		mutableColumns             = postgres.ColumnList{UUIDColumn, ProjectIDColumn, InlayIDColumn, ProofIDColumn, PriceGroupIDColumn, PriceCentsColumn, PriceAdjustmentTypeColumn, PriceAdjustmentValueColumn, WidthColumn, HeightColumn, CreatedAtColumn, RemakeIDColumn, QuoteIDColumn, ListPriceCentsColumn, TierDiscountCentsColumn, PriceTierIDColumn, ShipToColumn}
+		defaultColumns             = postgres.ColumnList{IDColumn, UUIDColumn, PriceAdjustmentTypeColumn, PriceAdjustmentValueColumn, CreatedAtColumn, TierDiscountCentsColumn}
 	)
 
 	return orderSnapshotsTable{
@@ -103,6 +115,12 @@ func newOrderSnapshotsTableImpl(schemaName, tableName, alias string) orderSnapsh
 		Width:                WidthColumn,
 		Height:               HeightColumn,
 		CreatedAt:            CreatedAtColumn,
+		RemakeID:             RemakeIDColumn,
+		QuoteID:              QuoteIDColumn,
+		ListPriceCents:       ListPriceCentsColumn,
+		TierDiscountCents:    TierDiscountCentsColumn,
+		PriceTierID:          PriceTierIDColumn,
+		ShipTo:               ShipToColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -31,6 +31,15 @@ type projectsTable struct {
 	Version                   postgres.ColumnInteger
 	InstallationKit           postgres.ColumnBool
 	InstallationKitPriceCents postgres.ColumnInteger
+	ParentProjectID           postgres.ColumnInteger
+	TaxCents                  postgres.ColumnInteger
+	TaxRatePercent            postgres.ColumnFloat
+	TaxJurisdiction           postgres.ColumnString
+	TaxExemptCertificate      postgres.ColumnString
+	Rush                      postgres.ColumnBool
+	RushSurchargeCents        postgres.ColumnInteger
+	RequiredBy                postgres.ColumnDate
+	ShipToAddressID           postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -86,9 +95,18 @@ func newProjectsTableImpl(schemaName, tableName, alias string) projectsTable {
 		VersionColumn                   = postgres.IntegerColumn("version")
 		InstallationKitColumn           = postgres.BoolColumn("installation_kit")
 		InstallationKitPriceCentsColumn = postgres.IntegerColumn("installation_kit_price_cents")
-		allColumns                      = postgres.ColumnList{IDColumn, UUIDColumn, DealershipIDColumn, NameColumn, InternalReferenceColumn, StatusColumn, TrackingNumberColumn, OrderedAtColumn, OrderedByColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, InstallationKitColumn, InstallationKitPriceCentsColumn}
-		mutableColumns                  = postgres.ColumnList{UUIDColumn, DealershipIDColumn, NameColumn, InternalReferenceColumn, StatusColumn, TrackingNumberColumn, OrderedAtColumn, OrderedByColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, InstallationKitColumn, InstallationKitPriceCentsColumn}
-		defaultColumns                  = postgres.ColumnList{IDColumn, UUIDColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, InstallationKitColumn}
+		ParentProjectIDColumn           = postgres.IntegerColumn("parent_project_id")
+		TaxCentsColumn                  = postgres.IntegerColumn("tax_cents")
+		TaxRatePercentColumn            = postgres.FloatColumn("tax_rate_percent")
+		TaxJurisdictionColumn           = postgres.StringColumn("tax_jurisdiction")
+		TaxExemptCertificateColumn      = postgres.StringColumn("tax_exempt_certificate")
+		RushColumn                      = postgres.BoolColumn("rush")
+		RushSurchargeCentsColumn        = postgres.IntegerColumn("rush_surcharge_cents")
+		RequiredByColumn                = postgres.DateColumn("required_by")
+		ShipToAddressIDColumn           = postgres.IntegerColumn("ship_to_address_id")
+		allColumns                      = postgres.ColumnList{IDColumn, UUIDColumn, DealershipIDColumn, NameColumn, InternalReferenceColumn, StatusColumn, TrackingNumberColumn, OrderedAtColumn, OrderedByColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, InstallationKitColumn, InstallationKitPriceCentsColumn, ParentProjectIDColumn, TaxCentsColumn, TaxRatePercentColumn, TaxJurisdictionColumn, TaxExemptCertificateColumn, RushColumn, RushSurchargeCentsColumn, RequiredByColumn, ShipToAddressIDColumn}
+		mutableColumns                  = postgres.ColumnList{UUIDColumn, DealershipIDColumn, NameColumn, InternalReferenceColumn, StatusColumn, TrackingNumberColumn, OrderedAtColumn, OrderedByColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, InstallationKitColumn, InstallationKitPriceCentsColumn, ParentProjectIDColumn, TaxCentsColumn, TaxRatePercentColumn, TaxJurisdictionColumn, TaxExemptCertificateColumn, RushColumn, RushSurchargeCentsColumn, RequiredByColumn, ShipToAddressIDColumn}
+		defaultColumns                  = postgres.ColumnList{IDColumn, UUIDColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn, VersionColumn, InstallationKitColumn, RushColumn}
 	)
 
 	return projectsTable{
@@ -109,6 +127,15 @@ func newProjectsTableImpl(schemaName, tableName, alias string) projectsTable {
 		Version:                   VersionColumn,
 		InstallationKit:           InstallationKitColumn,
 		InstallationKitPriceCents: InstallationKitPriceCentsColumn,
+		ParentProjectID:           ParentProjectIDColumn,
+		TaxCents:                  TaxCentsColumn,
+		TaxRatePercent:            TaxRatePercentColumn,
+		TaxJurisdiction:           TaxJurisdictionColumn,
+		TaxExemptCertificate:      TaxExemptCertificateColumn,
+		Rush:                      RushColumn,
+		RushSurchargeCents:        RushSurchargeCentsColumn,
+		RequiredBy:                RequiredByColumn,
+		ShipToAddressID:           ShipToAddressIDColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
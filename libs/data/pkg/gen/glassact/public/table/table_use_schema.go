@@ -13,31 +13,60 @@ func UseSchema(schema string) {
 	CatalogItemTags = CatalogItemTags.FromSchema(schema)
 	CatalogItems = CatalogItems.FromSchema(schema)
 	DealershipAccounts = DealershipAccounts.FromSchema(schema)
+	DealershipGroups = DealershipGroups.FromSchema(schema)
+	DealershipPriceTierOverrides = DealershipPriceTierOverrides.FromSchema(schema)
+	DealershipPriceTiers = DealershipPriceTiers.FromSchema(schema)
+	DealershipRolePermissions = DealershipRolePermissions.FromSchema(schema)
+	DealershipRoles = DealershipRoles.FromSchema(schema)
 	DealershipTokens = DealershipTokens.FromSchema(schema)
 	DealershipUserNotificationPrefs = DealershipUserNotificationPrefs.FromSchema(schema)
+	DealershipUserPermissions = DealershipUserPermissions.FromSchema(schema)
 	DealershipUsers = DealershipUsers.FromSchema(schema)
 	Dealerships = Dealerships.FromSchema(schema)
 	GlassColors = GlassColors.FromSchema(schema)
 	Grouts = Grouts.FromSchema(schema)
+	ImpersonationRequests = ImpersonationRequests.FromSchema(schema)
+	Impersonations = Impersonations.FromSchema(schema)
 	InlayCatalogInfos = InlayCatalogInfos.FromSchema(schema)
 	InlayCustomInfos = InlayCustomInfos.FromSchema(schema)
 	InlayCustomReferenceImages = InlayCustomReferenceImages.FromSchema(schema)
 	InlayMilestones = InlayMilestones.FromSchema(schema)
 	InlayProofs = InlayProofs.FromSchema(schema)
+	InlayRemakePhotos = InlayRemakePhotos.FromSchema(schema)
+	InlayRemakes = InlayRemakes.FromSchema(schema)
 	InlayUpdates = InlayUpdates.FromSchema(schema)
 	Inlays = Inlays.FromSchema(schema)
 	InternalAccounts = InternalAccounts.FromSchema(schema)
+	InternalRolePermissions = InternalRolePermissions.FromSchema(schema)
+	InternalRoles = InternalRoles.FromSchema(schema)
 	InternalTokens = InternalTokens.FromSchema(schema)
 	InternalUserNotificationPrefs = InternalUserNotificationPrefs.FromSchema(schema)
+	InternalUserPermissions = InternalUserPermissions.FromSchema(schema)
+	InternalUserRecoveryCodes = InternalUserRecoveryCodes.FromSchema(schema)
+	InternalUserTwoFactor = InternalUserTwoFactor.FromSchema(schema)
 	InternalUsers = InternalUsers.FromSchema(schema)
+	Invitations = Invitations.FromSchema(schema)
 	Invoices = Invoices.FromSchema(schema)
+	JobRuns = JobRuns.FromSchema(schema)
+	MaterialReservations = MaterialReservations.FromSchema(schema)
+	MaterialStocks = MaterialStocks.FromSchema(schema)
 	Notifications = Notifications.FromSchema(schema)
+	OidcProviderDealerships = OidcProviderDealerships.FromSchema(schema)
+	OidcProviders = OidcProviders.FromSchema(schema)
 	OrderSnapshots = OrderSnapshots.FromSchema(schema)
 	PriceGroups = PriceGroups.FromSchema(schema)
 	ProjectChats = ProjectChats.FromSchema(schema)
+	ProjectQuoteLines = ProjectQuoteLines.FromSchema(schema)
+	ProjectQuotes = ProjectQuotes.FromSchema(schema)
 	ProjectWatchers = ProjectWatchers.FromSchema(schema)
 	Projects = Projects.FromSchema(schema)
+	RateLimitBuckets = RateLimitBuckets.FromSchema(schema)
 	SchemaMigrations = SchemaMigrations.FromSchema(schema)
+	ShipmentInlays = ShipmentInlays.FromSchema(schema)
+	Shipments = Shipments.FromSchema(schema)
+	ShippingAddresses = ShippingAddresses.FromSchema(schema)
 	SpatialRefSys = SpatialRefSys.FromSchema(schema)
 	SupportArticles = SupportArticles.FromSchema(schema)
+	TaxRates = TaxRates.FromSchema(schema)
+	TwoFactorPolicy = TwoFactorPolicy.FromSchema(schema)
 }
@@ -14,13 +14,60 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+type GlassFinish string
+
+type glassFinishes struct {
+	Cathedral  GlassFinish
+	Opalescent GlassFinish
+	Iridescent GlassFinish
+}
+
+var GlassFinishes = glassFinishes{
+	Cathedral:  GlassFinish("cathedral"),
+	Opalescent: GlassFinish("opalescent"),
+	Iridescent: GlassFinish("iridescent"),
+}
+
+type GlassTexture string
+
+type glassTextures struct {
+	Smooth   GlassTexture
+	Streaky  GlassTexture
+	Rippled  GlassTexture
+	Seedy    GlassTexture
+	Hammered GlassTexture
+	Granite  GlassTexture
+}
+
+var GlassTextures = glassTextures{
+	Smooth:   GlassTexture("smooth"),
+	Streaky:  GlassTexture("streaky"),
+	Rippled:  GlassTexture("rippled"),
+	Seedy:    GlassTexture("seedy"),
+	Hammered: GlassTexture("hammered"),
+	Granite:  GlassTexture("granite"),
+}
+
 type GlassColor struct {
 	StandardTable
-	Name      string  `json:"name"`
-	Hex       string  `json:"hex"`
-	Family    *string `json:"family"`
-	SortOrder int     `json:"sort_order"`
-	IsActive  bool    `json:"is_active"`
+	Name      string       `json:"name"`
+	Hex       string       `json:"hex"`
+	Family    *string      `json:"family"`
+	SortOrder int          `json:"sort_order"`
+	IsActive  bool         `json:"is_active"`
+	Finish    GlassFinish  `json:"finish"`
+	Texture   GlassTexture `json:"texture"`
+	// Opacity is above 0 and at most 1 (fully opaque).
+	Opacity        float64 `json:"opacity"`
+	SwatchImageURL *string `json:"swatch_image_url"`
+	// UnavailableUntil is set while the color is out of stock with a known
+	// restock date. The palette still lists it, but it cannot be chosen.
+	UnavailableUntil *time.Time `json:"unavailable_until"`
+}
+
+// IsAvailable reports whether the color can be chosen at the given time.
+func (gc *GlassColor) IsAvailable(at time.Time) bool {
+	return gc.UnavailableUntil == nil || !gc.UnavailableUntil.After(at)
 }
 
 type GlassColorModel struct {
@@ -37,11 +84,16 @@ func glassColorFromGen(gen model.GlassColors) *GlassColor {
 			UpdatedAt: gen.UpdatedAt,
 			Version:   int(gen.Version),
 		},
-		Name:      gen.Name,
-		Hex:       gen.Hex,
-		Family:    gen.Family,
-		SortOrder: int(gen.SortOrder),
-		IsActive:  gen.IsActive,
+		Name:             gen.Name,
+		Hex:              gen.Hex,
+		Family:           gen.Family,
+		SortOrder:        int(gen.SortOrder),
+		IsActive:         gen.IsActive,
+		Finish:           GlassFinish(gen.Finish),
+		Texture:          GlassTexture(gen.Texture),
+		Opacity:          gen.Opacity,
+		SwatchImageURL:   gen.SwatchImageURL,
+		UnavailableUntil: gen.UnavailableUntil,
 	}
 }
 
@@ -56,17 +108,38 @@ func glassColorToGen(gc *GlassColor) (*model.GlassColors, error) {
 		}
 	}
 
+	// Zero values map to the column defaults so callers that predate these
+	// properties keep inserting opaque, smooth cathedral glass. Opacity is
+	// never stored as 0, so a zero one can only mean it was left unset.
+	finish := gc.Finish
+	if finish == "" {
+		finish = GlassFinishes.Cathedral
+	}
+	texture := gc.Texture
+	if texture == "" {
+		texture = GlassTextures.Smooth
+	}
+	opacity := gc.Opacity
+	if opacity == 0 {
+		opacity = 1
+	}
+
 	return &model.GlassColors{
-		ID:        int32(gc.ID),
-		UUID:      glassColorUUID,
-		Name:      gc.Name,
-		Hex:       gc.Hex,
-		Family:    gc.Family,
-		SortOrder: int32(gc.SortOrder),
-		IsActive:  gc.IsActive,
-		UpdatedAt: gc.UpdatedAt,
-		CreatedAt: gc.CreatedAt,
-		Version:   int32(gc.Version),
+		ID:               int32(gc.ID),
+		UUID:             glassColorUUID,
+		Name:             gc.Name,
+		Hex:              gc.Hex,
+		Family:           gc.Family,
+		SortOrder:        int32(gc.SortOrder),
+		IsActive:         gc.IsActive,
+		UpdatedAt:        gc.UpdatedAt,
+		CreatedAt:        gc.CreatedAt,
+		Version:          int32(gc.Version),
+		Finish:           string(finish),
+		Texture:          string(texture),
+		Opacity:          opacity,
+		SwatchImageURL:   gc.SwatchImageURL,
+		UnavailableUntil: gc.UnavailableUntil,
 	}, nil
 }
 
@@ -82,6 +155,11 @@ func (m GlassColorModel) Insert(glassColor *GlassColor) error {
 		table.GlassColors.Family,
 		table.GlassColors.SortOrder,
 		table.GlassColors.IsActive,
+		table.GlassColors.Finish,
+		table.GlassColors.Texture,
+		table.GlassColors.Opacity,
+		table.GlassColors.SwatchImageURL,
+		table.GlassColors.UnavailableUntil,
 	).MODEL(
 		gen,
 	).RETURNING(
@@ -223,6 +301,45 @@ func (m GlassColorModel) GetAllActive() ([]*GlassColor, error) {
 	return glassColors, nil
 }
 
+// GetAllActiveByFinish returns the active palette restricted to the given
+// finishes, in palette order.
+func (m GlassColorModel) GetAllActiveByFinish(finishes []GlassFinish) ([]*GlassColor, error) {
+	finishExprs := make([]postgres.Expression, len(finishes))
+	for i, f := range finishes {
+		finishExprs[i] = postgres.String(string(f))
+	}
+
+	query := postgres.SELECT(
+		table.GlassColors.AllColumns,
+	).FROM(
+		table.GlassColors,
+	).WHERE(
+		postgres.AND(
+			table.GlassColors.IsActive.EQ(postgres.Bool(true)),
+			table.GlassColors.Finish.IN(finishExprs...),
+		),
+	).ORDER_BY(
+		table.GlassColors.SortOrder.ASC(),
+		table.GlassColors.Name.ASC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.GlassColors
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	glassColors := make([]*GlassColor, len(dest))
+	for i, d := range dest {
+		glassColors[i] = glassColorFromGen(d)
+	}
+
+	return glassColors, nil
+}
+
 func (m GlassColorModel) Update(glassColor *GlassColor) error {
 	gen, err := glassColorToGen(glassColor)
 	if err != nil {
@@ -235,6 +352,11 @@ func (m GlassColorModel) Update(glassColor *GlassColor) error {
 		table.GlassColors.Family,
 		table.GlassColors.SortOrder,
 		table.GlassColors.IsActive,
+		table.GlassColors.Finish,
+		table.GlassColors.Texture,
+		table.GlassColors.Opacity,
+		table.GlassColors.SwatchImageURL,
+		table.GlassColors.UnavailableUntil,
 		table.GlassColors.Version,
 	).MODEL(
 		gen,
@@ -2,6 +2,7 @@ package data
 
 import (
 	"testing"
+	"time"
 )
 
 func TestGlassColor_Insert(t *testing.T) {
@@ -100,3 +101,128 @@ func TestGlassColor_VersionIncrement(t *testing.T) {
 		t.Errorf("Expected version to increment from %d, got %d", initial, gc.Version)
 	}
 }
+
+func TestGlassColor_Insert_PersistsPhysicalProperties(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+
+	gc := &GlassColor{
+		Name:           "Streaky Amber",
+		Hex:            "#c87a1e",
+		IsActive:       true,
+		Finish:         GlassFinishes.Opalescent,
+		Texture:        GlassTextures.Streaky,
+		Opacity:        0.8,
+		SwatchImageURL: stringPtr("/file/swatches/amber.png"),
+	}
+	if err := models.GlassColors.Insert(gc); err != nil {
+		t.Fatalf("Failed to insert glass color: %v", err)
+	}
+
+	got, found, err := models.GlassColors.GetByID(gc.ID)
+	if err != nil || !found {
+		t.Fatalf("GetByID failed: found=%v err=%v", found, err)
+	}
+	if got.Finish != GlassFinishes.Opalescent || got.Texture != GlassTextures.Streaky {
+		t.Errorf("Expected opalescent/streaky, got %s/%s", got.Finish, got.Texture)
+	}
+	if got.Opacity != 0.8 {
+		t.Errorf("Expected opacity 0.8, got %v", got.Opacity)
+	}
+	if got.SwatchImageURL == nil || *got.SwatchImageURL != "/file/swatches/amber.png" {
+		t.Errorf("Expected swatch image url to round-trip, got %v", got.SwatchImageURL)
+	}
+}
+
+func TestGlassColor_Insert_DefaultsFinishAndTexture(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+
+	gc := &GlassColor{Name: "Plain", Hex: "#444444", IsActive: true}
+	if err := models.GlassColors.Insert(gc); err != nil {
+		t.Fatalf("Failed to insert glass color: %v", err)
+	}
+
+	got, _, err := models.GlassColors.GetByID(gc.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.Finish != GlassFinishes.Cathedral || got.Texture != GlassTextures.Smooth {
+		t.Errorf("Expected cathedral/smooth defaults, got %s/%s", got.Finish, got.Texture)
+	}
+	if got.Opacity != 1 {
+		t.Errorf("Expected opaque default, got %v", got.Opacity)
+	}
+}
+
+func TestGlassColor_GetAllActiveByFinish(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+
+	mustInsert := func(gc *GlassColor) {
+		if err := models.GlassColors.Insert(gc); err != nil {
+			t.Fatalf("Failed to insert %s: %v", gc.Name, err)
+		}
+	}
+	mustInsert(&GlassColor{Name: "Opal", Hex: "#eeeeee", IsActive: true, Opacity: 1, Finish: GlassFinishes.Opalescent})
+	mustInsert(&GlassColor{Name: "Irid", Hex: "#555555", IsActive: true, Opacity: 1, Finish: GlassFinishes.Iridescent})
+	mustInsert(&GlassColor{Name: "Cathedral", Hex: "#777777", IsActive: true, Opacity: 0.4})
+	mustInsert(&GlassColor{Name: "Old Opal", Hex: "#dddddd", IsActive: false, Opacity: 1, Finish: GlassFinishes.Opalescent})
+
+	opal, err := models.GlassColors.GetAllActiveByFinish([]GlassFinish{GlassFinishes.Opalescent})
+	if err != nil {
+		t.Fatalf("GetAllActiveByFinish failed: %v", err)
+	}
+	if len(opal) != 1 || opal[0].Name != "Opal" {
+		t.Errorf("Expected only the active opalescent color, got %d results", len(opal))
+	}
+
+	both, err := models.GlassColors.GetAllActiveByFinish([]GlassFinish{GlassFinishes.Opalescent, GlassFinishes.Iridescent})
+	if err != nil {
+		t.Fatalf("GetAllActiveByFinish failed: %v", err)
+	}
+	if len(both) != 2 {
+		t.Errorf("Expected 2 colors, got %d", len(both))
+	}
+}
+
+func TestGlassColor_UnavailableUntil(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+
+	restock := time.Now().Add(72 * time.Hour).Truncate(time.Microsecond)
+	gc := &GlassColor{Name: "Ruby", Hex: "#9b111e", IsActive: true, Opacity: 1, UnavailableUntil: &restock}
+	if err := models.GlassColors.Insert(gc); err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+
+	got, _, err := models.GlassColors.GetByID(gc.ID)
+	if err != nil {
+		t.Fatalf("Failed to get: %v", err)
+	}
+	if got.UnavailableUntil == nil || !got.UnavailableUntil.Equal(restock) {
+		t.Fatalf("Expected unavailable_until %v, got %v", restock, got.UnavailableUntil)
+	}
+	if got.IsAvailable(time.Now()) {
+		t.Errorf("Expected color to be unavailable before the restock date")
+	}
+	if !got.IsAvailable(restock.Add(time.Minute)) {
+		t.Errorf("Expected color to be available after the restock date")
+	}
+
+	got.UnavailableUntil = nil
+	if err := models.GlassColors.Update(got); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	got, _, err = models.GlassColors.GetByID(gc.ID)
+	if err != nil {
+		t.Fatalf("Failed to get: %v", err)
+	}
+	if got.UnavailableUntil != nil {
+		t.Errorf("Expected unavailable_until to be cleared, got %v", got.UnavailableUntil)
+	}
+}
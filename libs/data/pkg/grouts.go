@@ -20,6 +20,14 @@ type Grout struct {
 	Hex       string `json:"hex"`
 	SortOrder int    `json:"sort_order"`
 	IsActive  bool   `json:"is_active"`
+	// UnavailableUntil is set while the grout is out of stock with a known
+	// restock date. The palette still lists it, but it cannot be chosen.
+	UnavailableUntil *time.Time `json:"unavailable_until"`
+}
+
+// IsAvailable reports whether the grout can be chosen at the given time.
+func (g *Grout) IsAvailable(at time.Time) bool {
+	return g.UnavailableUntil == nil || !g.UnavailableUntil.After(at)
 }
 
 type GroutModel struct {
@@ -36,10 +44,11 @@ func groutFromGen(gen model.Grouts) *Grout {
 			UpdatedAt: gen.UpdatedAt,
 			Version:   int(gen.Version),
 		},
-		Name:      gen.Name,
-		Hex:       gen.Hex,
-		SortOrder: int(gen.SortOrder),
-		IsActive:  gen.IsActive,
+		Name:             gen.Name,
+		Hex:              gen.Hex,
+		SortOrder:        int(gen.SortOrder),
+		IsActive:         gen.IsActive,
+		UnavailableUntil: gen.UnavailableUntil,
 	}
 }
 
@@ -55,15 +64,16 @@ func groutToGen(g *Grout) (*model.Grouts, error) {
 	}
 
 	return &model.Grouts{
-		ID:        int32(g.ID),
-		UUID:      groutUUID,
-		Name:      g.Name,
-		Hex:       g.Hex,
-		SortOrder: int32(g.SortOrder),
-		IsActive:  g.IsActive,
-		UpdatedAt: g.UpdatedAt,
-		CreatedAt: g.CreatedAt,
-		Version:   int32(g.Version),
+		ID:               int32(g.ID),
+		UUID:             groutUUID,
+		Name:             g.Name,
+		Hex:              g.Hex,
+		SortOrder:        int32(g.SortOrder),
+		IsActive:         g.IsActive,
+		UpdatedAt:        g.UpdatedAt,
+		CreatedAt:        g.CreatedAt,
+		Version:          int32(g.Version),
+		UnavailableUntil: g.UnavailableUntil,
 	}, nil
 }
 
@@ -78,6 +88,7 @@ func (m GroutModel) Insert(grout *Grout) error {
 		table.Grouts.Hex,
 		table.Grouts.SortOrder,
 		table.Grouts.IsActive,
+		table.Grouts.UnavailableUntil,
 	).MODEL(
 		gen,
 	).RETURNING(
@@ -230,6 +241,7 @@ func (m GroutModel) Update(grout *Grout) error {
 		table.Grouts.Hex,
 		table.Grouts.SortOrder,
 		table.Grouts.IsActive,
+		table.Grouts.UnavailableUntil,
 		table.Grouts.Version,
 	).MODEL(
 		gen,
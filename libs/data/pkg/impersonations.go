@@ -0,0 +1,266 @@
+package data
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ImpersonationTTL is how long an admin can view as a dealership user before
+// starting again.
+const ImpersonationTTL = 30 * time.Minute
+
+// Impersonation is an internal admin viewing the app as a dealership user.
+// Its access token only reads; every request made with it is recorded as an
+// ImpersonationRequest.
+type Impersonation struct {
+	ID                  int        `json:"id"`
+	UUID                string     `json:"uuid"`
+	InternalUserID      *int       `json:"internal_user_id"`
+	InternalUserEmail   string     `json:"internal_user_email"`
+	DealershipUserID    *int       `json:"dealership_user_id"`
+	DealershipUserEmail string     `json:"dealership_user_email"`
+	Reason              string     `json:"reason"`
+	IP                  string     `json:"ip"`
+	UserAgent           string     `json:"user_agent"`
+	ExpiresAt           time.Time  `json:"expires_at"`
+	EndedAt             *time.Time `json:"ended_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the impersonation's token still works at now.
+func (i *Impersonation) IsActive(now time.Time) bool {
+	return i.EndedAt == nil && now.Before(i.ExpiresAt)
+}
+
+type ImpersonationRequest struct {
+	ID        int       `json:"id"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Blocked   bool      `json:"blocked"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ImpersonationModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func impersonationFromGen(gen model.Impersonations) *Impersonation {
+	var internalUserID, dealershipUserID *int
+	if gen.InternalUserID != nil {
+		id := int(*gen.InternalUserID)
+		internalUserID = &id
+	}
+	if gen.DealershipUserID != nil {
+		id := int(*gen.DealershipUserID)
+		dealershipUserID = &id
+	}
+
+	return &Impersonation{
+		ID:                  int(gen.ID),
+		UUID:                gen.UUID.String(),
+		InternalUserID:      internalUserID,
+		InternalUserEmail:   gen.InternalUserEmail,
+		DealershipUserID:    dealershipUserID,
+		DealershipUserEmail: gen.DealershipUserEmail,
+		Reason:              gen.Reason,
+		IP:                  gen.IP,
+		UserAgent:           gen.UserAgent,
+		ExpiresAt:           gen.ExpiresAt,
+		EndedAt:             gen.EndedAt,
+		CreatedAt:           gen.CreatedAt,
+	}
+}
+
+// Start records a new impersonation and returns it with its access token.
+func (m ImpersonationModel) Start(admin *InternalUser, target *DealershipUser, reason string, client SessionClient) (*Impersonation, string, error) {
+	plaintext := rand.Text()
+	hash := sha256.Sum256([]byte(plaintext))
+
+	internalUserID := int32(admin.ID)
+	dealershipUserID := int32(target.ID)
+
+	query := table.Impersonations.INSERT(
+		table.Impersonations.InternalUserID,
+		table.Impersonations.InternalUserEmail,
+		table.Impersonations.DealershipUserID,
+		table.Impersonations.DealershipUserEmail,
+		table.Impersonations.Reason,
+		table.Impersonations.TokenHash,
+		table.Impersonations.IP,
+		table.Impersonations.UserAgent,
+		table.Impersonations.ExpiresAt,
+	).MODEL(model.Impersonations{
+		InternalUserID:      &internalUserID,
+		InternalUserEmail:   admin.Email,
+		DealershipUserID:    &dealershipUserID,
+		DealershipUserEmail: target.Email,
+		Reason:              reason,
+		TokenHash:           hash[:],
+		IP:                  client.IP,
+		UserAgent:           client.UserAgent,
+		ExpiresAt:           time.Now().Add(ImpersonationTTL),
+	}).RETURNING(
+		table.Impersonations.AllColumns,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.Impersonations
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return impersonationFromGen(dest), plaintext, nil
+}
+
+func (m ImpersonationModel) getOne(condition postgres.BoolExpression) (*Impersonation, bool, error) {
+	query := postgres.SELECT(
+		table.Impersonations.AllColumns,
+	).FROM(
+		table.Impersonations,
+	).WHERE(
+		condition,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.Impersonations
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return impersonationFromGen(dest), true, nil
+}
+
+// GetActiveForToken finds the impersonation an access token belongs to, if it
+// has neither expired nor been ended.
+func (m ImpersonationModel) GetActiveForToken(plaintext string) (*Impersonation, bool, error) {
+	hash := sha256.Sum256([]byte(plaintext))
+
+	return m.getOne(postgres.AND(
+		table.Impersonations.TokenHash.EQ(postgres.Bytea(hash[:])),
+		table.Impersonations.EndedAt.IS_NULL(),
+		table.Impersonations.ExpiresAt.GT(postgres.TimestampzT(time.Now())),
+	))
+}
+
+func (m ImpersonationModel) GetByUUID(uuidStr string) (*Impersonation, bool, error) {
+	parsedUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return m.getOne(table.Impersonations.UUID.EQ(postgres.UUID(parsedUUID)))
+}
+
+// GetRecent lists impersonations newest first.
+func (m ImpersonationModel) GetRecent(limit int) ([]*Impersonation, error) {
+	query := postgres.SELECT(
+		table.Impersonations.AllColumns,
+	).FROM(
+		table.Impersonations,
+	).ORDER_BY(
+		table.Impersonations.CreatedAt.DESC(),
+		table.Impersonations.ID.DESC(),
+	).LIMIT(int64(limit))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.Impersonations
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return nil, err
+	}
+
+	impersonations := make([]*Impersonation, len(dest))
+	for i, d := range dest {
+		impersonations[i] = impersonationFromGen(d)
+	}
+
+	return impersonations, nil
+}
+
+// End stops the impersonation's token working. Ending one that already ended
+// keeps the first end time.
+func (m ImpersonationModel) End(impersonation *Impersonation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.STDB.QueryRowContext(ctx, `
+		UPDATE impersonations
+		SET ended_at = COALESCE(ended_at, LEAST(now(), expires_at))
+		WHERE id = $1
+		RETURNING ended_at
+	`, impersonation.ID).Scan(&impersonation.EndedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+// LogRequest adds a request made with the impersonation's token to the audit
+// trail.
+func (m ImpersonationModel) LogRequest(impersonationID int, method, path string, blocked bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.STDB.ExecContext(ctx, `
+		INSERT INTO impersonation_requests (impersonation_id, method, path, blocked) VALUES ($1, $2, $3, $4)
+	`, impersonationID, method, path, blocked)
+	return err
+}
+
+// GetRequests lists the requests made during an impersonation in order.
+func (m ImpersonationModel) GetRequests(impersonationID int) ([]*ImpersonationRequest, error) {
+	query := postgres.SELECT(
+		table.ImpersonationRequests.AllColumns,
+	).FROM(
+		table.ImpersonationRequests,
+	).WHERE(
+		table.ImpersonationRequests.ImpersonationID.EQ(postgres.Int(int64(impersonationID))),
+	).ORDER_BY(
+		table.ImpersonationRequests.ID.ASC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.ImpersonationRequests
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return nil, err
+	}
+
+	requests := make([]*ImpersonationRequest, len(dest))
+	for i, d := range dest {
+		requests[i] = &ImpersonationRequest{
+			ID:        int(d.ID),
+			Method:    d.Method,
+			Path:      d.Path,
+			Blocked:   d.Blocked,
+			CreatedAt: d.CreatedAt,
+		}
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,84 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestImpersonationModel_StartAndEnd(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	target := createTestDealershipUser(t, models, dealership.ID)
+
+	admin := &InternalUser{
+		Name:     "Support",
+		Email:    "support@example.com",
+		Avatar:   "https://example.com/avatar.jpg",
+		Role:     InternalUserRoles.Admin,
+		IsActive: true,
+	}
+	err := models.InternalUsers.Insert(admin)
+	if err != nil {
+		t.Fatalf("Failed to create internal user: %v", err)
+	}
+
+	impersonation, token, err := models.Impersonations.Start(admin, target, "Checking a report", SessionClient{IP: "203.0.113.7"})
+	if err != nil {
+		t.Fatalf("Failed to start impersonation: %v", err)
+	}
+	if !impersonation.IsActive(time.Now()) {
+		t.Errorf("Expected a new impersonation to be active")
+	}
+
+	found, ok, err := models.Impersonations.GetActiveForToken(token)
+	if err != nil || !ok {
+		t.Fatalf("Expected the token to find the impersonation: ok=%v err=%v", ok, err)
+	}
+	if found.ID != impersonation.ID || found.DealershipUserEmail != target.Email {
+		t.Errorf("Expected the started impersonation, got %+v", found)
+	}
+
+	err = models.Impersonations.LogRequest(impersonation.ID, "DELETE", "/api/project/1", true)
+	if err != nil {
+		t.Fatalf("Failed to log request: %v", err)
+	}
+
+	requests, err := models.Impersonations.GetRequests(impersonation.ID)
+	if err != nil {
+		t.Fatalf("Failed to get requests: %v", err)
+	}
+	if len(requests) != 1 || !requests[0].Blocked {
+		t.Errorf("Expected the blocked request in the audit trail, got %+v", requests)
+	}
+
+	err = models.Impersonations.End(impersonation)
+	if err != nil {
+		t.Fatalf("Failed to end impersonation: %v", err)
+	}
+	if impersonation.EndedAt == nil {
+		t.Errorf("Expected the end time to be recorded")
+	}
+
+	_, ok, err = models.Impersonations.GetActiveForToken(token)
+	if err != nil {
+		t.Fatalf("Failed to look up token: %v", err)
+	}
+	if ok {
+		t.Errorf("Expected an ended impersonation's token to stop working")
+	}
+
+	err = models.DealershipUsers.Delete(target.ID)
+	if err != nil {
+		t.Fatalf("Failed to delete dealership user: %v", err)
+	}
+
+	found, ok, err = models.Impersonations.GetByUUID(impersonation.UUID)
+	if err != nil || !ok {
+		t.Fatalf("Expected the audit record to outlive the user: ok=%v err=%v", ok, err)
+	}
+	if found.DealershipUserID != nil || found.DealershipUserEmail != target.Email {
+		t.Errorf("Expected the user id cleared and the email kept, got %+v", found)
+	}
+}
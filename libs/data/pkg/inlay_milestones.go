@@ -26,11 +26,13 @@ func (m InlayMilestoneModel) TxInsert(tx *sql.Tx, milestone *InlayMilestone) err
 		table.InlayMilestones.EventType,
 		table.InlayMilestones.PerformedBy,
 		table.InlayMilestones.EventTime,
+		table.InlayMilestones.Cycle,
 	).MODEL(
 		genMilestone,
 	).RETURNING(
 		table.InlayMilestones.ID,
 		table.InlayMilestones.UUID,
+		table.InlayMilestones.Cycle,
 		table.InlayMilestones.UpdatedAt,
 		table.InlayMilestones.CreatedAt,
 		table.InlayMilestones.Version,
@@ -47,6 +49,7 @@ func (m InlayMilestoneModel) TxInsert(tx *sql.Tx, milestone *InlayMilestone) err
 
 	milestone.ID = int(dest.ID)
 	milestone.UUID = dest.UUID.String()
+	milestone.Cycle = int(dest.Cycle)
 	milestone.UpdatedAt = dest.UpdatedAt
 	milestone.CreatedAt = dest.CreatedAt
 	milestone.Version = int(dest.Version)
@@ -86,6 +89,8 @@ var MilestoneEventTypes = milestoneEventTypes{
 	Reverted: MilestoneEventType("reverted"),
 }
 
+// InlayMilestone is one step event on an inlay's manufacturing ladder. Cycle is
+// 1 for the original order and counts up with each remake.
 type InlayMilestone struct {
 	StandardTable
 	InlayID     int                `json:"inlay_id"`
@@ -93,6 +98,7 @@ type InlayMilestone struct {
 	EventType   MilestoneEventType `json:"event_type"`
 	PerformedBy int                `json:"performed_by"`
 	EventTime   time.Time          `json:"event_time"`
+	Cycle       int                `json:"cycle"`
 }
 
 type InlayMilestoneModel struct {
@@ -114,6 +120,7 @@ func inlayMilestoneFromGen(genMilestone model.InlayMilestones) *InlayMilestone {
 		EventType:   MilestoneEventType(genMilestone.EventType),
 		PerformedBy: int(genMilestone.PerformedBy),
 		EventTime:   genMilestone.EventTime,
+		Cycle:       int(genMilestone.Cycle),
 	}
 
 	return &milestone
@@ -130,6 +137,11 @@ func inlayMilestoneToGen(im *InlayMilestone) (*model.InlayMilestones, error) {
 		}
 	}
 
+	cycle := im.Cycle
+	if cycle == 0 {
+		cycle = 1
+	}
+
 	genMilestone := model.InlayMilestones{
 		ID:          int32(im.ID),
 		UUID:        milestoneUUID,
@@ -138,6 +150,7 @@ func inlayMilestoneToGen(im *InlayMilestone) (*model.InlayMilestones, error) {
 		EventType:   string(im.EventType),
 		PerformedBy: int32(im.PerformedBy),
 		EventTime:   im.EventTime,
+		Cycle:       int32(cycle),
 		UpdatedAt:   im.UpdatedAt,
 		CreatedAt:   im.CreatedAt,
 		Version:     int32(im.Version),
@@ -158,11 +171,13 @@ func (m InlayMilestoneModel) Insert(milestone *InlayMilestone) error {
 		table.InlayMilestones.EventType,
 		table.InlayMilestones.PerformedBy,
 		table.InlayMilestones.EventTime,
+		table.InlayMilestones.Cycle,
 	).MODEL(
 		genMilestone,
 	).RETURNING(
 		table.InlayMilestones.ID,
 		table.InlayMilestones.UUID,
+		table.InlayMilestones.Cycle,
 		table.InlayMilestones.UpdatedAt,
 		table.InlayMilestones.CreatedAt,
 		table.InlayMilestones.Version,
@@ -179,6 +194,7 @@ func (m InlayMilestoneModel) Insert(milestone *InlayMilestone) error {
 
 	milestone.ID = int(dest.ID)
 	milestone.UUID = dest.UUID.String()
+	milestone.Cycle = int(dest.Cycle)
 	milestone.UpdatedAt = dest.UpdatedAt
 	milestone.CreatedAt = dest.CreatedAt
 	milestone.Version = int(dest.Version)
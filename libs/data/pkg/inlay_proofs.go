@@ -278,6 +278,9 @@ func (m InlayProofModel) insertProof(ctx context.Context, executor qrm.Queryable
 		table.InlayProofs.ColorOverrides,
 		table.InlayProofs.ApprovalAuthority,
 		table.InlayProofs.Status,
+		table.InlayProofs.ApprovedAt,
+		table.InlayProofs.ApprovedByDealershipUserID,
+		table.InlayProofs.ApprovedByInternalUserID,
 		table.InlayProofs.SentInChatID,
 	).MODEL(
 		genProof,
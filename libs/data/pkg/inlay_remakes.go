@@ -0,0 +1,397 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RemakeStatus string
+
+type remakeStatuses struct {
+	Requested RemakeStatus
+	Approved  RemakeStatus
+	Declined  RemakeStatus
+	Shipped   RemakeStatus
+	Delivered RemakeStatus
+}
+
+var RemakeStatuses = remakeStatuses{
+	Requested: RemakeStatus("requested"),
+	Approved:  RemakeStatus("approved"),
+	Declined:  RemakeStatus("declined"),
+	Shipped:   RemakeStatus("shipped"),
+	Delivered: RemakeStatus("delivered"),
+}
+
+type RemakeBilling string
+
+type remakeBillings struct {
+	Free    RemakeBilling
+	Charged RemakeBilling
+}
+
+var RemakeBillings = remakeBillings{
+	Free:    RemakeBilling("free"),
+	Charged: RemakeBilling("charged"),
+}
+
+type InlayRemakePhoto struct {
+	ID        int       `json:"id"`
+	UUID      string    `json:"uuid"`
+	ImageURL  string    `json:"image_url"`
+	SortOrder int       `json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InlayRemake asks for a delivered inlay to be made again. Cycle numbers the
+// remake's trip through the manufacturing ladder, the original order being
+// cycle 1. Billing is set when the remake is approved.
+type InlayRemake struct {
+	StandardTable
+	InlayID                     int                `json:"inlay_id"`
+	Cycle                       int                `json:"cycle"`
+	Reason                      string             `json:"reason"`
+	Status                      RemakeStatus       `json:"status"`
+	Billing                     *RemakeBilling     `json:"billing"`
+	RequestedByDealershipUserID *int               `json:"requested_by_dealership_user_id"`
+	RequestedByInternalUserID   *int               `json:"requested_by_internal_user_id"`
+	ReviewedBy                  *int               `json:"reviewed_by"`
+	ReviewedAt                  *time.Time         `json:"reviewed_at"`
+	DeclineReason               *string            `json:"decline_reason"`
+	Photos                      []InlayRemakePhoto `json:"photos"`
+}
+
+// IsOpen reports whether the remake is still being worked: requested and
+// awaiting review, in production, or on its way.
+func (r *InlayRemake) IsOpen() bool {
+	return r.Status == RemakeStatuses.Requested ||
+		r.Status == RemakeStatuses.Approved ||
+		r.Status == RemakeStatuses.Shipped
+}
+
+type InlayRemakeModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func intPtrFromGen(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+func intPtrToGen(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
+
+func inlayRemakeFromGen(gen model.InlayRemakes) *InlayRemake {
+	var billing *RemakeBilling
+	if gen.Billing != nil {
+		b := RemakeBilling(*gen.Billing)
+		billing = &b
+	}
+
+	return &InlayRemake{
+		StandardTable: StandardTable{
+			ID:        int(gen.ID),
+			UUID:      gen.UUID.String(),
+			CreatedAt: gen.CreatedAt,
+			UpdatedAt: gen.UpdatedAt,
+			Version:   int(gen.Version),
+		},
+		InlayID:                     int(gen.InlayID),
+		Cycle:                       int(gen.Cycle),
+		Reason:                      gen.Reason,
+		Status:                      RemakeStatus(gen.Status),
+		Billing:                     billing,
+		RequestedByDealershipUserID: intPtrFromGen(gen.RequestedByDealershipUserID),
+		RequestedByInternalUserID:   intPtrFromGen(gen.RequestedByInternalUserID),
+		ReviewedBy:                  intPtrFromGen(gen.ReviewedBy),
+		ReviewedAt:                  gen.ReviewedAt,
+		DeclineReason:               gen.DeclineReason,
+		Photos:                      []InlayRemakePhoto{},
+	}
+}
+
+func inlayRemakeToGen(r *InlayRemake) (*model.InlayRemakes, error) {
+	var remakeUUID uuid.UUID
+	var err error
+
+	if r.UUID != "" {
+		remakeUUID, err = uuid.Parse(r.UUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var billing *string
+	if r.Billing != nil {
+		b := string(*r.Billing)
+		billing = &b
+	}
+
+	status := r.Status
+	if status == "" {
+		status = RemakeStatuses.Requested
+	}
+
+	return &model.InlayRemakes{
+		ID:                          int32(r.ID),
+		UUID:                        remakeUUID,
+		InlayID:                     int32(r.InlayID),
+		Cycle:                       int32(r.Cycle),
+		Reason:                      r.Reason,
+		Status:                      string(status),
+		Billing:                     billing,
+		RequestedByDealershipUserID: intPtrToGen(r.RequestedByDealershipUserID),
+		RequestedByInternalUserID:   intPtrToGen(r.RequestedByInternalUserID),
+		ReviewedBy:                  intPtrToGen(r.ReviewedBy),
+		ReviewedAt:                  r.ReviewedAt,
+		DeclineReason:               r.DeclineReason,
+		UpdatedAt:                   r.UpdatedAt,
+		CreatedAt:                   r.CreatedAt,
+		Version:                     int32(r.Version),
+	}, nil
+}
+
+// Insert opens a remake with its photos. The cycle is the next after the
+// inlay's latest, so it is assigned here and any Cycle on remake is ignored.
+// A second open remake for the same inlay fails on idx_inlay_remakes_open.
+func (m InlayRemakeModel) Insert(remake *InlayRemake, photoURLs []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO inlay_remakes (inlay_id, cycle, reason, requested_by_dealership_user_id, requested_by_internal_user_id)
+		VALUES ($1, (SELECT COALESCE(MAX(cycle), 1) + 1 FROM inlay_remakes WHERE inlay_id = $1), $2, $3, $4)
+		RETURNING id, uuid, cycle, status, created_at, updated_at, version
+	`, remake.InlayID, remake.Reason, remake.RequestedByDealershipUserID, remake.RequestedByInternalUserID).Scan(
+		&remake.ID,
+		&remake.UUID,
+		&remake.Cycle,
+		&remake.Status,
+		&remake.CreatedAt,
+		&remake.UpdatedAt,
+		&remake.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	remake.Photos = []InlayRemakePhoto{}
+	if len(photoURLs) > 0 {
+		genPhotos := make([]model.InlayRemakePhotos, len(photoURLs))
+		for i, url := range photoURLs {
+			genPhotos[i] = model.InlayRemakePhotos{
+				InlayRemakeID: int32(remake.ID),
+				ImageURL:      url,
+				SortOrder:     int32(i),
+			}
+		}
+
+		query := table.InlayRemakePhotos.INSERT(
+			table.InlayRemakePhotos.InlayRemakeID,
+			table.InlayRemakePhotos.ImageURL,
+			table.InlayRemakePhotos.SortOrder,
+		).MODELS(
+			genPhotos,
+		).RETURNING(
+			table.InlayRemakePhotos.AllColumns,
+		)
+
+		var dest []model.InlayRemakePhotos
+		if err := query.QueryContext(ctx, tx, &dest); err != nil {
+			return err
+		}
+		for _, d := range dest {
+			remake.Photos = append(remake.Photos, inlayRemakePhotoFromGen(d))
+		}
+	}
+
+	return tx.Commit()
+}
+
+func inlayRemakePhotoFromGen(gen model.InlayRemakePhotos) InlayRemakePhoto {
+	return InlayRemakePhoto{
+		ID:        int(gen.ID),
+		UUID:      gen.UUID.String(),
+		ImageURL:  gen.ImageURL,
+		SortOrder: int(gen.SortOrder),
+		CreatedAt: gen.CreatedAt,
+	}
+}
+
+func (m InlayRemakeModel) getOne(condition postgres.BoolExpression) (*InlayRemake, bool, error) {
+	remakes, err := m.getMany(condition)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(remakes) == 0 {
+		return nil, false, nil
+	}
+	return remakes[0], true, nil
+}
+
+func (m InlayRemakeModel) getMany(condition postgres.BoolExpression) ([]*InlayRemake, error) {
+	query := postgres.SELECT(
+		table.InlayRemakes.AllColumns,
+	).FROM(
+		table.InlayRemakes,
+	).WHERE(
+		condition,
+	).ORDER_BY(
+		table.InlayRemakes.CreatedAt.ASC(),
+		table.InlayRemakes.ID.ASC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.InlayRemakes
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return nil, err
+	}
+
+	remakes := make([]*InlayRemake, len(dest))
+	for i, d := range dest {
+		remakes[i] = inlayRemakeFromGen(d)
+	}
+
+	if err := m.withPhotos(ctx, remakes); err != nil {
+		return nil, err
+	}
+	return remakes, nil
+}
+
+func (m InlayRemakeModel) withPhotos(ctx context.Context, remakes []*InlayRemake) error {
+	if len(remakes) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*InlayRemake, len(remakes))
+	ids := make([]postgres.Expression, len(remakes))
+	for i, r := range remakes {
+		byID[r.ID] = r
+		ids[i] = postgres.Int(int64(r.ID))
+	}
+
+	query := postgres.SELECT(
+		table.InlayRemakePhotos.AllColumns,
+	).FROM(
+		table.InlayRemakePhotos,
+	).WHERE(
+		table.InlayRemakePhotos.InlayRemakeID.IN(ids...),
+	).ORDER_BY(
+		table.InlayRemakePhotos.SortOrder.ASC(),
+	)
+
+	var dest []model.InlayRemakePhotos
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range dest {
+		r := byID[int(d.InlayRemakeID)]
+		r.Photos = append(r.Photos, inlayRemakePhotoFromGen(d))
+	}
+	return nil
+}
+
+func (m InlayRemakeModel) GetByID(id int) (*InlayRemake, bool, error) {
+	return m.getOne(table.InlayRemakes.ID.EQ(postgres.Int(int64(id))))
+}
+
+func (m InlayRemakeModel) GetByUUID(uuidStr string) (*InlayRemake, bool, error) {
+	parsedUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, false, err
+	}
+	return m.getOne(table.InlayRemakes.UUID.EQ(postgres.UUID(parsedUUID)))
+}
+
+// GetByInlayID returns an inlay's remakes oldest first.
+func (m InlayRemakeModel) GetByInlayID(inlayID int) ([]*InlayRemake, error) {
+	return m.getMany(table.InlayRemakes.InlayID.EQ(postgres.Int(int64(inlayID))))
+}
+
+// GetOpenByInlayID returns the inlay's remake still being worked, if any.
+func (m InlayRemakeModel) GetOpenByInlayID(inlayID int) (*InlayRemake, bool, error) {
+	return m.getOne(postgres.AND(
+		table.InlayRemakes.InlayID.EQ(postgres.Int(int64(inlayID))),
+		table.InlayRemakes.Status.IN(
+			postgres.String(string(RemakeStatuses.Requested)),
+			postgres.String(string(RemakeStatuses.Approved)),
+			postgres.String(string(RemakeStatuses.Shipped)),
+		),
+	))
+}
+
+// GetByStatus returns every remake in the given status, oldest first, for the
+// internal queue.
+func (m InlayRemakeModel) GetByStatus(status RemakeStatus) ([]*InlayRemake, error) {
+	return m.getMany(table.InlayRemakes.Status.EQ(postgres.String(string(status))))
+}
+
+// TxUpdate writes a remake's review and progress. The request itself (inlay,
+// cycle, reason, requester, photos) never changes once opened.
+func (m InlayRemakeModel) TxUpdate(tx *sql.Tx, remake *InlayRemake) error {
+	gen, err := inlayRemakeToGen(remake)
+	if err != nil {
+		return err
+	}
+
+	query := table.InlayRemakes.UPDATE(
+		table.InlayRemakes.Status,
+		table.InlayRemakes.Billing,
+		table.InlayRemakes.ReviewedBy,
+		table.InlayRemakes.ReviewedAt,
+		table.InlayRemakes.DeclineReason,
+		table.InlayRemakes.Version,
+	).MODEL(
+		gen,
+	).WHERE(
+		postgres.AND(
+			table.InlayRemakes.ID.EQ(postgres.Int(int64(remake.ID))),
+			table.InlayRemakes.Version.EQ(postgres.Int(int64(remake.Version))),
+		),
+	).RETURNING(
+		table.InlayRemakes.UpdatedAt,
+		table.InlayRemakes.Version,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.InlayRemakes
+	err = query.QueryContext(ctx, tx, &dest)
+	if err != nil {
+		return err
+	}
+
+	remake.UpdatedAt = dest.UpdatedAt
+	remake.Version = int(dest.Version)
+	return nil
+}
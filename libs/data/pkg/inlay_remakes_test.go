@@ -0,0 +1,69 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInlayRemake_InsertNumbersCycles(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+	models := getTestModels(t)
+
+	dealership := createTestDealership(t, models)
+	project := createTestProject(t, models, dealership.ID)
+	inlay := createTestInlay(t, models, project.ID)
+
+	first := &InlayRemake{InlayID: inlay.ID, Reason: "Cracked in transit"}
+	if err := models.InlayRemakes.Insert(first, []string{"/file/a.jpg", "/file/b.jpg"}); err != nil {
+		t.Fatalf("Failed to insert remake: %v", err)
+	}
+	if first.Cycle != 2 || first.Status != RemakeStatuses.Requested {
+		t.Errorf("Expected a requested cycle 2 remake, got cycle %d status %s", first.Cycle, first.Status)
+	}
+
+	second := &InlayRemake{InlayID: inlay.ID, Reason: "Again"}
+	if err := models.InlayRemakes.Insert(second, nil); err == nil {
+		t.Error("Expected a second open remake to be rejected")
+	}
+
+	tx, err := models.STDB.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	reason := "Matches the proof"
+	now := time.Now()
+	first.Status = RemakeStatuses.Declined
+	first.DeclineReason = &reason
+	first.ReviewedAt = &now
+	if err := models.InlayRemakes.TxUpdate(tx, first); err != nil {
+		t.Fatalf("Failed to decline remake: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	if err := models.InlayRemakes.Insert(second, nil); err != nil {
+		t.Fatalf("Failed to insert remake after decline: %v", err)
+	}
+	if second.Cycle != 3 {
+		t.Errorf("Expected cycle 3, got %d", second.Cycle)
+	}
+
+	remakes, err := models.InlayRemakes.GetByInlayID(inlay.ID)
+	if err != nil {
+		t.Fatalf("Failed to get remakes: %v", err)
+	}
+	if len(remakes) != 2 || len(remakes[0].Photos) != 2 || remakes[0].Photos[1].ImageURL != "/file/b.jpg" {
+		t.Errorf("Unexpected remakes: %+v", remakes)
+	}
+
+	open, found, err := models.InlayRemakes.GetOpenByInlayID(inlay.ID)
+	if err != nil {
+		t.Fatalf("Failed to get open remake: %v", err)
+	}
+	if !found || open.ID != second.ID {
+		t.Errorf("Expected the second remake to be open, got %+v", open)
+	}
+}
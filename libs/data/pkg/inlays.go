@@ -735,6 +735,14 @@ func (m InlayModel) GetCustomNeedingProof() ([]*Inlay, error) {
 	)
 }
 
+// GetByManufacturingStep returns every inlay across all projects currently at
+// the given kanban step.
+func (m InlayModel) GetByManufacturingStep(step ManufacturingStep) ([]*Inlay, error) {
+	return m.queryInlaysWithInfo(
+		table.Inlays.ManufacturingStep.EQ(postgres.String(string(step))),
+	)
+}
+
 func (m InlayModel) queryInlaysWithInfo(where postgres.BoolExpression) ([]*Inlay, error) {
 	query := postgres.SELECT(
 		table.Inlays.AllColumns,
@@ -958,6 +966,33 @@ func (m InlayModel) UpdateSandblastFile(inlay *Inlay) error {
 	return nil
 }
 
+// TxMoveToProject reassigns inlays to another project. Their proofs,
+// milestones and updates hang off the inlay and follow it.
+func (m InlayModel) TxMoveToProject(tx *sql.Tx, inlayIDs []int, projectID int) error {
+	if len(inlayIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]postgres.Expression, len(inlayIDs))
+	for i, id := range inlayIDs {
+		ids[i] = postgres.Int(int64(id))
+	}
+
+	query := table.Inlays.UPDATE(
+		table.Inlays.ProjectID,
+	).SET(
+		postgres.Int(int64(projectID)),
+	).WHERE(
+		table.Inlays.ID.IN(ids...),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := query.ExecContext(ctx, tx)
+	return err
+}
+
 func (m InlayModel) CountByProjectID(projectID int) (int, error) {
 	query := postgres.SELECT(
 		postgres.COUNT(table.Inlays.ID),
@@ -530,3 +530,45 @@ func TestInlay_GetDeleteBlockers_WithEmptyInput_ReturnsEmptyMap(t *testing.T) {
 		t.Errorf("Expected an empty map, got %v", blockers)
 	}
 }
+
+func TestInlay_GetByManufacturingStep_ReturnsOnlyInlaysAtStep(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	project := createTestProject(t, models, dealership.ID)
+
+	atPrep := createTestInlay(t, models, project.ID)
+	elsewhere := createTestInlay(t, models, project.ID)
+	createTestInlay(t, models, project.ID)
+
+	setStep := func(inlay *Inlay, step ManufacturingStep) {
+		tx, err := models.STDB.Begin()
+		if err != nil {
+			t.Fatalf("Failed to begin tx: %v", err)
+		}
+		s := string(step)
+		inlay.ManufacturingStep = &s
+		if err := models.Inlays.TxUpdateFields(tx, inlay); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to set manufacturing step: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+	setStep(atPrep, ManufacturingSteps.MaterialsPrep)
+	setStep(elsewhere, ManufacturingSteps.Manufacturing)
+
+	inlays, err := models.Inlays.GetByManufacturingStep(ManufacturingSteps.MaterialsPrep)
+	if err != nil {
+		t.Fatalf("Failed to get inlays by step: %v", err)
+	}
+
+	if len(inlays) != 1 {
+		t.Fatalf("Expected 1 inlay at materials-prep, got %d", len(inlays))
+	}
+	if inlays[0].ID != atPrep.ID {
+		t.Errorf("Expected inlay %d, got %d", atPrep.ID, inlays[0].ID)
+	}
+}
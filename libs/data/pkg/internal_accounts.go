@@ -71,7 +71,7 @@ func internalAccountToGen(a *InternalAccount) (*model.InternalAccounts, error) {
 	return &genAcc, nil
 }
 
-func (m InternalAccountModel) Insert(account *InternalAccount) error {
+func (m InternalAccountModel) insertAccount(ctx context.Context, executor qrm.Queryable, account *InternalAccount) error {
 	genAcc, err := internalAccountToGen(account)
 	if err != nil {
 		return err
@@ -92,11 +92,8 @@ func (m InternalAccountModel) Insert(account *InternalAccount) error {
 		table.InternalAccounts.Version,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
 	var dest model.InternalAccounts
-	err = query.QueryContext(ctx, m.STDB, &dest)
+	err = query.QueryContext(ctx, executor, &dest)
 	if err != nil {
 		return err
 	}
@@ -110,6 +107,20 @@ func (m InternalAccountModel) Insert(account *InternalAccount) error {
 	return nil
 }
 
+func (m InternalAccountModel) Insert(account *InternalAccount) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.insertAccount(ctx, m.STDB, account)
+}
+
+func (m InternalAccountModel) TxInsert(tx *sql.Tx, account *InternalAccount) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.insertAccount(ctx, tx, account)
+}
+
 func (m InternalAccountModel) GetByID(id int) (*InternalAccount, bool, error) {
 	query := postgres.SELECT(
 		table.InternalAccounts.AllColumns,
@@ -5,17 +5,22 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"database/sql"
+	"errors"
 	"time"
 
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
 	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
 	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 const (
-	InternalScopeLogin   = "login"
-	InternalScopeAccess  = "access"
-	InternalScopeRefresh = "refresh"
+	InternalScopeLogin     = "login"
+	InternalScopeAccess    = "access"
+	InternalScopeRefresh   = "refresh"
+	InternalScopeTwoFactor = "two_factor"
 )
 
 type InternalToken struct {
@@ -24,6 +29,8 @@ type InternalToken struct {
 	InternalUserID int
 	Expiry         time.Time
 	Scope          string
+	SessionID      string
+	Client         SessionClient
 }
 
 func generateInternalToken(internalUserID int, ttl time.Duration, scope string) *InternalToken {
@@ -56,18 +63,47 @@ func (m InternalTokenModel) New(internalUserID int, ttl time.Duration, scope str
 	return token, nil
 }
 
+// NewForSession issues a refresh or access token belonging to a session.
+func (m InternalTokenModel) NewForSession(internalUserID int, ttl time.Duration, scope, sessionID string, client SessionClient) (*InternalToken, error) {
+	token := generateInternalToken(internalUserID, ttl, scope)
+	token.SessionID = sessionID
+	token.Client = client
+
+	err := m.Insert(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
 func (m InternalTokenModel) Insert(token *InternalToken) error {
+	var sessionID *uuid.UUID
+	if token.SessionID != "" {
+		parsed, err := uuid.Parse(token.SessionID)
+		if err != nil {
+			return err
+		}
+		sessionID = &parsed
+	}
+
 	query := table.InternalTokens.INSERT(
 		table.InternalTokens.Hash,
 		table.InternalTokens.InternalUserID,
 		table.InternalTokens.Expiry,
 		table.InternalTokens.Scope,
-	).VALUES(
-		token.Hash,
-		int32(token.InternalUserID),
-		token.Expiry,
-		token.Scope,
-	)
+		table.InternalTokens.SessionID,
+		table.InternalTokens.UserAgent,
+		table.InternalTokens.IP,
+	).MODEL(model.InternalTokens{
+		Hash:           token.Hash,
+		InternalUserID: int32(token.InternalUserID),
+		Expiry:         token.Expiry,
+		Scope:          token.Scope,
+		SessionID:      sessionID,
+		UserAgent:      token.Client.UserAgent,
+		IP:             token.Client.IP,
+	})
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -91,6 +127,23 @@ func (m InternalTokenModel) DeleteAllForUser(scope string, internalUserID int) e
 	return err
 }
 
+// DeleteExpired removes tokens past their expiry, which no longer work, and
+// returns how many there were.
+func (m InternalTokenModel) DeleteExpired() (int64, error) {
+	query := table.InternalTokens.DELETE().WHERE(
+		table.InternalTokens.Expiry.LT(postgres.TimestampzExp(Now())),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := query.ExecContext(ctx, m.STDB)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 func (m InternalTokenModel) DeleteByPlaintext(scope string, plaintext string) error {
 	hash := sha256.Sum256([]byte(plaintext))
 
@@ -107,3 +160,70 @@ func (m InternalTokenModel) DeleteByPlaintext(scope string, plaintext string) er
 	_, err := query.ExecContext(ctx, m.STDB)
 	return err
 }
+
+// Consume uses up an unexpired token and returns whose it was. Deleting it
+// as it is looked up means two requests racing with the same link cannot both
+// get in. The user's other tokens of the scope go with it, so older links stop
+// working once one has been used.
+func (m InternalTokenModel) Consume(scope string, plaintext string) (int, bool, error) {
+	hash := sha256.Sum256([]byte(plaintext))
+
+	query := table.InternalTokens.DELETE().WHERE(
+		postgres.AND(
+			table.InternalTokens.Scope.EQ(postgres.String(scope)),
+			table.InternalTokens.Hash.EQ(postgres.Bytea(hash[:])),
+			table.InternalTokens.Expiry.GT(postgres.TimestampzExp(Now())),
+		),
+	).RETURNING(
+		table.InternalTokens.InternalUserID,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.InternalTokens
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	userID := int(dest.InternalUserID)
+
+	err = m.DeleteAllForUser(scope, userID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return userID, true, nil
+}
+
+// Rotate exchanges a refresh token for the next one in its session. See
+// RotateRefreshToken.
+func (m InternalTokenModel) Rotate(plaintext string, client SessionClient) (*RefreshRotation, error) {
+	return internalSessionTokens.rotate(m.STDB, plaintext, client)
+}
+
+func (m InternalTokenModel) GetSessions(internalUserID int) ([]*Session, error) {
+	return internalSessionTokens.list(m.STDB, internalUserID)
+}
+
+// GetSessionID returns the session a refresh or access token belongs to.
+func (m InternalTokenModel) GetSessionID(scope, plaintext string) (string, bool, error) {
+	return internalSessionTokens.sessionID(m.STDB, scope, plaintext)
+}
+
+func (m InternalTokenModel) RevokeSession(internalUserID int, sessionID string) (bool, error) {
+	return internalSessionTokens.revoke(m.STDB, internalUserID, sessionID)
+}
+
+// RevokeSessionByPlaintext ends the session a refresh token belongs to.
+func (m InternalTokenModel) RevokeSessionByPlaintext(plaintext string) error {
+	return internalSessionTokens.revokeByPlaintext(m.STDB, plaintext)
+}
+
+func (m InternalTokenModel) RevokeAllSessions(internalUserID int) error {
+	return internalSessionTokens.revokeAll(m.STDB, internalUserID)
+}
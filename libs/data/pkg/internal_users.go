@@ -270,7 +270,7 @@ func (m InternalUserModel) GetAll() ([]*InternalUser, error) {
 	return users, nil
 }
 
-func (m InternalUserModel) Update(user *InternalUser) error {
+func (m InternalUserModel) updateUser(ctx context.Context, executor qrm.Queryable, user *InternalUser) error {
 	genUser, err := internalUserToGen(user)
 	if err != nil {
 		return err
@@ -295,11 +295,8 @@ func (m InternalUserModel) Update(user *InternalUser) error {
 		table.InternalUsers.Version,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
 	var dest model.InternalUsers
-	err = query.QueryContext(ctx, m.STDB, &dest)
+	err = query.QueryContext(ctx, executor, &dest)
 	if err != nil {
 		return err
 	}
@@ -310,6 +307,20 @@ func (m InternalUserModel) Update(user *InternalUser) error {
 	return nil
 }
 
+func (m InternalUserModel) Update(user *InternalUser) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.updateUser(ctx, m.STDB, user)
+}
+
+func (m InternalUserModel) TxUpdate(tx *sql.Tx, user *InternalUser) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.updateUser(ctx, tx, user)
+}
+
 func (m InternalUserModel) Delete(id int) error {
 	query := table.InternalUsers.DELETE().WHERE(
 		table.InternalUsers.ID.EQ(postgres.Int(int64(id))),
@@ -365,55 +376,3 @@ func (u *InternalUser) IsDealership() bool {
 func (u *InternalUser) GetDealershipID() *int {
 	return nil
 }
-
-func (u *InternalUser) Can(action string) bool {
-	switch action {
-	case ActionCreateProof:
-		return u.Role == InternalUserRoles.Designer ||
-			u.Role == InternalUserRoles.Admin
-	case ActionInternalApproveProof:
-		return u.Role == InternalUserRoles.Designer ||
-			u.Role == InternalUserRoles.Admin
-	case ActionManageKanban:
-		return u.Role == InternalUserRoles.Production ||
-			u.Role == InternalUserRoles.Admin
-	case ActionManageShipping:
-		return u.Role == InternalUserRoles.Production ||
-			u.Role == InternalUserRoles.Admin
-	case ActionCreateInlayUpdate:
-		return u.Role == InternalUserRoles.Production ||
-			u.Role == InternalUserRoles.Admin
-	case ActionCreateInvoice:
-		return u.Role == InternalUserRoles.Billing ||
-			u.Role == InternalUserRoles.Admin
-	case ActionManageInternalUsers:
-		return u.Role == InternalUserRoles.Admin
-	case ActionManageDealershipUsers:
-		return u.Role == InternalUserRoles.Admin
-	case ActionViewAll:
-		return u.Role == InternalUserRoles.Admin
-	case ActionManageCatalog:
-		return u.Role == InternalUserRoles.Designer ||
-			u.Role == InternalUserRoles.Admin
-	case ActionManagePriceGroups:
-		return u.Role == InternalUserRoles.Billing ||
-			u.Role == InternalUserRoles.Admin
-	case ActionManageDealerships:
-		return u.Role == InternalUserRoles.Admin
-	case ActionManageDealership:
-		return u.Role == InternalUserRoles.Admin
-	case ActionManageSupport:
-		return u.Role == InternalUserRoles.Admin
-	case ActionManageMaterials:
-		return u.Role == InternalUserRoles.Designer ||
-			u.Role == InternalUserRoles.Admin
-	case ActionAccessAdmin:
-		return true
-	case ActionManageProject:
-		return true
-	case ActionSendChat:
-		return true
-	default:
-		return false
-	}
-}
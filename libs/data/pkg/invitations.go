@@ -0,0 +1,340 @@
+package data
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InvitationTTL is how long an invitation link works after it is sent.
+const InvitationTTL = 7 * 24 * time.Hour
+
+type InvitationStatus string
+
+type invitationStatuses struct {
+	Pending  InvitationStatus
+	Accepted InvitationStatus
+	Revoked  InvitationStatus
+}
+
+var InvitationStatuses = invitationStatuses{
+	Pending:  InvitationStatus("pending"),
+	Accepted: InvitationStatus("accepted"),
+	Revoked:  InvitationStatus("revoked"),
+}
+
+// ErrInvitationClosed is returned when an invitation has already been
+// accepted, revoked or has expired.
+var ErrInvitationClosed = errors.New("invitation is no longer open")
+
+// Invitation asks a newly created user to join. Exactly one of
+// DealershipUserID and InternalUserID is set, as is at most one of the
+// InvitedBy IDs. Plaintext is only known when the token is minted, to be put
+// in the email; the database keeps its hash.
+type Invitation struct {
+	StandardTable
+	DealershipUserID          *int             `json:"dealership_user_id"`
+	InternalUserID            *int             `json:"internal_user_id"`
+	Email                     string           `json:"email"`
+	Role                      string           `json:"role"`
+	InvitedByDealershipUserID *int             `json:"invited_by_dealership_user_id"`
+	InvitedByInternalUserID   *int             `json:"invited_by_internal_user_id"`
+	Status                    InvitationStatus `json:"status"`
+	ExpiresAt                 time.Time        `json:"expires_at"`
+	SentAt                    time.Time        `json:"sent_at"`
+	AcceptedAt                *time.Time       `json:"accepted_at"`
+	RevokedAt                 *time.Time       `json:"revoked_at"`
+	Plaintext                 string           `json:"-"`
+	TokenHash                 []byte           `json:"-"`
+}
+
+// NewInvitation invites user on behalf of inviter.
+func NewInvitation(user AuthUser, inviter AuthUser) *Invitation {
+	invitation := &Invitation{
+		Email: user.GetEmail(),
+		Role:  user.GetRole(),
+	}
+
+	id := user.GetID()
+	if user.IsDealership() {
+		invitation.DealershipUserID = &id
+	} else {
+		invitation.InternalUserID = &id
+	}
+
+	if inviter != nil {
+		inviterID := inviter.GetID()
+		if inviter.IsDealership() {
+			invitation.InvitedByDealershipUserID = &inviterID
+		} else {
+			invitation.InvitedByInternalUserID = &inviterID
+		}
+	}
+
+	return invitation
+}
+
+// IsOpen reports whether the invitation can still be accepted.
+func (i *Invitation) IsOpen(at time.Time) bool {
+	return i.Status == InvitationStatuses.Pending && at.Before(i.ExpiresAt)
+}
+
+// mintToken gives the invitation a fresh link that works for InvitationTTL.
+func (i *Invitation) mintToken(now time.Time) {
+	i.Plaintext = rand.Text()
+	hash := sha256.Sum256([]byte(i.Plaintext))
+	i.TokenHash = hash[:]
+	i.ExpiresAt = now.Add(InvitationTTL)
+	i.SentAt = now
+}
+
+type InvitationModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func invitationFromGen(gen model.Invitations) *Invitation {
+	return &Invitation{
+		StandardTable: StandardTable{
+			ID:        int(gen.ID),
+			UUID:      gen.UUID.String(),
+			CreatedAt: gen.CreatedAt,
+			UpdatedAt: gen.UpdatedAt,
+			Version:   int(gen.Version),
+		},
+		DealershipUserID:          intPtrFromGen(gen.DealershipUserID),
+		InternalUserID:            intPtrFromGen(gen.InternalUserID),
+		Email:                     gen.Email,
+		Role:                      gen.Role,
+		InvitedByDealershipUserID: intPtrFromGen(gen.InvitedByDealershipUserID),
+		InvitedByInternalUserID:   intPtrFromGen(gen.InvitedByInternalUserID),
+		Status:                    InvitationStatus(gen.Status),
+		ExpiresAt:                 gen.ExpiresAt,
+		SentAt:                    gen.SentAt,
+		AcceptedAt:                gen.AcceptedAt,
+		RevokedAt:                 gen.RevokedAt,
+		TokenHash:                 gen.TokenHash,
+	}
+}
+
+func invitationToGen(i *Invitation) (*model.Invitations, error) {
+	var invitationUUID uuid.UUID
+	var err error
+
+	if i.UUID != "" {
+		invitationUUID, err = uuid.Parse(i.UUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	status := i.Status
+	if status == "" {
+		status = InvitationStatuses.Pending
+	}
+
+	return &model.Invitations{
+		ID:                        int32(i.ID),
+		UUID:                      invitationUUID,
+		DealershipUserID:          intPtrToGen(i.DealershipUserID),
+		InternalUserID:            intPtrToGen(i.InternalUserID),
+		Email:                     i.Email,
+		Role:                      i.Role,
+		InvitedByDealershipUserID: intPtrToGen(i.InvitedByDealershipUserID),
+		InvitedByInternalUserID:   intPtrToGen(i.InvitedByInternalUserID),
+		TokenHash:                 i.TokenHash,
+		Status:                    string(status),
+		ExpiresAt:                 i.ExpiresAt,
+		SentAt:                    i.SentAt,
+		AcceptedAt:                i.AcceptedAt,
+		RevokedAt:                 i.RevokedAt,
+		CreatedAt:                 i.CreatedAt,
+		UpdatedAt:                 i.UpdatedAt,
+		Version:                   int32(i.Version),
+	}, nil
+}
+
+func (m InvitationModel) insertInvitation(ctx context.Context, executor qrm.Queryable, invitation *Invitation) error {
+	invitation.Status = InvitationStatuses.Pending
+	invitation.mintToken(time.Now())
+
+	gen, err := invitationToGen(invitation)
+	if err != nil {
+		return err
+	}
+
+	query := table.Invitations.INSERT(
+		table.Invitations.DealershipUserID,
+		table.Invitations.InternalUserID,
+		table.Invitations.Email,
+		table.Invitations.Role,
+		table.Invitations.InvitedByDealershipUserID,
+		table.Invitations.InvitedByInternalUserID,
+		table.Invitations.TokenHash,
+		table.Invitations.Status,
+		table.Invitations.ExpiresAt,
+		table.Invitations.SentAt,
+	).MODEL(gen).RETURNING(
+		table.Invitations.ID,
+		table.Invitations.UUID,
+		table.Invitations.CreatedAt,
+		table.Invitations.UpdatedAt,
+		table.Invitations.Version,
+	)
+
+	var dest model.Invitations
+	err = query.QueryContext(ctx, executor, &dest)
+	if err != nil {
+		return err
+	}
+
+	invitation.ID = int(dest.ID)
+	invitation.UUID = dest.UUID.String()
+	invitation.CreatedAt = dest.CreatedAt
+	invitation.UpdatedAt = dest.UpdatedAt
+	invitation.Version = int(dest.Version)
+
+	return nil
+}
+
+// Insert mints the invitation's token and stores it pending. Plaintext is set
+// for the email afterwards.
+func (m InvitationModel) Insert(invitation *Invitation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.insertInvitation(ctx, m.STDB, invitation)
+}
+
+func (m InvitationModel) TxInsert(tx *sql.Tx, invitation *Invitation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.insertInvitation(ctx, tx, invitation)
+}
+
+func (m InvitationModel) getWhere(condition postgres.BoolExpression) (*Invitation, bool, error) {
+	query := postgres.SELECT(
+		table.Invitations.AllColumns,
+	).FROM(
+		table.Invitations,
+	).WHERE(
+		condition,
+	).ORDER_BY(
+		table.Invitations.ID.DESC(),
+	).LIMIT(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.Invitations
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return invitationFromGen(dest), true, nil
+}
+
+// GetByToken finds the invitation a link was sent for, whatever its status.
+// A resent invitation no longer answers to its earlier links.
+func (m InvitationModel) GetByToken(plaintext string) (*Invitation, bool, error) {
+	hash := sha256.Sum256([]byte(plaintext))
+	return m.getWhere(table.Invitations.TokenHash.EQ(postgres.Bytea(hash[:])))
+}
+
+// GetLatestForUser returns the most recent invitation sent to a user.
+func (m InvitationModel) GetLatestForUser(user AuthUser) (*Invitation, bool, error) {
+	id := postgres.Int(int64(user.GetID()))
+	if user.IsDealership() {
+		return m.getWhere(table.Invitations.DealershipUserID.EQ(id))
+	}
+	return m.getWhere(table.Invitations.InternalUserID.EQ(id))
+}
+
+// Resend mints a new link for a pending invitation and restarts its clock.
+// The old link stops working.
+func (m InvitationModel) Resend(invitation *Invitation) error {
+	invitation.mintToken(time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.STDB.QueryRowContext(ctx, `
+		UPDATE invitations SET token_hash = $2, expires_at = $3, sent_at = $4
+		WHERE id = $1 AND status = 'pending'
+		RETURNING updated_at, version
+	`, invitation.ID, invitation.TokenHash, invitation.ExpiresAt, invitation.SentAt).Scan(&invitation.UpdatedAt, &invitation.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvitationClosed
+	}
+	return err
+}
+
+const acceptInvitation = `
+	UPDATE invitations SET status = 'accepted', accepted_at = now()
+	WHERE id = $1 AND status = 'pending' AND expires_at > now()
+	RETURNING status, accepted_at, revoked_at, updated_at, version
+`
+
+// Accept closes a pending, unexpired invitation as accepted. Only one caller
+// can accept a given invitation; the rest get ErrInvitationClosed.
+func (m InvitationModel) Accept(invitation *Invitation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return closeInvitation(m.STDB.QueryRowContext(ctx, acceptInvitation, invitation.ID), invitation)
+}
+
+func (m InvitationModel) TxAccept(tx *sql.Tx, invitation *Invitation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return closeInvitation(tx.QueryRowContext(ctx, acceptInvitation, invitation.ID), invitation)
+}
+
+// Revoke withdraws a pending invitation. Its user stays inactive.
+func (m InvitationModel) Revoke(invitation *Invitation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return closeInvitation(m.STDB.QueryRowContext(ctx, `
+		UPDATE invitations SET status = 'revoked', revoked_at = now()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING status, accepted_at, revoked_at, updated_at, version
+	`, invitation.ID), invitation)
+}
+
+// closeInvitation reads back the invitation a closing statement returned.
+func closeInvitation(row *sql.Row, invitation *Invitation) error {
+	var status string
+	err := row.Scan(
+		&status,
+		&invitation.AcceptedAt,
+		&invitation.RevokedAt,
+		&invitation.UpdatedAt,
+		&invitation.Version,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvitationClosed
+	}
+	if err != nil {
+		return err
+	}
+
+	invitation.Status = InvitationStatus(status)
+	return nil
+}
@@ -0,0 +1,147 @@
+package data
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInvitation_AcceptOnce(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	user := createTestDealershipUser(t, models, dealership.ID)
+
+	invitation := NewInvitation(user, nil)
+	err := models.Invitations.Insert(invitation)
+	if err != nil {
+		t.Fatalf("Failed to insert invitation: %v", err)
+	}
+
+	if invitation.Plaintext == "" {
+		t.Fatalf("Expected a plaintext token to email")
+	}
+	if !invitation.IsOpen(time.Now()) {
+		t.Errorf("Expected a new invitation to be open")
+	}
+
+	found, ok, err := models.Invitations.GetByToken(invitation.Plaintext)
+	if err != nil || !ok {
+		t.Fatalf("Expected to find the invitation by token: ok=%v err=%v", ok, err)
+	}
+	if found.ID != invitation.ID || *found.DealershipUserID != user.ID {
+		t.Errorf("Found the wrong invitation: %+v", found)
+	}
+
+	err = models.Invitations.Accept(found)
+	if err != nil {
+		t.Fatalf("Failed to accept invitation: %v", err)
+	}
+	if found.Status != InvitationStatuses.Accepted || found.AcceptedAt == nil {
+		t.Errorf("Expected accepted status and time, got %s %v", found.Status, found.AcceptedAt)
+	}
+
+	err = models.Invitations.Accept(invitation)
+	if !errors.Is(err, ErrInvitationClosed) {
+		t.Errorf("Expected a second accept to fail with ErrInvitationClosed, got %v", err)
+	}
+
+	err = models.Invitations.Revoke(invitation)
+	if !errors.Is(err, ErrInvitationClosed) {
+		t.Errorf("Expected revoking an accepted invitation to fail, got %v", err)
+	}
+}
+
+func TestInvitation_TxAcceptRollsBack(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	user := createTestDealershipUser(t, models, dealership.ID)
+
+	invitation := NewInvitation(user, nil)
+	err := models.Invitations.Insert(invitation)
+	if err != nil {
+		t.Fatalf("Failed to insert invitation: %v", err)
+	}
+
+	tx, err := models.STDB.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	err = models.Invitations.TxAccept(tx, invitation)
+	if err != nil {
+		t.Fatalf("Failed to accept invitation: %v", err)
+	}
+
+	user.IsActive = true
+	err = models.DealershipUsers.TxUpdate(tx, user)
+	if err != nil {
+		t.Fatalf("Failed to activate user: %v", err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+
+	found, ok, err := models.Invitations.GetByToken(invitation.Plaintext)
+	if err != nil || !ok {
+		t.Fatalf("Expected to find the invitation by token: ok=%v err=%v", ok, err)
+	}
+	if found.Status != InvitationStatuses.Pending {
+		t.Errorf("Expected a rolled back accept to leave the invitation pending, got %s", found.Status)
+	}
+}
+
+func TestInvitation_ResendReplacesToken(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	user := createTestDealershipUser(t, models, dealership.ID)
+
+	invitation := NewInvitation(user, nil)
+	err := models.Invitations.Insert(invitation)
+	if err != nil {
+		t.Fatalf("Failed to insert invitation: %v", err)
+	}
+	oldToken := invitation.Plaintext
+
+	err = models.Invitations.Resend(invitation)
+	if err != nil {
+		t.Fatalf("Failed to resend invitation: %v", err)
+	}
+
+	_, ok, err := models.Invitations.GetByToken(oldToken)
+	if err != nil {
+		t.Fatalf("Failed to look up old token: %v", err)
+	}
+	if ok {
+		t.Errorf("Expected the old link to stop working after a resend")
+	}
+
+	_, ok, err = models.Invitations.GetByToken(invitation.Plaintext)
+	if err != nil || !ok {
+		t.Errorf("Expected the new link to work: ok=%v err=%v", ok, err)
+	}
+
+	err = models.Invitations.Revoke(invitation)
+	if err != nil {
+		t.Fatalf("Failed to revoke invitation: %v", err)
+	}
+
+	err = models.Invitations.Resend(invitation)
+	if !errors.Is(err, ErrInvitationClosed) {
+		t.Errorf("Expected resending a revoked invitation to fail, got %v", err)
+	}
+
+	latest, ok, err := models.Invitations.GetLatestForUser(user)
+	if err != nil || !ok {
+		t.Fatalf("Expected to find the user's invitation: ok=%v err=%v", ok, err)
+	}
+	if latest.Status != InvitationStatuses.Revoked {
+		t.Errorf("Expected revoked status, got %s", latest.Status)
+	}
+}
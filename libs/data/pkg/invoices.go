@@ -30,12 +30,25 @@ var InvoiceStatuses = invoiceStatuses{
 	Void:  InvoiceStatus("void"),
 }
 
+// ErrInvoicePaid and ErrInvoiceSent are returned when a charge is added to a
+// project whose invoice the dealership already has. A sent invoice has to be
+// voided and issued again to take the charge.
+var (
+	ErrInvoicePaid = errors.New("the project's invoice has already been paid")
+	ErrInvoiceSent = errors.New("the project's invoice has already been sent; void it and issue a new one to bill the remake")
+)
+
 type Invoice struct {
 	StandardTable
 	ProjectID  int           `json:"project_id"`
 	InvoiceURL *string       `json:"invoice_url"`
 	Status     InvoiceStatus `json:"status"`
 	PaidAt     *time.Time    `json:"paid_at"`
+	// The totals are fixed when the invoice is created, from the project's
+	// order snapshots, kit charge and tax, and only grow by charged remakes.
+	SubtotalCents int `json:"subtotal_cents"`
+	TaxCents      int `json:"tax_cents"`
+	TotalCents    int `json:"total_cents"`
 }
 
 type InvoiceModel struct {
@@ -56,6 +69,10 @@ func invoiceFromGen(gen model.Invoices) *Invoice {
 		InvoiceURL: gen.InvoiceURL,
 		Status:     InvoiceStatus(gen.Status),
 		PaidAt:     gen.PaidAt,
+
+		SubtotalCents: int(gen.SubtotalCents),
+		TaxCents:      int(gen.TaxCents),
+		TotalCents:    int(gen.TotalCents),
 	}
 
 	return invoice
@@ -82,6 +99,10 @@ func invoiceToGen(i *Invoice) (*model.Invoices, error) {
 		CreatedAt:  i.CreatedAt,
 		UpdatedAt:  i.UpdatedAt,
 		Version:    int32(i.Version),
+
+		SubtotalCents: int32(i.SubtotalCents),
+		TaxCents:      int32(i.TaxCents),
+		TotalCents:    int32(i.TotalCents),
 	}
 
 	return gen, nil
@@ -98,6 +119,9 @@ func (m InvoiceModel) Insert(invoice *Invoice) error {
 		table.Invoices.InvoiceURL,
 		table.Invoices.Status,
 		table.Invoices.PaidAt,
+		table.Invoices.SubtotalCents,
+		table.Invoices.TaxCents,
+		table.Invoices.TotalCents,
 	).MODEL(gen).RETURNING(
 		table.Invoices.ID,
 		table.Invoices.UUID,
@@ -124,6 +148,105 @@ func (m InvoiceModel) Insert(invoice *Invoice) error {
 	return nil
 }
 
+// SetProjectTotals fills an invoice's totals from its project: the original
+// order's snapshots, the kit charge, any rush surcharge and any charged
+// remakes approved so far make the subtotal. The tax locked at order time is
+// added on top, along with each charged remake's tax at the order's locked
+// rate.
+func (m InvoiceModel) SetProjectTotals(invoice *Invoice) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var subtotal, tax int
+	var ratePercent float64
+	err := m.STDB.QueryRowContext(ctx, `
+		SELECT COALESCE((
+		           SELECT SUM(price_cents) FROM order_snapshots
+		           WHERE project_id = projects.id AND remake_id IS NULL
+		       ), 0)
+		     + COALESCE(projects.installation_kit_price_cents, 0)
+		     + COALESCE(projects.rush_surcharge_cents, 0),
+		       COALESCE(projects.tax_cents, 0),
+		       COALESCE(projects.tax_rate_percent, 0)
+		FROM projects
+		WHERE projects.id = $1
+	`, invoice.ProjectID).Scan(&subtotal, &tax, &ratePercent)
+	if err != nil {
+		return err
+	}
+
+	rows, err := m.STDB.QueryContext(ctx, `
+		SELECT order_snapshots.price_cents FROM order_snapshots
+		JOIN inlay_remakes ON inlay_remakes.id = order_snapshots.remake_id
+		WHERE order_snapshots.project_id = $1 AND inlay_remakes.billing = 'charged'
+	`, invoice.ProjectID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var priceCents int
+		if err := rows.Scan(&priceCents); err != nil {
+			return err
+		}
+		subtotal += priceCents
+		tax += ComputeTaxCents(priceCents, ratePercent)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	invoice.SubtotalCents = subtotal
+	invoice.TaxCents = tax
+	invoice.TotalCents = subtotal + tax
+
+	return nil
+}
+
+// TxAddRemakeCharge bills a charged remake, taxed at the rate locked on its
+// project when the order was placed (zero for an exempt order). A project not
+// invoiced yet picks the charge up when its invoice is created, and a draft
+// invoice takes it straight away. An invoice already sent or paid cannot
+// change under the dealership, so those get ErrInvoiceSent or ErrInvoicePaid.
+func (m InvoiceModel) TxAddRemakeCharge(tx *sql.Tx, projectID int, cents int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var invoiceID int
+	var status InvoiceStatus
+	var ratePercent float64
+	err := tx.QueryRowContext(ctx, `
+		SELECT invoices.id, invoices.status, COALESCE(projects.tax_rate_percent, 0)
+		FROM invoices
+		JOIN projects ON projects.id = invoices.project_id
+		WHERE invoices.project_id = $1 AND invoices.status != 'void'
+		FOR UPDATE OF invoices
+	`, projectID).Scan(&invoiceID, &status, &ratePercent)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	switch status {
+	case InvoiceStatuses.Paid:
+		return ErrInvoicePaid
+	case InvoiceStatuses.Sent:
+		return ErrInvoiceSent
+	}
+
+	taxCents := ComputeTaxCents(cents, ratePercent)
+	_, err = tx.ExecContext(ctx, `
+		UPDATE invoices
+		SET subtotal_cents = subtotal_cents + $2,
+		    tax_cents = tax_cents + $3,
+		    total_cents = total_cents + $2 + $3
+		WHERE id = $1
+	`, invoiceID, cents, taxCents)
+	return err
+}
+
 func (m InvoiceModel) GetByID(id int) (*Invoice, bool, error) {
 	query := postgres.SELECT(
 		table.Invoices.AllColumns,
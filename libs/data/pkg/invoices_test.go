@@ -1,6 +1,7 @@
 package data
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -228,3 +229,116 @@ func TestInvoice_GetAll(t *testing.T) {
 		t.Errorf("Expected at least 2 invoices, got %d", len(invoices))
 	}
 }
+
+func TestInvoice_RemakeCharges(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	project := createTestProject(t, models, dealership.ID)
+	priceGroup := createTestPriceGroup(t, models)
+	inlay := createTestInlay(t, models, project.ID)
+
+	taxCents, ratePercent := 800, 8.0
+	project.TaxCents = &taxCents
+	project.TaxRatePercent = &ratePercent
+	if err := models.Projects.Update(project); err != nil {
+		t.Fatalf("Failed to lock tax on project: %v", err)
+	}
+
+	err := models.OrderSnapshots.Insert(&OrderSnapshot{
+		ProjectID:    project.ID,
+		InlayID:      inlay.ID,
+		PriceGroupID: priceGroup.ID,
+		PriceCents:   10000,
+		Width:        10.0,
+		Height:       10.0,
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert order snapshot: %v", err)
+	}
+
+	invoice := &Invoice{ProjectID: project.ID, Status: InvoiceStatuses.Draft}
+	if err := models.Invoices.SetProjectTotals(invoice); err != nil {
+		t.Fatalf("Failed to total invoice: %v", err)
+	}
+	if err := models.Invoices.Insert(invoice); err != nil {
+		t.Fatalf("Failed to insert invoice: %v", err)
+	}
+
+	remake := &InlayRemake{InlayID: inlay.ID, Reason: "Cracked in transit"}
+	if err := models.InlayRemakes.Insert(remake, nil); err != nil {
+		t.Fatalf("Failed to insert remake: %v", err)
+	}
+
+	tx, err := models.STDB.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	charged := RemakeBillings.Charged
+	remake.Status = RemakeStatuses.Approved
+	remake.Billing = &charged
+	if err := models.InlayRemakes.TxUpdate(tx, remake); err != nil {
+		t.Fatalf("Failed to approve remake: %v", err)
+	}
+	err = models.OrderSnapshots.TxInsert(tx, &OrderSnapshot{
+		ProjectID:    project.ID,
+		InlayID:      inlay.ID,
+		PriceGroupID: priceGroup.ID,
+		PriceCents:   4000,
+		Width:        10.0,
+		Height:       10.0,
+		RemakeID:     &remake.ID,
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert remake snapshot: %v", err)
+	}
+	if err := models.Invoices.TxAddRemakeCharge(tx, project.ID, 4000); err != nil {
+		t.Fatalf("Failed to bill remake: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	billed, _, err := models.Invoices.GetByID(invoice.ID)
+	if err != nil {
+		t.Fatalf("Failed to get invoice: %v", err)
+	}
+	if billed.SubtotalCents != 14000 || billed.TaxCents != 1120 || billed.TotalCents != 15120 {
+		t.Errorf("Expected the remake added to the invoice with 8%% tax, got subtotal %d tax %d total %d", billed.SubtotalCents, billed.TaxCents, billed.TotalCents)
+	}
+
+	recomputed := &Invoice{ProjectID: project.ID}
+	if err := models.Invoices.SetProjectTotals(recomputed); err != nil {
+		t.Fatalf("Failed to total invoice: %v", err)
+	}
+	if recomputed.SubtotalCents != billed.SubtotalCents || recomputed.TaxCents != billed.TaxCents || recomputed.TotalCents != billed.TotalCents {
+		t.Errorf("Expected the project totals to match the invoice, got %+v and %+v", recomputed, billed)
+	}
+
+	for _, tt := range []struct {
+		status InvoiceStatus
+		want   error
+	}{
+		{InvoiceStatuses.Sent, ErrInvoiceSent},
+		{InvoiceStatuses.Paid, ErrInvoicePaid},
+	} {
+		billed.Status = tt.status
+		if err := models.Invoices.Update(billed); err != nil {
+			t.Fatalf("Failed to mark invoice %s: %v", tt.status, err)
+		}
+
+		tx, err := models.STDB.Begin()
+		if err != nil {
+			t.Fatalf("Failed to begin transaction: %v", err)
+		}
+
+		err = models.Invoices.TxAddRemakeCharge(tx, project.ID, 4000)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Expected a %s invoice to refuse the charge, got %v", tt.status, err)
+		}
+		tx.Rollback()
+	}
+}
@@ -0,0 +1,296 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type JobRunStatus string
+
+type jobRunStatuses struct {
+	Running   JobRunStatus
+	Succeeded JobRunStatus
+	Failed    JobRunStatus
+}
+
+var JobRunStatuses = jobRunStatuses{
+	Running:   JobRunStatus("running"),
+	Succeeded: JobRunStatus("succeeded"),
+	Failed:    JobRunStatus("failed"),
+}
+
+type JobRunTrigger string
+
+type jobRunTriggers struct {
+	Schedule JobRunTrigger
+	Manual   JobRunTrigger
+}
+
+var JobRunTriggers = jobRunTriggers{
+	Schedule: JobRunTrigger("schedule"),
+	Manual:   JobRunTrigger("manual"),
+}
+
+// JobRun is one run of a background maintenance job. ScheduledFor is the
+// schedule tick a scheduled run was for; manual runs have none and record
+// who started them instead.
+type JobRun struct {
+	ID                        int           `json:"id"`
+	UUID                      string        `json:"uuid"`
+	JobName                   string        `json:"job_name"`
+	Trigger                   JobRunTrigger `json:"trigger"`
+	ScheduledFor              *time.Time    `json:"scheduled_for"`
+	TriggeredByInternalUserID *int          `json:"triggered_by_internal_user_id"`
+	Status                    JobRunStatus  `json:"status"`
+	RowsAffected              int64         `json:"rows_affected"`
+	Error                     *string       `json:"error"`
+	StartedAt                 time.Time     `json:"started_at"`
+	FinishedAt                *time.Time    `json:"finished_at"`
+}
+
+type JobRunModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func jobRunFromGen(gen model.JobRuns) *JobRun {
+	var triggeredBy *int
+	if gen.TriggeredByInternalUserID != nil {
+		id := int(*gen.TriggeredByInternalUserID)
+		triggeredBy = &id
+	}
+
+	return &JobRun{
+		ID:                        int(gen.ID),
+		UUID:                      gen.UUID.String(),
+		JobName:                   gen.JobName,
+		Trigger:                   JobRunTrigger(gen.Trigger),
+		ScheduledFor:              gen.ScheduledFor,
+		TriggeredByInternalUserID: triggeredBy,
+		Status:                    JobRunStatus(gen.Status),
+		RowsAffected:              gen.RowsAffected,
+		Error:                     gen.Error,
+		StartedAt:                 gen.StartedAt,
+		FinishedAt:                gen.FinishedAt,
+	}
+}
+
+// JobLock is a job's Postgres advisory lock. Whichever instance holds it is
+// the only one running the job; the rest skip it until it is released.
+type JobLock struct {
+	conn    *sql.Conn
+	jobName string
+}
+
+// TryLock takes the advisory lock for jobName without waiting, reporting
+// false when another instance holds it. The lock belongs to a connection, so
+// it is held on one taken from the pool until Release, and an instance that
+// dies lets it go with its connection.
+func (m JobRunModel) TryLock(jobName string) (*JobLock, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	conn, err := m.STDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var locked bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext('job:' || $1))`, jobName).Scan(&locked)
+	if err != nil || !locked {
+		conn.Close()
+		return nil, false, err
+	}
+
+	return &JobLock{conn: conn, jobName: jobName}, true, nil
+}
+
+// Release gives up the lock and returns its connection to the pool. If the
+// unlock fails the connection is thrown away instead, which releases the lock
+// with it.
+func (l *JobLock) Release() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock(hashtext('job:' || $1))`, l.jobName)
+	if err != nil {
+		l.conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	l.conn.Close()
+
+	return err
+}
+
+// FailAbandoned marks runs of jobName still recorded as running as failed.
+// Only the holder of the job's lock may call it: any run it finds belonged to
+// an instance that stopped before finishing.
+func (m JobRunModel) FailAbandoned(jobName string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.STDB.ExecContext(ctx, `
+		UPDATE job_runs
+		SET status = 'failed', error = 'abandoned: the instance running it stopped', finished_at = now()
+		WHERE job_name = $1 AND status = 'running'
+	`, jobName)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Start records run as running. It reports false, recording nothing, when
+// run is for a schedule tick that has already been run.
+func (m JobRunModel) Start(run *JobRun) (bool, error) {
+	var triggeredBy *int32
+	if run.TriggeredByInternalUserID != nil {
+		id := int32(*run.TriggeredByInternalUserID)
+		triggeredBy = &id
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.JobRuns
+	err := m.STDB.QueryRowContext(ctx, `
+		INSERT INTO job_runs (job_name, trigger, scheduled_for, triggered_by_internal_user_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (job_name, scheduled_for) WHERE scheduled_for IS NOT NULL DO NOTHING
+		RETURNING id, uuid, status, started_at
+	`, run.JobName, string(run.Trigger), run.ScheduledFor, triggeredBy).Scan(&dest.ID, &dest.UUID, &dest.Status, &dest.StartedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	run.ID = int(dest.ID)
+	run.UUID = dest.UUID.String()
+	run.Status = JobRunStatus(dest.Status)
+	run.StartedAt = dest.StartedAt
+
+	return true, nil
+}
+
+// Finish records how a run ended: succeeded with the rows it cleaned up, or
+// failed with runErr.
+func (m JobRunModel) Finish(run *JobRun, rowsAffected int64, runErr error) error {
+	run.Status = JobRunStatuses.Succeeded
+	run.RowsAffected = rowsAffected
+	run.Error = nil
+	if runErr != nil {
+		message := runErr.Error()
+		run.Status = JobRunStatuses.Failed
+		run.Error = &message
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var finishedAt time.Time
+	err := m.STDB.QueryRowContext(ctx, `
+		UPDATE job_runs SET status = $2, rows_affected = $3, error = $4, finished_at = now()
+		WHERE id = $1
+		RETURNING finished_at
+	`, run.ID, string(run.Status), run.RowsAffected, run.Error).Scan(&finishedAt)
+	if err != nil {
+		return err
+	}
+
+	run.FinishedAt = &finishedAt
+
+	return nil
+}
+
+// DeleteFinishedBefore removes runs that finished before cutoff and returns
+// how many there were. Each job's latest run is kept however old, so the jobs
+// list can still say when it last ran.
+func (m JobRunModel) DeleteFinishedBefore(cutoff time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := m.STDB.ExecContext(ctx, `
+		DELETE FROM job_runs j
+		WHERE j.finished_at < $1
+		  AND EXISTS (
+			SELECT 1 FROM job_runs newer
+			WHERE newer.job_name = j.job_name
+			  AND (newer.started_at, newer.id) > (j.started_at, j.id)
+		  )
+	`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetLatestForEachJob maps each job that has run to its most recent run.
+func (m JobRunModel) GetLatestForEachJob() (map[string]*JobRun, error) {
+	query := postgres.SELECT(
+		table.JobRuns.AllColumns,
+	).DISTINCT(
+		table.JobRuns.JobName,
+	).FROM(
+		table.JobRuns,
+	).ORDER_BY(
+		table.JobRuns.JobName,
+		table.JobRuns.StartedAt.DESC(),
+		table.JobRuns.ID.DESC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.JobRuns
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return nil, err
+	}
+
+	latest := make(map[string]*JobRun, len(dest))
+	for _, d := range dest {
+		latest[d.JobName] = jobRunFromGen(d)
+	}
+
+	return latest, nil
+}
+
+// GetForJob lists a job's runs newest first.
+func (m JobRunModel) GetForJob(jobName string, limit int) ([]*JobRun, error) {
+	query := postgres.SELECT(
+		table.JobRuns.AllColumns,
+	).FROM(
+		table.JobRuns,
+	).WHERE(
+		table.JobRuns.JobName.EQ(postgres.String(jobName)),
+	).ORDER_BY(
+		table.JobRuns.StartedAt.DESC(),
+		table.JobRuns.ID.DESC(),
+	).LIMIT(int64(limit))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.JobRuns
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return nil, err
+	}
+
+	runs := make([]*JobRun, len(dest))
+	for i, d := range dest {
+		runs[i] = jobRunFromGen(d)
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,142 @@
+package data
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRunModel_TryLockIsExclusive(t *testing.T) {
+	models := getTestModels(t)
+
+	lock, locked, err := models.JobRuns.TryLock("test-job")
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	_, locked, err = models.JobRuns.TryLock("test-job")
+	require.NoError(t, err)
+	assert.False(t, locked, "a held lock cannot be taken again")
+
+	other, locked, err := models.JobRuns.TryLock("other-job")
+	require.NoError(t, err)
+	assert.True(t, locked, "each job has its own lock")
+	require.NoError(t, other.Release())
+
+	require.NoError(t, lock.Release())
+
+	lock, locked, err = models.JobRuns.TryLock("test-job")
+	require.NoError(t, err)
+	assert.True(t, locked, "a released lock can be taken again")
+	require.NoError(t, lock.Release())
+}
+
+func TestJobRunModel_ScheduledTickRunsOnce(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	tick := time.Date(2026, time.March, 4, 10, 15, 0, 0, time.UTC)
+
+	run := &JobRun{JobName: "test-job", Trigger: JobRunTriggers.Schedule, ScheduledFor: &tick}
+	started, err := models.JobRuns.Start(run)
+	require.NoError(t, err)
+	require.True(t, started)
+	assert.Equal(t, JobRunStatuses.Running, run.Status)
+
+	require.NoError(t, models.JobRuns.Finish(run, 7, nil))
+
+	again := &JobRun{JobName: "test-job", Trigger: JobRunTriggers.Schedule, ScheduledFor: &tick}
+	started, err = models.JobRuns.Start(again)
+	require.NoError(t, err)
+	assert.False(t, started, "another instance does not repeat a tick")
+
+	admin := createTestInternalUser(t, models)
+	for range 2 {
+		manual := &JobRun{JobName: "test-job", Trigger: JobRunTriggers.Manual, TriggeredByInternalUserID: &admin.ID}
+		started, err = models.JobRuns.Start(manual)
+		require.NoError(t, err)
+		assert.True(t, started, "manual runs are never deduplicated")
+		require.NoError(t, models.JobRuns.Finish(manual, 0, errors.New("boom")))
+	}
+
+	runs, err := models.JobRuns.GetForJob("test-job", 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+	assert.Equal(t, JobRunStatuses.Failed, runs[0].Status)
+	require.NotNil(t, runs[0].Error)
+	assert.Equal(t, "boom", *runs[0].Error)
+	assert.Equal(t, &admin.ID, runs[0].TriggeredByInternalUserID)
+
+	scheduled := runs[2]
+	assert.Equal(t, JobRunStatuses.Succeeded, scheduled.Status)
+	assert.Equal(t, int64(7), scheduled.RowsAffected)
+	assert.NotNil(t, scheduled.FinishedAt)
+}
+
+func TestJobRunModel_FailAbandonedAndLatest(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+
+	finished := &JobRun{JobName: "first-job", Trigger: JobRunTriggers.Manual}
+	_, err := models.JobRuns.Start(finished)
+	require.NoError(t, err)
+	require.NoError(t, models.JobRuns.Finish(finished, 1, nil))
+
+	abandoned := &JobRun{JobName: "first-job", Trigger: JobRunTriggers.Manual}
+	_, err = models.JobRuns.Start(abandoned)
+	require.NoError(t, err)
+
+	running := &JobRun{JobName: "second-job", Trigger: JobRunTriggers.Manual}
+	_, err = models.JobRuns.Start(running)
+	require.NoError(t, err)
+
+	failed, err := models.JobRuns.FailAbandoned("first-job")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), failed, "only the job's own running runs are failed")
+
+	latest, err := models.JobRuns.GetLatestForEachJob()
+	require.NoError(t, err)
+	require.Len(t, latest, 2)
+	assert.Equal(t, abandoned.UUID, latest["first-job"].UUID)
+	assert.Equal(t, JobRunStatuses.Failed, latest["first-job"].Status)
+	assert.Equal(t, JobRunStatuses.Running, latest["second-job"].Status)
+}
+
+func TestJobRunModel_DeleteFinishedBeforeKeepsLatest(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+
+	var runs []*JobRun
+	for range 3 {
+		run := &JobRun{JobName: "test-job", Trigger: JobRunTriggers.Manual}
+		_, err := models.JobRuns.Start(run)
+		require.NoError(t, err)
+		require.NoError(t, models.JobRuns.Finish(run, 0, nil))
+		runs = append(runs, run)
+	}
+
+	running := &JobRun{JobName: "other-job", Trigger: JobRunTriggers.Manual}
+	_, err := models.JobRuns.Start(running)
+	require.NoError(t, err)
+
+	deleted, err := models.JobRuns.DeleteFinishedBefore(time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deleted, "recent runs are kept")
+
+	deleted, err = models.JobRuns.DeleteFinishedBefore(time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted, "only the job's latest run survives")
+
+	remaining, err := models.JobRuns.GetForJob("test-job", 10)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, runs[2].UUID, remaining[0].UUID)
+
+	remaining, err = models.JobRuns.GetForJob("other-job", 10)
+	require.NoError(t, err)
+	assert.Len(t, remaining, 1, "unfinished runs are never deleted")
+}
@@ -0,0 +1,168 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MaterialReservationStatus string
+
+type materialReservationStatuses struct {
+	Reserved MaterialReservationStatus
+	Consumed MaterialReservationStatus
+	Released MaterialReservationStatus
+}
+
+var MaterialReservationStatuses = materialReservationStatuses{
+	Reserved: MaterialReservationStatus("reserved"),
+	Consumed: MaterialReservationStatus("consumed"),
+	Released: MaterialReservationStatus("released"),
+}
+
+// MaterialReservation holds one inlay's usage of one stock row, in the stock's
+// unit, from order placement until the inlay reaches materials-prep.
+type MaterialReservation struct {
+	StandardTable
+	MaterialStockID int                       `json:"material_stock_id"`
+	ProjectID       int                       `json:"project_id"`
+	InlayID         int                       `json:"inlay_id"`
+	Quantity        float64                   `json:"quantity"`
+	Status          MaterialReservationStatus `json:"status"`
+	ConsumedAt      *time.Time                `json:"consumed_at"`
+}
+
+type MaterialReservationModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func materialReservationFromGen(gen model.MaterialReservations) *MaterialReservation {
+	return &MaterialReservation{
+		StandardTable: StandardTable{
+			ID:        int(gen.ID),
+			UUID:      gen.UUID.String(),
+			CreatedAt: gen.CreatedAt,
+			UpdatedAt: gen.UpdatedAt,
+			Version:   int(gen.Version),
+		},
+		MaterialStockID: int(gen.MaterialStockID),
+		ProjectID:       int(gen.ProjectID),
+		InlayID:         int(gen.InlayID),
+		Quantity:        gen.Quantity,
+		Status:          MaterialReservationStatus(gen.Status),
+		ConsumedAt:      gen.ConsumedAt,
+	}
+}
+
+// TxReserve records a reservation. An inlay that was ordered, cancelled and
+// ordered again reuses its row for the same stock rather than stacking a second
+// one.
+func (m MaterialReservationModel) TxReserve(tx *sql.Tx, reservation *MaterialReservation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO material_reservations (material_stock_id, project_id, inlay_id, quantity)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (material_stock_id, inlay_id)
+		DO UPDATE SET project_id = $2, quantity = $4, status = 'reserved', consumed_at = NULL
+		RETURNING id, uuid, status, created_at, updated_at, version
+	`, reservation.MaterialStockID, reservation.ProjectID, reservation.InlayID, reservation.Quantity).Scan(
+		&reservation.ID,
+		&reservation.UUID,
+		&reservation.Status,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+		&reservation.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	reservation.ConsumedAt = nil
+	return nil
+}
+
+func (m MaterialReservationModel) GetByInlayID(inlayID int) ([]*MaterialReservation, error) {
+	query := postgres.SELECT(
+		table.MaterialReservations.AllColumns,
+	).FROM(
+		table.MaterialReservations,
+	).WHERE(
+		table.MaterialReservations.InlayID.EQ(postgres.Int(int64(inlayID))),
+	).ORDER_BY(
+		table.MaterialReservations.MaterialStockID.ASC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.MaterialReservations
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations := make([]*MaterialReservation, len(dest))
+	for i, d := range dest {
+		reservations[i] = materialReservationFromGen(d)
+	}
+
+	return reservations, nil
+}
+
+// TxConsumeForInlay marks an inlay's open reservations consumed and takes their
+// quantities off on_hand in the same statement, returning the ids of the stock
+// rows it touched. Consuming twice is a no-op: only reserved rows move.
+func (m MaterialReservationModel) TxConsumeForInlay(tx *sql.Tx, inlayID int) ([]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := tx.QueryContext(ctx, `
+		WITH consumed AS (
+			UPDATE material_reservations SET status = 'consumed', consumed_at = now()
+			WHERE inlay_id = $1 AND status = 'reserved'
+			RETURNING material_stock_id, quantity
+		)
+		UPDATE material_stocks SET on_hand = material_stocks.on_hand - totals.quantity
+		FROM (
+			SELECT material_stock_id, SUM(quantity) AS quantity FROM consumed
+			GROUP BY material_stock_id
+		) totals
+		WHERE material_stocks.id = totals.material_stock_id
+		RETURNING material_stocks.id
+	`, inlayID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stockIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		stockIDs = append(stockIDs, id)
+	}
+	return stockIDs, rows.Err()
+}
+
+// TxReleaseForProject returns every open reservation of a project to stock.
+// Consumed reservations stay consumed: that glass has already been cut.
+func (m MaterialReservationModel) TxReleaseForProject(tx *sql.Tx, projectID int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE material_reservations SET status = 'released'
+		WHERE project_id = $1 AND status = 'reserved'
+	`, projectID)
+	return err
+}
@@ -0,0 +1,390 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MaterialUnit string
+
+type materialUnits struct {
+	Sheets       MaterialUnit
+	SquareInches MaterialUnit
+}
+
+var MaterialUnits = materialUnits{
+	Sheets:       MaterialUnit("sheets"),
+	SquareInches: MaterialUnit("square_inches"),
+}
+
+// MaterialStock is the count production keeps for one glass color or grout —
+// exactly one of GlassColorID and GroutID is set. OnHand and LowStockThreshold
+// are in Unit. Reserved and Available are derived from open reservations on
+// every read and are never written.
+type MaterialStock struct {
+	StandardTable
+	GlassColorID       *int         `json:"glass_color_id"`
+	GroutID            *int         `json:"grout_id"`
+	Unit               MaterialUnit `json:"unit"`
+	SheetArea          *float64     `json:"sheet_area"`
+	OnHand             float64      `json:"on_hand"`
+	LowStockThreshold  float64      `json:"low_stock_threshold"`
+	LowStockNotifiedAt *time.Time   `json:"low_stock_notified_at"`
+	Reserved           float64      `json:"reserved"`
+	Available          float64      `json:"available"`
+}
+
+// FromSquareInches converts a design usage into this stock's unit.
+func (s *MaterialStock) FromSquareInches(area float64) float64 {
+	if s.Unit == MaterialUnits.Sheets && s.SheetArea != nil && *s.SheetArea > 0 {
+		return area / *s.SheetArea
+	}
+	return area
+}
+
+// IsLowStock reports whether available stock has fallen to the threshold. A
+// zero threshold means production has not asked to be warned.
+func (s *MaterialStock) IsLowStock() bool {
+	return s.LowStockThreshold > 0 && s.Available <= s.LowStockThreshold
+}
+
+type MaterialStockModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func materialStockFromGen(gen model.MaterialStocks) *MaterialStock {
+	var glassColorID *int
+	if gen.GlassColorID != nil {
+		id := int(*gen.GlassColorID)
+		glassColorID = &id
+	}
+	var groutID *int
+	if gen.GroutID != nil {
+		id := int(*gen.GroutID)
+		groutID = &id
+	}
+
+	return &MaterialStock{
+		StandardTable: StandardTable{
+			ID:        int(gen.ID),
+			UUID:      gen.UUID.String(),
+			CreatedAt: gen.CreatedAt,
+			UpdatedAt: gen.UpdatedAt,
+			Version:   int(gen.Version),
+		},
+		GlassColorID:       glassColorID,
+		GroutID:            groutID,
+		Unit:               MaterialUnit(gen.Unit),
+		SheetArea:          gen.SheetArea,
+		OnHand:             gen.OnHand,
+		LowStockThreshold:  gen.LowStockThreshold,
+		LowStockNotifiedAt: gen.LowStockNotifiedAt,
+	}
+}
+
+func materialStockToGen(s *MaterialStock) (*model.MaterialStocks, error) {
+	var stockUUID uuid.UUID
+	var err error
+
+	if s.UUID != "" {
+		stockUUID, err = uuid.Parse(s.UUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var glassColorID *int32
+	if s.GlassColorID != nil {
+		id := int32(*s.GlassColorID)
+		glassColorID = &id
+	}
+	var groutID *int32
+	if s.GroutID != nil {
+		id := int32(*s.GroutID)
+		groutID = &id
+	}
+
+	unit := s.Unit
+	if unit == "" {
+		unit = MaterialUnits.SquareInches
+	}
+
+	return &model.MaterialStocks{
+		ID:                 int32(s.ID),
+		UUID:               stockUUID,
+		GlassColorID:       glassColorID,
+		GroutID:            groutID,
+		Unit:               string(unit),
+		SheetArea:          s.SheetArea,
+		OnHand:             s.OnHand,
+		LowStockThreshold:  s.LowStockThreshold,
+		LowStockNotifiedAt: s.LowStockNotifiedAt,
+		UpdatedAt:          s.UpdatedAt,
+		CreatedAt:          s.CreatedAt,
+		Version:            int32(s.Version),
+	}, nil
+}
+
+func (m MaterialStockModel) Insert(stock *MaterialStock) error {
+	gen, err := materialStockToGen(stock)
+	if err != nil {
+		return err
+	}
+
+	query := table.MaterialStocks.INSERT(
+		table.MaterialStocks.GlassColorID,
+		table.MaterialStocks.GroutID,
+		table.MaterialStocks.Unit,
+		table.MaterialStocks.SheetArea,
+		table.MaterialStocks.OnHand,
+		table.MaterialStocks.LowStockThreshold,
+	).MODEL(
+		gen,
+	).RETURNING(
+		table.MaterialStocks.ID,
+		table.MaterialStocks.UUID,
+		table.MaterialStocks.Unit,
+		table.MaterialStocks.UpdatedAt,
+		table.MaterialStocks.CreatedAt,
+		table.MaterialStocks.Version,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.MaterialStocks
+	err = query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return err
+	}
+
+	stock.ID = int(dest.ID)
+	stock.UUID = dest.UUID.String()
+	stock.Unit = MaterialUnit(dest.Unit)
+	stock.UpdatedAt = dest.UpdatedAt
+	stock.CreatedAt = dest.CreatedAt
+	stock.Version = int(dest.Version)
+
+	return m.withAvailability(stock)
+}
+
+func (m MaterialStockModel) getOne(condition postgres.BoolExpression) (*MaterialStock, bool, error) {
+	query := postgres.SELECT(
+		table.MaterialStocks.AllColumns,
+	).FROM(
+		table.MaterialStocks,
+	).WHERE(
+		condition,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.MaterialStocks
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		switch {
+		case errors.Is(err, qrm.ErrNoRows):
+			return nil, false, nil
+		default:
+			return nil, false, err
+		}
+	}
+
+	stock := materialStockFromGen(dest)
+	if err := m.withAvailability(stock); err != nil {
+		return nil, false, err
+	}
+	return stock, true, nil
+}
+
+func (m MaterialStockModel) GetByID(id int) (*MaterialStock, bool, error) {
+	return m.getOne(table.MaterialStocks.ID.EQ(postgres.Int(int64(id))))
+}
+
+func (m MaterialStockModel) GetByUUID(uuidStr string) (*MaterialStock, bool, error) {
+	parsedUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, false, err
+	}
+	return m.getOne(table.MaterialStocks.UUID.EQ(postgres.UUID(parsedUUID)))
+}
+
+func (m MaterialStockModel) GetByGlassColorID(glassColorID int) (*MaterialStock, bool, error) {
+	return m.getOne(table.MaterialStocks.GlassColorID.EQ(postgres.Int(int64(glassColorID))))
+}
+
+func (m MaterialStockModel) GetByGroutID(groutID int) (*MaterialStock, bool, error) {
+	return m.getOne(table.MaterialStocks.GroutID.EQ(postgres.Int(int64(groutID))))
+}
+
+// GetAll returns every stock row, glass before grout, each with its open
+// reservations folded in.
+func (m MaterialStockModel) GetAll() ([]*MaterialStock, error) {
+	query := postgres.SELECT(
+		table.MaterialStocks.AllColumns,
+	).FROM(
+		table.MaterialStocks,
+	).ORDER_BY(
+		table.MaterialStocks.GroutID.ASC().NULLS_FIRST(),
+		table.MaterialStocks.GlassColorID.ASC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.MaterialStocks
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	reserved, err := m.reservedByStock()
+	if err != nil {
+		return nil, err
+	}
+
+	stocks := make([]*MaterialStock, len(dest))
+	for i, d := range dest {
+		stocks[i] = materialStockFromGen(d)
+		stocks[i].Reserved = reserved[stocks[i].ID]
+		stocks[i].Available = stocks[i].OnHand - stocks[i].Reserved
+	}
+
+	return stocks, nil
+}
+
+// Update writes the counted fields. Restocking above the threshold clears
+// low_stock_notified_at so the next dip warns production again.
+func (m MaterialStockModel) Update(stock *MaterialStock) error {
+	if err := m.withAvailability(stock); err != nil {
+		return err
+	}
+	if !stock.IsLowStock() {
+		stock.LowStockNotifiedAt = nil
+	}
+
+	gen, err := materialStockToGen(stock)
+	if err != nil {
+		return err
+	}
+
+	query := table.MaterialStocks.UPDATE(
+		table.MaterialStocks.Unit,
+		table.MaterialStocks.SheetArea,
+		table.MaterialStocks.OnHand,
+		table.MaterialStocks.LowStockThreshold,
+		table.MaterialStocks.LowStockNotifiedAt,
+		table.MaterialStocks.Version,
+	).MODEL(
+		gen,
+	).WHERE(
+		postgres.AND(
+			table.MaterialStocks.ID.EQ(postgres.Int(int64(stock.ID))),
+			table.MaterialStocks.Version.EQ(postgres.Int(int64(stock.Version))),
+		),
+	).RETURNING(
+		table.MaterialStocks.UpdatedAt,
+		table.MaterialStocks.Version,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.MaterialStocks
+	err = query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return err
+	}
+
+	stock.UpdatedAt = dest.UpdatedAt
+	stock.Version = int(dest.Version)
+
+	return nil
+}
+
+// MarkLowStockNotified claims the low-stock warning for a stock row. It returns
+// false when another request already claimed it, so concurrent orders dipping
+// under the threshold notify production only once.
+func (m MaterialStockModel) MarkLowStockNotified(id int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	res, err := m.STDB.ExecContext(ctx, `
+		UPDATE material_stocks SET low_stock_notified_at = now()
+		WHERE id = $1 AND low_stock_notified_at IS NULL
+	`, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (m MaterialStockModel) Delete(id int) error {
+	query := table.MaterialStocks.DELETE().WHERE(
+		table.MaterialStocks.ID.EQ(postgres.Int(int64(id))),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := query.ExecContext(ctx, m.STDB)
+	return err
+}
+
+func (m MaterialStockModel) withAvailability(stock *MaterialStock) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.STDB.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(quantity), 0) FROM material_reservations
+		WHERE material_stock_id = $1 AND status = 'reserved'
+	`, stock.ID).Scan(&stock.Reserved)
+	if err != nil {
+		return err
+	}
+
+	stock.Available = stock.OnHand - stock.Reserved
+	return nil
+}
+
+func (m MaterialStockModel) reservedByStock() (map[int]float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.STDB.QueryContext(ctx, `
+		SELECT material_stock_id, SUM(quantity) FROM material_reservations
+		WHERE status = 'reserved'
+		GROUP BY material_stock_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reserved := map[int]float64{}
+	for rows.Next() {
+		var id int
+		var quantity float64
+		if err := rows.Scan(&id, &quantity); err != nil {
+			return nil, err
+		}
+		reserved[id] = quantity
+	}
+	return reserved, rows.Err()
+}
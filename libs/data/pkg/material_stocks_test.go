@@ -0,0 +1,190 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+func createTestGlassStock(t *testing.T, models Models, onHand, threshold float64) (*GlassColor, *MaterialStock) {
+	t.Helper()
+
+	gc := &GlassColor{Name: "Amber", Hex: "#c07a1a", IsActive: true, Opacity: 1}
+	if err := models.GlassColors.Insert(gc); err != nil {
+		t.Fatalf("Failed to insert glass color: %v", err)
+	}
+
+	sheetArea := 432.0
+	stock := &MaterialStock{
+		GlassColorID:      &gc.ID,
+		Unit:              MaterialUnits.Sheets,
+		SheetArea:         &sheetArea,
+		OnHand:            onHand,
+		LowStockThreshold: threshold,
+	}
+	if err := models.MaterialStocks.Insert(stock); err != nil {
+		t.Fatalf("Failed to insert material stock: %v", err)
+	}
+	return gc, stock
+}
+
+func reserveForInlay(t *testing.T, models Models, stock *MaterialStock, projectID, inlayID int, quantity float64) {
+	t.Helper()
+
+	tx, err := models.STDB.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	r := &MaterialReservation{MaterialStockID: stock.ID, ProjectID: projectID, InlayID: inlayID, Quantity: quantity}
+	if err := models.MaterialReservations.TxReserve(tx, r); err != nil {
+		t.Fatalf("Failed to reserve: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+}
+
+func TestMaterialStock_InsertAndLookupByMaterial(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+	models := getTestModels(t)
+
+	gc, stock := createTestGlassStock(t, models, 10, 2)
+
+	got, found, err := models.MaterialStocks.GetByGlassColorID(gc.ID)
+	if err != nil || !found {
+		t.Fatalf("Expected stock for glass color, found=%v err=%v", found, err)
+	}
+	if got.ID != stock.ID || got.Unit != MaterialUnits.Sheets || got.OnHand != 10 {
+		t.Errorf("Unexpected stock: %+v", got)
+	}
+	if got.Available != 10 {
+		t.Errorf("Expected 10 available with no reservations, got %v", got.Available)
+	}
+	if math.Abs(got.FromSquareInches(216)-0.5) > 1e-9 {
+		t.Errorf("Expected 216 sq in to be half a 432 sq in sheet, got %v", got.FromSquareInches(216))
+	}
+
+	_, found, err = models.MaterialStocks.GetByGroutID(gc.ID)
+	if err != nil || found {
+		t.Errorf("Expected no grout stock, found=%v err=%v", found, err)
+	}
+}
+
+func TestMaterialStock_RequiresExactlyOneMaterial(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+	models := getTestModels(t)
+
+	if err := models.MaterialStocks.Insert(&MaterialStock{OnHand: 1}); err == nil {
+		t.Errorf("Expected insert without a material to fail")
+	}
+}
+
+func TestMaterialReservation_ReserveConsumeRelease(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+	models := getTestModels(t)
+
+	dealership := createTestDealership(t, models)
+	project := createTestProject(t, models, dealership.ID)
+	inlayA := createTestInlay(t, models, project.ID)
+	inlayB := createTestInlay(t, models, project.ID)
+	_, stock := createTestGlassStock(t, models, 10, 0)
+
+	reserveForInlay(t, models, stock, project.ID, inlayA.ID, 1.5)
+	reserveForInlay(t, models, stock, project.ID, inlayB.ID, 2)
+	// Re-reserving the same inlay replaces its quantity rather than stacking.
+	reserveForInlay(t, models, stock, project.ID, inlayA.ID, 1)
+
+	got, _, err := models.MaterialStocks.GetByID(stock.ID)
+	if err != nil {
+		t.Fatalf("Failed to get stock: %v", err)
+	}
+	if got.Reserved != 3 || got.Available != 7 || got.OnHand != 10 {
+		t.Errorf("Expected reserved=3 available=7 on_hand=10, got %+v", got)
+	}
+
+	tx, err := models.STDB.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	stockIDs, err := models.MaterialReservations.TxConsumeForInlay(tx, inlayA.ID)
+	if err != nil {
+		t.Fatalf("Failed to consume: %v", err)
+	}
+	again, err := models.MaterialReservations.TxConsumeForInlay(tx, inlayA.ID)
+	if err != nil {
+		t.Fatalf("Failed to consume twice: %v", err)
+	}
+	if err := models.MaterialReservations.TxReleaseForProject(tx, project.ID); err != nil {
+		t.Fatalf("Failed to release: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	if len(stockIDs) != 1 || stockIDs[0] != stock.ID {
+		t.Errorf("Expected consumption to touch stock %d, got %v", stock.ID, stockIDs)
+	}
+	if len(again) != 0 {
+		t.Errorf("Expected second consumption to be a no-op, got %v", again)
+	}
+
+	got, _, err = models.MaterialStocks.GetByID(stock.ID)
+	if err != nil {
+		t.Fatalf("Failed to get stock: %v", err)
+	}
+	if got.OnHand != 9 || got.Reserved != 0 || got.Available != 9 {
+		t.Errorf("Expected on_hand=9 with nothing reserved after consume+release, got %+v", got)
+	}
+
+	reservations, err := models.MaterialReservations.GetByInlayID(inlayB.ID)
+	if err != nil {
+		t.Fatalf("Failed to get reservations: %v", err)
+	}
+	if len(reservations) != 1 || reservations[0].Status != MaterialReservationStatuses.Released {
+		t.Errorf("Expected inlay B's reservation released, got %+v", reservations)
+	}
+}
+
+func TestMaterialStock_LowStockNotifiesOnceUntilRestocked(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+	models := getTestModels(t)
+
+	dealership := createTestDealership(t, models)
+	project := createTestProject(t, models, dealership.ID)
+	inlay := createTestInlay(t, models, project.ID)
+	_, stock := createTestGlassStock(t, models, 5, 2)
+
+	reserveForInlay(t, models, stock, project.ID, inlay.ID, 4)
+
+	got, _, err := models.MaterialStocks.GetByID(stock.ID)
+	if err != nil {
+		t.Fatalf("Failed to get stock: %v", err)
+	}
+	if !got.IsLowStock() {
+		t.Fatalf("Expected 1 available against a threshold of 2 to be low")
+	}
+
+	claimed, err := models.MaterialStocks.MarkLowStockNotified(stock.ID)
+	if err != nil || !claimed {
+		t.Fatalf("Expected first claim to succeed, claimed=%v err=%v", claimed, err)
+	}
+	claimed, err = models.MaterialStocks.MarkLowStockNotified(stock.ID)
+	if err != nil || claimed {
+		t.Fatalf("Expected second claim to be refused, claimed=%v err=%v", claimed, err)
+	}
+
+	got, _, err = models.MaterialStocks.GetByID(stock.ID)
+	if err != nil {
+		t.Fatalf("Failed to get stock: %v", err)
+	}
+	got.OnHand = 20
+	if err := models.MaterialStocks.Update(got); err != nil {
+		t.Fatalf("Failed to restock: %v", err)
+	}
+
+	claimed, err = models.MaterialStocks.MarkLowStockNotified(stock.ID)
+	if err != nil || !claimed {
+		t.Errorf("Expected restock to re-arm the warning, claimed=%v err=%v", claimed, err)
+	}
+}
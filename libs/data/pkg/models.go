@@ -10,27 +10,44 @@ type Models struct {
 	CatalogItems            CatalogItemModel
 	Dashboard               DashboardModel
 	DealershipAccounts      DealershipAccountModel
+	DealershipGroups        DealershipGroupModel
+	DealershipPriceTiers    DealershipPriceTierModel
+	DealershipRoles         RoleModel
 	DealershipTokens        DealershipTokenModel
 	DealershipUsers         DealershipUserModel
 	Dealerships             DealershipModel
 	GlassColors             GlassColorModel
 	Grouts                  GroutModel
+	Impersonations          ImpersonationModel
 	InlayMilestones         InlayMilestoneModel
 	InlayProofs             InlayProofModel
+	InlayRemakes            InlayRemakeModel
 	InlayUpdates            InlayUpdateModel
 	Inlays                  InlayModel
 	InternalAccounts        InternalAccountModel
+	InternalRoles           RoleModel
 	InternalTokens          InternalTokenModel
 	InternalUsers           InternalUserModel
+	Invitations             InvitationModel
 	Invoices                InvoiceModel
+	JobRuns                 JobRunModel
+	MaterialReservations    MaterialReservationModel
+	MaterialStocks          MaterialStockModel
 	Notifications           NotificationModel
 	NotificationPreferences NotificationPreferencesModel
+	OIDCProviders           OIDCProviderModel
 	OrderSnapshots          OrderSnapshotModel
 	PriceGroups             PriceGroupModel
 	ProjectChats            ProjectChatModel
+	ProjectQuotes           ProjectQuoteModel
 	ProjectWatchers         ProjectWatcherModel
 	Projects                ProjectModel
+	RateLimits              RateLimitModel
+	Shipments               ShipmentModel
+	ShippingAddresses       ShippingAddressModel
 	SupportArticles         SupportArticleModel
+	TaxRates                TaxRateModel
+	TwoFactor               TwoFactorModel
 	Pool                    *pgxpool.Pool
 	STDB                    *sql.DB
 }
@@ -40,27 +57,44 @@ func NewModels(db *pgxpool.Pool, stdb *sql.DB) Models {
 		CatalogItems:            CatalogItemModel{DB: db, STDB: stdb},
 		Dashboard:               DashboardModel{DB: db, STDB: stdb},
 		DealershipAccounts:      DealershipAccountModel{DB: db, STDB: stdb},
+		DealershipGroups:        DealershipGroupModel{DB: db, STDB: stdb},
+		DealershipPriceTiers:    DealershipPriceTierModel{DB: db, STDB: stdb},
+		DealershipRoles:         RoleModel{DB: db, STDB: stdb, tables: dealershipRoleTables},
 		DealershipTokens:        DealershipTokenModel{DB: db, STDB: stdb},
 		DealershipUsers:         DealershipUserModel{DB: db, STDB: stdb},
 		Dealerships:             DealershipModel{DB: db, STDB: stdb},
 		GlassColors:             GlassColorModel{DB: db, STDB: stdb},
 		Grouts:                  GroutModel{DB: db, STDB: stdb},
+		Impersonations:          ImpersonationModel{DB: db, STDB: stdb},
 		InlayMilestones:         InlayMilestoneModel{DB: db, STDB: stdb},
 		InlayProofs:             InlayProofModel{DB: db, STDB: stdb},
+		InlayRemakes:            InlayRemakeModel{DB: db, STDB: stdb},
 		InlayUpdates:            InlayUpdateModel{DB: db, STDB: stdb},
 		Inlays:                  InlayModel{DB: db, STDB: stdb},
 		InternalAccounts:        InternalAccountModel{DB: db, STDB: stdb},
+		InternalRoles:           RoleModel{DB: db, STDB: stdb, tables: internalRoleTables},
 		InternalTokens:          InternalTokenModel{DB: db, STDB: stdb},
 		InternalUsers:           InternalUserModel{DB: db, STDB: stdb},
+		Invitations:             InvitationModel{DB: db, STDB: stdb},
 		Invoices:                InvoiceModel{DB: db, STDB: stdb},
+		JobRuns:                 JobRunModel{DB: db, STDB: stdb},
+		MaterialReservations:    MaterialReservationModel{DB: db, STDB: stdb},
+		MaterialStocks:          MaterialStockModel{DB: db, STDB: stdb},
 		Notifications:           NotificationModel{DB: db, STDB: stdb},
 		NotificationPreferences: NotificationPreferencesModel{DB: db, STDB: stdb},
+		OIDCProviders:           OIDCProviderModel{DB: db, STDB: stdb},
 		OrderSnapshots:          OrderSnapshotModel{DB: db, STDB: stdb},
 		PriceGroups:             PriceGroupModel{DB: db, STDB: stdb},
 		ProjectChats:            ProjectChatModel{DB: db, STDB: stdb},
+		ProjectQuotes:           ProjectQuoteModel{DB: db, STDB: stdb},
 		ProjectWatchers:         ProjectWatcherModel{DB: db, STDB: stdb},
 		Projects:                ProjectModel{DB: db, STDB: stdb},
+		RateLimits:              RateLimitModel{DB: db, STDB: stdb},
+		Shipments:               ShipmentModel{DB: db, STDB: stdb},
+		ShippingAddresses:       ShippingAddressModel{DB: db, STDB: stdb},
 		SupportArticles:         SupportArticleModel{DB: db, STDB: stdb},
+		TaxRates:                TaxRateModel{DB: db, STDB: stdb},
+		TwoFactor:               TwoFactorModel{DB: db, STDB: stdb},
 		Pool:                    db,
 		STDB:                    stdb,
 	}
@@ -31,6 +31,10 @@ type notificationEventTypes struct {
 	InvoiceVoided          NotificationEventType
 	PaymentReceived        NotificationEventType
 	ChatMessage            NotificationEventType
+	LowStock               NotificationEventType
+	RemakeRequested        NotificationEventType
+	RemakeReviewed         NotificationEventType
+	RushOrderPlaced        NotificationEventType
 }
 
 var NotificationEventTypes = notificationEventTypes{
@@ -48,6 +52,10 @@ var NotificationEventTypes = notificationEventTypes{
 	InvoiceVoided:          NotificationEventType("invoice_voided"),
 	PaymentReceived:        NotificationEventType("payment_received"),
 	ChatMessage:            NotificationEventType("chat_message"),
+	LowStock:               NotificationEventType("low_stock"),
+	RemakeRequested:        NotificationEventType("remake_requested"),
+	RemakeReviewed:         NotificationEventType("remake_reviewed"),
+	RushOrderPlaced:        NotificationEventType("rush_order_placed"),
 }
 
 type Notification struct {
@@ -453,6 +461,22 @@ func (m NotificationModel) MarkEmailSent(id int) error {
 	return err
 }
 
+// DeleteReadBefore removes notifications that were read before cutoff and
+// returns how many there were. Unread notifications are kept however old.
+func (m NotificationModel) DeleteReadBefore(cutoff time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := m.STDB.ExecContext(ctx,
+		"DELETE FROM notifications WHERE read_at < $1",
+		cutoff,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 func (m NotificationModel) Delete(id int) error {
 	query := table.Notifications.DELETE().WHERE(
 		table.Notifications.ID.EQ(postgres.Int(int64(id))),
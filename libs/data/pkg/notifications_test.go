@@ -304,6 +304,59 @@ func TestNotification_Delete(t *testing.T) {
 	}
 }
 
+func TestNotification_DeleteReadBefore(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	user := createTestDealershipUser(t, models, dealership.ID)
+
+	var notifications []*Notification
+	for range 2 {
+		notification := &Notification{
+			DealershipUserID: &user.ID,
+			EventType:        NotificationEventTypes.ChatMessage,
+			Title:            "New Message",
+			Body:             "You have a new message",
+		}
+
+		err := models.Notifications.Insert(notification)
+		if err != nil {
+			t.Fatalf("Failed to insert: %v", err)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	err := models.Notifications.MarkRead(notifications[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to mark as read: %v", err)
+	}
+
+	deleted, err := models.Notifications.DeleteReadBefore(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to delete read notifications: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("Expected recently read notifications to be kept, got %d deleted", deleted)
+	}
+
+	deleted, err = models.Notifications.DeleteReadBefore(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to delete read notifications: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 read notification deleted, got %d", deleted)
+	}
+
+	_, found, err := models.Notifications.GetByID(notifications[1].ID)
+	if err != nil {
+		t.Fatalf("Failed to get unread notification: %v", err)
+	}
+	if !found {
+		t.Errorf("Expected unread notification to be kept")
+	}
+}
+
 // Helper functions
 func intPtr(i int) *int {
 	return &i
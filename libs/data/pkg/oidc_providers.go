@@ -0,0 +1,417 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ReservedProviderKeys are the providers the API signs in with itself, which a
+// registered provider cannot be named after.
+var ReservedProviderKeys = []string{"google", "microsoft", "magic_link", "email"}
+
+var providerKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+// ValidateProviderKey checks a key is usable in a login route and is not one
+// of ReservedProviderKeys.
+func ValidateProviderKey(key string) error {
+	if !providerKeyPattern.MatchString(key) {
+		return errors.New("key must be 2-63 lowercase letters, digits or dashes")
+	}
+	if slices.Contains(ReservedProviderKeys, key) {
+		return fmt.Errorf("%q is reserved", key)
+	}
+	return nil
+}
+
+// OIDCProvider is a registered identity provider. It signs in users of the
+// dealerships in DealershipIDs only. ClientSecret is sealed; see
+// SetClientSecret and OpenClientSecret.
+type OIDCProvider struct {
+	StandardTable
+	Key                string `json:"key"`
+	Name               string `json:"name"`
+	DiscoveryURL       string `json:"discovery_url"`
+	ClientID           string `json:"client_id"`
+	ClientSecret       []byte `json:"-"`
+	Scopes             string `json:"scopes"`
+	SubjectClaim       string `json:"subject_claim"`
+	EmailClaim         string `json:"email_claim"`
+	EmailVerifiedClaim string `json:"email_verified_claim"`
+	IsActive           bool   `json:"is_active"`
+	DealershipIDs      []int  `json:"dealership_ids"`
+}
+
+// OIDCProviderKey derives the key client secrets are sealed with from the
+// API's auth secret.
+func OIDCProviderKey(authSecret string) []byte {
+	return secretKey("oidc-provider", authSecret)
+}
+
+func (p *OIDCProvider) SetClientSecret(key []byte, secret string) error {
+	sealed, err := sealSecret(key, secret)
+	if err != nil {
+		return err
+	}
+	p.ClientSecret = sealed
+	return nil
+}
+
+func (p *OIDCProvider) OpenClientSecret(key []byte) (string, error) {
+	return openSecret(key, p.ClientSecret)
+}
+
+// AllowsDealership reports whether the provider may sign in users of a
+// dealership.
+func (p *OIDCProvider) AllowsDealership(dealershipID int) bool {
+	return slices.Contains(p.DealershipIDs, dealershipID)
+}
+
+// ScopeList splits Scopes for the authorization request.
+func (p *OIDCProvider) ScopeList() []string {
+	return strings.Fields(p.Scopes)
+}
+
+// OIDCIdentity is who a provider says signed in, read from its userinfo
+// claims.
+type OIDCIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// MapClaims reads an identity out of userinfo claims using the provider's
+// claim names. A provider that does not send the verified claim is trusted
+// to have verified the email; one that sends it false is not.
+func (p *OIDCProvider) MapClaims(claims map[string]any) (*OIDCIdentity, error) {
+	identity := &OIDCIdentity{EmailVerified: true}
+
+	switch subject := claims[p.SubjectClaim].(type) {
+	case string:
+		identity.Subject = subject
+	case float64:
+		identity.Subject = fmt.Sprint(int64(subject))
+	}
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("userinfo has no %q claim", p.SubjectClaim)
+	}
+
+	identity.Email, _ = claims[p.EmailClaim].(string)
+
+	if p.EmailVerifiedClaim != "" {
+		switch verified := claims[p.EmailVerifiedClaim].(type) {
+		case bool:
+			identity.EmailVerified = verified
+		case string:
+			identity.EmailVerified = verified == "true"
+		}
+	}
+
+	return identity, nil
+}
+
+type OIDCProviderModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func oidcProviderFromGen(gen model.OidcProviders) *OIDCProvider {
+	return &OIDCProvider{
+		StandardTable: StandardTable{
+			ID:        int(gen.ID),
+			UUID:      gen.UUID.String(),
+			CreatedAt: gen.CreatedAt,
+			UpdatedAt: gen.UpdatedAt,
+			Version:   int(gen.Version),
+		},
+		Key:                gen.Key,
+		Name:               gen.Name,
+		DiscoveryURL:       gen.DiscoveryURL,
+		ClientID:           gen.ClientID,
+		ClientSecret:       gen.ClientSecret,
+		Scopes:             gen.Scopes,
+		SubjectClaim:       gen.SubjectClaim,
+		EmailClaim:         gen.EmailClaim,
+		EmailVerifiedClaim: gen.EmailVerifiedClaim,
+		IsActive:           gen.IsActive,
+		DealershipIDs:      []int{},
+	}
+}
+
+func oidcProviderToGen(p *OIDCProvider) (*model.OidcProviders, error) {
+	var providerUUID uuid.UUID
+	var err error
+
+	if p.UUID != "" {
+		providerUUID, err = uuid.Parse(p.UUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scopes := p.Scopes
+	if scopes == "" {
+		scopes = "openid email profile"
+	}
+	subjectClaim := p.SubjectClaim
+	if subjectClaim == "" {
+		subjectClaim = "sub"
+	}
+	emailClaim := p.EmailClaim
+	if emailClaim == "" {
+		emailClaim = "email"
+	}
+
+	return &model.OidcProviders{
+		ID:                 int32(p.ID),
+		UUID:               providerUUID,
+		Key:                p.Key,
+		Name:               p.Name,
+		DiscoveryURL:       p.DiscoveryURL,
+		ClientID:           p.ClientID,
+		ClientSecret:       p.ClientSecret,
+		Scopes:             scopes,
+		SubjectClaim:       subjectClaim,
+		EmailClaim:         emailClaim,
+		EmailVerifiedClaim: p.EmailVerifiedClaim,
+		IsActive:           p.IsActive,
+		UpdatedAt:          p.UpdatedAt,
+		CreatedAt:          p.CreatedAt,
+		Version:            int32(p.Version),
+	}, nil
+}
+
+// Insert stores the provider and the dealerships it is enabled for.
+func (m OIDCProviderModel) Insert(provider *OIDCProvider) error {
+	gen, err := oidcProviderToGen(provider)
+	if err != nil {
+		return err
+	}
+
+	query := table.OidcProviders.INSERT(
+		table.OidcProviders.Key,
+		table.OidcProviders.Name,
+		table.OidcProviders.DiscoveryURL,
+		table.OidcProviders.ClientID,
+		table.OidcProviders.ClientSecret,
+		table.OidcProviders.Scopes,
+		table.OidcProviders.SubjectClaim,
+		table.OidcProviders.EmailClaim,
+		table.OidcProviders.EmailVerifiedClaim,
+		table.OidcProviders.IsActive,
+	).MODEL(gen).RETURNING(
+		table.OidcProviders.AllColumns,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var dest model.OidcProviders
+	err = query.QueryContext(ctx, tx, &dest)
+	if err != nil {
+		return err
+	}
+
+	dealershipIDs := provider.DealershipIDs
+	*provider = *oidcProviderFromGen(dest)
+
+	err = setProviderDealerships(ctx, tx, provider, dealershipIDs)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setProviderDealerships(ctx context.Context, tx *sql.Tx, provider *OIDCProvider, dealershipIDs []int) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM oidc_provider_dealerships WHERE oidc_provider_id = $1`, provider.ID)
+	if err != nil {
+		return err
+	}
+
+	provider.DealershipIDs = []int{}
+	for _, dealershipID := range dealershipIDs {
+		if slices.Contains(provider.DealershipIDs, dealershipID) {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO oidc_provider_dealerships (oidc_provider_id, dealership_id) VALUES ($1, $2)
+		`, provider.ID, dealershipID)
+		if err != nil {
+			return err
+		}
+
+		provider.DealershipIDs = append(provider.DealershipIDs, dealershipID)
+	}
+
+	return nil
+}
+
+func (m OIDCProviderModel) getWhere(condition postgres.BoolExpression) ([]*OIDCProvider, error) {
+	query := postgres.SELECT(
+		table.OidcProviders.AllColumns,
+		table.OidcProviderDealerships.DealershipID,
+	).FROM(
+		table.OidcProviders.LEFT_JOIN(
+			table.OidcProviderDealerships,
+			table.OidcProviderDealerships.OidcProviderID.EQ(table.OidcProviders.ID),
+		),
+	).WHERE(
+		condition,
+	).ORDER_BY(
+		table.OidcProviders.Name.ASC(),
+		table.OidcProviderDealerships.DealershipID.ASC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []struct {
+		model.OidcProviders
+		Dealerships []model.OidcProviderDealerships
+	}
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return nil, err
+	}
+
+	providers := make([]*OIDCProvider, len(dest))
+	for i, d := range dest {
+		providers[i] = oidcProviderFromGen(d.OidcProviders)
+		for _, dealership := range d.Dealerships {
+			providers[i].DealershipIDs = append(providers[i].DealershipIDs, int(dealership.DealershipID))
+		}
+	}
+
+	return providers, nil
+}
+
+func (m OIDCProviderModel) getOne(condition postgres.BoolExpression) (*OIDCProvider, bool, error) {
+	providers, err := m.getWhere(condition)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(providers) == 0 {
+		return nil, false, nil
+	}
+	return providers[0], true, nil
+}
+
+func (m OIDCProviderModel) GetByUUID(uuidStr string) (*OIDCProvider, bool, error) {
+	parsedUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return m.getOne(table.OidcProviders.UUID.EQ(postgres.UUID(parsedUUID)))
+}
+
+func (m OIDCProviderModel) GetByKey(key string) (*OIDCProvider, bool, error) {
+	return m.getOne(table.OidcProviders.Key.EQ(postgres.String(key)))
+}
+
+func (m OIDCProviderModel) GetAll() ([]*OIDCProvider, error) {
+	return m.getWhere(postgres.Bool(true))
+}
+
+// GetActive lists the providers offered on the login page.
+func (m OIDCProviderModel) GetActive() ([]*OIDCProvider, error) {
+	return m.getWhere(table.OidcProviders.IsActive.IS_TRUE())
+}
+
+// Update saves the provider and replaces its dealerships. The key cannot
+// change, since linked accounts are stored under it.
+func (m OIDCProviderModel) Update(provider *OIDCProvider) error {
+	gen, err := oidcProviderToGen(provider)
+	if err != nil {
+		return err
+	}
+
+	query := table.OidcProviders.UPDATE(
+		table.OidcProviders.Name,
+		table.OidcProviders.DiscoveryURL,
+		table.OidcProviders.ClientID,
+		table.OidcProviders.ClientSecret,
+		table.OidcProviders.Scopes,
+		table.OidcProviders.SubjectClaim,
+		table.OidcProviders.EmailClaim,
+		table.OidcProviders.EmailVerifiedClaim,
+		table.OidcProviders.IsActive,
+	).MODEL(gen).WHERE(
+		postgres.AND(
+			table.OidcProviders.ID.EQ(postgres.Int(int64(provider.ID))),
+			table.OidcProviders.Version.EQ(postgres.Int(int64(provider.Version))),
+		),
+	).RETURNING(
+		table.OidcProviders.UpdatedAt,
+		table.OidcProviders.Version,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var dest model.OidcProviders
+	err = query.QueryContext(ctx, tx, &dest)
+	if err != nil {
+		return err
+	}
+
+	provider.UpdatedAt = dest.UpdatedAt
+	provider.Version = int(dest.Version)
+
+	err = setProviderDealerships(ctx, tx, provider, provider.DealershipIDs)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the provider along with the identities linked through it.
+func (m OIDCProviderModel) Delete(provider *OIDCProvider) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM dealership_accounts WHERE type = 'oidc' AND provider = $1`, provider.Key)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM oidc_providers WHERE id = $1`, provider.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
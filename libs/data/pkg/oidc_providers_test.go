@@ -0,0 +1,126 @@
+package data
+
+import (
+	"testing"
+)
+
+func TestOIDCProvider_MapClaims(t *testing.T) {
+	provider := &OIDCProvider{SubjectClaim: "oid", EmailClaim: "upn", EmailVerifiedClaim: "email_verified"}
+
+	identity, err := provider.MapClaims(map[string]any{"oid": "abc", "upn": "a@example.com"})
+	if err != nil {
+		t.Fatalf("Failed to map claims: %v", err)
+	}
+	if identity.Subject != "abc" || identity.Email != "a@example.com" || !identity.EmailVerified {
+		t.Errorf("Expected the mapped claims with the email trusted, got %+v", identity)
+	}
+
+	identity, err = provider.MapClaims(map[string]any{"oid": float64(42), "upn": "a@example.com", "email_verified": false})
+	if err != nil {
+		t.Fatalf("Failed to map claims: %v", err)
+	}
+	if identity.Subject != "42" || identity.EmailVerified {
+		t.Errorf("Expected a numeric subject and an unverified email, got %+v", identity)
+	}
+
+	_, err = provider.MapClaims(map[string]any{"sub": "abc"})
+	if err == nil {
+		t.Errorf("Expected an error when the subject claim is missing")
+	}
+}
+
+func TestValidateProviderKey(t *testing.T) {
+	for _, key := range []string{"acme-sso", "okta2"} {
+		if err := ValidateProviderKey(key); err != nil {
+			t.Errorf("Expected %q to be accepted, got %v", key, err)
+		}
+	}
+
+	for _, key := range []string{"google", "magic_link", "Acme", "a", "-acme", "acme/sso"} {
+		if err := ValidateProviderKey(key); err == nil {
+			t.Errorf("Expected %q to be refused", key)
+		}
+	}
+}
+
+func TestOIDCProviderModel_CRUD(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	other := createTestDealership(t, models)
+	user := createTestDealershipUser(t, models, dealership.ID)
+
+	key := OIDCProviderKey("test-secret")
+	provider := &OIDCProvider{
+		Key:                "acme",
+		Name:               "Acme SSO",
+		DiscoveryURL:       "https://idp.example.com/.well-known/openid-configuration",
+		ClientID:           "client",
+		EmailVerifiedClaim: "email_verified",
+		IsActive:           true,
+		DealershipIDs:      []int{dealership.ID},
+	}
+	err := provider.SetClientSecret(key, "shh")
+	if err != nil {
+		t.Fatalf("Failed to seal client secret: %v", err)
+	}
+
+	err = models.OIDCProviders.Insert(provider)
+	if err != nil {
+		t.Fatalf("Failed to insert provider: %v", err)
+	}
+	if provider.Scopes != "openid email profile" || provider.SubjectClaim != "sub" {
+		t.Errorf("Expected default scopes and subject claim, got %q and %q", provider.Scopes, provider.SubjectClaim)
+	}
+
+	found, ok, err := models.OIDCProviders.GetByKey("acme")
+	if err != nil || !ok {
+		t.Fatalf("Failed to get provider by key: ok=%v err=%v", ok, err)
+	}
+	if !found.AllowsDealership(dealership.ID) || found.AllowsDealership(other.ID) {
+		t.Errorf("Expected the provider to be enabled for the first dealership only, got %v", found.DealershipIDs)
+	}
+
+	secret, err := found.OpenClientSecret(key)
+	if err != nil || secret != "shh" {
+		t.Errorf("Expected the client secret to round-trip, got %q (%v)", secret, err)
+	}
+
+	found.DealershipIDs = []int{other.ID}
+	err = models.OIDCProviders.Update(found)
+	if err != nil {
+		t.Fatalf("Failed to update provider: %v", err)
+	}
+
+	found, _, err = models.OIDCProviders.GetByUUID(provider.UUID)
+	if err != nil {
+		t.Fatalf("Failed to get provider by uuid: %v", err)
+	}
+	if found.AllowsDealership(dealership.ID) || !found.AllowsDealership(other.ID) {
+		t.Errorf("Expected the dealerships to be replaced, got %v", found.DealershipIDs)
+	}
+
+	err = models.DealershipAccounts.Insert(&DealershipAccount{
+		DealershipUserID:  user.ID,
+		Type:              "oidc",
+		Provider:          "acme",
+		ProviderAccountID: "subject",
+	})
+	if err != nil {
+		t.Fatalf("Failed to link account: %v", err)
+	}
+
+	err = models.OIDCProviders.Delete(found)
+	if err != nil {
+		t.Fatalf("Failed to delete provider: %v", err)
+	}
+
+	_, ok, err = models.DealershipAccounts.GetByProvider("acme", "subject")
+	if err != nil {
+		t.Fatalf("Failed to get account: %v", err)
+	}
+	if ok {
+		t.Errorf("Expected accounts linked through the provider to be removed with it")
+	}
+}
@@ -22,11 +22,19 @@ type OrderSnapshot struct {
 	ProofID              *int                `json:"proof_id"`
 	PriceGroupID         int                 `json:"price_group_id"`
 	PriceCents           int                 `json:"price_cents"`
+	ListPriceCents       int                 `json:"list_price_cents"`
+	TierDiscountCents    int                 `json:"tier_discount_cents"`
+	PriceTierID          *int                `json:"price_tier_id"`
 	PriceAdjustmentType  PriceAdjustmentType `json:"price_adjustment_type"`
 	PriceAdjustmentValue float64             `json:"price_adjustment_value"`
 	Width                float64             `json:"width"`
 	Height               float64             `json:"height"`
+	RemakeID             *int                `json:"remake_id"`
+	QuoteID              *int                `json:"quote_id"`
 	CreatedAt            time.Time           `json:"created_at"`
+	// ShipTo is where the inlay was sent when it was ordered. It is nil only on
+	// snapshots written without one; see OrderSnapshotModel.TxGetShipTo.
+	ShipTo *ShipTo `json:"ship_to"`
 }
 
 type OrderSnapshotModel struct {
@@ -41,6 +49,12 @@ func orderSnapshotFromGen(genSnapshot model.OrderSnapshots) *OrderSnapshot {
 		proofID = &v
 	}
 
+	var remakeID *int
+	if genSnapshot.RemakeID != nil {
+		v := int(*genSnapshot.RemakeID)
+		remakeID = &v
+	}
+
 	snapshot := OrderSnapshot{
 		ID:                   int(genSnapshot.ID),
 		UUID:                 genSnapshot.UUID.String(),
@@ -49,10 +63,16 @@ func orderSnapshotFromGen(genSnapshot model.OrderSnapshots) *OrderSnapshot {
 		ProofID:              proofID,
 		PriceGroupID:         int(genSnapshot.PriceGroupID),
 		PriceCents:           int(genSnapshot.PriceCents),
+		ListPriceCents:       int(genSnapshot.ListPriceCents),
+		TierDiscountCents:    int(genSnapshot.TierDiscountCents),
+		PriceTierID:          intPtrFromGen(genSnapshot.PriceTierID),
 		PriceAdjustmentType:  PriceAdjustmentType(genSnapshot.PriceAdjustmentType),
 		PriceAdjustmentValue: genSnapshot.PriceAdjustmentValue,
 		Width:                genSnapshot.Width,
 		Height:               genSnapshot.Height,
+		RemakeID:             remakeID,
+		QuoteID:              intPtrFromGen(genSnapshot.QuoteID),
+		ShipTo:               shipToFromGen(genSnapshot.ShipTo),
 		CreatedAt:            genSnapshot.CreatedAt,
 	}
 
@@ -76,11 +96,29 @@ func orderSnapshotToGen(os *OrderSnapshot) (*model.OrderSnapshots, error) {
 		proofID = &v
 	}
 
+	var remakeID *int32
+	if os.RemakeID != nil {
+		v := int32(*os.RemakeID)
+		remakeID = &v
+	}
+
+	// Net is list less the tier discount, so a snapshot priced without a tier
+	// lists at what it charges.
+	listPriceCents := os.ListPriceCents
+	if listPriceCents == 0 {
+		listPriceCents = os.PriceCents + os.TierDiscountCents
+	}
+
 	adjustmentType := string(os.PriceAdjustmentType)
 	if adjustmentType == "" {
 		adjustmentType = string(PriceAdjustmentTypes.None)
 	}
 
+	shipTo, err := shipToToGen(os.ShipTo)
+	if err != nil {
+		return nil, err
+	}
+
 	genSnapshot := model.OrderSnapshots{
 		ID:                   int32(os.ID),
 		UUID:                 snapshotUUID,
@@ -89,10 +127,16 @@ func orderSnapshotToGen(os *OrderSnapshot) (*model.OrderSnapshots, error) {
 		ProofID:              proofID,
 		PriceGroupID:         int32(os.PriceGroupID),
 		PriceCents:           int32(os.PriceCents),
+		ListPriceCents:       int32(listPriceCents),
+		TierDiscountCents:    int32(os.TierDiscountCents),
+		PriceTierID:          intPtrToGen(os.PriceTierID),
 		PriceAdjustmentType:  adjustmentType,
 		PriceAdjustmentValue: os.PriceAdjustmentValue,
 		Width:                os.Width,
 		Height:               os.Height,
+		RemakeID:             remakeID,
+		QuoteID:              intPtrToGen(os.QuoteID),
+		ShipTo:               shipTo,
 		CreatedAt:            os.CreatedAt,
 	}
 
@@ -111,10 +155,16 @@ func (m OrderSnapshotModel) insertSnapshot(ctx context.Context, executor qrm.Que
 		table.OrderSnapshots.ProofID,
 		table.OrderSnapshots.PriceGroupID,
 		table.OrderSnapshots.PriceCents,
+		table.OrderSnapshots.ListPriceCents,
+		table.OrderSnapshots.TierDiscountCents,
+		table.OrderSnapshots.PriceTierID,
 		table.OrderSnapshots.PriceAdjustmentType,
 		table.OrderSnapshots.PriceAdjustmentValue,
 		table.OrderSnapshots.Width,
 		table.OrderSnapshots.Height,
+		table.OrderSnapshots.RemakeID,
+		table.OrderSnapshots.QuoteID,
+		table.OrderSnapshots.ShipTo,
 	).MODEL(
 		genSnapshot,
 	).RETURNING(
@@ -129,6 +179,7 @@ func (m OrderSnapshotModel) insertSnapshot(ctx context.Context, executor qrm.Que
 		return err
 	}
 
+	orderSnapshot.ListPriceCents = int(genSnapshot.ListPriceCents)
 	orderSnapshot.ID = int(dest.ID)
 	orderSnapshot.UUID = dest.UUID.String()
 	orderSnapshot.CreatedAt = dest.CreatedAt
@@ -207,13 +258,80 @@ func (m OrderSnapshotModel) GetByUUID(uuidStr string) (*OrderSnapshot, bool, err
 	return orderSnapshotFromGen(dest), true, nil
 }
 
+// GetByInlayID returns the snapshot taken when the inlay was first ordered.
+// Remakes carry their own snapshots; see GetByRemakeID.
 func (m OrderSnapshotModel) GetByInlayID(inlayID int) (*OrderSnapshot, bool, error) {
 	query := postgres.SELECT(
 		table.OrderSnapshots.AllColumns,
 	).FROM(
 		table.OrderSnapshots,
 	).WHERE(
-		table.OrderSnapshots.InlayID.EQ(postgres.Int(int64(inlayID))),
+		postgres.AND(
+			table.OrderSnapshots.InlayID.EQ(postgres.Int(int64(inlayID))),
+			table.OrderSnapshots.RemakeID.IS_NULL(),
+		),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.OrderSnapshots
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		switch {
+		case errors.Is(err, qrm.ErrNoRows):
+			return nil, false, nil
+		default:
+			return nil, false, err
+		}
+	}
+
+	return orderSnapshotFromGen(dest), true, nil
+}
+
+// TxGetShipTo returns where an inlay's original order was sent: the ship-to
+// captured in its snapshot, or the dealership's own address when the snapshot
+// has none. Remakes go back to the same place.
+func (m OrderSnapshotModel) TxGetShipTo(tx *sql.Tx, inlayID int) (ShipTo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var captured *string
+	var fallback ShipTo
+	err := tx.QueryRowContext(ctx, `
+		SELECT os.ship_to, d.name, d.street, d.street_ext, d.city, d.state, d.postal_code, d.country
+		FROM inlays i
+		JOIN projects p ON p.id = i.project_id
+		JOIN dealerships d ON d.id = p.dealership_id
+		LEFT JOIN order_snapshots os ON os.inlay_id = i.id AND os.remake_id IS NULL
+		WHERE i.id = $1
+	`, inlayID).Scan(
+		&captured,
+		&fallback.Recipient,
+		&fallback.Street,
+		&fallback.StreetExt,
+		&fallback.City,
+		&fallback.State,
+		&fallback.PostalCode,
+		&fallback.Country,
+	)
+	if err != nil {
+		return ShipTo{}, err
+	}
+
+	if shipTo := shipToFromGen(captured); shipTo != nil {
+		return *shipTo, nil
+	}
+	return fallback, nil
+}
+
+func (m OrderSnapshotModel) GetByRemakeID(remakeID int) (*OrderSnapshot, bool, error) {
+	query := postgres.SELECT(
+		table.OrderSnapshots.AllColumns,
+	).FROM(
+		table.OrderSnapshots,
+	).WHERE(
+		table.OrderSnapshots.RemakeID.EQ(postgres.Int(int64(remakeID))),
 	)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -233,6 +351,8 @@ func (m OrderSnapshotModel) GetByInlayID(inlayID int) (*OrderSnapshot, bool, err
 	return orderSnapshotFromGen(dest), true, nil
 }
 
+// GetByProjectID returns every snapshot taken for the project, remakes
+// included; a remake's snapshot has RemakeID set.
 func (m OrderSnapshotModel) GetByProjectID(projectID int) ([]*OrderSnapshot, error) {
 	query := postgres.SELECT(
 		table.OrderSnapshots.AllColumns,
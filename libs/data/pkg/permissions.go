@@ -25,5 +25,10 @@ const (
 	ActionManageDealerships   = "manage_dealerships"
 	ActionManageSupport       = "manage_support"
 	ActionManageMaterials     = "manage_materials"
+	ActionManageRemakes       = "manage_remakes"
+	ActionManageTaxes         = "manage_taxes"
+	ActionImpersonate         = "impersonate"
+	ActionManageRoles         = "manage_roles"
+	ActionManageJobs          = "manage_jobs"
 	ActionAccessAdmin         = "access_admin"
 )
@@ -9,6 +9,16 @@ import "math"
 // libs/data/src/installation-kits.ts.
 const InstallationKitPriceCents = 9500
 
+// DefaultRushSurchargePercent is the rush surcharge when RUSH_SURCHARGE_PERCENT
+// is not set.
+const DefaultRushSurchargePercent = 25
+
+// ComputeRushSurchargeCents is the rush charge on an order: a percentage of
+// its inlays, rounded to the nearest cent. The kit is not marked up.
+func ComputeRushSurchargeCents(inlaysCents int, percent float64) int {
+	return max(0, int(math.Round(float64(inlaysCents)*percent/100)))
+}
+
 // ComputeAdjustedPriceCents applies a proof's price adjustment to a price
 // group's base price. For "percent", adjValue is percentage points
 // (20 = +20%); for "fixed", adjValue is cents (1221 = +$12.21). Adjustments may
@@ -24,3 +34,21 @@ func ComputeAdjustedPriceCents(baseCents int, adjType PriceAdjustmentType, adjVa
 		return baseCents
 	}
 }
+
+// TieredPrice is a price as a dealership pays it: the list price, the
+// dealership's tier discount off it, and the net charged.
+type TieredPrice struct {
+	ListCents     int
+	DiscountCents int
+	NetCents      int
+}
+
+// ComputeTieredPrice prices a price group for a dealership. The proof's
+// adjustment applies on top of both the list base and the tier's base, so the
+// discount is the difference the tier makes to the final price. A nil tier
+// pays list.
+func ComputeTieredPrice(tier *DealershipPriceTier, priceGroupID, listBaseCents int, adjType PriceAdjustmentType, adjValue float64) TieredPrice {
+	list := ComputeAdjustedPriceCents(listBaseCents, adjType, adjValue)
+	net := ComputeAdjustedPriceCents(tier.BasePriceCents(priceGroupID, listBaseCents), adjType, adjValue)
+	return TieredPrice{ListCents: list, DiscountCents: list - net, NetCents: net}
+}
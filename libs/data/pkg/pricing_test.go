@@ -1,6 +1,9 @@
 package data
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestComputeAdjustedPriceCents(t *testing.T) {
 	tests := []struct {
@@ -31,3 +34,134 @@ func TestComputeAdjustedPriceCents(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeTieredPrice(t *testing.T) {
+	tier := &DealershipPriceTier{
+		DiscountPercent: 10,
+		Overrides:       []PriceTierOverride{{PriceGroupID: 2, PriceCents: 7000}},
+	}
+
+	tests := []struct {
+		name         string
+		tier         *DealershipPriceTier
+		priceGroupID int
+		adjType      PriceAdjustmentType
+		adjValue     float64
+		want         TieredPrice
+	}{
+		{"no tier pays list", nil, 1, PriceAdjustmentTypes.None, 0, TieredPrice{ListCents: 10000, DiscountCents: 0, NetCents: 10000}},
+		{"percentage off list", tier, 1, PriceAdjustmentTypes.None, 0, TieredPrice{ListCents: 10000, DiscountCents: 1000, NetCents: 9000}},
+		{"override replaces the percentage", tier, 2, PriceAdjustmentTypes.None, 0, TieredPrice{ListCents: 10000, DiscountCents: 3000, NetCents: 7000}},
+		{"proof adjustment applies to both", tier, 1, PriceAdjustmentTypes.Percent, 20, TieredPrice{ListCents: 12000, DiscountCents: 1200, NetCents: 10800}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeTieredPrice(tt.tier, tt.priceGroupID, 10000, tt.adjType, tt.adjValue)
+			if got != tt.want {
+				t.Errorf("ComputeTieredPrice() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeTaxCents(t *testing.T) {
+	tests := []struct {
+		name    string
+		taxable int
+		rate    float64
+		want    int
+	}{
+		{"whole rate", 10000, 6, 600},
+		{"fractional rate", 10000, 7.25, 725},
+		{"rounds to nearest cent", 999, 6.85, 68},
+		{"zero rate", 10000, 0, 0},
+		{"nothing taxable", 0, 8, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeTaxCents(tt.taxable, tt.rate)
+			if got != tt.want {
+				t.Errorf("ComputeTaxCents(%d, %v) = %d, want %d", tt.taxable, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDealershipIsTaxExempt(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	certificate := "RESALE-1234"
+	empty := ""
+	yesterday := now.AddDate(0, 0, -1)
+	today := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		exemption TaxExemption
+		want      bool
+	}{
+		{"not flagged", TaxExemption{Certificate: &certificate}, false},
+		{"flagged without certificate", TaxExemption{Exempt: true}, false},
+		{"flagged with blank certificate", TaxExemption{Exempt: true, Certificate: &empty}, false},
+		{"no expiry", TaxExemption{Exempt: true, Certificate: &certificate}, true},
+		{"expires today", TaxExemption{Exempt: true, Certificate: &certificate, ExpiresAt: &today}, true},
+		{"expired", TaxExemption{Exempt: true, Certificate: &certificate, ExpiresAt: &yesterday}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dealership := &Dealership{TaxExemption: tt.exemption}
+			if got := dealership.IsTaxExempt(now); got != tt.want {
+				t.Errorf("IsTaxExempt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeRushSurchargeCents(t *testing.T) {
+	tests := []struct {
+		name    string
+		inlays  int
+		percent float64
+		want    int
+	}{
+		{"quarter of the inlays", 10000, 25, 2500},
+		{"rounds to nearest cent", 9999, 25, 2500},
+		{"no surcharge configured", 10000, 0, 0},
+		{"nothing to mark up", 0, 25, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeRushSurchargeCents(tt.inlays, tt.percent); got != tt.want {
+				t.Errorf("ComputeRushSurchargeCents(%d, %v) = %d, want %d", tt.inlays, tt.percent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectIsOverdue(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	yesterday := time.Date(2026, 6, 14, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		requiredBy *time.Time
+		want       bool
+	}{
+		{"no date", nil, false},
+		{"due today", &today, false},
+		{"due yesterday", &yesterday, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := &Project{RequiredBy: tt.requiredBy}
+			if got := project.IsOverdue(now); got != tt.want {
+				t.Errorf("IsOverdue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
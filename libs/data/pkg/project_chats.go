@@ -338,6 +338,37 @@ func (m ProjectChatModel) Update(chat *ProjectChat) error {
 	return nil
 }
 
+// TxMoveInlayMessages moves the messages tagged with any of the given inlays
+// from one project's thread to another's, so a conversation about an inlay
+// stays with it when the inlay changes project. Untagged messages stay put.
+func (m ProjectChatModel) TxMoveInlayMessages(tx *sql.Tx, fromProjectID, toProjectID int, inlayIDs []int) error {
+	if len(inlayIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]postgres.Expression, len(inlayIDs))
+	for i, id := range inlayIDs {
+		ids[i] = postgres.Int(int64(id))
+	}
+
+	query := table.ProjectChats.UPDATE(
+		table.ProjectChats.ProjectID,
+	).SET(
+		postgres.Int(int64(toProjectID)),
+	).WHERE(
+		postgres.AND(
+			table.ProjectChats.ProjectID.EQ(postgres.Int(int64(fromProjectID))),
+			table.ProjectChats.InlayID.IN(ids...),
+		),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := query.ExecContext(ctx, tx)
+	return err
+}
+
 func (m ProjectChatModel) Delete(id int) error {
 	query := table.ProjectChats.DELETE().WHERE(
 		table.ProjectChats.ID.EQ(postgres.Int(int64(id))),
@@ -0,0 +1,334 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QuoteValidityDays is how long a quote holds its prices unless the quoter
+// asks for something else.
+const QuoteValidityDays = 30
+
+// ProjectQuoteLine is one inlay's price on a quote, priced the same way the
+// order snapshot would be at the time of quoting. PriceCents is net of the
+// dealership's tier discount.
+type ProjectQuoteLine struct {
+	ID                   int                 `json:"id"`
+	UUID                 string              `json:"uuid"`
+	InlayID              *int                `json:"inlay_id"`
+	InlayName            string              `json:"inlay_name"`
+	ProofID              *int                `json:"proof_id"`
+	PriceGroupID         int                 `json:"price_group_id"`
+	PriceGroupName       string              `json:"price_group_name"`
+	BasePriceCents       int                 `json:"base_price_cents"`
+	PriceAdjustmentType  PriceAdjustmentType `json:"price_adjustment_type"`
+	PriceAdjustmentValue float64             `json:"price_adjustment_value"`
+	PriceCents           int                 `json:"price_cents"`
+	TierDiscountCents    int                 `json:"tier_discount_cents"`
+	PriceTierID          *int                `json:"price_tier_id"`
+	Width                float64             `json:"width"`
+	Height               float64             `json:"height"`
+	SortOrder            int                 `json:"sort_order"`
+}
+
+// ProjectQuote is an immutable price breakdown for a draft project. Quotes on a
+// project are numbered from 1; only the latest is honored when ordering.
+type ProjectQuote struct {
+	ID                        int                `json:"id"`
+	UUID                      string             `json:"uuid"`
+	ProjectID                 int                `json:"project_id"`
+	QuoteNumber               int                `json:"quote_number"`
+	ProjectName               string             `json:"project_name"`
+	DealershipName            string             `json:"dealership_name"`
+	SubtotalCents             int                `json:"subtotal_cents"`
+	InstallationKit           bool               `json:"installation_kit"`
+	InstallationKitPriceCents int                `json:"installation_kit_price_cents"`
+	TotalCents                int                `json:"total_cents"`
+	ExpiresAt                 time.Time          `json:"expires_at"`
+	CreatedByDealershipUserID *int               `json:"created_by_dealership_user_id"`
+	CreatedByInternalUserID   *int               `json:"created_by_internal_user_id"`
+	Lines                     []ProjectQuoteLine `json:"lines"`
+	CreatedAt                 time.Time          `json:"created_at"`
+}
+
+func (q *ProjectQuote) IsExpired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
+
+// LineForInlay returns the quoted price for an inlay, if the quote has one.
+func (q *ProjectQuote) LineForInlay(inlayID int) (*ProjectQuoteLine, bool) {
+	for i := range q.Lines {
+		if q.Lines[i].InlayID != nil && *q.Lines[i].InlayID == inlayID {
+			return &q.Lines[i], true
+		}
+	}
+	return nil, false
+}
+
+type ProjectQuoteModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func projectQuoteFromGen(gen model.ProjectQuotes) *ProjectQuote {
+	return &ProjectQuote{
+		ID:                        int(gen.ID),
+		UUID:                      gen.UUID.String(),
+		ProjectID:                 int(gen.ProjectID),
+		QuoteNumber:               int(gen.QuoteNumber),
+		ProjectName:               gen.ProjectName,
+		DealershipName:            gen.DealershipName,
+		SubtotalCents:             int(gen.SubtotalCents),
+		InstallationKit:           gen.InstallationKit,
+		InstallationKitPriceCents: int(gen.InstallationKitPriceCents),
+		TotalCents:                int(gen.TotalCents),
+		ExpiresAt:                 gen.ExpiresAt,
+		CreatedByDealershipUserID: intPtrFromGen(gen.CreatedByDealershipUserID),
+		CreatedByInternalUserID:   intPtrFromGen(gen.CreatedByInternalUserID),
+		Lines:                     []ProjectQuoteLine{},
+		CreatedAt:                 gen.CreatedAt,
+	}
+}
+
+func projectQuoteLineFromGen(gen model.ProjectQuoteLines) ProjectQuoteLine {
+	return ProjectQuoteLine{
+		ID:                   int(gen.ID),
+		UUID:                 gen.UUID.String(),
+		InlayID:              intPtrFromGen(gen.InlayID),
+		InlayName:            gen.InlayName,
+		ProofID:              intPtrFromGen(gen.ProofID),
+		PriceGroupID:         int(gen.PriceGroupID),
+		PriceGroupName:       gen.PriceGroupName,
+		BasePriceCents:       int(gen.BasePriceCents),
+		PriceAdjustmentType:  PriceAdjustmentType(gen.PriceAdjustmentType),
+		PriceAdjustmentValue: gen.PriceAdjustmentValue,
+		PriceCents:           int(gen.PriceCents),
+		TierDiscountCents:    int(gen.TierDiscountCents),
+		PriceTierID:          intPtrFromGen(gen.PriceTierID),
+		Width:                gen.Width,
+		Height:               gen.Height,
+		SortOrder:            int(gen.SortOrder),
+	}
+}
+
+func projectQuoteLineToGen(quoteID int, line ProjectQuoteLine) model.ProjectQuoteLines {
+	adjustmentType := string(line.PriceAdjustmentType)
+	if adjustmentType == "" {
+		adjustmentType = string(PriceAdjustmentTypes.None)
+	}
+
+	return model.ProjectQuoteLines{
+		QuoteID:              int32(quoteID),
+		InlayID:              intPtrToGen(line.InlayID),
+		InlayName:            line.InlayName,
+		ProofID:              intPtrToGen(line.ProofID),
+		PriceGroupID:         int32(line.PriceGroupID),
+		PriceGroupName:       line.PriceGroupName,
+		BasePriceCents:       int32(line.BasePriceCents),
+		PriceAdjustmentType:  adjustmentType,
+		PriceAdjustmentValue: line.PriceAdjustmentValue,
+		PriceCents:           int32(line.PriceCents),
+		TierDiscountCents:    int32(line.TierDiscountCents),
+		PriceTierID:          intPtrToGen(line.PriceTierID),
+		Width:                line.Width,
+		Height:               line.Height,
+		SortOrder:            int32(line.SortOrder),
+	}
+}
+
+// Insert stores a quote and its lines. The quote number is the next after the
+// project's latest, and the subtotal and total are summed from the lines, so
+// those fields on quote are overwritten.
+func (m ProjectQuoteModel) Insert(quote *ProjectQuote) error {
+	quote.SubtotalCents = 0
+	for i := range quote.Lines {
+		quote.Lines[i].SortOrder = i
+		quote.SubtotalCents += quote.Lines[i].PriceCents
+	}
+	if !quote.InstallationKit {
+		quote.InstallationKitPriceCents = 0
+	}
+	quote.TotalCents = quote.SubtotalCents + quote.InstallationKitPriceCents
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO project_quotes (
+			project_id, quote_number, project_name, dealership_name, subtotal_cents,
+			installation_kit, installation_kit_price_cents, total_cents, expires_at,
+			created_by_dealership_user_id, created_by_internal_user_id
+		)
+		VALUES ($1, (SELECT COALESCE(MAX(quote_number), 0) + 1 FROM project_quotes WHERE project_id = $1), $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, uuid, quote_number, created_at
+	`,
+		quote.ProjectID,
+		quote.ProjectName,
+		quote.DealershipName,
+		quote.SubtotalCents,
+		quote.InstallationKit,
+		quote.InstallationKitPriceCents,
+		quote.TotalCents,
+		quote.ExpiresAt,
+		quote.CreatedByDealershipUserID,
+		quote.CreatedByInternalUserID,
+	).Scan(&quote.ID, &quote.UUID, &quote.QuoteNumber, &quote.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if len(quote.Lines) > 0 {
+		genLines := make([]model.ProjectQuoteLines, len(quote.Lines))
+		for i, line := range quote.Lines {
+			genLines[i] = projectQuoteLineToGen(quote.ID, line)
+		}
+
+		query := table.ProjectQuoteLines.INSERT(
+			table.ProjectQuoteLines.QuoteID,
+			table.ProjectQuoteLines.InlayID,
+			table.ProjectQuoteLines.InlayName,
+			table.ProjectQuoteLines.ProofID,
+			table.ProjectQuoteLines.PriceGroupID,
+			table.ProjectQuoteLines.PriceGroupName,
+			table.ProjectQuoteLines.BasePriceCents,
+			table.ProjectQuoteLines.PriceAdjustmentType,
+			table.ProjectQuoteLines.PriceAdjustmentValue,
+			table.ProjectQuoteLines.PriceCents,
+			table.ProjectQuoteLines.TierDiscountCents,
+			table.ProjectQuoteLines.PriceTierID,
+			table.ProjectQuoteLines.Width,
+			table.ProjectQuoteLines.Height,
+			table.ProjectQuoteLines.SortOrder,
+		).MODELS(
+			genLines,
+		).RETURNING(
+			table.ProjectQuoteLines.AllColumns,
+		)
+
+		var dest []model.ProjectQuoteLines
+		if err := query.QueryContext(ctx, tx, &dest); err != nil {
+			return err
+		}
+		quote.Lines = make([]ProjectQuoteLine, len(dest))
+		for i, d := range dest {
+			quote.Lines[i] = projectQuoteLineFromGen(d)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (m ProjectQuoteModel) getMany(condition postgres.BoolExpression) ([]*ProjectQuote, error) {
+	query := postgres.SELECT(
+		table.ProjectQuotes.AllColumns,
+	).FROM(
+		table.ProjectQuotes,
+	).WHERE(
+		condition,
+	).ORDER_BY(
+		table.ProjectQuotes.QuoteNumber.DESC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.ProjectQuotes
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return nil, err
+	}
+
+	quotes := make([]*ProjectQuote, len(dest))
+	for i, d := range dest {
+		quotes[i] = projectQuoteFromGen(d)
+	}
+
+	if err := m.withLines(ctx, quotes); err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+func (m ProjectQuoteModel) withLines(ctx context.Context, quotes []*ProjectQuote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*ProjectQuote, len(quotes))
+	ids := make([]postgres.Expression, len(quotes))
+	for i, q := range quotes {
+		byID[q.ID] = q
+		ids[i] = postgres.Int(int64(q.ID))
+	}
+
+	query := postgres.SELECT(
+		table.ProjectQuoteLines.AllColumns,
+	).FROM(
+		table.ProjectQuoteLines,
+	).WHERE(
+		table.ProjectQuoteLines.QuoteID.IN(ids...),
+	).ORDER_BY(
+		table.ProjectQuoteLines.SortOrder.ASC(),
+	)
+
+	var dest []model.ProjectQuoteLines
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return err
+	}
+
+	for _, d := range dest {
+		q := byID[int(d.QuoteID)]
+		q.Lines = append(q.Lines, projectQuoteLineFromGen(d))
+	}
+	return nil
+}
+
+func (m ProjectQuoteModel) GetByUUID(uuidStr string) (*ProjectQuote, bool, error) {
+	parsedUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	quotes, err := m.getMany(table.ProjectQuotes.UUID.EQ(postgres.UUID(parsedUUID)))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(quotes) == 0 {
+		return nil, false, nil
+	}
+	return quotes[0], true, nil
+}
+
+// GetByProjectID returns a project's quotes, newest first.
+func (m ProjectQuoteModel) GetByProjectID(projectID int) ([]*ProjectQuote, error) {
+	return m.getMany(table.ProjectQuotes.ProjectID.EQ(postgres.Int(int64(projectID))))
+}
+
+// GetLatestByProjectID returns the project's newest quote, expired or not.
+// Earlier quotes are superseded by it.
+func (m ProjectQuoteModel) GetLatestByProjectID(projectID int) (*ProjectQuote, bool, error) {
+	quotes, err := m.GetByProjectID(projectID)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(quotes) == 0 {
+		return nil, false, nil
+	}
+	return quotes[0], true, nil
+}
@@ -0,0 +1,61 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProjectQuote_InsertNumbersAndTotals(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+	models := getTestModels(t)
+
+	dealership := createTestDealership(t, models)
+	project := createTestProject(t, models, dealership.ID)
+	inlay := createTestInlay(t, models, project.ID)
+
+	inlayID := inlay.ID
+	newQuote := func() *ProjectQuote {
+		return &ProjectQuote{
+			ProjectID:                 project.ID,
+			ProjectName:               project.Name,
+			DealershipName:            dealership.Name,
+			InstallationKit:           true,
+			InstallationKitPriceCents: InstallationKitPriceCents,
+			ExpiresAt:                 time.Now().AddDate(0, 0, QuoteValidityDays),
+			Lines: []ProjectQuoteLine{
+				{InlayID: &inlayID, InlayName: inlay.Name, PriceGroupID: 1, PriceGroupName: "Standard", BasePriceCents: 10000, PriceCents: 10000, Width: 12, Height: 8},
+				{InlayName: "Removed Inlay", PriceGroupID: 1, PriceGroupName: "Standard", BasePriceCents: 10000, PriceCents: 8000, Width: 12, Height: 8},
+			},
+		}
+	}
+
+	first := newQuote()
+	if err := models.ProjectQuotes.Insert(first); err != nil {
+		t.Fatalf("Failed to insert quote: %v", err)
+	}
+	if first.QuoteNumber != 1 || first.SubtotalCents != 18000 || first.TotalCents != 18000+InstallationKitPriceCents {
+		t.Errorf("Unexpected quote figures: %+v", first)
+	}
+
+	second := newQuote()
+	if err := models.ProjectQuotes.Insert(second); err != nil {
+		t.Fatalf("Failed to insert second quote: %v", err)
+	}
+	if second.QuoteNumber != 2 {
+		t.Errorf("Expected quote number 2, got %d", second.QuoteNumber)
+	}
+
+	latest, found, err := models.ProjectQuotes.GetLatestByProjectID(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get latest quote: %v", err)
+	}
+	if !found || latest.ID != second.ID || len(latest.Lines) != 2 {
+		t.Fatalf("Expected the second quote with its lines, got %+v", latest)
+	}
+	if line, ok := latest.LineForInlay(inlay.ID); !ok || line.PriceCents != 10000 {
+		t.Errorf("Expected the inlay's line, got %+v", line)
+	}
+	if latest.IsExpired(time.Now()) {
+		t.Error("Expected the quote to be current")
+	}
+}
@@ -90,6 +90,22 @@ func (m ProjectWatcherModel) TxAutoSubscribe(tx *sql.Tx, projectID int, user Aut
 	return err
 }
 
+// TxCopy gives a new project the same watchers as an existing one, explicit
+// unwatches included, so splitting a project does not change who hears about
+// its inlays.
+func (m ProjectWatcherModel) TxCopy(tx *sql.Tx, fromProjectID, toProjectID int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO project_watchers (project_id, dealership_user_id, internal_user_id, is_watching)
+		SELECT $2, dealership_user_id, internal_user_id, is_watching
+		FROM project_watchers
+		WHERE project_id = $1
+		ON CONFLICT DO NOTHING`, fromProjectID, toProjectID)
+	return err
+}
+
 // SetWatching records an explicit choice, which does override whatever
 // auto-subscription previously decided.
 func (m ProjectWatcherModel) SetWatching(projectID int, user AuthUser, isWatching bool) error {
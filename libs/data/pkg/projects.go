@@ -51,6 +51,41 @@ type Project struct {
 	// inlay prices.
 	InstallationKit           bool `json:"installation_kit"`
 	InstallationKitPriceCents *int `json:"installation_kit_price_cents"`
+	// ParentProjectID is set on a draft split off a partially ordered project,
+	// pointing at the project that was ordered.
+	ParentProjectID *int `json:"parent_project_id"`
+	// Tax is locked with the kit charge when the order is placed; all four stay
+	// nil on drafts. TaxExemptCertificate is the certificate an exempt order
+	// was taken under.
+	TaxCents             *int     `json:"tax_cents"`
+	TaxRatePercent       *float64 `json:"tax_rate_percent"`
+	TaxJurisdiction      *string  `json:"tax_jurisdiction"`
+	TaxExemptCertificate *string  `json:"tax_exempt_certificate"`
+	// Rush is chosen at order time. RushSurchargeCents is nil until the order is
+	// placed, then locks the charge like the kit's; it is 0 on an order placed
+	// without rush. RequiredBy is the date the dealership needs it shipped by.
+	Rush               bool       `json:"rush"`
+	RushSurchargeCents *int       `json:"rush_surcharge_cents"`
+	RequiredBy         *time.Time `json:"required_by"`
+	// ShipToAddressID is the saved address a draft will ship to, nil for the
+	// dealership's own. The address is copied into the order snapshots when the
+	// order is placed.
+	ShipToAddressID *int `json:"ship_to_address_id"`
+}
+
+// IsOverdue reports whether the project has passed its required-by date. The
+// date is met through its end.
+func (p *Project) IsOverdue(at time.Time) bool {
+	return PastRequiredBy(p.RequiredBy, at)
+}
+
+// PastRequiredBy is IsOverdue for callers holding only the date.
+func PastRequiredBy(requiredBy *time.Time, at time.Time) bool {
+	if requiredBy == nil {
+		return false
+	}
+	year, month, day := requiredBy.Date()
+	return !at.Before(time.Date(year, month, day+1, 0, 0, 0, 0, requiredBy.Location()))
 }
 
 type ProjectModel struct {
@@ -71,6 +106,12 @@ func projectFromGen(genProj model.Projects) *Project {
 		kitPriceCents = &kitPriceCentsVal
 	}
 
+	var parentProjectID *int
+	if genProj.ParentProjectID != nil {
+		parentProjectIDVal := int(*genProj.ParentProjectID)
+		parentProjectID = &parentProjectIDVal
+	}
+
 	project := Project{
 		StandardTable: StandardTable{
 			ID:        int(genProj.ID),
@@ -89,6 +130,18 @@ func projectFromGen(genProj model.Projects) *Project {
 
 		InstallationKit:           genProj.InstallationKit,
 		InstallationKitPriceCents: kitPriceCents,
+		ParentProjectID:           parentProjectID,
+
+		TaxCents:             intPtrFromGen(genProj.TaxCents),
+		TaxRatePercent:       genProj.TaxRatePercent,
+		TaxJurisdiction:      genProj.TaxJurisdiction,
+		TaxExemptCertificate: genProj.TaxExemptCertificate,
+
+		Rush:               genProj.Rush,
+		RushSurchargeCents: intPtrFromGen(genProj.RushSurchargeCents),
+		RequiredBy:         genProj.RequiredBy,
+
+		ShipToAddressID: intPtrFromGen(genProj.ShipToAddressID),
 	}
 
 	return &project
@@ -117,6 +170,12 @@ func projectToGen(p *Project) (*model.Projects, error) {
 		kitPriceCents = &kitPriceCentsVal
 	}
 
+	var parentProjectID *int32
+	if p.ParentProjectID != nil {
+		parentProjectIDVal := int32(*p.ParentProjectID)
+		parentProjectID = &parentProjectIDVal
+	}
+
 	genProj := model.Projects{
 		ID:                int32(p.ID),
 		UUID:              projectUUID,
@@ -133,6 +192,18 @@ func projectToGen(p *Project) (*model.Projects, error) {
 
 		InstallationKit:           p.InstallationKit,
 		InstallationKitPriceCents: kitPriceCents,
+		ParentProjectID:           parentProjectID,
+
+		TaxCents:             intPtrToGen(p.TaxCents),
+		TaxRatePercent:       p.TaxRatePercent,
+		TaxJurisdiction:      p.TaxJurisdiction,
+		TaxExemptCertificate: p.TaxExemptCertificate,
+
+		Rush:               p.Rush,
+		RushSurchargeCents: intPtrToGen(p.RushSurchargeCents),
+		RequiredBy:         p.RequiredBy,
+
+		ShipToAddressID: intPtrToGen(p.ShipToAddressID),
 	}
 
 	return &genProj, nil
@@ -151,6 +222,9 @@ func (m ProjectModel) Insert(project *Project) error {
 		table.Projects.Status,
 		table.Projects.OrderedAt,
 		table.Projects.OrderedBy,
+		table.Projects.InstallationKit,
+		table.Projects.ParentProjectID,
+		table.Projects.ShipToAddressID,
 	).MODEL(
 		genProj,
 	).RETURNING(
@@ -192,6 +266,9 @@ func (m ProjectModel) TxInsert(tx *sql.Tx, project *Project) error {
 		table.Projects.Status,
 		table.Projects.OrderedAt,
 		table.Projects.OrderedBy,
+		table.Projects.InstallationKit,
+		table.Projects.ParentProjectID,
+		table.Projects.ShipToAddressID,
 	).MODEL(
 		genProj,
 	).RETURNING(
@@ -305,6 +382,74 @@ func (m ProjectModel) GetByDealershipID(dealershipID int) ([]*Project, error) {
 	return projects, nil
 }
 
+// GetByDealershipIDs lists the projects of several dealerships, such as the
+// locations a group-level user can see, newest first.
+func (m ProjectModel) GetByDealershipIDs(dealershipIDs []int) ([]*Project, error) {
+	if len(dealershipIDs) == 0 {
+		return []*Project{}, nil
+	}
+
+	ids := make([]postgres.Expression, len(dealershipIDs))
+	for i, id := range dealershipIDs {
+		ids[i] = postgres.Int(int64(id))
+	}
+
+	query := postgres.SELECT(
+		table.Projects.AllColumns,
+	).FROM(
+		table.Projects,
+	).WHERE(
+		table.Projects.DealershipID.IN(ids...),
+	).ORDER_BY(
+		table.Projects.CreatedAt.DESC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.Projects
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]*Project, len(dest))
+	for i, d := range dest {
+		projects[i] = projectFromGen(d)
+	}
+
+	return projects, nil
+}
+
+// GetChildren returns the drafts split off a project, oldest first.
+func (m ProjectModel) GetChildren(parentProjectID int) ([]*Project, error) {
+	query := postgres.SELECT(
+		table.Projects.AllColumns,
+	).FROM(
+		table.Projects,
+	).WHERE(
+		table.Projects.ParentProjectID.EQ(postgres.Int(int64(parentProjectID))),
+	).ORDER_BY(
+		table.Projects.CreatedAt.ASC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.Projects
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]*Project, len(dest))
+	for i, d := range dest {
+		projects[i] = projectFromGen(d)
+	}
+
+	return projects, nil
+}
+
 func (m ProjectModel) GetAll() ([]*Project, error) {
 	query := postgres.SELECT(
 		table.Projects.AllColumns,
@@ -475,6 +620,14 @@ func (m ProjectModel) updateProject(ctx context.Context, executor qrm.Queryable,
 		table.Projects.OrderedBy,
 		table.Projects.InstallationKit,
 		table.Projects.InstallationKitPriceCents,
+		table.Projects.TaxCents,
+		table.Projects.TaxRatePercent,
+		table.Projects.TaxJurisdiction,
+		table.Projects.TaxExemptCertificate,
+		table.Projects.Rush,
+		table.Projects.RushSurchargeCents,
+		table.Projects.RequiredBy,
+		table.Projects.ShipToAddressID,
 		table.Projects.Version,
 	).MODEL(
 		genProj,
@@ -327,3 +327,40 @@ func TestProjectStatuses(t *testing.T) {
 		}
 	}
 }
+
+func TestProject_GetChildren(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	parent := createTestProject(t, models, dealership.ID)
+	createTestProject(t, models, dealership.ID)
+
+	child := &Project{
+		DealershipID:    dealership.ID,
+		Name:            parent.Name + " (continued)",
+		Status:          ProjectStatuses.Draft,
+		InstallationKit: true,
+		ParentProjectID: &parent.ID,
+	}
+	if err := models.Projects.Insert(child); err != nil {
+		t.Fatalf("Failed to insert child project: %v", err)
+	}
+
+	children, err := models.Projects.GetChildren(parent.ID)
+	if err != nil {
+		t.Fatalf("Failed to get children: %v", err)
+	}
+	if len(children) != 1 {
+		t.Fatalf("Expected 1 child project, got %d", len(children))
+	}
+	if children[0].ID != child.ID {
+		t.Errorf("Expected child ID %d, got %d", child.ID, children[0].ID)
+	}
+	if children[0].ParentProjectID == nil || *children[0].ParentProjectID != parent.ID {
+		t.Errorf("Expected parent_project_id %d, got %v", parent.ID, children[0].ParentProjectID)
+	}
+	if !children[0].InstallationKit {
+		t.Errorf("Expected installation_kit to be inserted")
+	}
+}
@@ -0,0 +1,80 @@
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RateLimit is a token bucket: up to Burst requests straight away, then one
+// more each Interval.
+type RateLimit struct {
+	Burst    int
+	Interval time.Duration
+}
+
+type RateLimitModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+// Take spends a token from key's bucket, starting it full the first time key
+// is seen. When the bucket is empty it reports false and how long until the
+// next token.
+func (m RateLimitModel) Take(key string, limit RateLimit) (bool, time.Duration, error) {
+	hash := sha256.Sum256([]byte(key))
+	interval := limit.Interval.Seconds()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// A refused request leaves the row alone, so the bucket keeps refilling
+	// from its last successful take.
+	var tokens float64
+	err := m.STDB.QueryRowContext(ctx, `
+		INSERT INTO rate_limit_buckets AS b (key_hash, tokens, updated_at, full_at)
+		VALUES ($1, $2::float8 - 1, now(), now() + make_interval(secs => $3::float8))
+		ON CONFLICT (key_hash) DO UPDATE
+		SET
+			tokens = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 / $3::float8) - 1,
+			updated_at = now(),
+			full_at = now() + make_interval(secs => ($2::float8 - LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 / $3::float8) + 1) * $3::float8)
+		WHERE LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 / $3::float8) >= 1
+		RETURNING tokens
+	`, hash[:], limit.Burst, interval).Scan(&tokens)
+	if err == nil {
+		return true, 0, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, 0, err
+	}
+
+	var wait float64
+	err = m.STDB.QueryRowContext(ctx, `
+		SELECT GREATEST(0, (1 - tokens - EXTRACT(EPOCH FROM now() - updated_at)::float8 / $2::float8) * $2::float8)
+		FROM rate_limit_buckets
+		WHERE key_hash = $1
+	`, hash[:], interval).Scan(&wait)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return false, time.Duration(wait * float64(time.Second)), nil
+}
+
+// Prune deletes buckets that have refilled, which behave the same as no
+// bucket at all, and returns how many there were.
+func (m RateLimitModel) Prune() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := m.STDB.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE full_at < now()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
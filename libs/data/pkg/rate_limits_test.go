@@ -0,0 +1,66 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimit_TakeAndRefill(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	limit := RateLimit{Burst: 2, Interval: time.Hour}
+
+	for i := range limit.Burst {
+		allowed, _, err := models.RateLimits.Take("test:a", limit)
+		if err != nil {
+			t.Fatalf("Failed to take token: %v", err)
+		}
+		if !allowed {
+			t.Fatalf("Expected take %d to be within the burst", i+1)
+		}
+	}
+
+	allowed, retryAfter, err := models.RateLimits.Take("test:a", limit)
+	if err != nil {
+		t.Fatalf("Failed to take token: %v", err)
+	}
+	if allowed {
+		t.Fatalf("Expected an empty bucket to refuse")
+	}
+	if retryAfter <= 0 || retryAfter > limit.Interval {
+		t.Errorf("Expected to wait up to one interval, got %v", retryAfter)
+	}
+
+	allowed, _, err = models.RateLimits.Take("test:b", limit)
+	if err != nil || !allowed {
+		t.Errorf("Expected another key to have its own bucket: allowed=%v err=%v", allowed, err)
+	}
+
+	_, err = models.STDB.Exec(`UPDATE rate_limit_buckets SET updated_at = now() - interval '1 hour', full_at = now() - interval '1 minute'`)
+	if err != nil {
+		t.Fatalf("Failed to age buckets: %v", err)
+	}
+
+	allowed, _, err = models.RateLimits.Take("test:a", limit)
+	if err != nil || !allowed {
+		t.Errorf("Expected a token back after an interval: allowed=%v err=%v", allowed, err)
+	}
+
+	pruned, err := models.RateLimits.Prune()
+	if err != nil {
+		t.Fatalf("Failed to prune: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("Expected the refilled bucket to be pruned, got %d", pruned)
+	}
+
+	var remaining int
+	err = models.STDB.QueryRow(`SELECT count(*) FROM rate_limit_buckets`).Scan(&remaining)
+	if err != nil {
+		t.Fatalf("Failed to count buckets: %v", err)
+	}
+	if remaining != 1 {
+		t.Errorf("Expected only the bucket in use to survive pruning, got %d", remaining)
+	}
+}
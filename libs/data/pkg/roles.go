@@ -0,0 +1,454 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DealershipActions are the actions a dealership role or override can grant.
+var DealershipActions = []string{
+	ActionCreateProject,
+	ActionManageProject,
+	ActionSendChat,
+	ActionApproveProof,
+	ActionPlaceOrder,
+	ActionPayInvoice,
+	ActionManageDealershipUsers,
+	ActionManageDealership,
+	ActionViewProjects,
+	ActionViewInvoices,
+}
+
+// InternalActions are the actions an internal role or override can grant.
+var InternalActions = []string{
+	ActionCreateProof,
+	ActionInternalApproveProof,
+	ActionManageKanban,
+	ActionManageShipping,
+	ActionCreateInlayUpdate,
+	ActionCreateInvoice,
+	ActionManageInternalUsers,
+	ActionManageDealershipUsers,
+	ActionViewAll,
+	ActionManageCatalog,
+	ActionManagePriceGroups,
+	ActionManageDealerships,
+	ActionManageDealership,
+	ActionManageSupport,
+	ActionManageMaterials,
+	ActionManageRemakes,
+	ActionManageTaxes,
+	ActionImpersonate,
+	ActionManageRoles,
+	ActionManageJobs,
+	ActionAccessAdmin,
+	ActionManageProject,
+	ActionSendChat,
+}
+
+var roleKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,62}$`)
+
+// ValidateRoleKey checks a key for a new role.
+func ValidateRoleKey(key string) error {
+	if !roleKeyPattern.MatchString(key) {
+		return errors.New("key must be 2-63 lowercase letters, digits or underscores, starting with a letter")
+	}
+	return nil
+}
+
+var (
+	ErrRoleBuiltin = errors.New("built-in roles cannot be deleted")
+	ErrRoleInUse   = errors.New("role is assigned to users")
+)
+
+// PermissionSet is what a user may do: their role's actions with their own
+// overrides applied.
+type PermissionSet map[string]bool
+
+func (s PermissionSet) Has(action string) bool {
+	return s[action]
+}
+
+// Actions lists the set in a stable order.
+func (s PermissionSet) Actions() []string {
+	actions := make([]string, 0, len(s))
+	for action, granted := range s {
+		if granted {
+			actions = append(actions, action)
+		}
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+type Role struct {
+	StandardTable
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	IsBuiltin   bool     `json:"is_builtin"`
+	Actions     []string `json:"actions"`
+}
+
+// PermissionOverride is a per-user exception to their role. Granted adds an
+// action the role lacks; otherwise the action is denied even if the role has
+// it.
+type PermissionOverride struct {
+	Action  string `json:"action"`
+	Granted bool   `json:"granted"`
+}
+
+// roleTables names one kind of user's role tables, which are otherwise the
+// same for dealership and internal users.
+type roleTables struct {
+	roles           string
+	rolePermissions string
+	roleID          string
+	users           string
+	userPermissions string
+	userID          string
+	actions         []string
+}
+
+var dealershipRoleTables = roleTables{
+	roles:           "dealership_roles",
+	rolePermissions: "dealership_role_permissions",
+	roleID:          "dealership_role_id",
+	users:           "dealership_users",
+	userPermissions: "dealership_user_permissions",
+	userID:          "dealership_user_id",
+	actions:         DealershipActions,
+}
+
+var internalRoleTables = roleTables{
+	roles:           "internal_roles",
+	rolePermissions: "internal_role_permissions",
+	roleID:          "internal_role_id",
+	users:           "internal_users",
+	userPermissions: "internal_user_permissions",
+	userID:          "internal_user_id",
+	actions:         InternalActions,
+}
+
+// RoleModel manages one kind of user's roles and permission overrides; see
+// Models.DealershipRoles and Models.InternalRoles.
+type RoleModel struct {
+	DB     *pgxpool.Pool
+	STDB   *sql.DB
+	tables roleTables
+}
+
+// ResolvePermissions works out what user may do.
+func ResolvePermissions(models *Models, user AuthUser) (PermissionSet, error) {
+	return models.RolesFor(user).Resolve(user.GetID(), user.GetRole())
+}
+
+// RolesFor is the role model for user's kind.
+func (m Models) RolesFor(user AuthUser) RoleModel {
+	if user.IsDealership() {
+		return m.DealershipRoles
+	}
+	return m.InternalRoles
+}
+
+// Actions are the actions this kind of role can grant.
+func (m RoleModel) Actions() []string {
+	return m.tables.actions
+}
+
+// ValidateActions reports an error naming the first action this kind of role
+// cannot grant.
+func (m RoleModel) ValidateActions(actions []string) error {
+	for _, action := range actions {
+		if !slices.Contains(m.tables.actions, action) {
+			return fmt.Errorf("unknown action %q", action)
+		}
+	}
+	return nil
+}
+
+func (m RoleModel) getWhere(ctx context.Context, condition string, args ...any) ([]*Role, error) {
+	rows, err := m.STDB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT r.id, r.uuid, r.key, r.name, r.description, r.is_builtin, r.updated_at, r.created_at, r.version, p.action
+		FROM %s r
+		LEFT JOIN %s p ON p.%s = r.id
+		WHERE %s
+		ORDER BY r.is_builtin DESC, r.name, r.id, p.action
+	`, m.tables.roles, m.tables.rolePermissions, m.tables.roleID, condition), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []*Role{}
+	for rows.Next() {
+		var role Role
+		var action sql.NullString
+
+		err = rows.Scan(
+			&role.ID, &role.UUID, &role.Key, &role.Name, &role.Description, &role.IsBuiltin,
+			&role.UpdatedAt, &role.CreatedAt, &role.Version, &action,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(roles) == 0 || roles[len(roles)-1].ID != role.ID {
+			role.Actions = []string{}
+			roles = append(roles, &role)
+		}
+		if action.Valid {
+			last := roles[len(roles)-1]
+			last.Actions = append(last.Actions, action.String)
+		}
+	}
+
+	return roles, rows.Err()
+}
+
+func (m RoleModel) getOne(condition string, args ...any) (*Role, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	roles, err := m.getWhere(ctx, condition, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(roles) == 0 {
+		return nil, false, nil
+	}
+	return roles[0], true, nil
+}
+
+func (m RoleModel) GetAll() ([]*Role, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.getWhere(ctx, "true")
+}
+
+func (m RoleModel) GetByKey(key string) (*Role, bool, error) {
+	return m.getOne("r.key = $1", key)
+}
+
+func (m RoleModel) GetByUUID(uuidStr string) (*Role, bool, error) {
+	parsedUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return m.getOne("r.uuid = $1", parsedUUID)
+}
+
+func (m RoleModel) setActions(ctx context.Context, tx *sql.Tx, role *Role) error {
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, m.tables.rolePermissions, m.tables.roleID), role.ID)
+	if err != nil {
+		return err
+	}
+
+	actions := []string{}
+	for _, action := range role.Actions {
+		if slices.Contains(actions, action) {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (%s, action) VALUES ($1, $2)`, m.tables.rolePermissions, m.tables.roleID), role.ID, action)
+		if err != nil {
+			return err
+		}
+
+		actions = append(actions, action)
+	}
+
+	sort.Strings(actions)
+	role.Actions = actions
+	return nil
+}
+
+// Insert creates a custom role with its actions.
+func (m RoleModel) Insert(role *Role) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s (key, name, description) VALUES ($1, $2, $3)
+		RETURNING id, uuid, is_builtin, updated_at, created_at, version
+	`, m.tables.roles), role.Key, role.Name, role.Description).Scan(
+		&role.ID, &role.UUID, &role.IsBuiltin, &role.UpdatedAt, &role.CreatedAt, &role.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = m.setActions(ctx, tx, role)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update saves the role's name, description and actions. Its key cannot
+// change, since users reference it.
+func (m RoleModel) Update(role *Role) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE %s SET name = $1, description = $2
+		WHERE id = $3 AND version = $4
+		RETURNING updated_at, version
+	`, m.tables.roles), role.Name, role.Description, role.ID, role.Version).Scan(&role.UpdatedAt, &role.Version)
+	if err != nil {
+		return err
+	}
+
+	err = m.setActions(ctx, tx, role)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes a custom role. Returns ErrRoleBuiltin for a built-in role and
+// ErrRoleInUse while any user has it.
+func (m RoleModel) Delete(role *Role) error {
+	if role.IsBuiltin {
+		return ErrRoleBuiltin
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var inUse bool
+	err := m.STDB.QueryRowContext(ctx, fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE role = $1)`, m.tables.users), role.Key).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrRoleInUse
+	}
+
+	_, err = m.STDB.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, m.tables.roles), role.ID)
+	return err
+}
+
+// Resolve works out what a user with roleKey may do, applying the user's
+// overrides on top of the role.
+func (m RoleModel) Resolve(userID int, roleKey string) (PermissionSet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.STDB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT p.action, true, 0
+		FROM %s p
+		JOIN %s r ON r.id = p.%s
+		WHERE r.key = $1
+		UNION ALL
+		SELECT action, granted, 1
+		FROM %s
+		WHERE %s = $2
+		ORDER BY 3
+	`, m.tables.rolePermissions, m.tables.roles, m.tables.roleID, m.tables.userPermissions, m.tables.userID), roleKey, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := PermissionSet{}
+	for rows.Next() {
+		var action string
+		var granted bool
+		var precedence int
+
+		err = rows.Scan(&action, &granted, &precedence)
+		if err != nil {
+			return nil, err
+		}
+
+		if granted {
+			permissions[action] = true
+		} else {
+			delete(permissions, action)
+		}
+	}
+
+	return permissions, rows.Err()
+}
+
+func (m RoleModel) GetOverrides(userID int) ([]PermissionOverride, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.STDB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT action, granted FROM %s WHERE %s = $1 ORDER BY action
+	`, m.tables.userPermissions, m.tables.userID), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := []PermissionOverride{}
+	for rows.Next() {
+		var override PermissionOverride
+		err = rows.Scan(&override.Action, &override.Granted)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, override)
+	}
+
+	return overrides, rows.Err()
+}
+
+// SetOverrides replaces a user's overrides. A later override for the same
+// action wins.
+func (m RoleModel) SetOverrides(userID int, overrides []PermissionOverride) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, m.tables.userPermissions, m.tables.userID), userID)
+	if err != nil {
+		return err
+	}
+
+	for _, override := range overrides {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+			INSERT INTO %s (%s, action, granted) VALUES ($1, $2, $3)
+			ON CONFLICT (%s, action) DO UPDATE SET granted = EXCLUDED.granted
+		`, m.tables.userPermissions, m.tables.userID, m.tables.userID), userID, override.Action, override.Granted)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
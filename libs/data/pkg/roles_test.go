@@ -0,0 +1,111 @@
+package data
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRoleModel_BuiltinRolesAreSeeded(t *testing.T) {
+	models := getTestModels(t)
+
+	viewer, err := models.DealershipRoles.Resolve(0, string(DealershipUserRoles.Viewer))
+	if err != nil {
+		t.Fatalf("Failed to resolve viewer: %v", err)
+	}
+	if !viewer.Has(ActionViewProjects) || viewer.Has(ActionCreateProject) {
+		t.Errorf("Expected viewers to only view, got %v", viewer.Actions())
+	}
+
+	admin, err := models.InternalRoles.Resolve(0, string(InternalUserRoles.Admin))
+	if err != nil {
+		t.Fatalf("Failed to resolve admin: %v", err)
+	}
+	if !admin.Has(ActionManageRoles) || !admin.Has(ActionImpersonate) {
+		t.Errorf("Expected internal admins to manage roles and impersonate, got %v", admin.Actions())
+	}
+
+	role, found, err := models.InternalRoles.GetByKey(string(InternalUserRoles.Admin))
+	if err != nil || !found {
+		t.Fatalf("Expected the admin role to exist: found=%v err=%v", found, err)
+	}
+
+	err = models.InternalRoles.Delete(role)
+	if !errors.Is(err, ErrRoleBuiltin) {
+		t.Errorf("Expected built-in roles to be kept, got %v", err)
+	}
+}
+
+func TestRoleModel_CustomRoleAndOverrides(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+
+	role := &Role{
+		Key:     "orderer",
+		Name:    "Orderer",
+		Actions: []string{ActionViewProjects, ActionPlaceOrder, ActionPlaceOrder},
+	}
+	err := models.DealershipRoles.Insert(role)
+	if err != nil {
+		t.Fatalf("Failed to create role: %v", err)
+	}
+	if len(role.Actions) != 2 {
+		t.Errorf("Expected duplicate actions to be dropped, got %v", role.Actions)
+	}
+
+	err = models.DealershipRoles.ValidateActions([]string{ActionManageCatalog})
+	if err == nil {
+		t.Errorf("Expected an internal-only action to be refused for a dealership role")
+	}
+
+	user := &DealershipUser{
+		Name:         "Orderer",
+		Email:        "orderer@example.com",
+		Avatar:       "https://example.com/avatar.jpg",
+		DealershipID: dealership.ID,
+		Role:         DealershipUserRole(role.Key),
+		IsActive:     true,
+	}
+	err = models.DealershipUsers.Insert(user)
+	if err != nil {
+		t.Fatalf("Failed to create user with custom role: %v", err)
+	}
+
+	err = models.DealershipRoles.SetOverrides(user.ID, []PermissionOverride{
+		{Action: ActionSendChat, Granted: true},
+		{Action: ActionPlaceOrder, Granted: false},
+	})
+	if err != nil {
+		t.Fatalf("Failed to set overrides: %v", err)
+	}
+
+	permissions, err := ResolvePermissions(&models, user)
+	if err != nil {
+		t.Fatalf("Failed to resolve permissions: %v", err)
+	}
+	if !permissions.Has(ActionSendChat) || permissions.Has(ActionPlaceOrder) || !permissions.Has(ActionViewProjects) {
+		t.Errorf("Expected the overrides applied on top of the role, got %v", permissions.Actions())
+	}
+
+	err = models.DealershipRoles.Delete(role)
+	if !errors.Is(err, ErrRoleInUse) {
+		t.Errorf("Expected a role in use to be kept, got %v", err)
+	}
+
+	user.Role = DealershipUserRoles.Viewer
+	err = models.DealershipUsers.Update(user)
+	if err != nil {
+		t.Fatalf("Failed to update user: %v", err)
+	}
+
+	err = models.DealershipRoles.Delete(role)
+	if err != nil {
+		t.Fatalf("Failed to delete unused role: %v", err)
+	}
+
+	_, found, err := models.DealershipRoles.GetByKey(role.Key)
+	if err != nil || found {
+		t.Errorf("Expected the role to be gone: found=%v err=%v", found, err)
+	}
+}
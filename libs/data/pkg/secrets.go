@@ -0,0 +1,59 @@
+package data
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+var errSealedSecretTooShort = errors.New("sealed secret is too short")
+
+// secretKey derives a key for sealing one kind of secret from the API's auth
+// secret. purpose keeps the keys for different kinds apart.
+func secretKey(purpose, authSecret string) []byte {
+	key := sha256.Sum256([]byte(purpose + ":" + authSecret))
+	return key[:]
+}
+
+// sealSecret encrypts a secret for storage with AES-GCM, prefixed by its
+// nonce.
+func sealSecret(key []byte, secret string) ([]byte, error) {
+	gcm, err := secretCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+
+	return gcm.Seal(nonce, nonce, []byte(secret), nil), nil
+}
+
+func openSecret(key []byte, sealed []byte) (string, error) {
+	gcm, err := secretCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", errSealedSecretTooShort
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	secret, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(secret), nil
+}
+
+func secretCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,329 @@
+package data
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenTTL is how long a session survives without being used. Every
+// refresh starts the clock again.
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+// RefreshReuseGrace is how long a refresh token that has just been rotated
+// is still honoured. Two tabs refreshing at the same moment both present the
+// same cookie; the loser gets an access token but no new refresh token, since
+// the browser already holds the winner's.
+const RefreshReuseGrace = 30 * time.Second
+
+var (
+	// ErrRefreshTokenNotFound is returned for a refresh token that does not
+	// exist, has expired or whose session was revoked.
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+	// ErrRefreshTokenReused is returned when a refresh token is presented again
+	// after it was rotated. Someone else holds a copy, so the whole session is
+	// revoked.
+	ErrRefreshTokenReused = errors.New("refresh token reused; session revoked")
+)
+
+// SessionClient is the device a session is being used from.
+type SessionClient struct {
+	UserAgent string
+	IP        string
+}
+
+// Session is one sign-in on one device. The refresh tokens it has gone
+// through, and the access tokens they minted, share its ID. UserAgent and IP
+// are from its most recent refresh.
+type Session struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// RefreshRotation is the outcome of exchanging a refresh token. Plaintext and
+// Expiry are empty when the token had just been rotated by a concurrent
+// request: the caller should mint an access token but leave the cookie alone.
+type RefreshRotation struct {
+	UserID    int
+	SessionID string
+	Plaintext string
+	Expiry    time.Time
+}
+
+// sessionTokens runs the session queries against dealership_tokens or
+// internal_tokens, which have the same shape apart from the user column.
+type sessionTokens struct {
+	table      string
+	userColumn string
+}
+
+var (
+	dealershipSessionTokens = sessionTokens{table: "dealership_tokens", userColumn: "dealership_user_id"}
+	internalSessionTokens   = sessionTokens{table: "internal_tokens", userColumn: "internal_user_id"}
+)
+
+func (s sessionTokens) rotate(stdb *sql.DB, plaintext string, client SessionClient) (*RefreshRotation, error) {
+	hash := sha256.Sum256([]byte(plaintext))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := stdb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rotation := &RefreshRotation{}
+	var sessionID sql.NullString
+	var rotated, raced bool
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(`
+		SELECT
+			%s,
+			session_id::text,
+			rotated_at IS NOT NULL,
+			COALESCE(rotated_at > now() - make_interval(secs => $2), false)
+		FROM %s
+		WHERE hash = $1 AND scope = 'refresh' AND expiry > now()
+		FOR UPDATE
+	`, s.userColumn, s.table), hash[:], RefreshReuseGrace.Seconds()).Scan(
+		&rotation.UserID,
+		&sessionID,
+		&rotated,
+		&raced,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A refresh token issued outside a session starts one here.
+	rotation.SessionID = sessionID.String
+	if !sessionID.Valid {
+		rotation.SessionID = uuid.NewString()
+	}
+
+	if rotated {
+		if raced {
+			return rotation, nil
+		}
+
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE session_id = $1`, s.table), rotation.SessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return nil, err
+		}
+
+		return rotation, ErrRefreshTokenReused
+	}
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s SET rotated_at = now(), session_id = $2 WHERE hash = $1
+	`, s.table), hash[:], rotation.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	rotation.Plaintext = rand.Text()
+	rotation.Expiry = time.Now().Add(RefreshTokenTTL)
+	nextHash := sha256.Sum256([]byte(rotation.Plaintext))
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s (hash, %s, expiry, scope, session_id, user_agent, ip)
+		VALUES ($1, $2, $3, 'refresh', $4, $5, $6)
+	`, s.table, s.userColumn), nextHash[:], rotation.UserID, rotation.Expiry, rotation.SessionID, client.UserAgent, client.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return rotation, nil
+}
+
+func (s sessionTokens) list(stdb *sql.DB, userID int) ([]*Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := stdb.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			session_id::text,
+			(ARRAY_AGG(user_agent ORDER BY created_at DESC))[1],
+			(ARRAY_AGG(ip ORDER BY created_at DESC))[1],
+			MIN(created_at),
+			MAX(created_at),
+			MAX(expiry)
+		FROM %s
+		WHERE %s = $1 AND scope = 'refresh' AND session_id IS NOT NULL
+		GROUP BY session_id
+		HAVING bool_or(rotated_at IS NULL AND expiry > now())
+		ORDER BY MAX(created_at) DESC
+	`, s.table, s.userColumn), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*Session{}
+	for rows.Next() {
+		var session Session
+		err := rows.Scan(
+			&session.ID,
+			&session.UserAgent,
+			&session.IP,
+			&session.CreatedAt,
+			&session.LastUsedAt,
+			&session.ExpiresAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (s sessionTokens) sessionID(stdb *sql.DB, scope, plaintext string) (string, bool, error) {
+	hash := sha256.Sum256([]byte(plaintext))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var sessionID sql.NullString
+	err := stdb.QueryRowContext(ctx, fmt.Sprintf(`
+		SELECT session_id::text FROM %s WHERE hash = $1 AND scope = $2
+	`, s.table), hash[:], scope).Scan(&sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return sessionID.String, sessionID.Valid, nil
+}
+
+func (s sessionTokens) revoke(stdb *sql.DB, userID int, sessionID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := stdb.ExecContext(ctx, fmt.Sprintf(`
+		DELETE FROM %s WHERE %s = $1 AND session_id = $2
+	`, s.table, s.userColumn), userID, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (s sessionTokens) revokeByPlaintext(stdb *sql.DB, plaintext string) error {
+	hash := sha256.Sum256([]byte(plaintext))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := stdb.ExecContext(ctx, fmt.Sprintf(`
+		DELETE FROM %[1]s
+		WHERE hash = $1
+			OR session_id = (SELECT session_id FROM %[1]s WHERE hash = $1 AND scope = 'refresh')
+	`, s.table), hash[:])
+	return err
+}
+
+func (s sessionTokens) revokeAll(stdb *sql.DB, userID int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := stdb.ExecContext(ctx, fmt.Sprintf(`
+		DELETE FROM %s WHERE %s = $1 AND scope IN ('refresh', 'access')
+	`, s.table, s.userColumn), userID)
+	return err
+}
+
+// RotateRefreshToken exchanges a refresh token, whichever kind of user it
+// belongs to, for the next one in its session. The user must still be active.
+// On ErrRefreshTokenReused the returned user is the session's owner, for the
+// caller to log.
+func RotateRefreshToken(models *Models, plaintext string, client SessionClient) (AuthUser, *RefreshRotation, error) {
+	rotation, err := models.DealershipTokens.Rotate(plaintext, client)
+	if err == nil || errors.Is(err, ErrRefreshTokenReused) {
+		user, found, lookupErr := models.DealershipUsers.GetByID(rotation.UserID)
+		if lookupErr != nil {
+			return nil, nil, lookupErr
+		}
+		if !found || !user.IsActive {
+			return nil, nil, ErrRefreshTokenNotFound
+		}
+		return user, rotation, err
+	}
+	if !errors.Is(err, ErrRefreshTokenNotFound) {
+		return nil, nil, err
+	}
+
+	rotation, err = models.InternalTokens.Rotate(plaintext, client)
+	if err == nil || errors.Is(err, ErrRefreshTokenReused) {
+		user, found, lookupErr := models.InternalUsers.GetByID(rotation.UserID)
+		if lookupErr != nil {
+			return nil, nil, lookupErr
+		}
+		if !found || !user.IsActive {
+			return nil, nil, ErrRefreshTokenNotFound
+		}
+		return user, rotation, err
+	}
+
+	return nil, nil, err
+}
+
+// GetSessions lists a user's live sessions, most recently used first.
+func GetSessions(models *Models, user AuthUser) ([]*Session, error) {
+	if user.IsDealership() {
+		return models.DealershipTokens.GetSessions(user.GetID())
+	}
+	return models.InternalTokens.GetSessions(user.GetID())
+}
+
+// RevokeSession signs a user out of one session. Reports false if the session
+// is not theirs or is already gone.
+func RevokeSession(models *Models, user AuthUser, sessionID string) (bool, error) {
+	if user.IsDealership() {
+		return models.DealershipTokens.RevokeSession(user.GetID(), sessionID)
+	}
+	return models.InternalTokens.RevokeSession(user.GetID(), sessionID)
+}
+
+// RevokeAllSessions signs a user out everywhere, access tokens included.
+func RevokeAllSessions(models *Models, user AuthUser) error {
+	if user.IsDealership() {
+		return models.DealershipTokens.RevokeAllSessions(user.GetID())
+	}
+	return models.InternalTokens.RevokeAllSessions(user.GetID())
+}
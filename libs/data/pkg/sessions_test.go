@@ -0,0 +1,131 @@
+package data
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSessions_RotateAndDetectReuse(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	user := createTestDealershipUser(t, models, dealership.ID)
+
+	sessionID := uuid.NewString()
+	client := SessionClient{UserAgent: "test-agent", IP: "203.0.113.7"}
+
+	first, err := models.DealershipTokens.NewForSession(user.ID, RefreshTokenTTL, DealershipScopeRefresh, sessionID, client)
+	if err != nil {
+		t.Fatalf("Failed to create refresh token: %v", err)
+	}
+
+	rotation, err := models.DealershipTokens.Rotate(first.Plaintext, client)
+	if err != nil {
+		t.Fatalf("Failed to rotate: %v", err)
+	}
+	if rotation.SessionID != sessionID || rotation.UserID != user.ID {
+		t.Errorf("Rotation left the session: %+v", rotation)
+	}
+	if rotation.Plaintext == "" || rotation.Plaintext == first.Plaintext {
+		t.Fatalf("Expected a new refresh token")
+	}
+
+	raced, err := models.DealershipTokens.Rotate(first.Plaintext, client)
+	if err != nil {
+		t.Fatalf("Expected a just-rotated token to be honoured, got %v", err)
+	}
+	if raced.Plaintext != "" {
+		t.Errorf("Expected no new refresh token for a concurrent refresh")
+	}
+
+	sessions, err := models.DealershipTokens.GetSessions(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to list sessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != sessionID || sessions[0].UserAgent != "test-agent" {
+		t.Fatalf("Expected the one session, got %+v", sessions)
+	}
+
+	_, err = models.STDB.Exec(`UPDATE dealership_tokens SET rotated_at = now() - interval '1 hour' WHERE rotated_at IS NOT NULL`)
+	if err != nil {
+		t.Fatalf("Failed to age rotation: %v", err)
+	}
+
+	_, err = models.DealershipTokens.Rotate(first.Plaintext, client)
+	if !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Expected reuse to be detected, got %v", err)
+	}
+
+	_, err = models.DealershipTokens.Rotate(rotation.Plaintext, client)
+	if !errors.Is(err, ErrRefreshTokenNotFound) {
+		t.Errorf("Expected reuse to revoke the whole session, got %v", err)
+	}
+
+	sessions, err = models.DealershipTokens.GetSessions(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to list sessions: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("Expected no sessions left, got %d", len(sessions))
+	}
+}
+
+func TestSessions_Revoke(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	dealership := createTestDealership(t, models)
+	user := createTestDealershipUser(t, models, dealership.ID)
+
+	laptop := uuid.NewString()
+	phone := uuid.NewString()
+	for _, sessionID := range []string{laptop, phone} {
+		_, err := models.DealershipTokens.NewForSession(user.ID, RefreshTokenTTL, DealershipScopeRefresh, sessionID, SessionClient{})
+		if err != nil {
+			t.Fatalf("Failed to create refresh token: %v", err)
+		}
+	}
+
+	access, err := models.DealershipTokens.NewForSession(user.ID, time.Hour, DealershipScopeAccess, laptop, SessionClient{})
+	if err != nil {
+		t.Fatalf("Failed to create access token: %v", err)
+	}
+
+	revoked, err := models.DealershipTokens.RevokeSession(user.ID, laptop)
+	if err != nil || !revoked {
+		t.Fatalf("Expected to revoke the session: revoked=%v err=%v", revoked, err)
+	}
+
+	_, found, err := models.DealershipUsers.GetForToken(DealershipScopeAccess, access.Plaintext)
+	if err != nil {
+		t.Fatalf("Failed to look up access token: %v", err)
+	}
+	if found {
+		t.Errorf("Expected the session's access tokens to be revoked with it")
+	}
+
+	sessions, err := models.DealershipTokens.GetSessions(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to list sessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != phone {
+		t.Errorf("Expected only the phone session left, got %+v", sessions)
+	}
+
+	err = models.DealershipTokens.RevokeAllSessions(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to revoke all sessions: %v", err)
+	}
+
+	sessions, err = models.DealershipTokens.GetSessions(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to list sessions: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("Expected no sessions left, got %d", len(sessions))
+	}
+}
@@ -139,6 +139,10 @@ func getTestModels(t *testing.T) Models {
 func cleanupTables(t *testing.T) {
 	t.Helper()
 	_, err := testDB.STDB.Exec(`TRUNCATE TABLE
+		shipment_inlays,
+		shipments,
+		inlay_remake_photos,
+		inlay_remakes,
 		inlay_updates,
 		inlay_milestones,
 		inlay_proofs,
@@ -148,12 +152,20 @@ func cleanupTables(t *testing.T) {
 		inlay_catalog_infos,
 		inlays,
 		order_snapshots,
+		project_quote_lines,
+		project_quotes,
+		dealership_price_tier_overrides,
+		dealership_price_tiers,
+		material_reservations,
+		material_stocks,
 		invoices,
 		project_chats,
 		projects,
+		shipping_addresses,
 		catalog_item_tags,
 		catalog_items,
 		support_articles,
+		tax_rates,
 		price_groups,
 		glass_colors,
 		grouts,
@@ -165,12 +177,41 @@ func cleanupTables(t *testing.T) {
 		internal_user_notification_prefs,
 		notifications,
 		project_watchers,
+		invitations,
+		internal_user_recovery_codes,
+		internal_user_two_factor,
+		rate_limit_buckets,
+		oidc_provider_dealerships,
+		oidc_providers,
+		impersonation_requests,
+		impersonations,
+		job_runs,
+		dealership_user_permissions,
+		internal_user_permissions,
 		dealership_users,
 		internal_users,
-		dealerships CASCADE`)
+		dealerships,
+		dealership_groups CASCADE`)
 	if err != nil {
 		t.Fatalf("Failed to truncate tables: %v", err)
 	}
+
+	// The built-in roles are seeded by migrations, so only custom roles go.
+	_, err = testDB.STDB.Exec(`DELETE FROM dealership_roles WHERE NOT is_builtin`)
+	if err != nil {
+		t.Fatalf("Failed to delete dealership roles: %v", err)
+	}
+	_, err = testDB.STDB.Exec(`DELETE FROM internal_roles WHERE NOT is_builtin`)
+	if err != nil {
+		t.Fatalf("Failed to delete internal roles: %v", err)
+	}
+
+	// two_factor_policy is a single settings row, so it is reset rather than
+	// truncated.
+	_, err = testDB.STDB.Exec(`UPDATE two_factor_policy SET require_for_privileged_roles = false`)
+	if err != nil {
+		t.Fatalf("Failed to reset two-factor policy: %v", err)
+	}
 }
 
 func createTestDealership(t *testing.T, models Models) *Dealership {
@@ -0,0 +1,259 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+// ManufacturingStepOrder is the production ladder, first step to last.
+var ManufacturingStepOrder = []ManufacturingStep{
+	ManufacturingSteps.Ordered,
+	ManufacturingSteps.MaterialsPrep,
+	ManufacturingSteps.Manufacturing,
+	ManufacturingSteps.Packaging,
+	ManufacturingSteps.ReadyToShip,
+}
+
+// StepHistoryDays is how far back dwell times are drawn from. Long enough to
+// smooth out a bad week, short enough to follow changes on the floor.
+const StepHistoryDays = 180
+
+// MinStepSamples is the fewest dwell times a group needs before its own
+// percentiles are trusted over the figures for every inlay.
+const MinStepSamples = 5
+
+// StepGrouping splits step durations by something that changes how long an
+// inlay takes.
+type StepGrouping string
+
+type stepGroupings struct {
+	None       StepGrouping
+	PriceGroup StepGrouping
+	InlayType  StepGrouping
+}
+
+var StepGroupings = stepGroupings{
+	None:       StepGrouping(""),
+	PriceGroup: StepGrouping("price_group"),
+	InlayType:  StepGrouping("inlay_type"),
+}
+
+// StepDuration is how long inlays have historically spent in one step, from
+// entering it to leaving it. GroupKey is the price group ID or inlay type the
+// figures are for, empty when they cover every inlay.
+type StepDuration struct {
+	Step     ManufacturingStep `json:"step"`
+	GroupKey string            `json:"group_key"`
+	Samples  int               `json:"samples"`
+	P50Hours float64           `json:"p50_hours"`
+	P80Hours float64           `json:"p80_hours"`
+	P90Hours float64           `json:"p90_hours"`
+}
+
+// GetStepDurations measures every stay in a step that ended between from and
+// to. A stay runs from the latest entered or reverted event for the step to
+// the exited event that closes it, within one cycle, so remakes are measured
+// on their own. Placing an order records no milestone, so a first stay in
+// ordered is timed from when the project was ordered.
+func (m InlayMilestoneModel) GetStepDurations(from, to time.Time, grouping StepGrouping) ([]StepDuration, error) {
+	var groupJoin, groupKey string
+	switch grouping {
+	case StepGroupings.None:
+		groupKey = "''"
+	case StepGroupings.PriceGroup:
+		groupJoin = "LEFT JOIN order_snapshots os ON os.inlay_id = dwell.inlay_id AND os.remake_id IS NULL"
+		groupKey = "COALESCE(os.price_group_id::text, '')"
+	case StepGroupings.InlayType:
+		groupKey = "dwell.inlay_type"
+	default:
+		return nil, fmt.Errorf("unknown step grouping %q", grouping)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rows, err := m.STDB.QueryContext(ctx, `
+		WITH dwell AS (
+			SELECT ex.step, ex.inlay_id, i.type AS inlay_type,
+			       EXTRACT(EPOCH FROM ex.event_time - started.at) / 3600.0 AS hours
+			FROM inlay_milestones ex
+			JOIN inlays i ON i.id = ex.inlay_id
+			JOIN projects p ON p.id = i.project_id
+			CROSS JOIN LATERAL (
+				SELECT COALESCE(
+					(
+						SELECT en.event_time FROM inlay_milestones en
+						WHERE en.inlay_id = ex.inlay_id AND en.cycle = ex.cycle AND en.step = ex.step
+						AND en.event_type IN ('entered', 'reverted') AND en.id < ex.id
+						ORDER BY en.id DESC
+						LIMIT 1
+					),
+					CASE WHEN ex.step = 'ordered' AND ex.cycle = 1 THEN p.ordered_at END
+				) AS at
+			) started
+			WHERE ex.event_type = 'exited' AND ex.event_time >= $1 AND ex.event_time < $2
+			AND started.at IS NOT NULL
+		)
+		SELECT dwell.step, `+groupKey+`, COUNT(*),
+		       percentile_cont(0.5) WITHIN GROUP (ORDER BY dwell.hours),
+		       percentile_cont(0.8) WITHIN GROUP (ORDER BY dwell.hours),
+		       percentile_cont(0.9) WITHIN GROUP (ORDER BY dwell.hours)
+		FROM dwell
+		`+groupJoin+`
+		GROUP BY 1, 2
+		ORDER BY 1, 2
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	durations := []StepDuration{}
+	for rows.Next() {
+		var d StepDuration
+		if err := rows.Scan(&d.Step, &d.GroupKey, &d.Samples, &d.P50Hours, &d.P80Hours, &d.P90Hours); err != nil {
+			return nil, err
+		}
+		durations = append(durations, d)
+	}
+	return durations, rows.Err()
+}
+
+// GetCurrentStepEntries returns, per inlay, the event that put it in the step
+// it is in now: its latest entered or reverted milestone.
+func (m InlayMilestoneModel) GetCurrentStepEntries(inlayIDs []int) (map[int]*InlayMilestone, error) {
+	entries := make(map[int]*InlayMilestone, len(inlayIDs))
+	if len(inlayIDs) == 0 {
+		return entries, nil
+	}
+
+	ids := make([]postgres.Expression, len(inlayIDs))
+	for i, id := range inlayIDs {
+		ids[i] = postgres.Int(int64(id))
+	}
+
+	query := postgres.SELECT(
+		table.InlayMilestones.AllColumns,
+	).FROM(
+		table.InlayMilestones,
+	).WHERE(
+		table.InlayMilestones.InlayID.IN(ids...).AND(
+			table.InlayMilestones.EventType.IN(
+				postgres.String(string(MilestoneEventTypes.Entered)),
+				postgres.String(string(MilestoneEventTypes.Reverted)),
+			),
+		),
+	).ORDER_BY(
+		table.InlayMilestones.InlayID.ASC(),
+		table.InlayMilestones.ID.DESC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.InlayMilestones
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range dest {
+		if _, seen := entries[int(d.InlayID)]; !seen {
+			entries[int(d.InlayID)] = inlayMilestoneFromGen(d)
+		}
+	}
+	return entries, nil
+}
+
+// ShipEstimate is when an inlay is expected to be ready to ship. ExpectedAt
+// takes each remaining step at its median; LatestAt at its 90th percentile.
+type ShipEstimate struct {
+	Step       ManufacturingStep `json:"step"`
+	ExpectedAt time.Time         `json:"expected_at"`
+	LatestAt   time.Time         `json:"latest_at"`
+}
+
+// ShipEstimator turns historical step durations into ship dates. It prefers the
+// figures for the inlay's price group and falls back to those for every inlay
+// while the group has too little history.
+type ShipEstimator struct {
+	overall      map[ManufacturingStep]StepDuration
+	byPriceGroup map[string]map[ManufacturingStep]StepDuration
+}
+
+func NewShipEstimator(overall, byPriceGroup []StepDuration) *ShipEstimator {
+	e := &ShipEstimator{
+		overall:      make(map[ManufacturingStep]StepDuration, len(overall)),
+		byPriceGroup: make(map[string]map[ManufacturingStep]StepDuration),
+	}
+	for _, d := range overall {
+		e.overall[d.Step] = d
+	}
+	for _, d := range byPriceGroup {
+		if d.GroupKey == "" || d.Samples < MinStepSamples {
+			continue
+		}
+		if e.byPriceGroup[d.GroupKey] == nil {
+			e.byPriceGroup[d.GroupKey] = make(map[ManufacturingStep]StepDuration)
+		}
+		e.byPriceGroup[d.GroupKey][d.Step] = d
+	}
+	return e
+}
+
+func (e *ShipEstimator) duration(step ManufacturingStep, priceGroupID int) (StepDuration, bool) {
+	if d, ok := e.byPriceGroup[strconv.Itoa(priceGroupID)][step]; ok {
+		return d, true
+	}
+	d, ok := e.overall[step]
+	return d, ok
+}
+
+// Estimate dates an inlay that entered its current step at enteredAt. Time
+// already spent in the step counts against that step's typical stay. It
+// reports false when some remaining step has no history to go on.
+func (e *ShipEstimator) Estimate(step ManufacturingStep, enteredAt time.Time, priceGroupID int, now time.Time) (ShipEstimate, bool) {
+	estimate := ShipEstimate{Step: step, ExpectedAt: now, LatestAt: now}
+
+	current := -1
+	for i, s := range ManufacturingStepOrder {
+		if s == step {
+			current = i
+		}
+	}
+	if current == -1 {
+		return ShipEstimate{}, false
+	}
+
+	elapsed := now.Sub(enteredAt).Hours()
+	var expectedHours, latestHours float64
+	for i := current; i < len(ManufacturingStepOrder); i++ {
+		s := ManufacturingStepOrder[i]
+		if s == ManufacturingSteps.ReadyToShip {
+			break
+		}
+
+		d, ok := e.duration(s, priceGroupID)
+		if !ok {
+			return ShipEstimate{}, false
+		}
+
+		if i == current {
+			expectedHours += max(0, d.P50Hours-elapsed)
+			latestHours += max(0, d.P90Hours-elapsed)
+		} else {
+			expectedHours += d.P50Hours
+			latestHours += d.P90Hours
+		}
+	}
+
+	estimate.ExpectedAt = now.Add(time.Duration(expectedHours * float64(time.Hour)))
+	estimate.LatestAt = now.Add(time.Duration(latestHours * float64(time.Hour)))
+	return estimate, true
+}
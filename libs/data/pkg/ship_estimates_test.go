@@ -0,0 +1,69 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShipEstimatorEstimate(t *testing.T) {
+	overall := []StepDuration{
+		{Step: ManufacturingSteps.Ordered, Samples: 10, P50Hours: 4, P90Hours: 8},
+		{Step: ManufacturingSteps.MaterialsPrep, Samples: 10, P50Hours: 10, P90Hours: 20},
+		{Step: ManufacturingSteps.Manufacturing, Samples: 10, P50Hours: 20, P90Hours: 40},
+		{Step: ManufacturingSteps.Packaging, Samples: 10, P50Hours: 2, P90Hours: 4},
+	}
+	byPriceGroup := []StepDuration{
+		{Step: ManufacturingSteps.Manufacturing, GroupKey: "7", Samples: MinStepSamples, P50Hours: 50, P90Hours: 60},
+		{Step: ManufacturingSteps.Manufacturing, GroupKey: "8", Samples: MinStepSamples - 1, P50Hours: 90, P90Hours: 99},
+	}
+	estimator := NewShipEstimator(overall, byPriceGroup)
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		step         ManufacturingStep
+		hoursInStep  float64
+		priceGroupID int
+		wantExpected float64
+		wantLatest   float64
+	}{
+		{"just entered counts every remaining step", ManufacturingSteps.MaterialsPrep, 0, 1, 32, 64},
+		{"time in step is taken off its stay", ManufacturingSteps.Manufacturing, 5, 1, 17, 39},
+		{"overdue step counts as done", ManufacturingSteps.Manufacturing, 45, 1, 2, 4},
+		{"price group with enough history wins", ManufacturingSteps.Manufacturing, 0, 7, 52, 64},
+		{"thin price group falls back", ManufacturingSteps.Manufacturing, 0, 8, 22, 44},
+		{"ready to ship is now", ManufacturingSteps.ReadyToShip, 10, 1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enteredAt := now.Add(-time.Duration(tt.hoursInStep * float64(time.Hour)))
+			got, ok := estimator.Estimate(tt.step, enteredAt, tt.priceGroupID, now)
+			if !ok {
+				t.Fatalf("Estimate(%q) reported no estimate", tt.step)
+			}
+			if want := now.Add(time.Duration(tt.wantExpected * float64(time.Hour))); !got.ExpectedAt.Equal(want) {
+				t.Errorf("ExpectedAt = %v, want %v", got.ExpectedAt, want)
+			}
+			if want := now.Add(time.Duration(tt.wantLatest * float64(time.Hour))); !got.LatestAt.Equal(want) {
+				t.Errorf("LatestAt = %v, want %v", got.LatestAt, want)
+			}
+		})
+	}
+}
+
+func TestShipEstimatorEstimateWithoutHistory(t *testing.T) {
+	estimator := NewShipEstimator([]StepDuration{
+		{Step: ManufacturingSteps.Packaging, Samples: 10, P50Hours: 2, P90Hours: 4},
+	}, nil)
+
+	if _, ok := estimator.Estimate(ManufacturingSteps.Manufacturing, time.Now(), 1, time.Now()); ok {
+		t.Errorf("expected no estimate when manufacturing has no history")
+	}
+	if _, ok := estimator.Estimate(ManufacturingSteps.Packaging, time.Now(), 1, time.Now()); !ok {
+		t.Errorf("expected an estimate when every remaining step has history")
+	}
+	if _, ok := estimator.Estimate(ManufacturingStep("bogus"), time.Now(), 1, time.Now()); ok {
+		t.Errorf("expected no estimate for an unknown step")
+	}
+}
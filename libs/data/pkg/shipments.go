@@ -0,0 +1,425 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ShipmentCarrier string
+
+type shipmentCarriers struct {
+	UPS   ShipmentCarrier
+	FedEx ShipmentCarrier
+	USPS  ShipmentCarrier
+	DHL   ShipmentCarrier
+	Other ShipmentCarrier
+}
+
+var ShipmentCarriers = shipmentCarriers{
+	UPS:   ShipmentCarrier("ups"),
+	FedEx: ShipmentCarrier("fedex"),
+	USPS:  ShipmentCarrier("usps"),
+	DHL:   ShipmentCarrier("dhl"),
+	Other: ShipmentCarrier("other"),
+}
+
+var trackingURLFormats = map[ShipmentCarrier]string{
+	ShipmentCarriers.UPS:   "https://www.ups.com/track?tracknum=%s",
+	ShipmentCarriers.FedEx: "https://www.fedex.com/fedextrack/?trknbr=%s",
+	ShipmentCarriers.USPS:  "https://tools.usps.com/go/TrackConfirmAction?tLabels=%s",
+	ShipmentCarriers.DHL:   "https://www.dhl.com/us-en/home/tracking/tracking-express.html?submit=1&tracking-id=%s",
+}
+
+// TrackingURL links to the carrier's public tracking page for a tracking
+// number. There is none for carriers we do not know, or without a number.
+func TrackingURL(carrier ShipmentCarrier, trackingNumber *string) *string {
+	format, ok := trackingURLFormats[carrier]
+	if !ok || trackingNumber == nil || *trackingNumber == "" {
+		return nil
+	}
+	link := fmt.Sprintf(format, url.QueryEscape(*trackingNumber))
+	return &link
+}
+
+// Shipment is one package sent for a project. TrackingURL is derived from the
+// carrier and tracking number and is never written. InlayIDs are the inlays
+// packed in it; each inlay ships in exactly one package of the original order.
+// A package sent for a remake has RemakeID set and holds just the remade inlay.
+// ShipTo is the address it was sent to, copied from the order.
+type Shipment struct {
+	StandardTable
+	ProjectID      int             `json:"project_id"`
+	Carrier        ShipmentCarrier `json:"carrier"`
+	TrackingNumber *string         `json:"tracking_number"`
+	TrackingURL    *string         `json:"tracking_url"`
+	WeightLbs      *float64        `json:"weight_lbs"`
+	ShippedAt      time.Time       `json:"shipped_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	InlayIDs       []int           `json:"inlay_ids"`
+	RemakeID       *int            `json:"remake_id"`
+	ShipTo         *ShipTo         `json:"ship_to"`
+}
+
+// ShipmentProgress counts a project's inlays against the packages of the
+// original order they went out in; remake packages are not counted. A project
+// with no inlays has nothing left to ship or deliver.
+type ShipmentProgress struct {
+	Inlays    int `json:"inlays"`
+	Shipped   int `json:"shipped"`
+	Delivered int `json:"delivered"`
+}
+
+func (p ShipmentProgress) AllShipped() bool {
+	return p.Shipped >= p.Inlays
+}
+
+func (p ShipmentProgress) AllDelivered() bool {
+	return p.Delivered >= p.Inlays
+}
+
+type ShipmentModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func shipmentFromGen(gen model.Shipments) *Shipment {
+	carrier := ShipmentCarrier(gen.Carrier)
+	return &Shipment{
+		StandardTable: StandardTable{
+			ID:        int(gen.ID),
+			UUID:      gen.UUID.String(),
+			CreatedAt: gen.CreatedAt,
+			UpdatedAt: gen.UpdatedAt,
+			Version:   int(gen.Version),
+		},
+		ProjectID:      int(gen.ProjectID),
+		Carrier:        carrier,
+		TrackingNumber: gen.TrackingNumber,
+		TrackingURL:    TrackingURL(carrier, gen.TrackingNumber),
+		WeightLbs:      gen.WeightLbs,
+		ShippedAt:      gen.ShippedAt,
+		DeliveredAt:    gen.DeliveredAt,
+		InlayIDs:       []int{},
+		ShipTo:         shipToFromGen(gen.ShipTo),
+	}
+}
+
+func shipmentToGen(s *Shipment) (*model.Shipments, error) {
+	var shipmentUUID uuid.UUID
+	var err error
+
+	if s.UUID != "" {
+		shipmentUUID, err = uuid.Parse(s.UUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	carrier := s.Carrier
+	if carrier == "" {
+		carrier = ShipmentCarriers.Other
+	}
+
+	shippedAt := s.ShippedAt
+	if shippedAt.IsZero() {
+		shippedAt = time.Now()
+	}
+
+	shipTo, err := shipToToGen(s.ShipTo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Shipments{
+		ID:             int32(s.ID),
+		UUID:           shipmentUUID,
+		ProjectID:      int32(s.ProjectID),
+		Carrier:        string(carrier),
+		TrackingNumber: s.TrackingNumber,
+		WeightLbs:      s.WeightLbs,
+		ShippedAt:      shippedAt,
+		DeliveredAt:    s.DeliveredAt,
+		ShipTo:         shipTo,
+		UpdatedAt:      s.UpdatedAt,
+		CreatedAt:      s.CreatedAt,
+		Version:        int32(s.Version),
+	}, nil
+}
+
+// TxInsert records a package and the inlays packed in it. An inlay that
+// already shipped with the original order, or a remake that already shipped,
+// fails the insert on shipment_inlays' unique indexes.
+func (m ShipmentModel) TxInsert(tx *sql.Tx, shipment *Shipment) error {
+	gen, err := shipmentToGen(shipment)
+	if err != nil {
+		return err
+	}
+
+	query := table.Shipments.INSERT(
+		table.Shipments.ProjectID,
+		table.Shipments.Carrier,
+		table.Shipments.TrackingNumber,
+		table.Shipments.WeightLbs,
+		table.Shipments.ShippedAt,
+		table.Shipments.DeliveredAt,
+		table.Shipments.ShipTo,
+	).MODEL(
+		gen,
+	).RETURNING(
+		table.Shipments.AllColumns,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.Shipments
+	err = query.QueryContext(ctx, tx, &dest)
+	if err != nil {
+		return err
+	}
+
+	inlayIDs := shipment.InlayIDs
+	remakeID := shipment.RemakeID
+	*shipment = *shipmentFromGen(dest)
+
+	var genRemakeID *int32
+	if remakeID != nil {
+		v := int32(*remakeID)
+		genRemakeID = &v
+	}
+
+	if len(inlayIDs) > 0 {
+		rows := make([]model.ShipmentInlays, len(inlayIDs))
+		for i, inlayID := range inlayIDs {
+			rows[i] = model.ShipmentInlays{ShipmentID: dest.ID, InlayID: int32(inlayID), RemakeID: genRemakeID}
+		}
+
+		insertInlays := table.ShipmentInlays.INSERT(
+			table.ShipmentInlays.ShipmentID,
+			table.ShipmentInlays.InlayID,
+			table.ShipmentInlays.RemakeID,
+		).MODELS(rows)
+
+		if _, err := insertInlays.ExecContext(ctx, tx); err != nil {
+			return err
+		}
+		shipment.InlayIDs = inlayIDs
+		shipment.RemakeID = remakeID
+	}
+
+	return nil
+}
+
+func (m ShipmentModel) GetByUUID(uuidStr string) (*Shipment, bool, error) {
+	parsedUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	query := postgres.SELECT(
+		table.Shipments.AllColumns,
+	).FROM(
+		table.Shipments,
+	).WHERE(
+		table.Shipments.UUID.EQ(postgres.UUID(parsedUUID)),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.Shipments
+	err = query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		switch {
+		case errors.Is(err, qrm.ErrNoRows):
+			return nil, false, nil
+		default:
+			return nil, false, err
+		}
+	}
+
+	shipment := shipmentFromGen(dest)
+	if err := m.withInlayIDs(ctx, []*Shipment{shipment}); err != nil {
+		return nil, false, err
+	}
+	return shipment, true, nil
+}
+
+// GetByProjectID returns a project's packages in the order they were sent.
+func (m ShipmentModel) GetByProjectID(projectID int) ([]*Shipment, error) {
+	query := postgres.SELECT(
+		table.Shipments.AllColumns,
+	).FROM(
+		table.Shipments,
+	).WHERE(
+		table.Shipments.ProjectID.EQ(postgres.Int(int64(projectID))),
+	).ORDER_BY(
+		table.Shipments.ShippedAt.ASC(),
+		table.Shipments.ID.ASC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.Shipments
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	shipments := make([]*Shipment, len(dest))
+	for i, d := range dest {
+		shipments[i] = shipmentFromGen(d)
+	}
+
+	if err := m.withInlayIDs(ctx, shipments); err != nil {
+		return nil, err
+	}
+	return shipments, nil
+}
+
+func (m ShipmentModel) withInlayIDs(ctx context.Context, shipments []*Shipment) error {
+	if len(shipments) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*Shipment, len(shipments))
+	ids := make([]postgres.Expression, len(shipments))
+	for i, s := range shipments {
+		byID[s.ID] = s
+		ids[i] = postgres.Int(int64(s.ID))
+	}
+
+	query := postgres.SELECT(
+		table.ShipmentInlays.AllColumns,
+	).FROM(
+		table.ShipmentInlays,
+	).WHERE(
+		table.ShipmentInlays.ShipmentID.IN(ids...),
+	).ORDER_BY(
+		table.ShipmentInlays.InlayID.ASC(),
+	)
+
+	var dest []model.ShipmentInlays
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range dest {
+		s := byID[int(d.ShipmentID)]
+		s.InlayIDs = append(s.InlayIDs, int(d.InlayID))
+		if d.RemakeID != nil {
+			id := int(*d.RemakeID)
+			s.RemakeID = &id
+		}
+	}
+	return nil
+}
+
+// TxGetUnshippedInlayIDs returns the project's ordered inlays not yet in any
+// package of the original order. Inlays left out of the order never ship.
+func (m ShipmentModel) TxGetUnshippedInlayIDs(tx *sql.Tx, projectID int) ([]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT i.id FROM inlays i
+		JOIN order_snapshots os ON os.inlay_id = i.id AND os.remake_id IS NULL
+		WHERE i.project_id = $1
+		AND NOT EXISTS (
+			SELECT 1 FROM shipment_inlays si WHERE si.inlay_id = i.id AND si.remake_id IS NULL
+		)
+		ORDER BY i.id
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// TxProgress counts how many of a project's ordered inlays have shipped and how
+// many of those have arrived, read inside the transaction that just changed
+// them.
+func (m ShipmentModel) TxProgress(tx *sql.Tx, projectID int) (ShipmentProgress, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var progress ShipmentProgress
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(i.id), COUNT(si.id), COUNT(s.delivered_at)
+		FROM inlays i
+		JOIN order_snapshots os ON os.inlay_id = i.id AND os.remake_id IS NULL
+		LEFT JOIN shipment_inlays si ON si.inlay_id = i.id AND si.remake_id IS NULL
+		LEFT JOIN shipments s ON s.id = si.shipment_id
+		WHERE i.project_id = $1
+	`, projectID).Scan(&progress.Inlays, &progress.Shipped, &progress.Delivered)
+	return progress, err
+}
+
+// TxMarkDelivered stamps a package delivered. Delivering it again keeps the
+// first delivery time.
+func (m ShipmentModel) TxMarkDelivered(tx *sql.Tx, shipment *Shipment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return tx.QueryRowContext(ctx, `
+		UPDATE shipments SET delivered_at = COALESCE(delivered_at, now())
+		WHERE id = $1
+		RETURNING delivered_at, updated_at, version
+	`, shipment.ID).Scan(&shipment.DeliveredAt, &shipment.UpdatedAt, &shipment.Version)
+}
+
+// TxMarkProjectDelivered stamps every package of a project's original order
+// still in transit. Remake packages are delivered one at a time.
+func (m ShipmentModel) TxMarkProjectDelivered(tx *sql.Tx, projectID int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE shipments SET delivered_at = now()
+		WHERE project_id = $1 AND delivered_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM shipment_inlays si WHERE si.shipment_id = shipments.id AND si.remake_id IS NOT NULL
+		)
+	`, projectID)
+	return err
+}
+
+// IsInlayDelivered reports whether the package an inlay went out in with its
+// original order has arrived.
+func (m ShipmentModel) IsInlayDelivered(inlayID int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var delivered bool
+	err := m.STDB.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM shipment_inlays si
+			JOIN shipments s ON s.id = si.shipment_id
+			WHERE si.inlay_id = $1 AND si.remake_id IS NULL AND s.delivered_at IS NOT NULL
+		)
+	`, inlayID).Scan(&delivered)
+	return delivered, err
+}
@@ -0,0 +1,116 @@
+package data
+
+import (
+	"testing"
+)
+
+func insertTestShipment(t *testing.T, models Models, shipment *Shipment) error {
+	t.Helper()
+
+	tx, err := models.STDB.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := models.Shipments.TxInsert(tx, shipment); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	return nil
+}
+
+func TestTrackingURL(t *testing.T) {
+	number := "1Z 999"
+	if got := TrackingURL(ShipmentCarriers.UPS, &number); got == nil || *got != "https://www.ups.com/track?tracknum=1Z+999" {
+		t.Errorf("Unexpected UPS link: %v", got)
+	}
+	if got := TrackingURL(ShipmentCarriers.Other, &number); got != nil {
+		t.Errorf("Expected no link for an unknown carrier, got %q", *got)
+	}
+	if got := TrackingURL(ShipmentCarriers.FedEx, nil); got != nil {
+		t.Errorf("Expected no link without a tracking number, got %q", *got)
+	}
+}
+
+func TestShipment_InsertProgressAndDeliver(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+	models := getTestModels(t)
+
+	dealership := createTestDealership(t, models)
+	project := createTestProject(t, models, dealership.ID)
+	priceGroup := createTestPriceGroup(t, models)
+	first := createTestInlay(t, models, project.ID)
+	second := createTestInlay(t, models, project.ID)
+	// Left out of the order, so never shipped or counted.
+	createTestInlay(t, models, project.ID)
+
+	for _, inlay := range []*Inlay{first, second} {
+		err := models.OrderSnapshots.Insert(&OrderSnapshot{
+			ProjectID:    project.ID,
+			InlayID:      inlay.ID,
+			PriceGroupID: priceGroup.ID,
+			PriceCents:   10000,
+			Width:        10.0,
+			Height:       10.0,
+		})
+		if err != nil {
+			t.Fatalf("Failed to insert order snapshot: %v", err)
+		}
+	}
+
+	number := "1Z999AA10123456784"
+	shipment := &Shipment{ProjectID: project.ID, Carrier: ShipmentCarriers.UPS, TrackingNumber: &number, InlayIDs: []int{first.ID}}
+	if err := insertTestShipment(t, models, shipment); err != nil {
+		t.Fatalf("Failed to insert shipment: %v", err)
+	}
+	if shipment.UUID == "" || shipment.TrackingURL == nil {
+		t.Errorf("Expected generated uuid and tracking url, got %+v", shipment)
+	}
+
+	again := &Shipment{ProjectID: project.ID, InlayIDs: []int{first.ID}}
+	if err := insertTestShipment(t, models, again); err == nil {
+		t.Error("Expected an inlay to ship only once")
+	}
+
+	tx, err := models.STDB.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	unshipped, err := models.Shipments.TxGetUnshippedInlayIDs(tx, project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get unshipped inlays: %v", err)
+	}
+	if len(unshipped) != 1 || unshipped[0] != second.ID {
+		t.Errorf("Expected only the second inlay unshipped, got %v", unshipped)
+	}
+
+	if err := models.Shipments.TxMarkDelivered(tx, shipment); err != nil {
+		t.Fatalf("Failed to mark delivered: %v", err)
+	}
+	progress, err := models.Shipments.TxProgress(tx, project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get progress: %v", err)
+	}
+	if progress != (ShipmentProgress{Inlays: 2, Shipped: 1, Delivered: 1}) {
+		t.Errorf("Unexpected progress: %+v", progress)
+	}
+	if progress.AllShipped() {
+		t.Error("Expected the project to still have an inlay to ship")
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	shipments, err := models.Shipments.GetByProjectID(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get shipments: %v", err)
+	}
+	if len(shipments) != 1 || shipments[0].DeliveredAt == nil || len(shipments[0].InlayIDs) != 1 {
+		t.Errorf("Unexpected shipments: %+v", shipments)
+	}
+}
@@ -0,0 +1,382 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ShipTo is where an order goes. Orders and packages keep their own copy, so
+// later edits to a saved address never change where they went.
+type ShipTo struct {
+	Recipient  string `json:"recipient"`
+	Street     string `json:"street"`
+	StreetExt  string `json:"street_ext"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// DealershipShipTo is the ship-to for an order that names no saved address:
+// the dealership itself.
+func DealershipShipTo(d *Dealership) ShipTo {
+	return ShipTo{
+		Recipient:  d.Name,
+		Street:     d.Address.Street,
+		StreetExt:  d.Address.StreetExt,
+		City:       d.Address.City,
+		State:      d.Address.State,
+		PostalCode: d.Address.PostalCode,
+		Country:    d.Address.Country,
+	}
+}
+
+func shipToFromGen(gen *string) *ShipTo {
+	if gen == nil || *gen == "" {
+		return nil
+	}
+	var shipTo ShipTo
+	if err := json.Unmarshal([]byte(*gen), &shipTo); err != nil {
+		return nil
+	}
+	return &shipTo
+}
+
+func shipToToGen(s *ShipTo) (*string, error) {
+	if s == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	str := string(b)
+	return &str, nil
+}
+
+type addressRule struct {
+	regions    []string
+	postalCode *regexp.Regexp
+	// formatPostalCode rewrites a valid postal code the way the post office
+	// prints it.
+	formatPostalCode func(string) string
+}
+
+var addressRules = map[string]addressRule{
+	"US": {
+		regions: []string{
+			"AL", "AK", "AZ", "AR", "CA", "CO", "CT", "DE", "DC", "FL", "GA", "HI", "ID", "IL", "IN", "IA",
+			"KS", "KY", "LA", "ME", "MD", "MA", "MI", "MN", "MS", "MO", "MT", "NE", "NV", "NH", "NJ", "NM",
+			"NY", "NC", "ND", "OH", "OK", "OR", "PA", "RI", "SC", "SD", "TN", "TX", "UT", "VT", "VA", "WA",
+			"WV", "WI", "WY", "AS", "GU", "MP", "PR", "VI", "AA", "AE", "AP",
+		},
+		postalCode: regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	},
+	"CA": {
+		regions:    []string{"AB", "BC", "MB", "NB", "NL", "NS", "NT", "NU", "ON", "PE", "QC", "SK", "YT"},
+		postalCode: regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z] ?\d[ABCEGHJ-NPRSTV-Z]\d$`),
+		formatPostalCode: func(code string) string {
+			code = strings.ReplaceAll(code, " ", "")
+			return code[:3] + " " + code[3:]
+		},
+	},
+	"MX": {
+		postalCode: regexp.MustCompile(`^\d{5}$`),
+	},
+}
+
+// NormalizeAddress checks a ship-to against the rules of its country and
+// returns it tidied: trimmed, with country, state and postal code upper-cased
+// and the postal code in the country's usual form. The US and Canada need a
+// known state or province and a well-formed postal code; Mexico a state and a
+// five-digit postal code. Other countries only need a street and city.
+func NormalizeAddress(s ShipTo) (ShipTo, error) {
+	s.Recipient = strings.TrimSpace(s.Recipient)
+	s.Street = strings.TrimSpace(s.Street)
+	s.StreetExt = strings.TrimSpace(s.StreetExt)
+	s.City = strings.TrimSpace(s.City)
+	s.State = strings.ToUpper(strings.TrimSpace(s.State))
+	s.PostalCode = strings.ToUpper(strings.TrimSpace(s.PostalCode))
+	s.Country = strings.ToUpper(strings.TrimSpace(s.Country))
+
+	if len(s.Country) != 2 {
+		return s, fmt.Errorf("country must be a two-letter code")
+	}
+	if s.Street == "" {
+		return s, fmt.Errorf("street is required")
+	}
+	if s.City == "" {
+		return s, fmt.Errorf("city is required")
+	}
+
+	rule, ok := addressRules[s.Country]
+	if !ok {
+		return s, nil
+	}
+
+	switch {
+	case rule.regions != nil && !slices.Contains(rule.regions, s.State):
+		return s, fmt.Errorf("%q is not a state or province in %s", s.State, s.Country)
+	case s.State == "":
+		return s, fmt.Errorf("state is required in %s", s.Country)
+	case !rule.postalCode.MatchString(s.PostalCode):
+		return s, fmt.Errorf("%q is not a valid postal code in %s", s.PostalCode, s.Country)
+	}
+
+	if rule.formatPostalCode != nil {
+		s.PostalCode = rule.formatPostalCode(s.PostalCode)
+	}
+	return s, nil
+}
+
+// ShippingAddress is a place a dealership has saved to send orders to, besides
+// its own address.
+type ShippingAddress struct {
+	StandardTable
+	DealershipID int    `json:"dealership_id"`
+	Label        string `json:"label"`
+	ShipTo
+}
+
+type ShippingAddressModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func shippingAddressFromGen(gen model.ShippingAddresses) *ShippingAddress {
+	return &ShippingAddress{
+		StandardTable: StandardTable{
+			ID:        int(gen.ID),
+			UUID:      gen.UUID.String(),
+			CreatedAt: gen.CreatedAt,
+			UpdatedAt: gen.UpdatedAt,
+			Version:   int(gen.Version),
+		},
+		DealershipID: int(gen.DealershipID),
+		Label:        gen.Label,
+		ShipTo: ShipTo{
+			Recipient:  gen.Recipient,
+			Street:     gen.Street,
+			StreetExt:  gen.StreetExt,
+			City:       gen.City,
+			State:      gen.State,
+			PostalCode: gen.PostalCode,
+			Country:    gen.Country,
+		},
+	}
+}
+
+func shippingAddressToGen(a *ShippingAddress) (*model.ShippingAddresses, error) {
+	var addressUUID uuid.UUID
+	var err error
+
+	if a.UUID != "" {
+		addressUUID, err = uuid.Parse(a.UUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.ShippingAddresses{
+		ID:           int32(a.ID),
+		UUID:         addressUUID,
+		DealershipID: int32(a.DealershipID),
+		Label:        a.Label,
+		Recipient:    a.Recipient,
+		Street:       a.Street,
+		StreetExt:    a.StreetExt,
+		City:         a.City,
+		State:        a.State,
+		PostalCode:   a.PostalCode,
+		Country:      a.Country,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+		Version:      int32(a.Version),
+	}, nil
+}
+
+// ForDealership fills in a blank recipient with the dealership's name.
+func (a *ShippingAddress) ForDealership(d *Dealership) ShipTo {
+	shipTo := a.ShipTo
+	if shipTo.Recipient == "" {
+		shipTo.Recipient = d.Name
+	}
+	return shipTo
+}
+
+func (m ShippingAddressModel) Insert(address *ShippingAddress) error {
+	gen, err := shippingAddressToGen(address)
+	if err != nil {
+		return err
+	}
+
+	query := table.ShippingAddresses.INSERT(
+		table.ShippingAddresses.DealershipID,
+		table.ShippingAddresses.Label,
+		table.ShippingAddresses.Recipient,
+		table.ShippingAddresses.Street,
+		table.ShippingAddresses.StreetExt,
+		table.ShippingAddresses.City,
+		table.ShippingAddresses.State,
+		table.ShippingAddresses.PostalCode,
+		table.ShippingAddresses.Country,
+	).MODEL(gen).RETURNING(
+		table.ShippingAddresses.ID,
+		table.ShippingAddresses.UUID,
+		table.ShippingAddresses.CreatedAt,
+		table.ShippingAddresses.UpdatedAt,
+		table.ShippingAddresses.Version,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.ShippingAddresses
+	err = query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return err
+	}
+
+	address.ID = int(dest.ID)
+	address.UUID = dest.UUID.String()
+	address.CreatedAt = dest.CreatedAt
+	address.UpdatedAt = dest.UpdatedAt
+	address.Version = int(dest.Version)
+
+	return nil
+}
+
+func (m ShippingAddressModel) GetByID(id int) (*ShippingAddress, bool, error) {
+	return m.getWhere(table.ShippingAddresses.ID.EQ(postgres.Int(int64(id))))
+}
+
+func (m ShippingAddressModel) GetByUUID(uuidStr string) (*ShippingAddress, bool, error) {
+	parsedUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return m.getWhere(table.ShippingAddresses.UUID.EQ(postgres.UUID(parsedUUID)))
+}
+
+func (m ShippingAddressModel) getWhere(condition postgres.BoolExpression) (*ShippingAddress, bool, error) {
+	query := postgres.SELECT(
+		table.ShippingAddresses.AllColumns,
+	).FROM(
+		table.ShippingAddresses,
+	).WHERE(
+		condition,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.ShippingAddresses
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return shippingAddressFromGen(dest), true, nil
+}
+
+func (m ShippingAddressModel) GetByDealershipID(dealershipID int) ([]*ShippingAddress, error) {
+	query := postgres.SELECT(
+		table.ShippingAddresses.AllColumns,
+	).FROM(
+		table.ShippingAddresses,
+	).WHERE(
+		table.ShippingAddresses.DealershipID.EQ(postgres.Int(int64(dealershipID))),
+	).ORDER_BY(
+		table.ShippingAddresses.Label.ASC(),
+		table.ShippingAddresses.ID.ASC(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.ShippingAddresses
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]*ShippingAddress, len(dest))
+	for i, d := range dest {
+		addresses[i] = shippingAddressFromGen(d)
+	}
+
+	return addresses, nil
+}
+
+func (m ShippingAddressModel) Update(address *ShippingAddress) error {
+	gen, err := shippingAddressToGen(address)
+	if err != nil {
+		return err
+	}
+
+	query := table.ShippingAddresses.UPDATE(
+		table.ShippingAddresses.Label,
+		table.ShippingAddresses.Recipient,
+		table.ShippingAddresses.Street,
+		table.ShippingAddresses.StreetExt,
+		table.ShippingAddresses.City,
+		table.ShippingAddresses.State,
+		table.ShippingAddresses.PostalCode,
+		table.ShippingAddresses.Country,
+		table.ShippingAddresses.Version,
+	).MODEL(
+		gen,
+	).WHERE(
+		postgres.AND(
+			table.ShippingAddresses.ID.EQ(postgres.Int(int64(address.ID))),
+			table.ShippingAddresses.Version.EQ(postgres.Int(int64(address.Version))),
+		),
+	).RETURNING(
+		table.ShippingAddresses.UpdatedAt,
+		table.ShippingAddresses.Version,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.ShippingAddresses
+	err = query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return err
+	}
+
+	address.UpdatedAt = dest.UpdatedAt
+	address.Version = int(dest.Version)
+
+	return nil
+}
+
+func (m ShippingAddressModel) Delete(id int) error {
+	query := table.ShippingAddresses.DELETE().WHERE(
+		table.ShippingAddresses.ID.EQ(postgres.Int(int64(id))),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := query.ExecContext(ctx, m.STDB)
+	return err
+}
@@ -0,0 +1,46 @@
+package data
+
+import "testing"
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		name           string
+		in             ShipTo
+		wantErr        bool
+		wantState      string
+		wantPostalCode string
+	}{
+		{"us zip", ShipTo{Street: "1 Main St", City: "Boise", State: "id", PostalCode: "83702", Country: "us"}, false, "ID", "83702"},
+		{"us zip+4", ShipTo{Street: "1 Main St", City: "Boise", State: "ID", PostalCode: "83702-1234", Country: "US"}, false, "ID", "83702-1234"},
+		{"us unknown state", ShipTo{Street: "1 Main St", City: "Boise", State: "XX", PostalCode: "83702", Country: "US"}, true, "", ""},
+		{"us short zip", ShipTo{Street: "1 Main St", City: "Boise", State: "ID", PostalCode: "8370", Country: "US"}, true, "", ""},
+		{"ca postal code gets its space", ShipTo{Street: "1 Rue", City: "Montréal", State: "qc", PostalCode: "h2x1y4", Country: "CA"}, false, "QC", "H2X 1Y4"},
+		{"ca bad postal code", ShipTo{Street: "1 Rue", City: "Montréal", State: "QC", PostalCode: "12345", Country: "CA"}, true, "", ""},
+		{"mx needs a state", ShipTo{Street: "Calle 1", City: "Puebla", PostalCode: "72000", Country: "MX"}, true, "", ""},
+		{"mx", ShipTo{Street: "Calle 1", City: "Puebla", State: "Pue", PostalCode: "72000", Country: "MX"}, false, "PUE", "72000"},
+		{"other country is loose", ShipTo{Street: "1 High St", City: "Cork", Country: "IE"}, false, "", ""},
+		{"street required", ShipTo{City: "Cork", Country: "IE"}, true, "", ""},
+		{"country must be a code", ShipTo{Street: "1 High St", City: "Cork", Country: "Ireland"}, true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeAddress(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NormalizeAddress(%+v) = %+v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeAddress(%+v) error = %v", tt.in, err)
+			}
+			if got.State != tt.wantState {
+				t.Errorf("State = %q, want %q", got.State, tt.wantState)
+			}
+			if got.PostalCode != tt.wantPostalCode {
+				t.Errorf("PostalCode = %q, want %q", got.PostalCode, tt.wantPostalCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"math"
+	"time"
+)
+
+// Tax is the sales tax charged on one order. Jurisdiction is empty when no
+// rule covers the dealership; ExemptCertificate is set when none was charged
+// because the dealership is exempt.
+type Tax struct {
+	Cents             int
+	RatePercent       float64
+	Jurisdiction      string
+	ExemptCertificate *string
+}
+
+// TaxEngine works out the sales tax a dealership owes on an order. The local
+// rate table is the engine in use; a hosted tax service would sit behind the
+// same interface.
+type TaxEngine interface {
+	Calculate(dealership *Dealership, taxableCents int, at time.Time) (Tax, error)
+}
+
+// RateTableTaxEngine charges the rate of the local tax_rates rule covering the
+// dealership's address. Where no rule covers it, no tax is charged.
+type RateTableTaxEngine struct {
+	Rates TaxRateModel
+}
+
+func (e RateTableTaxEngine) Calculate(dealership *Dealership, taxableCents int, at time.Time) (Tax, error) {
+	if dealership.IsTaxExempt(at) {
+		return Tax{ExemptCertificate: dealership.TaxExemption.Certificate}, nil
+	}
+
+	rate, found, err := e.Rates.GetForAddress(dealership.Address.State, dealership.Address.PostalCode)
+	if err != nil {
+		return Tax{}, err
+	}
+	if !found {
+		return Tax{}, nil
+	}
+
+	return Tax{
+		Cents:        ComputeTaxCents(taxableCents, rate.RatePercent),
+		RatePercent:  rate.RatePercent,
+		Jurisdiction: rate.Jurisdiction(),
+	}, nil
+}
+
+// ComputeTaxCents applies a percentage rate, rounding to the nearest cent.
+func ComputeTaxCents(taxableCents int, ratePercent float64) int {
+	return max(0, int(math.Round(float64(taxableCents)*ratePercent/100)))
+}
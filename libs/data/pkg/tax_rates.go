@@ -0,0 +1,266 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TaxRate is one rule in the local rate table. With no PostalCodePrefix it
+// covers the whole state; otherwise only postal codes starting with the prefix.
+type TaxRate struct {
+	StandardTable
+	Name             string  `json:"name"`
+	State            string  `json:"state"`
+	PostalCodePrefix *string `json:"postal_code_prefix"`
+	RatePercent      float64 `json:"rate_percent"`
+}
+
+// Jurisdiction names the area the rule covers, for the order and invoice.
+func (t *TaxRate) Jurisdiction() string {
+	if t.PostalCodePrefix == nil {
+		return t.State
+	}
+	return t.State + " " + *t.PostalCodePrefix
+}
+
+type TaxRateModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func taxRateFromGen(gen model.TaxRates) *TaxRate {
+	return &TaxRate{
+		StandardTable: StandardTable{
+			ID:        int(gen.ID),
+			UUID:      gen.UUID.String(),
+			CreatedAt: gen.CreatedAt,
+			UpdatedAt: gen.UpdatedAt,
+			Version:   int(gen.Version),
+		},
+		Name:             gen.Name,
+		State:            gen.State,
+		PostalCodePrefix: gen.PostalCodePrefix,
+		RatePercent:      gen.RatePercent,
+	}
+}
+
+func taxRateToGen(t *TaxRate) (*model.TaxRates, error) {
+	var taxRateUUID uuid.UUID
+	var err error
+
+	if t.UUID != "" {
+		taxRateUUID, err = uuid.Parse(t.UUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.TaxRates{
+		ID:               int32(t.ID),
+		UUID:             taxRateUUID,
+		Name:             t.Name,
+		State:            strings.ToUpper(t.State),
+		PostalCodePrefix: t.PostalCodePrefix,
+		RatePercent:      t.RatePercent,
+		CreatedAt:        t.CreatedAt,
+		UpdatedAt:        t.UpdatedAt,
+		Version:          int32(t.Version),
+	}, nil
+}
+
+func (m TaxRateModel) Insert(taxRate *TaxRate) error {
+	gen, err := taxRateToGen(taxRate)
+	if err != nil {
+		return err
+	}
+
+	query := table.TaxRates.INSERT(
+		table.TaxRates.Name,
+		table.TaxRates.State,
+		table.TaxRates.PostalCodePrefix,
+		table.TaxRates.RatePercent,
+	).MODEL(gen).RETURNING(
+		table.TaxRates.ID,
+		table.TaxRates.UUID,
+		table.TaxRates.State,
+		table.TaxRates.CreatedAt,
+		table.TaxRates.UpdatedAt,
+		table.TaxRates.Version,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.TaxRates
+	err = query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return err
+	}
+
+	taxRate.ID = int(dest.ID)
+	taxRate.UUID = dest.UUID.String()
+	taxRate.State = dest.State
+	taxRate.CreatedAt = dest.CreatedAt
+	taxRate.UpdatedAt = dest.UpdatedAt
+	taxRate.Version = int(dest.Version)
+
+	return nil
+}
+
+func (m TaxRateModel) GetByUUID(uuidStr string) (*TaxRate, bool, error) {
+	parsedUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	query := postgres.SELECT(
+		table.TaxRates.AllColumns,
+	).FROM(
+		table.TaxRates,
+	).WHERE(
+		table.TaxRates.UUID.EQ(postgres.UUID(parsedUUID)),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.TaxRates
+	err = query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return taxRateFromGen(dest), true, nil
+}
+
+func (m TaxRateModel) GetAll() ([]*TaxRate, error) {
+	query := postgres.SELECT(
+		table.TaxRates.AllColumns,
+	).FROM(
+		table.TaxRates,
+	).ORDER_BY(
+		table.TaxRates.State.ASC(),
+		table.TaxRates.PostalCodePrefix.ASC().NULLS_FIRST(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest []model.TaxRates
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	taxRates := make([]*TaxRate, len(dest))
+	for i, d := range dest {
+		taxRates[i] = taxRateFromGen(d)
+	}
+
+	return taxRates, nil
+}
+
+// GetForAddress finds the rule covering an address: the longest postal-code
+// prefix that matches, falling back to the state-wide rule.
+func (m TaxRateModel) GetForAddress(state, postalCode string) (*TaxRate, bool, error) {
+	query := postgres.SELECT(
+		table.TaxRates.AllColumns,
+	).FROM(
+		table.TaxRates,
+	).WHERE(
+		table.TaxRates.State.EQ(postgres.String(strings.ToUpper(strings.TrimSpace(state)))).AND(
+			postgres.OR(
+				table.TaxRates.PostalCodePrefix.IS_NULL(),
+				postgres.String(strings.TrimSpace(postalCode)).LIKE(
+					table.TaxRates.PostalCodePrefix.CONCAT(postgres.String("%")),
+				),
+			),
+		),
+	).ORDER_BY(
+		postgres.CHAR_LENGTH(table.TaxRates.PostalCodePrefix).DESC().NULLS_LAST(),
+	).LIMIT(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.TaxRates
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return taxRateFromGen(dest), true, nil
+}
+
+func (m TaxRateModel) Update(taxRate *TaxRate) error {
+	gen, err := taxRateToGen(taxRate)
+	if err != nil {
+		return err
+	}
+
+	query := table.TaxRates.UPDATE(
+		table.TaxRates.Name,
+		table.TaxRates.State,
+		table.TaxRates.PostalCodePrefix,
+		table.TaxRates.RatePercent,
+		table.TaxRates.Version,
+	).MODEL(
+		gen,
+	).WHERE(
+		postgres.AND(
+			table.TaxRates.ID.EQ(postgres.Int(int64(taxRate.ID))),
+			table.TaxRates.Version.EQ(postgres.Int(int64(taxRate.Version))),
+		),
+	).RETURNING(
+		table.TaxRates.State,
+		table.TaxRates.UpdatedAt,
+		table.TaxRates.Version,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.TaxRates
+	err = query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return err
+	}
+
+	taxRate.State = dest.State
+	taxRate.UpdatedAt = dest.UpdatedAt
+	taxRate.Version = int(dest.Version)
+
+	return nil
+}
+
+func (m TaxRateModel) Delete(id int) error {
+	query := table.TaxRates.DELETE().WHERE(
+		table.TaxRates.ID.EQ(postgres.Int(int64(id))),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := query.ExecContext(ctx, m.STDB)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
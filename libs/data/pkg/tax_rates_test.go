@@ -0,0 +1,84 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaxRate_GetForAddressPrefersLongestPrefix(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+	models := getTestModels(t)
+
+	zip3 := "841"
+	zip5 := "84101"
+	for _, rate := range []*TaxRate{
+		{Name: "Utah", State: "ut", RatePercent: 6.1},
+		{Name: "Salt Lake County", State: "UT", PostalCodePrefix: &zip3, RatePercent: 7.25},
+		{Name: "Downtown", State: "UT", PostalCodePrefix: &zip5, RatePercent: 8.35},
+	} {
+		if err := models.TaxRates.Insert(rate); err != nil {
+			t.Fatalf("Failed to insert tax rate %q: %v", rate.Name, err)
+		}
+	}
+
+	tests := []struct {
+		state, postalCode string
+		want              string
+	}{
+		{"UT", "84101", "Downtown"},
+		{"UT", "84105", "Salt Lake County"},
+		{"ut", "84720", "Utah"},
+	}
+	for _, tt := range tests {
+		rate, found, err := models.TaxRates.GetForAddress(tt.state, tt.postalCode)
+		if err != nil {
+			t.Fatalf("Failed to look up %s %s: %v", tt.state, tt.postalCode, err)
+		}
+		if !found || rate.Name != tt.want {
+			t.Errorf("Expected %q for %s %s, got %+v", tt.want, tt.state, tt.postalCode, rate)
+		}
+	}
+
+	if _, found, err := models.TaxRates.GetForAddress("NV", "89101"); err != nil || found {
+		t.Errorf("Expected no rule for NV, found=%v err=%v", found, err)
+	}
+}
+
+func TestRateTableTaxEngine_Calculate(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+	models := getTestModels(t)
+
+	dealership := createTestDealership(t, models)
+	if err := models.TaxRates.Insert(&TaxRate{Name: "Test State", State: "TS", RatePercent: 6}); err != nil {
+		t.Fatalf("Failed to insert tax rate: %v", err)
+	}
+
+	engine := RateTableTaxEngine{Rates: models.TaxRates}
+
+	tax, err := engine.Calculate(dealership, 10000, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to calculate tax: %v", err)
+	}
+	if tax.Cents != 600 || tax.Jurisdiction != "TS" {
+		t.Errorf("Expected 600 cents in TS, got %+v", tax)
+	}
+
+	certificate := "RESALE-42"
+	dealership.TaxExemption = TaxExemption{Exempt: true, Certificate: &certificate}
+	if err := models.Dealerships.UpdateTaxExemption(dealership); err != nil {
+		t.Fatalf("Failed to update exemption: %v", err)
+	}
+
+	reloaded, _, err := models.Dealerships.GetByID(dealership.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload dealership: %v", err)
+	}
+
+	tax, err = engine.Calculate(reloaded, 10000, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to calculate tax: %v", err)
+	}
+	if tax.Cents != 0 || tax.ExemptCertificate == nil || *tax.ExemptCertificate != certificate {
+		t.Errorf("Expected an exempt order under %s, got %+v", certificate, tax)
+	}
+}
@@ -0,0 +1,489 @@
+package data
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/model"
+	"github.com/Lil-Strudel/glassact-studios/libs/data/pkg/gen/glassact/public/table"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// TOTPIssuer is the name authenticator apps show next to the code.
+	TOTPIssuer = "Glassact Studios"
+	TOTPDigits = 6
+	TOTPPeriod = 30 * time.Second
+
+	// totpSkew is how many periods either side of now a code is still
+	// accepted, to allow for a phone clock that has drifted.
+	totpSkew = 1
+
+	// RecoveryCodeCount is how many recovery codes are handed out at a time.
+	RecoveryCodeCount = 10
+
+	// MaxTwoFactorAttempts is how many wrong codes end a sign-in. The user has
+	// to go back through the magic link or provider for another go.
+	MaxTwoFactorAttempts = 5
+
+	// TwoFactorChallengeTTL is how long someone has to enter their code after
+	// the first step of signing in.
+	TwoFactorChallengeTTL = 10 * time.Minute
+)
+
+var (
+	// ErrTwoFactorEnabled is returned when enrolling a user whose
+	// authenticator is already confirmed. They have to turn it off first.
+	ErrTwoFactorEnabled = errors.New("two-factor authentication is already enabled")
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret makes a random 160-bit secret, base32 encoded the way
+// authenticator apps expect it.
+func NewTOTPSecret() string {
+	secret := make([]byte, 20)
+	rand.Read(secret)
+	return totpEncoding.EncodeToString(secret)
+}
+
+// TOTPStep is the RFC 6238 time step at.
+func TOTPStep(at time.Time) int64 {
+	return at.Unix() / int64(TOTPPeriod.Seconds())
+}
+
+// TOTPCode is the code an authenticator shows for secret at the given time
+// step.
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// RFC 4226 dynamic truncation.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulus := uint32(1)
+	for range TOTPDigits {
+		modulus *= 10
+	}
+
+	return fmt.Sprintf("%0*d", TOTPDigits, value%modulus), nil
+}
+
+// VerifyTOTP checks code against secret at the given time, returning the step
+// it matched. Steps at or before lastStep are refused so a code cannot be
+// used twice.
+func VerifyTOTP(secret, code string, at time.Time, lastStep int64) (int64, bool) {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	now := TOTPStep(at)
+	for step := now - totpSkew; step <= now+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// TOTPURI is the otpauth:// link enrolment QR codes encode.
+func TOTPURI(secret, account string) string {
+	label := url.PathEscape(TOTPIssuer + ":" + account)
+
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", TOTPIssuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(TOTPDigits))
+	q.Set("period", fmt.Sprint(int(TOTPPeriod.Seconds())))
+
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+// TwoFactorKey derives the key TOTP secrets are sealed with from the API's
+// auth secret, so a database dump alone cannot mint codes.
+func TwoFactorKey(authSecret string) []byte {
+	return secretKey("two-factor", authSecret)
+}
+
+// newRecoveryCode makes a code like "k3f9a-2hq7x".
+func newRecoveryCode() string {
+	text := strings.ToLower(rand.Text())
+	return text[:5] + "-" + text[5:10]
+}
+
+func hashRecoveryCode(code string) []byte {
+	normalized := strings.ToLower(code)
+	normalized = strings.ReplaceAll(normalized, "-", "")
+	normalized = strings.ReplaceAll(normalized, " ", "")
+
+	hash := sha256.Sum256([]byte(normalized))
+	return hash[:]
+}
+
+// TwoFactor is an internal user's authenticator. It only protects their
+// sign-in once ConfirmedAt is set, after they have entered a first code.
+type TwoFactor struct {
+	StandardTable
+	InternalUserID int        `json:"internal_user_id"`
+	ConfirmedAt    *time.Time `json:"confirmed_at"`
+	Secret         []byte     `json:"-"`
+	LastUsedStep   int64      `json:"-"`
+	FailedAttempts int        `json:"-"`
+}
+
+// IsEnabled reports whether the authenticator has been confirmed.
+func (t *TwoFactor) IsEnabled() bool {
+	return t.ConfirmedAt != nil
+}
+
+// IsLocked reports whether too many wrong codes have been entered since the
+// user last signed in.
+func (t *TwoFactor) IsLocked() bool {
+	return t.FailedAttempts >= MaxTwoFactorAttempts
+}
+
+// TwoFactorPolicy is the admin setting for who has to use two-factor
+// authentication.
+type TwoFactorPolicy struct {
+	RequireForPrivilegedRoles bool      `json:"require_for_privileged_roles"`
+	UpdatedAt                 time.Time `json:"updated_at"`
+	Version                   int       `json:"version"`
+}
+
+// Requires reports whether the policy makes a user with these permissions
+// enrol. Privileged users are the ones who can manage internal users or create
+// invoices.
+func (p *TwoFactorPolicy) Requires(permissions PermissionSet) bool {
+	if !p.RequireForPrivilegedRoles {
+		return false
+	}
+	return permissions.Has(ActionManageInternalUsers) || permissions.Has(ActionCreateInvoice)
+}
+
+type TwoFactorModel struct {
+	DB   *pgxpool.Pool
+	STDB *sql.DB
+}
+
+func twoFactorFromGen(gen model.InternalUserTwoFactor) *TwoFactor {
+	return &TwoFactor{
+		StandardTable: StandardTable{
+			ID:        int(gen.ID),
+			UUID:      gen.UUID.String(),
+			CreatedAt: gen.CreatedAt,
+			UpdatedAt: gen.UpdatedAt,
+			Version:   int(gen.Version),
+		},
+		InternalUserID: int(gen.InternalUserID),
+		ConfirmedAt:    gen.ConfirmedAt,
+		Secret:         gen.Secret,
+		LastUsedStep:   gen.LastUsedStep,
+		FailedAttempts: int(gen.FailedAttempts),
+	}
+}
+
+func (m TwoFactorModel) GetByInternalUserID(internalUserID int) (*TwoFactor, bool, error) {
+	query := postgres.SELECT(
+		table.InternalUserTwoFactor.AllColumns,
+	).FROM(
+		table.InternalUserTwoFactor,
+	).WHERE(
+		table.InternalUserTwoFactor.InternalUserID.EQ(postgres.Int(int64(internalUserID))),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.InternalUserTwoFactor
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return twoFactorFromGen(dest), true, nil
+}
+
+// Enroll gives the user a new, unconfirmed secret, replacing any earlier one
+// they never confirmed. The plaintext secret is returned for the QR code and
+// is not kept anywhere else.
+func (m TwoFactorModel) Enroll(internalUserID int, key []byte) (*TwoFactor, string, error) {
+	secret := NewTOTPSecret()
+	sealed, err := sealSecret(key, secret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	twoFactor := &TwoFactor{InternalUserID: internalUserID, Secret: sealed}
+	err = m.STDB.QueryRowContext(ctx, `
+		INSERT INTO internal_user_two_factor (internal_user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (internal_user_id) DO UPDATE
+			SET secret = EXCLUDED.secret, last_used_step = 0, failed_attempts = 0
+			WHERE internal_user_two_factor.confirmed_at IS NULL
+		RETURNING id, uuid, created_at, updated_at, version
+	`, internalUserID, sealed).Scan(
+		&twoFactor.ID,
+		&twoFactor.UUID,
+		&twoFactor.CreatedAt,
+		&twoFactor.UpdatedAt,
+		&twoFactor.Version,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrTwoFactorEnabled
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return twoFactor, secret, nil
+}
+
+// Verify checks a code from the user's authenticator. A right code confirms
+// an unconfirmed authenticator and clears the failed attempts; a wrong one
+// counts towards MaxTwoFactorAttempts. Each code only works once.
+func (m TwoFactorModel) Verify(twoFactor *TwoFactor, key []byte, code string) (bool, error) {
+	secret, err := openSecret(key, twoFactor.Secret)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := VerifyTOTP(secret, code, time.Now(), twoFactor.LastUsedStep)
+	if !ok {
+		return false, m.RecordFailure(twoFactor)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// The step guard stops two requests racing to use the same code.
+	err = m.STDB.QueryRowContext(ctx, `
+		UPDATE internal_user_two_factor
+		SET last_used_step = $2, failed_attempts = 0, confirmed_at = COALESCE(confirmed_at, now())
+		WHERE id = $1 AND last_used_step < $2
+		RETURNING confirmed_at, last_used_step, failed_attempts, updated_at, version
+	`, twoFactor.ID, step).Scan(
+		&twoFactor.ConfirmedAt,
+		&twoFactor.LastUsedStep,
+		&twoFactor.FailedAttempts,
+		&twoFactor.UpdatedAt,
+		&twoFactor.Version,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RecordFailure counts a wrong code or recovery code against the user.
+func (m TwoFactorModel) RecordFailure(twoFactor *TwoFactor) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.STDB.QueryRowContext(ctx, `
+		UPDATE internal_user_two_factor SET failed_attempts = failed_attempts + 1
+		WHERE id = $1
+		RETURNING failed_attempts
+	`, twoFactor.ID).Scan(&twoFactor.FailedAttempts)
+}
+
+// ResetFailedAttempts gives the user a fresh set of tries. Called when they
+// get through the first step of signing in again.
+func (m TwoFactorModel) ResetFailedAttempts(internalUserID int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.STDB.ExecContext(ctx, `
+		UPDATE internal_user_two_factor SET failed_attempts = 0
+		WHERE internal_user_id = $1 AND failed_attempts > 0
+	`, internalUserID)
+	return err
+}
+
+// Disable removes the user's authenticator and recovery codes.
+func (m TwoFactorModel) Disable(internalUserID int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM internal_user_recovery_codes WHERE internal_user_id = $1`, internalUserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM internal_user_two_factor WHERE internal_user_id = $1`, internalUserID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceRecoveryCodes issues a new set of recovery codes, voiding the old
+// ones. The plaintext codes are returned to show the user once.
+func (m TwoFactorModel) ReplaceRecoveryCodes(internalUserID int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.STDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM internal_user_recovery_codes WHERE internal_user_id = $1`, internalUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		codes[i] = newRecoveryCode()
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO internal_user_recovery_codes (internal_user_id, code_hash) VALUES ($1, $2)
+		`, internalUserID, hashRecoveryCode(codes[i]))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// UseRecoveryCode spends one of the user's recovery codes. Reports false if
+// the code is wrong or already used.
+func (m TwoFactorModel) UseRecoveryCode(internalUserID int, code string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.STDB.ExecContext(ctx, `
+		UPDATE internal_user_recovery_codes SET used_at = now()
+		WHERE internal_user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`, internalUserID, hashRecoveryCode(code))
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if affected == 0 {
+		return false, nil
+	}
+
+	_, err = m.STDB.ExecContext(ctx, `
+		UPDATE internal_user_two_factor SET failed_attempts = 0 WHERE internal_user_id = $1
+	`, internalUserID)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RemainingRecoveryCodes counts the user's unused recovery codes.
+func (m TwoFactorModel) RemainingRecoveryCodes(internalUserID int) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var remaining int
+	err := m.STDB.QueryRowContext(ctx, `
+		SELECT count(*) FROM internal_user_recovery_codes
+		WHERE internal_user_id = $1 AND used_at IS NULL
+	`, internalUserID).Scan(&remaining)
+
+	return remaining, err
+}
+
+func (m TwoFactorModel) GetPolicy() (*TwoFactorPolicy, error) {
+	query := postgres.SELECT(
+		table.TwoFactorPolicy.AllColumns,
+	).FROM(
+		table.TwoFactorPolicy,
+	).WHERE(
+		table.TwoFactorPolicy.ID.EQ(postgres.Int(1)),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var dest model.TwoFactorPolicy
+	err := query.QueryContext(ctx, m.STDB, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorPolicy{
+		RequireForPrivilegedRoles: dest.RequireForPrivilegedRoles,
+		UpdatedAt:                 dest.UpdatedAt,
+		Version:                   int(dest.Version),
+	}, nil
+}
+
+func (m TwoFactorModel) UpdatePolicy(policy *TwoFactorPolicy) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.STDB.QueryRowContext(ctx, `
+		UPDATE two_factor_policy SET require_for_privileged_roles = $1
+		WHERE id = 1
+		RETURNING updated_at, version
+	`, policy.RequireForPrivilegedRoles).Scan(&policy.UpdatedAt, &policy.Version)
+}
@@ -0,0 +1,157 @@
+package data
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// The RFC 6238 SHA-1 vectors for the key "12345678901234567890",
+	// truncated from eight digits to six.
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, want := range vectors {
+		got, err := TOTPCode(secret, TOTPStep(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatalf("Failed to generate code at %d: %v", unix, err)
+		}
+		if got != want {
+			t.Errorf("At %d expected %s, got %s", unix, want, got)
+		}
+	}
+
+	at := time.Unix(1111111111, 0)
+	step, ok := VerifyTOTP(secret, "081804", at, 0)
+	if !ok || step != TOTPStep(at)-1 {
+		t.Errorf("Expected the previous step's code to be accepted for clock drift")
+	}
+
+	_, ok = VerifyTOTP(secret, "081804", at, step)
+	if ok {
+		t.Errorf("Expected a code to be refused once its step was used")
+	}
+}
+
+func TestTwoFactor_EnrollVerifyAndRecover(t *testing.T) {
+	t.Cleanup(func() { cleanupTables(t) })
+
+	models := getTestModels(t)
+	user := &InternalUser{
+		Name:     "Billing",
+		Email:    "billing@example.com",
+		Avatar:   "https://example.com/avatar.jpg",
+		Role:     InternalUserRoles.Billing,
+		IsActive: true,
+	}
+	err := models.InternalUsers.Insert(user)
+	if err != nil {
+		t.Fatalf("Failed to create internal user: %v", err)
+	}
+
+	key := TwoFactorKey("test-secret")
+
+	twoFactor, secret, err := models.TwoFactor.Enroll(user.ID, key)
+	if err != nil {
+		t.Fatalf("Failed to enroll: %v", err)
+	}
+	if twoFactor.IsEnabled() {
+		t.Errorf("Expected enrolment to wait for a first code")
+	}
+
+	code, err := TOTPCode(secret, TOTPStep(time.Now()))
+	if err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	ok, err := models.TwoFactor.Verify(twoFactor, key, code)
+	if err != nil || !ok {
+		t.Fatalf("Expected the first code to confirm enrolment: ok=%v err=%v", ok, err)
+	}
+	if !twoFactor.IsEnabled() {
+		t.Errorf("Expected the authenticator to be confirmed")
+	}
+
+	ok, err = models.TwoFactor.Verify(twoFactor, key, code)
+	if err != nil {
+		t.Fatalf("Failed to verify: %v", err)
+	}
+	if ok {
+		t.Errorf("Expected a replayed code to be refused")
+	}
+	if twoFactor.FailedAttempts != 1 {
+		t.Errorf("Expected the replay to count as a failure, got %d", twoFactor.FailedAttempts)
+	}
+
+	_, _, err = models.TwoFactor.Enroll(user.ID, key)
+	if !errors.Is(err, ErrTwoFactorEnabled) {
+		t.Errorf("Expected re-enrolling a confirmed authenticator to fail, got %v", err)
+	}
+
+	codes, err := models.TwoFactor.ReplaceRecoveryCodes(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to create recovery codes: %v", err)
+	}
+	if len(codes) != RecoveryCodeCount {
+		t.Fatalf("Expected %d recovery codes, got %d", RecoveryCodeCount, len(codes))
+	}
+
+	ok, err = models.TwoFactor.UseRecoveryCode(user.ID, codes[0])
+	if err != nil || !ok {
+		t.Fatalf("Expected the recovery code to work: ok=%v err=%v", ok, err)
+	}
+
+	ok, err = models.TwoFactor.UseRecoveryCode(user.ID, codes[0])
+	if err != nil {
+		t.Fatalf("Failed to use recovery code: %v", err)
+	}
+	if ok {
+		t.Errorf("Expected a recovery code to work only once")
+	}
+
+	remaining, err := models.TwoFactor.RemainingRecoveryCodes(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to count recovery codes: %v", err)
+	}
+	if remaining != RecoveryCodeCount-1 {
+		t.Errorf("Expected %d recovery codes left, got %d", RecoveryCodeCount-1, remaining)
+	}
+
+	permissions, err := ResolvePermissions(&models, user)
+	if err != nil {
+		t.Fatalf("Failed to resolve permissions: %v", err)
+	}
+
+	policy, err := models.TwoFactor.GetPolicy()
+	if err != nil {
+		t.Fatalf("Failed to get policy: %v", err)
+	}
+	if policy.Requires(permissions) {
+		t.Errorf("Expected two-factor authentication to be optional by default")
+	}
+
+	policy.RequireForPrivilegedRoles = true
+	err = models.TwoFactor.UpdatePolicy(policy)
+	if err != nil {
+		t.Fatalf("Failed to update policy: %v", err)
+	}
+	if !policy.Requires(permissions) {
+		t.Errorf("Expected the policy to cover billing, who can create invoices")
+	}
+
+	designer, err := models.InternalRoles.Resolve(0, string(InternalUserRoles.Designer))
+	if err != nil {
+		t.Fatalf("Failed to resolve permissions: %v", err)
+	}
+	if policy.Requires(designer) {
+		t.Errorf("Expected the policy to leave designers alone")
+	}
+}
@@ -19,6 +19,11 @@ export const PERMISSION_ACTIONS = {
   MANAGE_DEALERSHIPS: "manage_dealerships",
   MANAGE_SUPPORT: "manage_support",
   MANAGE_MATERIALS: "manage_materials",
+  MANAGE_REMAKES: "manage_remakes",
+  MANAGE_TAXES: "manage_taxes",
+  IMPERSONATE: "impersonate",
+  MANAGE_ROLES: "manage_roles",
+  MANAGE_JOBS: "manage_jobs",
   MANAGE_CATALOG: "manage_catalog",
   MANAGE_PRICE_GROUPS: "manage_price_groups",
   ACCESS_ADMIN: "access_admin",
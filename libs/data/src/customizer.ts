@@ -65,3 +65,15 @@ export interface BakeResult {
   width: number;
   height: number;
 }
+
+// Exactly one of `family` (a glass color family, matched case-insensitively) or
+// `seed_hex` (a color whose hue the theme should follow) is required.
+export interface ThemeRequest {
+  family?: string;
+  seed_hex?: string;
+}
+
+// A proposed coloring: group overrides only, ready to review and bake.
+export interface ThemeResult {
+  color_overrides: ColorOverrides;
+}
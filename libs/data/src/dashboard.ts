@@ -27,3 +27,14 @@ export type InternalDashboard = {
   outstanding_invoice_amount_cents: number;
   recent_projects: GET<Project>[];
 };
+
+export type LocationDashboard = {
+  dealership_id: number;
+  dealership_name: string;
+  dashboard: DealershipDashboard;
+};
+
+// The dealership dashboard summed over every location in a group.
+export type GroupDashboard = DealershipDashboard & {
+  locations: LocationDashboard[];
+};
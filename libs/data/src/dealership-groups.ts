@@ -0,0 +1,24 @@
+import { StandardTable, GET } from "./helpers";
+import type { Invoice } from "./invoices";
+
+// A parent organization with several dealership locations. Dealership users
+// with its id as their dealership_group_id see every location in it.
+export type DealershipGroup = StandardTable<{
+  name: string;
+  dealership_ids: number[];
+}>;
+
+export type GroupInvoice = GET<Invoice> & {
+  dealership_id: number;
+  dealership_name: string;
+  project_uuid: string;
+  project_name: string;
+};
+
+// GET /api/dealership-group/{uuid}/statement. Void invoices are left out
+// unless asked for with ?status=void.
+export type GroupStatement = {
+  invoices: GroupInvoice[];
+  outstanding_cents: number;
+  paid_cents: number;
+};
@@ -0,0 +1,44 @@
+import type { Dealership } from "./dealerships";
+import type { DealershipUser } from "./dealership-users";
+import type { GET } from "./helpers";
+
+// POST /api/dealership-import. The CSVs are sent as text; either may be left
+// empty.
+//
+// Dealerships columns: ref, name, phone, payment_timing,
+// sandblast_file_format, street, street_ext, city, state, postal_code,
+// country, latitude, longitude. Coordinates can be left blank when the server
+// has a geocoder.
+//
+// Users columns: dealership (a ref above or an existing dealership's uuid),
+// name, email, role, avatar.
+export interface DealershipImportRequest {
+  dealerships_csv?: string;
+  users_csv?: string;
+  dry_run?: boolean;
+  send_invitations?: boolean;
+}
+
+export interface ImportRowResult {
+  row: number; // the CSV line, counting the header as 1
+  errors: string[];
+}
+
+export interface DealershipImportResult extends ImportRowResult {
+  ref: string;
+  dealership: GET<Dealership> | null;
+}
+
+export interface DealershipUserImportResult extends ImportRowResult {
+  user: GET<DealershipUser> | null;
+  invited: boolean;
+}
+
+// Nothing is applied unless error_count is 0.
+export interface DealershipImportReport {
+  dry_run: boolean;
+  applied: boolean;
+  error_count: number;
+  dealerships: DealershipImportResult[];
+  users: DealershipUserImportResult[];
+}
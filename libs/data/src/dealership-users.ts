@@ -1,4 +1,5 @@
 import { StandardTable } from "./helpers";
+import { Impersonation } from "./impersonations";
 
 export type DealershipUserRole = 
   | "viewer" 
@@ -8,9 +9,17 @@ export type DealershipUserRole =
 
 export type DealershipUser = StandardTable<{
   dealership_id: number;
+  // Set for group-level users, who see every location in the group.
+  dealership_group_id: number | null;
   name: string;
   email: string;
   avatar: string;
   role: DealershipUserRole;
   is_active: boolean;
 }>;
+
+// GET /api/user/self for a dealership user. impersonation is set when an
+// admin is viewing as them.
+export type DealershipUserSelf = DealershipUser & {
+  impersonation: Impersonation | null;
+};
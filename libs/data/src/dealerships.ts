@@ -38,4 +38,16 @@ export type Dealership = StandardTable<{
     latitude: number;
     longitude: number;
   };
+  // Set through the dealership group endpoints.
+  dealership_group_id: number | null;
+  // Always returned, but set through its own endpoint rather than on create.
+  tax_exemption?: TaxExemption;
 }>;
+
+// Set by GlassAct billing. Only holds with a certificate on file, through the
+// end of `expires_at`.
+export type TaxExemption = {
+  exempt: boolean;
+  certificate: string | null;
+  expires_at: string | null;
+};
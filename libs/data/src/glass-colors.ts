@@ -1,9 +1,26 @@
 import { StandardTable } from "./helpers";
 
+export type GlassFinish = "cathedral" | "opalescent" | "iridescent";
+
+export type GlassTexture =
+  | "smooth"
+  | "streaky"
+  | "rippled"
+  | "seedy"
+  | "hammered"
+  | "granite";
+
 export type GlassColor = StandardTable<{
   name: string;
   hex: string;
   family: string | null;
   sort_order: number;
   is_active: boolean;
+  finish: GlassFinish;
+  texture: GlassTexture;
+  opacity: number;
+  swatch_image_url: string | null;
+  // Set while out of stock with a known restock date; the palette shows the
+  // color but the customizer refuses it until then.
+  unavailable_until: string | null;
 }>;
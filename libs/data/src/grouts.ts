@@ -5,4 +5,7 @@ export type Grout = StandardTable<{
   hex: string;
   sort_order: number;
   is_active: boolean;
+  // Set while out of stock with a known restock date; the palette shows the
+  // grout but the customizer refuses it until then.
+  unavailable_until: string | null;
 }>;
@@ -0,0 +1,37 @@
+// An internal admin viewing the app as a dealership user. The user ids are
+// null once that user is deleted; the emails stay for the audit trail.
+export type Impersonation = {
+  id: number;
+  uuid: string;
+  internal_user_id: number | null;
+  internal_user_email: string;
+  dealership_user_id: number | null;
+  dealership_user_email: string;
+  reason: string;
+  ip: string;
+  user_agent: string;
+  expires_at: string;
+  ended_at: string | null;
+  created_at: string;
+};
+
+export type ImpersonationRequest = {
+  id: number;
+  method: string;
+  path: string;
+  blocked: boolean; // writes are refused while impersonating
+  created_at: string;
+};
+
+export interface StartImpersonationRequest {
+  dealership_user_uuid: string;
+  reason: string;
+}
+
+// From POST /api/auth/impersonation. The access token is read-only and cannot
+// be refreshed.
+export interface StartImpersonationResponse {
+  access_token: string;
+  access_token_exp: string;
+  impersonation: Impersonation;
+}
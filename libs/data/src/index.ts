@@ -4,11 +4,14 @@ export * from "./catalog-item";
 export * from "./customizer";
 export * from "./dashboard";
 export * from "./dealership-accounts";
+export * from "./dealership-groups";
+export * from "./dealership-imports";
 export * from "./dealership-users";
 export * from "./dealerships";
 export * from "./glass-colors";
 export * from "./grouts";
 export * from "./helpers";
+export * from "./impersonations";
 export * from "./inlay-milestones";
 export * from "./inlay-proofs";
 export * from "./inlay-updates";
@@ -16,12 +19,27 @@ export * from "./inlays";
 export * from "./installation-kits";
 export * from "./internal-accounts";
 export * from "./internal-users";
+export * from "./inventory";
+export * from "./invitations";
 export * from "./invoices";
+export * from "./jobs";
+export * from "./nesting";
 export * from "./notifications";
+export * from "./oidc-providers";
 export * from "./order-snapshots";
 export * from "./price-groups";
+export * from "./price-tiers";
 export * from "./project-chats";
 export * from "./project-watchers";
 export * from "./projects";
+export * from "./quotes";
+export * from "./remakes";
 export * from "./review-queue";
+export * from "./roles";
+export * from "./sessions";
+export * from "./ship-estimates";
+export * from "./shipments";
+export * from "./shipping-addresses";
 export * from "./support-articles";
+export * from "./tax-rates";
+export * from "./two-factor";
@@ -9,4 +9,5 @@ export type InlayMilestone = StandardTable<{
   event_type: MilestoneEventType;
   performed_by: number;
   event_time: string;
+  cycle: number; // 1 for the original order, counting up with each remake
 }>;
@@ -6,6 +6,7 @@ import type {
   ProofStatus,
 } from "./inlay-proofs";
 import type { OrderSnapshot } from "./order-snapshots";
+import type { InlayRemake } from "./remakes";
 
 export type InlayType = "catalog" | "custom";
 
@@ -72,6 +73,8 @@ export type InlayWithInfo = GET<Inlay> & {
   price_group_id: number | null;
   price_group_name: string | null;
   price_cents: number | null;
+  list_price_cents: number | null;
+  tier_discount_cents: number;
   price_adjustment_type: PriceAdjustmentType;
   price_adjustment_value: number;
 };
@@ -97,4 +100,5 @@ export type InlayDetail = InlayWithInfo & {
   approved_proof: GET<InlayProof> | null;
   latest_proof: GET<InlayProof> | null;
   order_snapshot: GET<OrderSnapshot> | null;
+  remakes: GET<InlayRemake>[];
 };
@@ -0,0 +1,35 @@
+import { StandardTable } from "./helpers";
+
+// Stock is counted in whole or partial sheets, or in square inches. Design
+// usage is always measured in square inches and converted with sheet_area.
+export type MaterialUnit = "sheets" | "square_inches";
+
+export type MaterialStock = StandardTable<{
+  // Exactly one of these is set.
+  glass_color_id: number | null;
+  grout_id: number | null;
+  unit: MaterialUnit;
+  sheet_area: number | null; // square inches per sheet
+  on_hand: number;
+  low_stock_threshold: number; // 0 disables the warning
+  low_stock_notified_at: string | null;
+  // Derived: open order reservations, and on_hand less those.
+  reserved: number;
+  available: number;
+}>;
+
+export type InventoryItem = MaterialStock & {
+  material_uuid: string;
+  name: string;
+  hex: string;
+  unavailable_until: string | null;
+  is_low_stock: boolean;
+};
+
+export interface MaterialStockRequest {
+  unit: MaterialUnit;
+  sheet_area?: number | null;
+  on_hand: number;
+  low_stock_threshold: number;
+  unavailable_until: string | null;
+}
@@ -0,0 +1,28 @@
+import { StandardTable } from "./helpers";
+
+export type InvitationStatus = "pending" | "accepted" | "revoked";
+
+// Sent when a user is created; the user stays inactive until they accept.
+// Exactly one of dealership_user_id and internal_user_id is set.
+export type Invitation = StandardTable<{
+  dealership_user_id: number | null;
+  internal_user_id: number | null;
+  email: string;
+  role: string;
+  invited_by_dealership_user_id: number | null;
+  invited_by_internal_user_id: number | null;
+  status: InvitationStatus;
+  expires_at: string;
+  sent_at: string;
+  accepted_at: string | null;
+  revoked_at: string | null;
+}>;
+
+// What GET /api/auth/invitation shows before the invite is accepted.
+export interface InvitationPreview {
+  email: string;
+  role: string;
+  status: InvitationStatus;
+  expires_at: string;
+  open: boolean;
+}
@@ -7,4 +7,7 @@ export type Invoice = StandardTable<{
   invoice_url: string | null;
   status: InvoiceStatus;
   paid_at: string | null;
+  subtotal_cents: number;
+  tax_cents: number;
+  total_cents: number;
 }>;
@@ -0,0 +1,28 @@
+export type JobRunTrigger = "schedule" | "manual";
+
+export type JobRunStatus = "running" | "succeeded" | "failed";
+
+// One run of a background job. Scheduled runs have the tick they were for;
+// manual runs have the internal user who started them.
+export type JobRun = {
+  id: number;
+  uuid: string;
+  job_name: string;
+  trigger: JobRunTrigger;
+  scheduled_for: string | null;
+  triggered_by_internal_user_id: number | null;
+  status: JobRunStatus;
+  rows_affected: number;
+  error: string | null;
+  started_at: string;
+  finished_at: string | null;
+};
+
+// From GET /api/jobs.
+export type Job = {
+  name: string;
+  description: string;
+  schedule: string; // cron expression, in UTC
+  next_run_at: string | null;
+  last_run: JobRun | null;
+};
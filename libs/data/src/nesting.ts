@@ -0,0 +1,69 @@
+// Glass sheet nesting for the materials-prep column. All dimensions are in
+// inches. Pieces of one glass color are packed onto that color's stock sheet;
+// `oversize` pieces fit no sheet in either orientation and must be cut by hand.
+
+export interface SheetSize {
+  width: number;
+  height: number;
+}
+
+export interface NestingRequest {
+  // Omit to nest every materials-prep inlay (of the project, for the project
+  // endpoint).
+  inlay_uuids?: string[];
+  sheet?: SheetSize;
+  // Per glass_color_id stock size, overriding `sheet`.
+  color_sheets?: Record<number, SheetSize>;
+  spacing?: number;
+}
+
+export interface NestPiece {
+  inlay_ref: string; // inlay uuid
+  piece_id: string;
+  glass_color_id: number;
+  width: number;
+  height: number;
+  area: number;
+}
+
+export interface NestPlacement {
+  inlay_ref: string;
+  piece_id: string;
+  x: number;
+  y: number;
+  width: number;
+  height: number;
+  rotated: boolean;
+}
+
+export interface NestSheet {
+  index: number;
+  utilization: number; // 0..1, glass area / sheet area
+  placements: NestPlacement[];
+}
+
+export interface ColorNest {
+  glass_color_id: number;
+  sheet: SheetSize;
+  piece_count: number;
+  sheets_needed: number;
+  utilization: number;
+  sheets: NestSheet[];
+  oversize: NestPiece[];
+}
+
+export interface NestingLayout {
+  glass_color_id: number;
+  sheet_index: number;
+  svg: string;
+  dxf: string;
+}
+
+export interface NestingResult {
+  spacing: number;
+  colors: ColorNest[];
+  total_sheets: number;
+  inlay_count: number;
+  layouts: NestingLayout[];
+  skipped: { inlay_uuid: string; name: string; reason: string }[];
+}
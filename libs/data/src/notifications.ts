@@ -14,7 +14,11 @@ export type NotificationEventType =
   | "invoice_sent"
   | "invoice_voided"
   | "payment_received"
-  | "chat_message";
+  | "chat_message"
+  | "low_stock"
+  | "remake_requested"
+  | "remake_reviewed"
+  | "rush_order_placed";
 
 export const NOTIFICATION_EVENT_TYPES: NotificationEventType[] = [
   "proof_ready",
@@ -31,6 +35,10 @@ export const NOTIFICATION_EVENT_TYPES: NotificationEventType[] = [
   "invoice_voided",
   "payment_received",
   "chat_message",
+  "low_stock",
+  "remake_requested",
+  "remake_reviewed",
+  "rush_order_placed",
 ];
 
 export const DEALERSHIP_NOTIFICATION_EVENT_TYPES: NotificationEventType[] = [
@@ -44,6 +52,7 @@ export const DEALERSHIP_NOTIFICATION_EVENT_TYPES: NotificationEventType[] = [
   "invoice_sent",
   "payment_received",
   "chat_message",
+  "remake_reviewed",
 ];
 
 export const INTERNAL_NOTIFICATION_EVENT_TYPES: NotificationEventType[] = [
@@ -55,6 +64,9 @@ export const INTERNAL_NOTIFICATION_EVENT_TYPES: NotificationEventType[] = [
   "proof_declined",
   "project_delivered",
   "chat_message",
+  "low_stock",
+  "remake_requested",
+  "rush_order_placed",
 ];
 
 export const NOTIFICATION_EVENT_LABELS: Record<NotificationEventType, string> =
@@ -73,6 +85,10 @@ export const NOTIFICATION_EVENT_LABELS: Record<NotificationEventType, string> =
     invoice_voided: "Invoice Voided",
     payment_received: "Payment Received",
     chat_message: "New Chat Message",
+    low_stock: "Material Running Low",
+    remake_requested: "Remake Requested",
+    remake_reviewed: "Remake Reviewed",
+    rush_order_placed: "Rush Order Placed",
   };
 
 export type Notification = StandardTable<{
@@ -0,0 +1,37 @@
+import { StandardTable } from "./helpers";
+
+// A registered identity provider. It signs in users of the listed
+// dealerships only; the client secret is never sent back.
+export type OIDCProvider = StandardTable<{
+  key: string;
+  name: string;
+  discovery_url: string;
+  client_id: string;
+  scopes: string;
+  subject_claim: string;
+  email_claim: string;
+  email_verified_claim: string;
+  is_active: boolean;
+  dealership_ids: number[];
+}>;
+
+export interface OIDCProviderRequest {
+  key: string;
+  name: string;
+  discovery_url: string;
+  client_id: string;
+  client_secret?: string; // required when registering; omit to keep the old one
+  scopes?: string;
+  subject_claim?: string;
+  email_claim?: string;
+  email_verified_claim?: string;
+  is_active: boolean;
+  dealership_ids: number[];
+}
+
+// A button on the login page.
+export interface OIDCLoginOption {
+  key: string;
+  name: string;
+  login_url: string;
+}
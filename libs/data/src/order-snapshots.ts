@@ -1,14 +1,21 @@
 import { StandardTable } from "./helpers";
 import type { PriceAdjustmentType } from "./inlay-proofs";
+import type { ShipTo } from "./shipping-addresses";
 
 export type OrderSnapshot = StandardTable<{
   project_id: number;
   inlay_id: number;
   proof_id: number | null;
   price_group_id: number;
-  price_cents: number;
+  price_cents: number; // net, after any tier discount
+  list_price_cents: number;
+  tier_discount_cents: number;
+  price_tier_id: number | null;
   price_adjustment_type: PriceAdjustmentType;
   price_adjustment_value: number;
   width: number;
   height: number;
+  remake_id: number | null;
+  quote_id: number | null; // the quote whose prices were locked in, if any
+  ship_to: ShipTo | null; // where the order was sent, captured when placed
 }>;
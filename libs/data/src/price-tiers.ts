@@ -0,0 +1,26 @@
+import { StandardTable } from "./helpers";
+
+// A fixed price for one price group, in place of the tier's percentage.
+export interface PriceTierOverride {
+  price_group_id: number;
+  price_cents: number;
+}
+
+// A dealership's negotiated pricing. The tier in effect when an order is
+// placed prices it; when ranges overlap the latest start wins.
+export type DealershipPriceTier = StandardTable<{
+  dealership_id: number;
+  name: string;
+  discount_percent: number;
+  effective_from: string;
+  effective_until: string | null;
+  overrides: PriceTierOverride[];
+}>;
+
+export interface PriceTierRequest {
+  name: string;
+  discount_percent?: number;
+  effective_from?: string;
+  effective_until?: string | null;
+  overrides?: PriceTierOverride[];
+}
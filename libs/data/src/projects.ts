@@ -1,5 +1,6 @@
 import type { PaymentTiming, SandblastFileFormat } from "./dealerships";
 import { GET, StandardTable } from "./helpers";
+import type { Inlay } from "./inlays";
 
 export type ProjectStatus =
   | "draft"
@@ -33,6 +34,21 @@ export type Project = StandardTable<{
   // is null until the order is placed, then locks the charge.
   installation_kit: boolean;
   installation_kit_price_cents: number | null;
+  // Set on a draft split off a partially ordered project.
+  parent_project_id: number | null;
+  // Locked with the kit charge when the order is placed; null on drafts.
+  tax_cents: number | null;
+  tax_rate_percent: number | null;
+  tax_jurisdiction: string | null;
+  tax_exempt_certificate: string | null;
+  // `rush` is chosen at order time; `rush_surcharge_cents` is null until the
+  // order is placed, then locks the charge like the kit's. `required_by` is a
+  // YYYY-MM-DD date the order must ship by.
+  rush: boolean;
+  rush_surcharge_cents: number | null;
+  required_by: string | null;
+  // The saved address a draft will ship to; null ships to the dealership.
+  ship_to_address_id: number | null;
 }>;
 
 // Per-project counts of outstanding internal actions, attached to the project
@@ -67,4 +83,44 @@ export type ProjectDetail = GET<Project> & {
   awaiting_payment?: boolean;
   is_watching: boolean;
   watcher_count: number;
+  // Projects linked by a split at order time: the ordered project this draft
+  // came from, and the drafts that took inlays left out of this one's order.
+  parent_project: RelatedProject | null;
+  child_projects: RelatedProject[];
+};
+
+export type RelatedProject = {
+  uuid: string;
+  name: string;
+  status: ProjectStatus;
+};
+
+// The place-order body. `rush` adds the rush surcharge to the order.
+export type PlaceOrderRequest = {
+  inlay_uuids: string[];
+  rush?: boolean;
+  required_by?: string;
+};
+
+// Placing an order for only some inlays moves the rest to a new draft, returned
+// as `follow_up_project`.
+export type PlaceOrderResult = GET<Project> & {
+  follow_up_project?: GET<Project>;
+};
+
+// A new draft started from a past project; `inlay_uuids` limits the copy to
+// some of its inlays. `name` defaults to the source name plus " (reorder)".
+export type ReorderProjectRequest = {
+  name?: string;
+  inlay_uuids?: string[];
+};
+
+export type ReorderProjectResult = {
+  project: GET<Project>;
+  inlays: GET<Inlay>[];
+};
+
+// Copies inlays from any of the dealership's projects into a draft.
+export type CloneInlaysRequest = {
+  inlay_uuids: string[];
 };
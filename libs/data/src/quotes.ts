@@ -0,0 +1,47 @@
+import type { PriceAdjustmentType } from "./inlay-proofs";
+
+// One inlay's price on a quote. inlay_id goes null if the inlay is later
+// removed from the project; the name stays for the document.
+export interface ProjectQuoteLine {
+  id: number;
+  uuid: string;
+  inlay_id: number | null;
+  inlay_name: string;
+  proof_id: number | null;
+  price_group_id: number;
+  price_group_name: string;
+  base_price_cents: number;
+  price_adjustment_type: PriceAdjustmentType;
+  price_adjustment_value: number;
+  price_cents: number;
+  tier_discount_cents: number;
+  price_tier_id: number | null;
+  width: number;
+  height: number;
+  sort_order: number;
+}
+
+// An immutable price breakdown for a draft project. Placing the order before
+// the latest quote expires locks its prices.
+export interface ProjectQuote {
+  id: number;
+  uuid: string;
+  project_id: number;
+  quote_number: number;
+  project_name: string;
+  dealership_name: string;
+  subtotal_cents: number;
+  installation_kit: boolean;
+  installation_kit_price_cents: number;
+  total_cents: number;
+  expires_at: string;
+  created_by_dealership_user_id: number | null;
+  created_by_internal_user_id: number | null;
+  lines: ProjectQuoteLine[];
+  created_at: string;
+}
+
+export interface PostProjectQuoteRequest {
+  inlay_uuids?: string[];
+  valid_days?: number;
+}
@@ -0,0 +1,54 @@
+import { StandardTable } from "./helpers";
+import type { ShipmentCarrier } from "./shipments";
+
+export type RemakeStatus =
+  | "requested"
+  | "approved"
+  | "declined"
+  | "shipped"
+  | "delivered";
+
+export type RemakeBilling = "free" | "charged";
+
+export interface InlayRemakePhoto {
+  id: number;
+  uuid: string;
+  image_url: string;
+  sort_order: number;
+  created_at: string;
+}
+
+// A delivered inlay sent back through production. Cycle numbers the trip
+// through the manufacturing ladder, the original order being cycle 1.
+export type InlayRemake = StandardTable<{
+  inlay_id: number;
+  cycle: number;
+  reason: string;
+  status: RemakeStatus;
+  billing: RemakeBilling | null; // set on approval
+  requested_by_dealership_user_id: number | null;
+  requested_by_internal_user_id: number | null;
+  reviewed_by: number | null;
+  reviewed_at: string | null;
+  decline_reason: string | null;
+  photos: InlayRemakePhoto[];
+}>;
+
+export interface PostInlayRemakeRequest {
+  reason: string;
+  photo_urls?: string[];
+}
+
+export interface ApproveRemakeRequest {
+  billing: RemakeBilling;
+}
+
+export interface DeclineRemakeRequest {
+  reason: string;
+}
+
+export interface ShipRemakeRequest {
+  carrier: ShipmentCarrier;
+  tracking_number?: string;
+  weight_lbs?: number;
+}
@@ -0,0 +1,38 @@
+import { StandardTable } from "./helpers";
+
+export type RoleUserType = "dealership" | "internal";
+
+// A named set of permission actions. Built-in roles are seeded and can be
+// edited but not deleted.
+export type Role = StandardTable<{
+  key: string;
+  name: string;
+  description: string;
+  is_builtin: boolean;
+  actions: string[];
+}>;
+
+export interface RoleList {
+  roles: Role[];
+  actions: string[]; // what this kind of role can grant
+}
+
+export interface RoleRequest {
+  key: string; // fixed once created
+  name: string;
+  description?: string;
+  actions: string[];
+}
+
+// A per-user exception to their role: granted adds the action, otherwise it
+// is denied even if the role has it.
+export interface PermissionOverride {
+  action: string;
+  granted: boolean;
+}
+
+export interface UserPermissions {
+  role: string;
+  overrides: PermissionOverride[];
+  actions: string[]; // the role with the overrides applied
+}
@@ -0,0 +1,11 @@
+// One sign-in on one device, from GET /api/auth/sessions. user_agent and ip
+// are from its most recent refresh; current marks the session asking.
+export type Session = {
+  id: string;
+  user_agent: string;
+  ip: string;
+  created_at: string;
+  last_used_at: string;
+  expires_at: string;
+  current: boolean;
+};
@@ -0,0 +1,50 @@
+import { ManufacturingStep } from "./inlays";
+
+// Predicted ready-to-ship dates, from how long past inlays spent in each step.
+// `expected_at` takes each remaining step at its usual time; `latest_at` at
+// the slow end. Both are null until there is history to go on.
+export interface InlayShipEstimate {
+  inlay_uuid: string;
+  inlay_name: string;
+  step: ManufacturingStep | null;
+  expected_at: string | null;
+  latest_at: string | null;
+}
+
+// Dated by the slowest inlay still to ship.
+export interface ProjectShipEstimate {
+  project_uuid: string;
+  expected_at: string | null;
+  latest_at: string | null;
+  inlays: InlayShipEstimate[];
+}
+
+export type StepGrouping = "price_group" | "inlay_type";
+
+export interface StepDuration {
+  step: ManufacturingStep;
+  // Price group ID or inlay type; empty when ungrouped.
+  group_key: string;
+  samples: number;
+  p50_hours: number;
+  p80_hours: number;
+  p90_hours: number;
+}
+
+export interface StuckInlay {
+  inlay_uuid: string;
+  inlay_name: string;
+  hours_in_step: number;
+}
+
+// The last two weeks in a step against the history before them.
+export interface SlowStep {
+  step: ManufacturingStep;
+  baseline_samples: number;
+  baseline_p50_hours: number;
+  baseline_p90_hours: number;
+  recent_samples: number;
+  recent_p50_hours: number;
+  slow: boolean;
+  stuck_inlays: StuckInlay[];
+}
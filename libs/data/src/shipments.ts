@@ -0,0 +1,33 @@
+import { StandardTable } from "./helpers";
+import type { Project } from "./projects";
+import type { ShipTo } from "./shipping-addresses";
+
+export type ShipmentCarrier = "ups" | "fedex" | "usps" | "dhl" | "other";
+
+// One package sent for a project. Each inlay ships in exactly one package.
+export type Shipment = StandardTable<{
+  project_id: number;
+  carrier: ShipmentCarrier;
+  tracking_number: string | null;
+  tracking_url: string | null; // derived from carrier and tracking number
+  weight_lbs: number | null;
+  shipped_at: string;
+  delivered_at: string | null;
+  inlay_ids: number[];
+  remake_id: number | null; // set when the package carries a remade inlay
+  ship_to: ShipTo | null; // copied from the order when the package goes out
+}>;
+
+export interface PostShipmentRequest {
+  carrier: ShipmentCarrier;
+  tracking_number?: string;
+  weight_lbs?: number;
+  inlay_uuids: string[];
+}
+
+// The project comes back with its status re-derived: shipped once every inlay
+// is in a package, invoiced or completed once every package has arrived.
+export interface ShipmentResult {
+  shipment: Shipment;
+  project: Project;
+}
@@ -0,0 +1,54 @@
+import { StandardTable } from "./helpers";
+
+// Where an order goes. Orders and packages keep their own copy, so editing a
+// saved address never changes where they went.
+export type ShipTo = {
+  recipient: string;
+  street: string;
+  street_ext: string;
+  city: string;
+  state: string;
+  postal_code: string;
+  country: string; // ISO 3166-1 alpha-2
+};
+
+// A place the dealership has saved to send orders to. A blank recipient ships
+// to the dealership's name.
+export type ShippingAddress = StandardTable<
+  ShipTo & {
+    dealership_id: number;
+    label: string;
+  }
+>;
+
+// US and Canadian addresses need a known state or province and a valid postal
+// code; Mexican ones a state and a five-digit postal code.
+export interface ShippingAddressRequest {
+  label: string;
+  recipient?: string;
+  street: string;
+  street_ext?: string;
+  city: string;
+  state?: string;
+  postal_code?: string;
+  country: string;
+}
+
+export type PackingSlipLine = {
+  inlay_uuid: string;
+  name: string;
+  type: string;
+};
+
+export type PackingSlip = {
+  shipment_uuid: string;
+  project_name: string;
+  internal_reference: string | null;
+  dealership_name: string;
+  ship_to: ShipTo;
+  carrier: string;
+  tracking_number: string | null;
+  shipped_at: string;
+  remake: boolean;
+  lines: PackingSlipLine[];
+};
@@ -0,0 +1,23 @@
+import { StandardTable } from "./helpers";
+
+// A rule in the local rate table. Without a prefix it covers the whole state;
+// the longest matching prefix wins.
+export type TaxRate = StandardTable<{
+  name: string;
+  state: string;
+  postal_code_prefix: string | null;
+  rate_percent: number;
+}>;
+
+export interface TaxRateRequest {
+  name: string;
+  state: string;
+  postal_code_prefix?: string | null;
+  rate_percent: number;
+}
+
+export interface TaxExemptionRequest {
+  exempt: boolean;
+  certificate?: string | null;
+  expires_at?: string | null; // YYYY-MM-DD
+}
@@ -0,0 +1,39 @@
+// GET /api/auth/two-factor/challenge, between the first step of signing in
+// and the code. enrolled is false when the policy requires an authenticator
+// the user has not set up yet.
+export type TwoFactorChallenge = {
+  email: string;
+  enrolled: boolean;
+};
+
+// GET /api/auth/two-factor for the signed-in internal user.
+export type TwoFactorStatus = {
+  enabled: boolean;
+  required: boolean;
+  recovery_codes_remaining: number;
+};
+
+// Returned when starting enrolment. otpauth_url is what the QR code encodes.
+export type TwoFactorEnrolment = {
+  secret: string;
+  otpauth_url: string;
+};
+
+// Send one or the other.
+export type TwoFactorVerifyBody = {
+  code?: string;
+  recovery_code?: string;
+};
+
+// recovery_codes is only present when the code confirmed a new
+// authenticator; they are not shown again.
+export type TwoFactorVerifyResponse = {
+  success: boolean;
+  recovery_codes?: string[];
+};
+
+export type TwoFactorPolicy = {
+  require_for_privileged_roles: boolean;
+  updated_at: string;
+  version: number;
+};